@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"voidraft/internal/services"
+)
+
+// runBench 隐藏的 bench 命令：运行核心服务基准，超出性能预算时以非零状态退出
+//
+//	voidraft bench [-sizes 1000,10000,100000] [-budget budget.json] [-json]
+//
+// 预算文件为基准名称到耗时上限的映射，如 {"Search/10k": "20ms"}，未列出的项使用默认预算
+func runBench(args []string) int {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	sizesFlag := fs.String("sizes", "1000,10000,100000", "comma separated document counts for search benchmarks")
+	budgetFlag := fs.String("budget", "", "JSON file overriding the default performance budget")
+	jsonFlag := fs.Bool("json", false, "print results as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var sizes []int
+	for _, field := range strings.Split(*sizesFlag, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || size <= 0 {
+			fmt.Fprintf(os.Stderr, "invalid size %q\n", field)
+			return 2
+		}
+		sizes = append(sizes, size)
+	}
+
+	budget, err := loadBenchBudget(*budgetFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	results, err := services.RunBenchmarks(sizes, budget)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "bench failed:", err)
+		return 1
+	}
+
+	overBudget := false
+	for _, result := range results {
+		overBudget = overBudget || result.OverBudget
+	}
+
+	if *jsonFlag {
+		json.NewEncoder(os.Stdout).Encode(results)
+	} else {
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "BENCHMARK\tITERATIONS\tPER OP\tBUDGET\tSTATUS")
+		for _, result := range results {
+			status := "ok"
+			if result.OverBudget {
+				status = "OVER BUDGET"
+			}
+			budget := "-"
+			if result.Budget > 0 {
+				budget = result.Budget.String()
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\n", result.Name, result.Iterations, result.PerOp, budget, status)
+		}
+		tw.Flush()
+	}
+
+	if overBudget {
+		fmt.Fprintln(os.Stderr, services.ErrOverBudget)
+		return 1
+	}
+	return 0
+}
+
+// loadBenchBudget 读取预算文件并与默认预算合并
+func loadBenchBudget(path string) (services.PerfBudget, error) {
+	budget := services.PerfBudget{}
+	for name, limit := range services.DefaultPerfBudget {
+		budget[name] = limit
+	}
+	if path == "" {
+		return budget, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read budget file: %w", err)
+	}
+	var overrides map[string]string
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("invalid budget file: %w", err)
+	}
+	for name, value := range overrides {
+		limit, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid budget for %s: %w", name, err)
+		}
+		budget[name] = limit
+	}
+	return budget, nil
+}
@@ -0,0 +1,88 @@
+-- voidraft editor plugin example for Neovim (0.10+)
+--
+-- require("voidraft").setup({ token = os.getenv("VOIDRAFT_TOKEN") })
+-- :'<,'>VoidraftPush   push the visual selection into the default document
+-- :VoidraftSearch foo  pick a snippet by fuzzy search and insert it below the cursor
+
+local M = {
+  opts = { host = "127.0.0.1", port = 17300, token = nil, document = 0 },
+}
+
+-- Runs a single command over a fresh connection and calls cb(err, result).
+local function request(verb, args, cb)
+  local tcp = vim.uv.new_tcp()
+  local buffer, lines, sent = "", {}, false
+
+  local function finish(err, result)
+    if not tcp:is_closing() then tcp:close() end
+    vim.schedule(function() cb(err, result) end)
+  end
+
+  tcp:connect(M.opts.host, M.opts.port, function(err)
+    if err then return finish(err) end
+    tcp:write(table.concat({
+      "GET /api/v1/plugin HTTP/1.1",
+      "Host: " .. M.opts.host,
+      "Authorization: Bearer " .. M.opts.token,
+      "Connection: Upgrade",
+      "Upgrade: voidraft-line",
+      "", "",
+    }, "\r\n"))
+
+    tcp:read_start(function(rerr, chunk)
+      if rerr or not chunk then return finish(rerr or "connection closed") end
+      buffer = buffer .. chunk
+      if not sent then
+        local head, rest = buffer:match("^(.-)\r\n\r\n(.*)$")
+        if not head then return end
+        if not head:match("^HTTP/1%.1 101") then return finish(head:match("^[^\r]*")) end
+        buffer, sent = rest, true
+        tcp:write('HELLO {"client":"nvim","version":"0.1.0","protocol":1,"capabilities":["push","search","get"]}\n')
+        tcp:write(verb .. " " .. vim.json.encode(args) .. "\n")
+      end
+      for line in buffer:gmatch("([^\n]*)\n") do table.insert(lines, line) end
+      buffer = buffer:match("[^\n]*$")
+      if #lines >= 2 then
+        local status, payload = lines[2]:match("^(%u+) (.*)$")
+        if status == "OK" then return finish(nil, vim.json.decode(payload)) end
+        return finish(payload)
+      end
+    end)
+  end)
+end
+
+function M.push(line1, line2)
+  local text = table.concat(vim.api.nvim_buf_get_lines(0, line1 - 1, line2, false), "\n")
+  request("PUSH", {
+    documentId = M.opts.document,
+    language = vim.bo.filetype ~= "" and vim.bo.filetype or "text",
+    text = text,
+    source = vim.fn.expand("%:~:.") .. ":" .. line1,
+  }, function(err)
+    vim.notify(err and ("voidraft: " .. err) or "voidraft: selection pushed")
+  end)
+end
+
+function M.search(query)
+  request("SEARCH", { query = query }, function(err, snippets)
+    if err then return vim.notify("voidraft: " .. err, vim.log.levels.ERROR) end
+    vim.ui.select(snippets, {
+      prompt = "voidraft",
+      format_item = function(s) return s.title .. " › " .. s.preview:gsub("\n", " ⏎ ") end,
+    }, function(choice)
+      if not choice then return end
+      request("GET", { documentId = choice.documentId, block = choice.block }, function(gerr, block)
+        if gerr then return vim.notify("voidraft: " .. gerr, vim.log.levels.ERROR) end
+        vim.api.nvim_put(vim.split(block.text, "\n"), "l", true, true)
+      end)
+    end)
+  end)
+end
+
+function M.setup(opts)
+  M.opts = vim.tbl_extend("force", M.opts, opts or {})
+  vim.api.nvim_create_user_command("VoidraftPush", function(c) M.push(c.line1, c.line2) end, { range = true })
+  vim.api.nvim_create_user_command("VoidraftSearch", function(c) M.search(c.args) end, { nargs = "+" })
+end
+
+return M
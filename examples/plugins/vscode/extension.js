@@ -0,0 +1,103 @@
+// voidraft editor plugin example for VS Code.
+// Settings: voidraft.port (default 17300) and voidraft.token.
+const net = require("net");
+const vscode = require("vscode");
+
+class VoidraftClient {
+  constructor(port, token) {
+    this.port = port;
+    this.token = token;
+    this.pending = [];
+    this.buffer = "";
+  }
+
+  connect() {
+    return new Promise((resolve, reject) => {
+      this.socket = net.connect(this.port, "127.0.0.1", () => {
+        this.socket.write(
+          "GET /api/v1/plugin HTTP/1.1\r\nHost: 127.0.0.1\r\n" +
+            `Authorization: Bearer ${this.token}\r\nConnection: Upgrade\r\nUpgrade: voidraft-line\r\n\r\n`
+        );
+      });
+      let upgraded = false;
+      this.socket.on("error", reject);
+      this.socket.on("data", (chunk) => {
+        this.buffer += chunk.toString("utf8");
+        if (!upgraded) {
+          const end = this.buffer.indexOf("\r\n\r\n");
+          if (end < 0) return;
+          const status = this.buffer.slice(0, this.buffer.indexOf("\r\n"));
+          if (!status.startsWith("HTTP/1.1 101")) return reject(new Error(status));
+          this.buffer = this.buffer.slice(end + 4);
+          upgraded = true;
+          this.send("HELLO", { client: "vscode", version: "0.1.0", protocol: 1, capabilities: ["push", "search", "get"] })
+            .then(resolve, reject);
+        }
+        let newline;
+        while ((newline = this.buffer.indexOf("\n")) >= 0) {
+          const line = this.buffer.slice(0, newline);
+          this.buffer = this.buffer.slice(newline + 1);
+          const { resolve: ok, reject: fail } = this.pending.shift();
+          const space = line.indexOf(" ");
+          if (line.slice(0, space) === "OK") ok(JSON.parse(line.slice(space + 1)));
+          else fail(new Error(line.slice(space + 1)));
+        }
+      });
+    });
+  }
+
+  send(verb, args) {
+    return new Promise((resolve, reject) => {
+      this.pending.push({ resolve, reject });
+      this.socket.write(`${verb} ${JSON.stringify(args)}\n`);
+    });
+  }
+
+  close() {
+    this.socket.end("BYE {}\n");
+  }
+}
+
+async function withClient(fn) {
+  const config = vscode.workspace.getConfiguration("voidraft");
+  const client = new VoidraftClient(config.get("port", 17300), config.get("token"));
+  try {
+    await client.connect();
+    return await fn(client);
+  } catch (err) {
+    vscode.window.showErrorMessage(`voidraft: ${err.message}`);
+  } finally {
+    client.close();
+  }
+}
+
+function activate(context) {
+  context.subscriptions.push(
+    vscode.commands.registerTextEditorCommand("voidraft.pushSelection", (editor) =>
+      withClient(async (client) => {
+        await client.send("PUSH", {
+          documentId: 0,
+          language: editor.document.languageId,
+          text: editor.document.getText(editor.selection),
+          source: `${vscode.workspace.asRelativePath(editor.document.uri)}:${editor.selection.start.line + 1}`,
+        });
+        vscode.window.setStatusBarMessage("voidraft: selection pushed", 3000);
+      })
+    ),
+    vscode.commands.registerTextEditorCommand("voidraft.insertSnippet", async (editor) => {
+      const query = await vscode.window.showInputBox({ prompt: "Search voidraft snippets" });
+      if (!query) return;
+      await withClient(async (client) => {
+        const snippets = await client.send("SEARCH", { query });
+        const pick = await vscode.window.showQuickPick(
+          snippets.map((s) => ({ label: s.title, description: s.language, detail: s.preview, snippet: s }))
+        );
+        if (!pick) return;
+        const block = await client.send("GET", { documentId: pick.snippet.documentId, block: pick.snippet.block });
+        await editor.edit((edit) => edit.insert(editor.selection.active, block.text));
+      });
+    })
+  );
+}
+
+module.exports = { activate };
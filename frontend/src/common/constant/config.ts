@@ -2,9 +2,14 @@ import {
     AppConfig,
     AuthMethod,
     LanguageType,
+    RichPasteMode,
     SystemThemeType,
     TabType,
-    UpdateSourceType
+    UpdateMode,
+    UpdateSourceType,
+    WindowSnapDragMode,
+    NewWindowSnapEdge,
+    SecondInstanceAction
 } from '@/../bindings/voidraft/internal/models/models';
 import {FONT_OPTIONS} from './fonts';
 
@@ -21,6 +26,12 @@ export const CONFIG_KEY_MAP = {
     enableGlobalHotkey: 'general.enableGlobalHotkey',
     globalHotkey: 'general.globalHotkey',
     enableWindowSnap: 'general.enableWindowSnap',
+    animateWindowSnap: 'general.animateWindowSnap',
+    windowSnapDragMode: 'general.windowSnapDragMode',
+    snapNewWindows: 'general.snapNewWindows',
+    newWindowSnapEdge: 'general.newWindowSnapEdge',
+    secondInstanceAction: 'general.secondInstanceAction',
+    windowSnapCompat: 'general.windowSnapCompat',
     enableLoadingAnimation: 'general.enableLoadingAnimation',
     enableTabs: 'general.enableTabs',
     // editing
@@ -32,12 +43,14 @@ export const CONFIG_KEY_MAP = {
     tabSize: 'editing.tabSize',
     tabType: 'editing.tabType',
     autoSaveDelay: 'editing.autoSaveDelay',
+    richPaste: 'editing.richPaste',
     // appearance
     language: 'appearance.language',
     systemTheme: 'appearance.systemTheme',
     currentTheme: 'appearance.currentTheme',
     // updates
     version: 'updates.version',
+    mode: 'updates.mode',
     autoUpdate: 'updates.autoUpdate',
     primarySource: 'updates.primarySource',
     backupSource: 'updates.backupSource',
@@ -84,6 +97,17 @@ export const DEFAULT_CONFIG: AppConfig = {
             key: 'X'
         },
         enableWindowSnap: true,
+        animateWindowSnap: true,
+        windowSnapDragMode: WindowSnapDragMode.WindowSnapDragDetach,
+        snapNewWindows: false,
+        newWindowSnapEdge: NewWindowSnapEdge.NewWindowSnapRight,
+        secondInstanceAction: SecondInstanceAction.SecondInstanceFocus,
+        windowSnapCompat: {
+            snapLayouts: true,
+            fancyZones: true,
+            stageManager: true,
+            tilingWM: true
+        },
         enableLoadingAnimation: true,
         enableTabs: false,
     },
@@ -95,7 +119,8 @@ export const DEFAULT_CONFIG: AppConfig = {
         enableTabIndent: true,
         tabSize: CONFIG_LIMITS.tabSize.default,
         tabType: CONFIG_LIMITS.tabType.default,
-        autoSaveDelay: 5000
+        autoSaveDelay: 5000,
+        richPaste: RichPasteMode.RichPasteConvert
     },
     appearance: {
         language: LanguageType.LangZhCN,
@@ -104,6 +129,7 @@ export const DEFAULT_CONFIG: AppConfig = {
     },
     updates: {
         version: "1.0.0",
+        mode: UpdateMode.UpdateModeNotify,
         autoUpdate: true,
         primarySource: UpdateSourceType.UpdateSourceGithub,
         backupSource: UpdateSourceType.UpdateSourceGitea,
@@ -10,4 +10,6 @@ export const EDITOR_CONFIG = {
   SYNTAX_TREE_CACHE_TIMEOUT: 30000,
   /** 加载状态延迟时间（毫秒） */
   LOADING_DELAY: 500,
+  /** 记录自动保存日志的防抖时间（毫秒） */
+  JOURNAL_DELAY: 1000,
 } as const;
\ No newline at end of file
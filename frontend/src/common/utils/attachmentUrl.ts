@@ -0,0 +1,46 @@
+/**
+ * 附件地址工具
+ * 文档中保存的附件地址不含令牌，加载前需要追加本次运行的附件令牌
+ */
+
+import {AttachmentService} from '@/../bindings/voidraft/internal/services';
+
+/** 附件路由前缀，与后端 attachmentRoutePrefix 一致 */
+const ATTACHMENT_ROUTE_PREFIX = '/attachments/';
+
+let tokenPromise: Promise<string> | null = null;
+
+/**
+ * 获取本次运行的附件令牌，只请求一次
+ */
+function getAttachmentToken(): Promise<string> {
+  if (!tokenPromise) {
+    tokenPromise = AttachmentService.GetAttachmentToken().catch((err) => {
+      tokenPromise = null;
+      throw err;
+    });
+  }
+  return tokenPromise;
+}
+
+/**
+ * 是否为附件地址
+ */
+export function isAttachmentUrl(url: string): boolean {
+  return url.startsWith(ATTACHMENT_ROUTE_PREFIX);
+}
+
+/**
+ * 解析可加载的地址，附件地址追加 token 参数，其他地址原样返回
+ *
+ * @param url 文档中的地址
+ * @returns 可直接用于 img 等元素的地址
+ */
+export async function resolveAttachmentUrl(url: string): Promise<string> {
+  if (!isAttachmentUrl(url)) {
+    return url;
+  }
+  const token = await getAttachmentToken();
+  const separator = url.includes('?') ? '&' : '?';
+  return `${url}${separator}token=${encodeURIComponent(token)}`;
+}
@@ -1,16 +1,38 @@
 export default {
   locale: 'en-US',
+  appLock: {
+    title: 'voidraft is locked',
+    passphrase: 'Passphrase',
+    unlock: 'Unlock'
+  },
   titlebar: {
     minimize: 'Minimize',
     maximize: 'Maximize',
     restore: 'Restore Down',
-    close: 'Close'
+    close: 'Close',
+    snapped: 'Docked to {target} ({edge})',
+    snapTargetMain: 'the main window',
+    snapTargetWindow: 'another window',
+    unsnap: 'Unsnap',
+    snapEdges: {
+      top: 'top',
+      right: 'right',
+      bottom: 'bottom',
+      left: 'left',
+      topRight: 'top right',
+      bottomRight: 'bottom right',
+      bottomLeft: 'bottom left',
+      topLeft: 'top left'
+    }
   },
   toolbar: {
     editor: {
       lines: 'Ln',
       characters: 'Ch',
-      selected: 'Sel'
+      selected: 'Sel',
+      codeLines: 'LOC',
+      comments: 'Comments',
+      languageMix: 'Document languages'
     },
     fontSizeTooltip: 'Font Size (Ctrl+wheel to adjust)',
     settings: 'Settings',
@@ -19,6 +41,12 @@ export default {
     searchLanguage: 'Search language...',
     noLanguageFound: 'No language found',
     formatHint: 'Click Format Block (Ctrl+Shift+F)',
+    follow: {
+      following: 'Following',
+      editing: 'Editing',
+      takeOver: 'This document is open in another window and is read-only here. Click to edit in this window.',
+      editingHere: 'This document is open in another window, which follows this one read-only.'
+    },
     previewMarkdown: 'Preview Markdown',
     closePreview: 'Close Preview',
     // Document selector
@@ -111,6 +139,7 @@ export default {
       deleteCharForward: 'Delete character forward',
       deleteGroupBackward: 'Delete group backward',
       deleteGroupForward: 'Delete group forward',
+      lockApp: 'Lock application',
     }
   },
   tabs: {
@@ -121,6 +150,11 @@ export default {
       closeRight: 'Close Right'
     }
   },
+  exportDocument: {
+    markdown: 'Export as Markdown…',
+    html: 'Export as HTML…',
+    pdf: 'Export as PDF…'
+  },
   settings: {
     title: 'Settings',
     backToEditor: 'Back to Editor',
@@ -144,10 +178,43 @@ export default {
     enableSystemTray: 'Enable System Tray',
     alwaysOnTop: 'Always on Top',
     enableWindowSnap: 'Enable Window Snapping',
+    animateWindowSnap: 'Animate Window Snapping',
+    windowSnapDragMode: 'Dragging a Snapped Window',
+    windowSnapDragModeDescription: 'Pull the window away from the main window, or move the whole group together',
+    windowSnapDragModes: {
+      detach: 'Detach it',
+      cluster: 'Move the group'
+    },
+    snapNewWindows: 'Open Windows Beside the Main Window',
+    snapNewWindowsDescription: 'New document windows open already snapped to the main window instead of centered on screen',
+    newWindowSnapEdge: 'Side for New Windows',
+    newWindowSnapEdges: {
+      right: 'Right',
+      left: 'Left',
+      bottom: 'Bottom',
+      top: 'Top'
+    },
+    animateWindowSnapDescription: 'Slide windows into place instead of jumping. Turned off automatically when reduced motion is on',
+    tilingCompat: {
+      snapLayouts: 'Yield to Windows Snap Layouts',
+      fancyZones: 'Yield to PowerToys FancyZones',
+      stageManager: 'Yield to Stage Manager',
+      tilingWM: 'Yield to tiling window manager',
+      detected: '{name} is active; windows it arranges are not snapped',
+      notDetected: 'Not detected on this system',
+    },
     enableLoadingAnimation: 'Enable Loading Animation',
     enableTabs: 'Enable Tabs',
     startup: 'Startup Settings',
     startAtLogin: 'Start at Login',
+    secondInstanceAction: 'When Launched Again',
+    secondInstanceActionDescription: 'What happens when voidraft is started while it is already running. Launch options such as --new-document take precedence',
+    secondInstanceActions: {
+      focus: 'Show the main window',
+      capture: 'Open the capture inbox',
+      newDocument: 'Create a new document',
+      restoreSession: 'Reopen recent windows'
+    },
     dataStorage: 'Data Storage',
     dataPath: 'Data Storage Path',
     clickToSelectPath: 'Click to select path',
@@ -172,8 +239,29 @@ export default {
     presetTheme: 'Preset Theme',
     saveOptions: 'Save Options',
     autoSaveDelay: 'Auto Save Delay (ms)',
+    pasteOptions: 'Paste Options',
+    richPaste: 'Paste Rich Text',
+    richPasteDescription: 'How HTML or RTF content copied from web pages, Word and similar sources is pasted into Markdown blocks',
+    richPasteModes: {
+      convert: 'Convert to Markdown',
+      plain: 'Plain text only',
+      ask: 'Ask every time',
+    },
+    richPasteConfirm: 'The clipboard contains rich text. Convert it to Markdown? Choose Cancel to paste plain text.',
     updateSettings: 'Update Settings',
+    updateMode: 'Update Mode',
+    updateModeDescription: 'Update checks never send tokens or device information; offline mode never contacts any server',
+    updateModes: {
+      auto: 'Install automatically',
+      notify: 'Notify only',
+      manual: 'Manual check only',
+      offline: 'Offline'
+    },
     autoCheckUpdates: 'Automatically Check Updates',
+    whatsNew: "What's New",
+    whatsNewUpgraded: 'Updated from {from} to {to}',
+    whatsNewDismiss: 'Got it',
+    whatsNewOffline: 'Release notes could not be loaded. See the project release page for details.',
     autoCheckUpdatesDescription: 'Check for updates when application starts',
     manualCheck: 'Manual Update',
     currentVersion: 'Current Version',
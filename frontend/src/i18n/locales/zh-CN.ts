@@ -1,16 +1,38 @@
 export default {
   locale: 'zh-CN',
+  appLock: {
+    title: 'voidraft 已锁定',
+    passphrase: '解锁口令',
+    unlock: '解锁'
+  },
   titlebar: {
     minimize: '最小化',
     maximize: '最大化',
     restore: '向下还原',
-    close: '关闭'
+    close: '关闭',
+    snapped: '已吸附到{target}（{edge}）',
+    snapTargetMain: '主窗口',
+    snapTargetWindow: '其他窗口',
+    unsnap: '解除吸附',
+    snapEdges: {
+      top: '上方',
+      right: '右侧',
+      bottom: '下方',
+      left: '左侧',
+      topRight: '右上角',
+      bottomRight: '右下角',
+      bottomLeft: '左下角',
+      topLeft: '左上角'
+    }
   },
   toolbar: {
     editor: {
       lines: 'Ln',
       characters: 'Ch',
-      selected: 'Sel'
+      selected: 'Sel',
+      codeLines: '代码行',
+      comments: '注释',
+      languageMix: '文档语言分布'
     },
     fontSizeTooltip: '字体大小 (Ctrl+滚轮调整)',
     settings: '设置',
@@ -19,6 +41,12 @@ export default {
     searchLanguage: '搜索语言...',
     noLanguageFound: '未找到匹配的语言',
     formatHint: '点击格式化区块（Ctrl+Shift+F）',
+    follow: {
+      following: '跟随中',
+      editing: '编辑中',
+      takeOver: '该文档已在另一窗口中打开，此处只读跟随。点击在此窗口中编辑。',
+      editingHere: '该文档已在另一窗口中打开，另一窗口只读跟随此处的编辑。'
+    },
     previewMarkdown: '预览 Markdown',
     closePreview: '关闭预览',
     // 文档选择器
@@ -111,6 +139,7 @@ export default {
       deleteCharForward: '向前删除字符',
       deleteGroupBackward: '向后删除组',
       deleteGroupForward: '向前删除组',
+      lockApp: '锁定应用',
     }
   },
   tabs: {
@@ -121,6 +150,11 @@ export default {
       closeRight: '关闭右侧'
     }
   },
+  exportDocument: {
+    markdown: '导出为 Markdown…',
+    html: '导出为 HTML…',
+    pdf: '导出为 PDF…'
+  },
   settings: {
     title: '设置',
     backToEditor: '返回编辑器',
@@ -145,10 +179,43 @@ export default {
     enableSystemTray: '启用系统托盘',
     alwaysOnTop: '窗口始终置顶',
     enableWindowSnap: '启用窗口吸附',
+    animateWindowSnap: '窗口吸附动画',
+    windowSnapDragMode: '拖动已吸附的窗口',
+    windowSnapDragModeDescription: '拖开窗口解除吸附，或带动主窗口整组移动',
+    windowSnapDragModes: {
+      detach: '解除吸附',
+      cluster: '整组移动'
+    },
+    snapNewWindows: '在主窗口旁打开新窗口',
+    snapNewWindowsDescription: '新建的文档窗口直接吸附到主窗口一侧，而不是在屏幕居中打开',
+    newWindowSnapEdge: '新窗口吸附位置',
+    newWindowSnapEdges: {
+      right: '右侧',
+      left: '左侧',
+      bottom: '下方',
+      top: '上方'
+    },
+    animateWindowSnapDescription: '吸附时平滑移动窗口，开启减少动态效果时自动关闭',
+    tilingCompat: {
+      snapLayouts: '兼容 Windows 贴靠布局',
+      fancyZones: '兼容 PowerToys FancyZones',
+      stageManager: '兼容台前调度',
+      tilingWM: '兼容平铺式窗口管理器',
+      detected: '已检测到 {name}，由其排列的窗口不再吸附',
+      notDetected: '当前系统未检测到',
+    },
     enableLoadingAnimation: '启用加载动画',
     enableTabs: '启用标签页',
     startup: '启动设置',
     startAtLogin: '开机自启动',
+    secondInstanceAction: '再次启动时',
+    secondInstanceActionDescription: '应用已在运行时再次启动的行为，启动参数（如 --new-document）优先',
+    secondInstanceActions: {
+      focus: '显示主窗口',
+      capture: '打开收集箱',
+      newDocument: '新建文档',
+      restoreSession: '重新打开最近的窗口'
+    },
     dataStorage: '数据存储',
     dataPath: '数据存储路径',
     clickToSelectPath: '点击选择路径',
@@ -170,8 +237,29 @@ export default {
     presetTheme: '预设主题',
     saveOptions: '保存选项',
     autoSaveDelay: '自动保存延迟(毫秒)',
+    pasteOptions: '粘贴选项',
+    richPaste: '粘贴富文本',
+    richPasteDescription: '在 Markdown 块中粘贴网页、Word 等来源的 HTML 或 RTF 内容时的处理方式',
+    richPasteModes: {
+      convert: '转换为 Markdown',
+      plain: '仅纯文本',
+      ask: '每次询问',
+    },
+    richPasteConfirm: '剪贴板中包含富文本，是否转换为 Markdown？选择“取消”将粘贴纯文本。',
     updateSettings: '更新设置',
+    updateMode: '更新模式',
+    updateModeDescription: '检查更新不会发送令牌或设备信息；离线模式下不访问任何服务器',
+    updateModes: {
+      auto: '自动安装',
+      notify: '仅提示',
+      manual: '仅手动检查',
+      offline: '离线'
+    },
     autoCheckUpdates: '自动检查更新',
+    whatsNew: '更新内容',
+    whatsNewUpgraded: '已从 {from} 升级到 {to}',
+    whatsNewDismiss: '知道了',
+    whatsNewOffline: '未能获取发布说明，详情请查看项目发布页面。',
     autoCheckUpdatesDescription: '应用启动时自动检查更新',
     manualCheck: '手动更新',
     currentVersion: '当前版本',
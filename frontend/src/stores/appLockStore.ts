@@ -0,0 +1,95 @@
+import {ref} from 'vue';
+import {defineStore} from 'pinia';
+import {Events} from '@wailsio/runtime';
+import {AppLockService} from '@/../bindings/voidraft/internal/services';
+
+// 上报用户活动的最短间隔，避免每次按键都调用后端
+const ACTIVITY_REPORT_INTERVAL = 30 * 1000;
+// 视为用户活动的事件
+const ACTIVITY_EVENTS = ['keydown', 'mousedown', 'mousemove', 'wheel', 'touchstart'] as const;
+
+export const useAppLockStore = defineStore('appLock', () => {
+    // 应用是否锁定，锁定时遮挡所有内容并显示解锁界面
+    const locked = ref(false);
+    // 最近一次解锁失败的原因
+    const error = ref('');
+    const unlocking = ref(false);
+
+    let lastReport = 0;
+    let listening = false;
+
+    // 用户活动时重置后端的空闲计时
+    const reportActivity = () => {
+        if (locked.value) return;
+        const now = Date.now();
+        if (now - lastReport < ACTIVITY_REPORT_INTERVAL) return;
+        lastReport = now;
+        AppLockService.ReportActivity().catch((err) => {
+            console.error('Failed to report activity:', err);
+        });
+    };
+
+    // 立即锁定应用，未设置口令时忽略
+    const lock = async () => {
+        try {
+            await AppLockService.LockApp();
+        } catch (err) {
+            console.warn('Failed to lock application:', err);
+        }
+    };
+
+    /**
+     * 使用口令解锁
+     * @param passphrase 解锁口令
+     * @returns 是否解锁成功
+     */
+    const unlock = async (passphrase: string): Promise<boolean> => {
+        unlocking.value = true;
+        error.value = '';
+        try {
+            await AppLockService.UnlockApp(passphrase);
+            locked.value = false;
+            return true;
+        } catch (err) {
+            error.value = err instanceof Error ? err.message : String(err);
+            return false;
+        } finally {
+            unlocking.value = false;
+        }
+    };
+
+    // 读取当前锁定状态并开始上报用户活动
+    const initialize = async () => {
+        try {
+            locked.value = await AppLockService.IsLocked();
+        } catch (err) {
+            console.error('Failed to get lock state:', err);
+        }
+        if (!listening) {
+            listening = true;
+            ACTIVITY_EVENTS.forEach((type) => {
+                window.addEventListener(type, reportActivity, {capture: true, passive: true});
+            });
+        }
+    };
+
+    Events.On('app:locked', () => {
+        locked.value = true;
+        error.value = '';
+    });
+
+    Events.On('app:unlocked', () => {
+        locked.value = false;
+        error.value = '';
+        lastReport = 0;
+    });
+
+    return {
+        locked,
+        error,
+        unlocking,
+        lock,
+        unlock,
+        initialize
+    };
+});
@@ -6,8 +6,14 @@ import {
     AuthMethod,
     EditingConfig,
     LanguageType,
+    RichPasteMode,
     SystemThemeType,
-    TabType
+    TabType,
+    UpdateMode,
+    WindowSnapCompatConfig,
+    WindowSnapDragMode,
+    NewWindowSnapEdge,
+    SecondInstanceAction
 } from '@/../bindings/voidraft/internal/models/models';
 import {useI18n} from 'vue-i18n';
 import {ConfigUtils} from '@/common/utils/configUtils';
@@ -253,6 +259,9 @@ export const useConfigStore = defineStore('config', () => {
         // 保存配置相关方法
         setAutoSaveDelay: (value: number) => updateConfig('autoSaveDelay', value),
 
+        // 粘贴选项
+        setRichPaste: (value: RichPasteMode) => updateConfig('richPaste', value),
+
         // 热键配置相关方法
         setEnableGlobalHotkey: (value: boolean) => updateConfig('enableGlobalHotkey', value),
         setGlobalHotkey: (hotkey: any) => updateConfig('globalHotkey', hotkey),
@@ -266,8 +275,16 @@ export const useConfigStore = defineStore('config', () => {
             await StartupService.SetEnabled(value);
         },
 
+        // 再次启动行为配置相关方法
+        setSecondInstanceAction: (value: SecondInstanceAction) => updateConfig('secondInstanceAction', value),
+
         // 窗口吸附配置相关方法
         setEnableWindowSnap: (value: boolean) => updateConfig('enableWindowSnap', value),
+        setAnimateWindowSnap: (value: boolean) => updateConfig('animateWindowSnap', value),
+        setWindowSnapDragMode: (value: WindowSnapDragMode) => updateConfig('windowSnapDragMode', value),
+        setSnapNewWindows: (value: boolean) => updateConfig('snapNewWindows', value),
+        setNewWindowSnapEdge: (value: NewWindowSnapEdge) => updateConfig('newWindowSnapEdge', value),
+        setWindowSnapCompat: (value: WindowSnapCompatConfig) => updateConfig('windowSnapCompat', value),
 
         // 加载动画配置相关方法
         setEnableLoadingAnimation: (value: boolean) => updateConfig('enableLoadingAnimation', value),
@@ -277,6 +294,7 @@ export const useConfigStore = defineStore('config', () => {
 
         // 更新配置相关方法
         setAutoUpdate: (value: boolean) => updateConfig('autoUpdate', value),
+        setUpdateMode: (value: UpdateMode) => updateConfig('mode', value),
 
         // 备份配置相关方法
         setEnableBackup: (value: boolean) => updateConfig('enabled', value),
@@ -1,8 +1,9 @@
 import {defineStore} from 'pinia';
 import {computed, ref} from 'vue';
+import {Events} from '@wailsio/runtime';
 import {DocumentService} from '@/../bindings/voidraft/internal/services';
 import {OpenDocumentWindow} from '@/../bindings/voidraft/internal/services/windowservice';
-import {Document} from '@/../bindings/voidraft/internal/models/models';
+import {Document, DocumentChangeEvent, DocumentColor} from '@/../bindings/voidraft/internal/models/models';
 import {useTabStore} from "@/stores/tabStore";
 import type {EditorViewState} from '@/stores/editorStore';
 
@@ -11,6 +12,8 @@ export const useDocumentStore = defineStore('document', () => {
 
     // === 核心状态 ===
     const documents = ref<Record<number, Document>>({});
+    // 最近打开或编辑的文档ID，从新到旧
+    const recentDocumentIds = ref<number[]>([]);
     const currentDocumentId = ref<number | null>(null);
     const currentDocument = ref<Document | null>(null);
 
@@ -24,8 +27,15 @@ export const useDocumentStore = defineStore('document', () => {
     const isLoading = ref(false);
 
     // === 计算属性 ===
+    // 与后端一致：置顶文档在前，未手动排序的文档按修改时间排在已排序的文档之前
     const documentList = computed(() =>
         Object.values(documents.value).sort((a, b) => {
+            if (a.isPinned !== b.isPinned) {
+                return a.isPinned ? -1 : 1;
+            }
+            if (a.sortOrder !== b.sortOrder) {
+                return a.sortOrder - b.sortOrder;
+            }
             return new Date(b.updatedAt).getTime() - new Date(a.updatedAt).getTime();
         })
     );
@@ -105,6 +115,21 @@ export const useDocumentStore = defineStore('document', () => {
         }
     };
 
+    // 创建只能追加的日志文档
+    const createJournal = async (title: string): Promise<Document | null> => {
+        try {
+            const doc = await DocumentService.CreateJournal(title);
+            if (doc) {
+                documents.value[doc.id] = doc;
+                return doc;
+            }
+            return null;
+        } catch (error) {
+            console.error('Failed to create journal:', error);
+            return null;
+        }
+    };
+
     // 获取文档列表
     const getDocumentMetaList = async () => {
         try {
@@ -120,6 +145,18 @@ export const useDocumentStore = defineStore('document', () => {
         }
     };
 
+    // 获取最近打开或编辑的文档
+    const getRecentDocuments = async (limit = 20) => {
+        try {
+            const docs = await DocumentService.GetRecentDocuments(limit);
+            recentDocumentIds.value = (docs || [])
+                .filter((doc): doc is Document => doc !== null)
+                .map(doc => doc.id);
+        } catch (error) {
+            console.error('Failed to get recent documents:', error);
+        }
+    };
+
     // 打开文档
     const openDocument = async (docId: number): Promise<boolean> => {
         try {
@@ -134,6 +171,11 @@ export const useDocumentStore = defineStore('document', () => {
             currentDocumentId.value = docId;
             currentDocument.value = doc;
 
+            // 记录打开时间，用于最近文档和常用度排序
+            DocumentService.RecordDocumentOpened(docId).catch(error => {
+                console.error('Failed to record document opened:', error);
+            });
+
             return true;
         } catch (error) {
             console.error('Failed to open document:', error);
@@ -147,14 +189,21 @@ export const useDocumentStore = defineStore('document', () => {
             await DocumentService.UpdateDocumentTitle(docId, title);
 
             // 更新本地状态
+            // 标题为空时由后端按内容生成，通过 document:title-generated 事件更新
+            if (!title.trim()) {
+                return true;
+            }
+
             const doc = documents.value[docId];
             if (doc) {
                 doc.title = title;
+                doc.titleAuto = false;
                 doc.updatedAt = new Date().toISOString();
             }
 
             if (currentDocument.value?.id === docId) {
                 currentDocument.value.title = title;
+                currentDocument.value.titleAuto = false;
                 currentDocument.value.updatedAt = new Date().toISOString();
             }
 
@@ -196,6 +245,101 @@ export const useDocumentStore = defineStore('document', () => {
         }
     };
 
+    // 置顶或取消置顶文档
+    const setDocumentPinned = async (docId: number, pinned: boolean): Promise<boolean> => {
+        try {
+            if (pinned) {
+                await DocumentService.PinDocument(docId);
+            } else {
+                await DocumentService.UnpinDocument(docId);
+            }
+            const doc = documents.value[docId];
+            if (doc) {
+                doc.isPinned = pinned;
+            }
+            return true;
+        } catch (error) {
+            console.error('Failed to pin document:', error);
+            return false;
+        }
+    };
+
+    // 收藏或取消收藏文档
+    const setDocumentFavorite = async (docId: number, favorite: boolean): Promise<boolean> => {
+        try {
+            await DocumentService.SetDocumentFavorite(docId, favorite);
+            const doc = documents.value[docId];
+            if (doc) {
+                doc.isFavorite = favorite;
+            }
+            return true;
+        } catch (error) {
+            console.error('Failed to set document favorite:', error);
+            return false;
+        }
+    };
+
+    // 设置文档的颜色标签，空字符串表示清除
+    const setDocumentColorLabel = async (docId: number, color: DocumentColor): Promise<boolean> => {
+        try {
+            await DocumentService.SetDocumentColorLabel(docId, color);
+            const doc = documents.value[docId];
+            if (doc) {
+                doc.colorLabel = color;
+            }
+            return true;
+        } catch (error) {
+            console.error('Failed to set document color label:', error);
+            return false;
+        }
+    };
+
+    // 设置文档的 emoji 图标，空字符串表示清除
+    const setDocumentIcon = async (docId: number, icon: string): Promise<boolean> => {
+        try {
+            await DocumentService.SetDocumentIcon(docId, icon);
+            const doc = documents.value[docId];
+            if (doc) {
+                doc.icon = icon.trim();
+            }
+            return true;
+        } catch (error) {
+            console.error('Failed to set document icon:', error);
+            return false;
+        }
+    };
+
+    // 保存拖动后的顺序，ids 为列表中从上到下的文档
+    const reorderDocuments = async (ids: number[]): Promise<boolean> => {
+        try {
+            await DocumentService.ReorderDocuments(ids);
+            ids.forEach((id, index) => {
+                const doc = documents.value[id];
+                if (doc) {
+                    doc.sortOrder = index + 1;
+                }
+            });
+            return true;
+        } catch (error) {
+            console.error('Failed to reorder documents:', error);
+            return false;
+        }
+    };
+
+    // 未手动设置标题的文档按内容重新生成了标题
+    Events.On('document:title-generated', (event) => {
+        const data = event.data as DocumentChangeEvent;
+        const doc = documents.value[data.documentId];
+        if (doc) {
+            doc.title = data.title;
+            doc.titleAuto = true;
+        }
+        if (currentDocument.value?.id === data.documentId) {
+            currentDocument.value.title = data.title;
+            currentDocument.value.titleAuto = true;
+        }
+    });
+
     // === 初始化 ===
     const initialize = async (urlDocumentId?: number): Promise<void> => {
         try {
@@ -221,6 +365,7 @@ export const useDocumentStore = defineStore('document', () => {
         // 状态
         documents,
         documentList,
+        recentDocumentIds,
         currentDocumentId,
         currentDocument,
         documentStates,
@@ -230,11 +375,18 @@ export const useDocumentStore = defineStore('document', () => {
 
         // 方法
         getDocumentMetaList,
+        getRecentDocuments,
         openDocument,
         openDocumentInNewWindow,
         createNewDocument,
+        createJournal,
         updateDocumentMetadata,
         deleteDocument,
+        setDocumentPinned,
+        setDocumentFavorite,
+        setDocumentColorLabel,
+        setDocumentIcon,
+        reorderDocuments,
         openDocumentSelector,
         closeDocumentSelector,
         toggleDocumentSelector,
@@ -1,11 +1,12 @@
 import {defineStore} from 'pinia';
-import {computed, nextTick, ref, watch} from 'vue';
+import {computed, nextTick, reactive, ref, watch} from 'vue';
 import {EditorView} from '@codemirror/view';
 import {EditorState, Extension} from '@codemirror/state';
 import {useConfigStore} from './configStore';
 import {useDocumentStore} from './documentStore';
-import {ExtensionID} from '@/../bindings/voidraft/internal/models/models';
-import {DocumentService, ExtensionService} from '@/../bindings/voidraft/internal/services';
+import {ExtensionID, FollowChanges, FollowMode, FollowState, FollowView} from '@/../bindings/voidraft/internal/models/models';
+import {DocumentService, ExtensionService, SessionService, WindowService} from '@/../bindings/voidraft/internal/services';
+import {Events} from '@wailsio/runtime';
 import {ensureSyntaxTree} from "@codemirror/language";
 import {createBasicSetup} from '@/views/editor/basic/basicSetup';
 import {createThemeExtension, updateEditorTheme} from '@/views/editor/basic/themeExtension';
@@ -14,6 +15,12 @@ import {createFontExtensionFromBackend, updateFontConfig} from '@/views/editor/b
 import {createStatsUpdateExtension} from '@/views/editor/basic/statsExtension';
 import {createContentChangePlugin} from '@/views/editor/basic/contentChangeExtension';
 import {createWheelZoomExtension} from '@/views/editor/basic/wheelZoomExtension';
+import {
+    applyFollowChanges,
+    applyFollowState,
+    createFollowExtension,
+    publishFollowSnapshot
+} from '@/views/editor/basic/followExtension';
 import {createDynamicKeymapExtension, updateKeymapExtension} from '@/views/editor/keymap';
 import {
     createDynamicExtensions,
@@ -22,6 +29,7 @@ import {
     setExtensionManagerView
 } from '@/views/editor/manager';
 import {useExtensionStore} from './extensionStore';
+import {useWindowStore} from './windowStore';
 import createCodeBlockExtension, {blockState} from "@/views/editor/extensions/codeblock";
 import {LruCache} from '@/common/utils/lruCache';
 import {AsyncManager} from '@/common/utils/asyncManager';
@@ -49,6 +57,7 @@ interface EditorInstance {
     isDirty: boolean;
     lastModified: Date;
     autoSaveTimer: TimerManager;
+    journalTimer: TimerManager;
     syntaxTreeCache: {
         lastDocLength: number;
         lastContentHash: string;
@@ -62,6 +71,7 @@ export const useEditorStore = defineStore('editor', () => {
     // === 依赖store ===
     const configStore = useConfigStore();
     const documentStore = useDocumentStore();
+    const windowStore = useWindowStore();
     const extensionStore = useExtensionStore();
 
     // === 核心状态 ===
@@ -83,6 +93,18 @@ export const useEditorStore = defineStore('editor', () => {
     // 异步操作管理器
     const operationManager = new AsyncManager<number>();
 
+    // 同一文档在主窗口和文档窗口中重复打开时的跟随状态
+    const followView = windowStore.isMainWindow ? FollowView.FollowViewMain : FollowView.FollowViewWindow;
+    const followStates = reactive(new Map<number, FollowState>());
+    const isFollowEditor = (documentId: number) => {
+        const state = followStates.get(documentId);
+        return !!state?.duplicated && state.editor === followView;
+    };
+    const isFollowReadOnly = (documentId: number) => {
+        const state = followStates.get(documentId);
+        return !!state?.duplicated && state.editor !== followView;
+    };
+
     // 自动保存设置 - 从配置动态获取
     const getAutoSaveDelay = () => configStore.config.editing.autoSaveDelay;
 
@@ -262,6 +284,14 @@ export const useEditorStore = defineStore('editor', () => {
 
         const httpExtension = createHttpClientExtension();
 
+        // 跟随扩展
+        const followExtension = createFollowExtension(documentId, followView, () => isFollowEditor(documentId));
+
+        // 日志文档只能通过后台粘贴或 API 追加，锁定的文档不能修改，编辑器只读
+        const doc = documentStore.documents[documentId];
+        const kind = doc?.kind;
+        const journalExtension = EditorState.readOnly.of(kind === 'journal' || kind === 'journal_archive' || !!doc?.is_locked);
+
 
         // 再次检查操作有效性
         if (!operationManager.isOperationValid(operationId, documentId)) {
@@ -297,6 +327,8 @@ export const useEditorStore = defineStore('editor', () => {
             codeBlockExtension,
             ...dynamicExtensions,
             ...httpExtension,
+            followExtension,
+            journalExtension,
         ];
 
         // 创建编辑器状态
@@ -319,6 +351,7 @@ export const useEditorStore = defineStore('editor', () => {
             isDirty: false,
             lastModified: new Date(),
             autoSaveTimer: createTimerManager(),
+            journalTimer: createTimerManager(),
             syntaxTreeCache: null,
             // 修复：创建实例时从 documentStore 读取持久化的编辑器状态
             editorState: documentStore.documentStates[documentId]
@@ -337,6 +370,53 @@ export const useEditorStore = defineStore('editor', () => {
 
         // 初始化语法树缓存
         ensureSyntaxTreeCached(view, documentId);
+
+        // 同步跟随状态
+        WindowService.GetFollowState(documentId).then((state) => {
+            if (state) handleFollowState(state);
+        }).catch((error) => {
+            console.error('Failed to get follow state:', error);
+        });
+    };
+
+    // 当前文档的跟随状态
+    const currentFollow = computed(() => {
+        const documentId = documentStore.currentDocumentId;
+        return documentId ? followStates.get(documentId) ?? null : null;
+    });
+    const isCurrentFollowReadOnly = computed(() => {
+        const documentId = documentStore.currentDocumentId;
+        return !!documentId && isFollowReadOnly(documentId);
+    });
+
+    // 在当前视图接管编辑，另一视图转为只读跟随
+    const takeOverEditing = async () => {
+        const documentId = documentStore.currentDocumentId;
+        if (!documentId || !isFollowReadOnly(documentId)) return;
+        const mode = followView === FollowView.FollowViewMain ? FollowMode.FollowModeMirror : FollowMode.FollowModeTakeover;
+        const state = await WindowService.SetFollowMode(documentId, mode);
+        if (state) handleFollowState(state);
+    };
+
+    // 应用跟随状态：只读视图停止自动保存，可编辑视图发送完整内容
+    const handleFollowState = (state: FollowState) => {
+        followStates.set(state.documentId, state);
+        const instance = editorCache.get(state.documentId);
+        if (!instance) return;
+
+        const readOnly = applyFollowState(instance.view, state, followView);
+        if (readOnly) {
+            instance.autoSaveTimer.clear();
+            instance.journalTimer.clear();
+            if (instance.isDirty) {
+                instance.isDirty = false;
+                reportDirty(state.documentId, false);
+            }
+        } else if (state.duplicated) {
+            publishFollowSnapshot(state.documentId, followView, instance.view).catch((error) => {
+                console.error('Failed to publish follow snapshot:', error);
+            });
+        }
     };
 
     // 获取或创建编辑器
@@ -416,6 +496,16 @@ export const useEditorStore = defineStore('editor', () => {
         }
     };
 
+    // 向后端上报未保存状态：文档窗口关闭时据此提示保存，主窗口据此组合标题
+    const reportDirty = (documentId: number, dirty: boolean) => {
+        const report = windowStore.isMainWindow
+            ? WindowService.SetMainDocumentDirty(documentId, dirty)
+            : WindowService.SetDocumentDirty(documentId, dirty);
+        report.catch((error) => {
+            console.error('Failed to report dirty state:', error);
+        });
+    };
+
     // 保存编辑器内容
     const saveEditorContent = async (documentId: number): Promise<boolean> => {
         const instance = editorCache.get(documentId);
@@ -432,6 +522,9 @@ export const useEditorStore = defineStore('editor', () => {
                 instance.content = content;
                 instance.isDirty = false;
                 instance.lastModified = new Date();
+                reportDirty(documentId, false);
+                instance.journalTimer.clear();
+                SessionService.ClearJournal(documentId).catch(() => {});
             }
             // 如果内容在保存期间被修改了，保持 isDirty 状态
 
@@ -449,19 +542,80 @@ export const useEditorStore = defineStore('editor', () => {
         const instance = editorCache.get(documentId);
         if (!instance) return;
 
+        // 只读跟随的视图由另一视图保存
+        if (isFollowReadOnly(documentId)) {
+            instance.content = instance.view.state.doc.toString();
+            return;
+        }
+
         // 立即设置脏标记和修改时间（切换文档时需要判断）
+        if (!instance.isDirty) {
+            reportDirty(documentId, true);
+        }
         instance.isDirty = true;
         instance.lastModified = new Date();
         
         // 优使用防抖清理语法树缓存
         debouncedClearSyntaxCache.debouncedFn(instance);
 
+        // 记录自动保存日志，异常退出后可恢复未保存的内容
+        instance.journalTimer.set(() => {
+            SessionService.JournalDocument(documentId, instance.view.state.doc.toString()).catch((error) => {
+                console.error('Failed to journal document:', error);
+            });
+        }, EDITOR_CONFIG.JOURNAL_DELAY);
+
         // 设置自动保存定时器（已经是防抖效果：每次重置定时器）
         instance.autoSaveTimer.set(() => {
             saveEditorContent(documentId);
         }, getAutoSaveDelay());
     };
 
+    // 关闭文档窗口前用户选择保存时立即保存
+    Events.On('document:save-requested', async (event) => {
+        const documentId = Number(event.data);
+        const instance = editorCache.get(documentId);
+        if (!instance) return;
+        instance.autoSaveTimer.clear();
+        await saveEditorContent(documentId);
+        if (!instance.isDirty) {
+            reportDirty(documentId, false);
+        }
+    });
+
+    // 锁定期间保存失败的内容保持未保存状态，解锁后立即保存
+    Events.On('app:unlocked', () => {
+        editorCache.values().forEach((instance) => {
+            if (instance.isDirty) {
+                instance.autoSaveTimer.clear();
+                void saveEditorContent(instance.documentId);
+            }
+        });
+    });
+
+    // 文档在另一窗口中重复打开或跟随模式变化
+    Events.On('document:follow-changed', (event) => {
+        handleFollowState(event.data as FollowState);
+    });
+
+    // 另一视图的编辑
+    Events.On('document:follow-changes', (event) => {
+        const data = event.data as FollowChanges;
+        if (data.source === followView) return;
+        const instance = editorCache.get(data.documentId);
+        if (!instance) return;
+        applyFollowChanges(instance.view, data.changes);
+    });
+
+    // 主窗口上报当前文档，用于检测重复打开
+    if (windowStore.isMainWindow) {
+        watch(() => documentStore.currentDocumentId, (documentId) => {
+            WindowService.ReportMainDocument(documentId ?? 0).catch((error) => {
+                console.error('Failed to report main document:', error);
+            });
+        }, {immediate: true});
+    }
+
     // === 公共API ===
 
     // 设置编辑器容器
@@ -588,6 +742,7 @@ export const useEditorStore = defineStore('editor', () => {
 
                 // 清除自动保存定时器
                 instance.autoSaveTimer.clear();
+                instance.journalTimer.clear();
 
                 // 从扩展管理器中移除视图
                 removeExtensionManagerView(documentId);
@@ -680,6 +835,7 @@ export const useEditorStore = defineStore('editor', () => {
             
             // 清除自动保存定时器
             instance.autoSaveTimer.clear();
+            instance.journalTimer.clear();
             
             // 从扩展管理器移除
             removeExtensionManagerView(instance.documentId);
@@ -766,6 +922,8 @@ export const useEditorStore = defineStore('editor', () => {
         currentEditor,
         documentStats,
         isLoading,
+        currentFollow,
+        isCurrentFollowReadOnly,
 
         // 方法
         setEditorContainer,
@@ -773,6 +931,7 @@ export const useEditorStore = defineStore('editor', () => {
         removeEditor,
         clearAllEditors,
         onContentChange,
+        takeOverEditing,
 
         // 配置更新方法
         applyFontSettings,
@@ -1,7 +1,9 @@
 import { defineStore } from 'pinia';
 import { computed, readonly, ref, onScopeDispose } from 'vue';
-import { CheckForUpdates, ApplyUpdate, RestartApplication } from '@/../bindings/voidraft/internal/services/selfupdateservice';
+import { CheckForUpdates, CheckOnStartup, ApplyUpdate, RestartApplication } from '@/../bindings/voidraft/internal/services/selfupdateservice';
 import { SelfUpdateResult } from '@/../bindings/voidraft/internal/services/models';
+import { UpdateMode, WhatsNew } from '@/../bindings/voidraft/internal/models/models';
+import { GetWhatsNew, AcknowledgeWhatsNew } from '@/../bindings/voidraft/internal/services/whatsnewservice';
 import { useConfigStore } from './configStore';
 import { createTimerManager } from '@/common/utils/timerUtils';
 import * as runtime from "@wailsio/runtime";
@@ -38,6 +40,9 @@ export const useUpdateStore = defineStore('update', () => {
     status: UpdateStatus.IDLE
   });
 
+  // 升级后尚未确认的更新说明
+  const whatsNew = ref<WhatsNew | null>(null);
+
   // === 定时器管理 ===
   const statusTimer = createTimerManager();
   
@@ -192,10 +197,46 @@ export const useUpdateStore = defineStore('update', () => {
   const checkOnStartup = async (): Promise<void> => {
     if (hasCheckedOnStartup.value) return;
     
-    if (configStore.config.updates.autoUpdate) {
-      await checkForUpdates();
-    }
     hasCheckedOnStartup.value = true;
+
+    // 是否联网由后端按 updates.mode 决定，manual/offline 模式下返回空
+    try {
+      const result = await CheckOnStartup();
+      if (!result) return;
+      if (result.error) {
+        setUpdateStatus(UpdateStatus.ERROR, result, result.error);
+        return;
+      }
+      if (!result.hasUpdate) {
+        setUpdateStatus(UpdateStatus.IDLE, result);
+        return;
+      }
+      setUpdateStatus(UpdateStatus.UPDATE_AVAILABLE, result);
+      if (configStore.config.updates.mode === UpdateMode.UpdateModeAuto) {
+        await applyUpdate();
+      }
+    } catch (error) {
+      const message = isUpdateError(error) ? error.message : 'Network error';
+      setUpdateStatus(UpdateStatus.ERROR, undefined, message);
+    }
+  };
+
+  /**
+   * 加载升级后的更新说明，并监听后台获取完成的事件
+   */
+  const loadWhatsNew = async (): Promise<void> => {
+    runtime.Events.On('updates:whats-new', (event) => {
+      whatsNew.value = event.data as WhatsNew;
+    });
+    whatsNew.value = await GetWhatsNew();
+  };
+
+  /**
+   * 确认已查看更新说明
+   */
+  const acknowledgeWhatsNew = async (): Promise<void> => {
+    await AcknowledgeWhatsNew();
+    whatsNew.value = null;
   };
 
   /**
@@ -212,6 +253,7 @@ export const useUpdateStore = defineStore('update', () => {
   return {
     // 只读状态
     hasCheckedOnStartup: readonly(hasCheckedOnStartup),
+    whatsNew: readonly(whatsNew),
     
     // 计算属性
     isChecking,
@@ -227,6 +269,8 @@ export const useUpdateStore = defineStore('update', () => {
     applyUpdate,
     restartApplication,
     checkOnStartup,
+    loadWhatsNew,
+    acknowledgeWhatsNew,
     openReleaseURL,
     clearStatus,
     
@@ -1,11 +1,18 @@
 import {computed, ref} from 'vue';
 import {defineStore} from 'pinia';
-import {IsDocumentWindowOpen} from "@/../bindings/voidraft/internal/services/windowservice";
+import {Events} from '@wailsio/runtime';
+import {
+    GetSnapState,
+    IsDocumentWindowOpen,
+    UnsnapDocumentWindow
+} from "@/../bindings/voidraft/internal/services/windowservice";
+import {WindowSnapState} from '@/../bindings/voidraft/internal/models/models';
 
 
 export const useWindowStore = defineStore('window', () => {
 
     const DOCUMENT_ID_KEY = ref<string>('documentId');
+    const WINDOW_ID_KEY = ref<string>('windowId');
 
     // 判断是否为主窗口
     const isMainWindow = computed(() => {
@@ -19,6 +26,13 @@ export const useWindowStore = defineStore('window', () => {
         return urlParams.get(DOCUMENT_ID_KEY.value);
     });
 
+    // 获取当前文档窗口的windowId，一个文档窗口可以以标签打开多个文档
+    const currentWindowId = computed(() => {
+        const urlParams = new URLSearchParams(window.location.search);
+        const windowId = urlParams.get(WINDOW_ID_KEY.value);
+        return windowId ? parseInt(windowId) : null;
+    });
+
     /**
      * 判断文档窗口是否打开
      * @param documentId 文档ID
@@ -27,9 +41,44 @@ export const useWindowStore = defineStore('window', () => {
         return IsDocumentWindowOpen(documentId);
     }
 
+    // 当前文档窗口的吸附状态，主窗口始终为空
+    const snapState = ref<WindowSnapState | null>(null);
+
+    /**
+     * 加载文档所在文档窗口的吸附状态
+     * @param documentId 文档ID
+     */
+    async function loadSnapState(documentId: number) {
+        if (isMainWindow.value) return;
+        try {
+            snapState.value = await GetSnapState(documentId);
+        } catch (error) {
+            console.error('Failed to get snap state:', error);
+        }
+    }
+
+    /**
+     * 解除当前文档窗口的吸附，窗口留在原位
+     * @param documentId 文档ID
+     */
+    async function unsnap(documentId: number) {
+        await UnsnapDocumentWindow(documentId);
+    }
+
+    Events.On('window:snap-changed', (event) => {
+        const state = event.data as WindowSnapState;
+        if (state.windowId === currentWindowId.value) {
+            snapState.value = state;
+        }
+    });
+
     return {
         isMainWindow,
         currentDocumentId,
-        isDocumentWindowOpen
+        currentWindowId,
+        isDocumentWindowOpen,
+        snapState,
+        loadSnapState,
+        unsnap
     };
 });
\ No newline at end of file
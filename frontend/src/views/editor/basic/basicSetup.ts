@@ -14,6 +14,7 @@ import {bracketMatching, defaultHighlightStyle, indentOnInput, syntaxHighlightin
 import {history} from '@codemirror/commands';
 import {highlightSelectionMatches} from '@codemirror/search';
 import {closeBrackets, closeBracketsKeymap} from '@codemirror/autocomplete';
+import {spellcheck} from '../extensions/spellcheck/spellcheck';
 
 // 基本编辑器设置
 export const createBasicSetup = (): Extension[] => {
@@ -39,6 +40,9 @@ export const createBasicSetup = (): Extension[] => {
         bracketMatching(),
         closeBrackets(),
 
+        // 按块识别的语言检查拼写
+        spellcheck(),
+
         scrollPastEnd(),
 
         // 键盘映射
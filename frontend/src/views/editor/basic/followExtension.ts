@@ -0,0 +1,79 @@
+import {ChangeSet, Compartment, EditorState, Extension, Transaction} from '@codemirror/state';
+import {EditorView} from '@codemirror/view';
+import {FollowState, FollowView} from '@/../bindings/voidraft/internal/models/models';
+import {WindowService} from '@/../bindings/voidraft/internal/services';
+
+// 只读跟随状态的配置隔间
+const followCompartment = new Compartment();
+
+// 文档在另一视图中打开时跟随编辑，非可编辑视图只读
+const followReadOnly = (readOnly: boolean): Extension => [
+  EditorState.readOnly.of(readOnly),
+  EditorView.editable.of(!readOnly)
+];
+
+/**
+ * 创建跟随扩展：可编辑视图的每次修改都转发给另一视图
+ * @param documentId 文档ID
+ * @param view 当前窗口的视图类型
+ * @param isPublishing 当前文档是否处于跟随模式且由本视图编辑
+ */
+export function createFollowExtension(
+  documentId: number,
+  view: FollowView,
+  isPublishing: () => boolean
+): Extension {
+  return [
+    followCompartment.of(followReadOnly(false)),
+    EditorView.updateListener.of((update) => {
+      if (!update.docChanged || !isPublishing()) return;
+      // 来自另一视图的远程修改不再转发
+      if (update.transactions.some(tr => tr.annotation(Transaction.remote))) return;
+      WindowService.PublishDocumentChanges(documentId, view, JSON.stringify(update.changes.toJSON())).catch((error) => {
+        console.error('Failed to publish document changes:', error);
+      });
+    })
+  ];
+}
+
+/**
+ * 按跟随状态切换编辑器的只读状态
+ * @returns 当前视图是否只读
+ */
+export function applyFollowState(editor: EditorView, state: FollowState, view: FollowView): boolean {
+  const readOnly = state.duplicated && state.editor !== view;
+  editor.dispatch({effects: followCompartment.reconfigure(followReadOnly(readOnly))});
+  return readOnly;
+}
+
+/**
+ * 可编辑视图发送完整内容，跟随开始或切换编辑视图时保证两侧一致
+ */
+export async function publishFollowSnapshot(documentId: number, view: FollowView, editor: EditorView): Promise<void> {
+  await WindowService.PublishDocumentChanges(documentId, view, JSON.stringify({doc: editor.state.doc.toString()}));
+}
+
+/**
+ * 应用另一视图发出的修改或完整内容，不计入撤销历史
+ */
+export function applyFollowChanges(editor: EditorView, changes: string): void {
+  try {
+    const payload = JSON.parse(changes);
+    let changeSet: ChangeSet;
+    if (Array.isArray(payload)) {
+      changeSet = ChangeSet.fromJSON(payload);
+      if (changeSet.length !== editor.state.doc.length) {
+        console.warn('Follow changes do not match the current document, waiting for the next snapshot');
+        return;
+      }
+    } else {
+      changeSet = ChangeSet.of({from: 0, to: editor.state.doc.length, insert: String(payload.doc ?? '')}, editor.state.doc.length);
+    }
+    editor.dispatch({
+      changes: changeSet,
+      annotations: [Transaction.remote.of(true), Transaction.addToHistory.of(false)]
+    });
+  } catch (error) {
+    console.error('Failed to apply follow changes:', error);
+  }
+}
@@ -1,6 +1,7 @@
 /**
  * 代码块复制粘贴扩展
- * 防止复制分隔符标记，自动替换为换行符
+ * 防止复制分隔符标记，自动替换为换行符；粘贴终端输出时去除 ANSI 转义序列
+ * 在 Markdown 块中粘贴 HTML、RTF 富文本时按设置转换为 Markdown
  */
 
 import { EditorState, EditorSelection } from "@codemirror/state";
@@ -8,6 +9,44 @@ import { EditorView } from "@codemirror/view";
 import { Command } from "@codemirror/view";
 import { LANGUAGES } from "./lang-parser/languages";
 import { USER_EVENTS, codeBlockEvent, CONTENT_EDIT } from "./annotation";
+import { getActiveNoteBlock } from "./state";
+import { MarkdownService } from "@/../bindings/voidraft/internal/services";
+import { RichPasteMode } from "@/../bindings/voidraft/internal/models/models";
+import { useConfigStore } from "@/stores/configStore";
+import i18n from "@/i18n";
+
+/**
+ * 粘贴富文本：按设置转换为 Markdown，转换失败或结果为空时粘贴纯文本
+ */
+async function pasteRichText(view: EditorView, text: string, html: string, rtf: string) {
+  const mode = useConfigStore().config.editing.richPaste;
+  if (mode === RichPasteMode.RichPasteAsk && !window.confirm(i18n.global.t('settings.richPasteConfirm'))) {
+    doPaste(view, text);
+    return;
+  }
+  try {
+    const markdown = await MarkdownService.ConvertRichText(html, rtf);
+    doPaste(view, markdown || text);
+  } catch (error) {
+    console.error('Failed to convert rich text:', error);
+    doPaste(view, text);
+  }
+}
+
+/**
+ * 剪贴板中是否有需要转换的富文本，只在 Markdown 块中转换
+ * VS Code 等编辑器复制代码时附带的 HTML 只是高亮样式，按纯文本粘贴
+ */
+function shouldConvertRichText(view: EditorView, data: DataTransfer): boolean {
+  const mode = useConfigStore().config.editing.richPaste;
+  if (mode === RichPasteMode.RichPastePlain || data.types.includes("vscode-editor-data")) {
+    return false;
+  }
+  if (!data.types.includes("text/html") && !data.types.includes("text/rtf")) {
+    return false;
+  }
+  return getActiveNoteBlock(view.state)?.language.name === "md";
+}
 
 /**
  * 构建块分隔符正则表达式
@@ -15,6 +54,28 @@ import { USER_EVENTS, codeBlockEvent, CONTENT_EDIT } from "./annotation";
 const languageTokensMatcher = LANGUAGES.map(lang => lang.token).join("|");
 const blockSeparatorRegex = new RegExp(`\\n∞∞∞(${languageTokensMatcher})(-a)?\\n`, "g");
 
+/**
+ * ANSI 转义序列正则：CSI、OSC（以 BEL 或 ST 结束）、字符集选择和其他单字符序列
+ */
+const ansiEscapeRegex = /\x1b\[[0-?]*[ -/]*[@-~]|\u009b[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[()*+#%].|\x1b[@-_]/g;
+
+/**
+ * 去除终端输出中的 ANSI 转义序列，回车覆盖的行只保留最后一次输出
+ */
+function stripAnsi(text: string): string {
+  if (!/[\x1b\u009b]/.test(text)) {
+    return text;
+  }
+  return text
+    .replace(ansiEscapeRegex, "")
+    .split("\n")
+    .map(line => {
+      const parts = line.split("\r").filter(part => part !== "");
+      return parts.length > 0 ? parts[parts.length - 1] : "";
+    })
+    .join("\n");
+}
+
 /**
  * 获取被复制的范围和内容
  */
@@ -74,6 +135,24 @@ export const codeBlockCopyCut = EditorView.domEventHandlers({
     }
   },
   
+  paste(event, view) {
+    const data = event.clipboardData;
+    if (data && !view.state.readOnly && shouldConvertRichText(view, data)) {
+      event.preventDefault();
+      pasteRichText(view, data.getData("text/plain"), data.getData("text/html"), data.getData("text/rtf"));
+      return true;
+    }
+
+    // 粘贴带颜色的终端输出时去除转义序列，其余情况交给默认处理
+    const text = data?.getData("text/plain");
+    if (!text || !/[\x1b\u009b]/.test(text)) {
+      return false;
+    }
+    event.preventDefault();
+    doPaste(view, stripAnsi(text));
+    return true;
+  },
+
   cut(event, view) {
     let { text, ranges } = copiedRange(view.state, true);
     // 将块分隔符替换为双换行符
@@ -126,7 +205,7 @@ const copyCut = (view: EditorView, cut: boolean): boolean => {
  */
 function doPaste(view: EditorView, input: string) {
   const { state } = view;
-  const text = state.toText(input);
+  const text = state.toText(stripAnsi(input));
   const byLine = text.lines === state.selection.ranges.length;
   
   let changes: any;
@@ -6,6 +6,8 @@ import { useKeybindingStore } from '@/stores/keybindingStore';
 import { undo, redo } from '@codemirror/commands';
 import i18n from '@/i18n';
 import { useSystemStore } from '@/stores/systemStore';
+import { useDocumentStore } from '@/stores/documentStore';
+import { ExportService } from '../../../../../bindings/voidraft/internal/services';
 import { showContextMenu } from './manager';
 import {
   buildRegisteredMenu,
@@ -65,6 +67,17 @@ function getShortcutText(command?: KeyBindingCommand): string {
 }
 
 
+// 导出当前文档，保存位置由系统对话框选择
+function exportCurrentDocument(format: string): boolean {
+  const documentId = useDocumentStore().currentDocumentId;
+  if (!documentId) return false;
+  ExportService.ExportDocument(documentId, format, "").catch((error) => {
+    console.error("Failed to export document:", error);
+  });
+  return true;
+}
+
+
 function getBuiltinMenuNodes(): MenuSchemaNode[] {
   return [
     {
@@ -102,6 +115,25 @@ function getBuiltinMenuNodes(): MenuSchemaNode[] {
       command: redo,
       shortcutCommand: KeyBindingCommand.HistoryRedoCommand,
       visible: (context) => context.isEditable
+    },
+    {
+      id: "export-separator",
+      type: "separator"
+    },
+    {
+      id: "export-markdown",
+      labelKey: "exportDocument.markdown",
+      command: () => exportCurrentDocument("md")
+    },
+    {
+      id: "export-html",
+      labelKey: "exportDocument.html",
+      command: () => exportCurrentDocument("html")
+    },
+    {
+      id: "export-pdf",
+      labelKey: "exportDocument.pdf",
+      command: () => exportCurrentDocument("pdf")
     }
   ];
 }
@@ -21,6 +21,7 @@ import {
 } from '@codemirror/view';
 import DOMPurify from 'dompurify';
 import { LruCache } from '@/common/utils/lruCache';
+import { isAttachmentUrl, resolveAttachmentUrl } from '@/common/utils/attachmentUrl';
 
 interface HTMLBlockInfo {
 	from: number;
@@ -163,6 +164,18 @@ const htmlTooltipState = StateField.define<HTMLBlockInfo | null>({
 					dom.className = 'cm-html-tooltip';
 					dom.innerHTML = block.sanitized;
 
+					// Attachment URLs need the per-run token appended before loading
+					dom.querySelectorAll('img').forEach((imgEl) => {
+						const src = imgEl.getAttribute('src');
+						if (!src || !isAttachmentUrl(src)) return;
+						imgEl.removeAttribute('src');
+						resolveAttachmentUrl(src).then((resolved) => {
+							imgEl.src = resolved;
+						}, (err) => {
+							console.error('Failed to resolve attachment URL:', err);
+						});
+					});
+
 					// Prevent clicks inside tooltip from closing it
 					dom.addEventListener('click', (e) => {
 						e.stopPropagation();
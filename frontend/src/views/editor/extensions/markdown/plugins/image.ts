@@ -19,6 +19,7 @@ import {
 	showTooltip,
 	Tooltip
 } from '@codemirror/view';
+import { resolveAttachmentUrl } from '@/common/utils/attachmentUrl';
 
 interface ImageInfo {
 	src: string;
@@ -117,19 +118,24 @@ const imageTooltipState = StateField.define<ImageInfo | null>({
 					spinner.className = 'cm-image-spinner';
 
 					const imgEl = document.createElement('img');
-					imgEl.src = img.src;
 					imgEl.alt = img.alt;
 
-					imgEl.onload = () => {
-						dom.classList.remove('cm-image-loading');
-					};
-					imgEl.onerror = () => {
+					const showError = () => {
 						spinner.remove();
 						imgEl.remove();
 						dom.textContent = 'Failed to load image';
 						dom.classList.remove('cm-image-loading');
 						dom.classList.add('cm-image-tooltip-error');
 					};
+					imgEl.onload = () => {
+						dom.classList.remove('cm-image-loading');
+					};
+					imgEl.onerror = showError;
+
+					// Attachment URLs need the per-run token appended before loading
+					resolveAttachmentUrl(img.src).then((src) => {
+						imgEl.src = src;
+					}, showError);
 
 					dom.append(spinner, imgEl);
 
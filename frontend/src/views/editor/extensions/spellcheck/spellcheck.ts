@@ -1,8 +1,115 @@
-import type { Extension } from '@codemirror/state'
-import { EditorView } from '@codemirror/view'
+import {RangeSetBuilder, StateEffect, StateField, type Extension} from '@codemirror/state'
+import {Decoration, type DecorationSet, EditorView, ViewPlugin, type ViewUpdate} from '@codemirror/view'
+import {TranslationService} from '@/../bindings/voidraft/internal/services'
+import {blockState} from '../codeblock/state'
+import type {Block} from '../codeblock/types'
 
-export const spellcheck = (): Extension => {
-  return EditorView.contentAttributes.of({
-    spellcheck: 'true',
+// 文本块，只对这些块做拼写检查
+const PROSE_LANGUAGES = new Set(['text', 'md'])
+
+// 停止输入后重新识别语言的延迟
+const DETECT_DELAY = 800
+
+// 按内容缓存识别结果，编辑一个块时其余块不必重新识别
+const detected = new Map<string, string>()
+const MAX_CACHED = 500
+
+const setBlockLanguages = StateEffect.define<string[]>()
+
+// 各块识别出的自然语言，与 blockState 按序号对应，代码块和无法识别的块为空
+const blockLanguages = StateField.define<string[]>({
+  create: () => [],
+  update(languages, tr) {
+    for (const effect of tr.effects) {
+      if (effect.is(setBlockLanguages)) return effect.value
+    }
+    return languages
+  },
+})
+
+const isProse = (block: Block) => PROSE_LANGUAGES.has(block.language.name)
+
+// 文本块的行标注识别出的语言，浏览器按语言选择拼写检查词典；代码块的行关闭拼写检查
+function buildDecorations(view: EditorView): DecorationSet {
+  const builder = new RangeSetBuilder<Decoration>()
+  const blocks = view.state.field(blockState, false) ?? []
+  const languages = view.state.field(blockLanguages)
+  const noSpellcheck = Decoration.line({attributes: {spellcheck: 'false'}})
+
+  blocks.forEach((block, index) => {
+    const from = view.state.doc.lineAt(block.content.from)
+    const to = view.state.doc.lineAt(block.content.to)
+    let decoration: Decoration | null = noSpellcheck
+    if (isProse(block)) {
+      const lang = languages[index]
+      decoration = lang ? Decoration.line({attributes: {lang}}) : null
+    }
+    if (!decoration) return
+    for (let n = from.number; n <= to.number; n++) {
+      builder.add(view.state.doc.line(n).from, view.state.doc.line(n).from, decoration)
+    }
   })
+  return builder.finish()
+}
+
+const detector = ViewPlugin.fromClass(class {
+  decorations: DecorationSet
+  private timer: number | undefined
+
+  constructor(private view: EditorView) {
+    this.decorations = buildDecorations(view)
+    this.schedule()
+  }
+
+  update(update: ViewUpdate) {
+    if (update.docChanged) this.schedule()
+    if (update.docChanged || update.transactions.some(tr => tr.effects.some(e => e.is(setBlockLanguages)))) {
+      this.decorations = buildDecorations(update.view)
+    }
+  }
+
+  schedule() {
+    clearTimeout(this.timer)
+    this.timer = window.setTimeout(() => this.detect(), DETECT_DELAY)
+  }
+
+  async detect() {
+    const blocks = this.view.state.field(blockState, false) ?? []
+    const texts = blocks.map(block => isProse(block) ? this.view.state.doc.sliceString(block.content.from, block.content.to) : '')
+    const pending = [...new Set(texts.filter(text => text.trim() && !detected.has(text)))]
+
+    if (pending.length > 0) {
+      try {
+        const results = await TranslationService.DetectLanguages(pending)
+        if (detected.size + pending.length > MAX_CACHED) detected.clear()
+        pending.forEach((text, i) => detected.set(text, results[i] ?? ''))
+      } catch (error) {
+        console.error('Failed to detect block languages:', error)
+        return
+      }
+    }
+
+    // 等待期间文档已变化时由下一次识别更新
+    if (this.view.state.field(blockState, false) !== blocks) return
+    this.view.dispatch({effects: setBlockLanguages.of(texts.map(text => detected.get(text) ?? ''))})
+  }
+
+  destroy() {
+    clearTimeout(this.timer)
+  }
+}, {
+  decorations: plugin => plugin.decorations,
+})
+
+/**
+ * 拼写检查：按块识别自然语言，浏览器按各块的语言检查拼写，代码块不检查
+ */
+export const spellcheck = (): Extension => {
+  return [
+    EditorView.contentAttributes.of({
+      spellcheck: 'true',
+    }),
+    blockLanguages,
+    detector,
+  ]
 }
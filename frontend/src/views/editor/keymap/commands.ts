@@ -49,6 +49,7 @@ import {
 } from '@codemirror/commands';
 import {foldAll, foldCode, unfoldAll, unfoldCode} from '@codemirror/language';
 import i18n from '@/i18n';
+import {useAppLockStore} from '@/stores/appLockStore';
 
 // 默认编辑器选项
 const defaultEditorOptions = {
@@ -258,6 +259,13 @@ export const commands = {
         handler: deleteGroupForward,
         descriptionKey: 'keybindings.commands.deleteGroupForward'
     },
+    [KeyBindingCommand.LockAppCommand]: {
+        handler: () => {
+            void useAppLockStore().lock();
+            return true;
+        },
+        descriptionKey: 'keybindings.commands.lockApp'
+    },
 } as const;
 
 /**
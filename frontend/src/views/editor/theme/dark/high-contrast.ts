@@ -0,0 +1,116 @@
+import {Extension} from '@codemirror/state';
+import {createBaseTheme} from '../base';
+import type {ThemeColors} from '../types';
+
+// 高对比度主题：所有文字与背景的对比度不低于 7:1（WCAG AAA）
+export const config: ThemeColors = {
+  themeName: 'high-contrast',
+  dark: true,
+
+  background: '#000000',
+  backgroundSecondary: '#0c0c0c',
+
+  foreground: '#ffffff',
+  cursor: '#ffff00',
+  selection: '#1a4b8c',
+  activeLine: '#ffffff14',
+  lineNumber: '#bfbfbf',
+  activeLineNumber: '#ffffff',
+  diffInserted: '#7cff7c',
+  diffDeleted: '#ff8c8c',
+  diffChanged: '#ffd866',
+  borderColor: '#6fc3df',
+  matchingBracket: '#ffffff40',
+
+  // 搜索匹配 - 使用描边保证可辨识
+  searchMatch: 'rgba(255, 255, 0, 0.35)',
+  searchMatchSelected: 'rgba(255, 165, 0, 0.6)',
+  searchMatchSelectedOutline: '#ffff00',
+
+  comment: '#c8c8c8',
+  lineComment: '#c8c8c8',
+  blockComment: '#c8c8c8',
+  docComment: '#d7d7d7',
+  name: '#ffffff',
+  variableName: '#9cdcfe',
+  typeName: '#4ec9b0',
+  tagName: '#569cd6',
+  propertyName: '#9cdcfe',
+  attributeName: '#9cdcfe',
+  className: '#4ec9b0',
+  labelName: '#c8c8c8',
+  namespace: '#4ec9b0',
+  macroName: '#dcdcaa',
+  literal: '#b5cea8',
+  string: '#ce9178',
+  docString: '#ce9178',
+  character: '#ce9178',
+  attributeValue: '#ce9178',
+  number: '#b5cea8',
+  integer: '#b5cea8',
+  float: '#b5cea8',
+  bool: '#569cd6',
+  regexp: '#f08080',
+  escape: '#d7ba7d',
+  color: '#ce9178',
+  url: '#5fb3ff',
+  keyword: '#569cd6',
+  self: '#569cd6',
+  null: '#569cd6',
+  atom: '#569cd6',
+  unit: '#b5cea8',
+  modifier: '#569cd6',
+  operatorKeyword: '#569cd6',
+  controlKeyword: '#c586c0',
+  definitionKeyword: '#569cd6',
+  moduleKeyword: '#c586c0',
+  operator: '#ffffff',
+  derefOperator: '#ffffff',
+  arithmeticOperator: '#ffffff',
+  logicOperator: '#ffffff',
+  bitwiseOperator: '#ffffff',
+  compareOperator: '#ffffff',
+  updateOperator: '#ffffff',
+  definitionOperator: '#ffffff',
+  typeOperator: '#ffffff',
+  controlOperator: '#ffffff',
+  punctuation: '#ffffff',
+  separator: '#ffffff',
+  bracket: '#ffffff',
+  angleBracket: '#c8c8c8',
+  squareBracket: '#ffffff',
+  paren: '#ffffff',
+  brace: '#ffffff',
+  content: '#ffffff',
+  heading: '#6fc3df',
+  heading1: '#6fc3df',
+  heading2: '#6fc3df',
+  heading3: '#6fc3df',
+  heading4: '#6fc3df',
+  heading5: '#6fc3df',
+  heading6: '#6fc3df',
+  contentSeparator: '#ffffff',
+  list: '#ffffff',
+  quote: '#c8c8c8',
+  emphasis: '#ffffff',
+  strong: '#ffffff',
+  link: '#5fb3ff',
+  monospace: '#ce9178',
+  strikethrough: '#c8c8c8',
+  inserted: '#7cff7c',
+  deleted: '#ff8c8c',
+  changed: '#ffd866',
+  invalid: '#ff8c8c',
+  meta: '#c8c8c8',
+  documentMeta: '#c8c8c8',
+  annotation: '#dcdcaa',
+  processingInstruction: '#c8c8c8',
+  definition: '#dcdcaa',
+  constant: '#4fc1ff',
+  function: '#dcdcaa',
+  standard: '#dcdcaa',
+  local: '#9cdcfe',
+  special: '#d7ba7d',
+};
+
+export const highContrast: Extension = createBaseTheme(config);
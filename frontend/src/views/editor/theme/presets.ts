@@ -10,6 +10,7 @@ import {config as oneDarkColors} from './dark/one-dark';
 import {config as solarizedDarkColors} from './dark/solarized-dark';
 import {config as tokyoNightColors} from './dark/tokyo-night';
 import {config as tokyoNightStormColors} from './dark/tokyo-night-storm';
+import {config as highContrastColors} from './dark/high-contrast';
 import {config as githubLightColors} from './light/github-light';
 import {config as materialLightColors} from './light/material-light';
 import {config as solarizedLightColors} from './light/solarized-light';
@@ -33,6 +34,7 @@ export const themePresetList: ThemePreset[] = [
     {name: solarizedDarkColors.themeName, type: ThemeType.ThemeTypeDark, colors: solarizedDarkColors},
     {name: tokyoNightColors.themeName, type: ThemeType.ThemeTypeDark, colors: tokyoNightColors},
     {name: tokyoNightStormColors.themeName, type: ThemeType.ThemeTypeDark, colors: tokyoNightStormColors},
+    {name: highContrastColors.themeName, type: ThemeType.ThemeTypeDark, colors: highContrastColors},
     {name: defaultLightColors.themeName, type: ThemeType.ThemeTypeLight, colors: defaultLightColors},
     {name: githubLightColors.themeName, type: ThemeType.ThemeTypeLight, colors: githubLightColors},
     {name: materialLightColors.themeName, type: ThemeType.ThemeTypeLight, colors: materialLightColors},
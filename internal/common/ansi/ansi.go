@@ -0,0 +1,198 @@
+// Package ansi 处理终端输出中的 ANSI 转义序列：去除后得到纯文本，或将颜色和样式转换为 HTML
+package ansi
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// Contains 文本中是否包含 ESC 或 C1 CSI 控制字符
+func Contains(s string) bool {
+	return strings.ContainsRune(s, 0x1b) || strings.ContainsRune(s, 0x9b)
+}
+
+// Strip 去除转义序列，并按终端的显示效果处理回车覆盖和退格
+func Strip(s string) string {
+	var buf strings.Builder
+	scan(normalize(s), func(text string) {
+		buf.WriteString(text)
+	}, nil)
+	return buf.String()
+}
+
+// ToHTML 将带颜色的终端输出转换为 HTML 片段，颜色和样式以内联样式保留
+func ToHTML(s string) string {
+	var buf strings.Builder
+	var st state
+	buf.WriteString(`<pre class="ansi">`)
+	scan(normalize(s), func(text string) {
+		if text == "" {
+			return
+		}
+		style := st.css()
+		if style != "" {
+			fmt.Fprintf(&buf, `<span style="%s">`, style)
+		}
+		buf.WriteString(html.EscapeString(text))
+		if style != "" {
+			buf.WriteString("</span>")
+		}
+	}, func(params []int) {
+		st.apply(params)
+	})
+	buf.WriteString("</pre>")
+	return buf.String()
+}
+
+// normalize 统一换行，按行处理回车覆盖：保留最后一次覆盖的文本和之前出现的样式序列
+func normalize(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	if !strings.ContainsAny(s, "\r\b") {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.Contains(line, "\r") {
+			parts := strings.Split(line, "\r")
+			last := len(parts) - 1
+			for last > 0 && parts[last] == "" {
+				last--
+			}
+			var prefix strings.Builder
+			for _, part := range parts[:last] {
+				scan(part, nil, func(params []int) {
+					prefix.WriteString(sgr(params))
+				})
+			}
+			line = prefix.String() + parts[last]
+		}
+		lines[i] = backspace(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// backspace 处理退格，man 等程序用 "x\bx" 表示粗体、"_\bx" 表示下划线
+func backspace(line string) string {
+	if !strings.Contains(line, "\b") {
+		return line
+	}
+	out := make([]rune, 0, len(line))
+	for _, r := range line {
+		if r == '\b' {
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+			continue
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+// sgr 重新生成样式序列
+func sgr(params []int) string {
+	parts := make([]string, len(params))
+	for i, p := range params {
+		parts[i] = strconv.Itoa(p)
+	}
+	return "\x1b[" + strings.Join(parts, ";") + "m"
+}
+
+// scan 遍历文本，text 接收普通文本，style 接收 SGR 参数；其他控制序列直接丢弃
+func scan(s string, text func(string), style func([]int)) {
+	start := 0
+	flush := func(end int) {
+		if text != nil && end > start {
+			text(s[start:end])
+		}
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == 0x1b && i+1 < len(s):
+			flush(i)
+			i = skipEscape(s, i+1, style)
+			start = i
+		case c == 0x1b:
+			flush(i)
+			i++
+			start = i
+		case c == 0xc2 && i+1 < len(s) && s[i+1] == 0x9b:
+			// UTF-8 编码的 C1 CSI
+			flush(i)
+			i = skipCSI(s, i+2, style)
+			start = i
+		case c < 0x20 && c != '\n' && c != '\t':
+			// 其余 C0 控制字符（响铃等）不显示
+			flush(i)
+			i++
+			start = i
+		default:
+			i++
+		}
+	}
+	flush(len(s))
+}
+
+// skipEscape 跳过 ESC 之后的序列，返回序列结束后的位置
+func skipEscape(s string, i int, style func([]int)) int {
+	switch s[i] {
+	case '[':
+		return skipCSI(s, i+1, style)
+	case ']', 'P', '_', '^', 'X':
+		// OSC/DCS 等字符串序列，以 BEL 或 ST 结束，OSC 8 超链接只保留链接文字
+		for j := i + 1; j < len(s); j++ {
+			if s[j] == 0x07 {
+				return j + 1
+			}
+			if s[j] == 0x1b && j+1 < len(s) && s[j+1] == '\\' {
+				return j + 2
+			}
+		}
+		return len(s)
+	case '(', ')', '*', '+', '#', '%':
+		// 字符集选择，带一个参数字节
+		return min(i+2, len(s))
+	default:
+		return i + 1
+	}
+}
+
+// skipCSI 跳过 CSI 序列，SGR 序列（以 m 结尾）交给 style 处理
+func skipCSI(s string, i int, style func([]int)) int {
+	j := i
+	for j < len(s) && s[j] >= 0x30 && s[j] <= 0x3f {
+		j++
+	}
+	params := s[i:j]
+	for j < len(s) && s[j] >= 0x20 && s[j] <= 0x2f {
+		j++
+	}
+	if j >= len(s) {
+		return len(s)
+	}
+	if s[j] == 'm' && style != nil {
+		style(parseParams(params))
+	}
+	return j + 1
+}
+
+// parseParams 解析 SGR 参数，空参数按 0 处理
+// 冒号分隔的 38:2::r:g:b 形式带有色彩空间参数，转换为分号形式
+func parseParams(params string) []int {
+	var out []int
+	for _, group := range strings.Split(params, ";") {
+		fields := strings.Split(group, ":")
+		if len(fields) == 6 && fields[1] == "2" {
+			fields = append(fields[:2], fields[3:]...)
+		}
+		for _, f := range fields {
+			n, _ := strconv.Atoi(f)
+			out = append(out, n)
+		}
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package ansi
+
+import "testing"
+
+func TestStrip(t *testing.T) {
+	cases := map[string]string{
+		"\x1b[1;31merror\x1b[0m: failed":                  "error: failed",
+		"plain text\n":                                    "plain text\n",
+		"\x1b]8;;https://example.com\x07link\x1b]8;;\x07": "link",
+		"\x1b[2K\x1b[1Gdone":                              "done",
+		"10%\r50%\r100%\n":                                "100%\n",
+		"line\r\nnext":                                    "line\nnext",
+		"N\bNA\bAM\bME\bE":                                "NAME",
+		"\x1b(Bok\x07":                                    "ok",
+		"\u009b32mgreen\u009b0m":                          "green",
+	}
+	for in, want := range cases {
+		if got := Strip(in); got != want {
+			t.Errorf("Strip(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestToHTML(t *testing.T) {
+	cases := map[string]string{
+		"\x1b[1;31mERR\x1b[0m <x>":       `<pre class="ansi"><span style="color:#cd3131;font-weight:bold">ERR</span> &lt;x&gt;</pre>`,
+		"\x1b[38;5;196mA\x1b[39mB":       `<pre class="ansi"><span style="color:#ff0000">A</span>B</pre>`,
+		"\x1b[48;2;16;32;48mA":           `<pre class="ansi"><span style="background-color:#102030">A</span></pre>`,
+		"\x1b[38:2::1:2:3mA":             `<pre class="ansi"><span style="color:#010203">A</span></pre>`,
+		"\x1b[7mA":                       `<pre class="ansi"><span style="color:Canvas;background-color:CanvasText">A</span></pre>`,
+		"\x1b[32mold\rnew\x1b[m":         `<pre class="ansi"><span style="color:#0dbc79">new</span></pre>`,
+		"\x1b[4;9mA\x1b[24mB\x1b[38;5mC": `<pre class="ansi"><span style="text-decoration:underline line-through">A</span><span style="text-decoration:line-through">B</span><span style="text-decoration:line-through">C</span></pre>`,
+	}
+	for in, want := range cases {
+		if got := ToHTML(in); got != want {
+			t.Errorf("ToHTML(%q) =\n%s\nwant\n%s", in, got, want)
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	if Contains("plain") || !Contains("\x1b[0m") {
+		t.Error("Contains mismatch")
+	}
+}
@@ -0,0 +1,161 @@
+package ansi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// palette 16 色调色板，取常见终端的默认配色
+var palette = [16]string{
+	"#000000", "#cd3131", "#0dbc79", "#e5e510", "#2472c8", "#bc3fbc", "#11a8cd", "#e5e5e5",
+	"#666666", "#f14c4c", "#23d18b", "#f5f543", "#3b8eea", "#d670d6", "#29b8db", "#ffffff",
+}
+
+// state 当前生效的文本样式
+type state struct {
+	fg, bg    string
+	bold      bool
+	dim       bool
+	italic    bool
+	underline bool
+	inverse   bool
+	hidden    bool
+	strike    bool
+}
+
+// apply 应用一组 SGR 参数
+func (st *state) apply(params []int) {
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+		switch {
+		case p == 0:
+			*st = state{}
+		case p == 1:
+			st.bold = true
+		case p == 2:
+			st.dim = true
+		case p == 3:
+			st.italic = true
+		case p == 4:
+			st.underline = true
+		case p == 7:
+			st.inverse = true
+		case p == 8:
+			st.hidden = true
+		case p == 9:
+			st.strike = true
+		case p == 22:
+			st.bold, st.dim = false, false
+		case p == 23:
+			st.italic = false
+		case p == 24:
+			st.underline = false
+		case p == 27:
+			st.inverse = false
+		case p == 28:
+			st.hidden = false
+		case p == 29:
+			st.strike = false
+		case p >= 30 && p <= 37:
+			st.fg = palette[p-30]
+		case p >= 90 && p <= 97:
+			st.fg = palette[p-90+8]
+		case p == 39:
+			st.fg = ""
+		case p >= 40 && p <= 47:
+			st.bg = palette[p-40]
+		case p >= 100 && p <= 107:
+			st.bg = palette[p-100+8]
+		case p == 49:
+			st.bg = ""
+		case p == 38 || p == 48:
+			color, n := extendedColor(params[i+1:])
+			i += n
+			if color == "" {
+				continue
+			}
+			if p == 38 {
+				st.fg = color
+			} else {
+				st.bg = color
+			}
+		}
+	}
+}
+
+// extendedColor 解析 5;n（256 色）或 2;r;g;b（真彩色），返回颜色和消耗的参数个数
+func extendedColor(params []int) (string, int) {
+	if len(params) >= 2 && params[0] == 5 {
+		return color256(params[1]), 2
+	}
+	if len(params) >= 4 && params[0] == 2 {
+		return fmt.Sprintf("#%02x%02x%02x", clamp(params[1]), clamp(params[2]), clamp(params[3])), 4
+	}
+	return "", len(params)
+}
+
+// color256 256 色调色板：16 个基本色、6x6x6 色立方和 24 级灰度
+func color256(n int) string {
+	switch {
+	case n < 0 || n > 255:
+		return ""
+	case n < 16:
+		return palette[n]
+	case n < 232:
+		levels := [6]int{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return fmt.Sprintf("#%02x%02x%02x", levels[n/36], levels[n/6%6], levels[n%6])
+	default:
+		gray := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+	}
+}
+
+func clamp(v int) int {
+	return max(0, min(255, v))
+}
+
+// css 生成内联样式，反显时交换前景色和背景色
+func (st *state) css() string {
+	fg, bg := st.fg, st.bg
+	if st.inverse {
+		fg, bg = bg, fg
+		if fg == "" {
+			fg = "Canvas"
+		}
+		if bg == "" {
+			bg = "CanvasText"
+		}
+	}
+
+	var parts []string
+	if fg != "" {
+		parts = append(parts, "color:"+fg)
+	}
+	if bg != "" {
+		parts = append(parts, "background-color:"+bg)
+	}
+	if st.bold {
+		parts = append(parts, "font-weight:bold")
+	}
+	if st.dim {
+		parts = append(parts, "opacity:0.7")
+	}
+	if st.italic {
+		parts = append(parts, "font-style:italic")
+	}
+	var decorations []string
+	if st.underline {
+		decorations = append(decorations, "underline")
+	}
+	if st.strike {
+		decorations = append(decorations, "line-through")
+	}
+	if len(decorations) > 0 {
+		parts = append(parts, "text-decoration:"+strings.Join(decorations, " "))
+	}
+	if st.hidden {
+		parts = append(parts, "visibility:hidden")
+	}
+	return strings.Join(parts, ";")
+}
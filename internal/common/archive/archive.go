@@ -0,0 +1,141 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// FormatVersion 归档格式版本
+const FormatVersion = 1
+
+// manifestName 清单文件名
+const manifestName = "manifest.json"
+
+// maxEntrySize 单个文档的最大解压大小，防止恶意归档耗尽内存
+const maxEntrySize = 256 << 20
+
+// Entry 归档中的单个文档
+type Entry struct {
+	ID        int64  `json:"id"`
+	Title     string `json:"title"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+	IsLocked  bool   `json:"isLocked"`
+	File      string `json:"file"`
+	Content   string `json:"-"`
+}
+
+// Manifest 归档清单
+type Manifest struct {
+	Version    int     `json:"version"`
+	AppVersion string  `json:"appVersion"`
+	ExportedAt string  `json:"exportedAt"`
+	Documents  []Entry `json:"documents"`
+}
+
+// Build 将文档打包为zip归档
+func Build(entries []Entry, appVersion string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	manifest := Manifest{
+		Version:    FormatVersion,
+		AppVersion: appVersion,
+		ExportedAt: time.Now().Format(time.RFC3339),
+		Documents:  make([]Entry, 0, len(entries)),
+	}
+
+	for _, entry := range entries {
+		entry.File = fmt.Sprintf("documents/%d.txt", entry.ID)
+		w, err := zw.Create(entry.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create archive entry: %w", err)
+		}
+		if _, err := io.WriteString(w, entry.Content); err != nil {
+			return nil, fmt.Errorf("failed to write archive entry: %w", err)
+		}
+		manifest.Documents = append(manifest.Documents, entry)
+	}
+
+	w, err := zw.Create(manifestName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create manifest: %w", err)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Parse 解析zip归档，文档内容填充到清单的 Documents 中
+func Parse(data []byte) (*Manifest, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	manifestFile, ok := files[manifestName]
+	if !ok {
+		return nil, errors.New("invalid archive: manifest not found")
+	}
+	manifestData, err := readZipFile(manifestFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if manifest.Version > FormatVersion {
+		return nil, fmt.Errorf("unsupported archive version: %d", manifest.Version)
+	}
+
+	for i := range manifest.Documents {
+		f, ok := files[manifest.Documents[i].File]
+		if !ok {
+			return nil, fmt.Errorf("invalid archive: missing %s", manifest.Documents[i].File)
+		}
+		content, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Documents[i].Content = string(content)
+	}
+
+	return &manifest, nil
+}
+
+// readZipFile 读取zip内文件，限制最大大小
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxEntrySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	if len(data) > maxEntrySize {
+		return nil, fmt.Errorf("archive entry too large: %s", f.Name)
+	}
+	return data, nil
+}
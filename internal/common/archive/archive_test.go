@@ -0,0 +1,55 @@
+package archive
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBuildParseRoundTrip(t *testing.T) {
+	entries := []Entry{
+		{ID: 1, Title: "default", Content: "\n∞∞∞text-a\nhello"},
+		{ID: 7, Title: "notes", Content: "\n∞∞∞go\nfunc main() {}", IsLocked: true},
+	}
+
+	data, err := Build(entries, "1.0.0")
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	manifest, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if manifest.AppVersion != "1.0.0" || len(manifest.Documents) != 2 {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+	for i, doc := range manifest.Documents {
+		if doc.Title != entries[i].Title || doc.Content != entries[i].Content || doc.IsLocked != entries[i].IsLocked {
+			t.Errorf("document %d mismatch: got %+v", i, doc)
+		}
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	plaintext := []byte("secret archive data")
+
+	encrypted, err := Encrypt(plaintext, "pass")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !IsEncrypted(encrypted) {
+		t.Fatal("expected encrypted header")
+	}
+
+	decrypted, err := Decrypt(encrypted, "pass")
+	if err != nil || string(decrypted) != string(plaintext) {
+		t.Fatalf("Decrypt failed: %v, %q", err, decrypted)
+	}
+
+	if _, err := Decrypt(encrypted, "wrong"); !errors.Is(err, ErrDecryptFailed) {
+		t.Errorf("expected ErrDecryptFailed, got %v", err)
+	}
+	if _, err := Decrypt(encrypted, ""); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("expected ErrPassphraseRequired, got %v", err)
+	}
+}
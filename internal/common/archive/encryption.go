@@ -0,0 +1,105 @@
+package archive
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// 加密归档文件格式：magic | salt | nonce | AES-256-GCM密文
+const (
+	saltSize  = 16
+	nonceSize = 12
+	keySize   = 32
+)
+
+// encryptedMagic 加密归档文件头
+var encryptedMagic = []byte("VOIDRAFT-ENC1\n")
+
+var (
+	// ErrPassphraseRequired 归档已加密但未提供口令
+	ErrPassphraseRequired = errors.New("archive is encrypted, passphrase required")
+	// ErrDecryptFailed 口令错误或数据损坏
+	ErrDecryptFailed = errors.New("failed to decrypt archive: wrong passphrase or corrupted data")
+)
+
+// IsEncrypted 判断数据是否为加密归档
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedMagic)
+}
+
+// Encrypt 使用口令加密数据，密钥由argon2id派生
+func Encrypt(plaintext []byte, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase is empty")
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(encryptedMagic)+saltSize+nonceSize+len(plaintext)+gcm.Overhead())
+	out = append(out, encryptedMagic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	// 文件头作为附加数据参与认证，防止篡改
+	return gcm.Seal(out, nonce, plaintext, encryptedMagic), nil
+}
+
+// Decrypt 使用口令解密加密归档
+func Decrypt(data []byte, passphrase string) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, errors.New("data is not an encrypted archive")
+	}
+	if passphrase == "" {
+		return nil, ErrPassphraseRequired
+	}
+
+	body := data[len(encryptedMagic):]
+	if len(body) < saltSize+nonceSize {
+		return nil, ErrDecryptFailed
+	}
+	salt := body[:saltSize]
+	nonce := body[saltSize : saltSize+nonceSize]
+	ciphertext := body[saltSize+nonceSize:]
+
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, encryptedMagic)
+	if err != nil {
+		return nil, ErrDecryptFailed
+	}
+	return plaintext, nil
+}
+
+// newGCM 从口令和盐派生密钥并创建GCM实例
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key := argon2.IDKey([]byte(passphrase), salt, 3, 64*1024, 2, keySize)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcm: %w", err)
+	}
+	return gcm, nil
+}
@@ -0,0 +1,103 @@
+package blocks
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DelimiterPrefix 块分隔符前缀，与前端 codeblock 扩展保持一致
+const DelimiterPrefix = "\n∞∞∞"
+
+// DefaultLanguage 默认块语言
+const DefaultLanguage = "text"
+
+// delimiterRegex 匹配块分隔符 \n∞∞∞lang(-a)?\n
+var delimiterRegex = regexp.MustCompile(`\n∞∞∞([a-zA-Z0-9_-]+?)(-a)?\n`)
+
+// Block 文档中的单个块
+type Block struct {
+	Language string `json:"language"` // 块语言标记，如 text、md、go
+	Auto     bool   `json:"auto"`     // 是否自动检测语言
+	Content  string `json:"content"`  // 块内容（不含分隔符）
+	From     int    `json:"from"`     // 分隔符在文档中的起始字节偏移
+	Start    int    `json:"start"`    // 块内容的起始字节偏移
+	End      int    `json:"end"`      // 块内容的结束字节偏移
+}
+
+// Delimiter 生成块分隔符
+func Delimiter(language string, auto bool) string {
+	if language == "" {
+		language = DefaultLanguage
+	}
+	if auto {
+		return DelimiterPrefix + language + "-a\n"
+	}
+	return DelimiterPrefix + language + "\n"
+}
+
+// Parse 将文档内容解析为块列表
+// 文档开头没有分隔符时，开头部分视为一个 text 块
+func Parse(content string) []Block {
+	matches := delimiterRegex.FindAllStringSubmatchIndex(content, -1)
+
+	var blocks []Block
+	if len(matches) == 0 || matches[0][0] > 0 {
+		end := len(content)
+		if len(matches) > 0 {
+			end = matches[0][0]
+		}
+		if end > 0 || len(matches) == 0 {
+			blocks = append(blocks, Block{
+				Language: DefaultLanguage,
+				Content:  content[:end],
+				From:     0,
+				Start:    0,
+				End:      end,
+			})
+		}
+	}
+
+	for i, m := range matches {
+		end := len(content)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		blocks = append(blocks, Block{
+			Language: content[m[2]:m[3]],
+			Auto:     m[4] >= 0,
+			Content:  content[m[1]:end],
+			From:     m[0],
+			Start:    m[1],
+			End:      end,
+		})
+	}
+
+	return blocks
+}
+
+// Serialize 将块列表序列化为文档内容
+func Serialize(blocks []Block) string {
+	var builder strings.Builder
+	for _, block := range blocks {
+		builder.WriteString(Delimiter(block.Language, block.Auto))
+		builder.WriteString(block.Content)
+	}
+	return builder.String()
+}
+
+// Text 将块内容拼接为纯文本，块之间以空行分隔，忽略空白块
+func Text(blocks []Block) string {
+	var parts []string
+	for _, block := range blocks {
+		content := strings.TrimRight(block.Content, "\n")
+		if strings.TrimSpace(content) != "" {
+			parts = append(parts, content)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// IsProse 判断块是否为自然语言文本（text/markdown）
+func IsProse(language string) bool {
+	return language == "text" || language == "md"
+}
@@ -0,0 +1,42 @@
+package blocks
+
+import "testing"
+
+func TestParseAndSerialize(t *testing.T) {
+	content := "\n∞∞∞text-a\nhello\n∞∞∞go\nfunc main() {}\n∞∞∞md\n# Title"
+
+	blocks := Parse(content)
+	if len(blocks) != 3 {
+		t.Fatalf("expected 3 blocks, got %d", len(blocks))
+	}
+
+	expected := []struct {
+		lang    string
+		auto    bool
+		content string
+	}{
+		{"text", true, "hello"},
+		{"go", false, "func main() {}"},
+		{"md", false, "# Title"},
+	}
+	for i, e := range expected {
+		b := blocks[i]
+		if b.Language != e.lang || b.Auto != e.auto || b.Content != e.content {
+			t.Errorf("block %d = %+v, want %+v", i, b, e)
+		}
+		if content[b.Start:b.End] != b.Content {
+			t.Errorf("block %d offsets do not match content", i)
+		}
+	}
+
+	if got := Serialize(blocks); got != content {
+		t.Errorf("Serialize() = %q, want %q", got, content)
+	}
+}
+
+func TestParseWithoutDelimiter(t *testing.T) {
+	blocks := Parse("plain text")
+	if len(blocks) != 1 || blocks[0].Language != DefaultLanguage || blocks[0].Content != "plain text" {
+		t.Errorf("unexpected blocks: %+v", blocks)
+	}
+}
@@ -0,0 +1,127 @@
+package blocks
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// StreamLimits 流式拆分的大小限制（字节）
+type StreamLimits struct {
+	BlockBytes    int // 单个块的上限，在行边界拆分，单行超长时在字符边界拆分
+	DocumentBytes int // 单个文档的上限，超出后开始新文档
+}
+
+// SplitStream 逐行读取文本并拆分为若干文档内容，每个文档由不超过上限的块组成。
+// 内存占用不超过一个文档的大小；以分隔符开头的行会加零宽空格，避免被解析为块分隔符。
+// emit 依次接收每个文档的完整内容，read 在每次读取后接收累计读取的字节数
+func SplitStream(r io.Reader, language string, limits StreamLimits, emit func(content string) error, read func(total int64)) error {
+	if limits.BlockBytes <= 0 || limits.DocumentBytes < limits.BlockBytes {
+		return errors.New("invalid stream limits")
+	}
+
+	reader := bufio.NewReaderSize(r, 64*1024)
+	delimiter := Delimiter(language, false)
+	var doc, block strings.Builder
+	var total int64
+
+	flushBlock := func() {
+		if block.Len() == 0 {
+			return
+		}
+		doc.WriteString(delimiter)
+		doc.WriteString(strings.TrimSuffix(block.String(), "\n"))
+		block.Reset()
+	}
+	flushDocument := func() error {
+		flushBlock()
+		if doc.Len() == 0 {
+			return nil
+		}
+		content := doc.String()
+		doc.Reset()
+		return emit(content)
+	}
+
+	lineStart := true
+	var carry []byte
+	for {
+		slice, err := reader.ReadSlice('\n')
+		total += int64(len(slice))
+		chunk := append(carry, slice...)
+		carry = nil
+		if errors.Is(err, bufio.ErrBufferFull) {
+			// 缓冲区满时行尾可能截断了多字节字符，留到下一次拼接
+			if cut := incompleteRuneStart(chunk); cut < len(chunk) {
+				carry = append([]byte(nil), chunk[cut:]...)
+				chunk = chunk[:cut]
+			}
+		}
+		if len(chunk) > 0 {
+			text := strings.ToValidUTF8(string(chunk), "�")
+			if lineStart && strings.HasPrefix(text, "∞∞∞") {
+				text = "​" + text
+			}
+			lineStart = strings.HasSuffix(text, "\n")
+
+			for text != "" {
+				if block.Len()+len(text) > limits.BlockBytes && block.Len() > 0 {
+					if doc.Len()+len(delimiter)+block.Len() > limits.DocumentBytes {
+						if err := flushDocument(); err != nil {
+							return err
+						}
+					}
+					flushBlock()
+					if doc.Len()+len(delimiter)+limits.BlockBytes > limits.DocumentBytes {
+						if err := flushDocument(); err != nil {
+							return err
+						}
+					}
+				}
+				if len(text) <= limits.BlockBytes {
+					block.WriteString(text)
+					break
+				}
+				// 单行超过块上限，在字符边界截断
+				cut := limits.BlockBytes
+				for cut > 0 && !utf8.RuneStart(text[cut]) {
+					cut--
+				}
+				if cut == 0 {
+					cut = limits.BlockBytes
+				}
+				block.WriteString(text[:cut])
+				text = text[cut:]
+			}
+			if read != nil {
+				read(total)
+			}
+		}
+
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return flushDocument()
+}
+
+// incompleteRuneStart 返回末尾不完整 UTF-8 字符的起始位置，没有时返回 len(b)
+func incompleteRuneStart(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(b)
+}
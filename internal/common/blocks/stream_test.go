@@ -0,0 +1,90 @@
+package blocks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitStream(t *testing.T) {
+	var input strings.Builder
+	for i := 0; i < 100; i++ {
+		input.WriteString("line of log output\n")
+	}
+	input.WriteString("∞∞∞md\n")
+
+	var docs []string
+	var read int64
+	limits := StreamLimits{BlockBytes: 200, DocumentBytes: 1000}
+	err := SplitStream(strings.NewReader(input.String()), "log", limits, func(content string) error {
+		docs = append(docs, content)
+		return nil
+	}, func(total int64) { read = total })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read != int64(input.Len()) {
+		t.Errorf("read = %d, want %d", read, input.Len())
+	}
+	if len(docs) < 2 {
+		t.Fatalf("expected multiple documents, got %d", len(docs))
+	}
+
+	var rebuilt strings.Builder
+	for _, doc := range docs {
+		if len(doc) > limits.DocumentBytes {
+			t.Errorf("document size %d exceeds limit", len(doc))
+		}
+		for _, block := range Parse(doc) {
+			if block.Language != "log" {
+				t.Errorf("block language = %q", block.Language)
+			}
+			if len(block.Content) > limits.BlockBytes {
+				t.Errorf("block size %d exceeds limit", len(block.Content))
+			}
+			rebuilt.WriteString(block.Content)
+			rebuilt.WriteString("\n")
+		}
+	}
+	want := strings.Replace(input.String(), "∞∞∞md", "​∞∞∞md", 1)
+	if rebuilt.String() != want {
+		t.Errorf("rebuilt content does not match input")
+	}
+}
+
+func TestSplitStreamLongLine(t *testing.T) {
+	line := strings.Repeat("中", 100) // 300 字节，无换行
+	var blocks []Block
+	err := SplitStream(strings.NewReader(line), "text", StreamLimits{BlockBytes: 64, DocumentBytes: 4096}, func(content string) error {
+		blocks = append(blocks, Parse(content)...)
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rebuilt strings.Builder
+	for _, block := range blocks {
+		if len(block.Content) > 64 {
+			t.Errorf("block size %d exceeds limit", len(block.Content))
+		}
+		rebuilt.WriteString(block.Content)
+	}
+	if rebuilt.String() != line {
+		t.Errorf("long line was not preserved")
+	}
+}
+
+func TestSplitStreamMultibyteAcrossBuffer(t *testing.T) {
+	line := "a" + strings.Repeat("中", 50_000) // 跨越读缓冲区边界
+	var out string
+	err := SplitStream(strings.NewReader(line), "text", StreamLimits{BlockBytes: 1 << 20, DocumentBytes: 4 << 20}, func(content string) error {
+		out = Parse(content)[0].Content
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != line {
+		t.Error("multibyte characters were corrupted at buffer boundary")
+	}
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Cache 读穿透缓存：未命中时调用加载函数并缓存结果，数据变更时由调用方失效。
+// 同一键的并发加载只执行一次；加载期间发生失效时结果不写入缓存，避免缓存旧数据。
+type Cache[K comparable, V any] struct {
+	ttl time.Duration
+
+	mu         sync.Mutex
+	entries    map[K]*entry[V]
+	inflight   map[K]*call[V]
+	generation uint64
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// Stats 缓存命中统计
+type Stats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+}
+
+// New 创建缓存，ttl 为0表示只依赖显式失效
+func New[K comparable, V any](ttl time.Duration) *Cache[K, V] {
+	return &Cache[K, V]{
+		ttl:      ttl,
+		entries:  make(map[K]*entry[V]),
+		inflight: make(map[K]*call[V]),
+	}
+}
+
+// Get 读取缓存，未命中时调用 load 加载
+func (c *Cache[K, V]) Get(key K, load func() (V, error)) (V, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		if c.ttl <= 0 || time.Now().Before(e.expires) {
+			c.mu.Unlock()
+			c.hits.Add(1)
+			return e.value, nil
+		}
+		delete(c.entries, key)
+	}
+	if cl, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-cl.done
+		c.hits.Add(1)
+		return cl.value, cl.err
+	}
+
+	cl := &call[V]{done: make(chan struct{})}
+	c.inflight[key] = cl
+	generation := c.generation
+	c.mu.Unlock()
+	c.misses.Add(1)
+
+	cl.value, cl.err = load()
+
+	c.mu.Lock()
+	if c.inflight[key] == cl {
+		delete(c.inflight, key)
+	}
+	if cl.err == nil && generation == c.generation {
+		c.entries[key] = &entry[V]{value: cl.value, expires: time.Now().Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	close(cl.done)
+
+	return cl.value, cl.err
+}
+
+// Invalidate 清空全部缓存
+func (c *Cache[K, V]) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	clear(c.entries)
+	// 进行中的加载可能读到旧数据，让后续请求重新加载
+	clear(c.inflight)
+}
+
+// Stats 返回命中统计
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	entries := len(c.entries)
+	c.mu.Unlock()
+	return Stats{Hits: c.hits.Load(), Misses: c.misses.Load(), Entries: entries}
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetCachesUntilInvalidated(t *testing.T) {
+	c := New[string, int](0)
+	loads := 0
+	load := func() (int, error) {
+		loads++
+		return loads, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		if v, _ := c.Get("a", load); v != 1 {
+			t.Fatalf("Get() = %d, want 1", v)
+		}
+	}
+	c.Invalidate()
+	if v, _ := c.Get("a", load); v != 2 {
+		t.Errorf("Get() after Invalidate = %d, want 2", v)
+	}
+	if stats := c.Stats(); stats.Hits != 2 || stats.Misses != 2 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestGetDoesNotCacheErrors(t *testing.T) {
+	c := New[string, int](0)
+	if _, err := c.Get("a", func() (int, error) { return 0, errors.New("boom") }); err == nil {
+		t.Fatal("expected error")
+	}
+	if v, err := c.Get("a", func() (int, error) { return 7, nil }); err != nil || v != 7 {
+		t.Errorf("Get() = %d, %v", v, err)
+	}
+}
+
+func TestGetExpires(t *testing.T) {
+	c := New[string, int](10 * time.Millisecond)
+	c.Get("a", func() (int, error) { return 1, nil })
+	time.Sleep(20 * time.Millisecond)
+	if v, _ := c.Get("a", func() (int, error) { return 2, nil }); v != 2 {
+		t.Errorf("Get() after ttl = %d, want 2", v)
+	}
+}
+
+func TestInvalidateDuringLoadDiscardsResult(t *testing.T) {
+	c := New[string, int](0)
+	c.Get("a", func() (int, error) {
+		c.Invalidate()
+		return 1, nil
+	})
+	if v, _ := c.Get("a", func() (int, error) { return 2, nil }); v != 2 {
+		t.Errorf("Get() = %d, want fresh value 2", v)
+	}
+}
+
+func TestConcurrentGetLoadsOnce(t *testing.T) {
+	c := New[string, int](0)
+	var loads atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Get("a", func() (int, error) {
+				loads.Add(1)
+				<-release
+				return 1, nil
+			})
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Errorf("loads = %d, want 1", n)
+	}
+}
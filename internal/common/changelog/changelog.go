@@ -0,0 +1,123 @@
+// Package changelog 比较版本号并挑选两个版本之间的更新说明
+package changelog
+
+import (
+	"cmp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry 某个版本的更新说明
+type Entry struct {
+	Version     string    `json:"version"`     // 版本号，不含 v 前缀
+	Title       string    `json:"title"`       // 发布标题
+	Notes       string    `json:"notes"`       // 更新说明（Markdown）
+	URL         string    `json:"url"`         // 发布页面
+	PublishedAt time.Time `json:"publishedAt"` // 发布时间
+}
+
+// Normalize 去掉版本号的 v 前缀和构建元数据
+func Normalize(v string) string {
+	v = strings.TrimSpace(v)
+	v = strings.TrimPrefix(strings.TrimPrefix(v, "v"), "V")
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		v = v[:i]
+	}
+	return v
+}
+
+// Compare 按语义化版本比较 a 与 b，返回 -1、0 或 1
+// 缺少的数字段按 0 处理，预发布版本低于对应的正式版本
+func Compare(a, b string) int {
+	aCore, aPre, _ := strings.Cut(Normalize(a), "-")
+	bCore, bPre, _ := strings.Cut(Normalize(b), "-")
+
+	as, bs := strings.Split(aCore, "."), strings.Split(bCore, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		if c := compareNumber(part(as, i), part(bs, i)); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return comparePrerelease(aPre, bPre)
+}
+
+// part 取第 i 段，不存在时视为 0
+func part(parts []string, i int) string {
+	if i < len(parts) {
+		return parts[i]
+	}
+	return "0"
+}
+
+// compareNumber 比较数字段，非数字段按字符串比较且低于数字段
+func compareNumber(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return cmp.Compare(an, bn)
+	case aErr == nil:
+		return 1
+	case bErr == nil:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+// comparePrerelease 逐段比较预发布标识，数字标识低于字母标识
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < min(len(as), len(bs)); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = cmp.Compare(an, bn)
+		case aErr == nil:
+			c = -1
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(as[i], bs[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
+// Between 挑选 (from, to] 区间内的版本，按版本从新到旧排序
+// from 为空时只返回 to 对应的版本
+func Between(entries []Entry, from, to string) []Entry {
+	var out []Entry
+	for _, e := range entries {
+		if Compare(e.Version, to) > 0 {
+			continue
+		}
+		if from == "" {
+			if Compare(e.Version, to) != 0 {
+				continue
+			}
+		} else if Compare(e.Version, from) <= 0 {
+			continue
+		}
+		out = append(out, e)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return Compare(out[i].Version, out[j].Version) > 0
+	})
+	return out
+}
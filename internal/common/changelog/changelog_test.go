@@ -0,0 +1,55 @@
+package changelog
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.5.4", "1.5.4", 0},
+		{"v1.5.4", "1.5.4", 0},
+		{"1.5", "1.5.0", 0},
+		{"1.5.10", "1.5.9", 1},
+		{"1.4.9", "1.5.0", -1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.6.0-beta.1", "1.6.0", -1},
+		{"1.6.0-beta.2", "1.6.0-beta.10", -1},
+		{"1.6.0-alpha", "1.6.0-beta", -1},
+		{"1.6.0-1", "1.6.0-alpha", -1},
+		{"1.6.0-beta", "1.6.0-beta.1", -1},
+		{"1.6.0+build.5", "1.6.0", 0},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Compare(tt.b, tt.a); got != -tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestBetween(t *testing.T) {
+	entries := []Entry{
+		{Version: "1.5.2"},
+		{Version: "1.5.4"},
+		{Version: "1.5.3"},
+		{Version: "1.6.0"},
+		{Version: "1.5.1"},
+	}
+
+	got := Between(entries, "1.5.2", "1.5.4")
+	if len(got) != 2 || got[0].Version != "1.5.4" || got[1].Version != "1.5.3" {
+		t.Fatalf("Between = %+v", got)
+	}
+
+	got = Between(entries, "", "v1.5.4")
+	if len(got) != 1 || got[0].Version != "1.5.4" {
+		t.Fatalf("Between without from = %+v", got)
+	}
+
+	if got = Between(entries, "1.6.0", "1.6.0"); len(got) != 0 {
+		t.Fatalf("Between same version = %+v", got)
+	}
+}
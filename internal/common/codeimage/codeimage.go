@@ -0,0 +1,248 @@
+// Package codeimage 将代码文本渲染为带窗口外框的图片，用于分享代码截图
+package codeimage
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/text/width"
+)
+
+const (
+	// MaxLines 渲染的最大行数，超出部分截断
+	MaxLines = 500
+	// MaxColumns 每行渲染的最大列数，超出部分截断
+	MaxColumns = 160
+
+	defaultFontSize = 14
+	defaultPadding  = 48
+	defaultTabWidth = 4
+	defaultScale    = 2
+)
+
+// 窗口标题栏的三个圆点
+var windowDots = []color.RGBA{
+	{R: 0xff, G: 0x5f, B: 0x56, A: 0xff},
+	{R: 0xff, G: 0xbd, B: 0x2e, A: 0xff},
+	{R: 0x27, G: 0xc9, B: 0x3f, A: 0xff},
+}
+
+// Style 图片配色
+type Style struct {
+	Background color.Color // 窗口外的背景
+	Window     color.Color // 窗口背景，一般为编辑器背景色
+	Foreground color.Color // 文本颜色
+	LineNumber color.Color // 行号和标题颜色
+}
+
+// Options 渲染选项
+type Options struct {
+	Title       string  // 标题栏中的标题，为空时不显示
+	LineNumbers bool    // 是否显示行号
+	FirstLine   int     // 第一行的行号，默认为 1
+	FontSize    float64 // 字号（逻辑像素）
+	Padding     int     // 窗口四周的留白（逻辑像素）
+	TabWidth    int     // 制表符宽度
+	Scale       int     // 输出的像素倍率，默认 2 倍以便在高分屏上清晰
+	Style       Style
+}
+
+// withDefaults 补全未设置的选项
+func (o Options) withDefaults() Options {
+	if o.FontSize <= 0 {
+		o.FontSize = defaultFontSize
+	}
+	if o.Padding < 0 {
+		o.Padding = 0
+	} else if o.Padding == 0 {
+		o.Padding = defaultPadding
+	}
+	if o.TabWidth <= 0 {
+		o.TabWidth = defaultTabWidth
+	}
+	if o.Scale <= 0 {
+		o.Scale = defaultScale
+	}
+	if o.FirstLine <= 0 {
+		o.FirstLine = 1
+	}
+	if o.Style.Window == nil {
+		o.Style.Window = color.RGBA{R: 0x25, G: 0x2b, B: 0x37, A: 0xff}
+	}
+	if o.Style.Foreground == nil {
+		o.Style.Foreground = color.RGBA{R: 0xd4, G: 0xd4, B: 0xd4, A: 0xff}
+	}
+	if o.Style.LineNumber == nil {
+		o.Style.LineNumber = color.RGBA{R: 0x6b, G: 0x72, B: 0x80, A: 0xff}
+	}
+	if o.Style.Background == nil {
+		o.Style.Background = o.Style.Window
+	}
+	return o
+}
+
+// Render 将文本渲染为图片
+func Render(text string, opts Options) (*image.RGBA, error) {
+	opts = opts.withDefaults()
+	scale := float64(opts.Scale)
+	faces, err := loadFaces(opts.FontSize * scale)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := layoutLines(text, opts.TabWidth)
+	columns := 1
+	for _, line := range lines {
+		columns = max(columns, line.columns)
+	}
+
+	px := func(v float64) int { return int(math.Round(v * scale)) }
+	cell := faces.cellWidth()
+	lineHeight := px(opts.FontSize * 1.5)
+	inset := px(opts.FontSize * 1.5)
+	titleBar := px(opts.FontSize * 2.5)
+	radius := px(opts.FontSize * 0.6)
+	padding := px(float64(opts.Padding))
+
+	gutter := 0
+	digits := len(strconv.Itoa(opts.FirstLine + len(lines) - 1))
+	if opts.LineNumbers {
+		gutter = (digits + 2) * cell
+	}
+
+	windowWidth := inset*2 + gutter + columns*cell
+	windowHeight := titleBar + len(lines)*lineHeight + inset
+	// 标题较长时加宽窗口
+	if opts.Title != "" {
+		windowWidth = max(windowWidth, faces.measure(opts.Title)+titleBar*3)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, windowWidth+padding*2, windowHeight+padding*2))
+	draw.Draw(img, img.Bounds(), image.NewUniform(opts.Style.Background), image.Point{}, draw.Src)
+
+	window := image.Rect(padding, padding, padding+windowWidth, padding+windowHeight)
+	draw.DrawMask(img, window, image.NewUniform(opts.Style.Window), image.Point{}, roundedRect{window, radius}, window.Min, draw.Over)
+
+	// 标题栏
+	dotRadius := px(opts.FontSize * 0.4)
+	for i, dot := range windowDots {
+		center := image.Pt(window.Min.X+inset+dotRadius+i*dotRadius*3, window.Min.Y+titleBar/2)
+		area := image.Rect(center.X-dotRadius, center.Y-dotRadius, center.X+dotRadius, center.Y+dotRadius)
+		draw.DrawMask(img, area, image.NewUniform(dot), image.Point{}, roundedRect{area, dotRadius}, area.Min, draw.Over)
+	}
+	baseline := func(top int) int {
+		return top + (lineHeight+faces.ascent()-faces.descent())/2
+	}
+	if opts.Title != "" {
+		x := window.Min.X + (windowWidth-faces.measure(opts.Title))/2
+		faces.draw(img, opts.Style.LineNumber, x, window.Min.Y+(titleBar-lineHeight)/2, baseline, opts.Title, 0)
+	}
+
+	// 正文
+	top := window.Min.Y + titleBar
+	for i, line := range lines {
+		y := top + i*lineHeight
+		if opts.LineNumbers {
+			number := strconv.Itoa(opts.FirstLine + i)
+			x := window.Min.X + inset + (digits-len(number))*cell
+			faces.draw(img, opts.Style.LineNumber, x, y, baseline, number, cell)
+		}
+		faces.draw(img, opts.Style.Foreground, window.Min.X+inset+gutter, y, baseline, line.text, cell)
+	}
+	return img, nil
+}
+
+// line 展开制表符并截断后的一行
+type line struct {
+	text    string
+	columns int
+}
+
+// layoutLines 拆分行、展开制表符并按最大行数和列数截断
+func layoutLines(text string, tabWidth int) []line {
+	text = strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	rawLines := strings.Split(text, "\n")
+	truncated := len(rawLines) > MaxLines
+	if truncated {
+		rawLines = rawLines[:MaxLines]
+	}
+
+	lines := make([]line, 0, len(rawLines)+1)
+	for _, raw := range rawLines {
+		var b strings.Builder
+		columns := 0
+		for _, r := range raw {
+			if r == '\t' {
+				spaces := tabWidth - columns%tabWidth
+				b.WriteString(strings.Repeat(" ", spaces))
+				columns += spaces
+				continue
+			}
+			if r < ' ' || r == 0x7f {
+				continue
+			}
+			w := runeColumns(r)
+			if columns+w > MaxColumns-1 {
+				b.WriteRune('…')
+				columns++
+				break
+			}
+			b.WriteRune(r)
+			columns += w
+		}
+		lines = append(lines, line{text: b.String(), columns: columns})
+	}
+	if truncated {
+		lines = append(lines, line{text: "…", columns: 1})
+	}
+	return lines
+}
+
+// runeColumns 字符占用的列数，全角字符占两列
+func runeColumns(r rune) int {
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// roundedRect 圆角矩形遮罩
+type roundedRect struct {
+	rect   image.Rectangle
+	radius int
+}
+
+func (r roundedRect) ColorModel() color.Model { return color.AlphaModel }
+
+func (r roundedRect) Bounds() image.Rectangle { return r.rect }
+
+func (r roundedRect) At(x, y int) color.Color {
+	if !(image.Point{X: x, Y: y}.In(r.rect)) {
+		return color.Transparent
+	}
+	// 只有四个角需要计算到圆心的距离
+	cx := min(max(x, r.rect.Min.X+r.radius), r.rect.Max.X-r.radius-1)
+	cy := min(max(y, r.rect.Min.Y+r.radius), r.rect.Max.Y-r.radius-1)
+	dx, dy := float64(x-cx), float64(y-cy)
+	d := math.Sqrt(dx*dx+dy*dy) - float64(r.radius)
+	switch {
+	case d <= -0.5:
+		return color.Opaque
+	case d >= 0.5:
+		return color.Transparent
+	}
+	return color.Alpha{A: uint8((0.5 - d) * 0xff)}
+}
+
+// drawRune 以指定字体绘制单个字符
+func drawRune(dst draw.Image, face font.Face, c color.Color, dot fixed.Point26_6, r rune) {
+	d := font.Drawer{Dst: dst, Src: image.NewUniform(c), Face: face, Dot: dot}
+	d.DrawString(string(r))
+}
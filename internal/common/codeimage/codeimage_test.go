@@ -0,0 +1,59 @@
+package codeimage
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestLayoutLines(t *testing.T) {
+	lines := layoutLines("a\tb\r\n中文\x01", 4)
+	if len(lines) != 2 {
+		t.Fatalf("len(lines) = %d", len(lines))
+	}
+	if lines[0].text != "a   b" || lines[0].columns != 5 {
+		t.Errorf("lines[0] = %+v", lines[0])
+	}
+	if lines[1].text != "中文" || lines[1].columns != 4 {
+		t.Errorf("lines[1] = %+v", lines[1])
+	}
+
+	long := layoutLines(strings.Repeat("x", MaxColumns*2), 4)
+	if long[0].columns != MaxColumns || !strings.HasSuffix(long[0].text, "…") {
+		t.Errorf("long line columns = %d", long[0].columns)
+	}
+
+	many := layoutLines(strings.Repeat("x\n", MaxLines*2), 4)
+	if len(many) != MaxLines+1 || many[MaxLines].text != "…" {
+		t.Errorf("len(many) = %d", len(many))
+	}
+}
+
+func TestRender(t *testing.T) {
+	background := color.RGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xff}
+	window := color.RGBA{R: 0x20, G: 0x20, B: 0x20, A: 0xff}
+	opts := Options{Title: "main.go", LineNumbers: true, Scale: 1, Padding: 10, Style: Style{Background: background, Window: window}}
+
+	short, err := Render("x", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wide, err := Render(strings.Repeat("x", 80)+"\n2\n3", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wide.Bounds().Dx() <= short.Bounds().Dx() || wide.Bounds().Dy() <= short.Bounds().Dy() {
+		t.Errorf("bounds: short %v, wide %v", short.Bounds(), wide.Bounds())
+	}
+
+	if got := wide.RGBAAt(0, 0); got != background {
+		t.Errorf("corner = %v, want background", got)
+	}
+	// 圆角外侧仍为背景色，窗口中部为窗口色
+	if got := wide.RGBAAt(10, 10); got != background {
+		t.Errorf("rounded corner = %v, want background", got)
+	}
+	if got := wide.RGBAAt(wide.Bounds().Dx()-20, wide.Bounds().Dy()-20); got != window {
+		t.Errorf("window = %v, want window colour", got)
+	}
+}
@@ -0,0 +1,83 @@
+package codeimage
+
+import (
+	"fmt"
+	"image/color"
+	"image/draw"
+	"sync"
+	"voidraft/internal/common/fontface"
+
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+var (
+	fontsOnce sync.Once
+	monoFont  *opentype.Font
+	fontsErr  error
+)
+
+// loadFonts 解析内置的 Go Mono，只执行一次
+func loadFonts() error {
+	fontsOnce.Do(func() {
+		monoFont, fontsErr = opentype.Parse(gomono.TTF)
+		if fontsErr != nil {
+			fontsErr = fmt.Errorf("failed to parse mono font: %w", fontsErr)
+		}
+	})
+	return fontsErr
+}
+
+// faces 等宽主字体和中日韩后备字体
+type faces struct {
+	*fontface.Set
+}
+
+// loadFaces 创建指定像素大小的字体
+func loadFaces(size float64) (*faces, error) {
+	if err := loadFonts(); err != nil {
+		return nil, err
+	}
+	set, err := fontface.New(monoFont, size)
+	if err != nil {
+		return nil, err
+	}
+	return &faces{Set: set}, nil
+}
+
+// cellWidth 等宽字体单个字符的宽度
+func (f *faces) cellWidth() int {
+	advance, _ := f.Primary.GlyphAdvance('M')
+	return advance.Ceil()
+}
+
+func (f *faces) ascent() int  { return f.Primary.Metrics().Ascent.Ceil() }
+func (f *faces) descent() int { return f.Primary.Metrics().Descent.Ceil() }
+
+// measure 按字体实际宽度测量文本
+func (f *faces) measure(text string) int {
+	var total fixed.Int26_6
+	for _, r := range text {
+		total += f.Advance(r)
+	}
+	return total.Ceil()
+}
+
+// draw 在 (x, top) 开始的一行中绘制文本
+// cell 大于 0 时按等宽网格排列，全角字符占两格；否则按字体实际宽度排列
+func (f *faces) draw(dst draw.Image, c color.Color, x, top int, baseline func(top int) int, text string, cell int) {
+	dot := fixed.P(x, baseline(top))
+	for _, r := range text {
+		face := f.FaceFor(r)
+		advance, _ := face.GlyphAdvance(r)
+		if r != ' ' {
+			drawRune(dst, face, c, dot, r)
+		}
+		if cell > 0 {
+			dot.X += fixed.I(cell * runeColumns(r))
+		} else {
+			dot.X += advance
+		}
+	}
+}
@@ -14,3 +14,12 @@ const VOIDRAFT_WINDOW_WIDTH = 700
 
 // VOIDRAFT_WINDOW_HEIGHT 定义应用程序窗口的默认高度（像素）
 const VOIDRAFT_WINDOW_HEIGHT = 800
+
+// VOIDRAFT_MENUBAR_WINDOW_NAME 是固定到菜单栏的文档窗口的名称标识
+const VOIDRAFT_MENUBAR_WINDOW_NAME = "voidraft-menubar-window"
+
+// VOIDRAFT_MENUBAR_WINDOW_WIDTH 定义菜单栏文档窗口的宽度（像素）
+const VOIDRAFT_MENUBAR_WINDOW_WIDTH = 380
+
+// VOIDRAFT_MENUBAR_WINDOW_HEIGHT 定义菜单栏文档窗口的高度（像素）
+const VOIDRAFT_MENUBAR_WINDOW_HEIGHT = 460
@@ -0,0 +1,49 @@
+package constant
+
+// 后端发送给前端的事件名称
+const (
+	// EVENT_SECRETS_DETECTED 文档保存后检测到疑似敏感信息
+	EVENT_SECRETS_DETECTED = "secrets:detected"
+	// EVENT_APP_LOCKED 应用已锁定，前端应遮挡所有内容并显示解锁界面
+	EVENT_APP_LOCKED = "app:locked"
+	// EVENT_APP_UNLOCKED 应用已解锁
+	EVENT_APP_UNLOCKED = "app:unlocked"
+	// EVENT_EXTERNAL_EDIT_SYNCED 外部编辑器的修改已同步回文档，前端应重新加载
+	EVENT_EXTERNAL_EDIT_SYNCED = "external-edit:synced"
+	// EVENT_EXTERNAL_EDIT_CONFLICT 外部编辑器与应用内同时修改了文档，需要用户选择保留哪一份
+	EVENT_EXTERNAL_EDIT_CONFLICT = "external-edit:conflict"
+	// EVENT_DATA_READ_ONLY 数据目录被其他实例占用，当前实例只读
+	EVENT_DATA_READ_ONLY = "data:read-only"
+	// EVENT_JOB_UPDATED 后台任务状态或进度变化
+	EVENT_JOB_UPDATED = "job:updated"
+	// EVENT_DOCUMENT_PATCHED 后端对文档应用了编辑补丁，前端应以编辑事务应用到打开的编辑器
+	EVENT_DOCUMENT_PATCHED = "document:patched"
+	// EVENT_DOCUMENT_TITLE_GENERATED 未手动设置标题的文档按内容重新生成了标题
+	EVENT_DOCUMENT_TITLE_GENERATED = "document:title-generated"
+	// EVENT_ACCESSIBILITY_CHANGED 系统或配置中的辅助功能偏好发生变化
+	EVENT_ACCESSIBILITY_CHANGED = "accessibility:changed"
+	// EVENT_QUICK_TRANSLATE_RESULT 托盘或全局热键触发的剪贴板翻译完成
+	EVENT_QUICK_TRANSLATE_RESULT = "translation:quick-result"
+	// EVENT_DOCUMENT_SAVE_REQUESTED 关闭文档窗口前用户选择保存，前端应立即保存该文档
+	EVENT_DOCUMENT_SAVE_REQUESTED = "document:save-requested"
+	// EVENT_SESSION_RECOVERY_AVAILABLE 上次异常退出，可恢复窗口和未保存的内容
+	EVENT_SESSION_RECOVERY_AVAILABLE = "session:recovery-available"
+	// EVENT_WHATS_NEW 升级后有尚未查看的更新说明
+	EVENT_WHATS_NEW = "updates:whats-new"
+	// EVENT_DATA_MIGRATION_PROGRESS 升级后执行数据迁移的进度
+	EVENT_DATA_MIGRATION_PROGRESS = "data-migration:progress"
+	// EVENT_DOCUMENT_FOLLOW_CHANGED 文档在主窗口和文档窗口中重复打开，或跟随模式发生变化
+	EVENT_DOCUMENT_FOLLOW_CHANGED = "document:follow-changed"
+	// EVENT_DOCUMENT_FOLLOW_CHANGES 可编辑视图的编辑，只读视图应以远程事务应用
+	EVENT_DOCUMENT_FOLLOW_CHANGES = "document:follow-changes"
+	// EVENT_MAIN_WINDOW_LAYOUT_CHANGED 主窗口分屏、编辑区文档或未保存状态发生变化
+	EVENT_MAIN_WINDOW_LAYOUT_CHANGED = "window:main-layout-changed"
+	// EVENT_DOCUMENT_WINDOW_TABS_CHANGED 文档窗口中的标签增减或切换，前端按标签列表加载或关闭文档
+	EVENT_DOCUMENT_WINDOW_TABS_CHANGED = "window:tabs-changed"
+	// EVENT_WINDOW_SNAP_CHANGED 文档窗口吸附到其他窗口或解除吸附，数据为 models.WindowSnapState
+	EVENT_WINDOW_SNAP_CHANGED = "window:snap-changed"
+	// EVENT_TAGS_CHANGED 文档标签增减、标签重命名或合并，前端应刷新标签列表
+	EVENT_TAGS_CHANGED = "document:tags-changed"
+	// EVENT_DOCUMENT_BULK_PROGRESS 批量删除、移动、打标签或导出文档的进度
+	EVENT_DOCUMENT_BULK_PROGRESS = "document:bulk-progress"
+)
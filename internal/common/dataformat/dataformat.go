@@ -0,0 +1,249 @@
+// Package dataformat 在 JSON、YAML、TOML 之间转换和校验配置片段，错误中带有行列位置
+package dataformat
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Format 数据格式
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+// Error 带位置的解析或转换错误，行列从 1 开始，0 表示位置未知
+type Error struct {
+	Message string `json:"message"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+	case e.Line > 0:
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return e.Message
+}
+
+// ValidationResult 校验结果
+type ValidationResult struct {
+	Valid bool   `json:"valid"`
+	Error *Error `json:"error,omitempty"`
+}
+
+// yamlLinePattern yaml.v3 错误信息中的行号
+var yamlLinePattern = regexp.MustCompile(`line (\d+)(?:, column (\d+))?: `)
+
+// Validate 校验文本是否为合法的指定格式
+func Validate(text string, format Format) ValidationResult {
+	if _, err := decode(text, format); err != nil {
+		var formatErr *Error
+		if !errors.As(err, &formatErr) {
+			formatErr = &Error{Message: err.Error()}
+		}
+		return ValidationResult{Error: formatErr}
+	}
+	return ValidationResult{Valid: true}
+}
+
+// Convert 将文本从一种格式转换为另一种格式
+// JSON 和 TOML 的对象键按字母顺序输出，YAML 只转换第一个文档
+func Convert(text string, from, to Format) (string, error) {
+	value, err := decode(text, from)
+	if err != nil {
+		return "", err
+	}
+	return encode(value, to)
+}
+
+// decode 解析文本为通用数据结构
+func decode(text string, format Format) (any, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, &Error{Message: "input is empty"}
+	}
+	var value any
+	switch format {
+	case FormatJSON:
+		decoder := json.NewDecoder(strings.NewReader(text))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return nil, jsonError(text, err)
+		}
+		if _, err := decoder.Token(); err != io.EOF {
+			rest := text[decoder.InputOffset():]
+			offset := decoder.InputOffset() + int64(len(rest)-len(strings.TrimLeft(rest, " \t\r\n")))
+			return nil, offsetError(text, offset, "unexpected data after top-level value")
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal([]byte(text), &value); err != nil {
+			return nil, yamlError(err)
+		}
+	case FormatTOML:
+		if err := toml.Unmarshal([]byte(text), &value); err != nil {
+			return nil, tomlError(err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+	return normalize(value), nil
+}
+
+// encode 将通用数据结构序列化为指定格式
+func encode(value any, format Format) (string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(value); err != nil {
+			return "", &Error{Message: err.Error()}
+		}
+	case FormatYAML:
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(value); err != nil {
+			return "", &Error{Message: err.Error()}
+		}
+		encoder.Close()
+	case FormatTOML:
+		if _, ok := value.(map[string]any); !ok {
+			return "", &Error{Message: "TOML requires a table at the top level"}
+		}
+		if err := checkTOML(value, ""); err != nil {
+			return "", err
+		}
+		if err := toml.NewEncoder(&buf).Encode(value); err != nil {
+			return "", &Error{Message: err.Error()}
+		}
+	default:
+		return "", fmt.Errorf("unsupported format: %s", format)
+	}
+	return buf.String(), nil
+}
+
+// normalize 统一解析结果：非字符串键转为字符串，JSON 数字转为整数或浮点数
+func normalize(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = normalize(item)
+		}
+		return v
+	case map[any]any:
+		converted := make(map[string]any, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = normalize(item)
+		}
+		return converted
+	case []any:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+		return v
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	}
+	return value
+}
+
+// checkTOML TOML 没有空值，遇到 null 时报告所在路径
+func checkTOML(value any, path string) error {
+	switch v := value.(type) {
+	case nil:
+		return &Error{Message: fmt.Sprintf("TOML cannot represent null at %s", path)}
+	case map[string]any:
+		for key, item := range v {
+			if err := checkTOML(item, joinPath(path, key)); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, item := range v {
+			if err := checkTOML(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonError 将 encoding/json 的偏移量转换为行列
+func jsonError(text string, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// Offset 指向出错字符之后
+		return offsetError(text, syntaxErr.Offset-1, syntaxErr.Error())
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return offsetError(text, int64(len(text)), "unexpected end of input")
+	}
+	return &Error{Message: err.Error()}
+}
+
+// offsetError 按字节偏移量计算行列，列按字符计数
+func offsetError(text string, offset int64, message string) error {
+	offset = min(max(offset, 0), int64(len(text)))
+	before := text[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := len([]rune(before[strings.LastIndex(before, "\n")+1:])) + 1
+	return &Error{Message: message, Line: line, Column: column}
+}
+
+// yamlError 从 yaml.v3 的错误信息中提取行列
+func yamlError(err error) error {
+	message := err.Error()
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		message = typeErr.Errors[0]
+	}
+	message = strings.TrimPrefix(message, "yaml: ")
+	result := &Error{Message: message}
+	if match := yamlLinePattern.FindStringSubmatchIndex(message); match != nil {
+		result.Line, _ = strconv.Atoi(message[match[2]:match[3]])
+		if match[4] >= 0 {
+			result.Column, _ = strconv.Atoi(message[match[4]:match[5]])
+		}
+		result.Message = strings.TrimSpace(message[:match[0]] + message[match[1]:])
+	}
+	return result
+}
+
+// tomlError 从 go-toml 的解码错误中提取行列
+func tomlError(err error) error {
+	var decodeErr *toml.DecodeError
+	if errors.As(err, &decodeErr) {
+		line, column := decodeErr.Position()
+		return &Error{Message: decodeErr.Error(), Line: line, Column: column}
+	}
+	return &Error{Message: err.Error()}
+}
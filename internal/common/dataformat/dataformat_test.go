@@ -0,0 +1,73 @@
+package dataformat
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	json := `{"name": "app", "port": 8080, "ratio": 0.5, "tags": ["a", "b"], "db": {"host": "localhost"}}`
+
+	yamlText, err := Convert(json, FormatJSON, FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(yamlText, "port: 8080\n") || !strings.Contains(yamlText, "db:\n  host: localhost\n") {
+		t.Errorf("yaml = %q", yamlText)
+	}
+
+	tomlText, err := Convert(yamlText, FormatYAML, FormatTOML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(tomlText, "port = 8080\n") || !strings.Contains(tomlText, "[db]\nhost = 'localhost'\n") {
+		t.Errorf("toml = %q", tomlText)
+	}
+
+	back, err := Convert(tomlText, FormatTOML, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(back, `"port": 8080`) || !strings.Contains(back, `"ratio": 0.5`) {
+		t.Errorf("json = %q", back)
+	}
+
+	if _, err := Convert(`[1, 2]`, FormatJSON, FormatTOML); err == nil {
+		t.Error("expected error for top-level array in TOML")
+	}
+	if _, err := Convert(`{"a": {"b": null}}`, FormatJSON, FormatTOML); err == nil || !strings.Contains(err.Error(), "a.b") {
+		t.Errorf("null error = %v", err)
+	}
+}
+
+func TestValidatePositions(t *testing.T) {
+	tests := []struct {
+		format Format
+		text   string
+		line   int
+		column int
+	}{
+		{FormatJSON, "{\n  \"a\": 1,\n  \"b\" 2\n}", 3, 7},
+		{FormatJSON, "{\"a\": 1} x", 1, 10},
+		{FormatYAML, "a: 1\na: 2\n", 2, 0},
+		{FormatYAML, "a: 1\n  b: 2\n", 2, 0},
+		{FormatTOML, "a = 1\nb = = 2\n", 2, 5},
+	}
+	for _, tt := range tests {
+		result := Validate(tt.text, tt.format)
+		if result.Valid || result.Error == nil {
+			t.Errorf("%s %q: expected error", tt.format, tt.text)
+			continue
+		}
+		if result.Error.Line != tt.line || result.Error.Column != tt.column {
+			t.Errorf("%s %q: position = %d:%d (%s), want %d:%d", tt.format, tt.text, result.Error.Line, result.Error.Column, result.Error.Message, tt.line, tt.column)
+		}
+	}
+
+	if result := Validate("a: [1, 2]\n", FormatYAML); !result.Valid {
+		t.Errorf("valid yaml reported invalid: %+v", result.Error)
+	}
+	if result := Validate("  ", FormatJSON); result.Valid {
+		t.Error("empty input should be invalid")
+	}
+}
@@ -0,0 +1,158 @@
+package datalock
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName 锁文件名，位于数据目录中
+const FileName = "voidraft.lock"
+
+var (
+	// ErrLocked 数据目录正被其他实例使用
+	ErrLocked = errors.New("data directory is in use by another instance")
+	// ErrLost 锁已被其他实例接管
+	ErrLost = errors.New("data directory lock was taken over by another instance")
+)
+
+// Holder 锁持有者信息
+type Holder struct {
+	InstanceID string `json:"instanceId"`
+	Hostname   string `json:"hostname"`
+	PID        int    `json:"pid"`
+	StartedAt  string `json:"startedAt"`
+	Heartbeat  string `json:"heartbeat"`
+}
+
+// Lock 数据目录的咨询锁，通过心跳检测失效
+// 同步盘（Dropbox 等）上无法做到原子互斥，锁只用于发现并发实例，而不是强制保护
+type Lock struct {
+	path   string
+	holder Holder
+}
+
+// Acquire 获取数据目录锁
+// 已被其他存活实例持有时返回 ErrLocked 和持有者信息；心跳超过 staleAfter 或同机进程已退出的锁视为失效并接管
+func Acquire(dir string, staleAfter time.Duration) (*Lock, *Holder, error) {
+	path := filepath.Join(dir, FileName)
+
+	existing, err := read(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, err
+	}
+	if existing != nil && !isStale(existing, staleAfter) {
+		return nil, existing, ErrLocked
+	}
+
+	hostname, _ := os.Hostname()
+	now := time.Now().UTC().Format(time.RFC3339)
+	lock := &Lock{
+		path: path,
+		holder: Holder{
+			InstanceID: newInstanceID(),
+			Hostname:   hostname,
+			PID:        os.Getpid(),
+			StartedAt:  now,
+			Heartbeat:  now,
+		},
+	}
+	if err := lock.write(); err != nil {
+		return nil, nil, err
+	}
+	return lock, nil, nil
+}
+
+// Holder 当前实例的持有者信息
+func (l *Lock) Holder() Holder {
+	return l.holder
+}
+
+// Refresh 更新心跳；锁文件已被其他实例改写时返回 ErrLost 和新的持有者
+func (l *Lock) Refresh() (*Holder, error) {
+	current, err := read(l.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if current != nil && current.InstanceID != l.holder.InstanceID {
+		return current, ErrLost
+	}
+
+	l.holder.Heartbeat = time.Now().UTC().Format(time.RFC3339)
+	return nil, l.write()
+}
+
+// Release 释放锁，仅删除仍属于本实例的锁文件
+func (l *Lock) Release() error {
+	current, err := read(l.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if current.InstanceID != l.holder.InstanceID {
+		return nil
+	}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove lock file: %w", err)
+	}
+	return nil
+}
+
+// write 先写临时文件再重命名，避免其他实例读到半截内容
+func (l *Lock) write() error {
+	data, err := json.MarshalIndent(l.holder, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := l.path + ".tmp-" + l.holder.InstanceID
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write lock file: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write lock file: %w", err)
+	}
+	return nil
+}
+
+// read 读取锁文件，内容损坏时视为不存在
+func read(path string) (*Holder, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	holder := &Holder{}
+	if err := json.Unmarshal(data, holder); err != nil || holder.InstanceID == "" {
+		return nil, os.ErrNotExist
+	}
+	return holder, nil
+}
+
+// isStale 判断锁是否失效
+func isStale(holder *Holder, staleAfter time.Duration) bool {
+	heartbeat, err := time.Parse(time.RFC3339, holder.Heartbeat)
+	if err != nil || time.Since(heartbeat) > staleAfter {
+		return true
+	}
+
+	hostname, _ := os.Hostname()
+	if holder.Hostname == hostname {
+		return holder.PID == os.Getpid() || !processAlive(holder.PID)
+	}
+	return false
+}
+
+// newInstanceID 生成实例ID
+func newInstanceID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
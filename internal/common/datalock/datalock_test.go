@@ -0,0 +1,87 @@
+package datalock
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeHolder(t *testing.T, dir string, holder Holder) {
+	t.Helper()
+	data, _ := json.Marshal(holder)
+	if err := os.WriteFile(filepath.Join(dir, FileName), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAcquireAndRelease(t *testing.T) {
+	dir := t.TempDir()
+
+	lock, holder, err := Acquire(dir, time.Minute)
+	if err != nil || holder != nil {
+		t.Fatalf("Acquire() = %v, %v", holder, err)
+	}
+	if _, err := lock.Refresh(); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, FileName)); !os.IsNotExist(err) {
+		t.Error("lock file should be removed after release")
+	}
+}
+
+func TestAcquireHeldByOtherHost(t *testing.T) {
+	dir := t.TempDir()
+	writeHolder(t, dir, Holder{
+		InstanceID: "other",
+		Hostname:   "other-machine",
+		PID:        1,
+		Heartbeat:  time.Now().UTC().Format(time.RFC3339),
+	})
+
+	_, holder, err := Acquire(dir, time.Minute)
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("Acquire() error = %v, want ErrLocked", err)
+	}
+	if holder == nil || holder.Hostname != "other-machine" {
+		t.Errorf("holder = %+v", holder)
+	}
+}
+
+func TestAcquireStaleLock(t *testing.T) {
+	dir := t.TempDir()
+	writeHolder(t, dir, Holder{
+		InstanceID: "other",
+		Hostname:   "other-machine",
+		Heartbeat:  time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+	})
+
+	if _, _, err := Acquire(dir, time.Minute); err != nil {
+		t.Fatalf("stale lock should be taken over, got %v", err)
+	}
+}
+
+func TestRefreshDetectsTakeover(t *testing.T) {
+	dir := t.TempDir()
+	lock, _, err := Acquire(dir, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeHolder(t, dir, Holder{InstanceID: "intruder", Hostname: "other-machine"})
+	holder, err := lock.Refresh()
+	if !errors.Is(err, ErrLost) || holder.InstanceID != "intruder" {
+		t.Fatalf("Refresh() = %+v, %v; want ErrLost", holder, err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, FileName)); err != nil {
+		t.Error("release must not remove a lock owned by another instance")
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package datalock
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processAlive 判断本机进程是否存活
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,250 @@
+package dbcrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// 加密数据库文件格式：magic | nonce | AES-256-GCM密文
+// 数据密钥随机生成，由口令派生的密钥包装后保存在密钥文件中，修改口令无需重新加密数据库
+const (
+	KeySize   = 32
+	saltSize  = 16
+	nonceSize = 12
+)
+
+// argon2id 参数
+const (
+	argonTime    = 3
+	argonMemory  = 64 * 1024
+	argonThreads = 2
+)
+
+// fileMagic 加密数据库文件头
+var fileMagic = []byte("VOIDRAFT-DB1\n")
+
+var (
+	// ErrWrongPassphrase 口令错误
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+	// ErrCorrupted 文件损坏或密钥不匹配
+	ErrCorrupted = errors.New("encrypted database is corrupted or the key does not match")
+	// ErrInvalidKeyFile 密钥文件格式错误，可能已损坏或被篡改
+	ErrInvalidKeyFile = errors.New("database key file is invalid")
+)
+
+// KeyFile 包装后的数据密钥，可以随数据目录一起同步
+type KeyFile struct {
+	Version    int    `json:"version"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	WrappedKey []byte `json:"wrappedKey"`
+}
+
+// NewKey 生成随机数据密钥
+func NewKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	return key, nil
+}
+
+// WrapKey 使用口令包装数据密钥
+func WrapKey(key []byte, passphrase string) (*KeyFile, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase is empty")
+	}
+
+	kf := &KeyFile{Version: 1, Salt: make([]byte, saltSize), Nonce: make([]byte, nonceSize)}
+	if _, err := rand.Read(kf.Salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	if _, err := rand.Read(kf.Nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	gcm, err := newGCM(deriveKey(passphrase, kf.Salt))
+	if err != nil {
+		return nil, err
+	}
+	kf.WrappedKey = gcm.Seal(nil, kf.Nonce, key, nil)
+	return kf, nil
+}
+
+// UnwrapKey 使用口令解出数据密钥，密钥文件来自可能被同步的数据目录，先校验长度
+func UnwrapKey(kf *KeyFile, passphrase string) ([]byte, error) {
+	if len(kf.Salt) != saltSize {
+		return nil, ErrInvalidKeyFile
+	}
+	gcm, err := newGCM(deriveKey(passphrase, kf.Salt))
+	if err != nil {
+		return nil, err
+	}
+	if len(kf.Nonce) != gcm.NonceSize() {
+		return nil, ErrInvalidKeyFile
+	}
+	key, err := gcm.Open(nil, kf.Nonce, kf.WrappedKey, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return key, nil
+}
+
+// ReadKeyFile 读取密钥文件
+func ReadKeyFile(path string) (*KeyFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	kf := &KeyFile{}
+	if err := json.Unmarshal(data, kf); err != nil {
+		return nil, fmt.Errorf("invalid key file: %w", err)
+	}
+	return kf, nil
+}
+
+// WriteKeyFile 写入密钥文件
+func WriteKeyFile(path string, kf *KeyFile) error {
+	data, err := json.MarshalIndent(kf, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, data, 0600)
+}
+
+// EncryptFile 加密 src 写入 dst
+func EncryptFile(src, dst string, key []byte) error {
+	plaintext, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read database: %w", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	out := make([]byte, 0, len(fileMagic)+nonceSize+len(plaintext)+gcm.Overhead())
+	out = append(out, fileMagic...)
+	out = append(out, nonce...)
+	out = gcm.Seal(out, nonce, plaintext, fileMagic)
+	return writeAtomic(dst, out, 0600)
+}
+
+// DecryptFile 解密 src 写入 dst
+func DecryptFile(src, dst string, key []byte) error {
+	plaintext, err := decryptFile(src, key)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(dst, plaintext, 0600)
+}
+
+// VerifyFile 校验密钥能解开 src，只在内存中解密，不写出明文
+func VerifyFile(src string, key []byte) error {
+	_, err := decryptFile(src, key)
+	return err
+}
+
+// decryptFile 读取并解密 src，GCM 同时校验内容完整性
+func decryptFile(src string, key []byte) ([]byte, error) {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encrypted database: %w", err)
+	}
+	if !bytes.HasPrefix(data, fileMagic) || len(data) < len(fileMagic)+nonceSize {
+		return nil, ErrCorrupted
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	body := data[len(fileMagic):]
+	plaintext, err := gcm.Open(nil, body[:nonceSize], body[nonceSize:], fileMagic)
+	if err != nil {
+		return nil, ErrCorrupted
+	}
+	return plaintext, nil
+}
+
+// Shred 覆写后删除明文文件，尽量减少残留（不保证在SSD或写时复制文件系统上有效）
+func Shred(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	if file, err := os.OpenFile(path, os.O_WRONLY, 0); err == nil {
+		zeros := make([]byte, 64*1024)
+		for remaining := info.Size(); remaining > 0; remaining -= int64(len(zeros)) {
+			n := min(remaining, int64(len(zeros)))
+			if _, err := file.Write(zeros[:n]); err != nil {
+				break
+			}
+		}
+		file.Sync()
+		file.Close()
+	}
+	return os.Remove(path)
+}
+
+// deriveKey 由口令派生密钥
+func deriveKey(passphrase string, salt []byte) []byte {
+	return argon2.IDKey([]byte(passphrase), salt, argonTime, argonMemory, argonThreads, KeySize)
+}
+
+// newGCM 创建 AES-GCM
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// writeAtomic 先写临时文件再重命名
+func writeAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".voidraft-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return fmt.Errorf("failed to set file permission: %w", err)
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,110 @@
+package dbcrypt
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWrapUnwrapKey(t *testing.T) {
+	key, err := NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kf, err := WrapKey(key, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnwrapKey(kf, "correct horse")
+	if err != nil || !bytes.Equal(got, key) {
+		t.Fatalf("UnwrapKey() = %x, %v", got, err)
+	}
+	if _, err := UnwrapKey(kf, "wrong"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("UnwrapKey() with wrong passphrase error = %v", err)
+	}
+}
+
+func TestUnwrapKeyInvalidKeyFile(t *testing.T) {
+	key, _ := NewKey()
+	kf, err := WrapKey(key, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(kf *KeyFile)
+	}{
+		{"short nonce", func(kf *KeyFile) { kf.Nonce = kf.Nonce[:4] }},
+		{"empty nonce", func(kf *KeyFile) { kf.Nonce = nil }},
+		{"long nonce", func(kf *KeyFile) { kf.Nonce = append(kf.Nonce, 0) }},
+		{"short salt", func(kf *KeyFile) { kf.Salt = kf.Salt[:8] }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broken := *kf
+			broken.Salt = append([]byte(nil), kf.Salt...)
+			broken.Nonce = append([]byte(nil), kf.Nonce...)
+			tt.modify(&broken)
+			if _, err := UnwrapKey(&broken, "correct horse"); !errors.Is(err, ErrInvalidKeyFile) {
+				t.Errorf("UnwrapKey() error = %v, want ErrInvalidKeyFile", err)
+			}
+		})
+	}
+}
+
+func TestEncryptDecryptFile(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.db")
+	enc := filepath.Join(dir, "enc.db")
+	out := filepath.Join(dir, "out.db")
+	content := []byte("SQLite format 3\x00 some pages")
+	if err := os.WriteFile(plain, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	key, _ := NewKey()
+	if err := EncryptFile(plain, enc, key); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(enc); bytes.Contains(data, []byte("SQLite")) {
+		t.Error("encrypted file contains plaintext")
+	}
+	if err := DecryptFile(enc, out, key); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(out); !bytes.Equal(data, content) {
+		t.Errorf("decrypted = %q", data)
+	}
+
+	if err := VerifyFile(enc, key); err != nil {
+		t.Errorf("VerifyFile() error = %v", err)
+	}
+
+	other, _ := NewKey()
+	if err := DecryptFile(enc, out, other); !errors.Is(err, ErrCorrupted) {
+		t.Errorf("DecryptFile() with wrong key error = %v", err)
+	}
+	if err := VerifyFile(enc, other); !errors.Is(err, ErrCorrupted) {
+		t.Errorf("VerifyFile() with wrong key error = %v", err)
+	}
+}
+
+func TestShred(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret.db")
+	if err := os.WriteFile(path, []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Shred(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("file should be removed")
+	}
+	if err := Shred(path); err != nil {
+		t.Errorf("Shred() on missing file error = %v", err)
+	}
+}
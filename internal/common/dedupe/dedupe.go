@@ -0,0 +1,287 @@
+// Package dedupe 查找内容相同或相近的文档
+// 完全相同按规范化后的内容哈希分组，相近的文档用词组分片（shingle）的 MinHash 签名找出候选，再以 Jaccard 相似度确认
+package dedupe
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"voidraft/internal/common/blocks"
+)
+
+const (
+	// ShingleSize 每个分片包含的词数
+	ShingleSize = 3
+	// DefaultThreshold 视为相近的默认 Jaccard 相似度
+	DefaultThreshold = 0.8
+
+	// MinHash 签名长度和 LSH 分段，16 段每段 4 行时相似度 0.8 的文档几乎总能成为候选
+	signatureSize = 64
+	bandRows      = 4
+)
+
+// Item 参与比较的文档
+type Item struct {
+	ID      int64
+	Content string
+}
+
+// Cluster 一组重复的文档
+type Cluster struct {
+	IDs        []int64 // 按 ID 升序
+	Exact      bool    // 组内文档规范化后的内容完全相同
+	Similarity float64 // 组内相连文档的最低相似度，完全相同时为 1
+}
+
+// document 预处理后的文档
+type document struct {
+	id        int64
+	hash      string
+	shingles  map[uint64]struct{}
+	signature []uint64
+}
+
+// Find 查找重复文档，threshold 为相近的最低相似度，小于等于 0 时只查找完全相同的文档
+// 没有文字内容的文档不参与比较，避免把所有空文档归为一组
+func Find(items []Item, threshold float64) []Cluster {
+	docs := make([]*document, 0, len(items))
+	for _, item := range items {
+		tokens := Tokens(item.Content)
+		if len(tokens) == 0 {
+			continue
+		}
+		sum := sha256.Sum256([]byte(strings.Join(tokens, " ")))
+		doc := &document{id: item.ID, hash: hex.EncodeToString(sum[:])}
+		if threshold > 0 {
+			doc.shingles = shingles(tokens, ShingleSize)
+			doc.signature = minHash(doc.shingles)
+		}
+		docs = append(docs, doc)
+	}
+
+	uf := newUnionFind(len(docs))
+	similarity := make(map[int]float64)
+	link := func(a, b int, score float64) {
+		for _, root := range []int{uf.find(a), uf.find(b)} {
+			if prev, ok := similarity[root]; ok && prev < score {
+				score = prev
+			}
+		}
+		similarity[uf.union(a, b)] = score
+	}
+
+	// 完全相同
+	byHash := make(map[string]int)
+	for i, doc := range docs {
+		if first, ok := byHash[doc.hash]; ok {
+			link(first, i, 1)
+		} else {
+			byHash[doc.hash] = i
+		}
+	}
+
+	// 相近：签名的任一分段相同即为候选，只比较哈希不同的代表文档
+	if threshold > 0 {
+		checked := make(map[[2]int]bool)
+		for band := 0; band < signatureSize/bandRows; band++ {
+			buckets := make(map[string][]int)
+			for i, doc := range docs {
+				if byHash[doc.hash] != i {
+					continue
+				}
+				key := bandKey(doc.signature[band*bandRows : (band+1)*bandRows])
+				buckets[key] = append(buckets[key], i)
+			}
+			for _, bucket := range buckets {
+				for x := 0; x < len(bucket); x++ {
+					for y := x + 1; y < len(bucket); y++ {
+						pair := [2]int{bucket[x], bucket[y]}
+						if checked[pair] {
+							continue
+						}
+						checked[pair] = true
+						if score := Jaccard(docs[pair[0]].shingles, docs[pair[1]].shingles); score >= threshold {
+							link(pair[0], pair[1], score)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	for i := range docs {
+		root := uf.find(i)
+		groups[root] = append(groups[root], i)
+	}
+
+	var clusters []Cluster
+	for _, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		root := uf.find(members[0])
+		cluster := Cluster{Exact: true, Similarity: similarity[root]}
+		for _, i := range members {
+			cluster.IDs = append(cluster.IDs, docs[i].id)
+			if docs[i].hash != docs[members[0]].hash {
+				cluster.Exact = false
+			}
+		}
+		sort.Slice(cluster.IDs, func(a, b int) bool { return cluster.IDs[a] < cluster.IDs[b] })
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(a, b int) bool {
+		if clusters[a].Exact != clusters[b].Exact {
+			return clusters[a].Exact
+		}
+		return clusters[a].IDs[0] < clusters[b].IDs[0]
+	})
+	return clusters
+}
+
+// Tokens 把文档拆分为小写的词，忽略块分隔符、标点和空白；中日韩文字每个字为一个词
+func Tokens(content string) []string {
+	var tokens []string
+	for _, block := range blocks.Parse(content) {
+		var word strings.Builder
+		flush := func() {
+			if word.Len() > 0 {
+				tokens = append(tokens, word.String())
+				word.Reset()
+			}
+		}
+		for _, r := range block.Content {
+			switch {
+			case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+				flush()
+				tokens = append(tokens, string(r))
+			case unicode.IsLetter(r) || unicode.IsDigit(r):
+				word.WriteRune(unicode.ToLower(r))
+			default:
+				flush()
+			}
+		}
+		flush()
+	}
+	return tokens
+}
+
+// Jaccard 两个分片集合的相似度
+func Jaccard(a, b map[uint64]struct{}) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	shared := 0
+	for s := range a {
+		if _, ok := b[s]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// shingles 连续 size 个词为一个分片，词数不足时整篇为一个分片
+func shingles(tokens []string, size int) map[uint64]struct{} {
+	set := make(map[uint64]struct{})
+	if len(tokens) < size {
+		set[hashString(strings.Join(tokens, " "))] = struct{}{}
+		return set
+	}
+	for i := 0; i+size <= len(tokens); i++ {
+		set[hashString(strings.Join(tokens[i:i+size], " "))] = struct{}{}
+	}
+	return set
+}
+
+// minHash 计算 MinHash 签名，第 i 个哈希函数为分片哈希与固定种子异或后再混合
+func minHash(set map[uint64]struct{}) []uint64 {
+	signature := make([]uint64, signatureSize)
+	for i := range signature {
+		signature[i] = math.MaxUint64
+	}
+	for s := range set {
+		for i := range signature {
+			if h := mix(s ^ seeds[i]); h < signature[i] {
+				signature[i] = h
+			}
+		}
+	}
+	return signature
+}
+
+// seeds MinHash 各哈希函数的种子
+var seeds = func() []uint64 {
+	out := make([]uint64, signatureSize)
+	state := uint64(0x9e3779b97f4a7c15)
+	for i := range out {
+		state = mix(state + uint64(i))
+		out[i] = state
+	}
+	return out
+}()
+
+// mix splitmix64 的混合步骤
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// hashString 分片哈希
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// bandKey 签名分段作为分桶的键
+func bandKey(rows []uint64) string {
+	var b strings.Builder
+	for _, v := range rows {
+		for shift := 0; shift < 64; shift += 8 {
+			b.WriteByte(byte(v >> shift))
+		}
+	}
+	return b.String()
+}
+
+// unionFind 并查集，合并相连的重复文档
+type unionFind struct {
+	parent []int
+}
+
+func newUnionFind(n int) *unionFind {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	return &unionFind{parent: parent}
+}
+
+func (u *unionFind) find(i int) int {
+	for u.parent[i] != i {
+		u.parent[i] = u.parent[u.parent[i]]
+		i = u.parent[i]
+	}
+	return i
+}
+
+// union 合并两个集合，返回合并后的根
+func (u *unionFind) union(a, b int) int {
+	ra, rb := u.find(a), u.find(b)
+	if ra != rb {
+		u.parent[rb] = ra
+	}
+	return ra
+}
@@ -0,0 +1,58 @@
+package dedupe
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokens(t *testing.T) {
+	got := Tokens("\n∞∞∞md\n# Hello, World!\n∞∞∞text\n你好 go1.25")
+	want := []string{"hello", "world", "你", "好", "go1", "25"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokens = %v, want %v", got, want)
+	}
+}
+
+func TestFind(t *testing.T) {
+	base := "Meeting notes for the quarterly planning session. We reviewed the roadmap, agreed to ship " +
+		"the sync rewrite before the holidays, moved the mobile prototype to next quarter, and asked " +
+		"the design team for updated mockups of the settings page. Action items were assigned to " +
+		"each owner with due dates at the end of the month."
+	items := []Item{
+		{ID: 1, Content: "\n∞∞∞text\n" + base},
+		{ID: 2, Content: "\n∞∞∞md\n" + strings.ToUpper(base) + "  "}, // 只有大小写、空白和块类型不同
+		{ID: 3, Content: "\n∞∞∞text\n" + base + "and one more line"},
+		{ID: 4, Content: "\n∞∞∞text\nsomething entirely different about databases and indexes"},
+		{ID: 5, Content: "\n∞∞∞text-a\n"},
+		{ID: 6, Content: "\n∞∞∞text-a\n"},
+	}
+
+	exact := Find(items, 0)
+	if len(exact) != 1 || !exact[0].Exact || !reflect.DeepEqual(exact[0].IDs, []int64{1, 2}) {
+		t.Fatalf("Find exact = %+v", exact)
+	}
+
+	near := Find(items, DefaultThreshold)
+	if len(near) != 1 {
+		t.Fatalf("Find near = %+v, want one cluster", near)
+	}
+	cluster := near[0]
+	if cluster.Exact || !reflect.DeepEqual(cluster.IDs, []int64{1, 2, 3}) {
+		t.Errorf("near cluster = %+v, want ids 1, 2, 3", cluster)
+	}
+	if cluster.Similarity < DefaultThreshold || cluster.Similarity >= 1 {
+		t.Errorf("near cluster similarity = %v", cluster.Similarity)
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	a := shingles(strings.Fields("a b c d e"), 3)
+	b := shingles(strings.Fields("a b c d x"), 3)
+	if got := Jaccard(a, b); got != 0.5 {
+		t.Errorf("Jaccard = %v, want 0.5", got)
+	}
+	if got := Jaccard(a, a); got != 1 {
+		t.Errorf("Jaccard(a, a) = %v, want 1", got)
+	}
+}
@@ -0,0 +1,104 @@
+// Package doclinks 解析文档之间的链接
+// 支持 [[文档标题]]、[[文档标题|显示文字]] 和 voidraft://doc/<id> 两种写法，标题匹配不区分大小写
+package doclinks
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Scheme 按文档 ID 链接的前缀
+const Scheme = "voidraft://doc/"
+
+var (
+	titlePattern = regexp.MustCompile(`\[\[([^\[\]|\n]+)(?:\|([^\[\]\n]*))?\]\]`)
+	idPattern    = regexp.MustCompile(`voidraft://doc/(\d+)`)
+)
+
+// Link 文档中的一个链接目标
+type Link struct {
+	DocumentID int64  // voidraft://doc/<id> 链接的文档 ID，标题链接为 0
+	Title      string // [[标题]] 链接的标题，已去除首尾空白
+}
+
+// Span 内容中一个标题链接的位置，偏移量按字节计
+type Span struct {
+	Start int
+	End   int
+	Title string // 链接的标题，已去除首尾空白
+	Label string // | 后的显示文字，没有时为空
+}
+
+// Parse 提取内容中的全部链接目标，按首次出现的顺序去重；标题忽略大小写去重，保留首次出现的写法
+func Parse(content string) []Link {
+	var links []Link
+	seenIDs := make(map[int64]bool)
+	seenTitles := make(map[string]bool)
+
+	for _, span := range TitleSpans(content) {
+		key := strings.ToLower(span.Title)
+		if !seenTitles[key] {
+			seenTitles[key] = true
+			links = append(links, Link{Title: span.Title})
+		}
+	}
+	for _, match := range idPattern.FindAllStringSubmatch(content, -1) {
+		id, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || id <= 0 || seenIDs[id] {
+			continue
+		}
+		seenIDs[id] = true
+		links = append(links, Link{DocumentID: id})
+	}
+	return links
+}
+
+// TitleSpans 内容中全部标题链接的位置，标题为空白的链接被忽略
+func TitleSpans(content string) []Span {
+	var spans []Span
+	for _, m := range titlePattern.FindAllStringSubmatchIndex(content, -1) {
+		title := strings.TrimSpace(content[m[2]:m[3]])
+		if title == "" {
+			continue
+		}
+		span := Span{Start: m[0], End: m[1], Title: title}
+		if m[4] >= 0 {
+			span.Label = content[m[4]:m[5]]
+		}
+		spans = append(spans, span)
+	}
+	return spans
+}
+
+// Resolve 解析单个链接文本：voidraft://doc/<id>、[[标题]] 或直接给出的标题
+func Resolve(text string) (Link, bool) {
+	text = strings.TrimSpace(text)
+	if rest, ok := strings.CutPrefix(text, Scheme); ok {
+		id, err := strconv.ParseInt(rest, 10, 64)
+		if err != nil || id <= 0 {
+			return Link{}, false
+		}
+		return Link{DocumentID: id}, true
+	}
+	if spans := TitleSpans(text); len(spans) == 1 && spans[0].Start == 0 && spans[0].End == len(text) {
+		return Link{Title: spans[0].Title}, true
+	}
+	if text == "" || strings.ContainsAny(text, "[]|\n") {
+		return Link{}, false
+	}
+	return Link{Title: text}, true
+}
+
+// Format 生成标题链接，label 非空时作为显示文字
+func Format(title, label string) string {
+	if label == "" {
+		return "[[" + title + "]]"
+	}
+	return "[[" + title + "|" + label + "]]"
+}
+
+// DocumentURL 按文档 ID 链接的地址
+func DocumentURL(id int64) string {
+	return Scheme + strconv.FormatInt(id, 10)
+}
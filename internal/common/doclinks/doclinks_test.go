@@ -0,0 +1,62 @@
+package doclinks
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content := "\n∞∞∞md\nSee [[Meeting Notes]] and [[ meeting notes |notes]].\n" +
+		"Also voidraft://doc/12, voidraft://doc/12 again and [[Roadmap|the plan]].\n[[ ]] [[bad\nlink]]"
+	want := []Link{{Title: "Meeting Notes"}, {Title: "Roadmap"}, {DocumentID: 12}}
+	if got := Parse(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse = %+v, want %+v", got, want)
+	}
+}
+
+func TestTitleSpans(t *testing.T) {
+	content := "a [[Roadmap|the plan]] b [[中文标题]]"
+	spans := TitleSpans(content)
+	if len(spans) != 2 {
+		t.Fatalf("TitleSpans returned %d spans, want 2", len(spans))
+	}
+	if got := content[spans[0].Start:spans[0].End]; got != "[[Roadmap|the plan]]" {
+		t.Errorf("first span = %q", got)
+	}
+	if spans[0].Label != "the plan" || spans[1].Title != "中文标题" || spans[1].Label != "" {
+		t.Errorf("spans = %+v", spans)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		text string
+		want Link
+		ok   bool
+	}{
+		{"voidraft://doc/7", Link{DocumentID: 7}, true},
+		{"voidraft://doc/x", Link{}, false},
+		{"[[Roadmap|plan]]", Link{Title: "Roadmap"}, true},
+		{"  Roadmap ", Link{Title: "Roadmap"}, true},
+		{"[[a]] and [[b]]", Link{}, false},
+		{"", Link{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := Resolve(tt.text)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Resolve(%q) = %+v, %v, want %+v, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	if got := Format("Roadmap", ""); got != "[[Roadmap]]" {
+		t.Errorf("Format = %q", got)
+	}
+	if got := Format("Roadmap", "plan"); got != "[[Roadmap|plan]]" {
+		t.Errorf("Format with label = %q", got)
+	}
+	if got := DocumentURL(42); got != "voidraft://doc/42" {
+		t.Errorf("DocumentURL = %q", got)
+	}
+}
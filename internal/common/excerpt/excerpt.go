@@ -0,0 +1,69 @@
+package excerpt
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"voidraft/internal/common/blocks"
+)
+
+// DefaultLength 列表预览摘要的默认长度（字符）
+const DefaultLength = 160
+
+// markdown 标记的替换规则，按顺序执行
+var markdownRules = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile("(?m)^\\s*```.*$"), ""},
+	{regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`), "$1"},
+	{regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`), "$1"},
+	{regexp.MustCompile(`<[^>\n]+>`), ""},
+	{regexp.MustCompile(`(?m)^\s{0,3}#{1,6}\s+`), ""},
+	{regexp.MustCompile(`(?m)^\s{0,3}>\s?`), ""},
+	{regexp.MustCompile(`(?m)^\s*(?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?`), ""},
+	{regexp.MustCompile(`(?m)^\s*(?:-{3,}|\*{3,}|_{3,})\s*$`), ""},
+	{regexp.MustCompile(`\*\*([^*\n]+)\*\*`), "$1"},
+	{regexp.MustCompile(`__([^_\n]+)__`), "$1"},
+	{regexp.MustCompile(`\*([^*\n]+)\*`), "$1"},
+	{regexp.MustCompile(`~~([^~\n]+)~~`), "$1"},
+	{regexp.MustCompile("`([^`\n]+)`"), "$1"},
+}
+
+// Plain 生成文档的纯文本摘要：去掉块分隔符和 markdown 标记，合并空白，截取前 length 个字符
+func Plain(content string, length int) string {
+	if length <= 0 {
+		length = DefaultLength
+	}
+
+	var builder strings.Builder
+	count := 0
+	for _, block := range blocks.Parse(content) {
+		text := block.Content
+		if block.Language == "md" {
+			text = StripMarkdown(text)
+		}
+		for _, field := range strings.FieldsFunc(text, unicode.IsSpace) {
+			if count > 0 {
+				builder.WriteByte(' ')
+				count++
+			}
+			for _, r := range field {
+				if count >= length {
+					return strings.TrimSpace(builder.String()) + "…"
+				}
+				builder.WriteRune(r)
+				count++
+			}
+		}
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+// StripMarkdown 去除常见的 markdown 标记，保留文字内容
+func StripMarkdown(text string) string {
+	for _, rule := range markdownRules {
+		text = rule.pattern.ReplaceAllString(text, rule.replacement)
+	}
+	return text
+}
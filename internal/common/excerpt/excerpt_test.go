@@ -0,0 +1,103 @@
+package excerpt
+
+import "testing"
+
+func TestPlain(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		length  int
+		want    string
+	}{
+		{"empty document", "\n∞∞∞text-a\n", 10, ""},
+		{"collapses whitespace", "\n∞∞∞text-a\nhello\n\n  world\t!", 50, "hello world !"},
+		{"joins blocks", "\n∞∞∞text\nfirst\n∞∞∞go\nfunc main() {}", 50, "first func main() {}"},
+		{"strips markdown", "\n∞∞∞md\n# Title\n- **bold** and [link](http://x)\n> `code`", 50, "Title bold and link code"},
+		{"truncates", "\n∞∞∞text\nabcdefghij", 4, "abcd…"},
+		{"keeps snake_case in text", "\n∞∞∞text\nsome_var_name", 50, "some_var_name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Plain(tt.content, tt.length); got != tt.want {
+				t.Errorf("Plain() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMarked(t *testing.T) {
+	text, ranges := ParseMarked("…the quick fox 😀 jumps")
+	if text != "…the quick fox 😀 jumps" {
+		t.Fatalf("text = %q", text)
+	}
+	want := []Range{{Start: 5, End: 10}, {Start: 18, End: 23}}
+	if len(ranges) != len(want) {
+		t.Fatalf("ranges = %v, want %v", ranges, want)
+	}
+	for i := range want {
+		if ranges[i] != want[i] {
+			t.Errorf("ranges[%d] = %v, want %v", i, ranges[i], want[i])
+		}
+	}
+}
+
+func TestTitle(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		length  int
+		want    string
+	}{
+		{"empty document", "\n∞∞∞text-a\n\n  \n", 0, ""},
+		{"first non-empty line", "\n∞∞∞text-a\n\n  Shopping list  \nmilk", 0, "Shopping list"},
+		{"markdown heading wins", "\n∞∞∞md\nintro text\n## Meeting *notes* ##\nbody", 0, "Meeting notes"},
+		{"heading in later block", "\n∞∞∞text\nfirst line\n∞∞∞md\n# Plan", 0, "Plan"},
+		{"ignores hash in code", "\n∞∞∞python\n# comment\nprint(1)", 0, "# comment"},
+		{"strips list marker", "\n∞∞∞md\n- [ ] buy **milk**", 0, "buy milk"},
+		{"truncates", "\n∞∞∞text\nabcdefghij", 5, "abcd…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Title(tt.content, tt.length); got != tt.want {
+				t.Errorf("Title() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMark(t *testing.T) {
+	tests := []struct {
+		text  string
+		terms []string
+		want  string
+	}{
+		{"这是测试文本", []string{"测试"}, "这是测试文本"},
+		{"Go and GO", []string{"go"}, "Go and GO"},
+		{"abab", []string{"ab", "ba"}, "abab"},
+		{"go gopher", []string{"go"}, "go gopher"},
+		{"nothing", []string{"x", ""}, "nothing"},
+	}
+	for _, tt := range tests {
+		if got := Mark(tt.text, tt.terms); got != tt.want {
+			t.Errorf("Mark(%q, %q) = %q, want %q", tt.text, tt.terms, got, tt.want)
+		}
+	}
+}
+
+func TestAround(t *testing.T) {
+	text := "一二三四五六七八九十测试甲乙丙丁戊己庚辛壬癸"
+	if got, want := Around(text, []string{"测试"}, 8), "…九十测试甲乙丙丁…"; got != want {
+		t.Errorf("Around = %q, want %q", got, want)
+	}
+	if got, want := Around(text, []string{"癸"}, 4), "…庚辛壬癸"; got != want {
+		t.Errorf("Around at end = %q, want %q", got, want)
+	}
+	if got, want := Around(text, []string{"none"}, 4), "一二三四…"; got != want {
+		t.Errorf("Around without match = %q, want %q", got, want)
+	}
+	if got := Around("short", []string{"x"}, 10); got != "short" {
+		t.Errorf("Around short = %q", got)
+	}
+}
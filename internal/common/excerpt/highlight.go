@@ -0,0 +1,132 @@
+package excerpt
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// 高亮标记，使用私有区字符避免与正文冲突
+const (
+	MarkOpen  = ''
+	MarkClose = ''
+)
+
+// Range 高亮区间，偏移以 UTF-16 码元计，可直接用于前端字符串截取
+type Range struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// ParseMarked 去掉文本中的高亮标记并返回高亮区间
+func ParseMarked(marked string) (string, []Range) {
+	var builder strings.Builder
+	builder.Grow(len(marked))
+
+	var ranges []Range
+	offset, start := 0, -1
+	for _, r := range marked {
+		switch r {
+		case MarkOpen:
+			start = offset
+		case MarkClose:
+			if start >= 0 && offset > start {
+				ranges = append(ranges, Range{Start: start, End: offset})
+			}
+			start = -1
+		default:
+			builder.WriteRune(r)
+			offset += utf16.RuneLen(r)
+		}
+	}
+	return builder.String(), ranges
+}
+
+// Mark 不区分大小写地为文本中出现的词加上高亮标记，已有标记内的部分不再重复标记
+func Mark(text string, terms []string) string {
+	runes := []rune(text)
+	lower := lowerRunes(runes)
+	marked := make([]bool, len(runes))
+	inside := false
+	for i, r := range runes {
+		if r == MarkOpen {
+			inside = true
+		}
+		marked[i] = inside
+		if r == MarkClose {
+			inside = false
+		}
+	}
+
+	hit := make([]bool, len(runes))
+	for _, term := range terms {
+		needle := lowerRunes([]rune(term))
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if slices.Equal(lower[i:i+len(needle)], needle) && !slices.Contains(marked[i:i+len(needle)], true) {
+				for j := i; j < i+len(needle); j++ {
+					hit[j] = true
+				}
+			}
+		}
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(text))
+	for i, r := range runes {
+		if hit[i] && (i == 0 || !hit[i-1]) {
+			builder.WriteRune(MarkOpen)
+		}
+		builder.WriteRune(r)
+		if hit[i] && (i == len(runes)-1 || !hit[i+1]) {
+			builder.WriteRune(MarkClose)
+		}
+	}
+	return builder.String()
+}
+
+// Around 截取最先出现的词附近的 length 个字符，两端被截断时加省略号，没有出现时从开头截取
+func Around(text string, terms []string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	lower := lowerRunes(runes)
+	first := -1
+	for _, term := range terms {
+		needle := lowerRunes([]rune(term))
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if (first < 0 || i < first) && slices.Equal(lower[i:i+len(needle)], needle) {
+				first = i
+				break
+			}
+		}
+	}
+
+	start := max(first-length/4, 0)
+	end := min(start+length, len(runes))
+	start = max(end-length, 0)
+	result := string(runes[start:end])
+	if start > 0 {
+		result = "…" + result
+	}
+	if end < len(runes) {
+		result += "…"
+	}
+	return result
+}
+
+// lowerRunes 逐字符转为小写，长度与原文一致，便于按位置对应
+func lowerRunes(runes []rune) []rune {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
+}
@@ -0,0 +1,55 @@
+package excerpt
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"voidraft/internal/common/blocks"
+)
+
+// TitleLength 自动生成的标题长度上限（字符）
+const TitleLength = 60
+
+// headingRegex 匹配 markdown 标题行
+var headingRegex = regexp.MustCompile(`(?m)^\s{0,3}#{1,6}\s+(.+?)\s*#*\s*$`)
+
+// Title 从文档内容生成标题：优先取 markdown 块中的第一个标题，否则取第一个非空行
+// 去掉 markdown 标记并合并空白，超出 length 个字符时截断；没有文字内容时返回空字符串
+func Title(content string, length int) string {
+	if length <= 0 {
+		length = TitleLength
+	}
+
+	list := blocks.Parse(content)
+	for _, block := range list {
+		if block.Language != "md" {
+			continue
+		}
+		if match := headingRegex.FindStringSubmatch(block.Content); match != nil {
+			if title := titleText(StripMarkdown(match[1]), length); title != "" {
+				return title
+			}
+		}
+	}
+
+	for _, block := range list {
+		for _, line := range strings.Split(block.Content, "\n") {
+			if block.Language == "md" {
+				line = StripMarkdown(line)
+			}
+			if title := titleText(line, length); title != "" {
+				return title
+			}
+		}
+	}
+	return ""
+}
+
+// titleText 合并空白并截断
+func titleText(text string, length int) string {
+	text = strings.Join(strings.FieldsFunc(text, unicode.IsSpace), " ")
+	if runes := []rune(text); len(runes) > length {
+		return strings.TrimSpace(string(runes[:length-1])) + "…"
+	}
+	return text
+}
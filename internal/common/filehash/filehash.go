@@ -0,0 +1,125 @@
+// Package filehash 一次读取同时计算多种文件校验和
+package filehash
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"lukechampine.com/blake3"
+)
+
+// Algorithm 校验算法
+type Algorithm string
+
+const (
+	MD5    Algorithm = "md5"
+	SHA1   Algorithm = "sha1"
+	SHA256 Algorithm = "sha256"
+	BLAKE3 Algorithm = "blake3"
+)
+
+// All 支持的全部算法，按输出顺序排列
+var All = []Algorithm{MD5, SHA1, SHA256, BLAKE3}
+
+// bufferSize 每次读取的字节数
+const bufferSize = 1 << 20
+
+// Sum 算法和对应的十六进制校验和
+type Sum struct {
+	Algorithm Algorithm `json:"algorithm"`
+	Hex       string    `json:"hex"`
+}
+
+// Label 算法的显示名称
+func (a Algorithm) Label() string {
+	switch a {
+	case SHA1:
+		return "SHA-1"
+	case SHA256:
+		return "SHA-256"
+	}
+	return strings.ToUpper(string(a))
+}
+
+// newHash 创建算法对应的哈希
+func newHash(a Algorithm) (hash.Hash, error) {
+	switch a {
+	case MD5:
+		return md5.New(), nil
+	case SHA1:
+		return sha1.New(), nil
+	case SHA256:
+		return sha256.New(), nil
+	case BLAKE3:
+		return blake3.New(32, nil), nil
+	}
+	return nil, fmt.Errorf("unsupported algorithm: %s", a)
+}
+
+// Compute 流式读取 r 并计算校验和，algorithms 为空时计算全部算法
+// progress 在每次读取后收到累计字节数，ctx 取消时中止
+func Compute(ctx context.Context, r io.Reader, algorithms []Algorithm, progress func(read int64)) ([]Sum, error) {
+	if len(algorithms) == 0 {
+		algorithms = All
+	}
+	hashes := make([]hash.Hash, len(algorithms))
+	writers := make([]io.Writer, len(algorithms))
+	for i, a := range algorithms {
+		h, err := newHash(a)
+		if err != nil {
+			return nil, err
+		}
+		hashes[i], writers[i] = h, h
+	}
+	w := io.MultiWriter(writers...)
+
+	buf := make([]byte, bufferSize)
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		n, err := r.Read(buf)
+		if n > 0 {
+			w.Write(buf[:n])
+			total += int64(n)
+			if progress != nil {
+				progress(total)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+	}
+
+	sums := make([]Sum, len(algorithms))
+	for i, a := range algorithms {
+		sums[i] = Sum{Algorithm: a, Hex: hex.EncodeToString(hashes[i].Sum(nil))}
+	}
+	return sums, nil
+}
+
+// Format 生成插入文档的校验结果文本
+func Format(name string, size int64, sums []Sum) string {
+	width := len("Size")
+	for _, sum := range sums {
+		width = max(width, len(sum.Algorithm.Label()))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-*s  %s\n", width, "File", name)
+	fmt.Fprintf(&b, "%-*s  %d bytes\n", width, "Size", size)
+	for _, sum := range sums {
+		fmt.Fprintf(&b, "%-*s  %s\n", width, sum.Algorithm.Label(), sum.Hex)
+	}
+	return b.String()
+}
@@ -0,0 +1,49 @@
+package filehash
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	var reads []int64
+	sums, err := Compute(context.Background(), strings.NewReader("abc"), nil, func(read int64) { reads = append(reads, read) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[Algorithm]string{
+		MD5:    "900150983cd24fb0d6963f7d28e17f72",
+		SHA1:   "a9993e364706816aba3e25717850c26c9cd0d89d",
+		SHA256: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		BLAKE3: "6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85",
+	}
+	if len(sums) != len(want) {
+		t.Fatalf("len(sums) = %d", len(sums))
+	}
+	for _, sum := range sums {
+		if sum.Hex != want[sum.Algorithm] {
+			t.Errorf("%s = %s, want %s", sum.Algorithm, sum.Hex, want[sum.Algorithm])
+		}
+	}
+	if len(reads) != 1 || reads[0] != 3 {
+		t.Errorf("progress = %v", reads)
+	}
+
+	if _, err := Compute(context.Background(), strings.NewReader(""), []Algorithm{"crc32"}, nil); err == nil {
+		t.Error("expected error for unsupported algorithm")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Compute(ctx, strings.NewReader("abc"), nil, nil); err == nil {
+		t.Error("expected error for canceled context")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	got := Format("a.iso", 3, []Sum{{MD5, "aa"}, {SHA256, "bb"}})
+	want := "File     a.iso\nSize     3 bytes\nMD5      aa\nSHA-256  bb\n"
+	if got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+// Package fontface 主字体加系统中日韩后备字体，按字符选择包含字形的字体，供服务端绘制文字的渲染复用
+package fontface
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// fallbackFontPaths 各平台常见的中日韩字体，主字体缺少的字符从这里取字形
+var fallbackFontPaths = []string{
+	// Windows
+	`C:\Windows\Fonts\msyh.ttc`,
+	`C:\Windows\Fonts\simsun.ttc`,
+	`C:\Windows\Fonts\YuGothM.ttc`,
+	`C:\Windows\Fonts\malgun.ttf`,
+	// macOS
+	"/System/Library/Fonts/PingFang.ttc",
+	"/System/Library/Fonts/Hiragino Sans GB.ttc",
+	"/System/Library/Fonts/Supplemental/Arial Unicode.ttf",
+	// Linux
+	"/usr/share/fonts/opentype/noto/NotoSansCJK-Regular.ttc",
+	"/usr/share/fonts/noto-cjk/NotoSansCJK-Regular.ttc",
+	"/usr/share/fonts/google-noto-cjk/NotoSansCJK-Regular.ttc",
+	"/usr/share/fonts/truetype/wqy/wqy-microhei.ttc",
+	"/usr/share/fonts/wenquanyi/wqy-microhei/wqy-microhei.ttc",
+}
+
+var (
+	fallbackOnce sync.Once
+	fallbackFont *opentype.Font
+)
+
+// Fallback 第一个可用的系统中日韩字体，只查找一次；没有时返回 nil
+func Fallback() *opentype.Font {
+	fallbackOnce.Do(func() {
+		for _, path := range fallbackFontPaths {
+			file, err := os.Open(path)
+			if err != nil {
+				continue
+			}
+			// 字体文件较大，按需读取，文件在进程生命周期内保持打开
+			collection, err := opentype.ParseCollectionReaderAt(file)
+			if err == nil && collection.NumFonts() > 0 {
+				if fallbackFont, err = collection.Font(0); err == nil {
+					return
+				}
+			}
+			file.Close()
+		}
+	})
+	return fallbackFont
+}
+
+// Set 主字体和后备字体，后备字体可能为空
+type Set struct {
+	Primary  font.Face
+	Fallback font.Face
+}
+
+// New 创建指定像素大小的字体，系统中有中日韩字体时一并作为后备字体
+func New(primary *opentype.Font, size float64) (*Set, error) {
+	options := &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull}
+	face, err := opentype.NewFace(primary, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create font face: %w", err)
+	}
+	set := &Set{Primary: face}
+	if fallback := Fallback(); fallback != nil {
+		if face, err := opentype.NewFace(fallback, options); err == nil {
+			set.Fallback = face
+		}
+	}
+	return set, nil
+}
+
+// FaceFor 选择包含该字符字形的字体，都不包含时使用主字体
+func (s *Set) FaceFor(r rune) font.Face {
+	if _, ok := s.Primary.GlyphAdvance(r); ok || s.Fallback == nil {
+		return s.Primary
+	}
+	if _, ok := s.Fallback.GlyphAdvance(r); ok {
+		return s.Fallback
+	}
+	return s.Primary
+}
+
+// Advance 字符宽度
+func (s *Set) Advance(r rune) fixed.Int26_6 {
+	advance, _ := s.FaceFor(r).GlyphAdvance(r)
+	return advance
+}
@@ -0,0 +1,44 @@
+package fontface
+
+import (
+	"testing"
+
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+func TestFaceFor(t *testing.T) {
+	mono, err := opentype.Parse(gomono.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := New(mono, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.FaceFor('a') != set.Primary {
+		t.Error("FaceFor('a') should use the primary face")
+	}
+	if set.Advance('a') != set.Advance('M') {
+		t.Error("mono font should have equal advances")
+	}
+
+	// 后备字体也没有字形时退回主字体
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fallback, err := New(regular, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set.Fallback = fallback.Primary
+	if set.FaceFor('中') != set.Primary {
+		t.Error("FaceFor should fall back to the primary face when no face has the glyph")
+	}
+	set.Fallback = nil
+	if set.FaceFor('中') != set.Primary {
+		t.Error("FaceFor without a fallback face should use the primary face")
+	}
+}
@@ -0,0 +1,277 @@
+// Package gallery 获取并校验社区模板和主题索引
+//
+// 索引为 HTTPS 上的 JSON 文件，同目录下的 <索引>.sig 为 Base64 编码的 Ed25519 签名，
+// 索引中的每一项带有内容的 SHA-256，下载的内容必须与之一致。
+// 校验通过的索引和内容缓存在本地，离线时使用缓存。这里只下载和校验数据，不执行任何内容。
+package gallery
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ItemKind 条目类型
+type ItemKind string
+
+const (
+	KindTemplate ItemKind = "template" // 文档模板，纯文本
+	KindTheme    ItemKind = "theme"    // 主题，Base16 YAML 或 VS Code 主题 JSON
+)
+
+const (
+	// MaxIndexSize 索引大小上限
+	MaxIndexSize = 1 << 20
+	// MaxItemSize 单个条目大小上限
+	MaxItemSize = 4 << 20
+)
+
+// idPattern 条目 ID，同时用作本地文件名
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+// ErrSignature 签名校验失败
+var ErrSignature = errors.New("gallery index signature is invalid")
+
+// Item 索引中的条目
+type Item struct {
+	ID          string   `json:"id"`
+	Kind        ItemKind `json:"kind"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	URL         string   `json:"url"`    // 内容地址，相对地址基于索引地址解析
+	SHA256      string   `json:"sha256"` // 内容的十六进制 SHA-256
+	Size        int64    `json:"size"`
+}
+
+// Index 模板和主题索引
+type Index struct {
+	Version int    `json:"version"`
+	Updated string `json:"updated"`
+	Items   []Item `json:"items"`
+}
+
+// Find 按 ID 查找条目
+func (idx *Index) Find(id string) (*Item, bool) {
+	for i := range idx.Items {
+		if idx.Items[i].ID == id {
+			return &idx.Items[i], true
+		}
+	}
+	return nil, false
+}
+
+// ValidID 条目 ID 是否合法，只能包含小写字母、数字和 ._-，可安全用作文件名
+func ValidID(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+// ParsePublicKey 解析 Base64 或十六进制编码的 Ed25519 公钥
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	s = strings.TrimSpace(s)
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		key, err = hex.DecodeString(s)
+	}
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid gallery public key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// VerifyIndex 校验签名并解析索引
+func VerifyIndex(data, signature []byte, key ed25519.PublicKey) (*Index, error) {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil || !ed25519.Verify(key, data, sig) {
+		return nil, ErrSignature
+	}
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("invalid gallery index: %w", err)
+	}
+	for _, item := range idx.Items {
+		if !ValidID(item.ID) {
+			return nil, fmt.Errorf("invalid gallery item id: %q", item.ID)
+		}
+		if item.Kind != KindTemplate && item.Kind != KindTheme {
+			return nil, fmt.Errorf("unsupported gallery item kind: %s", item.Kind)
+		}
+		if _, err := hex.DecodeString(item.SHA256); err != nil || len(item.SHA256) != sha256.Size*2 {
+			return nil, fmt.Errorf("invalid checksum for gallery item %s", item.ID)
+		}
+	}
+	return &idx, nil
+}
+
+// VerifyItem 校验内容与索引中的 SHA-256 一致
+func VerifyItem(item *Item, data []byte) error {
+	sum := sha256.Sum256(data)
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), item.SHA256) {
+		return fmt.Errorf("checksum mismatch for gallery item %s", item.ID)
+	}
+	return nil
+}
+
+// Client 索引和内容的下载客户端，带本地缓存
+type Client struct {
+	HTTP     *http.Client
+	CacheDir string
+}
+
+// FetchIndex 下载并校验索引，成功后写入缓存；网络不可用时回退到缓存（同样重新校验）
+// 返回的 cached 表示结果来自缓存
+func (c *Client) FetchIndex(ctx context.Context, indexURL string, key ed25519.PublicKey) (*Index, bool, error) {
+	data, sig, err := c.download(ctx, indexURL)
+	if err == nil {
+		idx, verr := VerifyIndex(data, sig, key)
+		if verr != nil {
+			return nil, false, verr
+		}
+		c.writeCache("index.json", data)
+		c.writeCache("index.json.sig", sig)
+		return idx, false, nil
+	}
+
+	idx, cacheErr := c.CachedIndex(key)
+	if cacheErr != nil {
+		return nil, false, err
+	}
+	return idx, true, nil
+}
+
+// CachedIndex 读取并校验缓存的索引
+func (c *Client) CachedIndex(key ed25519.PublicKey) (*Index, error) {
+	data, err := os.ReadFile(filepath.Join(c.CacheDir, "index.json"))
+	if err != nil {
+		return nil, err
+	}
+	sig, err := os.ReadFile(filepath.Join(c.CacheDir, "index.json.sig"))
+	if err != nil {
+		return nil, err
+	}
+	return VerifyIndex(data, sig, key)
+}
+
+// FetchItem 获取条目内容，缓存中有校验一致的内容时不访问网络
+func (c *Client) FetchItem(ctx context.Context, indexURL string, item *Item) ([]byte, error) {
+	cacheName := filepath.Join("items", strings.ToLower(item.SHA256))
+	if data, err := os.ReadFile(filepath.Join(c.CacheDir, cacheName)); err == nil && VerifyItem(item, data) == nil {
+		return data, nil
+	}
+
+	itemURL, err := resolveURL(indexURL, item.URL)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.get(ctx, itemURL, MaxItemSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyItem(item, data); err != nil {
+		return nil, err
+	}
+	c.writeCache(cacheName, data)
+	return data, nil
+}
+
+// download 下载索引和签名
+func (c *Client) download(ctx context.Context, indexURL string) ([]byte, []byte, error) {
+	if err := requireHTTPS(indexURL); err != nil {
+		return nil, nil, err
+	}
+	data, err := c.get(ctx, indexURL, MaxIndexSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	sig, err := c.get(ctx, indexURL+".sig", 1024)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, sig, nil
+}
+
+// get 下载 HTTPS 资源，超过 limit 字节时报错
+func (c *Client) get(ctx context.Context, rawURL string, limit int64) ([]byte, error) {
+	if err := requireHTTPS(rawURL); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d for %s", resp.StatusCode, rawURL)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("response too large: %s", rawURL)
+	}
+	return data, nil
+}
+
+// writeCache 写入缓存，失败时忽略，缓存只用于离线回退
+func (c *Client) writeCache(name string, data []byte) {
+	if c.CacheDir == "" {
+		return
+	}
+	path := filepath.Join(c.CacheDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+	}
+}
+
+// resolveURL 解析条目地址，相对地址基于索引地址
+func resolveURL(indexURL, itemURL string) (string, error) {
+	base, err := url.Parse(indexURL)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(itemURL)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// requireHTTPS 只允许 HTTPS 地址
+func requireHTTPS(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid gallery url: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("gallery url must use https: %s", rawURL)
+	}
+	return nil
+}
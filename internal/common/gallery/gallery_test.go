@@ -0,0 +1,105 @@
+package gallery
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newGalleryServer 返回签名后的索引和一个模板条目
+func newGalleryServer(t *testing.T, tamper bool) (*httptest.Server, ed25519.PublicKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := []byte("# Meeting notes\n\n## Attendees\n")
+	sum := sha256.Sum256(content)
+	index := []byte(fmt.Sprintf(`{"version":1,"items":[{"id":"meeting-notes","kind":"template","name":"Meeting notes","url":"items/meeting.md","sha256":"%s"}]}`, hex.EncodeToString(sum[:])))
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, index))
+	if tamper {
+		content = []byte("tampered")
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.json", func(w http.ResponseWriter, r *http.Request) { w.Write(index) })
+	mux.HandleFunc("/index.json.sig", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(sig)) })
+	mux.HandleFunc("/items/meeting.md", func(w http.ResponseWriter, r *http.Request) { w.Write(content) })
+	server := httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+	return server, pub
+}
+
+func TestFetchIndexAndItem(t *testing.T) {
+	server, key := newGalleryServer(t, false)
+	client := &Client{HTTP: server.Client(), CacheDir: t.TempDir()}
+	ctx := context.Background()
+
+	idx, cached, err := client.FetchIndex(ctx, server.URL+"/index.json", key)
+	if err != nil || cached {
+		t.Fatalf("FetchIndex: %v cached=%v", err, cached)
+	}
+	item, ok := idx.Find("meeting-notes")
+	if !ok {
+		t.Fatal("item not found")
+	}
+	data, err := client.FetchItem(ctx, server.URL+"/index.json", item)
+	if err != nil || string(data) != "# Meeting notes\n\n## Attendees\n" {
+		t.Fatalf("FetchItem = %q, %v", data, err)
+	}
+
+	// 离线时使用缓存
+	server.Close()
+	idx, cached, err = client.FetchIndex(ctx, server.URL+"/index.json", key)
+	if err != nil || !cached || len(idx.Items) != 1 {
+		t.Fatalf("offline FetchIndex: %v cached=%v", err, cached)
+	}
+	if _, err := client.FetchItem(ctx, server.URL+"/index.json", item); err != nil {
+		t.Fatalf("offline FetchItem: %v", err)
+	}
+}
+
+func TestFetchRejectsBadSignatureAndContent(t *testing.T) {
+	server, key := newGalleryServer(t, true)
+	client := &Client{HTTP: server.Client(), CacheDir: t.TempDir()}
+	ctx := context.Background()
+
+	otherKey, _, _ := ed25519.GenerateKey(nil)
+	if _, _, err := client.FetchIndex(ctx, server.URL+"/index.json", otherKey); !errors.Is(err, ErrSignature) {
+		t.Errorf("wrong key err = %v", err)
+	}
+
+	idx, _, err := client.FetchIndex(ctx, server.URL+"/index.json", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.FetchItem(ctx, server.URL+"/index.json", &idx.Items[0]); err == nil {
+		t.Error("tampered content accepted")
+	}
+}
+
+func TestRequireHTTPS(t *testing.T) {
+	client := &Client{}
+	if _, _, err := client.FetchIndex(context.Background(), "http://example.com/index.json", nil); err == nil {
+		t.Error("plain http accepted")
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	for _, encoded := range []string{base64.StdEncoding.EncodeToString(pub), hex.EncodeToString(pub)} {
+		if key, err := ParsePublicKey(encoded); err != nil || !key.Equal(pub) {
+			t.Errorf("ParsePublicKey(%q) = %v", encoded, err)
+		}
+	}
+	if _, err := ParsePublicKey("short"); err == nil {
+		t.Error("invalid key accepted")
+	}
+}
@@ -0,0 +1,15 @@
+package helper
+
+import (
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// EmitEvent 向前端发送自定义事件
+// 应用尚未初始化（如单元测试中）时直接忽略
+func EmitEvent(name string, data ...any) {
+	app := application.Get()
+	if app == nil || app.Event == nil {
+		return
+	}
+	app.Event.Emit(name, data...)
+}
@@ -123,19 +123,19 @@ func (wh *WindowHelper) AutoShowMainWindow() {
 	}
 }
 
-// GetDocumentWindow 根据文档ID获取窗口
+// GetDocumentWindow 根据窗口ID获取文档窗口
 // 参数:
 //
-//	documentID - 文档的唯一标识符
+//	windowID - WindowService 分配的窗口ID，一个文档窗口可包含多个文档标签
 //
 // 返回值:
 //
 //	application.Window - 找到的窗口对象
 //	bool - 是否成功找到窗口
-func (wh *WindowHelper) GetDocumentWindow(documentID int64) (application.Window, bool) {
+func (wh *WindowHelper) GetDocumentWindow(windowID int64) (application.Window, bool) {
 	app := application.Get()
-	// 将文档ID转换为窗口名称
-	windowName := strconv.FormatInt(documentID, 10)
+	// 将窗口ID转换为窗口名称
+	windowName := strconv.FormatInt(windowID, 10)
 	return app.Window.GetByName(windowName)
 }
 
@@ -163,17 +163,17 @@ func (wh *WindowHelper) GetAllDocumentWindows() []application.Window {
 	return docWindows
 }
 
-// FocusDocumentWindow 聚焦指定文档的窗口
+// FocusDocumentWindow 聚焦指定的文档窗口
 // 参数:
 //
-//	documentID - 需要聚焦的文档ID
+//	windowID - 需要聚焦的窗口ID
 //
 // 返回值:
 //
 //	bool - 如果成功聚焦窗口则返回true，否则返回false
-func (wh *WindowHelper) FocusDocumentWindow(documentID int64) bool {
-	// 获取指定文档的窗口，如果存在则显示、恢复并聚焦该窗口
-	if window, exists := wh.GetDocumentWindow(documentID); exists {
+func (wh *WindowHelper) FocusDocumentWindow(windowID int64) bool {
+	// 获取指定的文档窗口，如果存在则显示、恢复并聚焦该窗口
+	if window, exists := wh.GetDocumentWindow(windowID); exists {
 		window.Show()
 		window.Restore()
 		window.Focus()
@@ -182,17 +182,17 @@ func (wh *WindowHelper) FocusDocumentWindow(documentID int64) bool {
 	return false
 }
 
-// CloseDocumentWindow 关闭指定文档的窗口
+// CloseDocumentWindow 关闭指定的文档窗口
 // 参数:
 //
-//	documentID - 要关闭窗口的文档ID
+//	windowID - 要关闭的窗口ID
 //
 // 返回值:
 //
 //	bool - 关闭成功返回true，否则返回false
-func (wh *WindowHelper) CloseDocumentWindow(documentID int64) bool {
-	// 获取文档对应的窗口，如果存在则关闭该窗口
-	if window, exists := wh.GetDocumentWindow(documentID); exists {
+func (wh *WindowHelper) CloseDocumentWindow(windowID int64) bool {
+	// 获取指定的文档窗口，如果存在则关闭该窗口
+	if window, exists := wh.GetDocumentWindow(windowID); exists {
 		window.Close()
 		return true
 	}
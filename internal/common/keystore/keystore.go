@@ -0,0 +1,43 @@
+package keystore
+
+import (
+	"errors"
+	"voidraft/internal/common/constant"
+
+	"github.com/zalando/go-keyring"
+)
+
+// ErrNotFound 密钥不存在
+var ErrNotFound = errors.New("secret not found in keyring")
+
+// Get 从系统钥匙串读取密钥
+func Get(key string) (string, error) {
+	value, err := keyring.Get(constant.VOIDRAFT_APP_NAME, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	}
+	return value, err
+}
+
+// Set 将密钥写入系统钥匙串
+func Set(key, value string) error {
+	return keyring.Set(constant.VOIDRAFT_APP_NAME, key, value)
+}
+
+// Delete 从系统钥匙串删除密钥，不存在时不报错
+func Delete(key string) error {
+	err := keyring.Delete(constant.VOIDRAFT_APP_NAME, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// Exists 检查密钥是否存在
+func Exists(key string) (bool, error) {
+	_, err := Get(key)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,24 @@
+package keystore
+
+import "testing"
+
+func TestHashAndVerifyPassphrase(t *testing.T) {
+	hash, err := HashPassphrase("correct horse")
+	if err != nil {
+		t.Fatalf("HashPassphrase failed: %v", err)
+	}
+
+	ok, err := VerifyPassphrase("correct horse", hash)
+	if err != nil || !ok {
+		t.Errorf("expected passphrase to verify, ok=%v err=%v", ok, err)
+	}
+
+	ok, err = VerifyPassphrase("wrong horse", hash)
+	if err != nil || ok {
+		t.Errorf("expected wrong passphrase to fail, ok=%v err=%v", ok, err)
+	}
+
+	if _, err := VerifyPassphrase("x", "plain-text"); err != ErrInvalidHash {
+		t.Errorf("expected ErrInvalidHash, got %v", err)
+	}
+}
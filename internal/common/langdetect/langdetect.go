@@ -0,0 +1,174 @@
+// Package langdetect 识别文本所用的自然语言
+// 先按文字系统区分，拉丁字母的文本再按常用词判断；文本过短或无法确定时返回空
+package langdetect
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minScriptLetters 按文字系统判断所需的最少字数
+const minScriptLetters = 4
+
+// minLatinHits 拉丁字母的文本按常用词判断时，至少出现的常用词数
+const minLatinHits = 2
+
+// script 文字系统
+type script int
+
+const (
+	scriptOther script = iota
+	scriptLatin
+	scriptHan
+	scriptKana
+	scriptHangul
+	scriptCyrillic
+	scriptArabic
+	scriptHebrew
+	scriptGreek
+	scriptThai
+	scriptDevanagari
+)
+
+// scriptLanguages 只对应一种语言的文字系统
+var scriptLanguages = map[script]string{
+	scriptHan:        "zh",
+	scriptHangul:     "ko",
+	scriptHebrew:     "he",
+	scriptGreek:      "el",
+	scriptThai:       "th",
+	scriptDevanagari: "hi",
+}
+
+// stopwords 拉丁字母语言的常用词
+var stopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "was", "were", "of", "to", "in", "that", "it", "for", "with", "on", "this", "be", "have", "has", "not", "you", "they", "at", "from", "by", "but", "or", "which", "will", "would", "can"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "zu", "den", "von", "mit", "sich", "des", "auf", "für", "im", "dem", "auch", "es", "werden", "aus", "er", "hat", "dass", "sie", "nach", "wird", "bei", "oder", "wir", "ich"},
+	"fr": {"le", "la", "les", "et", "est", "des", "une", "un", "du", "en", "que", "qui", "dans", "pour", "pas", "sur", "ce", "il", "elle", "au", "avec", "sont", "ne", "se", "plus", "par", "nous", "vous", "mais", "ou", "cette"},
+	"es": {"el", "la", "los", "las", "y", "es", "de", "que", "en", "un", "una", "por", "con", "para", "no", "se", "del", "al", "lo", "como", "más", "pero", "sus", "ya", "este", "está", "son", "muy", "también"},
+	"it": {"il", "lo", "la", "gli", "le", "di", "che", "e", "è", "un", "una", "per", "non", "con", "sono", "del", "della", "in", "si", "da", "al", "ma", "come", "più", "anche", "questo", "ha", "ci", "nel", "alla"},
+	"pt": {"o", "a", "os", "as", "de", "que", "e", "é", "um", "uma", "para", "com", "não", "em", "do", "da", "dos", "das", "por", "se", "mais", "mas", "como", "foi", "ao", "ele", "ela", "são", "está", "também", "você"},
+	"nl": {"de", "het", "een", "en", "is", "van", "dat", "die", "niet", "in", "op", "te", "zijn", "met", "voor", "er", "maar", "ook", "als", "bij", "aan", "wordt", "naar", "hij", "ze", "wij", "ik", "je", "dit", "was", "heeft"},
+}
+
+// stopwordIndex 常用词 -> 使用该词的语言
+var stopwordIndex = func() map[string][]string {
+	index := make(map[string][]string)
+	for lang, words := range stopwords {
+		for _, word := range words {
+			index[word] = append(index[word], lang)
+		}
+	}
+	return index
+}()
+
+// Detect 识别文本的语言，返回 ISO 639-1 代码，如 en、zh、ja；无法确定时返回空
+func Detect(text string) string {
+	counts := make(map[script]int)
+	ukrainian, persian := false, false
+	for _, r := range text {
+		s := classify(r)
+		if s == scriptOther {
+			continue
+		}
+		counts[s]++
+		switch r {
+		case 'і', 'ї', 'є', 'ґ', 'І', 'Ї', 'Є', 'Ґ':
+			ukrainian = true
+		case 'پ', 'چ', 'ژ', 'گ':
+			persian = true
+		}
+	}
+
+	// 日文混用汉字和假名，出现一定比例的假名即视为日文
+	if kana := counts[scriptKana]; kana >= minScriptLetters && kana*10 >= counts[scriptHan] {
+		return "ja"
+	}
+
+	dominant, best := scriptOther, 0
+	for s, n := range counts {
+		if s != scriptKana && (n > best || (n == best && s < dominant)) {
+			dominant, best = s, n
+		}
+	}
+
+	switch dominant {
+	case scriptOther:
+		return ""
+	case scriptLatin:
+		return detectLatin(text)
+	}
+	if best < minScriptLetters {
+		return ""
+	}
+	switch dominant {
+	case scriptCyrillic:
+		if ukrainian {
+			return "uk"
+		}
+		return "ru"
+	case scriptArabic:
+		if persian {
+			return "fa"
+		}
+		return "ar"
+	}
+	return scriptLanguages[dominant]
+}
+
+// detectLatin 按常用词出现的次数判断拉丁字母文本的语言，最高的语言须明显领先
+func detectLatin(text string) string {
+	scores := make(map[string]int)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	for _, word := range words {
+		for _, lang := range stopwordIndex[word] {
+			scores[lang]++
+		}
+	}
+
+	best, first, second := "", 0, 0
+	for lang, score := range scores {
+		switch {
+		case score > first || (score == first && lang < best):
+			second = max(second, first)
+			best, first = lang, score
+		case score > second:
+			second = score
+		}
+	}
+	if first < minLatinHits || first == second {
+		return ""
+	}
+	return best
+}
+
+// classify 字符所属的文字系统，数字、符号和空白为 scriptOther
+func classify(r rune) script {
+	switch {
+	case r < 'A':
+		return scriptOther
+	case unicode.Is(unicode.Latin, r):
+		return scriptLatin
+	case unicode.Is(unicode.Han, r):
+		return scriptHan
+	case unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r):
+		return scriptKana
+	case unicode.Is(unicode.Hangul, r):
+		return scriptHangul
+	case unicode.Is(unicode.Cyrillic, r):
+		return scriptCyrillic
+	case unicode.Is(unicode.Arabic, r):
+		return scriptArabic
+	case unicode.Is(unicode.Hebrew, r):
+		return scriptHebrew
+	case unicode.Is(unicode.Greek, r):
+		return scriptGreek
+	case unicode.Is(unicode.Thai, r):
+		return scriptThai
+	case unicode.Is(unicode.Devanagari, r):
+		return scriptDevanagari
+	}
+	return scriptOther
+}
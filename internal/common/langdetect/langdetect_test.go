@@ -0,0 +1,32 @@
+package langdetect
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"The meeting was moved to Friday because the room is not available.", "en"},
+		{"Die Besprechung wurde auf Freitag verschoben, weil der Raum nicht frei ist.", "de"},
+		{"La réunion est reportée à vendredi car la salle n'est pas disponible.", "fr"},
+		{"La reunión se movió al viernes porque la sala no está disponible.", "es"},
+		{"La riunione è stata spostata a venerdì perché la sala non è disponibile.", "it"},
+		{"A reunião foi adiada para sexta porque a sala não está disponível.", "pt"},
+		{"De vergadering is verplaatst naar vrijdag omdat de zaal niet vrij is.", "nl"},
+		{"会议改到周五，因为会议室不可用。", "zh"},
+		{"会議は会議室が使えないため金曜日に変更されました。", "ja"},
+		{"회의실을 사용할 수 없어서 회의가 금요일로 변경되었습니다.", "ko"},
+		{"Встреча перенесена на пятницу, потому что зал занят.", "ru"},
+		{"Зустріч перенесено на п'ятницю, бо зала зайнята.", "uk"},
+		{"Η συνάντηση μεταφέρθηκε την Παρασκευή.", "el"},
+		{"hello world", ""},
+		{"12345 + 678 = ?", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Detect(tt.text); got != tt.want {
+			t.Errorf("Detect(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
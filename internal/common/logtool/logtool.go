@@ -0,0 +1,205 @@
+// Package logtool 流式处理日志：统一时间戳的时区和格式，按级别、正则和时间范围过滤
+// 没有时间戳和级别的缩进行（如堆栈）视为上一条记录的延续，随记录一起保留或丢弃
+package logtool
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultLayout 统一后的时间格式
+const DefaultLayout = "2006-01-02 15:04:05.000 -07:00"
+
+// maxLineBytes 单行长度上限
+const maxLineBytes = 4 << 20
+
+// Level 归一化后的日志级别
+type Level string
+
+const (
+	LevelTrace Level = "trace"
+	LevelDebug Level = "debug"
+	LevelInfo  Level = "info"
+	LevelWarn  Level = "warn"
+	LevelError Level = "error"
+	LevelFatal Level = "fatal"
+)
+
+var (
+	// 结构化日志中的 level=info、"level":"info"
+	levelFieldPattern = regexp.MustCompile(`(?i)\b(?:level|lvl|severity)"?\s*[=:]\s*"?([a-z]+)`)
+	// 大写的级别关键字
+	levelWordPattern = regexp.MustCompile(`\b(TRACE|DEBUG|INFO|NOTICE|WARN|WARNING|ERROR|ERR|FATAL|CRITICAL|CRIT|PANIC)\b`)
+	// 方括号或尖括号中的级别，如 [info]、<warn>
+	levelBracketPattern = regexp.MustCompile(`(?i)[\[<](trace|debug|info|notice|warn|warning|error|err|fatal|critical|crit|panic)[\]>]`)
+)
+
+// Options 处理选项
+type Options struct {
+	SourceZone *time.Location // 时间戳没有时区时使用的时区，为空表示本地时区
+	TargetZone *time.Location // 统一换算到的时区，为空时不改写时间戳
+	Layout     string         // 改写时间戳使用的格式，为空时使用 DefaultLayout
+	Year       int            // 没有年份的时间戳（syslog）使用的年份，为 0 时使用当前年份
+
+	Levels  []Level        // 保留的级别，为空时不按级别过滤
+	Include *regexp.Regexp // 记录中任一行匹配时保留
+	Exclude *regexp.Regexp // 记录中任一行匹配时丢弃
+	Since   time.Time      // 时间范围起点（含），为零值时不限制
+	Until   time.Time      // 时间范围终点（不含），为零值时不限制
+}
+
+// Stats 处理统计
+type Stats struct {
+	Records    int `json:"records"`    // 记录总数
+	Kept       int `json:"kept"`       // 保留的记录数
+	Lines      int `json:"lines"`      // 输出的行数
+	Normalized int `json:"normalized"` // 改写的时间戳数量
+}
+
+// record 一条日志记录：首行和延续行
+type record struct {
+	lines []string
+	time  time.Time
+	level Level
+}
+
+// Process 逐行处理日志并写入 w
+func Process(r io.Reader, w io.Writer, opts Options) (Stats, error) {
+	if opts.SourceZone == nil {
+		opts.SourceZone = time.Local
+	}
+	if opts.Layout == "" {
+		opts.Layout = DefaultLayout
+	}
+	if opts.Year == 0 {
+		opts.Year = time.Now().Year()
+	}
+
+	var stats Stats
+	out := bufio.NewWriter(w)
+	var current *record
+	flush := func() error {
+		if current == nil {
+			return nil
+		}
+		stats.Records++
+		if opts.keep(current) {
+			stats.Kept++
+			for _, line := range current.lines {
+				if _, err := out.WriteString(line + "\n"); err != nil {
+					return err
+				}
+				stats.Lines++
+			}
+		}
+		current = nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineBytes)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		t, start, end, hasTime := findTimestamp(line, opts.SourceZone, opts.Year)
+		level := DetectLevel(line)
+
+		indented := strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+		if current != nil && !hasTime && (level == "" || indented) {
+			current.lines = append(current.lines, line)
+			continue
+		}
+		if err := flush(); err != nil {
+			return stats, err
+		}
+
+		if hasTime && opts.TargetZone != nil {
+			line = line[:start] + t.In(opts.TargetZone).Format(opts.Layout) + line[end:]
+			stats.Normalized++
+		}
+		current = &record{lines: []string{line}, time: t, level: level}
+	}
+	if err := scanner.Err(); err != nil {
+		return stats, err
+	}
+	if err := flush(); err != nil {
+		return stats, err
+	}
+	return stats, out.Flush()
+}
+
+// keep 记录是否满足过滤条件
+func (opts *Options) keep(rec *record) bool {
+	if len(opts.Levels) > 0 {
+		matched := false
+		for _, level := range opts.Levels {
+			if rec.level == level {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if !opts.Since.IsZero() || !opts.Until.IsZero() {
+		if rec.time.IsZero() {
+			return false
+		}
+		if !opts.Since.IsZero() && rec.time.Before(opts.Since) {
+			return false
+		}
+		if !opts.Until.IsZero() && !rec.time.Before(opts.Until) {
+			return false
+		}
+	}
+	if opts.Include != nil && !anyLineMatches(rec.lines, opts.Include) {
+		return false
+	}
+	if opts.Exclude != nil && anyLineMatches(rec.lines, opts.Exclude) {
+		return false
+	}
+	return true
+}
+
+func anyLineMatches(lines []string, pattern *regexp.Regexp) bool {
+	for _, line := range lines {
+		if pattern.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectLevel 识别行中的日志级别，未识别时返回空
+func DetectLevel(line string) Level {
+	for _, pattern := range []*regexp.Regexp{levelFieldPattern, levelWordPattern, levelBracketPattern} {
+		if m := pattern.FindStringSubmatch(line); m != nil {
+			if level := NormalizeLevel(m[1]); level != "" {
+				return level
+			}
+		}
+	}
+	return ""
+}
+
+// NormalizeLevel 将各种写法的级别归一化，未知级别返回空
+func NormalizeLevel(name string) Level {
+	switch strings.ToLower(name) {
+	case "trace", "trc":
+		return LevelTrace
+	case "debug", "dbg":
+		return LevelDebug
+	case "info", "inf", "notice":
+		return LevelInfo
+	case "warn", "warning", "wrn":
+		return LevelWarn
+	case "error", "err", "eror":
+		return LevelError
+	case "fatal", "crit", "critical", "panic", "ftl":
+		return LevelFatal
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package logtool
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sample = `2024-03-01T10:00:00Z INFO server started
+2024-03-01 18:00:05,250 +0800 [warn] disk almost full
+Mar  1 10:00:07 host app[42]: ERROR request failed
+	at com.example.Handler.handle(Handler.java:10)
+	at com.example.Server.run(Server.java:20)
+level=debug ts=1709287210 msg="cache miss"
+1709287215 FATAL shutting down
+`
+
+func process(t *testing.T, opts Options) (string, Stats) {
+	t.Helper()
+	var out strings.Builder
+	stats, err := Process(strings.NewReader(sample), &out, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out.String(), stats
+}
+
+func TestNormalizeTimestamps(t *testing.T) {
+	got, stats := process(t, Options{SourceZone: time.UTC, TargetZone: time.UTC, Year: 2024})
+	want := `2024-03-01 10:00:00.000 +00:00 INFO server started
+2024-03-01 10:00:05.250 +00:00 [warn] disk almost full
+2024-03-01 10:00:07.000 +00:00 host app[42]: ERROR request failed
+	at com.example.Handler.handle(Handler.java:10)
+	at com.example.Server.run(Server.java:20)
+level=debug ts=1709287210 msg="cache miss"
+2024-03-01 10:00:15.000 +00:00 FATAL shutting down
+`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if stats.Records != 5 || stats.Normalized != 4 || stats.Lines != 7 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestFilterLevels(t *testing.T) {
+	got, stats := process(t, Options{Levels: []Level{LevelError, LevelFatal}})
+	if !strings.HasPrefix(got, "Mar  1 10:00:07 host app[42]: ERROR request failed\n\tat com.example.Handler") || !strings.HasSuffix(got, "FATAL shutting down\n") {
+		t.Errorf("got\n%s", got)
+	}
+	if stats.Kept != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestFilterRegexAndRange(t *testing.T) {
+	got, _ := process(t, Options{Include: regexp.MustCompile(`Server\.java`)})
+	if strings.Count(got, "\n") != 3 {
+		t.Errorf("include kept continuation record:\n%s", got)
+	}
+
+	got, _ = process(t, Options{
+		SourceZone: time.UTC,
+		Year:       2024,
+		Since:      time.Date(2024, 3, 1, 10, 0, 5, 0, time.UTC),
+		Until:      time.Date(2024, 3, 1, 10, 0, 10, 0, time.UTC),
+		Exclude:    regexp.MustCompile(`disk`),
+	})
+	if !strings.HasPrefix(got, "Mar  1 10:00:07") || strings.Count(got, "\n") != 3 {
+		t.Errorf("range filter:\n%s", got)
+	}
+}
+
+func TestDetectLevel(t *testing.T) {
+	cases := map[string]Level{
+		`{"level":"warning","msg":"x"}`:     LevelWarn,
+		"2024-01-01 [ERR] boom":             LevelError,
+		"I0101 nothing to see":              "",
+		"user reported an error in billing": "",
+		"<crit> kernel":                     LevelFatal,
+	}
+	for line, want := range cases {
+		if got := DetectLevel(line); got != want {
+			t.Errorf("DetectLevel(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
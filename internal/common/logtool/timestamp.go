@@ -0,0 +1,100 @@
+package logtool
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timestampScanLimit 只在行首附近查找时间戳，避免把消息中的时间当作记录时间
+const timestampScanLimit = 64
+
+// timestampFormat 一种日志时间格式
+type timestampFormat struct {
+	pattern *regexp.Regexp
+	parse   func(s string, loc *time.Location, year int) (time.Time, bool)
+}
+
+var timestampFormats = []timestampFormat{
+	// ISO 8601 / RFC 3339，允许空格分隔和逗号小数，如 2024-01-02T03:04:05.123Z、2024-01-02 03:04:05,123 +0800
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z| ?[+-]\d{2}:?\d{2})?`), parseISO},
+	// Go log 包格式 2024/01/02 03:04:05.000000
+	{regexp.MustCompile(`\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(?:\.\d+)?`), layoutParser("2006/01/02 15:04:05")},
+	// Apache/Nginx 访问日志 02/Jan/2024:03:04:05 +0000
+	{regexp.MustCompile(`\d{2}/[A-Z][a-z]{2}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}`), layoutParser("02/Jan/2006:15:04:05 -0700")},
+	// syslog Jan  2 03:04:05，没有年份
+	{regexp.MustCompile(`[A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}(?:\.\d+)?`), parseSyslog},
+	// 行首的 Unix 秒或毫秒时间戳
+	{regexp.MustCompile(`^(?:\d{10}(?:\.\d+)?|\d{13})\b`), parseEpoch},
+}
+
+// findTimestamp 查找行首附近的时间戳，返回时间和在行中的位置
+func findTimestamp(line string, loc *time.Location, year int) (time.Time, int, int, bool) {
+	head := line
+	if len(head) > timestampScanLimit {
+		head = head[:timestampScanLimit]
+	}
+	for _, format := range timestampFormats {
+		m := format.pattern.FindStringIndex(head)
+		if m == nil {
+			continue
+		}
+		if t, ok := format.parse(line[m[0]:m[1]], loc, year); ok {
+			return t, m[0], m[1], true
+		}
+	}
+	return time.Time{}, 0, 0, false
+}
+
+// parseISO 解析 ISO 8601 时间，没有时区时使用 loc
+func parseISO(s string, loc *time.Location, _ int) (time.Time, bool) {
+	s = strings.ReplaceAll(strings.Replace(strings.Replace(s, ",", ".", 1), " ", "T", 1), " ", "")
+	layout := "2006-01-02T15:04:05"
+	if len(s) > len(layout) {
+		switch tail := s[len(layout):]; {
+		case strings.HasSuffix(tail, "Z") || strings.Contains(tail, ":"):
+			layout += "Z07:00"
+		case strings.ContainsAny(tail, "+-"):
+			layout += "-0700"
+		}
+	}
+	t, err := time.ParseInLocation(layout, s, loc)
+	return t, err == nil
+}
+
+// layoutParser 按固定格式解析，秒后的小数部分由 time.Parse 自动接受
+func layoutParser(layout string) func(string, *time.Location, int) (time.Time, bool) {
+	return func(s string, loc *time.Location, _ int) (time.Time, bool) {
+		t, err := time.ParseInLocation(layout, s, loc)
+		return t, err == nil
+	}
+}
+
+// parseSyslog 解析没有年份的 syslog 时间
+func parseSyslog(s string, loc *time.Location, year int) (time.Time, bool) {
+	t, err := time.ParseInLocation("Jan _2 15:04:05", s, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t.AddDate(year-t.Year(), 0, 0), true
+}
+
+// parseEpoch 解析 Unix 秒（可带小数）或毫秒
+func parseEpoch(s string, _ *time.Location, _ int) (time.Time, bool) {
+	if len(s) == 13 {
+		ms, err := strconv.ParseInt(s, 10, 64)
+		return time.UnixMilli(ms), err == nil
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	sec, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var nsec int64
+	if frac != "" {
+		frac = (frac + "000000000")[:9]
+		nsec, _ = strconv.ParseInt(frac, 10, 64)
+	}
+	return time.Unix(sec, nsec), true
+}
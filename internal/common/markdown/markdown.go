@@ -0,0 +1,226 @@
+// Package markdown 将 Markdown 和文档块渲染为经过清理的 HTML
+// 应用内预览、HTML/打印导出和分享页面都使用这里的渲染结果，保证各处显示一致
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/langdetect"
+	"voidraft/internal/common/table"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+)
+
+const (
+	// StyleLight 浅色代码高亮样式
+	StyleLight = "github"
+	// StyleDark 深色代码高亮样式
+	StyleDark = "github-dark"
+)
+
+// baseCSS 块和纯文本的基础样式，代码高亮样式另外生成
+const baseCSS = `.voidraft-markdown .block{margin:0 0 1em}
+.voidraft-markdown .prose{white-space:pre-wrap;word-wrap:break-word}
+.voidraft-markdown pre{overflow-x:auto;padding:12px 16px;border-radius:6px}
+.voidraft-markdown table{border-collapse:collapse}
+.voidraft-markdown th,.voidraft-markdown td{border:1px solid rgba(128,128,128,.4);padding:4px 8px}
+.voidraft-markdown img{max-width:100%}
+`
+
+// 代码块和高亮使用 class 标记，样式由 CSS 提供
+var classPattern = regexp.MustCompile(`^[\w\- ]+$`)
+
+// policy 清理渲染结果，去除脚本、事件属性和危险链接
+var policy = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(classPattern).OnElements("section", "div", "p", "pre", "code", "span")
+	p.AllowAttrs("id").Matching(bluemonday.SpaceSeparatedTokens).OnElements("li", "sup", "h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("type", "checked", "disabled").OnElements("input")
+	p.AllowElements("section", "input")
+	return p
+}()
+
+// Result 渲染结果
+type Result struct {
+	HTML string `json:"html"` // 清理后的 HTML 片段，外层为 .voidraft-markdown 容器
+	CSS  string `json:"css"`  // 渲染结果所需的样式
+}
+
+// newRenderer 创建使用指定高亮样式的 Markdown 渲染器
+func newRenderer(style string) goldmark.Markdown {
+	return goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.Footnote,
+			highlighting.NewHighlighting(
+				highlighting.WithStyle(style),
+				highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+			),
+		),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+}
+
+// normalizeStyle 未知样式回退为浅色样式
+func normalizeStyle(style string) string {
+	if _, ok := styles.Registry[style]; !ok {
+		return StyleLight
+	}
+	return style
+}
+
+// Render 渲染 Markdown 文本
+func Render(source string, style string) (*Result, error) {
+	style = normalizeStyle(style)
+	body, err := renderMarkdown(newRenderer(style), source)
+	if err != nil {
+		return nil, err
+	}
+	return wrap(body, style), nil
+}
+
+// RenderBlocks 渲染文档块：Markdown 块按 Markdown 渲染，CSV/TSV 块渲染为表格，纯文本块保留换行，其余按代码高亮
+func RenderBlocks(list []blocks.Block, style string) (*Result, error) {
+	style = normalizeStyle(style)
+	md := newRenderer(style)
+
+	var buf strings.Builder
+	for _, block := range list {
+		if strings.TrimSpace(block.Content) == "" {
+			continue
+		}
+		var body string
+		switch {
+		case block.Language == "md":
+			rendered, err := renderMarkdown(md, block.Content)
+			if err != nil {
+				return nil, err
+			}
+			body = rendered
+		case block.Language == "csv" || block.Language == "tsv":
+			// 表格块解析失败时按代码显示
+			if parsed, err := table.Parse(block.Content, table.Format(block.Language)); err == nil {
+				body = policy.Sanitize(parsed.HTML())
+				break
+			}
+			rendered, err := renderMarkdown(md, fence(block.Language, block.Content))
+			if err != nil {
+				return nil, err
+			}
+			body = rendered
+		case blocks.IsProse(block.Language):
+			body = `<p class="prose">` + html.EscapeString(strings.TrimRight(block.Content, "\n")) + "</p>"
+		default:
+			rendered, err := renderMarkdown(md, fence(block.Language, block.Content))
+			if err != nil {
+				return nil, err
+			}
+			body = rendered
+		}
+		// 文本块标注识别出的自然语言，便于浏览器选择字体、断词和朗读
+		lang := ""
+		if blocks.IsProse(block.Language) {
+			if detected := langdetect.Detect(block.Content); detected != "" {
+				lang = ` lang="` + detected + `"`
+			}
+		}
+		fmt.Fprintf(&buf, "<section class=\"block block-%s\"%s>%s</section>\n", classPattern.FindString(block.Language), lang, body)
+	}
+	return wrap(buf.String(), style), nil
+}
+
+// Source 将文档块转换为 Markdown 源文本：Markdown 和纯文本块原样保留，其余块包裹为围栏代码块
+func Source(list []blocks.Block) string {
+	var parts []string
+	for _, block := range list {
+		content := strings.TrimRight(block.Content, "\n")
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		if block.Language == "md" || blocks.IsProse(block.Language) {
+			parts = append(parts, content+"\n")
+		} else {
+			parts = append(parts, fence(block.Language, content))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// ChatSource 将文档块转换为聊天软件的消息文本：Markdown 和纯文本块原样保留，其余块包裹为围栏代码块
+// keepLanguage 为 false 时围栏不带语言标记，Slack 会把语言标记当作代码的第一行
+func ChatSource(list []blocks.Block, keepLanguage bool) string {
+	var parts []string
+	for _, block := range list {
+		content := strings.TrimRight(block.Content, "\n")
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		switch {
+		case block.Language == "md" || blocks.IsProse(block.Language):
+			parts = append(parts, content+"\n")
+		case keepLanguage:
+			parts = append(parts, fence(block.Language, content))
+		default:
+			parts = append(parts, fence("", content))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// CSS 生成指定高亮样式的完整样式表
+func CSS(style string) string {
+	var buf bytes.Buffer
+	buf.WriteString(baseCSS)
+	formatter := chromahtml.New(chromahtml.WithClasses(true))
+	if err := formatter.WriteCSS(&buf, styles.Get(normalizeStyle(style))); err != nil {
+		return baseCSS
+	}
+	return buf.String()
+}
+
+// AdaptiveCSS 生成跟随系统配色的样式表，深色模式下使用深色高亮样式
+func AdaptiveCSS() string {
+	return CSS(StyleLight) + "@media (prefers-color-scheme:dark){\n" + CSS(StyleDark) + "}\n"
+}
+
+// renderMarkdown 渲染并清理 Markdown
+func renderMarkdown(md goldmark.Markdown, source string) (string, error) {
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(source), &buf); err != nil {
+		return "", fmt.Errorf("failed to render markdown: %w", err)
+	}
+	return policy.Sanitize(buf.String()), nil
+}
+
+// wrap 包裹容器并附带样式
+func wrap(body string, style string) *Result {
+	return &Result{
+		HTML: `<div class="voidraft-markdown">` + body + "</div>",
+		CSS:  CSS(style),
+	}
+}
+
+// fence 将代码包裹为围栏代码块，围栏长度大于内容中最长的连续反引号
+func fence(language, content string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	marker := strings.Repeat("`", max(3, longest+1))
+	return marker + language + "\n" + strings.TrimRight(content, "\n") + "\n" + marker + "\n"
+}
@@ -0,0 +1,88 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+	"voidraft/internal/common/blocks"
+)
+
+func TestRenderSanitizes(t *testing.T) {
+	result, err := Render("# Title\n\n<script>alert(1)</script>\n\n[x](javascript:alert(1)) <img src=x onerror=alert(1)>\n\n- [x] done\n", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bad := range []string{"<script", "javascript:", "onerror"} {
+		if strings.Contains(result.HTML, bad) {
+			t.Errorf("HTML contains %q: %s", bad, result.HTML)
+		}
+	}
+	if !strings.Contains(result.HTML, `<h1 id="title">Title</h1>`) {
+		t.Errorf("heading missing: %s", result.HTML)
+	}
+	if !strings.Contains(result.HTML, `type="checkbox"`) {
+		t.Errorf("task list missing: %s", result.HTML)
+	}
+	if !strings.Contains(result.CSS, ".chroma") {
+		t.Errorf("CSS missing highlight styles")
+	}
+}
+
+func TestRenderBlocks(t *testing.T) {
+	list := []blocks.Block{
+		{Language: "text", Content: "a < b\nline two\n"},
+		{Language: "md", Content: "**bold**\n"},
+		{Language: "go", Content: "func main() {}\n// ``` inside\n"},
+		{Language: "text", Content: "  \n"},
+		{Language: "csv", Content: "k,v\na,<b>\n"},
+	}
+	result, err := RenderBlocks(list, StyleDark)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(result.HTML, "<section") != 4 {
+		t.Errorf("sections: %s", result.HTML)
+	}
+	if !strings.Contains(result.HTML, `<p class="prose">a &lt; b`) {
+		t.Errorf("prose block: %s", result.HTML)
+	}
+	if !strings.Contains(result.HTML, "<strong>bold</strong>") {
+		t.Errorf("markdown block: %s", result.HTML)
+	}
+	if !strings.Contains(result.HTML, `class="chroma"`) || !strings.Contains(result.HTML, "inside") {
+		t.Errorf("code block: %s", result.HTML)
+	}
+	if !strings.Contains(result.HTML, "<th>k</th>") || !strings.Contains(result.HTML, "<td>&lt;b&gt;</td>") {
+		t.Errorf("table block: %s", result.HTML)
+	}
+}
+
+func TestFence(t *testing.T) {
+	if got := fence("go", "x ```` y"); !strings.HasPrefix(got, "`````go\n") {
+		t.Errorf("fence = %q", got)
+	}
+}
+
+func TestSource(t *testing.T) {
+	list := []blocks.Block{
+		{Language: "md", Content: "# Title\n"},
+		{Language: "text", Content: "  "},
+		{Language: "go", Content: "package main\n"},
+	}
+	want := "# Title\n\n```go\npackage main\n```\n"
+	if got := Source(list); got != want {
+		t.Errorf("Source = %q, want %q", got, want)
+	}
+}
+
+func TestChatSource(t *testing.T) {
+	list := []blocks.Block{
+		{Language: "md", Content: "*bold*\n"},
+		{Language: "go", Content: "package main\n"},
+	}
+	if got, want := ChatSource(list, true), "*bold*\n\n```go\npackage main\n```\n"; got != want {
+		t.Errorf("ChatSource(discord) = %q, want %q", got, want)
+	}
+	if got, want := ChatSource(list, false), "*bold*\n\n```\npackage main\n```\n"; got != want {
+		t.Errorf("ChatSource(slack) = %q, want %q", got, want)
+	}
+}
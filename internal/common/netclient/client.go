@@ -0,0 +1,248 @@
+package netclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultUserAgent 请求和客户端都未指定 User-Agent 时使用
+const DefaultUserAgent = "voidraft"
+
+const (
+	// retryBaseDelay 第一次重试前的等待时间，之后每次加倍
+	retryBaseDelay = 500 * time.Millisecond
+	// retryMaxDelay 重试前最长的等待时间，包括服务器通过 Retry-After 要求的时间
+	retryMaxDelay = 10 * time.Second
+)
+
+// Config 客户端设置
+type Config struct {
+	Service   string        // 服务名，统计和日志按服务和目标主机区分，如 translator.bing
+	Timeout   time.Duration // 一次调用的总时长，包括重试，为 0 时不限时
+	Retries   int           // 网络错误、429 和 502/503/504 时的重试次数，请求体无法重放时不重试
+	UserAgent string        // 请求未设置 User-Agent 时使用，为空时使用 DefaultUserAgent
+}
+
+// Stat 一个服务访问一个目标主机的统计
+type Stat struct {
+	Service    string    `json:"service"`
+	Host       string    `json:"host"`
+	Requests   int64     `json:"requests"`   // 调用次数，重试不重复计数
+	Failures   int64     `json:"failures"`   // 以网络错误或 5xx 结束的调用次数
+	Retries    int64     `json:"retries"`    // 重试次数
+	AverageMs  int64     `json:"averageMs"`  // 平均耗时，包括重试
+	LastStatus int       `json:"lastStatus"` // 最近一次调用的状态码，网络错误时为 0
+	LastError  string    `json:"lastError"`  // 最近一次调用的错误，成功时为空
+	LastAt     time.Time `json:"lastAt"`     // 最近一次调用结束的时间
+}
+
+// Event 一次调用的结果，不含路径和查询参数，以免记录密钥
+type Event struct {
+	Service  string
+	Method   string
+	Host     string
+	Status   int
+	Err      error
+	Duration time.Duration
+	Retries  int
+}
+
+// Failed 调用是否以网络错误或 5xx 结束
+func (e Event) Failed() bool {
+	return e.Err != nil || e.Status >= http.StatusInternalServerError
+}
+
+// statKey 统计的分组
+type statKey struct {
+	service string
+	host    string
+}
+
+var (
+	statsMu sync.Mutex
+	stats   = make(map[statKey]*Stat)
+	elapsed = make(map[statKey]time.Duration)
+
+	observer atomic.Pointer[func(Event)]
+)
+
+// New 使用共用传输层的客户端，按设置重试、补充 User-Agent 并记录统计
+func New(config Config) *http.Client {
+	return &http.Client{Transport: NewTransport(config), Timeout: config.Timeout}
+}
+
+// NewTransport 按设置包装共用传输层，供需要自行创建客户端的调用方使用，Timeout 不生效
+func NewTransport(config Config) http.RoundTripper {
+	if config.Service == "" {
+		config.Service = "default"
+	}
+	if config.UserAgent == "" {
+		config.UserAgent = DefaultUserAgent
+	}
+	return &instrumented{config: config}
+}
+
+// SetObserver 设置每次调用结束时的回调，用于记录日志；回调在请求的协程中执行，应尽快返回
+func SetObserver(fn func(Event)) {
+	if fn == nil {
+		observer.Store(nil)
+		return
+	}
+	observer.Store(&fn)
+}
+
+// Stats 各服务访问各目标主机的统计，按服务和主机排序
+func Stats() []Stat {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	out := make([]Stat, 0, len(stats))
+	for _, stat := range stats {
+		out = append(out, *stat)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Service != out[j].Service {
+			return out[i].Service < out[j].Service
+		}
+		return out[i].Host < out[j].Host
+	})
+	return out
+}
+
+// ResetStats 清空统计
+func ResetStats() {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	stats = make(map[statKey]*Stat)
+	elapsed = make(map[statKey]time.Duration)
+}
+
+// instrumented 在共用传输层之上重试、补充 User-Agent 并记录统计
+type instrumented struct {
+	config Config
+}
+
+func (t *instrumented) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.config.UserAgent)
+	}
+
+	start := time.Now()
+	resp, err := roundTripper{}.RoundTrip(req)
+	retries := 0
+	for retries < t.config.Retries && retryable(resp, err) {
+		next, ok := rewind(req)
+		if !ok {
+			break
+		}
+		delay := retryDelay(resp, retries)
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		retries++
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			resp, err = nil, req.Context().Err()
+		case <-timer.C:
+			req = next
+			resp, err = roundTripper{}.RoundTrip(req)
+			continue
+		}
+		break
+	}
+
+	event := Event{
+		Service:  t.config.Service,
+		Method:   req.Method,
+		Host:     strings.ToLower(req.URL.Hostname()),
+		Err:      err,
+		Duration: time.Since(start),
+		Retries:  retries,
+	}
+	if resp != nil {
+		event.Status = resp.StatusCode
+	}
+	record(event)
+	return resp, err
+}
+
+// retryable 网络错误和表示暂时不可用的状态码可以重试，请求被取消或超时时不重试
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// rewind 为重试准备请求，请求体无法重放时返回 false
+func rewind(req *http.Request) (*http.Request, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	next := req.Clone(req.Context())
+	next.Body = body
+	return next, true
+}
+
+// retryDelay 第 attempt 次重试前的等待时间，服务器给出 Retry-After 秒数时按其等待
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(strings.TrimSpace(resp.Header.Get("Retry-After"))); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, retryMaxDelay)
+		}
+	}
+	return min(retryBaseDelay<<attempt, retryMaxDelay)
+}
+
+// record 更新统计并通知回调
+func record(event Event) {
+	statsMu.Lock()
+	key := statKey{service: event.Service, host: event.Host}
+	stat := stats[key]
+	if stat == nil {
+		stat = &Stat{Service: event.Service, Host: event.Host}
+		stats[key] = stat
+	}
+	stat.Requests++
+	stat.Retries += int64(event.Retries)
+	if event.Failed() {
+		stat.Failures++
+	}
+	elapsed[key] += event.Duration
+	stat.AverageMs = (elapsed[key] / time.Duration(stat.Requests)).Milliseconds()
+	stat.LastStatus = event.Status
+	stat.LastError = ""
+	if event.Err != nil {
+		stat.LastError = event.Err.Error()
+	}
+	stat.LastAt = time.Now()
+	statsMu.Unlock()
+
+	if fn := observer.Load(); fn != nil {
+		(*fn)(event)
+	}
+}
@@ -0,0 +1,71 @@
+package netclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientRetriesAndRecordsStats(t *testing.T) {
+	ResetStats()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" || r.Header.Get("User-Agent") != DefaultUserAgent {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := New(Config{Service: "test", Timeout: 5 * time.Second, Retries: 2})
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	stats := Stats()
+	if len(stats) != 1 {
+		t.Fatalf("stats = %+v, want one entry", stats)
+	}
+	if got := stats[0]; got.Service != "test" || got.Requests != 1 || got.Retries != 1 || got.Failures != 0 || got.LastStatus != http.StatusOK {
+		t.Errorf("stat = %+v", got)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		retryAfter string
+		attempt    int
+		want       time.Duration
+	}{
+		{"", 0, retryBaseDelay},
+		{"", 2, 4 * retryBaseDelay},
+		{"", 10, retryMaxDelay},
+		{"3", 0, 3 * time.Second},
+		{"3600", 0, retryMaxDelay},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{Header: http.Header{}}
+		if tt.retryAfter != "" {
+			resp.Header.Set("Retry-After", tt.retryAfter)
+		}
+		if got := retryDelay(resp, tt.attempt); got != tt.want {
+			t.Errorf("retryDelay(%q, %d) = %v, want %v", tt.retryAfter, tt.attempt, got, tt.want)
+		}
+	}
+}
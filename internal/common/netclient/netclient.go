@@ -0,0 +1,264 @@
+// Package netclient 所有对外 HTTP 请求共用的传输层
+// 遵循代理设置，在系统证书之外信任额外的 CA 证书，并允许指定主机使用固定指纹的证书，
+// 以便在使用 TLS 拦截代理的企业网络中正常访问翻译、备份、更新等服务
+package netclient
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Pin 对一个主机放宽证书校验：证书链无法校验时，服务器证书的指纹匹配，
+// 或服务器证书由指纹匹配的 CA 证书签发且主机名相符时信任
+type Pin struct {
+	Host   string // 主机名，不含端口，不区分大小写
+	SHA256 string // 证书 DER 编码的 SHA-256 指纹，十六进制，可带冒号
+}
+
+// Options 传输层设置
+type Options struct {
+	Proxy     string   // 代理地址，为空时使用 HTTP_PROXY、HTTPS_PROXY、NO_PROXY 环境变量
+	CABundles []string // 额外信任的 PEM 证书文件
+	Pins      []Pin    // 证书校验的例外
+}
+
+// transports 按设置创建的传输层，有证书例外的主机各自使用独立的传输层
+type transports struct {
+	base   *http.Transport
+	pinned map[string]*http.Transport // 主机 -> 传输层
+}
+
+// closeIdleConnections 关闭全部空闲连接
+func (t *transports) closeIdleConnections() {
+	t.base.CloseIdleConnections()
+	for _, transport := range t.pinned {
+		transport.CloseIdleConnections()
+	}
+}
+
+// current 当前使用的传输层，设置变化时整体替换，已创建的客户端随之生效
+var current atomic.Pointer[transports]
+
+func init() {
+	current.Store(&transports{base: newTransport(http.ProxyFromEnvironment, nil)})
+}
+
+// Configure 按设置重建共用的传输层，设置有误时保持原传输层不变并返回错误
+func Configure(options Options) error {
+	next, err := build(options)
+	if err != nil {
+		return err
+	}
+	current.Swap(next).closeIdleConnections()
+	return nil
+}
+
+// Transport 共用的传输层，每次请求使用最新的设置；不重试也不记录统计，一般应使用 NewTransport
+func Transport() http.RoundTripper {
+	return roundTripper{}
+}
+
+// Client 不重试、不区分服务的客户端，timeout 为 0 时不限时
+func Client(timeout time.Duration) *http.Client {
+	return New(Config{Timeout: timeout})
+}
+
+// roundTripper 把请求转交给当前的传输层
+type roundTripper struct{}
+
+func (roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	set := current.Load()
+	if transport, ok := set.pinned[strings.ToLower(req.URL.Hostname())]; ok {
+		return transport.RoundTrip(req)
+	}
+	return set.base.RoundTrip(req)
+}
+
+// newTransport 基于默认传输层创建，保留超时和连接池设置
+func newTransport(proxy func(*http.Request) (*url.URL, error), config *tls.Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	transport.TLSClientConfig = config
+	return transport
+}
+
+// proxyFunc 代理设置，为空时使用环境变量
+func proxyFunc(proxy string) (func(*http.Request) (*url.URL, error), error) {
+	proxy = strings.TrimSpace(proxy)
+	if proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy address %q", proxy)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+	}
+	return http.ProxyURL(proxyURL), nil
+}
+
+// build 根据设置创建传输层，没有额外设置时使用系统默认的证书校验
+func build(options Options) (*transports, error) {
+	proxy, err := proxyFunc(options.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	pins, err := parsePins(options.Pins)
+	if err != nil {
+		return nil, err
+	}
+	if len(options.CABundles) == 0 && len(pins) == 0 {
+		return &transports{base: newTransport(proxy, nil)}, nil
+	}
+
+	roots, err := x509.SystemCertPool()
+	if err != nil || roots == nil {
+		roots = x509.NewCertPool()
+	}
+	for _, path := range options.CABundles {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if _, err := LoadCABundle(roots, path); err != nil {
+			return nil, err
+		}
+	}
+
+	set := &transports{
+		base:   newTransport(proxy, &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}),
+		pinned: make(map[string]*http.Transport, len(pins)),
+	}
+	for host, fingerprints := range pins {
+		// 由 VerifyConnection 完成校验，以便在证书链无法校验时比较证书指纹
+		set.pinned[host] = newTransport(proxy, &tls.Config{
+			RootCAs:            roots,
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: true,
+			VerifyConnection: func(state tls.ConnectionState) error {
+				return verifyConnection(state, host, roots, fingerprints)
+			},
+		})
+	}
+	return set, nil
+}
+
+// LoadCABundle 把 PEM 文件中的证书加入 pool，返回加入的证书数
+func LoadCABundle(pool *x509.CertPool, path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("read CA bundle %s: %w", path, err)
+	}
+	count := 0
+	for _, cert := range parsePEMCertificates(data) {
+		pool.AddCert(cert)
+		count++
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("no certificates found in CA bundle %s", path)
+	}
+	return count, nil
+}
+
+// verifyConnection 按系统证书和额外证书校验证书链，失败时比较服务器证书的指纹，或以固定的 CA 证书为根重新校验
+func verifyConnection(state tls.ConnectionState, host string, roots *x509.CertPool, fingerprints map[string]bool) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("server presented no certificates")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	leaf := state.PeerCertificates[0]
+	_, verifyErr := leaf.Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	if verifyErr == nil {
+		return nil
+	}
+	if fingerprints[Fingerprint(leaf)] {
+		return nil
+	}
+	// 服务器可以在证书链中附带任意证书，固定的 CA 证书只作为唯一的根证书重新校验
+	pinned := x509.NewCertPool()
+	found := false
+	for _, cert := range state.PeerCertificates[1:] {
+		if fingerprints[Fingerprint(cert)] {
+			pinned.AddCert(cert)
+			found = true
+		}
+	}
+	if !found {
+		return verifyErr
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: pinned, Intermediates: intermediates}); err != nil {
+		return verifyErr
+	}
+	return nil
+}
+
+// Fingerprint 证书 DER 编码的 SHA-256 指纹，小写十六进制
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// parsePins 按主机整理证书指纹，指纹统一为不带冒号的小写十六进制
+func parsePins(pins []Pin) (map[string]map[string]bool, error) {
+	out := make(map[string]map[string]bool)
+	for _, pin := range pins {
+		host := strings.ToLower(strings.TrimSpace(pin.Host))
+		fingerprint := normalizeFingerprint(pin.SHA256)
+		if host == "" {
+			return nil, errors.New("certificate pin has no host")
+		}
+		if decoded, err := hex.DecodeString(fingerprint); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("invalid SHA-256 fingerprint for %s", host)
+		}
+		if out[host] == nil {
+			out[host] = make(map[string]bool)
+		}
+		out[host][fingerprint] = true
+	}
+	return out, nil
+}
+
+// normalizeFingerprint 去掉冒号和空白并转为小写
+func normalizeFingerprint(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return strings.NewReplacer(":", "", " ", "").Replace(value)
+}
+
+// parsePEMCertificates 解析 PEM 数据中的全部证书，跳过无法解析的块
+func parsePEMCertificates(data []byte) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			certs = append(certs, cert)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package netclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigure(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	defer Configure(Options{})
+
+	cert := server.Certificate()
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	host := "127.0.0.1"
+	// 指纹以常见的大写冒号分隔格式填写
+	var pairs []string
+	for i := 0; i < len(Fingerprint(cert)); i += 2 {
+		pairs = append(pairs, strings.ToUpper(Fingerprint(cert)[i:i+2]))
+	}
+	colonFingerprint := strings.Join(pairs, ":")
+
+	tests := []struct {
+		name    string
+		options Options
+		ok      bool
+	}{
+		{"system roots only", Options{}, false},
+		{"custom CA bundle", Options{CABundles: []string{bundle}}, true},
+		{"pinned certificate", Options{Pins: []Pin{{Host: host, SHA256: colonFingerprint}}}, true},
+		{"pin for another host", Options{Pins: []Pin{{Host: "example.com", SHA256: colonFingerprint}}}, false},
+		{"wrong fingerprint", Options{Pins: []Pin{{Host: host, SHA256: strings.Repeat("ab", 32)}}}, false},
+	}
+	client := Client(5 * time.Second)
+	for _, tt := range tests {
+		if err := Configure(tt.options); err != nil {
+			t.Fatalf("%s: configure: %v", tt.name, err)
+		}
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: got error %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+// newCertificate 生成测试证书，parent 为空时自签名
+func newCertificate(t *testing.T, name string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if !isCA {
+		template.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+// TestPinnedCA 固定的 CA 证书只在服务器证书确实由它签发时生效
+func TestPinnedCA(t *testing.T) {
+	defer Configure(Options{})
+	ca, caKey := newCertificate(t, "proxy CA", true, nil, nil)
+	signed, signedKey := newCertificate(t, "signed leaf", false, ca, caKey)
+	forged, forgedKey := newCertificate(t, "forged leaf", false, nil, nil)
+
+	tests := []struct {
+		name  string
+		chain tls.Certificate
+		ok    bool
+	}{
+		{"leaf signed by pinned CA", tls.Certificate{Certificate: [][]byte{signed.Raw, ca.Raw}, PrivateKey: signedKey}, true},
+		{"pinned CA appended to forged leaf", tls.Certificate{Certificate: [][]byte{forged.Raw, ca.Raw}, PrivateKey: forgedKey}, false},
+	}
+	if err := Configure(Options{Pins: []Pin{{Host: "127.0.0.1", SHA256: Fingerprint(ca)}}}); err != nil {
+		t.Fatal(err)
+	}
+	client := Client(5 * time.Second)
+	for _, tt := range tests {
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		server.TLS = &tls.Config{Certificates: []tls.Certificate{tt.chain}}
+		server.StartTLS()
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		server.Close()
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: got error %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestConfigureRejectsInvalidOptions(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, options := range []Options{
+		{CABundles: []string{empty}},
+		{CABundles: []string{filepath.Join(t.TempDir(), "missing.pem")}},
+		{Pins: []Pin{{Host: "", SHA256: strings.Repeat("ab", 32)}}},
+		{Pins: []Pin{{Host: "example.com", SHA256: "abcd"}}},
+		{Proxy: "ftp://proxy.example.com"},
+		{Proxy: "proxy.example.com:8080"},
+	} {
+		if err := Configure(options); err == nil {
+			t.Errorf("Configure(%+v) succeeded, want error", options)
+		}
+	}
+}
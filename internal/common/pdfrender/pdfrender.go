@@ -0,0 +1,370 @@
+// Package pdfrender 将文档块排版为 PDF
+// 页面在服务端绘制为图片后写入 PDF，中日韩文字使用系统字体，代码块按语言高亮，不依赖浏览器打印
+package pdfrender
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strings"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/fontface"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// A4 纸张大小（点）
+	a4Width  = 595.28
+	a4Height = 841.89
+
+	defaultMargin = 48
+	defaultScale  = 2
+	tabWidth      = 4
+
+	titleSize = 16
+	proseSize = 10.5
+	codeSize  = 9
+	// lineSpacing 行高与字号之比
+	lineSpacing = 1.5
+	// blockGap 块之间的间距与正文字号之比
+	blockGap = 1.0
+	// codeInset 代码块背景的内边距与代码字号之比
+	codeInset = 0.8
+)
+
+// DefaultStyle 未指定或样式不存在时的代码高亮样式
+const DefaultStyle = "github"
+
+// Options 排版选项
+type Options struct {
+	Style      string  // 代码高亮样式，chroma 样式名
+	PageWidth  float64 // 页面宽度（点），默认 A4
+	PageHeight float64 // 页面高度（点），默认 A4
+	Margin     float64 // 页边距（点）
+	Scale      int     // 每点的像素数，越大越清晰，文件也越大
+}
+
+// withDefaults 补全未设置的选项
+func (o Options) withDefaults() Options {
+	if o.PageWidth <= 0 || o.PageHeight <= 0 {
+		o.PageWidth, o.PageHeight = a4Width, a4Height
+	}
+	if o.Margin <= 0 {
+		o.Margin = defaultMargin
+	}
+	if o.Scale <= 0 {
+		o.Scale = defaultScale
+	}
+	if _, ok := styles.Registry[o.Style]; !ok {
+		o.Style = DefaultStyle
+	}
+	return o
+}
+
+// glyph 带颜色的字符
+type glyph struct {
+	r rune
+	c color.Color
+}
+
+// Render 排版文档块，标题显示在第一页顶部；只含空白的块跳过
+func Render(title string, list []blocks.Block, opts Options) ([]byte, error) {
+	opts = opts.withDefaults()
+	p, err := newPainter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if title = strings.TrimSpace(title); title != "" {
+		p.paragraph(p.title, toGlyphs(title, p.text), nil)
+		p.gap()
+	}
+	for _, block := range list {
+		if strings.TrimSpace(block.Content) == "" {
+			continue
+		}
+		content := strings.TrimRight(block.Content, "\n")
+		switch {
+		case block.Language == "md":
+			p.markdown(content)
+		case blocks.IsProse(block.Language):
+			for _, line := range strings.Split(content, "\n") {
+				p.paragraph(p.prose, toGlyphs(line, p.text), nil)
+			}
+		default:
+			p.code(block.Language, content)
+		}
+		p.gap()
+	}
+	return encodePDF(p.finish(), opts.PageWidth, opts.PageHeight, title)
+}
+
+// faceSet 主字体和中日韩后备字体
+type faceSet struct {
+	*fontface.Set
+	height int // 行高（像素）
+}
+
+// painter 逐行把内容绘制到页面上，放不下时换页
+type painter struct {
+	opts  Options
+	style *chroma.Style
+
+	title, prose, mono *faceSet
+
+	text       color.Color // 正文颜色
+	codeText   color.Color // 代码默认颜色
+	codeBack   color.Color // 代码块背景
+	pageWidth  int
+	pageHeight int
+	margin     int
+
+	pages []*image.RGBA
+	page  *image.RGBA
+	y     int
+}
+
+func newPainter(opts Options) (*painter, error) {
+	px := func(v float64) int { return int(math.Round(v * float64(opts.Scale))) }
+	p := &painter{
+		opts:       opts,
+		style:      styles.Get(opts.Style),
+		text:       color.RGBA{R: 0x22, G: 0x22, B: 0x22, A: 0xff},
+		pageWidth:  px(opts.PageWidth),
+		pageHeight: px(opts.PageHeight),
+		margin:     px(opts.Margin),
+	}
+
+	var err error
+	if p.title, err = newFaceSet(gobold.TTF, px(titleSize)); err != nil {
+		return nil, err
+	}
+	if p.prose, err = newFaceSet(goregular.TTF, px(proseSize)); err != nil {
+		return nil, err
+	}
+	if p.mono, err = newFaceSet(gomono.TTF, px(codeSize)); err != nil {
+		return nil, err
+	}
+
+	background := p.style.Get(chroma.Background)
+	p.codeText = p.text
+	if background.Colour.IsSet() {
+		p.codeText = chromaColor(background.Colour)
+	}
+	p.codeBack = color.RGBA{R: 0xf6, G: 0xf8, B: 0xfa, A: 0xff}
+	if background.Background.IsSet() {
+		p.codeBack = chromaColor(background.Background)
+	}
+	return p, nil
+}
+
+// newFaceSet 创建指定像素大小的字体
+func newFaceSet(ttf []byte, size int) (*faceSet, error) {
+	parsed, err := opentype.Parse(ttf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font: %w", err)
+	}
+	set, err := fontface.New(parsed, float64(size))
+	if err != nil {
+		return nil, err
+	}
+	return &faceSet{Set: set, height: int(math.Round(float64(size) * lineSpacing))}, nil
+}
+
+// newPage 开始新的一页
+func (p *painter) newPage() {
+	p.page = image.NewRGBA(image.Rect(0, 0, p.pageWidth, p.pageHeight))
+	draw.Draw(p.page, p.page.Bounds(), image.White, image.Point{}, draw.Src)
+	p.pages = append(p.pages, p.page)
+	p.y = p.margin
+}
+
+// ensure 当前页放不下 height 时换页
+func (p *painter) ensure(height int) {
+	if p.page == nil || p.y+height > p.pageHeight-p.margin {
+		p.newPage()
+	}
+}
+
+// gap 块之间的间距，页首不留间距
+func (p *painter) gap() {
+	if p.page != nil && p.y > p.margin {
+		p.y += int(float64(p.prose.height) * blockGap / lineSpacing)
+	}
+}
+
+// paragraph 按可用宽度折行绘制一行文本，background 非空时每行先绘制背景
+func (p *painter) paragraph(faces *faceSet, line []glyph, background color.Color) {
+	inset := 0
+	if background != nil {
+		inset = int(float64(faces.height) * codeInset / lineSpacing)
+	}
+	for _, row := range wrap(line, faces, p.pageWidth-2*p.margin-2*inset) {
+		p.ensure(faces.height)
+		if background != nil {
+			rect := image.Rect(p.margin, p.y, p.pageWidth-p.margin, p.y+faces.height)
+			draw.Draw(p.page, rect, image.NewUniform(background), image.Point{}, draw.Src)
+		}
+		p.draw(faces, row, p.margin+inset)
+		p.y += faces.height
+	}
+}
+
+// draw 在当前行绘制文本
+func (p *painter) draw(faces *faceSet, row []glyph, x int) {
+	metrics := faces.Primary.Metrics()
+	baseline := p.y + (faces.height+metrics.Ascent.Ceil()-metrics.Descent.Ceil())/2
+	dot := fixed.P(x, baseline)
+	for _, g := range row {
+		face := faces.FaceFor(g.r)
+		if g.r != ' ' {
+			d := font.Drawer{Dst: p.page, Src: image.NewUniform(g.c), Face: face, Dot: dot}
+			d.DrawString(string(g.r))
+		}
+		dot.X += faces.Advance(g.r)
+	}
+}
+
+// code 绘制高亮的代码块
+func (p *painter) code(language, content string) {
+	for _, line := range p.highlight(language, content) {
+		p.paragraph(p.mono, line, p.codeBack)
+	}
+}
+
+// markdown 绘制 Markdown 块：标题加粗，围栏代码块按标注的语言高亮，其余按原文绘制
+func (p *painter) markdown(content string) {
+	var code []string
+	fence, language := "", ""
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			fence = trimmed[:3]
+			language = strings.TrimSpace(strings.TrimLeft(trimmed, "`~"))
+			if fields := strings.Fields(language); len(fields) > 0 {
+				language = fields[0]
+			}
+		case fence != "" && strings.HasPrefix(trimmed, fence):
+			p.code(language, strings.Join(code, "\n"))
+			code, fence = nil, ""
+		case fence != "":
+			code = append(code, line)
+		case strings.HasPrefix(trimmed, "#"):
+			heading := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			p.paragraph(p.title, toGlyphs(heading, p.text), nil)
+		default:
+			p.paragraph(p.prose, toGlyphs(line, p.text), nil)
+		}
+	}
+	if fence != "" {
+		p.code(language, strings.Join(code, "\n"))
+	}
+}
+
+// highlight 按语言把代码切分为带颜色的行，没有对应的语法时不高亮
+func (p *painter) highlight(language, content string) [][]glyph {
+	lexer := lexers.Get(language)
+	if lexer == nil {
+		return splitLines(toGlyphs(content, p.codeText))
+	}
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, content)
+	if err != nil {
+		return splitLines(toGlyphs(content, p.codeText))
+	}
+
+	var all []glyph
+	for _, token := range iterator.Tokens() {
+		c := p.codeText
+		if entry := p.style.Get(token.Type); entry.Colour.IsSet() {
+			c = chromaColor(entry.Colour)
+		}
+		all = append(all, toGlyphs(token.Value, c)...)
+	}
+	return splitLines(all)
+}
+
+// finish 返回绘制好的页面，没有内容时返回一张空白页
+func (p *painter) finish() []*image.RGBA {
+	if len(p.pages) == 0 {
+		p.newPage()
+	}
+	return p.pages
+}
+
+// toGlyphs 把文本转换为同一颜色的字符，制表符展开为空格，去掉回车
+func toGlyphs(text string, c color.Color) []glyph {
+	out := make([]glyph, 0, len(text))
+	for _, r := range text {
+		switch r {
+		case '\r':
+			continue
+		case '\t':
+			for range tabWidth {
+				out = append(out, glyph{r: ' ', c: c})
+			}
+			continue
+		}
+		out = append(out, glyph{r: r, c: c})
+	}
+	return out
+}
+
+// splitLines 按换行拆分字符
+func splitLines(all []glyph) [][]glyph {
+	lines := [][]glyph{nil}
+	for _, g := range all {
+		if g.r == '\n' {
+			lines = append(lines, nil)
+			continue
+		}
+		lines[len(lines)-1] = append(lines[len(lines)-1], g)
+	}
+	return lines
+}
+
+// wrap 按宽度折行，优先在空格处断开，单词超过一行或中日韩文字时在字符间断开；空行保留为一行
+func wrap(line []glyph, faces *faceSet, width int) [][]glyph {
+	limit := fixed.I(width)
+	var rows [][]glyph
+	for len(line) > 0 {
+		var used fixed.Int26_6
+		end, lastSpace := 0, -1
+		for end < len(line) {
+			advance := faces.Advance(line[end].r)
+			if used+advance > limit && end > 0 {
+				break
+			}
+			used += advance
+			if line[end].r == ' ' {
+				lastSpace = end
+			}
+			end++
+		}
+		if end < len(line) && lastSpace > 0 {
+			end = lastSpace + 1
+		}
+		rows = append(rows, line[:end])
+		line = line[end:]
+	}
+	if len(rows) == 0 {
+		rows = append(rows, nil)
+	}
+	return rows
+}
+
+// chromaColor 转换高亮样式中的颜色
+func chromaColor(c chroma.Colour) color.Color {
+	return color.RGBA{R: c.Red(), G: c.Green(), B: c.Blue(), A: 0xff}
+}
@@ -0,0 +1,71 @@
+package pdfrender
+
+import (
+	"bytes"
+	"image/color"
+	"strings"
+	"testing"
+	"voidraft/internal/common/blocks"
+
+	"golang.org/x/image/font/gofont/gomono"
+)
+
+func TestWrap(t *testing.T) {
+	faces, err := newFaceSet(gomono.TTF, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cell := faces.Advance('M').Ceil()
+
+	text := func(rows [][]glyph) []string {
+		out := make([]string, len(rows))
+		for i, row := range rows {
+			var b strings.Builder
+			for _, g := range row {
+				b.WriteRune(g.r)
+			}
+			out[i] = b.String()
+		}
+		return out
+	}
+
+	tests := []struct {
+		line  string
+		width int
+		want  []string
+	}{
+		{"", 10, []string{""}},
+		{"short", 10, []string{"short"}},
+		{"hello world again", 12, []string{"hello world ", "again"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+	}
+	for _, tt := range tests {
+		got := text(wrap(toGlyphs(tt.line, color.Black), faces, tt.width*cell))
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("wrap(%q, %d) = %q, want %q", tt.line, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	content := "\n∞∞∞text\nHello 你好\n∞∞∞go\nfunc main() {\n\tprintln(\"hi\")\n}\n∞∞∞md\n# Notes\n```py\nx = 1\n```"
+	out, err := Render("Title", blocks.Parse(content), Options{Scale: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatalf("not a PDF file")
+	}
+	if got := bytes.Count(out, []byte("/Type /Page ")); got != 1 {
+		t.Errorf("pages = %d, want 1", got)
+	}
+
+	long := "\n∞∞∞text\n" + strings.Repeat("line\n", 200)
+	out, err = Render("", blocks.Parse(long), Options{Scale: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := bytes.Count(out, []byte("/Type /Page ")); got < 2 {
+		t.Errorf("pages = %d, want at least 2", got)
+	}
+}
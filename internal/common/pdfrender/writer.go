@@ -0,0 +1,120 @@
+package pdfrender
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"strings"
+	"unicode/utf16"
+)
+
+// writer 按顺序写出 PDF 对象并记录偏移，最后写出交叉引用表
+type writer struct {
+	buf     bytes.Buffer
+	offsets []int // 对象编号 - 1 -> 偏移
+}
+
+// reserve 预留一个对象编号，对象内容稍后写出
+func (w *writer) reserve() int {
+	w.offsets = append(w.offsets, 0)
+	return len(w.offsets)
+}
+
+// object 写出对象，body 为对象的字典或数组
+func (w *writer) object(id int, body string) {
+	w.offsets[id-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", id, body)
+}
+
+// stream 写出流对象，dict 为除 Length 外的字典项
+func (w *writer) stream(id int, dict string, data []byte) {
+	w.offsets[id-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n<< %s /Length %d >>\nstream\n", id, dict, len(data))
+	w.buf.Write(data)
+	w.buf.WriteString("\nendstream\nendobj\n")
+}
+
+// finish 写出交叉引用表和文件尾
+func (w *writer) finish(root, info int) []byte {
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, offset := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(w.offsets)+1, root, info, xref)
+	return w.buf.Bytes()
+}
+
+// encodePDF 把每页一张图片写为 PDF，图片铺满页面；width 和 height 为页面大小（点）
+func encodePDF(pages []*image.RGBA, width, height float64, title string) ([]byte, error) {
+	w := &writer{}
+	w.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	catalog := w.reserve()
+	tree := w.reserve()
+	info := w.reserve()
+
+	kids := make([]string, 0, len(pages))
+	for _, page := range pages {
+		pixels, err := compressRGB(page)
+		if err != nil {
+			return nil, err
+		}
+		bounds := page.Bounds()
+		img := w.reserve()
+		w.stream(img, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode",
+			bounds.Dx(), bounds.Dy()), pixels)
+
+		content := w.reserve()
+		w.stream(content, "", fmt.Appendf(nil, "q %s 0 0 %s 0 0 cm /Im0 Do Q", number(width), number(height)))
+
+		id := w.reserve()
+		w.object(id, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /XObject << /Im0 %d 0 R >> >> /Contents %d 0 R >>",
+			tree, number(width), number(height), img, content))
+		kids = append(kids, fmt.Sprintf("%d 0 R", id))
+	}
+
+	w.object(tree, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	w.object(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", tree))
+	w.object(info, fmt.Sprintf("<< /Title %s /Producer (voidraft) >>", textString(title)))
+	return w.finish(catalog, info), nil
+}
+
+// compressRGB 去掉透明通道并压缩像素
+func compressRGB(img *image.RGBA) ([]byte, error) {
+	var out bytes.Buffer
+	zw := zlib.NewWriter(&out)
+	bounds := img.Bounds()
+	row := make([]byte, bounds.Dx()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		src := img.Pix[img.PixOffset(bounds.Min.X, y):]
+		for x := 0; x < bounds.Dx(); x++ {
+			copy(row[x*3:x*3+3], src[x*4:x*4+3])
+		}
+		if _, err := zw.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// textString 文档信息中的文本，按 UTF-16BE 编码为十六进制字符串，以支持任意字符
+func textString(s string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", unit)
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+// number 格式化页面尺寸，去掉多余的小数位
+func number(v float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
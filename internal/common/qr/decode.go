@@ -0,0 +1,374 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+// grid 采样得到的模块矩阵，grid[y][x] 为 true 表示深色
+type grid [][]bool
+
+// functionModules 标记版本中的功能图形（定位、时序、校正、格式和版本信息），其余为数据模块
+func functionModules(version int) [][]bool {
+	size := version*4 + 17
+	marked := make([][]bool, size)
+	for i := range marked {
+		marked[i] = make([]bool, size)
+	}
+	fill := func(x0, y0, x1, y1 int) {
+		for y := max(y0, 0); y <= min(y1, size-1); y++ {
+			for x := max(x0, 0); x <= min(x1, size-1); x++ {
+				marked[y][x] = true
+			}
+		}
+	}
+	// 时序图形
+	fill(6, 0, 6, size-1)
+	fill(0, 6, size-1, 6)
+	// 定位图形及分隔符
+	fill(0, 0, 8, 8)
+	fill(size-8, 0, size-1, 8)
+	fill(0, size-8, 8, size-1)
+	// 校正图形，跳过与定位图形重叠的三个角
+	positions := alignmentPositions(version)
+	last := len(positions) - 1
+	for i, y := range positions {
+		for j, x := range positions {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			fill(x-2, y-2, x+2, y+2)
+		}
+	}
+	// 版本信息
+	if version >= 7 {
+		fill(size-11, 0, size-9, 5)
+		fill(0, size-11, 5, size-9)
+	}
+	return marked
+}
+
+// readFormat 读取两份格式信息，返回纠错等级下标和掩码
+func (g grid) readFormat() (level int, mask int, err error) {
+	size := len(g)
+	bit := func(x, y int) int {
+		if g[y][x] {
+			return 1
+		}
+		return 0
+	}
+	var first, second int
+	for i := 0; i <= 5; i++ {
+		first |= bit(8, i) << i
+	}
+	first |= bit(8, 7) << 6
+	first |= bit(8, 8) << 7
+	first |= bit(7, 8) << 8
+	for i := 9; i < 15; i++ {
+		first |= bit(14-i, 8) << i
+	}
+	for i := 0; i < 8; i++ {
+		second |= bit(size-1-i, 8) << i
+	}
+	for i := 8; i < 15; i++ {
+		second |= bit(8, size-15+i) << i
+	}
+
+	best, bestDistance := -1, 4
+	for data := 0; data < 32; data++ {
+		encoded := formatBits(data)
+		for _, read := range []int{first, second} {
+			if d := bits.OnesCount(uint(encoded ^ read)); d < bestDistance {
+				best, bestDistance = data, d
+			}
+		}
+	}
+	if best < 0 {
+		return 0, 0, errors.New("unreadable format information")
+	}
+	return formatLevels[best>>3], best & 7, nil
+}
+
+// formatBits 计算 5 位格式数据的 BCH 编码并加掩码
+func formatBits(data int) int {
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// masked 掩码条件成立时数据模块取反
+func masked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	}
+	return ((x+y)%2+x*y%3)%2 == 0
+}
+
+// decode 解码模块矩阵中的文本
+func (g grid) decode() (string, error) {
+	size := len(g)
+	version := (size - 17) / 4
+	if version < 1 || version > 40 || size != version*4+17 {
+		return "", fmt.Errorf("invalid symbol size: %d", size)
+	}
+	level, mask, err := g.readFormat()
+	if err != nil {
+		return "", err
+	}
+
+	// 按之字形顺序读取数据模块
+	function := functionModules(version)
+	var raw []byte
+	var current byte
+	count := 0
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = size - 1 - vert
+				}
+				if function[y][x] {
+					continue
+				}
+				current <<= 1
+				if g[y][x] != masked(mask, x, y) {
+					current |= 1
+				}
+				count++
+				if count%8 == 0 {
+					raw = append(raw, current)
+					current = 0
+				}
+			}
+		}
+	}
+
+	data, err := deinterleave(raw, version, level)
+	if err != nil {
+		return "", err
+	}
+	return decodeSegments(data, version)
+}
+
+// deinterleave 拆分交错的码字块，逐块纠错后拼接数据码字
+func deinterleave(raw []byte, version, level int) ([]byte, error) {
+	numBlocks := numErrorCorrectionBlocks[level][version]
+	ecc := eccCodewordsPerBlock[level][version]
+	numShort := numBlocks - len(raw)%numBlocks
+	shortLen := len(raw) / numBlocks
+	shortData := shortLen - ecc
+
+	blocks := make([][]byte, numBlocks)
+	for i := range blocks {
+		length := shortLen
+		if i >= numShort {
+			length++
+		}
+		blocks[i] = make([]byte, 0, length)
+	}
+	k := 0
+	for i := 0; i <= shortLen; i++ {
+		for j := range blocks {
+			// 短块比长块少一个数据码字
+			if i == shortData && j < numShort {
+				continue
+			}
+			if k >= len(raw) {
+				break
+			}
+			blocks[j] = append(blocks[j], raw[k])
+			k++
+		}
+	}
+
+	var data []byte
+	for _, block := range blocks {
+		if err := correct(block, ecc); err != nil {
+			return nil, err
+		}
+		data = append(data, block[:len(block)-ecc]...)
+	}
+	return data, nil
+}
+
+// bitReader 按位读取数据码字
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReader) remaining() int { return len(r.data)*8 - r.pos }
+
+func (r *bitReader) read(n int) (int, error) {
+	if n > r.remaining() {
+		return 0, errors.New("unexpected end of data")
+	}
+	v := 0
+	for i := 0; i < n; i++ {
+		v = v<<1 | int(r.data[r.pos/8]>>(7-r.pos%8))&1
+		r.pos++
+	}
+	return v, nil
+}
+
+const alphanumericChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// decodeSegments 解码数据段：数字、字母数字、字节和汉字模式
+func decodeSegments(data []byte, version int) (string, error) {
+	group := 0
+	if version >= 27 {
+		group = 2
+	} else if version >= 10 {
+		group = 1
+	}
+	r := &bitReader{data: data}
+	var out strings.Builder
+	for r.remaining() >= 4 {
+		mode, _ := r.read(4)
+		switch mode {
+		case 0:
+			return out.String(), nil
+		case 1: // 数字
+			n, err := r.read([3]int{10, 12, 14}[group])
+			if err != nil {
+				return "", err
+			}
+			for ; n >= 3; n -= 3 {
+				v, err := r.read(10)
+				if err != nil {
+					return "", err
+				}
+				fmt.Fprintf(&out, "%03d", v)
+			}
+			switch n {
+			case 2:
+				v, err := r.read(7)
+				if err != nil {
+					return "", err
+				}
+				fmt.Fprintf(&out, "%02d", v)
+			case 1:
+				v, err := r.read(4)
+				if err != nil {
+					return "", err
+				}
+				fmt.Fprintf(&out, "%d", v)
+			}
+		case 2: // 字母数字
+			n, err := r.read([3]int{9, 11, 13}[group])
+			if err != nil {
+				return "", err
+			}
+			for ; n >= 2; n -= 2 {
+				v, err := r.read(11)
+				if err != nil || v >= 45*45 {
+					return "", errors.New("invalid alphanumeric data")
+				}
+				out.WriteByte(alphanumericChars[v/45])
+				out.WriteByte(alphanumericChars[v%45])
+			}
+			if n == 1 {
+				v, err := r.read(6)
+				if err != nil || v >= 45 {
+					return "", errors.New("invalid alphanumeric data")
+				}
+				out.WriteByte(alphanumericChars[v])
+			}
+		case 4: // 字节，非 UTF-8 时按 ISO-8859-1 处理
+			n, err := r.read([3]int{8, 16, 16}[group])
+			if err != nil {
+				return "", err
+			}
+			buf := make([]byte, n)
+			for i := range buf {
+				v, err := r.read(8)
+				if err != nil {
+					return "", err
+				}
+				buf[i] = byte(v)
+			}
+			if utf8.Valid(buf) {
+				out.Write(buf)
+			} else {
+				for _, b := range buf {
+					out.WriteRune(rune(b))
+				}
+			}
+		case 8: // 汉字，Shift JIS 编码
+			n, err := r.read([3]int{8, 10, 12}[group])
+			if err != nil {
+				return "", err
+			}
+			buf := make([]byte, 0, n*2)
+			for i := 0; i < n; i++ {
+				v, err := r.read(13)
+				if err != nil {
+					return "", err
+				}
+				c := (v/0xc0)<<8 | v%0xc0
+				if c < 0x1f00 {
+					c += 0x8140
+				} else {
+					c += 0xc140
+				}
+				buf = append(buf, byte(c>>8), byte(c))
+			}
+			decoded, err := japanese.ShiftJIS.NewDecoder().Bytes(buf)
+			if err != nil {
+				return "", fmt.Errorf("invalid kanji data: %w", err)
+			}
+			out.Write(decoded)
+		case 7: // ECI，字节段按内容自动识别编码，这里只跳过指示符
+			v, err := r.read(8)
+			if err != nil {
+				return "", err
+			}
+			if v&0x80 != 0 {
+				extra := 8
+				if v&0xc0 == 0xc0 {
+					extra = 16
+				}
+				if _, err := r.read(extra); err != nil {
+					return "", err
+				}
+			}
+		case 3: // 结构链接
+			if _, err := r.read(16); err != nil {
+				return "", err
+			}
+		case 5: // FNC1 第一位置
+		case 9: // FNC1 第二位置
+			if _, err := r.read(8); err != nil {
+				return "", err
+			}
+		default:
+			return "", fmt.Errorf("unsupported mode: %d", mode)
+		}
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,273 @@
+package qr
+
+import (
+	"image"
+	"math"
+	"sort"
+)
+
+// bitmap 二值化后的图像
+type bitmap struct {
+	width, height int
+	dark          []bool
+}
+
+func (b *bitmap) at(x, y int) bool {
+	if x < 0 || y < 0 || x >= b.width || y >= b.height {
+		return false
+	}
+	return b.dark[y*b.width+x]
+}
+
+// binarize 将图像按大津法阈值二值化，透明像素视为白色
+func binarize(img image.Image) *bitmap {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	gray := make([]uint8, w*h)
+	var histogram [256]int
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			// 叠加到白色背景
+			white := 0xffff - a
+			lum := (299*(r+white) + 587*(g+white) + 114*(b+white)) / 1000
+			v := uint8(min(lum, 0xffff) >> 8)
+			gray[y*w+x] = v
+			histogram[v]++
+		}
+	}
+
+	total := w * h
+	var sum float64
+	for i, n := range histogram {
+		sum += float64(i * n)
+	}
+	var sumB, best float64
+	var weightB int
+	threshold := 127
+	for t, n := range histogram {
+		weightB += n
+		if weightB == 0 {
+			continue
+		}
+		weightF := total - weightB
+		if weightF == 0 {
+			break
+		}
+		sumB += float64(t * n)
+		meanB := sumB / float64(weightB)
+		meanF := (sum - sumB) / float64(weightF)
+		between := float64(weightB) * float64(weightF) * (meanB - meanF) * (meanB - meanF)
+		if between > best {
+			best, threshold = between, t
+		}
+	}
+
+	bm := &bitmap{width: w, height: h, dark: make([]bool, total)}
+	for i, v := range gray {
+		bm.dark[i] = int(v) <= threshold
+	}
+	return bm
+}
+
+// invert 反色，用于识别浅色码深色底的二维码
+func (b *bitmap) invert() *bitmap {
+	inverted := &bitmap{width: b.width, height: b.height, dark: make([]bool, len(b.dark))}
+	for i, v := range b.dark {
+		inverted.dark[i] = !v
+	}
+	return inverted
+}
+
+// finder 定位图形候选
+type finder struct {
+	x, y   float64
+	module float64 // 模块尺寸（像素）
+	count  int     // 被检测到的次数
+}
+
+// finderRatio 五段游程是否符合 1:1:3:1:1
+func finderRatio(runs [5]int) bool {
+	total := 0
+	for _, r := range runs {
+		if r == 0 {
+			return false
+		}
+		total += r
+	}
+	if total < 7 {
+		return false
+	}
+	unit := float64(total) / 7
+	variance := unit / 2
+	return math.Abs(unit-float64(runs[0])) < variance &&
+		math.Abs(unit-float64(runs[1])) < variance &&
+		math.Abs(3*unit-float64(runs[2])) < 3*variance &&
+		math.Abs(unit-float64(runs[3])) < variance &&
+		math.Abs(unit-float64(runs[4])) < variance
+}
+
+// crossCheck 沿一条线从 pos（位于中心深色块内）向两侧统计游程，返回中心坐标和总长度
+func crossCheck(dark func(i int) bool, pos, limit int) (float64, int, bool) {
+	var runs [5]int
+	i := pos
+	for i >= 0 && dark(i) {
+		runs[2]++
+		i--
+	}
+	for i >= 0 && !dark(i) {
+		runs[1]++
+		i--
+	}
+	for i >= 0 && dark(i) {
+		runs[0]++
+		i--
+	}
+	start := pos - runs[2] + 1
+	j := pos + 1
+	for j < limit && dark(j) {
+		runs[2]++
+		j++
+	}
+	for j < limit && !dark(j) {
+		runs[3]++
+		j++
+	}
+	for j < limit && dark(j) {
+		runs[4]++
+		j++
+	}
+	if !finderRatio(runs) {
+		return 0, 0, false
+	}
+	total := runs[0] + runs[1] + runs[2] + runs[3] + runs[4]
+	return float64(start) + float64(runs[2])/2, total, true
+}
+
+// findFinders 逐行扫描 1:1:3:1:1 图形，再纵横交叉验证并合并相近的候选
+func (b *bitmap) findFinders() []*finder {
+	var finders []*finder
+	for y := 0; y < b.height; y++ {
+		row := func(i int) bool { return b.at(i, y) }
+		// 行内游程，starts[k] 为第 k 段的起点
+		var starts, lengths []int
+		for x := 0; x < b.width; {
+			start := x
+			for x < b.width && b.at(x, y) == b.at(start, y) {
+				x++
+			}
+			starts, lengths = append(starts, start), append(lengths, x-start)
+		}
+		for k := 0; k+4 < len(lengths); k++ {
+			if !b.at(starts[k], y) {
+				continue
+			}
+			runs := [5]int{lengths[k], lengths[k+1], lengths[k+2], lengths[k+3], lengths[k+4]}
+			if !finderRatio(runs) {
+				continue
+			}
+			total := runs[0] + runs[1] + runs[2] + runs[3] + runs[4]
+			cx := starts[k+2] + lengths[k+2]/2
+			cy, vertical, ok := crossCheck(func(i int) bool { return b.at(cx, i) }, y, b.height)
+			if !ok || 5*abs(vertical-total) >= 2*total {
+				continue
+			}
+			refined, horizontal, ok := crossCheck(row, cx, b.width)
+			if !ok {
+				continue
+			}
+			if _, _, ok := crossCheck(func(i int) bool { return b.at(int(refined), i) }, int(cy), b.height); !ok {
+				continue
+			}
+			module := float64(horizontal+vertical) / 14
+			finders = addFinder(finders, refined, cy, module)
+		}
+	}
+	return finders
+}
+
+// addFinder 合并与已有候选重合的检测结果
+func addFinder(finders []*finder, x, y, module float64) []*finder {
+	for _, f := range finders {
+		if math.Abs(f.x-x) <= f.module && math.Abs(f.y-y) <= f.module && math.Abs(f.module-module) <= math.Max(f.module, 1) {
+			n := float64(f.count)
+			f.x = (f.x*n + x) / (n + 1)
+			f.y = (f.y*n + y) / (n + 1)
+			f.module = (f.module*n + module) / (n + 1)
+			f.count++
+			return finders
+		}
+	}
+	return append(finders, &finder{x: x, y: y, module: module, count: 1})
+}
+
+// symbolCorners 从候选中选出构成直角等腰三角形的三个定位图形，返回左上、右上、左下
+func symbolCorners(finders []*finder) (tl, tr, bl *finder, ok bool) {
+	sort.Slice(finders, func(i, j int) bool { return finders[i].count > finders[j].count })
+	if len(finders) > 12 {
+		finders = finders[:12]
+	}
+	bestScore := math.Inf(1)
+	for i := 0; i < len(finders); i++ {
+		for j := i + 1; j < len(finders); j++ {
+			for k := j + 1; k < len(finders); k++ {
+				a, b, c := finders[i], finders[j], finders[k]
+				minModule := math.Min(a.module, math.Min(b.module, c.module))
+				maxModule := math.Max(a.module, math.Max(b.module, c.module))
+				if maxModule > minModule*1.5 {
+					continue
+				}
+				// 直角顶点为左上角
+				corner, p, q := a, b, c
+				dab, dac, dbc := dist(a, b), dist(a, c), dist(b, c)
+				if dab >= dac && dab >= dbc {
+					corner, p, q = c, a, b
+				} else if dac >= dab && dac >= dbc {
+					corner, p, q = b, a, c
+				}
+				legP, legQ, hyp := dist(corner, p), dist(corner, q), dist(p, q)
+				if legP < minModule*14 || legQ < minModule*14 {
+					continue
+				}
+				score := math.Abs(legP-legQ)/math.Max(legP, legQ) + math.Abs(hyp*hyp-legP*legP-legQ*legQ)/(hyp*hyp)
+				if score > 0.2 || score >= bestScore {
+					continue
+				}
+				// 图像坐标 y 轴向下，右上角在左上角到左下角方向的逆时针侧
+				if (p.x-corner.x)*(q.y-corner.y)-(p.y-corner.y)*(q.x-corner.x) < 0 {
+					p, q = q, p
+				}
+				tl, tr, bl, bestScore = corner, p, q, score
+			}
+		}
+	}
+	return tl, tr, bl, tl != nil
+}
+
+// sample 按三个定位图形确定的仿射网格采样模块
+func (b *bitmap) sample(tl, tr, bl *finder, size int) grid {
+	span := float64(size - 7)
+	ux, uy := (tr.x-tl.x)/span, (tr.y-tl.y)/span
+	vx, vy := (bl.x-tl.x)/span, (bl.y-tl.y)/span
+	g := make(grid, size)
+	for r := 0; r < size; r++ {
+		g[r] = make([]bool, size)
+		for c := 0; c < size; c++ {
+			x := tl.x + float64(c-3)*ux + float64(r-3)*vx
+			y := tl.y + float64(c-3)*uy + float64(r-3)*vy
+			g[r][c] = b.at(int(math.Floor(x)), int(math.Floor(y)))
+		}
+	}
+	return g
+}
+
+func dist(a, b *finder) float64 {
+	return math.Hypot(a.x-b.x, a.y-b.y)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
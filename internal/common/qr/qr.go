@@ -0,0 +1,77 @@
+// Package qr 生成和识别二维码
+// 生成使用 go-qrcode；识别针对截图等未经透视变形的图像，支持任意旋转、1-40 版本和全部纠错等级
+package qr
+
+import (
+	"errors"
+	"image"
+	"math"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	// moduleSize 生成图片中每个模块的像素数
+	moduleSize = 8
+	// MaxDecodePixels 识别图片的像素上限，避免解码超大图片占用过多内存
+	MaxDecodePixels = 40 << 20
+)
+
+// ErrNotFound 图片中没有可识别的二维码
+var ErrNotFound = errors.New("no QR code found")
+
+// Generate 生成二维码 PNG，使用中等纠错等级
+func Generate(text string) ([]byte, error) {
+	if text == "" {
+		return nil, errors.New("text is empty")
+	}
+	code, err := qrcode.New(text, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+	return code.PNG(-moduleSize)
+}
+
+// Decode 识别图片中的二维码文本，先按深色码浅色底识别，失败时再尝试反色
+func Decode(img image.Image) (string, error) {
+	bounds := img.Bounds()
+	if bounds.Dx()*bounds.Dy() > MaxDecodePixels {
+		return "", errors.New("image is too large")
+	}
+	bm := binarize(img)
+	text, err := bm.decode()
+	if err == nil {
+		return text, nil
+	}
+	if inverted, invErr := bm.invert().decode(); invErr == nil {
+		return inverted, nil
+	}
+	return "", err
+}
+
+// decode 定位并解码二维码，版本由定位图形间距估算，估算偏差时尝试相邻版本
+func (b *bitmap) decode() (string, error) {
+	tl, tr, bl, ok := symbolCorners(b.findFinders())
+	if !ok {
+		return "", ErrNotFound
+	}
+	module := (tl.module + tr.module + bl.module) / 3
+	modules := (dist(tl, tr)+dist(tl, bl))/2/module + 7
+	estimate := int(math.Round((modules - 17) / 4))
+
+	err := ErrNotFound
+	for _, delta := range []int{0, -1, 1, -2, 2} {
+		version := estimate + delta
+		if version < 1 || version > 40 {
+			continue
+		}
+		text, decodeErr := b.sample(tl, tr, bl, version*4+17).decode()
+		if decodeErr == nil {
+			return text, nil
+		}
+		if delta == 0 {
+			err = decodeErr
+		}
+	}
+	return "", err
+}
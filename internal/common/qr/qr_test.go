@@ -0,0 +1,138 @@
+package qr
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math/rand"
+	"strings"
+	"testing"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+func TestGenerateAndDecode(t *testing.T) {
+	for _, text := range []string{
+		"https://example.com/path?q=1",
+		"WIFI:T:WPA;S:home;P:secret;;",
+		"otpauth://totp/voidraft:me?secret=JBSWY3DPEHPK3PXP&issuer=voidraft",
+		"12345678901234567890",
+		"中文内容 ✓",
+	} {
+		data, err := Generate(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Decode(img)
+		if err != nil || got != text {
+			t.Errorf("Decode(Generate(%q)) = %q, %v", text, got, err)
+		}
+	}
+}
+
+func TestDecodeAllVersions(t *testing.T) {
+	levels := []qrcode.RecoveryLevel{qrcode.Low, qrcode.Medium, qrcode.High, qrcode.Highest}
+	for version := 1; version <= 40; version++ {
+		for _, level := range levels {
+			text := fmt.Sprintf("v%d-%d ", version, level) + strings.Repeat("x", version)
+			code, err := qrcode.NewWithForcedVersion(text, version, level)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Decode(code.Image(-3))
+			if err != nil || got != text {
+				t.Errorf("version %d level %d: %q, %v", version, level, got, err)
+			}
+		}
+	}
+}
+
+func TestDecodeScaledInvertedAndRotated(t *testing.T) {
+	code, err := qrcode.New("rotate me", qrcode.Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := code.Image(-5)
+
+	// 放在更大的彩色背景上并旋转 90 度
+	b := src.Bounds()
+	rotated := image.NewRGBA(image.Rect(0, 0, b.Dy()+60, b.Dx()+40))
+	draw.Draw(rotated, rotated.Bounds(), image.NewUniform(color.RGBA{R: 200, G: 220, B: 255, A: 255}), image.Point{}, draw.Src)
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			rotated.Set(30+b.Dy()-1-y, 20+x, src.At(x, y))
+		}
+	}
+	if got, err := Decode(rotated); err != nil || got != "rotate me" {
+		t.Errorf("rotated: %q, %v", got, err)
+	}
+
+	inverted := image.NewGray(b)
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			v := color.GrayModel.Convert(src.At(x, y)).(color.Gray)
+			inverted.SetGray(x, y, color.Gray{Y: 255 - v.Y})
+		}
+	}
+	if got, err := Decode(inverted); err != nil || got != "rotate me" {
+		t.Errorf("inverted: %q, %v", got, err)
+	}
+
+	if _, err := Decode(image.NewGray(image.Rect(0, 0, 50, 50))); err == nil {
+		t.Error("expected error for blank image")
+	}
+}
+
+func TestCorrect(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, ecc := range []int{7, 10, 22, 30} {
+		message := make([]byte, 40)
+		rng.Read(message)
+		block := append(message, rsRemainder(message, ecc)...)
+
+		damaged := append([]byte(nil), block...)
+		for _, pos := range rng.Perm(len(damaged))[:ecc/2] {
+			damaged[pos] ^= byte(rng.Intn(255) + 1)
+		}
+		if err := correct(damaged, ecc); err != nil || !bytes.Equal(damaged, block) {
+			t.Errorf("ecc %d: correct() = %v", ecc, err)
+		}
+
+		for _, pos := range rng.Perm(len(damaged))[:ecc] {
+			damaged[pos] ^= byte(rng.Intn(255) + 1)
+		}
+		if err := correct(damaged, ecc); err == nil && bytes.Equal(damaged, block) {
+			t.Errorf("ecc %d: expected uncorrectable block", ecc)
+		}
+	}
+}
+
+// rsRemainder 计算纠错码字，生成多项式的根为 α^0..α^(ecc-1)
+func rsRemainder(data []byte, ecc int) []byte {
+	generator := []byte{1}
+	for i := 0; i < ecc; i++ {
+		next := make([]byte, len(generator)+1)
+		for j, c := range generator {
+			next[j] ^= c
+			next[j+1] ^= gfMul(c, gfPow(i))
+		}
+		generator = next
+	}
+	remainder := make([]byte, ecc)
+	for _, b := range data {
+		factor := b ^ remainder[0]
+		copy(remainder, remainder[1:])
+		remainder[ecc-1] = 0
+		for j := 0; j < ecc; j++ {
+			remainder[j] ^= gfMul(generator[j+1], factor)
+		}
+	}
+	return remainder
+}
@@ -0,0 +1,142 @@
+package qr
+
+import "errors"
+
+// errTooManyErrors 错误数超过纠错能力
+var errTooManyErrors = errors.New("too many errors to correct")
+
+// GF(256) 运算表，本原多项式 x^8 + x^4 + x^3 + x^2 + 1
+var gfExp, gfLog = func() (exp [512]byte, log [256]byte) {
+	x := 1
+	for i := 0; i < 255; i++ {
+		exp[i] = byte(x)
+		log[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+	for i := 255; i < 512; i++ {
+		exp[i] = exp[i-255]
+	}
+	return exp, log
+}()
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+255-int(gfLog[b])]
+}
+
+func gfInv(a byte) byte {
+	return gfExp[255-int(gfLog[a])]
+}
+
+// gfPow α 的 n 次幂
+func gfPow(n int) byte {
+	return gfExp[((n%255)+255)%255]
+}
+
+// evalAscending 计算升幂排列的多项式在 x 处的值
+func evalAscending(poly []byte, x byte) byte {
+	var y byte
+	for i := len(poly) - 1; i >= 0; i-- {
+		y = gfMul(y, x) ^ poly[i]
+	}
+	return y
+}
+
+// correct 原地纠正码字中的错误，码字第一个字节为最高次项，ecc 为纠错码字数
+func correct(block []byte, ecc int) error {
+	n := len(block)
+	syndromes := make([]byte, ecc)
+	clean := true
+	for j := range syndromes {
+		var s byte
+		x := gfPow(j)
+		for _, b := range block {
+			s = gfMul(s, x) ^ b
+		}
+		syndromes[j] = s
+		if s != 0 {
+			clean = false
+		}
+	}
+	if clean {
+		return nil
+	}
+
+	// Berlekamp-Massey 求错误位置多项式 Λ(x)，系数按升幂排列
+	lambda, prev := []byte{1}, []byte{1}
+	length, shift, lastDelta := 0, 1, byte(1)
+	for k := 0; k < ecc; k++ {
+		delta := syndromes[k]
+		for i := 1; i <= length && i < len(lambda); i++ {
+			delta ^= gfMul(lambda[i], syndromes[k-i])
+		}
+		if delta == 0 {
+			shift++
+			continue
+		}
+		scale := gfDiv(delta, lastDelta)
+		next := make([]byte, max(len(lambda), len(prev)+shift))
+		copy(next, lambda)
+		for i, c := range prev {
+			next[i+shift] ^= gfMul(scale, c)
+		}
+		if 2*length <= k {
+			prev, length, lastDelta, shift = lambda, k+1-length, delta, 1
+		} else {
+			shift++
+		}
+		lambda = next
+	}
+	if 2*length > ecc {
+		return errTooManyErrors
+	}
+
+	// Chien 搜索：位置 i 的错误定位值为 α^(n-1-i)
+	var positions []int
+	var locators []byte
+	for i := 0; i < n; i++ {
+		x := gfPow(n - 1 - i)
+		if evalAscending(lambda, gfInv(x)) == 0 {
+			positions = append(positions, i)
+			locators = append(locators, x)
+		}
+	}
+	if len(positions) != length {
+		return errTooManyErrors
+	}
+
+	// Forney 算法求错误值：Ω(x) = S(x)Λ(x) mod x^ecc，e = X·Ω(X⁻¹)/Λ'(X⁻¹)
+	omega := make([]byte, ecc)
+	for i, s := range syndromes {
+		for j, l := range lambda {
+			if i+j < ecc {
+				omega[i+j] ^= gfMul(s, l)
+			}
+		}
+	}
+	derivative := make([]byte, len(lambda))
+	for i := 1; i < len(lambda); i += 2 {
+		derivative[i-1] = lambda[i]
+	}
+	for k, pos := range positions {
+		inv := gfInv(locators[k])
+		denominator := evalAscending(derivative, inv)
+		if denominator == 0 {
+			return errTooManyErrors
+		}
+		block[pos] ^= gfMul(locators[k], gfDiv(evalAscending(omega, inv), denominator))
+	}
+	return nil
+}
@@ -0,0 +1,470 @@
+// Package richtext 将粘贴的富文本（HTML、RTF）转换为 Markdown
+// 保留标题、列表、表格、链接和强调等结构，去除字体、颜色等样式
+package richtext
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	spaceRun     = regexp.MustCompile(`[ \t\r\n\f]+`)
+	blankLineRun = regexp.MustCompile(`\n{3,}`)
+)
+
+// HTMLToMarkdown 将 HTML 片段转换为 Markdown
+func HTMLToMarkdown(src string) string {
+	doc, err := html.Parse(strings.NewReader(src))
+	if err != nil {
+		return ""
+	}
+	var out []string
+	htmlBlocks(doc, &out)
+	return joinBlocks(out)
+}
+
+// joinBlocks 块之间以空行分隔
+func joinBlocks(out []string) string {
+	text := strings.Join(out, "\n\n")
+	text = blankLineRun.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// htmlBlocks 转换块级内容，连续的行内节点合并为一个段落
+func htmlBlocks(n *html.Node, out *[]string) {
+	var para strings.Builder
+	flush := func() {
+		if p := cleanParagraph(para.String()); p != "" {
+			*out = append(*out, p)
+		}
+		para.Reset()
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			para.WriteString(htmlInline(c))
+			continue
+		}
+		switch c.DataAtom {
+		case atom.Head, atom.Script, atom.Style, atom.Template, atom.Title, atom.Meta, atom.Link:
+		case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+			flush()
+			level := int(c.Data[1] - '0')
+			if text := strings.ReplaceAll(cleanParagraph(htmlInlineChildren(c)), "\n", " "); text != "" {
+				*out = append(*out, strings.Repeat("#", level)+" "+text)
+			}
+		case atom.P:
+			flush()
+			if p := cleanParagraph(htmlInlineChildren(c)); p != "" {
+				*out = append(*out, p)
+			}
+		case atom.Ul, atom.Ol:
+			flush()
+			if list := htmlList(c); list != "" {
+				*out = append(*out, list)
+			}
+		case atom.Pre:
+			flush()
+			*out = append(*out, htmlPre(c))
+		case atom.Blockquote:
+			flush()
+			var inner []string
+			htmlBlocks(c, &inner)
+			if len(inner) > 0 {
+				*out = append(*out, quote(joinBlocks(inner)))
+			}
+		case atom.Table:
+			flush()
+			if table := htmlTable(c); table != "" {
+				*out = append(*out, table)
+			}
+		case atom.Hr:
+			flush()
+			*out = append(*out, "---")
+		case atom.Html, atom.Body, atom.Div, atom.Section, atom.Article, atom.Main, atom.Header, atom.Footer,
+			atom.Nav, atom.Aside, atom.Figure, atom.Figcaption, atom.Dl, atom.Dt, atom.Dd, atom.Li,
+			atom.Tbody, atom.Thead, atom.Tfoot, atom.Tr, atom.Td, atom.Th, atom.Form, atom.Fieldset, atom.Details, atom.Summary:
+			flush()
+			htmlBlocks(c, out)
+		default:
+			para.WriteString(htmlInline(c))
+		}
+	}
+	flush()
+}
+
+// htmlInline 转换行内节点
+func htmlInline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return escapeText(spaceRun.ReplaceAllString(n.Data, " "))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Template, atom.Head, atom.Title:
+		return ""
+	case atom.Br:
+		return "\n"
+	case atom.Img:
+		src := attr(n, "src")
+		if src == "" || strings.HasPrefix(src, "data:") {
+			return ""
+		}
+		return "![" + escapeText(attr(n, "alt")) + "](" + src + ")"
+	case atom.A:
+		text := htmlInlineChildren(n)
+		href := attr(n, "href")
+		if href == "" || strings.HasPrefix(href, "javascript:") || strings.TrimSpace(text) == "" {
+			return text
+		}
+		return wrap(text, "[", "]("+href+")")
+	case atom.Strong, atom.B:
+		// Google 文档以 font-weight:normal 的 <b> 包裹全部内容
+		if n.DataAtom == atom.B && styleValue(n, "font-weight") == "normal" {
+			return htmlInlineChildren(n)
+		}
+		return wrap(htmlInlineChildren(n), "**", "**")
+	case atom.Em, atom.I, atom.Cite:
+		return wrap(htmlInlineChildren(n), "*", "*")
+	case atom.Del, atom.S, atom.Strike:
+		return wrap(htmlInlineChildren(n), "~~", "~~")
+	case atom.Code, atom.Kbd, atom.Samp, atom.Tt:
+		return inlineCode(spaceRun.ReplaceAllString(nodeText(n), " "))
+	case atom.Span:
+		text := htmlInlineChildren(n)
+		if weight := styleValue(n, "font-weight"); weight == "bold" || weight == "bolder" || weightAtLeast(weight, 600) {
+			text = wrap(text, "**", "**")
+		}
+		if styleValue(n, "font-style") == "italic" {
+			text = wrap(text, "*", "*")
+		}
+		return text
+	}
+	return htmlInlineChildren(n)
+}
+
+// htmlInlineChildren 转换所有子节点的行内内容，嵌套的块级元素按行内处理
+func htmlInlineChildren(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			switch c.DataAtom {
+			case atom.P, atom.Div, atom.Li, atom.Tr, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+				b.WriteString("\n" + htmlInlineChildren(c) + "\n")
+				continue
+			}
+		}
+		b.WriteString(htmlInline(c))
+	}
+	return b.String()
+}
+
+// htmlList 转换列表，嵌套列表和多段内容按标记宽度缩进
+func htmlList(n *html.Node) string {
+	ordered := n.DataAtom == atom.Ol
+	index := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil && ordered {
+		index = start
+	}
+
+	var items []string
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if c.DataAtom == atom.Ul || c.DataAtom == atom.Ol {
+			// 部分编辑器将嵌套列表直接放在列表中而不是列表项中
+			if nested := htmlList(c); nested != "" {
+				items = append(items, indent(nested, "  "))
+			}
+			continue
+		}
+		if c.DataAtom != atom.Li {
+			continue
+		}
+
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(index) + ". "
+			index++
+		}
+		var inner []string
+		htmlBlocks(c, &inner)
+		first, rest, _ := strings.Cut(strings.Join(inner, "\n"), "\n")
+		item := marker + first
+		if rest != "" {
+			item += "\n" + indent(rest, strings.Repeat(" ", len(marker)))
+		}
+		items = append(items, item)
+	}
+	return strings.Join(items, "\n")
+}
+
+// htmlPre 转换预格式化文本为围栏代码块，语言取自 language-xxx 或 lang-xxx 类名
+func htmlPre(n *html.Node) string {
+	lang := codeLanguage(n)
+	for c := n.FirstChild; c != nil && lang == ""; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.DataAtom == atom.Code {
+			lang = codeLanguage(c)
+		}
+	}
+	return fence(strings.TrimRight(nodeText(n), "\n"), lang)
+}
+
+// htmlTable 转换为 GFM 表格，第一行作为表头
+func htmlTable(n *html.Node) string {
+	var rows [][]string
+	var aligns []string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.DataAtom {
+			case atom.Thead, atom.Tbody, atom.Tfoot:
+				walk(c)
+			case atom.Tr:
+				var row []string
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type != html.ElementNode || (cell.DataAtom != atom.Td && cell.DataAtom != atom.Th) {
+						continue
+					}
+					if len(rows) == 0 {
+						aligns = append(aligns, cellAlign(cell))
+					}
+					var inner []string
+					htmlBlocks(cell, &inner)
+					row = append(row, strings.Join(inner, " "))
+				}
+				if len(row) > 0 {
+					rows = append(rows, row)
+				}
+			}
+		}
+	}
+	walk(n)
+	return gfmTable(rows, aligns)
+}
+
+// gfmTable 生成 GFM 表格，列数取最宽的一行
+func gfmTable(rows [][]string, aligns []string) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	cols := 0
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+
+	var b strings.Builder
+	writeRow := func(row []string) {
+		b.WriteString("|")
+		for i := 0; i < cols; i++ {
+			cell := ""
+			if i < len(row) {
+				cell = strings.ReplaceAll(row[i], "\n", "<br>")
+				cell = strings.ReplaceAll(cell, "|", `\|`)
+			}
+			b.WriteString(" " + strings.TrimSpace(cell) + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(rows[0])
+	b.WriteString("|")
+	for i := 0; i < cols; i++ {
+		align := ""
+		if i < len(aligns) {
+			align = aligns[i]
+		}
+		switch align {
+		case "center":
+			b.WriteString(" :---: |")
+		case "right":
+			b.WriteString(" ---: |")
+		case "left":
+			b.WriteString(" :--- |")
+		default:
+			b.WriteString(" --- |")
+		}
+	}
+	b.WriteString("\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// cellAlign 单元格对齐方式，取 align 属性或 text-align 样式
+func cellAlign(n *html.Node) string {
+	if align := strings.ToLower(attr(n, "align")); align != "" {
+		return align
+	}
+	return styleValue(n, "text-align")
+}
+
+// codeLanguage 从类名中取代码语言
+func codeLanguage(n *html.Node) string {
+	for _, class := range strings.Fields(attr(n, "class")) {
+		for _, prefix := range []string{"language-", "lang-"} {
+			if lang, ok := strings.CutPrefix(class, prefix); ok {
+				return lang
+			}
+		}
+	}
+	return ""
+}
+
+// nodeText 节点的原始文本，<br> 视为换行
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(n.Data)
+		case n.Type == html.ElementNode && n.DataAtom == atom.Br:
+			b.WriteString("\n")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// attr 取元素属性
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+// styleValue 取内联样式中某个属性的值
+func styleValue(n *html.Node, property string) string {
+	for _, decl := range strings.Split(attr(n, "style"), ";") {
+		key, value, ok := strings.Cut(decl, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), property) {
+			return strings.ToLower(strings.TrimSpace(value))
+		}
+	}
+	return ""
+}
+
+// weightAtLeast 数字字重是否不低于 min
+func weightAtLeast(weight string, min int) bool {
+	n, err := strconv.Atoi(weight)
+	return err == nil && n >= min
+}
+
+// cleanParagraph 合并段落中的空白，去掉每行首尾的空格
+func cleanParagraph(s string) string {
+	lines := strings.Split(s, "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		line = strings.TrimSpace(spaceRun.ReplaceAllString(line, " "))
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// wrap 用标记包裹文本，首尾空白留在标记外侧
+func wrap(text, open, close string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	lead := text[:strings.Index(text, trimmed)]
+	trail := text[len(lead)+len(trimmed):]
+	return lead + open + trimmed + close + trail
+}
+
+// inlineCode 生成行内代码，内容含反引号时加长定界符
+func inlineCode(text string) string {
+	if strings.TrimSpace(text) == "" {
+		return text
+	}
+	ticks := "`"
+	for strings.Contains(text, ticks) {
+		ticks += "`"
+	}
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		return ticks + " " + text + " " + ticks
+	}
+	return ticks + text + ticks
+}
+
+// fence 生成围栏代码块，内容含 ``` 时加长围栏
+func fence(code, lang string) string {
+	marker := "```"
+	for strings.Contains(code, marker) {
+		marker += "`"
+	}
+	return marker + lang + "\n" + code + "\n" + marker
+}
+
+// quote 为每行加上引用前缀
+func quote(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indent 为每个非空行加上缩进
+func indent(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// escapeText 转义文本中的 Markdown 标记字符，单词内部的下划线保持原样
+func escapeText(s string) string {
+	if !strings.ContainsAny(s, "\\*_`[]") {
+		return s
+	}
+	var b strings.Builder
+	for i, r := range s {
+		switch r {
+		case '\\', '*', '`', '[', ']':
+			b.WriteByte('\\')
+		case '_':
+			prev, _ := utf8.DecodeLastRuneInString(s[:i])
+			next, _ := utf8.DecodeRuneInString(s[i+1:])
+			if !isWordRune(prev) || !isWordRune(next) {
+				b.WriteByte('\\')
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,40 @@
+package richtext
+
+import "testing"
+
+func TestHTMLToMarkdown(t *testing.T) {
+	cases := map[string]string{
+		`<h2 style="color:red">Title</h2><p>Some <b>bold</b> and <em>italic</em> text.</p>`:              "## Title\n\nSome **bold** and *italic* text.",
+		`<p><a href="https://example.com">a <strong>link</strong></a></p>`:                               "[a **link**](https://example.com)",
+		`<ul><li>one</li><li>two<ul><li>nested</li></ul></li></ul>`:                                      "- one\n- two\n  - nested",
+		`<ol start="3"><li><p>first</p></li><li>second</li></ol>`:                                        "3. first\n4. second",
+		`<table><tr><th>Name</th><th align="right">Qty</th></tr><tr><td>a|b</td><td>2</td></tr></table>`: "| Name | Qty |\n| --- | ---: |\n| a\\|b | 2 |",
+		`<pre><code class="language-go">func main() {}
+</code></pre>`: "```go\nfunc main() {}\n```",
+		`<blockquote><p>quoted</p><p>twice</p></blockquote>`:                                      "> quoted\n>\n> twice",
+		`<style>p{color:red}</style><span style="font-weight:700">heavy</span> snake_case *star*`: "**heavy** snake_case \\*star\\*",
+		`<b style="font-weight:normal" id="docs-internal-guid"><span>plain</span></b>`:            "plain",
+		`<p>line<br>break</p><hr><p>after <code>x</code></p>`:                                     "line\nbreak\n\n---\n\nafter `x`",
+	}
+	for in, want := range cases {
+		if got := HTMLToMarkdown(in); got != want {
+			t.Errorf("HTMLToMarkdown(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRTFToMarkdown(t *testing.T) {
+	cases := map[string]string{
+		`{\rtf1\ansi{\fonttbl{\f0 Arial;}}{\colortbl;\red255\green0\blue0;}\f0 Hello {\b bold} and {\i italic}.\par Next\par}`: "Hello **bold** and *italic*.\n\nNext",
+		`{\rtf1\ansi\ansicpg936 \'d6\'d0\'ce\'c4 \u8364?\par}`:                                                                 "中文 €",
+		`{\rtf1{\listtext\'b7\tab}one\par{\listtext\'b7\tab}two\par{\listtext 1.\tab}first\par}`:                               "- one\n- two\n1. first",
+		`{\rtf1\trowd\cellx1000\cellx2000\pard\intbl A\cell B\cell\row\trowd\pard\intbl 1\cell 2\cell\row\pard after\par}`:     "| A | B |\n| --- | --- |\n| 1 | 2 |\n\nafter",
+		`{\rtf1{\field{\*\fldinst{HYPERLINK "https://example.com"}}{\fldrslt{\ul site}}}\par}`:                                 "[site](https://example.com)",
+		`{\rtf1 {\*\generator Riched20;}a\{b\}\\c\par}`:                                                                        `a{b}\\c`,
+	}
+	for in, want := range cases {
+		if got := RTFToMarkdown(in); got != want {
+			t.Errorf("RTFToMarkdown(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
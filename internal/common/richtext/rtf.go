@@ -0,0 +1,427 @@
+package richtext
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// rtfSkipDestinations 不包含正文的目标组
+var rtfSkipDestinations = map[string]bool{
+	"fonttbl": true, "colortbl": true, "stylesheet": true, "info": true, "pict": true, "object": true,
+	"header": true, "headerl": true, "headerr": true, "headerf": true,
+	"footer": true, "footerl": true, "footerr": true, "footerf": true,
+	"footnote": true, "listtable": true, "listoverridetable": true,
+	"rsidtbl": true, "generator": true, "themedata": true, "colorschememapping": true,
+	"latentstyles": true, "datastore": true, "xmlnstbl": true, "pn": true, "revtbl": true,
+}
+
+// rtfSymbols 表示单个字符的控制字
+var rtfSymbols = map[string]string{
+	"tab": "\t", "emdash": "—", "endash": "–", "bullet": "•", "lquote": "‘", "rquote": "’",
+	"ldblquote": "“", "rdblquote": "”", "emspace": " ", "enspace": " ", "qmspace": " ",
+}
+
+// rtfGroup 组内生效的字符格式
+type rtfGroup struct {
+	bold, italic, strike bool
+	skip                 bool   // 忽略组内文本
+	marker               bool   // 列表项编号组（\listtext、\pntext）
+	fieldInst            bool   // 域指令组，超链接的地址在其中
+	link                 string // 域结果组所属的超链接地址
+	uc                   int    // \u 之后需跳过的替代字符数
+}
+
+// rtfRun 格式相同的一段文本
+type rtfRun struct {
+	text                 string
+	bold, italic, strike bool
+	link                 string
+}
+
+// rtfConverter RTF 到 Markdown 的转换状态
+type rtfConverter struct {
+	stack    []rtfGroup
+	group    rtfGroup
+	decoder  *encoding.Decoder
+	pending  []byte // 尚未解码的 \'hh 字节，多字节编码需要合并解码
+	skipNext int    // \u 之后剩余需跳过的替代字符数
+
+	runs      []rtfRun
+	marker    strings.Builder
+	fieldInst strings.Builder
+	level     int
+	inTbl     bool
+
+	cells  []string
+	rows   [][]string
+	blocks []string
+}
+
+// RTFToMarkdown 将 RTF 文档转换为 Markdown，保留段落、粗斜体、列表和表格
+func RTFToMarkdown(src string) string {
+	c := &rtfConverter{group: rtfGroup{uc: 1}, decoder: charmap.Windows1252.NewDecoder()}
+	c.parse(src)
+	c.endParagraph()
+	c.flushTable()
+	return joinRTFBlocks(c.blocks)
+}
+
+// parse 逐个处理控制字、组和文本
+func (c *rtfConverter) parse(src string) {
+	for i := 0; i < len(src); {
+		ch := src[i]
+		switch ch {
+		case '{':
+			c.flushBytes()
+			c.stack = append(c.stack, c.group)
+			i++
+			// {\*\dest ...} 为可忽略的目标组，不认识时跳过
+			if strings.HasPrefix(src[i:], `\*`) {
+				c.group.skip = true
+				i += 2
+			}
+		case '}':
+			c.flushBytes()
+			if n := len(c.stack); n > 0 {
+				c.group = c.stack[n-1]
+				c.stack = c.stack[:n-1]
+			}
+			i++
+		case '\\':
+			i = c.control(src, i+1)
+		case '\r', '\n':
+			i++
+		default:
+			c.flushBytes()
+			start := i
+			for i < len(src) && src[i] != '{' && src[i] != '}' && src[i] != '\\' && src[i] != '\r' && src[i] != '\n' {
+				i++
+			}
+			c.text(src[start:i])
+		}
+	}
+}
+
+// control 处理反斜杠之后的控制字或控制符，返回下一个位置
+func (c *rtfConverter) control(src string, i int) int {
+	if i >= len(src) {
+		return i
+	}
+	ch := src[i]
+	if !isASCIILetter(ch) {
+		switch ch {
+		case '\'':
+			if i+3 <= len(src) {
+				if b, err := strconv.ParseUint(src[i+1:i+3], 16, 8); err == nil {
+					if c.skipNext > 0 {
+						c.skipNext--
+					} else if !c.group.skip {
+						c.pending = append(c.pending, byte(b))
+					}
+				}
+			}
+			return i + 3
+		case '~':
+			c.text(" ")
+		case '_':
+			c.text("‑")
+		case '{', '}', '\\':
+			c.text(string(ch))
+		case '\n', '\r':
+			c.endParagraph()
+		}
+		return i + 1
+	}
+
+	start := i
+	for i < len(src) && isASCIILetter(src[i]) {
+		i++
+	}
+	word := src[start:i]
+	numStart := i
+	if i < len(src) && src[i] == '-' {
+		i++
+	}
+	for i < len(src) && src[i] >= '0' && src[i] <= '9' {
+		i++
+	}
+	param, hasParam := 0, i > numStart
+	if hasParam {
+		param, _ = strconv.Atoi(src[numStart:i])
+	}
+	if i < len(src) && src[i] == ' ' {
+		i++
+	}
+
+	c.flushBytes()
+	c.word(word, param, hasParam)
+	return i
+}
+
+// word 处理控制字
+func (c *rtfConverter) word(word string, param int, hasParam bool) {
+	on := !hasParam || param != 0
+	switch word {
+	case "ansicpg":
+		c.decoder = codePageDecoder(param)
+	case "par", "sect":
+		c.endParagraph()
+	case "line":
+		c.text("\n")
+	case "pard":
+		c.inTbl = false
+		c.level = 0
+	case "intbl":
+		c.inTbl = true
+	case "ilvl":
+		c.level = param
+	case "cell":
+		c.endCell()
+	case "row":
+		c.endRow()
+	case "b":
+		c.group.bold = on
+	case "i":
+		c.group.italic = on
+	case "strike":
+		c.group.strike = on
+	case "plain":
+		c.group.bold, c.group.italic, c.group.strike = false, false, false
+	case "uc":
+		c.group.uc = param
+	case "u":
+		if param < 0 {
+			param += 65536
+		}
+		c.text(string(rune(param)))
+		c.skipNext = c.group.uc
+	case "listtext", "pntext":
+		c.group.marker = true
+	case "fldinst":
+		c.group.skip = false
+		c.group.fieldInst = true
+		c.fieldInst.Reset()
+	case "fldrslt":
+		c.group.link = hyperlinkTarget(c.fieldInst.String())
+	default:
+		if skip, ok := rtfSkipDestinations[word]; ok && skip {
+			c.group.skip = true
+		} else if s, ok := rtfSymbols[word]; ok {
+			c.text(s)
+		}
+	}
+}
+
+// text 追加正文，跳过 \u 之后的替代字符
+func (c *rtfConverter) text(s string) {
+	if c.skipNext > 0 {
+		n := min(c.skipNext, len(s))
+		c.skipNext -= n
+		s = s[n:]
+	}
+	if s == "" {
+		return
+	}
+	if c.group.fieldInst {
+		c.fieldInst.WriteString(s)
+		return
+	}
+	if c.group.skip {
+		return
+	}
+	if c.group.marker {
+		c.marker.WriteString(s)
+		return
+	}
+	if n := len(c.runs); n > 0 {
+		last := &c.runs[n-1]
+		if last.bold == c.group.bold && last.italic == c.group.italic && last.strike == c.group.strike && last.link == c.group.link {
+			last.text += s
+			return
+		}
+	}
+	c.runs = append(c.runs, rtfRun{text: s, bold: c.group.bold, italic: c.group.italic, strike: c.group.strike, link: c.group.link})
+}
+
+// flushBytes 按当前代码页解码累积的字节
+func (c *rtfConverter) flushBytes() {
+	if len(c.pending) == 0 {
+		return
+	}
+	pending := c.pending
+	c.pending = nil
+	decoded, err := c.decoder.Bytes(pending)
+	if err != nil {
+		decoded = pending
+	}
+	c.text(string(decoded))
+}
+
+// paragraph 渲染当前段落的文本并清空
+func (c *rtfConverter) paragraph() string {
+	var b, link strings.Builder
+	for i, run := range c.runs {
+		text := escapeText(run.text)
+		if run.strike {
+			text = wrap(text, "~~", "~~")
+		}
+		if run.italic {
+			text = wrap(text, "*", "*")
+		}
+		if run.bold {
+			text = wrap(text, "**", "**")
+		}
+		if run.link == "" {
+			b.WriteString(text)
+			continue
+		}
+		// 同一超链接内格式不同的多段文本合并为一个链接
+		link.WriteString(text)
+		if i+1 == len(c.runs) || c.runs[i+1].link != run.link {
+			b.WriteString(wrap(link.String(), "[", "]("+run.link+")"))
+			link.Reset()
+		}
+	}
+	c.runs = nil
+	return cleanParagraph(strings.ReplaceAll(b.String(), "\t", " "))
+}
+
+// endParagraph 结束段落，表格内的段落作为单元格中的换行
+func (c *rtfConverter) endParagraph() {
+	if c.inTbl {
+		c.text("\n")
+		return
+	}
+	c.flushTable()
+
+	marker := strings.TrimSpace(c.marker.String())
+	c.marker.Reset()
+	text := c.paragraph()
+	if text == "" {
+		return
+	}
+	if marker != "" {
+		prefix := "- "
+		if digits := strings.TrimRight(marker, ".)"); digits != "" && isDigits(digits) {
+			prefix = digits + ". "
+		}
+		text = strings.Repeat("  ", c.level) + prefix + text
+	}
+	c.blocks = append(c.blocks, text)
+}
+
+// endCell 结束单元格
+func (c *rtfConverter) endCell() {
+	c.cells = append(c.cells, c.paragraph())
+	c.marker.Reset()
+}
+
+// endRow 结束表格行
+func (c *rtfConverter) endRow() {
+	if len(c.runs) > 0 {
+		c.endCell()
+	}
+	if len(c.cells) > 0 {
+		c.rows = append(c.rows, c.cells)
+	}
+	c.cells = nil
+}
+
+// flushTable 输出已收集的表格
+func (c *rtfConverter) flushTable() {
+	if len(c.rows) == 0 {
+		return
+	}
+	c.blocks = append(c.blocks, gfmTable(c.rows, nil))
+	c.rows = nil
+}
+
+// joinRTFBlocks 段落之间以空行分隔，相邻的列表项和表格行保持紧凑
+func joinRTFBlocks(blocks []string) string {
+	var b strings.Builder
+	for i, block := range blocks {
+		if i > 0 {
+			if isListItem(blocks[i-1]) && isListItem(block) {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+		b.WriteString(block)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// isListItem 是否为列表项
+func isListItem(block string) bool {
+	line := strings.TrimLeft(block, " ")
+	if strings.HasPrefix(line, "- ") {
+		return true
+	}
+	num, _, ok := strings.Cut(line, ". ")
+	return ok && isDigits(num)
+}
+
+// hyperlinkTarget 从 HYPERLINK "url" 域指令中取链接地址
+func hyperlinkTarget(inst string) string {
+	fields := strings.Fields(inst)
+	if len(fields) < 2 || !strings.EqualFold(fields[0], "HYPERLINK") {
+		return ""
+	}
+	target := fields[1]
+	if target == `\l` && len(fields) > 2 {
+		target = "#" + strings.Trim(fields[2], `"`)
+	}
+	return strings.Trim(target, `"`)
+}
+
+// codePageDecoder \ansicpg 对应的解码器，不支持的代码页按 Windows-1252 处理
+func codePageDecoder(cp int) *encoding.Decoder {
+	switch cp {
+	case 936:
+		return simplifiedchinese.GBK.NewDecoder()
+	case 950:
+		return traditionalchinese.Big5.NewDecoder()
+	case 932:
+		return japanese.ShiftJIS.NewDecoder()
+	case 949:
+		return korean.EUCKR.NewDecoder()
+	case 874:
+		return charmap.Windows874.NewDecoder()
+	case 1250:
+		return charmap.Windows1250.NewDecoder()
+	case 1251:
+		return charmap.Windows1251.NewDecoder()
+	case 1253:
+		return charmap.Windows1253.NewDecoder()
+	case 1254:
+		return charmap.Windows1254.NewDecoder()
+	case 1255:
+		return charmap.Windows1255.NewDecoder()
+	case 1256:
+		return charmap.Windows1256.NewDecoder()
+	case 1257:
+		return charmap.Windows1257.NewDecoder()
+	case 1258:
+		return charmap.Windows1258.NewDecoder()
+	case 10000:
+		return charmap.Macintosh.NewDecoder()
+	}
+	return charmap.Windows1252.NewDecoder()
+}
+
+func isASCIILetter(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
+
+func isDigits(s string) bool {
+	return s != "" && strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) }) < 0
+}
@@ -0,0 +1,194 @@
+package secret
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FindingType 敏感信息类型
+type FindingType string
+
+const (
+	TypeAWSAccessKey FindingType = "aws_access_key"
+	TypeAWSSecretKey FindingType = "aws_secret_key"
+	TypePrivateKey   FindingType = "private_key"
+	TypeJWT          FindingType = "jwt"
+	TypeGithubToken  FindingType = "github_token"
+	TypeSlackToken   FindingType = "slack_token"
+	TypeGoogleAPIKey FindingType = "google_api_key"
+	TypeStripeKey    FindingType = "stripe_key"
+	TypePassword     FindingType = "password_assignment"
+	TypeHighEntropy  FindingType = "high_entropy"
+)
+
+// Finding 检测到的疑似敏感信息
+type Finding struct {
+	Type    FindingType `json:"type"`    // 类型
+	Line    int         `json:"line"`    // 所在行（从1开始）
+	Start   int         `json:"start"`   // 起始字节偏移
+	End     int         `json:"end"`     // 结束字节偏移
+	Preview string      `json:"preview"` // 已脱敏的预览
+	Entropy float64     `json:"entropy"` // 香农熵（比特/字符）
+}
+
+// rule 检测规则
+type rule struct {
+	findingType FindingType
+	pattern     *regexp.Regexp
+	group       int     // 作为敏感值的捕获组，0表示整个匹配
+	minEntropy  float64 // 敏感值的最小熵，0表示不检查
+}
+
+var rules = []rule{
+	{findingType: TypePrivateKey, pattern: regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----[\s\S]*?(?:-----END (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----|$)`)},
+	{findingType: TypeAWSAccessKey, pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA)[0-9A-Z]{16}\b`)},
+	{findingType: TypeAWSSecretKey, pattern: regexp.MustCompile(`(?i)aws.{0,20}?(?:secret|private).{0,20}?[:=]\s*["']?([0-9a-zA-Z/+]{40})\b`), group: 1, minEntropy: 3.5},
+	{findingType: TypeJWT, pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+	{findingType: TypeGithubToken, pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{60,})\b`)},
+	{findingType: TypeSlackToken, pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`)},
+	{findingType: TypeGoogleAPIKey, pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{findingType: TypeStripeKey, pattern: regexp.MustCompile(`\b(?:sk|rk)_live_[0-9a-zA-Z]{24,}\b`)},
+	{findingType: TypePassword, pattern: regexp.MustCompile(`(?i)(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key)["']?\s*[:=]\s*["']?([^\s"']{8,})`), group: 1, minEntropy: 3.0},
+}
+
+// highEntropyPattern 长度足够的类Base64/Hex片段，作为兜底检测
+var highEntropyPattern = regexp.MustCompile(`[A-Za-z0-9+/_=-]{32,}`)
+
+// highEntropyThreshold 兜底检测的熵阈值
+const highEntropyThreshold = 4.5
+
+// Scan 扫描文本中的疑似敏感信息，结果按位置排序且互不重叠
+func Scan(content string) []Finding {
+	var findings []Finding
+
+	for _, r := range rules {
+		for _, m := range r.pattern.FindAllStringSubmatchIndex(content, -1) {
+			start, end := m[0], m[1]
+			if r.group > 0 {
+				if m[2*r.group] < 0 {
+					continue
+				}
+				start, end = m[2*r.group], m[2*r.group+1]
+			}
+			value := content[start:end]
+			entropy := ShannonEntropy(value)
+			if r.minEntropy > 0 && entropy < r.minEntropy {
+				continue
+			}
+			findings = appendIfFree(findings, Finding{
+				Type:    r.findingType,
+				Start:   start,
+				End:     end,
+				Entropy: entropy,
+			})
+		}
+	}
+
+	for _, m := range highEntropyPattern.FindAllStringIndex(content, -1) {
+		value := content[m[0]:m[1]]
+		entropy := ShannonEntropy(value)
+		if entropy < highEntropyThreshold || !mixedCharset(value) {
+			continue
+		}
+		findings = appendIfFree(findings, Finding{
+			Type:    TypeHighEntropy,
+			Start:   m[0],
+			End:     m[1],
+			Entropy: entropy,
+		})
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].Start < findings[j].Start
+	})
+
+	for i := range findings {
+		findings[i].Line = strings.Count(content[:findings[i].Start], "\n") + 1
+		findings[i].Preview = maskValue(content[findings[i].Start:findings[i].End])
+		findings[i].Entropy = math.Round(findings[i].Entropy*100) / 100
+	}
+
+	return findings
+}
+
+// Mask 将文本中检测到的敏感信息替换为脱敏形式
+func Mask(content string) string {
+	findings := Scan(content)
+	if len(findings) == 0 {
+		return content
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(content))
+	last := 0
+	for _, f := range findings {
+		builder.WriteString(content[last:f.Start])
+		builder.WriteString(maskValue(content[f.Start:f.End]))
+		last = f.End
+	}
+	builder.WriteString(content[last:])
+	return builder.String()
+}
+
+// ShannonEntropy 计算字符串的香农熵（比特/字符）
+func ShannonEntropy(value string) float64 {
+	if value == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range value {
+		counts[r]++
+		total++
+	}
+
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// appendIfFree 仅在与已有结果不重叠时追加，优先保留更具体的规则
+func appendIfFree(findings []Finding, f Finding) []Finding {
+	for _, existing := range findings {
+		if f.Start < existing.End && existing.Start < f.End {
+			return findings
+		}
+	}
+	return append(findings, f)
+}
+
+// mixedCharset 同时包含字母和数字，排除长单词、分隔线等误报
+func mixedCharset(value string) bool {
+	var hasLetter, hasDigit bool
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		case (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+			hasLetter = true
+		}
+	}
+	return hasLetter && hasDigit
+}
+
+// maskValue 保留前4个字符，其余替换为星号；多行内容（如私钥）仅保留首尾行
+func maskValue(value string) string {
+	if strings.Contains(value, "\n") {
+		lines := strings.Split(strings.TrimRight(value, "\n"), "\n")
+		masked := lines[0] + "\n[REDACTED]"
+		if last := lines[len(lines)-1]; len(lines) > 1 && strings.HasPrefix(last, "-----END") {
+			masked += "\n" + last
+		}
+		return masked
+	}
+	if len(value) <= 8 {
+		return strings.Repeat("*", len(value))
+	}
+	return value[:4] + strings.Repeat("*", min(len(value)-4, 16))
+}
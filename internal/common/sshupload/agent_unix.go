@@ -0,0 +1,18 @@
+//go:build !windows
+
+package sshupload
+
+import (
+	"errors"
+	"net"
+	"os"
+)
+
+// dialAgent 连接 SSH_AUTH_SOCK 指向的 agent
+func dialAgent() (net.Conn, error) {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, errors.New("SSH_AUTH_SOCK is not set")
+	}
+	return net.Dial("unix", socket)
+}
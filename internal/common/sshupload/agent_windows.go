@@ -0,0 +1,25 @@
+//go:build windows
+
+package sshupload
+
+import (
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Microsoft/go-winio"
+)
+
+// openSSHAgentPipe Windows OpenSSH agent 的命名管道
+const openSSHAgentPipe = `\\.\pipe\openssh-ssh-agent`
+
+// dialAgent 连接 Windows OpenSSH agent，SSH_AUTH_SOCK 指向命名管道时优先使用
+func dialAgent() (net.Conn, error) {
+	pipe := openSSHAgentPipe
+	if socket := os.Getenv("SSH_AUTH_SOCK"); strings.HasPrefix(socket, `\\.\pipe\`) {
+		pipe = socket
+	}
+	timeout := 5 * time.Second
+	return winio.DialPipe(pipe, &timeout)
+}
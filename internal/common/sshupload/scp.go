@@ -0,0 +1,77 @@
+package sshupload
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// scpSend 按 SCP 协议（scp -t）发送单个文件
+func scpSend(w io.WriteCloser, r io.Reader, remotePath string, content io.Reader, size int64, progress func(written int64)) error {
+	acks := bufio.NewReader(r)
+	if err := scpAck(acks); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "C0644 %d %s\n", size, path.Base(remotePath)); err != nil {
+		return err
+	}
+	if err := scpAck(acks); err != nil {
+		return err
+	}
+
+	written, err := io.Copy(w, &progressReader{r: io.LimitReader(content, size), progress: progress})
+	if err != nil {
+		return err
+	}
+	if written != size {
+		return fmt.Errorf("short read: %d of %d bytes", written, size)
+	}
+	if _, err := w.Write([]byte{0}); err != nil {
+		return err
+	}
+	if err := scpAck(acks); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// scpAck 读取远端应答，非零时附带错误信息
+func scpAck(r *bufio.Reader) error {
+	code, err := r.ReadByte()
+	if err != nil {
+		return fmt.Errorf("scp: %w", err)
+	}
+	if code == 0 {
+		return nil
+	}
+	message, _ := r.ReadString('\n')
+	message = strings.TrimSpace(message)
+	if message == "" {
+		message = "remote error"
+	}
+	return errors.New("scp: " + message)
+}
+
+// shellQuote 用单引号包裹参数，供远端 shell 解析
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// progressReader 读取时报告累计字节数
+type progressReader struct {
+	r        io.Reader
+	read     int64
+	progress func(written int64)
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	pr.read += int64(n)
+	if n > 0 && pr.progress != nil {
+		pr.progress(pr.read)
+	}
+	return n, err
+}
@@ -0,0 +1,218 @@
+package sshupload
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// SFTP 协议第 3 版中用到的报文类型和标志
+const (
+	sftpInit    = 1
+	sftpVersion = 2
+	sftpOpen    = 3
+	sftpClose   = 4
+	sftpWrite   = 6
+	sftpStatus  = 101
+	sftpHandle  = 102
+
+	sftpFlagWrite = 0x02
+	sftpFlagCreat = 0x08
+	sftpFlagTrunc = 0x10
+
+	sftpAttrPermissions = 0x04
+
+	// sftpChunkSize 单次写入的数据量，OpenSSH 接受的上限为 256KB
+	sftpChunkSize = 32 * 1024
+	// sftpMaxPacket 接收报文的长度上限
+	sftpMaxPacket = 256 * 1024
+)
+
+// sftpStatusError SFTP 服务端返回的错误状态
+type sftpStatusError struct {
+	Code    uint32
+	Message string
+}
+
+func (e *sftpStatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("sftp: %s (code %d)", e.Message, e.Code)
+	}
+	return fmt.Sprintf("sftp: status %d", e.Code)
+}
+
+// sftpConn 最小的 SFTP 客户端，按顺序收发请求
+type sftpConn struct {
+	w  io.Writer
+	r  io.Reader
+	id uint32
+}
+
+// sftpSend 通过 SFTP 写入单个文件，已存在时覆盖
+func sftpSend(w io.WriteCloser, r io.Reader, remotePath string, content io.Reader, progress func(written int64)) error {
+	conn := &sftpConn{w: w, r: r}
+	if err := conn.init(); err != nil {
+		return err
+	}
+
+	// 打开文件：写入、创建、截断，权限 0644
+	open := appendString(nil, remotePath)
+	open = binary.BigEndian.AppendUint32(open, sftpFlagWrite|sftpFlagCreat|sftpFlagTrunc)
+	open = binary.BigEndian.AppendUint32(open, sftpAttrPermissions)
+	open = binary.BigEndian.AppendUint32(open, 0644)
+	typ, payload, err := conn.request(sftpOpen, open)
+	if err != nil {
+		return err
+	}
+	if typ != sftpHandle {
+		return unexpectedPacket(typ, payload)
+	}
+	handle, _, ok := readString(payload)
+	if !ok {
+		return errors.New("sftp: malformed handle")
+	}
+
+	buf := make([]byte, sftpChunkSize)
+	var offset int64
+	for {
+		n, readErr := content.Read(buf)
+		if n > 0 {
+			write := appendString(nil, string(handle))
+			write = binary.BigEndian.AppendUint64(write, uint64(offset))
+			write = appendString(write, string(buf[:n]))
+			if err := conn.expectOK(sftpWrite, write); err != nil {
+				_ = conn.expectOK(sftpClose, appendString(nil, string(handle)))
+				return err
+			}
+			offset += int64(n)
+			if progress != nil {
+				progress(offset)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			_ = conn.expectOK(sftpClose, appendString(nil, string(handle)))
+			return readErr
+		}
+	}
+
+	if err := conn.expectOK(sftpClose, appendString(nil, string(handle))); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// init 协商协议版本
+func (c *sftpConn) init() error {
+	if err := c.writePacket(sftpInit, binary.BigEndian.AppendUint32(nil, 3)); err != nil {
+		return err
+	}
+	typ, payload, err := c.readPacket()
+	if err != nil {
+		return err
+	}
+	if typ != sftpVersion || len(payload) < 4 {
+		return fmt.Errorf("sftp: unexpected init response %d", typ)
+	}
+	if version := binary.BigEndian.Uint32(payload); version < 3 {
+		return fmt.Errorf("sftp: unsupported version %d", version)
+	}
+	return nil
+}
+
+// request 发送带请求 ID 的报文并读取对应的响应，返回值不含请求 ID
+func (c *sftpConn) request(typ byte, body []byte) (byte, []byte, error) {
+	c.id++
+	if err := c.writePacket(typ, append(binary.BigEndian.AppendUint32(nil, c.id), body...)); err != nil {
+		return 0, nil, err
+	}
+	respType, payload, err := c.readPacket()
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(payload) < 4 || binary.BigEndian.Uint32(payload) != c.id {
+		return 0, nil, errors.New("sftp: response id mismatch")
+	}
+	return respType, payload[4:], nil
+}
+
+// expectOK 发送请求并要求返回成功状态
+func (c *sftpConn) expectOK(typ byte, body []byte) error {
+	respType, payload, err := c.request(typ, body)
+	if err != nil {
+		return err
+	}
+	if respType != sftpStatus {
+		return unexpectedPacket(respType, payload)
+	}
+	return statusError(payload)
+}
+
+// writePacket 写入长度前缀的报文
+func (c *sftpConn) writePacket(typ byte, payload []byte) error {
+	packet := binary.BigEndian.AppendUint32(make([]byte, 0, 5+len(payload)), uint32(1+len(payload)))
+	packet = append(packet, typ)
+	_, err := c.w.Write(append(packet, payload...))
+	return err
+}
+
+// readPacket 读取一个报文
+func (c *sftpConn) readPacket() (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(c.r, header[:]); err != nil {
+		return 0, nil, fmt.Errorf("sftp: %w", err)
+	}
+	length := binary.BigEndian.Uint32(header[:4])
+	if length < 1 || length > sftpMaxPacket {
+		return 0, nil, fmt.Errorf("sftp: invalid packet length %d", length)
+	}
+	payload := make([]byte, length-1)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return 0, nil, fmt.Errorf("sftp: %w", err)
+	}
+	return header[4], payload, nil
+}
+
+// statusError 解析状态报文，成功时返回 nil
+func statusError(payload []byte) error {
+	if len(payload) < 4 {
+		return errors.New("sftp: malformed status")
+	}
+	code := binary.BigEndian.Uint32(payload)
+	if code == 0 {
+		return nil
+	}
+	message, _, _ := readString(payload[4:])
+	return &sftpStatusError{Code: code, Message: string(message)}
+}
+
+// unexpectedPacket 响应类型不符，状态报文转为对应错误
+func unexpectedPacket(typ byte, payload []byte) error {
+	if typ == sftpStatus {
+		if err := statusError(payload); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("sftp: unexpected response %d", typ)
+}
+
+// appendString 追加长度前缀的字符串
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// readString 读取长度前缀的字符串
+func readString(b []byte) ([]byte, []byte, bool) {
+	if len(b) < 4 {
+		return nil, b, false
+	}
+	n := binary.BigEndian.Uint32(b)
+	if uint64(len(b)-4) < uint64(n) {
+		return nil, b, false
+	}
+	return b[4 : 4+n], b[4+n:], true
+}
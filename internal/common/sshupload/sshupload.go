@@ -0,0 +1,200 @@
+// Package sshupload 通过 SSH 将文件上传到远程主机，支持 SFTP 和 SCP
+// 认证使用 SSH agent 中的密钥，主机密钥按 known_hosts 校验
+package sshupload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Protocol 上传协议
+type Protocol string
+
+const (
+	ProtocolSFTP Protocol = "sftp"
+	ProtocolSCP  Protocol = "scp"
+)
+
+// DefaultPort SSH 默认端口
+const DefaultPort = 22
+
+// dialTimeout 建立连接和握手的超时时间
+const dialTimeout = 20 * time.Second
+
+// ErrNoAgent 未找到 SSH agent
+var ErrNoAgent = errors.New("ssh agent is not available")
+
+// Target 远程主机
+type Target struct {
+	Host       string
+	Port       int
+	User       string
+	KnownHosts string // known_hosts 路径，为空时使用 ~/.ssh/known_hosts
+}
+
+// addr 主机地址
+func (t Target) addr() string {
+	port := t.Port
+	if port <= 0 {
+		port = DefaultPort
+	}
+	return net.JoinHostPort(t.Host, strconv.Itoa(port))
+}
+
+// Dial 连接远程主机，使用 agent 中的密钥认证
+func Dial(ctx context.Context, target Target) (*ssh.Client, error) {
+	if target.Host == "" || target.User == "" {
+		return nil, errors.New("host and user are required")
+	}
+
+	hostKeyCallback, err := knownHostsCallback(target.KnownHosts)
+	if err != nil {
+		return nil, err
+	}
+	agentConn, err := dialAgent()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoAgent, err)
+	}
+	defer agentConn.Close()
+
+	config := &ssh.ClientConfig{
+		User:            target.User,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         dialTimeout,
+	}
+	client, err := dial(ctx, target.addr(), config)
+
+	// known_hosts 中只记录了其他类型的主机密钥时，按已记录的类型重新协商
+	var keyErr *knownhosts.KeyError
+	if errors.As(err, &keyErr) && len(keyErr.Want) > 0 {
+		config.HostKeyAlgorithms = hostKeyAlgorithms(keyErr.Want)
+		client, err = dial(ctx, target.addr(), config)
+	}
+	if err != nil {
+		if errors.As(err, &keyErr) {
+			if len(keyErr.Want) == 0 {
+				return nil, fmt.Errorf("host %s is not in known_hosts", target.Host)
+			}
+			return nil, fmt.Errorf("host key mismatch for %s", target.Host)
+		}
+		return nil, err
+	}
+	return client, nil
+}
+
+// dial 建立 TCP 连接并完成 SSH 握手，握手可被 ctx 取消
+func dial(ctx context.Context, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		conn.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// knownHostsCallback 按 known_hosts 校验主机密钥
+func knownHostsCallback(path string) (ssh.HostKeyCallback, error) {
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	callback, err := knownhosts.New(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known_hosts: %w", err)
+	}
+	return callback, nil
+}
+
+// hostKeyAlgorithms known_hosts 中已记录密钥对应的签名算法
+func hostKeyAlgorithms(keys []knownhosts.KnownKey) []string {
+	var algorithms []string
+	for _, key := range keys {
+		if key.Key.Type() == ssh.KeyAlgoRSA {
+			algorithms = append(algorithms, ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256)
+		}
+		algorithms = append(algorithms, key.Key.Type())
+	}
+	return algorithms
+}
+
+// Upload 将 r 中 size 字节的内容上传到 remotePath，progress 报告已上传的字节数
+func Upload(ctx context.Context, client *ssh.Client, protocol Protocol, remotePath string, r io.Reader, size int64, progress func(written int64)) error {
+	session, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	stop := context.AfterFunc(ctx, func() { session.Close() })
+	defer stop()
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	r = &contextReader{ctx: ctx, r: r}
+
+	switch protocol {
+	case ProtocolSCP:
+		if err := session.Start("scp -t " + shellQuote(remotePath)); err != nil {
+			return fmt.Errorf("failed to start scp: %w", err)
+		}
+		err = scpSend(stdin, stdout, remotePath, r, size, progress)
+	case ProtocolSFTP, "":
+		if err := session.RequestSubsystem("sftp"); err != nil {
+			return fmt.Errorf("failed to start sftp: %w", err)
+		}
+		err = sftpSend(stdin, stdout, remotePath, r, progress)
+	default:
+		return fmt.Errorf("unsupported protocol: %s", protocol)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// contextReader 读取时检查 ctx 是否已取消
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
@@ -0,0 +1,148 @@
+package sshupload
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// pipeEnd 测试用的一端连接，关闭时结束写入
+type pipeEnd struct {
+	io.Reader
+	io.WriteCloser
+}
+
+// fakeSFTPServer 只支持 open/write/close 的 SFTP 服务端
+func fakeSFTPServer(t *testing.T, conn pipeEnd, files map[string]*bytes.Buffer, done chan<- struct{}) {
+	defer close(done)
+	c := &sftpConn{w: conn, r: conn}
+	handles := map[string]string{}
+	status := func(id uint32, code uint32) {
+		c.writePacket(sftpStatus, binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, id), code))
+	}
+	for {
+		typ, payload, err := c.readPacket()
+		if err != nil {
+			return
+		}
+		if typ == sftpInit {
+			c.writePacket(sftpVersion, binary.BigEndian.AppendUint32(nil, 3))
+			continue
+		}
+		id := binary.BigEndian.Uint32(payload)
+		body := payload[4:]
+		switch typ {
+		case sftpOpen:
+			name, _, _ := readString(body)
+			if strings.HasPrefix(string(name), "/denied/") {
+				c.writePacket(sftpStatus, appendString(binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, id), 3), "Permission denied"))
+				continue
+			}
+			files[string(name)] = &bytes.Buffer{}
+			handles["h1"] = string(name)
+			c.writePacket(sftpHandle, appendString(binary.BigEndian.AppendUint32(nil, id), "h1"))
+		case sftpWrite:
+			handle, rest, _ := readString(body)
+			offset := binary.BigEndian.Uint64(rest)
+			data, _, _ := readString(rest[8:])
+			buf := files[handles[string(handle)]]
+			if int(offset) != buf.Len() {
+				t.Errorf("write offset = %d, want %d", offset, buf.Len())
+			}
+			buf.Write(data)
+			status(id, 0)
+		case sftpClose:
+			status(id, 0)
+		default:
+			status(id, 8)
+		}
+	}
+}
+
+func newPipes() (client, server pipeEnd) {
+	cr, sw := io.Pipe()
+	sr, cw := io.Pipe()
+	return pipeEnd{cr, cw}, pipeEnd{sr, sw}
+}
+
+func TestSFTPSend(t *testing.T) {
+	client, server := newPipes()
+	files := map[string]*bytes.Buffer{}
+	done := make(chan struct{})
+	go fakeSFTPServer(t, server, files, done)
+
+	content := strings.Repeat("runbook\n", 10000)
+	var reported int64
+	err := sftpSend(client, client, "/srv/wiki/runbook.html", strings.NewReader(content), func(written int64) { reported = written })
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-done
+	if got := files["/srv/wiki/runbook.html"].String(); got != content {
+		t.Errorf("uploaded %d bytes, want %d", len(got), len(content))
+	}
+	if reported != int64(len(content)) {
+		t.Errorf("progress = %d", reported)
+	}
+}
+
+func TestSFTPSendStatusError(t *testing.T) {
+	client, server := newPipes()
+	done := make(chan struct{})
+	go fakeSFTPServer(t, server, map[string]*bytes.Buffer{}, done)
+
+	err := sftpSend(client, client, "/denied/x", strings.NewReader("x"), nil)
+	if err == nil || !strings.Contains(err.Error(), "Permission denied") {
+		t.Errorf("err = %v", err)
+	}
+	client.Close()
+	<-done
+}
+
+func TestSCPSend(t *testing.T) {
+	client, server := newPipes()
+	result := make(chan string, 1)
+	go func() {
+		r := bufio.NewReader(server)
+		server.Write([]byte{0})
+		header, _ := r.ReadString('\n')
+		server.Write([]byte{0})
+		var mode string
+		var size int
+		var name string
+		fmt.Sscan(header, &mode, &size, &name)
+		data := make([]byte, size+1)
+		io.ReadFull(r, data)
+		server.Write([]byte{0})
+		result <- header + string(data[:size])
+	}()
+
+	err := scpSend(client, client, "/srv/wiki/notes.md", strings.NewReader("hello"), 5, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := <-result; got != "C0644 5 notes.md\nhello" {
+		t.Errorf("scp stream = %q", got)
+	}
+}
+
+func TestSCPSendRemoteError(t *testing.T) {
+	client, server := newPipes()
+	go func() {
+		server.Write([]byte("\x01scp: /srv/x: No such file or directory\n"))
+	}()
+	err := scpSend(client, client, "/srv/x", strings.NewReader(""), 0, nil)
+	if err == nil || !strings.Contains(err.Error(), "No such file") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got := shellQuote("/srv/it's here"); got != `'/srv/it'\''s here'` {
+		t.Errorf("shellQuote = %s", got)
+	}
+}
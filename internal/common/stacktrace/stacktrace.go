@@ -0,0 +1,285 @@
+// Package stacktrace 识别文本中的 Go、Java、Python 和 JavaScript 堆栈，解析为结构化的帧列表
+package stacktrace
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Language 堆栈所属语言
+type Language string
+
+const (
+	LanguageGo     Language = "go"
+	LanguageJava   Language = "java"
+	LanguagePython Language = "python"
+	LanguageJS     Language = "js"
+)
+
+// Frame 堆栈中的一帧，File 为空或 Line 为 0 表示没有源码位置
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
+
+// Trace 识别出的一段堆栈
+type Trace struct {
+	Language Language `json:"language"`
+	Message  string   `json:"message"` // 错误信息所在行
+	Frames   []Frame  `json:"frames"`
+	Start    int      `json:"start"` // 在原文中的起始字节偏移
+	End      int      `json:"end"`   // 在原文中的结束字节偏移，不含末尾换行
+}
+
+var (
+	goHeaderPattern    = regexp.MustCompile(`^(panic: |fatal error: )`)
+	goroutinePattern   = regexp.MustCompile(`^goroutine \d+ \[.*\]:$`)
+	goLocationPattern  = regexp.MustCompile(`^\t(.+):(\d+)(?: \+0x[0-9a-f]+)?$`)
+	goFunctionPattern  = regexp.MustCompile(`^(.*?)\([^()]*\)$`)
+	javaHeaderPattern  = regexp.MustCompile(`^(?:Exception in thread "[^"]*" )?[\w$]+(?:\.[\w$]+)+(?::.*)?$`)
+	javaFramePattern   = regexp.MustCompile(`^\s+at ([\w$./<>-]+)\(([^()]*)\)$`)
+	javaContinuation   = regexp.MustCompile(`^\s*(?:Caused by: |Suppressed: |\.\.\. \d+ (?:more|common frames omitted)$)`)
+	pythonHeader       = "Traceback (most recent call last):"
+	pythonFramePattern = regexp.MustCompile(`^\s+File "(.+)", line (\d+)(?:, in (.+))?$`)
+	jsFramePattern     = regexp.MustCompile(`^\s+at (?:async )?(?:(.+?) \((.+)\)|(.+))$`)
+	jsLocationPattern  = regexp.MustCompile(`^(.*):(\d+):(\d+)$`)
+)
+
+// line 带偏移的单行文本
+type line struct {
+	text       string
+	start, end int
+}
+
+// Detect 识别文本中的所有堆栈
+func Detect(text string) []Trace {
+	lines := splitLines(text)
+	var traces []Trace
+	for i := 0; i < len(lines); {
+		var trace *Trace
+		next := i + 1
+		for _, detect := range []func([]line, int) (*Trace, int){detectPython, detectGo, detectJava, detectJS} {
+			if trace, next = detect(lines, i); trace != nil {
+				break
+			}
+		}
+		if trace == nil {
+			i++
+			continue
+		}
+		trace.Start = lines[i].start
+		trace.End = lines[next-1].end
+		traces = append(traces, *trace)
+		i = next
+	}
+	return traces
+}
+
+// Format 将堆栈格式化为统一的文本，每帧一行，源码位置为 file:line 形式
+func Format(trace Trace) string {
+	var b strings.Builder
+	b.WriteString(trace.Message)
+	for _, frame := range trace.Frames {
+		b.WriteString("\n    at ")
+		location := frame.Location()
+		switch {
+		case frame.Function == "":
+			b.WriteString(location)
+		case location == "":
+			b.WriteString(frame.Function)
+		default:
+			fmt.Fprintf(&b, "%s (%s)", frame.Function, location)
+		}
+	}
+	return b.String()
+}
+
+// Location 源码位置，形如 file:line 或 file:line:column
+func (f Frame) Location() string {
+	switch {
+	case f.Line <= 0:
+		return f.File
+	case f.Column > 0:
+		return fmt.Sprintf("%s:%d:%d", f.File, f.Line, f.Column)
+	default:
+		return fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+}
+
+// splitLines 按行拆分并记录偏移，去除行尾的 \r
+func splitLines(text string) []line {
+	var lines []line
+	for start := 0; start <= len(text); {
+		end := strings.IndexByte(text[start:], '\n')
+		if end < 0 {
+			end = len(text)
+		} else {
+			end += start
+		}
+		lines = append(lines, line{text: strings.TrimSuffix(text[start:end], "\r"), start: start, end: end})
+		start = end + 1
+	}
+	return lines
+}
+
+// detectPython 识别 Python Traceback，以异常信息行结束
+func detectPython(lines []line, i int) (*Trace, int) {
+	if strings.TrimSpace(lines[i].text) != pythonHeader {
+		return nil, i
+	}
+	trace := &Trace{Language: LanguagePython, Message: "Traceback"}
+	j := i + 1
+	for ; j < len(lines); j++ {
+		text := lines[j].text
+		if m := pythonFramePattern.FindStringSubmatch(text); m != nil {
+			n, _ := strconv.Atoi(m[2])
+			trace.Frames = append(trace.Frames, Frame{Function: m[3], File: m[1], Line: n})
+			continue
+		}
+		// 源码行、^^^ 标记等缩进内容属于上一帧
+		if strings.HasPrefix(text, " ") && len(trace.Frames) > 0 {
+			continue
+		}
+		break
+	}
+	if len(trace.Frames) == 0 {
+		return nil, i
+	}
+	if j < len(lines) && strings.TrimSpace(lines[j].text) != "" {
+		trace.Message = strings.TrimSpace(lines[j].text)
+		j++
+	}
+	return trace, j
+}
+
+// detectGo 识别 Go 的 panic 输出或 goroutine 堆栈
+func detectGo(lines []line, i int) (*Trace, int) {
+	text := lines[i].text
+	j := i
+	if goHeaderPattern.MatchString(text) {
+		// panic 信息和 goroutine 之间可能有 [recovered]、[signal ...] 和空行
+		found := false
+		for j = i + 1; j < len(lines) && j <= i+4; j++ {
+			if found = goroutinePattern.MatchString(lines[j].text); found {
+				break
+			}
+		}
+		if !found {
+			return nil, i
+		}
+	} else if !goroutinePattern.MatchString(text) {
+		return nil, i
+	}
+
+	trace := &Trace{Language: LanguageGo, Message: strings.TrimSpace(text)}
+	end := j + 1
+	for j++; j < len(lines); {
+		if goroutinePattern.MatchString(lines[j].text) {
+			j++
+			continue
+		}
+		if strings.TrimSpace(lines[j].text) == "" && j+1 < len(lines) && goroutinePattern.MatchString(lines[j+1].text) {
+			j += 2
+			continue
+		}
+		if j+1 >= len(lines) || strings.HasPrefix(lines[j].text, "\t") {
+			break
+		}
+		m := goLocationPattern.FindStringSubmatch(lines[j+1].text)
+		if m == nil {
+			break
+		}
+		n, _ := strconv.Atoi(m[2])
+		function := lines[j].text
+		if fm := goFunctionPattern.FindStringSubmatch(function); fm != nil {
+			function = fm[1]
+		}
+		trace.Frames = append(trace.Frames, Frame{Function: function, File: m[1], Line: n})
+		j += 2
+		end = j
+	}
+	if len(trace.Frames) == 0 {
+		return nil, i
+	}
+	return trace, end
+}
+
+// detectJava 识别 Java 异常堆栈，包含 Caused by 链
+func detectJava(lines []line, i int) (*Trace, int) {
+	text := strings.TrimSpace(lines[i].text)
+	if !javaHeaderPattern.MatchString(text) || i+1 >= len(lines) || !javaFramePattern.MatchString(lines[i+1].text) {
+		return nil, i
+	}
+	trace := &Trace{Language: LanguageJava, Message: text}
+	j := i + 1
+	for ; j < len(lines); j++ {
+		if m := javaFramePattern.FindStringSubmatch(lines[j].text); m != nil {
+			frame := Frame{Function: m[1], File: m[2]}
+			if k := strings.LastIndexByte(m[2], ':'); k > 0 {
+				if n, err := strconv.Atoi(m[2][k+1:]); err == nil {
+					frame.File, frame.Line = m[2][:k], n
+				}
+			}
+			trace.Frames = append(trace.Frames, frame)
+			continue
+		}
+		if !javaContinuation.MatchString(lines[j].text) {
+			break
+		}
+	}
+	return trace, j
+}
+
+// detectJS 识别 V8（Node.js、Chrome）格式的错误堆栈
+func detectJS(lines []line, i int) (*Trace, int) {
+	text := strings.TrimSpace(lines[i].text)
+	if text == "" || strings.HasPrefix(lines[i].text, " ") || i+1 >= len(lines) {
+		return nil, i
+	}
+	trace := &Trace{Language: LanguageJS, Message: text}
+	j := i + 1
+	for ; j < len(lines); j++ {
+		frame, ok := parseJSFrame(lines[j].text)
+		// 第一帧必须带有源码位置，避免把 "at ..." 开头的普通文本当作堆栈
+		if !ok || (len(trace.Frames) == 0 && frame.File == "") {
+			break
+		}
+		trace.Frames = append(trace.Frames, frame)
+	}
+	if len(trace.Frames) == 0 {
+		return nil, i
+	}
+	return trace, j
+}
+
+// parseJSFrame 解析 "at fn (file:line:col)" 或 "at file:line:col"
+func parseJSFrame(text string) (Frame, bool) {
+	m := jsFramePattern.FindStringSubmatch(text)
+	if m == nil {
+		return Frame{}, false
+	}
+	frame := Frame{Function: m[1]}
+	location := m[2]
+	if location == "" {
+		location = m[3]
+	}
+	if lm := jsLocationPattern.FindStringSubmatch(location); lm != nil {
+		frame.File = strings.TrimPrefix(lm[1], "file://")
+		frame.Line, _ = strconv.Atoi(lm[2])
+		frame.Column, _ = strconv.Atoi(lm[3])
+		return frame, true
+	}
+	if location == "<anonymous>" || location == "native" {
+		frame.File = location
+		return frame, true
+	}
+	// 如 "at async Promise.all (index 0)"，保留函数名，没有源码位置
+	if frame.Function != "" {
+		return frame, true
+	}
+	return Frame{}, false
+}
@@ -0,0 +1,103 @@
+package stacktrace
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectGo(t *testing.T) {
+	text := "starting\npanic: runtime error: index out of range [5] with length 3\n\ngoroutine 1 [running]:\nmain.(*Server).handle(0xc000010000, {0x1, 0x2})\n\t/home/dev/app/server.go:42 +0x1d\nmain.main()\n\t/home/dev/app/main.go:12 +0x25\nexit status 2"
+	traces := Detect(text)
+	if len(traces) != 1 {
+		t.Fatalf("got %d traces", len(traces))
+	}
+	got := traces[0]
+	want := []Frame{
+		{Function: "main.(*Server).handle", File: "/home/dev/app/server.go", Line: 42},
+		{Function: "main.main", File: "/home/dev/app/main.go", Line: 12},
+	}
+	if got.Language != LanguageGo || got.Message != "panic: runtime error: index out of range [5] with length 3" || !reflect.DeepEqual(got.Frames, want) {
+		t.Errorf("trace = %+v", got)
+	}
+	if text[got.Start:got.End] != text[len("starting\n"):strings.Index(text, "\nexit")] {
+		t.Errorf("range = %q", text[got.Start:got.End])
+	}
+}
+
+func TestDetectJava(t *testing.T) {
+	text := `Exception in thread "main" java.lang.IllegalStateException: boom
+	at com.example.App.run(App.java:27)
+	at java.base/java.lang.Thread.run(Native Method)
+Caused by: java.io.IOException: closed
+	at com.example.Io.read(Io.java:9)
+	... 2 more`
+	traces := Detect(text)
+	if len(traces) != 1 || traces[0].Language != LanguageJava {
+		t.Fatalf("traces = %+v", traces)
+	}
+	want := []Frame{
+		{Function: "com.example.App.run", File: "App.java", Line: 27},
+		{Function: "java.base/java.lang.Thread.run", File: "Native Method"},
+		{Function: "com.example.Io.read", File: "Io.java", Line: 9},
+	}
+	if !reflect.DeepEqual(traces[0].Frames, want) || traces[0].End != len(text) {
+		t.Errorf("trace = %+v", traces[0])
+	}
+}
+
+func TestDetectPython(t *testing.T) {
+	text := `Traceback (most recent call last):
+  File "/srv/app/main.py", line 8, in <module>
+    run()
+    ^^^^^
+  File "/srv/app/main.py", line 4, in run
+    raise ValueError("bad")
+ValueError: bad
+next line`
+	traces := Detect(text)
+	if len(traces) != 1 {
+		t.Fatalf("got %d traces", len(traces))
+	}
+	got := traces[0]
+	if got.Message != "ValueError: bad" || len(got.Frames) != 2 || got.Frames[1] != (Frame{Function: "run", File: "/srv/app/main.py", Line: 4}) {
+		t.Errorf("trace = %+v", got)
+	}
+	if !strings.HasSuffix(text[:got.End], "ValueError: bad") {
+		t.Errorf("end = %d", got.End)
+	}
+}
+
+func TestDetectJS(t *testing.T) {
+	text := "TypeError: Cannot read properties of undefined (reading 'id')\r\n    at getUser (/app/src/user.js:10:15)\r\n    at async Promise.all (index 0)\r\n    at file:///app/src/index.mjs:3:1\r\n"
+	traces := Detect(text)
+	if len(traces) != 1 || traces[0].Language != LanguageJS {
+		t.Fatalf("traces = %+v", traces)
+	}
+	want := []Frame{
+		{Function: "getUser", File: "/app/src/user.js", Line: 10, Column: 15},
+		{Function: "Promise.all"},
+		{File: "/app/src/index.mjs", Line: 3, Column: 1},
+	}
+	if !reflect.DeepEqual(traces[0].Frames, want) {
+		t.Errorf("frames = %+v", traces[0].Frames)
+	}
+}
+
+func TestDetectIgnoresProse(t *testing.T) {
+	if traces := Detect("Meeting notes\n  at the office (room 4)\ncom.example.Thing: not a trace\n"); len(traces) != 0 {
+		t.Errorf("traces = %+v", traces)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	trace := Trace{Message: "panic: x", Frames: []Frame{
+		{Function: "main.main", File: "/a/main.go", Line: 3},
+		{File: "/a/b.js", Line: 1, Column: 2},
+		{Function: "Thread.run", File: "Native Method"},
+	}}
+	want := "panic: x\n    at main.main (/a/main.go:3)\n    at /a/b.js:1:2\n    at Thread.run (Native Method)"
+	if got := Format(trace); got != want {
+		t.Errorf("Format = %q", got)
+	}
+}
@@ -0,0 +1,271 @@
+// Package table 在表格数据与 CSV/TSV/Markdown/HTML 文本之间转换，并提供排序和筛选
+// 用于管理查询结果等表格草稿数据
+package table
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Format 表格文本格式
+type Format string
+
+const (
+	FormatCSV      Format = "csv"
+	FormatTSV      Format = "tsv"
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// Table 表格，第一行作为列名
+type Table struct {
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+}
+
+// Detect 根据首行中制表符和逗号的数量判断是 TSV 还是 CSV
+func Detect(text string) Format {
+	first, _, _ := strings.Cut(text, "\n")
+	if strings.Count(first, "\t") > strings.Count(first, ",") {
+		return FormatTSV
+	}
+	return FormatCSV
+}
+
+// Parse 解析 CSV 或 TSV 文本，format 为空时自动判断
+// 各行列数不一致时补齐为最宽一行的列数
+func Parse(text string, format Format) (*Table, error) {
+	if format == "" {
+		format = Detect(text)
+	}
+	reader := csv.NewReader(strings.NewReader(text))
+	switch format {
+	case FormatCSV:
+	case FormatTSV:
+		reader.Comma = '\t'
+	default:
+		return nil, fmt.Errorf("unsupported table format: %s", format)
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", format, err)
+		}
+		records = append(records, record)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("table is empty")
+	}
+
+	width := 0
+	for _, record := range records {
+		width = max(width, len(record))
+	}
+	for i, record := range records {
+		if len(record) < width {
+			records[i] = append(record, make([]string, width-len(record))...)
+		}
+	}
+	return &Table{Columns: records[0], Rows: records[1:]}, nil
+}
+
+// Format 将表格序列化为指定格式
+func (t *Table) Format(format Format) (string, error) {
+	switch format {
+	case FormatCSV, FormatTSV:
+		var b strings.Builder
+		writer := csv.NewWriter(&b)
+		if format == FormatTSV {
+			writer.Comma = '\t'
+		}
+		writer.Write(t.Columns)
+		writer.WriteAll(t.Rows)
+		if err := writer.Error(); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", format, err)
+		}
+		return b.String(), nil
+	case FormatMarkdown:
+		return t.Markdown(), nil
+	case FormatHTML:
+		return t.HTML(), nil
+	}
+	return "", fmt.Errorf("unsupported table format: %s", format)
+}
+
+// Markdown 序列化为 Markdown 表格，单元格中的竖线被转义，换行转为 <br>
+func (t *Table) Markdown() string {
+	var b strings.Builder
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for _, cell := range cells {
+			cell = strings.ReplaceAll(cell, "|", `\|`)
+			cell = strings.ReplaceAll(strings.ReplaceAll(cell, "\r\n", "\n"), "\n", "<br>")
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+	}
+	writeRow(t.Columns)
+	b.WriteString("|")
+	for i := range t.Columns {
+		if t.isNumeric(i) {
+			b.WriteString(" ---: |")
+		} else {
+			b.WriteString(" --- |")
+		}
+	}
+	b.WriteString("\n")
+	for _, row := range t.Rows {
+		writeRow(row)
+	}
+	return b.String()
+}
+
+// HTML 序列化为 HTML 表格，单元格内容已转义
+func (t *Table) HTML() string {
+	var b strings.Builder
+	b.WriteString("<table>\n<thead>\n<tr>")
+	for _, column := range t.Columns {
+		b.WriteString("<th>" + html.EscapeString(column) + "</th>")
+	}
+	b.WriteString("</tr>\n</thead>\n<tbody>\n")
+	for _, row := range t.Rows {
+		b.WriteString("<tr>")
+		for _, cell := range row {
+			b.WriteString("<td>" + html.EscapeString(cell) + "</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	return b.String()
+}
+
+// Sort 按列稳定排序，两个单元格都是数字时按数值比较，否则按不区分大小写的文本比较
+func (t *Table) Sort(column int, descending bool) error {
+	if err := t.checkColumn(column); err != nil {
+		return err
+	}
+	slices.SortStableFunc(t.Rows, func(a, b []string) int {
+		c := compareCells(a[column], b[column])
+		if descending {
+			return -c
+		}
+		return c
+	})
+	return nil
+}
+
+// Filter 返回单元格包含 query（不区分大小写）的行组成的新表格，column 为 -1 时匹配任意列
+func (t *Table) Filter(column int, query string) (*Table, error) {
+	if column != -1 {
+		if err := t.checkColumn(column); err != nil {
+			return nil, err
+		}
+	}
+	query = strings.ToLower(query)
+	filtered := &Table{Columns: slices.Clone(t.Columns), Rows: [][]string{}}
+	for _, row := range t.Rows {
+		match := false
+		for i, cell := range row {
+			if (column == -1 || i == column) && strings.Contains(strings.ToLower(cell), query) {
+				match = true
+				break
+			}
+		}
+		if match {
+			filtered.Rows = append(filtered.Rows, slices.Clone(row))
+		}
+	}
+	return filtered, nil
+}
+
+// SelectColumns 按给定顺序保留部分列，返回新表格
+func (t *Table) SelectColumns(columns []int) (*Table, error) {
+	for _, column := range columns {
+		if err := t.checkColumn(column); err != nil {
+			return nil, err
+		}
+	}
+	pick := func(cells []string) []string {
+		picked := make([]string, len(columns))
+		for i, column := range columns {
+			picked[i] = cells[column]
+		}
+		return picked
+	}
+	selected := &Table{Columns: pick(t.Columns), Rows: make([][]string, len(t.Rows))}
+	for i, row := range t.Rows {
+		selected.Rows[i] = pick(row)
+	}
+	return selected, nil
+}
+
+// checkColumn 检查列序号是否有效
+func (t *Table) checkColumn(column int) error {
+	if column < 0 || column >= len(t.Columns) {
+		return fmt.Errorf("column out of range: %d", column)
+	}
+	return nil
+}
+
+// isNumeric 列中所有非空单元格都是数字
+func (t *Table) isNumeric(column int) bool {
+	found := false
+	for _, row := range t.Rows {
+		cell := strings.TrimSpace(row[column])
+		if cell == "" {
+			continue
+		}
+		if _, ok := parseNumber(cell); !ok {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// compareCells 比较两个单元格，数字排在文本之前
+func compareCells(a, b string) int {
+	na, aok := parseNumber(strings.TrimSpace(a))
+	nb, bok := parseNumber(strings.TrimSpace(b))
+	switch {
+	case aok && bok:
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	case aok:
+		return -1
+	case bok:
+		return 1
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// parseNumber 解析数字，允许千位分隔符
+func parseNumber(s string) (float64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,82 @@
+package table
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAndFormat(t *testing.T) {
+	text := "name,count,note\nb,10,\"x, y\"\na,9\n"
+	tbl, err := Parse(text, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tbl.Columns, []string{"name", "count", "note"}) {
+		t.Errorf("columns = %v", tbl.Columns)
+	}
+	if !reflect.DeepEqual(tbl.Rows, [][]string{{"b", "10", "x, y"}, {"a", "9", ""}}) {
+		t.Errorf("rows = %v", tbl.Rows)
+	}
+
+	csvText, err := tbl.Format(FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if csvText != "name,count,note\nb,10,\"x, y\"\na,9,\n" {
+		t.Errorf("csv = %q", csvText)
+	}
+
+	tsvText, _ := tbl.Format(FormatTSV)
+	if Detect(tsvText) != FormatTSV {
+		t.Errorf("Detect(tsv) = %s", Detect(tsvText))
+	}
+	round, err := Parse(tsvText, FormatTSV)
+	if err != nil || !reflect.DeepEqual(round, tbl) {
+		t.Errorf("tsv round-trip = %+v, %v", round, err)
+	}
+}
+
+func TestSortFilterSelect(t *testing.T) {
+	tbl := &Table{
+		Columns: []string{"name", "size"},
+		Rows:    [][]string{{"b", "10"}, {"A", "9"}, {"c", "1,200"}, {"d", "n/a"}},
+	}
+	if err := tbl.Sort(1, false); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, row := range tbl.Rows {
+		names = append(names, row[0])
+	}
+	if strings.Join(names, "") != "Abcd" {
+		t.Errorf("sorted = %v", names)
+	}
+	if err := tbl.Sort(0, true); err != nil || tbl.Rows[0][0] != "d" {
+		t.Errorf("descending sort = %v, %v", tbl.Rows, err)
+	}
+
+	filtered, err := tbl.Filter(-1, "a")
+	if err != nil || len(filtered.Rows) != 2 {
+		t.Errorf("filter = %v, %v", filtered, err)
+	}
+	if _, err := tbl.Filter(5, "a"); err == nil {
+		t.Error("expected error for invalid column")
+	}
+
+	selected, err := tbl.SelectColumns([]int{1})
+	if err != nil || !reflect.DeepEqual(selected.Columns, []string{"size"}) || len(selected.Rows[0]) != 1 {
+		t.Errorf("select = %+v, %v", selected, err)
+	}
+}
+
+func TestMarkdownAndHTML(t *testing.T) {
+	tbl := &Table{Columns: []string{"k", "n"}, Rows: [][]string{{"a|b", "1"}, {"<x>", "2"}}}
+	want := "| k | n |\n| --- | ---: |\n| a\\|b | 1 |\n| <x> | 2 |\n"
+	if got := tbl.Markdown(); got != want {
+		t.Errorf("Markdown() = %q", got)
+	}
+	if got := tbl.HTML(); !strings.Contains(got, "<td>&lt;x&gt;</td>") {
+		t.Errorf("HTML() = %q", got)
+	}
+}
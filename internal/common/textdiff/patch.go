@@ -0,0 +1,192 @@
+package textdiff
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrEmptyPatch 补丁中没有任何修改段
+	ErrEmptyPatch = errors.New("patch contains no hunks")
+	// ErrMultipleFiles 补丁修改了多个文件，无法应用到一段文本
+	ErrMultipleFiles = errors.New("patch touches more than one file")
+)
+
+// hunkHeader 段头 @@ -l,s +l,s @@，长度省略时为 1
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// Hunk 补丁中的一段修改，行号从 1 开始
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Edits              []Edit
+}
+
+// Parse 解析统一格式补丁，忽略 diff --git、index 等头部行
+// 补丁只能修改一个文件，CRLF 换行按 LF 处理
+func Parse(patch string) ([]Hunk, error) {
+	lines := SplitLines(strings.ReplaceAll(patch, "\r\n", "\n"))
+	var hunks []Hunk
+	files := 0
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, "+++ ") {
+			if files++; files > 1 {
+				return nil, ErrMultipleFiles
+			}
+			continue
+		}
+		if !strings.HasPrefix(line, "@@") {
+			continue
+		}
+
+		h, err := parseHunkHeader(line)
+		if err != nil {
+			return nil, err
+		}
+		oldLeft, newLeft := h.OldLines, h.NewLines
+		for oldLeft > 0 || newLeft > 0 {
+			i++
+			if i >= len(lines) {
+				return nil, fmt.Errorf("hunk %d is truncated", len(hunks)+1)
+			}
+			body := lines[i]
+			// 编辑器可能去掉空上下文行的前导空格
+			if body == "\n" {
+				body = " \n"
+			}
+			var edit Edit
+			switch body[0] {
+			case ' ':
+				edit.Op = Equal
+				oldLeft--
+				newLeft--
+			case '-':
+				edit.Op = Delete
+				oldLeft--
+			case '+':
+				edit.Op = Insert
+				newLeft--
+			case '\\':
+				noNewline(h.Edits)
+				continue
+			default:
+				return nil, fmt.Errorf("hunk %d: unexpected line %q", len(hunks)+1, strings.TrimSuffix(body, "\n"))
+			}
+			if oldLeft < 0 || newLeft < 0 {
+				return nil, fmt.Errorf("hunk %d: line counts do not match header", len(hunks)+1)
+			}
+			edit.Line = body[1:]
+			h.Edits = append(h.Edits, edit)
+		}
+		// 段尾的无换行提示
+		if i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\\") {
+			noNewline(h.Edits)
+			i++
+		}
+		hunks = append(hunks, h)
+	}
+	if len(hunks) == 0 {
+		return nil, ErrEmptyPatch
+	}
+	return hunks, nil
+}
+
+// parseHunkHeader 解析段头中的行号和行数
+func parseHunkHeader(line string) (Hunk, error) {
+	m := hunkHeader.FindStringSubmatch(line)
+	if m == nil {
+		return Hunk{}, fmt.Errorf("invalid hunk header %q", strings.TrimSuffix(line, "\n"))
+	}
+	count := func(s string) int {
+		if s == "" {
+			return 1
+		}
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	oldStart, _ := strconv.Atoi(m[1])
+	newStart, _ := strconv.Atoi(m[3])
+	return Hunk{OldStart: oldStart, OldLines: count(m[2]), NewStart: newStart, NewLines: count(m[4])}, nil
+}
+
+// noNewline 去掉上一行的换行符，对应 "\ No newline at end of file"
+func noNewline(edits []Edit) {
+	if len(edits) > 0 {
+		edits[len(edits)-1].Line = strings.TrimSuffix(edits[len(edits)-1].Line, "\n")
+	}
+}
+
+// Apply 把补丁应用到文本
+// 段头的行号不准时在附近查找上下文匹配的位置，找不到时返回错误且不修改文本
+func Apply(text, patch string) (string, error) {
+	hunks, err := Parse(patch)
+	if err != nil {
+		return "", err
+	}
+
+	lines := SplitLines(text)
+	out := make([]string, 0, len(lines))
+	next := 0   // lines 中尚未处理的第一行
+	offset := 0 // 前一段实际位置与段头行号之差
+	for i, h := range hunks {
+		var old, replacement []string
+		for _, edit := range h.Edits {
+			if edit.Op != Insert {
+				old = append(old, edit.Line)
+			}
+			if edit.Op != Delete {
+				replacement = append(replacement, edit.Line)
+			}
+		}
+
+		// 只有新增行时段头行号指向插入位置的前一行
+		base := h.OldStart - 1
+		if h.OldLines == 0 {
+			base = h.OldStart
+		}
+		pos := locate(lines, old, next, base+offset)
+		if pos < 0 {
+			return "", fmt.Errorf("hunk %d does not apply at line %d", i+1, h.OldStart)
+		}
+		out = append(out, lines[next:pos]...)
+		out = append(out, replacement...)
+		next = pos + len(old)
+		offset = pos - base
+	}
+	out = append(out, lines[next:]...)
+	return strings.Join(out, ""), nil
+}
+
+// locate 从 want 开始向两侧查找 old 出现的位置，不早于 from；找不到时返回 -1
+func locate(lines, old []string, from, want int) int {
+	want = max(from, min(want, len(lines)-len(old)))
+	for delta := 0; ; delta++ {
+		before, after := want-delta, want+delta
+		if before < from && after > len(lines)-len(old) {
+			return -1
+		}
+		if after <= len(lines)-len(old) && matchAt(lines, old, after) {
+			return after
+		}
+		if delta > 0 && before >= from && matchAt(lines, old, before) {
+			return before
+		}
+	}
+}
+
+// matchAt old 是否与 lines 从 pos 开始的行相同
+func matchAt(lines, old []string, pos int) bool {
+	if pos < 0 || pos+len(old) > len(lines) {
+		return false
+	}
+	for i, line := range old {
+		if lines[pos+i] != line {
+			return false
+		}
+	}
+	return true
+}
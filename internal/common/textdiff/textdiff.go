@@ -0,0 +1,232 @@
+// Package textdiff 按行比较文本，生成和应用 git 风格的统一格式补丁
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultContext 补丁中每处修改前后保留的上下文行数
+const DefaultContext = 3
+
+// Op 编辑操作
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Edit 一行的编辑，Line 含行尾换行符（文本最后一行没有换行时除外）
+type Edit struct {
+	Op   Op
+	Line string
+}
+
+// SplitLines 按行拆分文本，每行保留换行符
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Diff 用 Myers 算法计算把 a 变为 b 的最短编辑序列
+func Diff(a, b []string) []Edit {
+	// 相同的开头和结尾不参与计算
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]Edit, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		edits = append(edits, Edit{Op: Equal, Line: line})
+	}
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, Edit{Op: Equal, Line: line})
+	}
+	return edits
+}
+
+// myers 计算编辑序列；trace 只保存每一轮可能用到的对角线，内存与编辑距离成正比
+func myers(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	limit := n + m
+	if limit == 0 {
+		return nil
+	}
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack 从终点沿 trace 回溯出编辑序列
+func backtrack(trace [][]int, a, b []string) []Edit {
+	var edits []Edit
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		// trace[d] 保存第 d 轮开始前的对角线 -d-1 .. d+1
+		at := func(k int) int { return trace[d][k+d+1] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			edits = append(edits, Edit{Op: Equal, Line: a[x-1]})
+			x--
+			y--
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			edits = append(edits, Edit{Op: Insert, Line: b[y-1]})
+			y--
+		} else {
+			edits = append(edits, Edit{Op: Delete, Line: a[x-1]})
+			x--
+		}
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// Unified 生成统一格式补丁，文本相同时返回空；oldName 和 newName 为文件头中的名称
+func Unified(oldName, newName, oldText, newText string, context int) string {
+	if oldText == newText {
+		return ""
+	}
+	if context < 0 {
+		context = DefaultContext
+	}
+
+	edits := Diff(SplitLines(oldText), SplitLines(newText))
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+	for _, hunk := range groupHunks(edits, context) {
+		writeHunk(&b, hunk)
+	}
+	return b.String()
+}
+
+// hunk 一段修改及其上下文
+type hunk struct {
+	oldStart, newStart int // 从 0 开始的行号
+	edits              []Edit
+}
+
+// groupHunks 把编辑序列分为若干段，间隔不超过两倍上下文的修改合为一段
+func groupHunks(edits []Edit, context int) []hunk {
+	var hunks []hunk
+	oldLine, newLine := 0, 0
+	start, end := -1, -1 // 当前段在 edits 中的范围
+	var startOld, startNew int
+
+	flush := func() {
+		if start >= 0 {
+			stop := min(end+context, len(edits))
+			hunks = append(hunks, hunk{oldStart: startOld, newStart: startNew, edits: edits[start:stop]})
+		}
+		start = -1
+	}
+
+	for i, edit := range edits {
+		if edit.Op != Equal {
+			if start >= 0 && i-end > 2*context {
+				flush()
+			}
+			if start < 0 {
+				start = max(i-context, 0)
+				startOld, startNew = oldLine-(i-start), newLine-(i-start)
+			}
+			end = i + 1
+		}
+		if edit.Op != Insert {
+			oldLine++
+		}
+		if edit.Op != Delete {
+			newLine++
+		}
+	}
+	flush()
+	return hunks
+}
+
+// writeHunk 写出一段修改，最后一行没有换行时追加 git 的提示行
+func writeHunk(b *strings.Builder, h hunk) {
+	oldCount, newCount := 0, 0
+	for _, edit := range h.edits {
+		if edit.Op != Insert {
+			oldCount++
+		}
+		if edit.Op != Delete {
+			newCount++
+		}
+	}
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", hunkRange(h.oldStart, oldCount), hunkRange(h.newStart, newCount))
+	for _, edit := range h.edits {
+		switch edit.Op {
+		case Equal:
+			b.WriteByte(' ')
+		case Delete:
+			b.WriteByte('-')
+		case Insert:
+			b.WriteByte('+')
+		}
+		b.WriteString(edit.Line)
+		if !strings.HasSuffix(edit.Line, "\n") {
+			b.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange 段头中的行范围，行号从 1 开始；没有行时为前一行的行号
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,77 @@
+package textdiff
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	oldText := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	newText := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk"
+	want := `--- a/block
++++ b/block
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -8,3 +8,4 @@
+ h
+ i
+ j
++k
+\ No newline at end of file
+`
+	if got := Unified("a/block", "b/block", oldText, newText, DefaultContext); got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+	if got := Unified("a", "b", oldText, oldText, DefaultContext); got != "" {
+		t.Errorf("Unified() of equal text = %q, want empty", got)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	cases := []struct{ oldText, newText string }{
+		{"", "hello\n"},
+		{"hello\n", ""},
+		{"one\ntwo\nthree", "one\n2\nthree\nfour\n"},
+		{"x\ny\nz\n", "z\ny\nx\n"},
+		{strings.Repeat("line\n", 20) + "end\n", "start\n" + strings.Repeat("line\n", 20)},
+	}
+	for _, tc := range cases {
+		patch := Unified("a", "b", tc.oldText, tc.newText, DefaultContext)
+		got, err := Apply(tc.oldText, patch)
+		if err != nil {
+			t.Errorf("Apply(%q) error: %v\n%s", tc.oldText, err, patch)
+			continue
+		}
+		if got != tc.newText {
+			t.Errorf("Apply(%q) = %q, want %q\n%s", tc.oldText, got, tc.newText, patch)
+		}
+	}
+}
+
+func TestApplyWithOffset(t *testing.T) {
+	patch := "diff --git a/f b/f\nindex 1234..5678 100644\n--- a/f\n+++ b/f\n@@ -2,3 +2,3 @@\n b\n-c\n+C\n d\n"
+	// 目标文本在前面多了两行，段头行号不准
+	got, err := Apply("x\ny\na\nb\nc\nd\ne\n", strings.ReplaceAll(patch, "\n", "\r\n"))
+	if err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+	if want := "x\ny\na\nb\nC\nd\ne\n"; got != want {
+		t.Errorf("Apply() = %q, want %q", got, want)
+	}
+
+	if _, err := Apply("a\nb\nd\n", patch); err == nil {
+		t.Error("Apply() with missing context: want error")
+	}
+	if _, err := Apply("a\n", "just some text\n"); !errors.Is(err, ErrEmptyPatch) {
+		t.Errorf("Apply() without hunks error = %v, want ErrEmptyPatch", err)
+	}
+	if _, err := Apply("a\n", "--- a/x\n+++ b/x\n@@ -1 +1 @@\n-a\n+b\n--- a/y\n+++ b/y\n@@ -1 +1 @@\n-a\n+b\n"); !errors.Is(err, ErrMultipleFiles) {
+		t.Errorf("Apply() with two files error = %v, want ErrMultipleFiles", err)
+	}
+}
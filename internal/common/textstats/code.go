@@ -0,0 +1,184 @@
+package textstats
+
+import (
+	"strings"
+	"voidraft/internal/common/blocks"
+)
+
+// CodeStats 代码统计，按行分为代码、注释、空行和正文
+type CodeStats struct {
+	Lines        int            // 总行数
+	CodeLines    int            // 代码行，含行尾带注释的行
+	CommentLines int            // 只有注释的行
+	BlankLines   int            // 代码中的空行
+	ProseLines   int            // 文本和 Markdown 的正文行，不计入代码
+	Languages    map[string]int // 语言 -> 行数，Markdown 中的围栏代码块按围栏标注的语言计
+}
+
+// CommentRatio 注释行占代码和注释行的比例，没有代码时为 0
+func (s CodeStats) CommentRatio() float64 {
+	total := s.CodeLines + s.CommentLines
+	if total == 0 {
+		return 0
+	}
+	return float64(s.CommentLines) / float64(total)
+}
+
+// commentSyntax 语言的注释写法
+type commentSyntax struct {
+	line  []string // 行注释前缀
+	start string   // 块注释开始
+	end   string   // 块注释结束
+}
+
+var (
+	cStyle    = commentSyntax{line: []string{"//"}, start: "/*", end: "*/"}
+	hashStyle = commentSyntax{line: []string{"#"}}
+	markup    = commentSyntax{start: "<!--", end: "-->"}
+)
+
+// commentSyntaxes 块语言 -> 注释写法，未列出的语言没有注释
+var commentSyntaxes = map[string]commentSyntax{
+	"go": cStyle, "js": cStyle, "ts": cStyle, "java": cStyle, "cpp": cStyle, "cs": cStyle, "rs": cStyle,
+	"swift": cStyle, "kt": cStyle, "groovy": cStyle, "dart": cStyle, "scala": cStyle, "lezer": cStyle,
+	"less": cStyle, "sass": cStyle,
+	"py": hashStyle, "rb": hashStyle, "sh": hashStyle, "yaml": hashStyle, "toml": hashStyle,
+	"dockerfile": hashStyle, "ex": hashStyle, "http": hashStyle, "math": hashStyle,
+	"html": markup, "xml": markup, "vue": markup, "svelte": markup, "angular": markup,
+
+	"php":     {line: []string{"//", "#"}, start: "/*", end: "*/"},
+	"css":     {start: "/*", end: "*/"},
+	"ps1":     {line: []string{"#"}, start: "<#", end: "#>"},
+	"sql":     {line: []string{"--"}, start: "/*", end: "*/"},
+	"lua":     {line: []string{"--"}, start: "--[[", end: "]]"},
+	"clj":     {line: []string{";"}},
+	"erl":     {line: []string{"%"}},
+	"mermaid": {line: []string{"%%"}},
+	"wast":    {line: []string{";;"}, start: "(;", end: ";)"},
+	"liquid":  {start: "{% comment %}", end: "{% endcomment %}"},
+}
+
+// CommentLine 按块语言的注释写法把一行文本写成注释，优先使用行注释；没有注释写法的语言返回空字符串
+func CommentLine(language, text string) string {
+	syntax, ok := commentSyntaxes[language]
+	switch {
+	case !ok:
+		return ""
+	case len(syntax.line) > 0:
+		return syntax.line[0] + " " + text
+	default:
+		return syntax.start + " " + text + " " + syntax.end
+	}
+}
+
+// fenceAliases Markdown 围栏中常见的语言名 -> 块语言
+var fenceAliases = map[string]string{
+	"golang": "go", "javascript": "js", "typescript": "ts", "python": "py", "ruby": "rb", "rust": "rs",
+	"bash": "sh", "shell": "sh", "zsh": "sh", "c": "cpp", "c++": "cpp", "csharp": "cs", "c#": "cs",
+	"kotlin": "kt", "yml": "yaml", "powershell": "ps1", "elixir": "ex", "erlang": "erl", "clojure": "clj",
+}
+
+// CountCode 统计块中的代码行、注释行和各语言的行数
+func CountCode(list []blocks.Block) CodeStats {
+	stats := CodeStats{Languages: make(map[string]int)}
+	for _, block := range list {
+		switch {
+		case block.Language == "md":
+			countMarkdown(block.Content, &stats)
+		case proseLanguages[block.Language]:
+			n := strings.Count(block.Content, "\n") + 1
+			stats.Lines += n
+			stats.ProseLines += n
+			stats.Languages[block.Language] += n
+		default:
+			countCodeLines(block.Language, strings.Split(block.Content, "\n"), &stats)
+		}
+	}
+	return stats
+}
+
+// countMarkdown 正文行计为 Markdown，围栏代码块按围栏标注的语言统计，围栏行本身计为正文
+func countMarkdown(text string, stats *CodeStats) {
+	var code []string
+	fence, language := "", ""
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		marker := ""
+		if len(line)-len(trimmed) <= 3 {
+			marker = fenceMarker(trimmed)
+		}
+		switch {
+		case fence == "" && marker != "":
+			fence = marker
+			language = fenceLanguage(trimmed[len(marker):])
+		case fence != "" && strings.HasPrefix(marker, fence) && strings.TrimSpace(trimmed[len(marker):]) == "":
+			countCodeLines(language, code, stats)
+			code, fence = nil, ""
+		case fence != "":
+			code = append(code, line)
+			continue
+		}
+		stats.Lines++
+		stats.ProseLines++
+		stats.Languages["md"]++
+	}
+	// 未闭合的围栏到块末尾为止
+	if fence != "" {
+		countCodeLines(language, code, stats)
+	}
+}
+
+// fenceLanguage 围栏的语言标注，取第一个词转为块语言；未标注时为 text
+func fenceLanguage(info string) string {
+	fields := strings.Fields(info)
+	if len(fields) == 0 {
+		return blocks.DefaultLanguage
+	}
+	language := strings.ToLower(fields[0])
+	if alias, ok := fenceAliases[language]; ok {
+		return alias
+	}
+	return language
+}
+
+// countCodeLines 按语言的注释写法统计代码行
+func countCodeLines(language string, lines []string, stats *CodeStats) {
+	syntax := commentSyntaxes[language]
+	inComment := false
+	for _, line := range lines {
+		stats.Lines++
+		stats.Languages[language]++
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			stats.BlankLines++
+		case inComment:
+			stats.CommentLines++
+			inComment = !strings.Contains(trimmed, syntax.end)
+		case syntax.start != "" && strings.HasPrefix(trimmed, syntax.start):
+			stats.CommentLines++
+			inComment = !strings.Contains(trimmed[len(syntax.start):], syntax.end)
+		case hasAnyPrefix(trimmed, syntax.line):
+			stats.CommentLines++
+		default:
+			stats.CodeLines++
+			// 行尾开始、下一行才结束的块注释
+			if syntax.start != "" {
+				if i := strings.LastIndex(trimmed, syntax.start); i >= 0 {
+					inComment = !strings.Contains(trimmed[i+len(syntax.start):], syntax.end)
+				}
+			}
+		}
+	}
+}
+
+// hasAnyPrefix s 是否以任一前缀开头
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
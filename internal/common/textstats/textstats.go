@@ -0,0 +1,113 @@
+package textstats
+
+import (
+	"strings"
+	"unicode"
+	"voidraft/internal/common/blocks"
+)
+
+// Stats 文档的文本统计，不含块分隔符
+type Stats struct {
+	Words              int // 单词数，中日韩文字每个字计为一个词
+	Characters         int // 字符数，不含换行
+	CharactersNoSpaces int // 字符数，不含任何空白
+	Lines              int // 行数，按块分别计算后累加
+	Blocks             int // 块数
+	CodeBlocks         int // 代码块数：非文本和 Markdown 的块，加上 Markdown 块中的围栏代码块
+}
+
+// proseLanguages 按正文统计的块语言，其余语言视为代码块
+var proseLanguages = map[string]bool{
+	blocks.DefaultLanguage: true,
+	"md":                   true,
+}
+
+// Count 统计文档内容
+func Count(content string) Stats {
+	var stats Stats
+	for _, block := range blocks.Parse(content) {
+		stats.Blocks++
+		stats.Lines += strings.Count(block.Content, "\n") + 1
+		if !proseLanguages[block.Language] {
+			stats.CodeBlocks++
+		} else if block.Language == "md" {
+			stats.CodeBlocks += countFences(block.Content)
+		}
+		countText(block.Content, &stats)
+	}
+	return stats
+}
+
+// countText 统计单词和字符
+func countText(text string, stats *Stats) {
+	inWord := false
+	for _, r := range text {
+		switch {
+		case r == '\n' || r == '\r':
+			inWord = false
+			continue
+		case unicode.IsSpace(r):
+			inWord = false
+			stats.Characters++
+			continue
+		}
+
+		stats.Characters++
+		stats.CharactersNoSpaces++
+		switch {
+		case isCJK(r):
+			stats.Words++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			if !inWord {
+				stats.Words++
+				inWord = true
+			}
+		case inWord && (r == '\'' || r == '’' || r == '-'):
+			// 单词内的撇号和连字符，如 don't、well-known
+		default:
+			inWord = false
+		}
+	}
+}
+
+// isCJK 中日韩文字，没有空格分词，每个字计为一个词
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// countFences 统计 Markdown 中的围栏代码块，未闭合的围栏也计入
+func countFences(text string) int {
+	count := 0
+	fence := ""
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if len(line)-len(trimmed) > 3 {
+			continue
+		}
+		marker := fenceMarker(trimmed)
+		switch {
+		case fence == "" && marker != "":
+			fence = marker
+			count++
+		case fence != "" && strings.HasPrefix(marker, fence) && strings.TrimSpace(trimmed[len(marker):]) == "":
+			fence = ""
+		}
+	}
+	return count
+}
+
+// fenceMarker 行首的围栏标记，至少三个 ` 或 ~
+func fenceMarker(line string) string {
+	if line == "" || (line[0] != '`' && line[0] != '~') {
+		return ""
+	}
+	n := 0
+	for n < len(line) && line[n] == line[0] {
+		n++
+	}
+	if n < 3 {
+		return ""
+	}
+	return line[:n]
+}
@@ -0,0 +1,85 @@
+package textstats
+
+import (
+	"reflect"
+	"testing"
+	"voidraft/internal/common/blocks"
+)
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    Stats
+	}{
+		{"empty document", "\n∞∞∞text-a\n", Stats{Blocks: 1, Lines: 1}},
+		{
+			"english words",
+			"\n∞∞∞text-a\nDon't panic, it's well-known.\nsnake_case 42",
+			Stats{Words: 6, Characters: 42, CharactersNoSpaces: 38, Lines: 2, Blocks: 1},
+		},
+		{"cjk", "\n∞∞∞text\n你好 world", Stats{Words: 3, Characters: 8, CharactersNoSpaces: 7, Lines: 1, Blocks: 1}},
+		{
+			"code blocks",
+			"\n∞∞∞text\nnote\n∞∞∞go\nfunc main() {}\n∞∞∞md\n# Title\n```js\nx\n```\n~~~\ny",
+			Stats{Words: 7, Characters: 38, CharactersNoSpaces: 35, Lines: 8, Blocks: 3, CodeBlocks: 3},
+		},
+	}
+	for _, tt := range tests {
+		if got := Count(tt.content); got != tt.want {
+			t.Errorf("%s: Count = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCountFences(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"```\ncode\n```", 1},
+		{"````\n```\nstill code\n````", 1},
+		{"```go\na\n```\n\n~~~\nb\n~~~", 2},
+		{"    ```\nindented code", 0},
+		{"``inline``", 0},
+	}
+	for _, tt := range tests {
+		if got := countFences(tt.text); got != tt.want {
+			t.Errorf("countFences(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCountCode(t *testing.T) {
+	content := "\n∞∞∞text\nnote\n∞∞∞go\n// Package main\npackage main\n\n/* multi\nline */\nfunc main() {} // trailing\n" +
+		"∞∞∞md\n# Title\n```python\n# comment\nx = 1\n```\n∞∞∞lua\n--[[\nblock\n]]\nprint(1)"
+	got := CountCode(blocks.Parse(content))
+
+	want := CodeStats{
+		Lines: 16, CodeLines: 4, CommentLines: 7, BlankLines: 1, ProseLines: 4,
+		Languages: map[string]int{"text": 1, "go": 6, "md": 3, "py": 2, "lua": 4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CountCode = %+v, want %+v", got, want)
+	}
+	if ratio := got.CommentRatio(); ratio < 0.63 || ratio > 0.64 {
+		t.Errorf("CommentRatio = %v, want 7/11", ratio)
+	}
+}
+
+func TestCommentLine(t *testing.T) {
+	tests := []struct{ language, want string }{
+		{"go", "// main.go"},
+		{"py", "# main.go"},
+		{"sql", "-- main.go"},
+		{"css", "/* main.go */"},
+		{"html", "<!-- main.go -->"},
+		{"json", ""},
+		{"text", ""},
+	}
+	for _, tt := range tests {
+		if got := CommentLine(tt.language, "main.go"); got != tt.want {
+			t.Errorf("CommentLine(%q) = %q, want %q", tt.language, got, tt.want)
+		}
+	}
+}
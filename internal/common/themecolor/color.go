@@ -0,0 +1,269 @@
+// Package themecolor 提供主题颜色计算：颜色解析、对比度、调色板提取以及生成完整的主题颜色配置
+package themecolor
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Color RGBA 颜色
+type Color struct {
+	R, G, B, A uint8
+}
+
+// Parse 解析 #rgb、#rgba、#rrggbb、#rrggbbaa、rgb()、rgba()、hsl()、hsla() 和 oklch() 格式的颜色
+func Parse(s string) (Color, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if strings.HasPrefix(s, "#") {
+		return parseHex(s[1:])
+	}
+	if strings.HasPrefix(s, "rgb") {
+		return parseFunc(s)
+	}
+	if strings.HasPrefix(s, "hsl") {
+		return parseHSLFunc(s)
+	}
+	if strings.HasPrefix(s, "oklch") {
+		return parseOKLCHFunc(s)
+	}
+	return Color{}, fmt.Errorf("unsupported color: %q", s)
+}
+
+// MustParse 解析颜色，失败时 panic，仅用于常量
+func MustParse(s string) Color {
+	c, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// parseHex 解析十六进制颜色
+func parseHex(hex string) (Color, error) {
+	switch len(hex) {
+	case 3, 4:
+		expanded := make([]byte, 0, len(hex)*2)
+		for i := 0; i < len(hex); i++ {
+			expanded = append(expanded, hex[i], hex[i])
+		}
+		hex = string(expanded)
+	case 6, 8:
+	default:
+		return Color{}, fmt.Errorf("invalid hex color: #%s", hex)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid hex color: #%s", hex)
+	}
+	return Color{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// parseFunc 解析 rgb(r, g, b) 和 rgba(r, g, b, a)
+func parseFunc(s string) (Color, error) {
+	open, end := strings.IndexByte(s, '('), strings.LastIndexByte(s, ')')
+	if open < 0 || end < open {
+		return Color{}, fmt.Errorf("invalid color: %q", s)
+	}
+	parts := strings.FieldsFunc(s[open+1:end], func(r rune) bool { return r == ',' || r == ' ' || r == '/' })
+	if len(parts) != 3 && len(parts) != 4 {
+		return Color{}, fmt.Errorf("invalid color: %q", s)
+	}
+
+	var channels [3]uint8
+	for i := 0; i < 3; i++ {
+		v, err := strconv.ParseFloat(parts[i], 64)
+		if err != nil {
+			return Color{}, fmt.Errorf("invalid color: %q", s)
+		}
+		channels[i] = clampByte(v)
+	}
+	alpha := uint8(255)
+	if len(parts) == 4 {
+		a, err := strconv.ParseFloat(strings.TrimSuffix(parts[3], "%"), 64)
+		if err != nil {
+			return Color{}, fmt.Errorf("invalid color: %q", s)
+		}
+		if strings.HasSuffix(parts[3], "%") {
+			a /= 100
+		}
+		alpha = clampByte(a * 255)
+	}
+	return Color{R: channels[0], G: channels[1], B: channels[2], A: alpha}, nil
+}
+
+// Hex 十六进制表示，不透明时省略透明度
+func (c Color) Hex() string {
+	if c.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// WithAlpha 返回指定透明度的颜色
+func (c Color) WithAlpha(alpha uint8) Color {
+	c.A = alpha
+	return c
+}
+
+// Over 将半透明颜色叠加到背景上，得到实际显示的不透明颜色
+func (c Color) Over(bg Color) Color {
+	if c.A == 255 {
+		return c
+	}
+	t := float64(c.A) / 255
+	return Mix(bg, Color{R: c.R, G: c.G, B: c.B, A: 255}, t)
+}
+
+// Luminance WCAG 相对亮度
+func (c Color) Luminance() float64 {
+	channel := func(v uint8) float64 {
+		s := float64(v) / 255
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c.R) + 0.7152*channel(c.G) + 0.0722*channel(c.B)
+}
+
+// IsDark 颜色是否偏暗
+func (c Color) IsDark() bool {
+	return c.Luminance() < 0.18
+}
+
+// Contrast WCAG 对比度，范围 1 到 21，前景色的透明度按叠加后计算
+func Contrast(fg, bg Color) float64 {
+	fg = fg.Over(bg)
+	l1, l2 := fg.Luminance(), bg.Luminance()
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// Mix 按比例混合两种颜色，t 为 b 的占比
+func Mix(a, b Color, t float64) Color {
+	mix := func(x, y uint8) uint8 {
+		return clampByte(float64(x)*(1-t) + float64(y)*t)
+	}
+	return Color{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: mix(a.A, b.A)}
+}
+
+// HSL 转换为色相（0-360）、饱和度和亮度（0-1）
+func (c Color) HSL() (h, s, l float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	maxV, minV := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l = (maxV + minV) / 2
+	if maxV == minV {
+		return 0, 0, l
+	}
+
+	d := maxV - minV
+	if l > 0.5 {
+		s = d / (2 - maxV - minV)
+	} else {
+		s = d / (maxV + minV)
+	}
+	switch maxV {
+	case r:
+		h = (g - b) / d
+		if g < b {
+			h += 6
+		}
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	return h * 60, s, l
+}
+
+// FromHSL 由色相、饱和度和亮度创建不透明颜色
+func FromHSL(h, s, l float64) Color {
+	h = math.Mod(math.Mod(h, 360)+360, 360) / 360
+	s, l = clamp01(s), clamp01(l)
+	if s == 0 {
+		v := clampByte(l * 255)
+		return Color{R: v, G: v, B: v, A: 255}
+	}
+
+	var q float64
+	if l < 0.5 {
+		q = l * (1 + s)
+	} else {
+		q = l + s - l*s
+	}
+	p := 2*l - q
+	hue := func(t float64) float64 {
+		if t < 0 {
+			t++
+		}
+		if t > 1 {
+			t--
+		}
+		switch {
+		case t < 1.0/6:
+			return p + (q-p)*6*t
+		case t < 0.5:
+			return q
+		case t < 2.0/3:
+			return p + (q-p)*(2.0/3-t)*6
+		}
+		return p
+	}
+	return Color{
+		R: clampByte(hue(h+1.0/3) * 255),
+		G: clampByte(hue(h) * 255),
+		B: clampByte(hue(h-1.0/3) * 255),
+		A: 255,
+	}
+}
+
+// EnsureContrast 调整前景色亮度直到与背景的对比度不低于 minRatio，尽量保持色相和饱和度
+func EnsureContrast(fg, bg Color, minRatio float64) Color {
+	if Contrast(fg, bg) >= minRatio {
+		return fg
+	}
+
+	// 半透明颜色先叠加为不透明色再调整，结果也保持不透明
+	solid := fg.Over(bg)
+	h, s, l := solid.HSL()
+	// 背景偏暗时提亮前景，否则加深
+	step := 0.02
+	if !bg.IsDark() {
+		step = -step
+	}
+	for i := 0; i < 50; i++ {
+		l = clamp01(l + step)
+		candidate := FromHSL(h, s, l)
+		if Contrast(candidate, bg) >= minRatio || l == 0 || l == 1 {
+			solid = candidate
+			break
+		}
+	}
+	if Contrast(solid, bg) < minRatio {
+		// 纯黑或纯白兜底
+		if bg.IsDark() {
+			solid = Color{R: 255, G: 255, B: 255, A: 255}
+		} else {
+			solid = Color{A: 255}
+		}
+	}
+	return solid
+}
+
+// clampByte 限制到 0-255
+func clampByte(v float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(255, v))))
+}
+
+// clamp01 限制到 0-1
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
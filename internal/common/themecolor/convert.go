@@ -0,0 +1,238 @@
+package themecolor
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Format 颜色文本格式
+type Format string
+
+const (
+	FormatHex   Format = "hex"
+	FormatRGB   Format = "rgb"
+	FormatHSL   Format = "hsl"
+	FormatOKLCH Format = "oklch"
+)
+
+// Info 颜色的各种表示，用于内联色块和调色板
+type Info struct {
+	Hex   string  `json:"hex"`
+	RGB   string  `json:"rgb"`
+	HSL   string  `json:"hsl"`
+	OKLCH string  `json:"oklch"`
+	Alpha float64 `json:"alpha"` // 透明度，0-1
+	Dark  bool    `json:"dark"`  // 是否偏暗，用于选择色块上的文字颜色
+}
+
+// Describe 返回颜色的各种表示
+func (c Color) Describe() Info {
+	return Info{
+		Hex:   c.Hex(),
+		RGB:   c.RGBString(),
+		HSL:   c.HSLString(),
+		OKLCH: c.OKLCHString(),
+		Alpha: roundTo(float64(c.A)/255, 3),
+		Dark:  c.IsDark(),
+	}
+}
+
+// Format 按指定格式输出颜色
+func (c Color) Format(format Format) (string, error) {
+	switch format {
+	case FormatHex:
+		return c.Hex(), nil
+	case FormatRGB:
+		return c.RGBString(), nil
+	case FormatHSL:
+		return c.HSLString(), nil
+	case FormatOKLCH:
+		return c.OKLCHString(), nil
+	}
+	return "", fmt.Errorf("unsupported color format: %s", format)
+}
+
+// RGBString rgb()/rgba() 表示
+func (c Color) RGBString() string {
+	if c.A == 255 {
+		return fmt.Sprintf("rgb(%d, %d, %d)", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("rgba(%d, %d, %d, %s)", c.R, c.G, c.B, formatNumber(float64(c.A)/255, 3))
+}
+
+// HSLString hsl()/hsla() 表示
+func (c Color) HSLString() string {
+	h, s, l := c.HSL()
+	args := fmt.Sprintf("%s, %s%%, %s%%", formatNumber(h, 1), formatNumber(s*100, 1), formatNumber(l*100, 1))
+	if c.A == 255 {
+		return "hsl(" + args + ")"
+	}
+	return "hsla(" + args + ", " + formatNumber(float64(c.A)/255, 3) + ")"
+}
+
+// OKLCHString oklch() 表示
+func (c Color) OKLCHString() string {
+	l, ch, h := c.OKLCH()
+	args := fmt.Sprintf("%s %s %s", formatNumber(l, 4), formatNumber(ch, 4), formatNumber(h, 2))
+	if c.A != 255 {
+		args += " / " + formatNumber(float64(c.A)/255, 3)
+	}
+	return "oklch(" + args + ")"
+}
+
+// OKLCH 转换为 OKLCH：亮度（0-1）、色度和色相（0-360），无彩色的色相为 0
+func (c Color) OKLCH() (l, chroma, h float64) {
+	r, g, b := linearize(c.R), linearize(c.G), linearize(c.B)
+	lc := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	mc := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	sc := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+
+	l = 0.2104542553*lc + 0.7936177850*mc - 0.0040720468*sc
+	a := 1.9779984951*lc - 2.4285922050*mc + 0.4505937099*sc
+	bb := 0.0259040371*lc + 0.7827717662*mc - 0.8086757660*sc
+	chroma = math.Hypot(a, bb)
+	if chroma < 1e-4 {
+		return l, 0, 0
+	}
+	h = math.Mod(math.Atan2(bb, a)*180/math.Pi+360, 360)
+	return l, chroma, h
+}
+
+// FromOKLCH 由 OKLCH 创建颜色，超出 sRGB 色域时降低色度直到落入色域
+func FromOKLCH(l, chroma, h float64, alpha uint8) Color {
+	l = clamp01(l)
+	chroma = math.Max(0, chroma)
+	r, g, b, ok := oklchToLinear(l, chroma, h)
+	if !ok {
+		low, high := 0.0, chroma
+		for i := 0; i < 24; i++ {
+			mid := (low + high) / 2
+			if _, _, _, inGamut := oklchToLinear(l, mid, h); inGamut {
+				low = mid
+			} else {
+				high = mid
+			}
+		}
+		r, g, b, _ = oklchToLinear(l, low, h)
+	}
+	return Color{R: delinearize(r), G: delinearize(g), B: delinearize(b), A: alpha}
+}
+
+// oklchToLinear OKLCH 转线性 sRGB，并判断是否在色域内
+func oklchToLinear(l, chroma, h float64) (r, g, b float64, ok bool) {
+	rad := h * math.Pi / 180
+	a, bb := chroma*math.Cos(rad), chroma*math.Sin(rad)
+	lc := l + 0.3963377774*a + 0.2158037573*bb
+	mc := l - 0.1055613458*a - 0.0638541728*bb
+	sc := l - 0.0894841775*a - 1.2914855480*bb
+	lc, mc, sc = lc*lc*lc, mc*mc*mc, sc*sc*sc
+
+	r = 4.0767416621*lc - 3.3077115913*mc + 0.2309699292*sc
+	g = -1.2684380046*lc + 2.6097574011*mc - 0.3413193965*sc
+	b = -0.0041960863*lc - 0.7034186147*mc + 1.7076147010*sc
+	const eps = 1e-4
+	ok = r >= -eps && r <= 1+eps && g >= -eps && g <= 1+eps && b >= -eps && b <= 1+eps
+	return r, g, b, ok
+}
+
+// linearize sRGB 通道转线性值
+func linearize(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// delinearize 线性值转 sRGB 通道
+func delinearize(v float64) uint8 {
+	v = clamp01(v)
+	if v <= 0.0031308 {
+		return clampByte(v * 12.92 * 255)
+	}
+	return clampByte((1.055*math.Pow(v, 1/2.4) - 0.055) * 255)
+}
+
+// parseHSLFunc 解析 hsl(h, s%, l%) 和 hsla(h, s%, l%, a)，也支持空格分隔和 / 透明度
+func parseHSLFunc(s string) (Color, error) {
+	parts, alpha, err := funcArgs(s)
+	if err != nil {
+		return Color{}, err
+	}
+	h, err1 := parseAngle(parts[0])
+	sat, err2 := parsePercent(parts[1], 1)
+	light, err3 := parsePercent(parts[2], 1)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return Color{}, fmt.Errorf("invalid color: %q", s)
+	}
+	return FromHSL(h, sat, light).WithAlpha(alpha), nil
+}
+
+// parseOKLCHFunc 解析 oklch(L C H / a)，L 可为 0-1 或百分比，C 的 100% 对应 0.4
+func parseOKLCHFunc(s string) (Color, error) {
+	parts, alpha, err := funcArgs(s)
+	if err != nil {
+		return Color{}, err
+	}
+	l, err1 := parsePercent(parts[0], 1)
+	chroma, err2 := parsePercent(parts[1], 0.4)
+	h, err3 := parseAngle(parts[2])
+	if err1 != nil || err2 != nil || err3 != nil {
+		return Color{}, fmt.Errorf("invalid color: %q", s)
+	}
+	return FromOKLCH(l, chroma, h, alpha), nil
+}
+
+// funcArgs 拆分颜色函数的三个参数和可选的透明度
+func funcArgs(s string) ([]string, uint8, error) {
+	open, end := strings.IndexByte(s, '('), strings.LastIndexByte(s, ')')
+	if open < 0 || end < open {
+		return nil, 0, fmt.Errorf("invalid color: %q", s)
+	}
+	parts := strings.FieldsFunc(s[open+1:end], func(r rune) bool { return r == ',' || r == ' ' || r == '/' })
+	if len(parts) != 3 && len(parts) != 4 {
+		return nil, 0, fmt.Errorf("invalid color: %q", s)
+	}
+	alpha := uint8(255)
+	if len(parts) == 4 {
+		a, err := parsePercent(parts[3], 1)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid color: %q", s)
+		}
+		alpha = clampByte(a * 255)
+	}
+	return parts[:3], alpha, nil
+}
+
+// parsePercent 解析数字或百分比，百分比按 full 换算
+func parsePercent(s string, full float64) (float64, error) {
+	if strings.HasSuffix(s, "%") {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		return v / 100 * full, err
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// parseAngle 解析角度，支持 deg、turn 和 rad 单位
+func parseAngle(s string) (float64, error) {
+	for unit, factor := range map[string]float64{"deg": 1, "turn": 360, "rad": 180 / math.Pi} {
+		if strings.HasSuffix(s, unit) {
+			v, err := strconv.ParseFloat(strings.TrimSuffix(s, unit), 64)
+			return v * factor, err
+		}
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// formatNumber 按精度格式化并去掉多余的零
+func formatNumber(v float64, precision int) string {
+	return strconv.FormatFloat(roundTo(v, precision), 'f', -1, 64)
+}
+
+// roundTo 四舍五入到指定小数位
+func roundTo(v float64, precision int) float64 {
+	p := math.Pow10(precision)
+	return math.Round(v*p) / p
+}
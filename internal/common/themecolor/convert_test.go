@@ -0,0 +1,48 @@
+package themecolor
+
+import "testing"
+
+func TestParseHSLAndOKLCH(t *testing.T) {
+	for in, want := range map[string]string{
+		"hsl(0, 100%, 50%)":          "#ff0000",
+		"hsla(120, 100%, 25%, 0.5)":  "#00800080",
+		"hsl(240deg 100% 50% / 50%)": "#0000ff80",
+		"oklch(0.628 0.2577 29.23)":  "#ff0000",
+		"oklch(100% 0 0)":            "#ffffff",
+		"oklch(0.7 0.4 150)":         "#00be58",
+	} {
+		c, err := Parse(in)
+		if err != nil || c.Hex() != want {
+			t.Errorf("Parse(%q) = %s, %v; want %s", in, c.Hex(), err, want)
+		}
+	}
+	if _, err := Parse("hsl(1, 2)"); err == nil {
+		t.Error("expected error for missing hsl argument")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	c := MustParse("#3366ccb3")
+	want := map[Format]string{
+		FormatHex:   "#3366ccb3",
+		FormatRGB:   "rgba(51, 102, 204, 0.702)",
+		FormatHSL:   "hsla(220, 60%, 50%, 0.702)",
+		FormatOKLCH: "oklch(0.5325 0.1679 262.29 / 0.702)",
+	}
+	for format, s := range want {
+		got, err := c.Format(format)
+		if err != nil || got != s {
+			t.Errorf("Format(%s) = %q, %v; want %q", format, got, err, s)
+		}
+		// 每种格式都能解析回同一个颜色
+		if back, err := Parse(got); err != nil || back != c {
+			t.Errorf("Parse(%q) = %s, %v", got, back.Hex(), err)
+		}
+	}
+	if _, err := c.Format("cmyk"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+	if info := MustParse("#000").Describe(); !info.Dark || info.Alpha != 1 || info.OKLCH != "oklch(0 0 0)" {
+		t.Errorf("Describe() = %+v", info)
+	}
+}
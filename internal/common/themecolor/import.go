@@ -0,0 +1,442 @@
+package themecolor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnknownThemeFormat 无法识别的主题文件格式
+var ErrUnknownThemeFormat = errors.New("unknown theme format")
+
+// Imported 导入得到的主题
+type Imported struct {
+	Name   string                 // 原主题名称
+	Colors map[string]interface{} // 主题颜色配置，themeName 需由调用方设置
+}
+
+// Import 按内容识别 Base16 YAML 或 VS Code 主题 JSON 并转换
+func Import(data []byte) (*Imported, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") {
+		return ImportVSCode(data)
+	}
+	if strings.Contains(trimmed, "base00") {
+		return ImportBase16(data)
+	}
+	return nil, ErrUnknownThemeFormat
+}
+
+// base16Scheme Base16 方案，兼容旧格式（顶层 baseXX）和 tinted-theming 格式（palette 下的 baseXX）
+type base16Scheme struct {
+	Scheme  string            `yaml:"scheme"`
+	Name    string            `yaml:"name"`
+	Variant string            `yaml:"variant"`
+	Palette map[string]string `yaml:"palette"`
+}
+
+// ImportBase16 按 Base16 样式指南将 base00-base0F 映射到主题颜色
+func ImportBase16(data []byte) (*Imported, error) {
+	var scheme base16Scheme
+	if err := yaml.Unmarshal(data, &scheme); err != nil {
+		return nil, fmt.Errorf("invalid base16 scheme: %w", err)
+	}
+	palette := scheme.Palette
+	if len(palette) == 0 {
+		if err := yaml.Unmarshal(data, &palette); err != nil {
+			return nil, fmt.Errorf("invalid base16 scheme: %w", err)
+		}
+	}
+
+	var base [16]Color
+	for i := range base {
+		key := fmt.Sprintf("base%02X", i)
+		value, ok := palette[key]
+		if !ok {
+			return nil, fmt.Errorf("invalid base16 scheme: missing %s", key)
+		}
+		if !strings.HasPrefix(value, "#") {
+			value = "#" + value
+		}
+		c, err := Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base16 scheme: %s: %w", key, err)
+		}
+		base[i] = c
+	}
+
+	name := scheme.Name
+	if name == "" {
+		name = scheme.Scheme
+	}
+	dark := base[0].Luminance() < base[7].Luminance()
+	if scheme.Variant != "" {
+		dark = scheme.Variant == "dark"
+	}
+	selection := base[2]
+
+	colors := Build(name, Scheme{
+		Dark:       dark,
+		Background: base[0],
+		Foreground: base[5],
+		Cursor:     &base[5],
+		Selection:  &selection,
+		Roles: map[Role]Color{
+			RoleComment:     base[3],
+			RoleKeyword:     base[14],
+			RoleString:      base[11],
+			RoleNumber:      base[9],
+			RoleType:        base[10],
+			RoleFunction:    base[13],
+			RoleVariable:    base[5],
+			RoleProperty:    base[8],
+			RoleOperator:    base[12],
+			RolePunctuation: base[5],
+			RoleHeading:     base[13],
+			RoleLink:        base[9],
+			RoleInserted:    base[11],
+			RoleDeleted:     base[8],
+			RoleChanged:     base[14],
+			RoleInvalid:     base[8],
+		},
+		Tags: map[string]Color{
+			"variableName":  base[8],
+			"tagName":       base[8],
+			"attributeName": base[9],
+			"regexp":        base[12],
+			"escape":        base[12],
+			"quote":         base[12],
+			"list":          base[8],
+			"strong":        base[10],
+			"emphasis":      base[14],
+			"monospace":     base[11],
+			"special":       base[15],
+			"meta":          base[15],
+		},
+	})
+	colors["backgroundSecondary"] = base[1].Hex()
+	colors["activeLine"] = base[1].Hex()
+	colors["lineNumber"] = base[3].Hex()
+	colors["activeLineNumber"] = base[4].Hex()
+	colors["borderColor"] = base[1].Hex()
+	colors["matchingBracket"] = base[2].Hex()
+	return &Imported{Name: name, Colors: colors}, nil
+}
+
+// vscodeTheme VS Code 颜色主题
+type vscodeTheme struct {
+	Name        string            `json:"name"`
+	Type        string            `json:"type"`
+	Colors      map[string]string `json:"colors"`
+	TokenColors json.RawMessage   `json:"tokenColors"`
+}
+
+// vscodeTokenRule tokenColors 中的单条规则
+type vscodeTokenRule struct {
+	Scope    json.RawMessage `json:"scope"`
+	Settings struct {
+		Foreground string `json:"foreground"`
+	} `json:"settings"`
+}
+
+// tagScopes 语法标签对应的 TextMate 作用域，按优先级排列
+var tagScopes = map[string][]string{
+	"comment":               {"comment"},
+	"lineComment":           {"comment.line", "comment"},
+	"blockComment":          {"comment.block", "comment"},
+	"docComment":            {"comment.block.documentation", "comment"},
+	"name":                  {"variable"},
+	"variableName":          {"variable.other.readwrite", "variable"},
+	"local":                 {"variable.other.local", "variable"},
+	"typeName":              {"entity.name.type", "support.type", "storage.type"},
+	"className":             {"entity.name.type.class", "entity.name.class", "entity.name.type"},
+	"tagName":               {"entity.name.tag"},
+	"propertyName":          {"variable.other.property", "support.type.property-name", "variable.other.object.property"},
+	"attributeName":         {"entity.other.attribute-name"},
+	"labelName":             {"entity.name.label"},
+	"namespace":             {"entity.name.namespace", "entity.name.type.namespace", "entity.name.type"},
+	"macroName":             {"entity.name.function.preprocessor", "meta.preprocessor"},
+	"literal":               {"constant"},
+	"string":                {"string"},
+	"docString":             {"string.quoted.docstring", "string"},
+	"character":             {"constant.character", "string"},
+	"attributeValue":        {"string.quoted", "string"},
+	"number":                {"constant.numeric"},
+	"integer":               {"constant.numeric.integer", "constant.numeric"},
+	"float":                 {"constant.numeric.float", "constant.numeric"},
+	"bool":                  {"constant.language.boolean", "constant.language"},
+	"null":                  {"constant.language.null", "constant.language"},
+	"atom":                  {"constant.language"},
+	"constant":              {"constant.other", "variable.other.constant", "constant"},
+	"regexp":                {"string.regexp"},
+	"escape":                {"constant.character.escape"},
+	"color":                 {"constant.other.color", "constant"},
+	"unit":                  {"keyword.other.unit", "constant.numeric"},
+	"url":                   {"markup.underline.link", "string.other.link"},
+	"keyword":               {"keyword"},
+	"self":                  {"variable.language.this", "variable.language", "keyword"},
+	"modifier":              {"storage.modifier", "storage"},
+	"operatorKeyword":       {"keyword.operator.word", "keyword.operator", "keyword"},
+	"controlKeyword":        {"keyword.control"},
+	"definitionKeyword":     {"storage.type", "storage", "keyword"},
+	"moduleKeyword":         {"keyword.control.import", "keyword.control"},
+	"operator":              {"keyword.operator"},
+	"derefOperator":         {"punctuation.accessor", "keyword.operator"},
+	"arithmeticOperator":    {"keyword.operator.arithmetic", "keyword.operator"},
+	"logicOperator":         {"keyword.operator.logical", "keyword.operator"},
+	"bitwiseOperator":       {"keyword.operator.bitwise", "keyword.operator"},
+	"compareOperator":       {"keyword.operator.comparison", "keyword.operator"},
+	"updateOperator":        {"keyword.operator.assignment.compound", "keyword.operator.assignment", "keyword.operator"},
+	"definitionOperator":    {"keyword.operator.assignment", "keyword.operator"},
+	"typeOperator":          {"keyword.operator.type", "keyword.operator"},
+	"controlOperator":       {"keyword.operator.ternary", "keyword.operator"},
+	"punctuation":           {"punctuation"},
+	"separator":             {"punctuation.separator", "punctuation"},
+	"bracket":               {"punctuation.section", "meta.brace", "punctuation"},
+	"angleBracket":          {"punctuation.definition.tag", "punctuation"},
+	"squareBracket":         {"punctuation.section.brackets", "meta.brace.square", "punctuation"},
+	"paren":                 {"punctuation.section.parens", "meta.brace.round", "punctuation"},
+	"brace":                 {"punctuation.section.block", "meta.brace.curly", "punctuation"},
+	"heading":               {"markup.heading", "entity.name.section"},
+	"heading1":              {"markup.heading.1", "markup.heading"},
+	"heading2":              {"markup.heading.2", "markup.heading"},
+	"heading3":              {"markup.heading.3", "markup.heading"},
+	"heading4":              {"markup.heading.4", "markup.heading"},
+	"heading5":              {"markup.heading.5", "markup.heading"},
+	"heading6":              {"markup.heading.6", "markup.heading"},
+	"contentSeparator":      {"meta.separator", "punctuation"},
+	"list":                  {"markup.list", "punctuation.definition.list"},
+	"quote":                 {"markup.quote"},
+	"emphasis":              {"markup.italic"},
+	"strong":                {"markup.bold"},
+	"link":                  {"markup.underline.link", "string.other.link"},
+	"monospace":             {"markup.inline.raw", "markup.raw"},
+	"strikethrough":         {"markup.strikethrough"},
+	"inserted":              {"markup.inserted"},
+	"deleted":               {"markup.deleted"},
+	"changed":               {"markup.changed"},
+	"invalid":               {"invalid"},
+	"meta":                  {"meta.preprocessor", "meta"},
+	"documentMeta":          {"comment.block.documentation", "comment"},
+	"annotation":            {"meta.annotation", "storage.type.annotation", "meta.decorator"},
+	"processingInstruction": {"meta.preprocessor", "meta.tag.preprocessor"},
+	"definition":            {"entity.name.function", "entity.name"},
+	"function":              {"entity.name.function", "support.function"},
+	"standard":              {"support.function", "support"},
+	"special":               {"support.variable", "support"},
+}
+
+// roleScopes 角色的代表作用域，用于推导未单独匹配到的标签
+var roleScopes = map[Role]string{
+	RoleComment:     "comment",
+	RoleKeyword:     "keyword",
+	RoleString:      "string",
+	RoleNumber:      "constant.numeric",
+	RoleType:        "entity.name.type",
+	RoleFunction:    "entity.name.function",
+	RoleVariable:    "variable",
+	RoleProperty:    "variable.other.property",
+	RoleOperator:    "keyword.operator",
+	RolePunctuation: "punctuation",
+	RoleHeading:     "markup.heading",
+	RoleLink:        "markup.underline.link",
+	RoleInserted:    "markup.inserted",
+	RoleDeleted:     "markup.deleted",
+	RoleChanged:     "markup.changed",
+	RoleInvalid:     "invalid",
+}
+
+// ImportVSCode 将 VS Code 颜色主题（允许注释和尾随逗号）转换为主题颜色
+// tokenColors 按 TextMate 作用域前缀匹配，同等精确度时后出现的规则优先
+func ImportVSCode(data []byte) (*Imported, error) {
+	var theme vscodeTheme
+	if err := json.Unmarshal(stripJSONC(data), &theme); err != nil {
+		return nil, fmt.Errorf("invalid VS Code theme: %w", err)
+	}
+
+	color := func(key string) (Color, bool) {
+		value, ok := theme.Colors[key]
+		if !ok {
+			return Color{}, false
+		}
+		c, err := Parse(value)
+		return c, err == nil
+	}
+
+	bg, ok := color("editor.background")
+	if !ok {
+		return nil, errors.New("invalid VS Code theme: missing editor.background")
+	}
+	dark := theme.Type != "light" && theme.Type != "hcLight"
+	if theme.Type == "" {
+		dark = bg.IsDark()
+	}
+	fg, ok := color("editor.foreground")
+	if !ok {
+		fg, ok = color("foreground")
+	}
+	if !ok {
+		fg = Mix(bg, Color{R: 255, G: 255, B: 255, A: 255}, 0.85)
+		if !dark {
+			fg = Mix(bg, Color{A: 255}, 0.85)
+		}
+	}
+
+	var rules []vscodeTokenRule
+	if len(theme.TokenColors) > 0 && theme.TokenColors[0] == '[' {
+		if err := json.Unmarshal(theme.TokenColors, &rules); err != nil {
+			return nil, fmt.Errorf("invalid VS Code theme tokenColors: %w", err)
+		}
+	}
+	matcher := newScopeMatcher(rules)
+
+	scheme := Scheme{Dark: dark, Background: bg, Foreground: fg, Roles: map[Role]Color{}, Tags: map[string]Color{}}
+	if c, ok := color("editorCursor.foreground"); ok {
+		scheme.Cursor = &c
+	}
+	if c, ok := color("editor.selectionBackground"); ok {
+		scheme.Selection = &c
+	}
+	for role, scope := range roleScopes {
+		if c, ok := matcher.match(scope); ok {
+			scheme.Roles[role] = c
+		}
+	}
+	for tag, scopes := range tagScopes {
+		for _, scope := range scopes {
+			if c, ok := matcher.match(scope); ok {
+				scheme.Tags[tag] = c
+				break
+			}
+		}
+	}
+
+	colors := Build(theme.Name, scheme)
+	for key, vscodeKey := range map[string]string{
+		"backgroundSecondary": "sideBar.background",
+		"activeLine":          "editor.lineHighlightBackground",
+		"lineNumber":          "editorLineNumber.foreground",
+		"activeLineNumber":    "editorLineNumber.activeForeground",
+		"borderColor":         "editorGroup.border",
+		"matchingBracket":     "editorBracketMatch.background",
+		"searchMatch":         "editor.findMatchHighlightBackground",
+		"searchMatchSelected": "editor.findMatchBackground",
+		"diffInserted":        "gitDecoration.addedResourceForeground",
+		"diffDeleted":         "gitDecoration.deletedResourceForeground",
+		"diffChanged":         "gitDecoration.modifiedResourceForeground",
+	} {
+		if c, ok := color(vscodeKey); ok {
+			colors[key] = c.Hex()
+		}
+	}
+	return &Imported{Name: theme.Name, Colors: colors}, nil
+}
+
+// scopeRule 展开后的单个作用域规则
+type scopeRule struct {
+	scope string
+	color Color
+	order int
+}
+
+// scopeMatcher TextMate 作用域匹配
+type scopeMatcher struct {
+	rules []scopeRule
+}
+
+// newScopeMatcher 展开规则中的多个作用域，忽略后代选择器的前缀和排除项
+func newScopeMatcher(rules []vscodeTokenRule) *scopeMatcher {
+	m := &scopeMatcher{}
+	for i, rule := range rules {
+		c, err := Parse(rule.Settings.Foreground)
+		if err != nil {
+			continue
+		}
+
+		var scopes []string
+		var single string
+		if err := json.Unmarshal(rule.Scope, &single); err == nil {
+			scopes = strings.Split(single, ",")
+		} else {
+			json.Unmarshal(rule.Scope, &scopes)
+		}
+		for _, scope := range scopes {
+			scope, _, _ = strings.Cut(scope, " -")
+			fields := strings.Fields(scope)
+			if len(fields) == 0 {
+				continue
+			}
+			m.rules = append(m.rules, scopeRule{scope: fields[len(fields)-1], color: c, order: i})
+		}
+	}
+	return m
+}
+
+// match 查找最精确匹配目标作用域的规则
+func (m *scopeMatcher) match(target string) (Color, bool) {
+	best, bestLen := Color{}, -1
+	for _, rule := range m.rules {
+		if rule.scope != target && !strings.HasPrefix(target, rule.scope+".") {
+			continue
+		}
+		// 规则按顺序遍历，同等长度时后出现的覆盖先出现的
+		if len(rule.scope) >= bestLen {
+			best, bestLen = rule.color, len(rule.scope)
+		}
+	}
+	return best, bestLen >= 0
+}
+
+// stripJSONC 去除 JSON 中的注释和尾随逗号
+func stripJSONC(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString, escaped := false, false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out = append(out, c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			i--
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		case c == ']' || c == '}':
+			// 删除前面的尾随逗号
+			j := len(out) - 1
+			for j >= 0 && (out[j] == ' ' || out[j] == '\t' || out[j] == '\n' || out[j] == '\r') {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
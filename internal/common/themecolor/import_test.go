@@ -0,0 +1,81 @@
+package themecolor
+
+import "testing"
+
+func TestImportBase16(t *testing.T) {
+	scheme := `
+scheme: "Tomorrow Night"
+author: "Chris Kempson"
+base00: "1d1f21"
+base01: "282a2e"
+base02: "373b41"
+base03: "969896"
+base04: "b4b7b4"
+base05: "c5c8c6"
+base06: "e0e0e0"
+base07: "ffffff"
+base08: "cc6666"
+base09: "de935f"
+base0A: "f0c674"
+base0B: "b5bd68"
+base0C: "8abeb7"
+base0D: "81a2be"
+base0E: "b294bb"
+base0F: "a3685a"
+`
+	imported, err := Import([]byte(scheme))
+	if err != nil {
+		t.Fatal(err)
+	}
+	colors := imported.Colors
+	if imported.Name != "Tomorrow Night" || colors["dark"] != true || colors["background"] != "#1d1f21" {
+		t.Fatalf("imported = %s %v %v", imported.Name, colors["dark"], colors["background"])
+	}
+	if colors["keyword"] != "#b294bb" || colors["string"] != "#b5bd68" || colors["function"] != "#81a2be" {
+		t.Fatalf("syntax colors = %v %v %v", colors["keyword"], colors["string"], colors["function"])
+	}
+}
+
+func TestImportVSCode(t *testing.T) {
+	theme := `{
+	// 注释和尾随逗号
+	"name": "Sample Light",
+	"type": "light",
+	"colors": {
+		"editor.background": "#ffffff",
+		"editor.foreground": "#333333",
+		"editorLineNumber.foreground": "#999999",
+	},
+	"tokenColors": [
+		{"scope": "keyword", "settings": {"foreground": "#0000ff"}},
+		{"scope": ["keyword.control", "storage.type"], "settings": {"foreground": "#af00db"}},
+		{"scope": "string, string.quoted", "settings": {"foreground": "#a31515"}},
+		{"scope": "source.js comment", "settings": {"foreground": "#008000", "fontStyle": "italic"}},
+		{"scope": "entity.name.function", "settings": {"foreground": "#795e26"}},
+		{"scope": "constant.numeric", "settings": {"foreground": "#098658"}},
+	],
+}`
+	imported, err := Import([]byte(theme))
+	if err != nil {
+		t.Fatal(err)
+	}
+	colors := imported.Colors
+	want := map[string]string{
+		"background":     "#ffffff",
+		"keyword":        "#0000ff",
+		"controlKeyword": "#af00db",
+		"string":         "#a31515",
+		"comment":        "#008000",
+		"function":       "#795e26",
+		"number":         "#098658",
+		"lineNumber":     "#999999",
+	}
+	for key, value := range want {
+		if colors[key] != value {
+			t.Errorf("%s = %v, want %s", key, colors[key], value)
+		}
+	}
+	if colors["dark"] != false {
+		t.Fatal("expected light theme")
+	}
+}
@@ -0,0 +1,121 @@
+package themecolor
+
+import (
+	"image"
+	"sort"
+)
+
+// paletteSampleSize 提取调色板时的采样边长，大图按网格抽样
+const paletteSampleSize = 96
+
+// Swatch 调色板中的颜色及其像素占比
+type Swatch struct {
+	Color      Color   `json:"color"`
+	Population float64 `json:"population"` // 占全部采样像素的比例
+}
+
+// Extract 用中位切分法从图片中提取至多 count 种代表色，按占比从高到低排序
+func Extract(img image.Image, count int) []Swatch {
+	if count <= 0 {
+		return nil
+	}
+
+	pixels := samplePixels(img)
+	if len(pixels) == 0 {
+		return nil
+	}
+
+	boxes := [][]Color{pixels}
+	for len(boxes) < count {
+		// 切分颜色范围最大的盒子
+		index, channel, span := -1, 0, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			if c, s := widestChannel(box); s > span {
+				index, channel, span = i, c, s
+			}
+		}
+		if index < 0 || span == 0 {
+			break
+		}
+
+		box := boxes[index]
+		sort.Slice(box, func(i, j int) bool { return channelOf(box[i], channel) < channelOf(box[j], channel) })
+		mid := len(box) / 2
+		boxes[index] = box[:mid]
+		boxes = append(boxes, box[mid:])
+	}
+
+	swatches := make([]Swatch, 0, len(boxes))
+	for _, box := range boxes {
+		swatches = append(swatches, Swatch{Color: average(box), Population: float64(len(box)) / float64(len(pixels))})
+	}
+	sort.SliceStable(swatches, func(i, j int) bool { return swatches[i].Population > swatches[j].Population })
+	return swatches
+}
+
+// samplePixels 按网格抽样不透明像素
+func samplePixels(img image.Image) []Color {
+	bounds := img.Bounds()
+	stepX := max(1, bounds.Dx()/paletteSampleSize)
+	stepY := max(1, bounds.Dy()/paletteSampleSize)
+
+	pixels := make([]Color, 0, paletteSampleSize*paletteSampleSize)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += stepY {
+		for x := bounds.Min.X; x < bounds.Max.X; x += stepX {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			// 预乘透明度还原
+			pixels = append(pixels, Color{
+				R: uint8(r * 0xffff / a >> 8),
+				G: uint8(g * 0xffff / a >> 8),
+				B: uint8(b * 0xffff / a >> 8),
+				A: 255,
+			})
+		}
+	}
+	return pixels
+}
+
+// widestChannel 返回取值范围最大的通道及其范围
+func widestChannel(box []Color) (int, int) {
+	best, span := 0, -1
+	for channel := 0; channel < 3; channel++ {
+		lo, hi := 255, 0
+		for _, c := range box {
+			v := channelOf(c, channel)
+			lo, hi = min(lo, v), max(hi, v)
+		}
+		if hi-lo > span {
+			best, span = channel, hi-lo
+		}
+	}
+	return best, span
+}
+
+// channelOf 读取 RGB 通道
+func channelOf(c Color, channel int) int {
+	switch channel {
+	case 0:
+		return int(c.R)
+	case 1:
+		return int(c.G)
+	}
+	return int(c.B)
+}
+
+// average 计算平均色
+func average(box []Color) Color {
+	var r, g, b int
+	for _, c := range box {
+		r += int(c.R)
+		g += int(c.G)
+		b += int(c.B)
+	}
+	n := len(box)
+	return Color{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: 255}
+}
@@ -0,0 +1,228 @@
+package themecolor
+
+import (
+	"math"
+	"sort"
+)
+
+// 文本与背景的最低对比度（WCAG AA）
+const (
+	MinTextContrast  = 4.5 // 正文
+	MinLargeContrast = 3.0 // 大号文字和界面元素
+)
+
+// Role 语法高亮中的颜色角色，同一角色下的标签使用相同或相近的颜色
+type Role string
+
+const (
+	RoleComment     Role = "comment"
+	RoleKeyword     Role = "keyword"
+	RoleString      Role = "string"
+	RoleNumber      Role = "number"
+	RoleType        Role = "type"
+	RoleFunction    Role = "function"
+	RoleVariable    Role = "variable"
+	RoleProperty    Role = "property"
+	RoleOperator    Role = "operator"
+	RolePunctuation Role = "punctuation"
+	RoleHeading     Role = "heading"
+	RoleLink        Role = "link"
+	RoleInserted    Role = "inserted"
+	RoleDeleted     Role = "deleted"
+	RoleChanged     Role = "changed"
+	RoleInvalid     Role = "invalid"
+)
+
+// AccentRoles 需要从调色板分配强调色的角色，按重要程度排序
+var AccentRoles = []Role{RoleKeyword, RoleString, RoleFunction, RoleType, RoleNumber, RoleProperty, RoleOperator, RoleHeading, RoleLink}
+
+// RoleTags 角色到前端 ThemeColors 语法标签的映射
+var RoleTags = map[Role][]string{
+	RoleComment:     {"comment", "lineComment", "blockComment", "docComment", "meta", "documentMeta", "annotation", "processingInstruction"},
+	RoleKeyword:     {"keyword", "self", "modifier", "operatorKeyword", "controlKeyword", "definitionKeyword", "moduleKeyword"},
+	RoleString:      {"string", "docString", "character", "attributeValue", "regexp", "escape"},
+	RoleNumber:      {"literal", "number", "integer", "float", "bool", "null", "atom", "unit", "constant", "color"},
+	RoleType:        {"typeName", "className", "namespace", "tagName", "macroName"},
+	RoleFunction:    {"function", "definition", "labelName", "standard", "special"},
+	RoleVariable:    {"name", "variableName", "local", "content"},
+	RoleProperty:    {"propertyName", "attributeName"},
+	RoleOperator:    {"operator", "derefOperator", "arithmeticOperator", "logicOperator", "bitwiseOperator", "compareOperator", "updateOperator", "definitionOperator", "typeOperator", "controlOperator"},
+	RolePunctuation: {"punctuation", "separator", "bracket", "angleBracket", "squareBracket", "paren", "brace", "contentSeparator", "list", "quote"},
+	RoleHeading:     {"heading", "heading1", "heading2", "heading3", "heading4", "heading5", "heading6", "strong", "emphasis", "monospace", "strikethrough"},
+	RoleLink:        {"link", "url"},
+	RoleInserted:    {"inserted"},
+	RoleDeleted:     {"deleted"},
+	RoleChanged:     {"changed"},
+	RoleInvalid:     {"invalid"},
+}
+
+// SyntaxTags 全部语法标签，按名称排序
+func SyntaxTags() []string {
+	var tags []string
+	for _, roleTags := range RoleTags {
+		tags = append(tags, roleTags...)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// IsSyntaxTag 是否为语法标签
+func IsSyntaxTag(key string) bool {
+	for _, roleTags := range RoleTags {
+		for _, tag := range roleTags {
+			if tag == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Scheme 主题的基础颜色和各角色颜色，未设置的角色由前景色推导
+type Scheme struct {
+	Dark       bool
+	Background Color
+	Foreground Color
+	Cursor     *Color
+	Selection  *Color
+	Roles      map[Role]Color
+	Tags       map[string]Color // 单个标签的颜色，优先于角色颜色
+}
+
+// Build 生成完整的主题颜色配置，键与前端 ThemeColors 一致
+func Build(name string, scheme Scheme) map[string]interface{} {
+	bg, fg := scheme.Background, EnsureContrast(scheme.Foreground, scheme.Background, MinTextContrast)
+	toward := Color{R: 255, G: 255, B: 255, A: 255}
+	if !scheme.Dark {
+		toward = Color{A: 255}
+	}
+
+	role := func(r Role, fallback Color) Color {
+		if c, ok := scheme.Roles[r]; ok {
+			return c
+		}
+		return fallback
+	}
+
+	colors := map[string]interface{}{
+		"themeName":                  name,
+		"dark":                       scheme.Dark,
+		"background":                 bg.Hex(),
+		"backgroundSecondary":        Mix(bg, toward, 0.06).Hex(),
+		"foreground":                 fg.Hex(),
+		"borderColor":                Mix(bg, Color{A: 255}, 0.25).Hex(),
+		"activeLine":                 fg.WithAlpha(0x0a).Hex(),
+		"lineNumber":                 fg.WithAlpha(0x4d).Hex(),
+		"activeLineNumber":           fg.WithAlpha(0xb3).Hex(),
+		"matchingBracket":            fg.WithAlpha(0x26).Hex(),
+		"searchMatch":                role(RoleChanged, MustParse("#fadc51")).WithAlpha(0x80).Hex(),
+		"searchMatchSelected":        role(RoleNumber, MustParse("#ff8c00")).WithAlpha(0xb3).Hex(),
+		"searchMatchSelectedOutline": role(RoleNumber, MustParse("#ff6600")).Hex(),
+	}
+
+	accent := role(RoleKeyword, fg)
+	if scheme.Cursor != nil {
+		colors["cursor"] = scheme.Cursor.Hex()
+	} else {
+		colors["cursor"] = fg.Hex()
+	}
+	if scheme.Selection != nil {
+		colors["selection"] = scheme.Selection.Hex()
+	} else {
+		colors["selection"] = Mix(bg, accent, 0.35).Hex()
+	}
+
+	defaults := map[Role]Color{
+		RoleComment:     Mix(fg, bg, 0.45),
+		RoleVariable:    fg,
+		RolePunctuation: Mix(fg, bg, 0.2),
+		RoleInserted:    MustParse("#64d189"),
+		RoleDeleted:     MustParse("#ff6b6b"),
+		RoleChanged:     MustParse("#ffb86c"),
+		RoleInvalid:     MustParse("#ff5555"),
+	}
+	for r, tags := range RoleTags {
+		c := role(r, defaults[r])
+		if c == (Color{}) {
+			c = fg
+		}
+		minRatio := MinTextContrast
+		if r == RoleComment {
+			minRatio = MinLargeContrast
+		}
+		c = EnsureContrast(c, bg, minRatio)
+		for _, tag := range tags {
+			colors[tag] = c.Hex()
+		}
+	}
+	for tag, c := range scheme.Tags {
+		colors[tag] = EnsureContrast(c, bg, MinLargeContrast).Hex()
+	}
+	colors["diffInserted"] = colors["inserted"]
+	colors["diffDeleted"] = colors["deleted"]
+	colors["diffChanged"] = colors["changed"]
+
+	return colors
+}
+
+// FromPalette 由调色板生成主题：占比最高的颜色决定背景和明暗，其余颜色按饱和度分配给语法角色
+func FromPalette(name string, swatches []Swatch) map[string]interface{} {
+	if len(swatches) == 0 {
+		swatches = []Swatch{{Color: MustParse("#252b37"), Population: 1}}
+	}
+
+	base := swatches[0].Color
+	dark := base.Luminance() < 0.4
+	h, s, _ := base.HSL()
+	// 背景降低饱和度并压到合适的亮度，避免大面积高饱和色
+	var bg, fg Color
+	if dark {
+		bg = FromHSL(h, math.Min(s, 0.35), 0.13)
+		fg = FromHSL(h, math.Min(s, 0.15), 0.9)
+	} else {
+		bg = FromHSL(h, math.Min(s, 0.3), 0.96)
+		fg = FromHSL(h, math.Min(s, 0.2), 0.15)
+	}
+
+	accents := pickAccents(swatches[1:], len(AccentRoles))
+	roles := make(map[Role]Color, len(AccentRoles))
+	for i, r := range AccentRoles {
+		c := accents[i%len(accents)]
+		if i >= len(accents) {
+			// 颜色不够时旋转色相补足
+			ah, as, al := c.HSL()
+			c = FromHSL(ah+float64(i/len(accents))*47, as, al)
+		}
+		roles[r] = EnsureContrast(c, bg, MinTextContrast)
+	}
+
+	return Build(name, Scheme{Dark: dark, Background: bg, Foreground: fg, Roles: roles})
+}
+
+// pickAccents 选出饱和度较高的颜色作为强调色，调色板全为灰色时使用默认蓝色
+func pickAccents(swatches []Swatch, count int) []Color {
+	var accents []Color
+	for _, swatch := range swatches {
+		if _, s, l := swatch.Color.HSL(); s >= 0.2 && l > 0.1 && l < 0.9 {
+			accents = append(accents, swatch.Color)
+		}
+	}
+	sort.SliceStable(accents, func(i, j int) bool {
+		_, si, _ := accents[i].HSL()
+		_, sj, _ := accents[j].HSL()
+		return si > sj
+	})
+
+	if len(accents) == 0 {
+		accents = append(accents, FromHSL(210, 0.6, 0.6))
+	}
+	if len(accents) > count {
+		accents = accents[:count]
+	}
+	// 饱和度偏低的强调色适当提高，保证语法颜色可区分
+	for i, c := range accents {
+		h, s, l := c.HSL()
+		accents[i] = FromHSL(h, math.Max(s, 0.45), math.Min(math.Max(l, 0.45), 0.7))
+	}
+	return accents
+}
@@ -0,0 +1,71 @@
+package themecolor
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestParseAndContrast(t *testing.T) {
+	for in, want := range map[string]string{
+		"#FFF":                    "#ffffff",
+		"#252B37":                 "#252b37",
+		"#ffffff0a":               "#ffffff0a",
+		"rgba(250, 220, 81, 0.7)": "#fadc51b3",
+		"rgb(0 0 0)":              "#000000",
+	} {
+		c, err := Parse(in)
+		if err != nil || c.Hex() != want {
+			t.Errorf("Parse(%q) = %s, %v; want %s", in, c.Hex(), err, want)
+		}
+	}
+
+	if ratio := Contrast(MustParse("#000"), MustParse("#fff")); math.Abs(ratio-21) > 0.01 {
+		t.Fatalf("black on white = %.2f", ratio)
+	}
+	fixed := EnsureContrast(MustParse("#444"), MustParse("#222"), MinTextContrast)
+	if Contrast(fixed, MustParse("#222")) < MinTextContrast {
+		t.Fatalf("EnsureContrast returned %s", fixed.Hex())
+	}
+}
+
+func TestFromPalette(t *testing.T) {
+	// 左侧深蓝占多数，右侧橙色和绿色条纹
+	img := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			c := color.RGBA{R: 20, G: 30, B: 70, A: 255}
+			switch {
+			case x >= 80:
+				c = color.RGBA{R: 240, G: 140, B: 30, A: 255}
+			case x >= 65:
+				c = color.RGBA{R: 60, G: 200, B: 90, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+
+	palette := Extract(img, 6)
+	if len(palette) == 0 || palette[0].Color != (Color{R: 20, G: 30, B: 70, A: 255}) {
+		t.Fatalf("palette = %+v", palette)
+	}
+
+	colors := FromPalette("wallpaper", palette)
+	if colors["dark"] != true || colors["themeName"] != "wallpaper" {
+		t.Fatalf("colors = %v", colors)
+	}
+	bg := MustParse(colors["background"].(string))
+	for role, tags := range RoleTags {
+		minRatio := MinTextContrast
+		if role == RoleComment {
+			minRatio = MinLargeContrast
+		}
+		for _, tag := range tags {
+			c := MustParse(colors[tag].(string))
+			if ratio := Contrast(c, bg); ratio < minRatio {
+				t.Errorf("%s contrast %.2f below %.1f", tag, ratio, minRatio)
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package themecolor
+
+// ContrastIssue 对比度不足的颜色键
+type ContrastIssue struct {
+	Key        string  `json:"key"`        // 对比度不足的颜色键，自动修正时调整该键
+	Against    string  `json:"against"`    // 参与比较的另一颜色键
+	Foreground string  `json:"foreground"` // 实际显示的前景色
+	Background string  `json:"background"` // 实际显示的背景色
+	Ratio      float64 `json:"ratio"`
+	Required   float64 `json:"required"`
+}
+
+// contrastCheck 一组需要检查对比度的颜色
+type contrastCheck struct {
+	key      string
+	against  string
+	required float64
+	layer    bool // key 为叠加在 background 上的背景层，against 为其上的文字颜色
+}
+
+// contrastChecks 检查项，前景色先于背景层检查，保证修正背景层时使用修正后的前景色
+func contrastChecks() []contrastCheck {
+	checks := []contrastCheck{{key: "foreground", against: "background", required: MinTextContrast}}
+
+	comments := make(map[string]bool)
+	for _, tag := range RoleTags[RoleComment] {
+		comments[tag] = true
+	}
+	for _, tag := range SyntaxTags() {
+		// 注释与 Build 保持一致，只要求大号文字的对比度
+		required := MinTextContrast
+		if comments[tag] {
+			required = MinLargeContrast
+		}
+		checks = append(checks, contrastCheck{key: tag, against: "background", required: required})
+	}
+
+	for _, key := range []string{"cursor", "lineNumber", "activeLineNumber"} {
+		checks = append(checks, contrastCheck{key: key, against: "background", required: MinLargeContrast})
+	}
+	for _, key := range []string{"selection", "activeLine"} {
+		checks = append(checks, contrastCheck{key: key, against: "foreground", required: MinTextContrast, layer: true})
+	}
+	return checks
+}
+
+// Validate 按 WCAG 计算主题中前景与背景颜色的对比度，返回不达标的键，无法解析的颜色跳过
+func Validate(colors map[string]interface{}) []ContrastIssue {
+	var issues []ContrastIssue
+	for _, check := range contrastChecks() {
+		fg, bg, ok := resolvePair(colors, check)
+		if !ok {
+			continue
+		}
+		if ratio := Contrast(fg, bg); ratio < check.required {
+			issues = append(issues, ContrastIssue{
+				Key:        check.key,
+				Against:    check.against,
+				Foreground: fg.Over(bg).Hex(),
+				Background: bg.Hex(),
+				Ratio:      ratio,
+				Required:   check.required,
+			})
+		}
+	}
+	return issues
+}
+
+// Fix 返回修正后的副本：文字颜色调整亮度，背景层向背景色靠拢，直到对比度达标
+func Fix(colors map[string]interface{}) map[string]interface{} {
+	fixed := make(map[string]interface{}, len(colors))
+	for key, value := range colors {
+		fixed[key] = value
+	}
+
+	for _, check := range contrastChecks() {
+		fg, bg, ok := resolvePair(fixed, check)
+		if !ok || Contrast(fg, bg) >= check.required {
+			continue
+		}
+		if !check.layer {
+			fixed[check.key] = EnsureContrast(fg, bg, check.required).Hex()
+			continue
+		}
+
+		background, _ := colorAt(fixed, "background")
+		layer := bg
+		for t := 0.1; t <= 1; t += 0.1 {
+			layer = Mix(bg, background, t)
+			if Contrast(fg, layer) >= check.required {
+				break
+			}
+		}
+		fixed[check.key] = layer.Hex()
+	}
+	return fixed
+}
+
+// resolvePair 取出检查项实际显示的前景色和背景色，背景层按叠加到 background 后计算
+func resolvePair(colors map[string]interface{}, check contrastCheck) (fg, bg Color, ok bool) {
+	key, ok := colorAt(colors, check.key)
+	if !ok {
+		return Color{}, Color{}, false
+	}
+	against, ok := colorAt(colors, check.against)
+	if !ok {
+		return Color{}, Color{}, false
+	}
+	if !check.layer {
+		return key, against, true
+	}
+
+	background, ok := colorAt(colors, "background")
+	if !ok {
+		return Color{}, Color{}, false
+	}
+	return against, key.Over(background), true
+}
+
+// colorAt 读取并解析颜色键
+func colorAt(colors map[string]interface{}, key string) (Color, bool) {
+	value, ok := colors[key].(string)
+	if !ok {
+		return Color{}, false
+	}
+	c, err := Parse(value)
+	return c, err == nil
+}
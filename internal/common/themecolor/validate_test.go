@@ -0,0 +1,40 @@
+package themecolor
+
+import "testing"
+
+func TestValidateAndFix(t *testing.T) {
+	colors := map[string]interface{}{
+		"themeName":  "low",
+		"background": "#1e1e1e",
+		"foreground": "#5a5a5a",
+		"keyword":    "#569cd6",
+		"comment":    "#333333",
+		"selection":  "#d0d0d0",
+		"cursor":     "not a color",
+	}
+
+	issues := Validate(colors)
+	failing := make(map[string]bool)
+	for _, issue := range issues {
+		failing[issue.Key] = true
+		if issue.Ratio >= issue.Required {
+			t.Errorf("issue %+v passes", issue)
+		}
+	}
+	for _, key := range []string{"foreground", "comment", "selection"} {
+		if !failing[key] {
+			t.Errorf("%s not flagged: %+v", key, issues)
+		}
+	}
+	if failing["keyword"] || failing["cursor"] {
+		t.Errorf("unexpected issues: %+v", issues)
+	}
+
+	fixed := Fix(colors)
+	if remaining := Validate(fixed); len(remaining) != 0 {
+		t.Fatalf("issues after fix: %+v", remaining)
+	}
+	if colors["foreground"] != "#5a5a5a" || fixed["keyword"] != "#569cd6" || fixed["cursor"] != "not a color" {
+		t.Fatalf("Fix changed input or passing keys: %v", fixed)
+	}
+}
@@ -0,0 +1,424 @@
+// Package timeparse 解析时间戳、ISO 时间字符串和自然语言短语（如 "next friday 4pm CET"），
+// 并换算到多个时区。内嵌 IANA 时区数据库，不依赖系统时区文件
+package timeparse
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata"
+)
+
+// Result 解析结果
+type Result struct {
+	Unix      int64      `json:"unix"`      // Unix 时间戳（秒）
+	UnixMilli int64      `json:"unixMilli"` // Unix 时间戳（毫秒）
+	ISO       string     `json:"iso"`       // UTC 时间，RFC 3339 格式
+	Zones     []ZoneTime `json:"zones"`     // 各时区的本地时间
+}
+
+// ZoneTime 某个时区中的时间
+type ZoneTime struct {
+	Zone         string `json:"zone"`         // 请求的时区名称
+	Time         string `json:"time"`         // 该时区的本地时间，RFC 3339 格式
+	Abbreviation string `json:"abbreviation"` // 时区缩写，如 CET
+	Offset       string `json:"offset"`       // UTC 偏移，如 +01:00
+}
+
+// abbreviations 常用时区缩写对应的固定偏移（小时），CST 按北美中部时间处理
+var abbreviations = map[string]float64{
+	"UTC": 0, "GMT": 0, "Z": 0,
+	"WET": 0, "WEST": 1, "BST": 1, "CET": 1, "CEST": 2, "EET": 2, "EEST": 3, "MSK": 3,
+	"IST": 5.5, "SGT": 8, "HKT": 8, "AWST": 8, "JST": 9, "KST": 9,
+	"ACST": 9.5, "AEST": 10, "AEDT": 11, "NZST": 12, "NZDT": 13,
+	"EST": -5, "EDT": -4, "CST": -6, "CDT": -5, "MST": -7, "MDT": -6,
+	"PST": -8, "PDT": -7, "AKST": -9, "AKDT": -8, "HST": -10,
+}
+
+var (
+	epochPattern  = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+	offsetPattern = regexp.MustCompile(`^(?i:utc|gmt)?([+-])(\d{1,2})(?::?(\d{2}))?$`)
+	clockPattern  = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(?::(\d{2}))?(am|pm|a\.m\.|p\.m\.)?$`)
+)
+
+// layouts 支持的日期时间格式，不含时区的格式按指定时区解释
+var layouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 02 Jan 2006 15:04:05",
+	time.RFC850,
+	time.RFC822Z,
+	time.RFC822,
+	time.ANSIC,
+	time.UnixDate,
+	"Jan 2 2006 15:04",
+	"Jan 2 2006",
+	"Jan 2, 2006 15:04",
+	"Jan 2, 2006",
+	"2 Jan 2006 15:04",
+	"2 Jan 2006",
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// LoadZone 解析时区：IANA 名称、常用缩写、UTC 偏移（+08:00、UTC+8），空字符串和 Local 表示本地时区
+func LoadZone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "local") {
+		return time.Local, nil
+	}
+	if hours, ok := abbreviations[strings.ToUpper(name)]; ok {
+		return time.FixedZone(strings.ToUpper(name), int(hours*3600)), nil
+	}
+	if match := offsetPattern.FindStringSubmatch(name); match != nil {
+		hours, _ := strconv.Atoi(match[2])
+		minutes, _ := strconv.Atoi(match[3])
+		if hours > 14 || minutes > 59 {
+			return nil, fmt.Errorf("invalid offset: %s", name)
+		}
+		seconds := hours*3600 + minutes*60
+		if match[1] == "-" {
+			seconds = -seconds
+		}
+		return time.FixedZone(formatOffset(seconds), seconds), nil
+	}
+	if loc, err := time.LoadLocation(name); err == nil {
+		return loc, nil
+	}
+	return nil, fmt.Errorf("unknown time zone: %s", name)
+}
+
+// Parse 解析时间输入，loc 为输入中没有时区时使用的时区，now 为相对时间的基准
+//
+// 支持的输入：
+//   - Unix 时间戳，按位数识别秒、毫秒、微秒、纳秒
+//   - ISO 8601 / RFC 3339 等常见格式
+//   - 自然语言短语：now、today、tomorrow、friday、next friday 4pm、in 3 days、2 hours ago，
+//     末尾可带时区，如 "next friday 4pm CET"、"tomorrow 9:30 Asia/Shanghai"
+func Parse(input string, now time.Time, loc *time.Location) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return time.Time{}, errors.New("input is empty")
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	if epochPattern.MatchString(input) {
+		return parseEpoch(input)
+	}
+
+	// 末尾的时区
+	fields := strings.Fields(input)
+	stripped := input
+	if len(fields) > 1 {
+		if zone, err := LoadZone(fields[len(fields)-1]); err == nil {
+			loc = zone
+			fields = fields[:len(fields)-1]
+			stripped = strings.Join(fields, " ")
+		}
+	}
+
+	for _, candidate := range []string{stripped, input} {
+		for _, layout := range layouts {
+			if t, err := time.ParseInLocation(layout, candidate, loc); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return parsePhrase(fields, now.In(loc))
+}
+
+// parseEpoch 按位数识别时间戳精度
+func parseEpoch(input string) (time.Time, error) {
+	if strings.Contains(input, ".") {
+		seconds, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp: %w", err)
+		}
+		whole, frac := math.Modf(seconds)
+		return time.Unix(int64(whole), int64(math.Round(frac*1e9))).UTC(), nil
+	}
+	n, err := strconv.ParseInt(input, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp: %w", err)
+	}
+	digits := len(strings.TrimPrefix(input, "-"))
+	switch {
+	case digits <= 11:
+		return time.Unix(n, 0).UTC(), nil
+	case digits <= 14:
+		return time.UnixMilli(n).UTC(), nil
+	case digits <= 17:
+		return time.UnixMicro(n).UTC(), nil
+	}
+	return time.Unix(0, n).UTC(), nil
+}
+
+// parsePhrase 解析自然语言短语
+func parsePhrase(fields []string, now time.Time) (time.Time, error) {
+	loc := now.Location()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	result := now
+	clockSet := false
+	var clock time.Duration
+
+	for i := 0; i < len(fields); i++ {
+		word := strings.ToLower(strings.Trim(fields[i], ","))
+		next := func() string {
+			if i+1 < len(fields) {
+				return strings.ToLower(strings.Trim(fields[i+1], ","))
+			}
+			return ""
+		}
+
+		switch word {
+		case "", "at", "on":
+			continue
+		case "now":
+			result = now
+			continue
+		case "today":
+			result = day
+			continue
+		case "tonight":
+			result = day
+			if !clockSet {
+				clock, clockSet = 20*time.Hour, true
+			}
+			continue
+		case "tomorrow":
+			result = day.AddDate(0, 0, 1)
+			continue
+		case "yesterday":
+			result = day.AddDate(0, 0, -1)
+			continue
+		case "noon", "midday":
+			clock, clockSet = 12*time.Hour, true
+			continue
+		case "midnight":
+			clock, clockSet = 0, true
+			continue
+		case "next", "this", "last":
+			target := next()
+			i++
+			if weekday, ok := weekdays[target]; ok {
+				result = weekdayFrom(day, weekday, word)
+				continue
+			}
+			step := map[string]int{"next": 1, "this": 0, "last": -1}[word]
+			shifted, ok := shift(day, target, step)
+			if !ok {
+				return time.Time{}, fmt.Errorf("unrecognized phrase: %s %s", word, target)
+			}
+			result = shifted
+			continue
+		case "in":
+			amount, unit := next(), ""
+			if i+2 < len(fields) {
+				unit = strings.ToLower(fields[i+2])
+			}
+			n, ok := parseAmount(amount)
+			moved, unitOK := addUnit(result, unit, n)
+			if !ok || !unitOK {
+				return time.Time{}, fmt.Errorf("unrecognized phrase: in %s %s", amount, unit)
+			}
+			result = moved
+			i += 2
+			continue
+		}
+
+		if weekday, ok := weekdays[word]; ok {
+			result = weekdayFrom(day, weekday, "this")
+			continue
+		}
+		if t, err := time.ParseInLocation("2006-01-02", word, loc); err == nil {
+			result = t
+			continue
+		}
+		// N unit ago / +N unit
+		if n, ok := parseAmount(word); ok && i+1 < len(fields) {
+			unit := next()
+			if i+2 < len(fields) && strings.ToLower(fields[i+2]) == "ago" {
+				if moved, ok := addUnit(result, unit, -n); ok {
+					result = moved
+					i += 2
+					continue
+				}
+			} else if strings.HasPrefix(word, "+") || strings.HasPrefix(word, "-") {
+				if moved, ok := addUnit(result, unit, n); ok {
+					result = moved
+					i++
+					continue
+				}
+			}
+		}
+		// 时刻，am/pm 可能是单独的词
+		if suffix := next(); suffix == "am" || suffix == "pm" {
+			word += suffix
+			i++
+		}
+		if d, ok := parseClock(word); ok {
+			clock, clockSet = d, true
+			continue
+		}
+		return time.Time{}, fmt.Errorf("unrecognized phrase: %s", fields[i])
+	}
+
+	if clockSet {
+		result = time.Date(result.Year(), result.Month(), result.Day(), 0, 0, 0, 0, loc).Add(clock)
+	}
+	return result, nil
+}
+
+// weekdayFrom 计算星期几的日期：this 为今天或之后最近的一天，next 为今天之后，last 为今天之前
+func weekdayFrom(day time.Time, weekday time.Weekday, relation string) time.Time {
+	diff := (int(weekday) - int(day.Weekday()) + 7) % 7
+	switch relation {
+	case "next":
+		if diff == 0 {
+			diff = 7
+		}
+	case "last":
+		diff -= 7
+	}
+	return day.AddDate(0, 0, diff)
+}
+
+// shift 处理 next/this/last week|month|year
+func shift(day time.Time, unit string, step int) (time.Time, bool) {
+	switch strings.TrimSuffix(unit, "s") {
+	case "day":
+		return day.AddDate(0, 0, step), true
+	case "week":
+		return day.AddDate(0, 0, 7*step), true
+	case "month":
+		return day.AddDate(0, step, 0), true
+	case "year":
+		return day.AddDate(step, 0, 0), true
+	}
+	return time.Time{}, false
+}
+
+// parseAmount 解析数量，支持 a/an
+func parseAmount(word string) (int, bool) {
+	if word == "a" || word == "an" {
+		return 1, true
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(word, "+"))
+	return n, err == nil
+}
+
+// addUnit 按单位加减时间
+func addUnit(t time.Time, unit string, n int) (time.Time, bool) {
+	unit = strings.ToLower(unit)
+	if len(unit) > 2 {
+		unit = strings.TrimSuffix(unit, "s")
+	}
+	switch unit {
+	case "s", "sec", "second":
+		return t.Add(time.Duration(n) * time.Second), true
+	case "m", "min", "minute":
+		return t.Add(time.Duration(n) * time.Minute), true
+	case "h", "hr", "hour":
+		return t.Add(time.Duration(n) * time.Hour), true
+	case "d", "day":
+		return t.AddDate(0, 0, n), true
+	case "w", "wk", "week":
+		return t.AddDate(0, 0, 7*n), true
+	case "mo", "mon", "month":
+		return t.AddDate(0, n, 0), true
+	case "y", "yr", "year":
+		return t.AddDate(n, 0, 0), true
+	}
+	return t, false
+}
+
+// parseClock 解析时刻：16:30、4pm、4:30pm、16:30:15
+func parseClock(word string) (time.Duration, bool) {
+	match := clockPattern.FindStringSubmatch(word)
+	if match == nil {
+		return 0, false
+	}
+	hour, _ := strconv.Atoi(match[1])
+	minute, _ := strconv.Atoi(match[2])
+	second, _ := strconv.Atoi(match[3])
+	meridiem := strings.ReplaceAll(match[4], ".", "")
+	if meridiem == "" && match[2] == "" {
+		// 单独的数字不视为时刻
+		return 0, false
+	}
+	if minute > 59 || second > 59 {
+		return 0, false
+	}
+	switch meridiem {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, false
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	default:
+		if hour > 23 {
+			return 0, false
+		}
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second, true
+}
+
+// Convert 将时间换算到多个时区
+func Convert(t time.Time, zones []string) (*Result, error) {
+	result := &Result{
+		Unix:      t.Unix(),
+		UnixMilli: t.UnixMilli(),
+		ISO:       t.UTC().Format(time.RFC3339Nano),
+		Zones:     make([]ZoneTime, 0, len(zones)),
+	}
+	for _, name := range zones {
+		loc, err := LoadZone(name)
+		if err != nil {
+			return nil, err
+		}
+		local := t.In(loc)
+		abbreviation, offset := local.Zone()
+		result.Zones = append(result.Zones, ZoneTime{
+			Zone:         name,
+			Time:         local.Format(time.RFC3339),
+			Abbreviation: abbreviation,
+			Offset:       formatOffset(offset),
+		})
+	}
+	return result, nil
+}
+
+// formatOffset 格式化 UTC 偏移，如 +05:30
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	return fmt.Sprintf("%s%02d:%02d", sign, seconds/3600, seconds%3600/60)
+}
@@ -0,0 +1,81 @@
+package timeparse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	shanghai, err := LoadZone("Asia/Shanghai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2024-05-15 是星期三
+	now := time.Date(2024, 5, 15, 10, 30, 0, 0, shanghai)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"1715740200", "2024-05-15T02:30:00Z"},
+		{"1715740200123", "2024-05-15T02:30:00.123Z"},
+		{"1715740200.5", "2024-05-15T02:30:00.5Z"},
+		{"2024-05-01T08:00:00+02:00", "2024-05-01T06:00:00Z"},
+		{"2024-05-01 08:00", "2024-05-01T00:00:00Z"},
+		{"2024-05-01 08:00 UTC", "2024-05-01T08:00:00Z"},
+		{"Wed, 01 May 2024 08:00:00 CEST", "2024-05-01T06:00:00Z"},
+		{"now", "2024-05-15T02:30:00Z"},
+		{"tomorrow", "2024-05-15T16:00:00Z"},
+		{"tomorrow 9:30", "2024-05-16T01:30:00Z"},
+		{"friday 4pm", "2024-05-17T08:00:00Z"},
+		{"next friday 4pm CET", "2024-05-17T15:00:00Z"},
+		{"next wednesday", "2024-05-21T16:00:00Z"},
+		{"wednesday noon", "2024-05-15T04:00:00Z"},
+		{"last monday at 9 am", "2024-05-13T01:00:00Z"},
+		{"in 3 days", "2024-05-18T02:30:00Z"},
+		{"in 2 hours", "2024-05-15T04:30:00Z"},
+		{"90 mins ago", "2024-05-15T01:00:00Z"},
+		{"next week", "2024-05-21T16:00:00Z"},
+		{"today 23:15 Europe/London", "2024-05-15T22:15:00Z"},
+		{"tomorrow 8am UTC+8", "2024-05-16T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.input, now, shanghai)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.input, err)
+			continue
+		}
+		if s := got.UTC().Format(time.RFC3339Nano); s != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, s, tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "someday", "in 3 parsecs", "25:00", "13pm"} {
+		if _, err := Parse(input, now, shanghai); err == nil {
+			t.Errorf("Parse(%q) expected error", input)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	result, err := Convert(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC), []string{"America/New_York", "Asia/Kolkata", "CET"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Unix != 1705320000 || result.ISO != "2024-01-15T12:00:00Z" {
+		t.Errorf("result = %+v", result)
+	}
+	want := []ZoneTime{
+		{"America/New_York", "2024-01-15T07:00:00-05:00", "EST", "-05:00"},
+		{"Asia/Kolkata", "2024-01-15T17:30:00+05:30", "IST", "+05:30"},
+		{"CET", "2024-01-15T13:00:00+01:00", "CET", "+01:00"},
+	}
+	for i, zone := range want {
+		if result.Zones[i] != zone {
+			t.Errorf("zone %d = %+v, want %+v", i, result.Zones[i], zone)
+		}
+	}
+	if _, err := Convert(time.Now(), []string{"Mars/Olympus"}); err == nil {
+		t.Error("expected error for unknown zone")
+	}
+}
@@ -0,0 +1,157 @@
+// Package totp 按 RFC 6238 生成基于时间的一次性密码，并解析 otpauth:// 配置链接
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultDigits    = 6
+	DefaultPeriod    = 30
+	DefaultAlgorithm = "SHA1"
+)
+
+// Key 一次性密码的参数
+type Key struct {
+	Issuer    string
+	Account   string
+	Secret    string // Base32 编码的密钥，已规范化为大写无填充
+	Algorithm string // SHA1、SHA256 或 SHA512
+	Digits    int
+	Period    int // 秒
+}
+
+// NormalizeSecret 去除空格、横线和填充并转为大写，校验是否为合法的 Base32
+func NormalizeSecret(secret string) (string, error) {
+	secret = strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "=", "").Replace(strings.TrimSpace(secret)))
+	if secret == "" {
+		return "", errors.New("secret is empty")
+	}
+	if _, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret); err != nil {
+		return "", errors.New("secret is not valid base32")
+	}
+	return secret, nil
+}
+
+// Parse 解析 otpauth://totp/ 链接或单独的 Base32 密钥
+func Parse(input string) (*Key, error) {
+	input = strings.TrimSpace(input)
+	if !strings.HasPrefix(strings.ToLower(input), "otpauth://") {
+		secret, err := NormalizeSecret(input)
+		if err != nil {
+			return nil, err
+		}
+		return &Key{Secret: secret, Algorithm: DefaultAlgorithm, Digits: DefaultDigits, Period: DefaultPeriod}, nil
+	}
+
+	u, err := url.Parse(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid otpauth link: %w", err)
+	}
+	if !strings.EqualFold(u.Host, "totp") {
+		return nil, fmt.Errorf("unsupported otp type: %s", u.Host)
+	}
+	query := u.Query()
+	key := &Key{Algorithm: DefaultAlgorithm, Digits: DefaultDigits, Period: DefaultPeriod}
+	if key.Secret, err = NormalizeSecret(query.Get("secret")); err != nil {
+		return nil, err
+	}
+
+	// 标签格式为 "发行方:账户" 或 "账户"
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		key.Issuer, key.Account = strings.TrimSpace(issuer), strings.TrimSpace(account)
+	} else {
+		key.Account = strings.TrimSpace(label)
+	}
+	if issuer := query.Get("issuer"); issuer != "" {
+		key.Issuer = issuer
+	}
+	if algorithm := query.Get("algorithm"); algorithm != "" {
+		key.Algorithm = strings.ToUpper(algorithm)
+	}
+	if digits := query.Get("digits"); digits != "" {
+		if key.Digits, err = strconv.Atoi(digits); err != nil {
+			return nil, fmt.Errorf("invalid digits: %s", digits)
+		}
+	}
+	if period := query.Get("period"); period != "" {
+		if key.Period, err = strconv.Atoi(period); err != nil {
+			return nil, fmt.Errorf("invalid period: %s", period)
+		}
+	}
+	if err := key.Validate(); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Validate 检查参数是否受支持
+func (k *Key) Validate() error {
+	if _, err := newHash(k.Algorithm); err != nil {
+		return err
+	}
+	if k.Digits < 6 || k.Digits > 8 {
+		return fmt.Errorf("unsupported digits: %d", k.Digits)
+	}
+	if k.Period <= 0 || k.Period > 300 {
+		return fmt.Errorf("unsupported period: %d", k.Period)
+	}
+	return nil
+}
+
+// Code 计算 t 时刻的密码，返回密码和剩余有效秒数
+func (k *Key) Code(t time.Time) (string, int, error) {
+	if err := k.Validate(); err != nil {
+		return "", 0, err
+	}
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(k.Secret)
+	if err != nil {
+		return "", 0, errors.New("secret is not valid base32")
+	}
+	newFn, _ := newHash(k.Algorithm)
+
+	unix := t.Unix()
+	counter := uint64(unix / int64(k.Period))
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(newFn, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// RFC 4226 动态截取
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < k.Digits; i++ {
+		mod *= 10
+	}
+	code := fmt.Sprintf("%0*d", k.Digits, value%mod)
+	remaining := k.Period - int(unix%int64(k.Period))
+	return code, remaining, nil
+}
+
+// newHash 算法名称对应的哈希
+func newHash(algorithm string) (func() hash.Hash, error) {
+	switch algorithm {
+	case "SHA1":
+		return sha1.New, nil
+	case "SHA256":
+		return sha256.New, nil
+	case "SHA512":
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("unsupported algorithm: %s", algorithm)
+}
@@ -0,0 +1,63 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// RFC 6238 附录 B 的测试向量
+func TestCodeRFC6238(t *testing.T) {
+	secrets := map[string]string{
+		"SHA1":   "12345678901234567890",
+		"SHA256": "12345678901234567890123456789012",
+		"SHA512": "1234567890123456789012345678901234567890123456789012345678901234",
+	}
+	tests := []struct {
+		unix      int64
+		algorithm string
+		want      string
+	}{
+		{59, "SHA1", "94287082"},
+		{59, "SHA256", "46119246"},
+		{59, "SHA512", "90693936"},
+		{1111111109, "SHA1", "07081804"},
+		{1234567890, "SHA256", "91819424"},
+		{20000000000, "SHA512", "47863826"},
+	}
+	for _, tt := range tests {
+		key := &Key{
+			Secret:    base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte(secrets[tt.algorithm])),
+			Algorithm: tt.algorithm,
+			Digits:    8,
+			Period:    30,
+		}
+		code, remaining, err := key.Code(time.Unix(tt.unix, 0))
+		if err != nil || code != tt.want {
+			t.Errorf("%s at %d = %s, %v; want %s", tt.algorithm, tt.unix, code, err, tt.want)
+		}
+		if want := 30 - int(tt.unix%30); remaining != want {
+			t.Errorf("remaining = %d, want %d", remaining, want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	key, err := Parse("otpauth://totp/ACME%20Co:john@example.com?secret=hxdm-vjec-jjws-rb3h&issuer=ACME+Co&algorithm=sha256&digits=8&period=60")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Key{Issuer: "ACME Co", Account: "john@example.com", Secret: "HXDMVJECJJWSRB3H", Algorithm: "SHA256", Digits: 8, Period: 60}
+	if *key != want {
+		t.Errorf("Parse() = %+v", key)
+	}
+
+	if key, err := Parse("jbsw y3dp ehpk 3pxp"); err != nil || key.Secret != "JBSWY3DPEHPK3PXP" || key.Digits != 6 {
+		t.Errorf("Parse(secret) = %+v, %v", key, err)
+	}
+	for _, input := range []string{"", "not base32!", "otpauth://hotp/x?secret=JBSWY3DP", "otpauth://totp/x?secret=JBSWY3DP&digits=12"} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) expected error", input)
+		}
+	}
+}
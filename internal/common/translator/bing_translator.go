@@ -68,11 +68,9 @@ var (
 // NewBingTranslator 创建一个新的Bing翻译器实例
 func NewBingTranslator() *BingTranslator {
 	translator := &BingTranslator{
-		httpClient: &http.Client{
-			Timeout: bingDefaultTimeout,
-		},
-		Timeout:   bingDefaultTimeout,
-		languages: initBingLanguages(),
+		httpClient: newHTTPClient(BingTranslatorType, bingDefaultTimeout),
+		Timeout:    bingDefaultTimeout,
+		languages:  initBingLanguages(),
 	}
 
 	return translator
@@ -96,7 +96,7 @@ func NewDeeplTranslator() *DeeplTranslator {
 	translator := &DeeplTranslator{
 		DeeplHost:  defaultDeeplHost,
 		Timeout:    deeplDefaultTimeout,
-		httpClient: &http.Client{Timeout: deeplDefaultTimeout},
+		httpClient: newHTTPClient(DeeplTranslatorType, deeplDefaultTimeout),
 		languages:  initDeeplLanguages(),
 	}
 
@@ -0,0 +1,143 @@
+package translator
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// ErrWordNotFound 词典中没有该词条
+var ErrWordNotFound = errors.New("word not found in dictionary")
+
+// Dictionary 可提供单词词典查询的翻译器
+type Dictionary interface {
+	// Lookup 查询单词的释义、词性、读音和例句
+	Lookup(word string, from string, to string) (*DictionaryEntry, error)
+}
+
+// DictionaryEntry 词条
+type DictionaryEntry struct {
+	Word          string            `json:"word"`
+	From          string            `json:"from"`
+	To            string            `json:"to"`
+	Pronunciation string            `json:"pronunciation"` // 读音转写，如拼音、罗马字
+	Translation   string            `json:"translation"`   // 最常用的译文
+	Senses        []DictionarySense `json:"senses"`        // 按词性分组的释义
+	Examples      []string          `json:"examples"`      // 例句
+}
+
+// DictionarySense 某一词性下的释义和译文
+type DictionarySense struct {
+	PartOfSpeech string                 `json:"partOfSpeech"`
+	Translations []string               `json:"translations"` // 目标语言中的对应词
+	Definitions  []DictionaryDefinition `json:"definitions"`  // 源语言释义
+}
+
+// DictionaryDefinition 单条释义
+type DictionaryDefinition struct {
+	Definition string `json:"definition"`
+	Example    string `json:"example,omitempty"`
+}
+
+// 每个词条保留的数量上限
+const (
+	dictionaryMaxTranslations = 8
+	dictionaryMaxDefinitions  = 5
+	dictionaryMaxExamples     = 5
+)
+
+// htmlTagPattern 例句中的高亮标签
+var htmlTagPattern = regexp.MustCompile(`</?[a-z]+>`)
+
+// Lookup 查询单词，解析谷歌翻译响应中的词典、释义和例句部分
+func (t *GoogleTranslator) Lookup(word, from, to string) (*DictionaryEntry, error) {
+	result, err := t.requestRaw(word, from, to)
+	if err != nil {
+		return nil, err
+	}
+	entry := parseGoogleDictionary(result)
+	if entry.Translation == "" && len(entry.Senses) == 0 {
+		return nil, ErrWordNotFound
+	}
+	entry.Word, entry.From, entry.To = word, from, to
+	return entry, nil
+}
+
+// parseGoogleDictionary 解析响应：
+// [0] 译文片段，末项为读音；[1] 按词性的对应词；[12] 按词性的释义；[13] 例句
+func parseGoogleDictionary(result []interface{}) *DictionaryEntry {
+	entry := &DictionaryEntry{}
+
+	var translation strings.Builder
+	for _, item := range arrayAt(result, 0) {
+		chunk, _ := item.([]interface{})
+		if len(chunk) >= 4 && chunk[0] == nil {
+			entry.Pronunciation = stringAt(chunk, 3)
+			continue
+		}
+		translation.WriteString(stringAt(chunk, 0))
+	}
+	entry.Translation = strings.TrimSpace(translation.String())
+
+	// 词性到 Senses 下标的索引
+	senses := make(map[string]int)
+	sense := func(pos string) *DictionarySense {
+		i, ok := senses[pos]
+		if !ok {
+			i = len(entry.Senses)
+			senses[pos] = i
+			entry.Senses = append(entry.Senses, DictionarySense{PartOfSpeech: pos})
+		}
+		return &entry.Senses[i]
+	}
+
+	for _, item := range arrayAt(result, 1) {
+		group, _ := item.([]interface{})
+		s := sense(stringAt(group, 0))
+		for _, term := range arrayAt(group, 1) {
+			if text, ok := term.(string); ok && len(s.Translations) < dictionaryMaxTranslations {
+				s.Translations = append(s.Translations, text)
+			}
+		}
+	}
+
+	for _, item := range arrayAt(result, 12) {
+		group, _ := item.([]interface{})
+		s := sense(stringAt(group, 0))
+		for _, def := range arrayAt(group, 1) {
+			fields, _ := def.([]interface{})
+			if text := stringAt(fields, 0); text != "" && len(s.Definitions) < dictionaryMaxDefinitions {
+				s.Definitions = append(s.Definitions, DictionaryDefinition{Definition: text, Example: stringAt(fields, 2)})
+			}
+		}
+	}
+
+	for _, item := range arrayAt(arrayAt(result, 13), 0) {
+		fields, _ := item.([]interface{})
+		if text := stringAt(fields, 0); text != "" && len(entry.Examples) < dictionaryMaxExamples {
+			entry.Examples = append(entry.Examples, htmlTagPattern.ReplaceAllString(text, ""))
+		}
+	}
+
+	return entry
+}
+
+// arrayAt 安全读取数组元素
+func arrayAt(arr []interface{}, i int) []interface{} {
+	if i < len(arr) {
+		if v, ok := arr[i].([]interface{}); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// stringAt 安全读取字符串元素
+func stringAt(arr []interface{}, i int) string {
+	if i < len(arr) {
+		if v, ok := arr[i].(string); ok {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,32 @@
+package translator
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseGoogleDictionary(t *testing.T) {
+	raw := `[[["跑","run",null,null,10],[null,null,"Pǎo","rʌn"]],
+		[["verb",["跑","运行","经营"],[["跑",["run"],null,0.5]],"run",2],["noun",["运行"],[],"run",1]],
+		"en",null,null,null,1,[],[["en"],null,[1],["en"]],null,null,null,
+		[["verb",[["move at a speed faster than a walk","m_en_1","he ran across the road"]],"run"]],
+		[[["she <b>run</b>s every morning",null,null,null,null,"m_en_2"]]]]`
+	var result []interface{}
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := parseGoogleDictionary(result)
+	if entry.Translation != "跑" || entry.Pronunciation != "rʌn" {
+		t.Fatalf("entry = %+v", entry)
+	}
+	if len(entry.Senses) != 2 || entry.Senses[0].PartOfSpeech != "verb" || len(entry.Senses[0].Translations) != 3 {
+		t.Fatalf("senses = %+v", entry.Senses)
+	}
+	if defs := entry.Senses[0].Definitions; len(defs) != 1 || defs[0].Example != "he ran across the road" {
+		t.Fatalf("definitions = %+v", defs)
+	}
+	if len(entry.Examples) != 1 || entry.Examples[0] != "she runs every morning" {
+		t.Fatalf("examples = %q", entry.Examples)
+	}
+}
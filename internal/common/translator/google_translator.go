@@ -43,7 +43,7 @@ type GoogleTranslatorTokenFree struct {
 // NewGoogleTranslator 创建一个新的带token的Google翻译器实例
 func NewGoogleTranslator() *GoogleTranslator {
 	return &GoogleTranslator{
-		httpClient: &http.Client{Timeout: defaultTimeout},
+		httpClient: newHTTPClient(GoogleTranslatorType, defaultTimeout),
 		Timeout:    defaultTimeout,
 		languages:  initGoogleLanguages(),
 	}
@@ -164,6 +164,58 @@ func (t *GoogleTranslator) TranslateWithParams(text string, params TranslationPa
 
 // translate 执行实际翻译操作（带token版本）
 func (t *GoogleTranslator) translate(text, from, to string) (string, error) {
+	translations, err := t.request(text, from, to)
+	if err != nil {
+		return "", err
+	}
+
+	var translatedText strings.Builder
+	for _, translation := range translations {
+		if chunk, ok := translation.([]interface{}); ok && len(chunk) > 0 {
+			if text, ok := chunk[0].(string); ok {
+				translatedText.WriteString(text)
+			}
+		}
+	}
+
+	return translatedText.String(), nil
+}
+
+// Romanize 获取原文的读音转写，如汉字的拼音、日文的罗马字
+func (t *GoogleTranslator) Romanize(text, from string) (string, error) {
+	translations, err := t.request(text, from, "en")
+	if err != nil {
+		return "", err
+	}
+
+	// 读音位于最后一项：[null, null, 译文读音, 原文读音]
+	for _, translation := range translations {
+		if chunk, ok := translation.([]interface{}); ok && len(chunk) >= 4 && chunk[0] == nil {
+			if romanized, ok := chunk[3].(string); ok && romanized != "" {
+				return romanized, nil
+			}
+		}
+	}
+	return "", errors.New("no romanization available")
+}
+
+// request 发送翻译请求，返回响应中的翻译片段
+func (t *GoogleTranslator) request(text, from, to string) ([]interface{}, error) {
+	result, err := t.requestRaw(text, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// 提取翻译片段
+	translations, ok := result[0].([]interface{})
+	if !ok {
+		return nil, errors.New("unexpected response format")
+	}
+	return translations, nil
+}
+
+// requestRaw 发送翻译请求，返回完整响应，包含词典、释义和例句
+func (t *GoogleTranslator) requestRaw(text, from, to string) ([]interface{}, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), t.Timeout)
 	defer cancel()
 
@@ -197,52 +249,37 @@ func (t *GoogleTranslator) translate(text, from, to string) (string, error) {
 	// 创建请求
 	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// 发送请求
 	resp, err := t.httpClient.Do(req)
 	if err != nil {
-		return "", ErrBadNetwork
+		return nil, ErrBadNetwork
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API error: status code %d", resp.StatusCode)
+		return nil, fmt.Errorf("API error: status code %d", resp.StatusCode)
 	}
 
 	// 读取响应
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// 解析JSON响应
 	var result []interface{}
 	if err := json.Unmarshal(body, &result); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if len(result) == 0 {
-		return "", errors.New("unexpected response format")
+		return nil, errors.New("unexpected response format")
 	}
 
-	// 提取翻译文本
-	translations, ok := result[0].([]interface{})
-	if !ok {
-		return "", errors.New("unexpected response format")
-	}
-
-	var translatedText strings.Builder
-	for _, translation := range translations {
-		if chunk, ok := translation.([]interface{}); ok && len(chunk) > 0 {
-			if text, ok := chunk[0].(string); ok {
-				translatedText.WriteString(text)
-			}
-		}
-	}
-
-	return translatedText.String(), nil
+	return result, nil
 }
 
 // GetSupportedLanguages 获取翻译器支持的语言列表
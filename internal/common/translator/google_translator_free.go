@@ -15,7 +15,7 @@ import (
 // NewGoogleTranslatorTokenFree 创建一个新的无token的Google翻译器实例
 func NewGoogleTranslatorTokenFree() *GoogleTranslatorTokenFree {
 	return &GoogleTranslatorTokenFree{
-		httpClient: &http.Client{Timeout: defaultTimeout},
+		httpClient: newHTTPClient(GoogleTranslatorType, defaultTimeout),
 		Timeout:    defaultTimeout,
 		languages:  initGoogleLanguages(),
 	}
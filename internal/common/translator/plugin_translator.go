@@ -0,0 +1,309 @@
+// Package translator 提供文本翻译功能
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// 外部翻译插件：通过清单文件声明，以 JSON 请求/响应与外部程序（标准输入输出）或 HTTP 服务通信。
+//
+// 请求：{"action": "translate", "text": "...", "from": "en", "to": "zh"}
+//
+//	{"action": "languages"}
+//
+// 响应：{"text": "..."}、{"languages": {"en": "English"}} 或 {"error": "..."}
+const (
+	pluginDefaultTimeout = 30 * time.Second
+	// pluginMaxResponse 插件响应大小上限
+	pluginMaxResponse = 4 << 20
+)
+
+// 错误定义
+var (
+	ErrPluginManifest = errors.New("invalid translator plugin manifest")
+	ErrPluginResponse = errors.New("translator plugin response error")
+)
+
+// pluginNamePattern 插件名称格式
+var pluginNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// PluginManifest 翻译插件清单，command 和 url 二选一
+type PluginManifest struct {
+	Name        string            `json:"name"`        // 插件名称，作为翻译器类型
+	DisplayName string            `json:"displayName"` // 显示名称
+	Command     []string          `json:"command"`     // 可执行文件及参数，每次翻译启动一次
+	URL         string            `json:"url"`         // HTTP 服务地址，以 POST 发送请求
+	Headers     map[string]string `json:"headers"`     // HTTP 请求头，如认证信息
+	Timeout     string            `json:"timeout"`     // 超时时间，如 "20s"
+	Languages   map[string]string `json:"languages"`   // 支持的语言，为空时向插件查询
+}
+
+// pluginRequest 发送给插件的请求
+type pluginRequest struct {
+	Action string `json:"action"`
+	Text   string `json:"text,omitempty"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
+// pluginResponse 插件返回的响应
+type pluginResponse struct {
+	Text      string            `json:"text"`
+	Languages map[string]string `json:"languages"`
+	Error     string            `json:"error"`
+}
+
+// PluginTranslator 外部翻译插件
+type PluginTranslator struct {
+	manifest   PluginManifest
+	dir        string // 清单所在目录，命令的相对路径基于该目录
+	httpClient *http.Client
+	Timeout    time.Duration
+
+	languagesOnce sync.Once
+	languages     map[string]LanguageInfo
+}
+
+// LoadPluginManifests 读取目录下的全部 *.json 清单，单个清单无效时返回错误列表但不影响其他插件
+func LoadPluginManifests(dir string) ([]*PluginTranslator, []error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var plugins []*PluginTranslator
+	var errs []error
+	for _, path := range paths {
+		plugin, err := LoadPluginManifest(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(path), err))
+			continue
+		}
+		plugins = append(plugins, plugin)
+	}
+	return plugins, errs
+}
+
+// LoadPluginManifest 读取并校验单个插件清单
+func LoadPluginManifest(path string) (*PluginTranslator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var manifest PluginManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPluginManifest, err)
+	}
+	return NewPluginTranslator(manifest, filepath.Dir(path))
+}
+
+// NewPluginTranslator 根据清单创建插件翻译器
+func NewPluginTranslator(manifest PluginManifest, dir string) (*PluginTranslator, error) {
+	if !pluginNamePattern.MatchString(manifest.Name) {
+		return nil, fmt.Errorf("%w: name must match %s", ErrPluginManifest, pluginNamePattern)
+	}
+	if (len(manifest.Command) == 0) == (manifest.URL == "") {
+		return nil, fmt.Errorf("%w: exactly one of command or url is required", ErrPluginManifest)
+	}
+	if manifest.URL != "" && !strings.HasPrefix(manifest.URL, "http://") && !strings.HasPrefix(manifest.URL, "https://") {
+		return nil, fmt.Errorf("%w: url must be http or https", ErrPluginManifest)
+	}
+
+	timeout := pluginDefaultTimeout
+	if manifest.Timeout != "" {
+		parsed, err := time.ParseDuration(manifest.Timeout)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("%w: invalid timeout %q", ErrPluginManifest, manifest.Timeout)
+		}
+		timeout = parsed
+	}
+
+	t := &PluginTranslator{
+		manifest:   manifest,
+		dir:        dir,
+		httpClient: newHTTPClient(TranslatorType(manifest.Name), 0),
+		Timeout:    timeout,
+	}
+	if len(manifest.Languages) > 0 {
+		t.languagesOnce.Do(func() { t.languages = toLanguageInfo(manifest.Languages) })
+	}
+	return t, nil
+}
+
+// Type 插件的翻译器类型
+func (t *PluginTranslator) Type() TranslatorType {
+	return TranslatorType(t.manifest.Name)
+}
+
+// SetTimeout 设置请求超时时间，清单中声明的超时优先
+func (t *PluginTranslator) SetTimeout(timeout time.Duration) {
+	if t.manifest.Timeout == "" && timeout > 0 {
+		t.Timeout = timeout
+	}
+}
+
+// Translate 使用标准语言标签进行文本翻译
+func (t *PluginTranslator) Translate(text string, from language.Tag, to language.Tag) (string, error) {
+	return t.translate(text, from.String(), to.String())
+}
+
+// TranslateWithParams 使用简单字符串参数进行文本翻译
+func (t *PluginTranslator) TranslateWithParams(text string, params TranslationParams) (string, error) {
+	if params.Timeout > 0 {
+		t.SetTimeout(params.Timeout)
+	}
+	return t.translate(text, params.From, params.To)
+}
+
+// GetSupportedLanguages 获取插件支持的语言，清单未声明时向插件查询一次
+func (t *PluginTranslator) GetSupportedLanguages() map[string]LanguageInfo {
+	t.languagesOnce.Do(func() {
+		resp, err := t.call(pluginRequest{Action: "languages"})
+		if err != nil {
+			t.languages = map[string]LanguageInfo{}
+			return
+		}
+		t.languages = toLanguageInfo(resp.Languages)
+	})
+	return t.languages
+}
+
+// IsLanguageSupported 检查指定的语言代码是否受支持，插件未声明语言时不做限制
+func (t *PluginTranslator) IsLanguageSupported(languageCode string) bool {
+	languages := t.GetSupportedLanguages()
+	if len(languages) == 0 || languageCode == "auto" {
+		return true
+	}
+	_, ok := languages[languageCode]
+	return ok
+}
+
+// translate 执行实际翻译操作
+func (t *PluginTranslator) translate(text, from, to string) (string, error) {
+	if text == "" {
+		return "", fmt.Errorf("text cannot be empty")
+	}
+	resp, err := t.call(pluginRequest{Action: "translate", Text: text, From: from, To: to})
+	if err != nil {
+		return "", err
+	}
+	return resp.Text, nil
+}
+
+// call 发送请求并解析响应
+func (t *PluginTranslator) call(request pluginRequest) (*pluginResponse, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.Timeout)
+	defer cancel()
+
+	var body []byte
+	if t.manifest.URL != "" {
+		body, err = t.callHTTP(ctx, payload)
+	} else {
+		body, err = t.callCommand(ctx, payload)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var response pluginResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse response: %v", ErrPluginResponse, err)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("%w: %s", ErrPluginResponse, response.Error)
+	}
+	return &response, nil
+}
+
+// callHTTP 以 POST 发送请求
+func (t *PluginTranslator) callHTTP(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.manifest.URL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for key, value := range t.manifest.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPluginResponse, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, pluginMaxResponse))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: HTTP %d - %s", ErrPluginResponse, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// callCommand 启动插件程序，请求写入标准输入，从标准输出读取响应
+func (t *PluginTranslator) callCommand(ctx context.Context, payload []byte) ([]byte, error) {
+	name := t.manifest.Command[0]
+	if !filepath.IsAbs(name) && strings.ContainsRune(name, filepath.Separator) {
+		name = filepath.Join(t.dir, name)
+	}
+
+	cmd := exec.CommandContext(ctx, name, t.manifest.Command[1:]...)
+	cmd.Dir = t.dir
+	cmd.Stdin = bytes.NewReader(append(payload, '\n'))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &limitedBuffer{buf: &stdout, limit: pluginMaxResponse}
+	cmd.Stderr = &limitedBuffer{buf: &stderr, limit: 4096}
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %v: %s", ErrPluginResponse, err, msg)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrPluginResponse, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// limitedBuffer 超出上限的输出直接丢弃
+type limitedBuffer struct {
+	buf   *bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.buf.Len(); remaining > 0 {
+		b.buf.Write(p[:min(len(p), remaining)])
+	}
+	return len(p), nil
+}
+
+// toLanguageInfo 转换语言映射
+func toLanguageInfo(languages map[string]string) map[string]LanguageInfo {
+	result := make(map[string]LanguageInfo, len(languages))
+	for code, name := range languages {
+		result[code] = LanguageInfo{Code: code, Name: name}
+	}
+	return result
+}
@@ -0,0 +1,69 @@
+package translator
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPluginTranslatorHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req pluginRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("X-Token") != "secret" {
+			json.NewEncoder(w).Encode(pluginResponse{Error: "unauthorized"})
+			return
+		}
+		switch req.Action {
+		case "languages":
+			json.NewEncoder(w).Encode(pluginResponse{Languages: map[string]string{"en": "English", "xx": "Internal"}})
+		case "translate":
+			json.NewEncoder(w).Encode(pluginResponse{Text: req.To + ":" + req.Text})
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	manifest := `{"name": "internal", "url": "` + server.URL + `", "headers": {"X-Token": "secret"}, "timeout": "5s"}`
+	if err := os.WriteFile(filepath.Join(dir, "internal.json"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"name": "Bad Name"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plugins, errs := LoadPluginManifests(dir)
+	if len(plugins) != 1 || len(errs) != 1 || !errors.Is(errs[0], ErrPluginManifest) {
+		t.Fatalf("plugins = %d, errs = %v", len(plugins), errs)
+	}
+	plugin := plugins[0]
+
+	got, err := plugin.TranslateWithParams("hello", TranslationParams{From: "en", To: "xx"})
+	if err != nil || got != "xx:hello" {
+		t.Fatalf("translate = %q, %v", got, err)
+	}
+	if !plugin.IsLanguageSupported("xx") || plugin.IsLanguageSupported("fr") {
+		t.Fatal("languages not loaded from plugin")
+	}
+
+	factory := NewTranslatorFactory()
+	if err := factory.Register(BingTranslatorType, func() Translator { return plugin }); err == nil {
+		t.Fatal("registering a built-in name should fail")
+	}
+	if err := factory.Register(plugin.Type(), func() Translator { return plugin }); err != nil {
+		t.Fatal(err)
+	}
+	if created, err := factory.Create("internal"); err != nil || created != plugin {
+		t.Fatalf("create = %v, %v", created, err)
+	}
+	if types := factory.Types(); types[len(types)-1] != "internal" {
+		t.Fatalf("types = %v", types)
+	}
+}
@@ -0,0 +1,64 @@
+package translator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrPlaceholderLost 译文中丢失了受保护片段的占位符
+var ErrPlaceholderLost = errors.New("protected placeholder lost in translation")
+
+// protectedPattern 翻译时需要原样保留的片段：行内代码、URL、模板占位符和格式化占位符
+var protectedPattern = regexp.MustCompile("`[^`\n]+`" +
+	`|https?://[^\s<>()\[\]"']+` +
+	`|\{\{[^{}\n]*\}\}|\$\{[^{}\n]*\}|\{[0-9]+\}|%[-+ #0]*[0-9]*(?:\.[0-9]+)?[sdvfqxXt]`)
+
+// tokenPattern 占位符，翻译服务可能在括号内插入空格
+var tokenPattern = regexp.MustCompile(`⟦\s*([0-9]+)\s*⟧`)
+
+// ProtectedText 已替换为占位符的文本
+type ProtectedText struct {
+	Text     string   // 替换后的文本，交给翻译器
+	segments []string // 被替换的原始片段
+}
+
+// Protect 将不应翻译的片段替换为占位符
+func Protect(text string) ProtectedText {
+	var segments []string
+	masked := protectedPattern.ReplaceAllStringFunc(text, func(match string) string {
+		segments = append(segments, match)
+		return "⟦" + strconv.Itoa(len(segments)-1) + "⟧"
+	})
+	return ProtectedText{Text: masked, segments: segments}
+}
+
+// Restore 将译文中的占位符还原为原始片段，任一占位符丢失或重复时返回错误
+func (p ProtectedText) Restore(translated string) (string, error) {
+	if len(p.segments) == 0 {
+		return translated, nil
+	}
+
+	seen := make([]bool, len(p.segments))
+	var restoreErr error
+	result := tokenPattern.ReplaceAllStringFunc(translated, func(token string) string {
+		index, _ := strconv.Atoi(tokenPattern.FindStringSubmatch(token)[1])
+		if index >= len(p.segments) || seen[index] {
+			restoreErr = fmt.Errorf("%w: unexpected %s", ErrPlaceholderLost, token)
+			return token
+		}
+		seen[index] = true
+		return p.segments[index]
+	})
+	if restoreErr != nil {
+		return "", restoreErr
+	}
+	for index, ok := range seen {
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrPlaceholderLost, strings.TrimSpace(p.segments[index]))
+		}
+	}
+	return result, nil
+}
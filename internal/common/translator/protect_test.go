@@ -0,0 +1,24 @@
+package translator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProtectRestore(t *testing.T) {
+	text := "Run `go test` then open https://example.com/a?b=1 for {{name}} and %d items"
+	protected := Protect(text)
+	want := "Run ⟦0⟧ then open ⟦1⟧ for ⟦2⟧ and ⟦3⟧ items"
+	if protected.Text != want {
+		t.Fatalf("Protect = %q", protected.Text)
+	}
+
+	got, err := protected.Restore("运行 ⟦ 0 ⟧，然后打开 ⟦1⟧ 查看 ⟦2⟧ 的 ⟦3⟧ 项")
+	if err != nil || got != "运行 `go test`，然后打开 https://example.com/a?b=1 查看 {{name}} 的 %d 项" {
+		t.Fatalf("Restore = %q, %v", got, err)
+	}
+
+	if _, err := protected.Restore("运行 ⟦0⟧ ⟦1⟧ ⟦2⟧"); !errors.Is(err, ErrPlaceholderLost) {
+		t.Fatalf("missing placeholder: %v", err)
+	}
+}
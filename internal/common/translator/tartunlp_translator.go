@@ -52,11 +52,9 @@ type TartuNLPResponse struct {
 // NewTartuNLPTranslator 创建一个新的TartuNLP翻译器实例
 func NewTartuNLPTranslator() *TartuNLPTranslator {
 	translator := &TartuNLPTranslator{
-		httpClient: &http.Client{
-			Timeout: tartuNLPDefaultTimeout,
-		},
-		Timeout:   tartuNLPDefaultTimeout,
-		languages: initTartuNLPLanguages(),
+		httpClient: newHTTPClient(TartuNLPTranslatorType, tartuNLPDefaultTimeout),
+		Timeout:    tartuNLPDefaultTimeout,
+		languages:  initTartuNLPLanguages(),
 	}
 
 	return translator
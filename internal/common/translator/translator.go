@@ -3,7 +3,11 @@ package translator
 
 import (
 	"fmt"
+	"net/http"
+	"sort"
+	"sync"
 	"time"
+	"voidraft/internal/common/netclient"
 
 	"golang.org/x/text/language"
 )
@@ -18,8 +22,19 @@ type TranslationParams struct {
 // 常量定义
 const (
 	defaultTimeout = 30 * time.Second
+	// defaultRetries 网络错误或服务暂时不可用时的重试次数
+	defaultRetries = 2
 )
 
+// newHTTPClient 翻译器使用的客户端，统计按翻译器区分
+func newHTTPClient(translatorType TranslatorType, timeout time.Duration) *http.Client {
+	return netclient.New(netclient.Config{
+		Service: "translator." + string(translatorType),
+		Timeout: timeout,
+		Retries: defaultRetries,
+	})
+}
+
 // TranslatorType 翻译器类型
 type TranslatorType string
 
@@ -60,12 +75,65 @@ type Translator interface {
 	IsLanguageSupported(languageCode string) bool
 }
 
+// Romanizer 可提供读音转写的翻译器，如汉字拼音、日文罗马字
+type Romanizer interface {
+	// Romanize 获取原文的读音转写
+	Romanize(text string, from string) (string, error)
+}
+
+// builtinTranslatorTypes 内置翻译器类型，插件不能占用
+var builtinTranslatorTypes = []TranslatorType{
+	BingTranslatorType,
+	GoogleTranslatorType,
+	YoudaoTranslatorType,
+	DeeplTranslatorType,
+	TartuNLPTranslatorType,
+}
+
 // TranslatorFactory 翻译器工厂，用于创建不同类型的翻译器
-type TranslatorFactory struct{}
+type TranslatorFactory struct {
+	mu      sync.RWMutex
+	plugins map[TranslatorType]func() Translator // 已注册的外部翻译器
+}
 
 // NewTranslatorFactory 创建一个新的翻译器工厂
 func NewTranslatorFactory() *TranslatorFactory {
-	return &TranslatorFactory{}
+	return &TranslatorFactory{plugins: make(map[TranslatorType]func() Translator)}
+}
+
+// Register 注册外部翻译器，名称不能与内置翻译器重复，重复注册时覆盖
+func (f *TranslatorFactory) Register(translatorType TranslatorType, create func() Translator) error {
+	for _, builtin := range builtinTranslatorTypes {
+		if translatorType == builtin {
+			return fmt.Errorf("translator type %s is reserved", translatorType)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.plugins[translatorType] = create
+	return nil
+}
+
+// ClearPlugins 移除全部已注册的外部翻译器
+func (f *TranslatorFactory) ClearPlugins() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.plugins = make(map[TranslatorType]func() Translator)
+}
+
+// Types 返回全部可用的翻译器类型，内置在前，外部翻译器按名称排序
+func (f *TranslatorFactory) Types() []TranslatorType {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	types := append([]TranslatorType(nil), builtinTranslatorTypes...)
+	plugins := make([]TranslatorType, 0, len(f.plugins))
+	for translatorType := range f.plugins {
+		plugins = append(plugins, translatorType)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i] < plugins[j] })
+	return append(types, plugins...)
 }
 
 // Create 根据类型创建翻译器
@@ -81,7 +149,13 @@ func (f *TranslatorFactory) Create(translatorType TranslatorType) (Translator, e
 		return NewDeeplTranslator(), nil
 	case TartuNLPTranslatorType:
 		return NewTartuNLPTranslator(), nil
-	default:
+	}
+
+	f.mu.RLock()
+	create, ok := f.plugins[translatorType]
+	f.mu.RUnlock()
+	if !ok {
 		return nil, fmt.Errorf("unsupported translator type: %s", translatorType)
 	}
+	return create(), nil
 }
@@ -38,7 +38,7 @@ var (
 func NewYoudaoTranslator() *YoudaoTranslator {
 	translator := &YoudaoTranslator{
 		Timeout:    youdaoDefaultTimeout,
-		httpClient: &http.Client{Timeout: youdaoDefaultTimeout},
+		httpClient: newHTTPClient(YoudaoTranslatorType, youdaoDefaultTimeout),
 		languages:  initYoudaoLanguages(),
 	}
 
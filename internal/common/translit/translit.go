@@ -0,0 +1,216 @@
+// Package translit 提供不依赖网络的文字转写：日文假名转罗马字、西里尔字母转拉丁字母
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Scheme 转写方案
+type Scheme string
+
+const (
+	SchemePinyin        Scheme = "pinyin"         // 汉字转拼音，需要翻译服务提供读音
+	SchemeRomaji        Scheme = "romaji"         // 日文转罗马字（平文式），汉字需要翻译服务提供读音
+	SchemeCyrillicLatin Scheme = "cyrillic-latin" // 西里尔字母转拉丁字母
+)
+
+// Schemes 全部支持的转写方案
+var Schemes = []Scheme{SchemePinyin, SchemeRomaji, SchemeCyrillicLatin}
+
+// IsValid 转写方案是否有效
+func (s Scheme) IsValid() bool {
+	for _, scheme := range Schemes {
+		if s == scheme {
+			return true
+		}
+	}
+	return false
+}
+
+// cyrillicTable 西里尔字母转写表，覆盖俄语、乌克兰语和白俄罗斯语字母
+var cyrillicTable = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g", 'ў': "u",
+}
+
+// CyrillicToLatin 将西里尔字母转写为拉丁字母，其他字符保持不变
+func CyrillicToLatin(text string) string {
+	runes := []rune(text)
+	var sb strings.Builder
+	sb.Grow(len(text))
+	for i, r := range runes {
+		latin, ok := cyrillicTable[unicode.ToLower(r)]
+		if !ok {
+			sb.WriteRune(r)
+			continue
+		}
+		if unicode.IsUpper(r) && latin != "" {
+			// 全大写单词保持全大写，否则只大写首字母
+			if allUpperAround(runes, i) {
+				latin = strings.ToUpper(latin)
+			} else {
+				latin = strings.ToUpper(latin[:1]) + latin[1:]
+			}
+		}
+		sb.WriteString(latin)
+	}
+	return sb.String()
+}
+
+// allUpperAround 判断相邻字母是否也是大写
+func allUpperAround(runes []rune, i int) bool {
+	if i+1 < len(runes) && unicode.IsLetter(runes[i+1]) {
+		return unicode.IsUpper(runes[i+1])
+	}
+	if i > 0 && unicode.IsLetter(runes[i-1]) {
+		return unicode.IsUpper(runes[i-1])
+	}
+	return false
+}
+
+// kanaTable 平假名转写表（平文式），片假名先转换为平假名再查表
+var kanaTable = map[string]string{
+	"あ": "a", "い": "i", "う": "u", "え": "e", "お": "o",
+	"か": "ka", "き": "ki", "く": "ku", "け": "ke", "こ": "ko",
+	"さ": "sa", "し": "shi", "す": "su", "せ": "se", "そ": "so",
+	"た": "ta", "ち": "chi", "つ": "tsu", "て": "te", "と": "to",
+	"な": "na", "に": "ni", "ぬ": "nu", "ね": "ne", "の": "no",
+	"は": "ha", "ひ": "hi", "ふ": "fu", "へ": "he", "ほ": "ho",
+	"ま": "ma", "み": "mi", "む": "mu", "め": "me", "も": "mo",
+	"や": "ya", "ゆ": "yu", "よ": "yo",
+	"ら": "ra", "り": "ri", "る": "ru", "れ": "re", "ろ": "ro",
+	"わ": "wa", "ゐ": "i", "ゑ": "e", "を": "o", "ん": "n",
+	"が": "ga", "ぎ": "gi", "ぐ": "gu", "げ": "ge", "ご": "go",
+	"ざ": "za", "じ": "ji", "ず": "zu", "ぜ": "ze", "ぞ": "zo",
+	"だ": "da", "ぢ": "ji", "づ": "zu", "で": "de", "ど": "do",
+	"ば": "ba", "び": "bi", "ぶ": "bu", "べ": "be", "ぼ": "bo",
+	"ぱ": "pa", "ぴ": "pi", "ぷ": "pu", "ぺ": "pe", "ぽ": "po",
+	"ぁ": "a", "ぃ": "i", "ぅ": "u", "ぇ": "e", "ぉ": "o",
+	"ゃ": "ya", "ゅ": "yu", "ょ": "yo", "ゎ": "wa", "ゔ": "vu",
+	"きゃ": "kya", "きゅ": "kyu", "きょ": "kyo",
+	"しゃ": "sha", "しゅ": "shu", "しょ": "sho", "しぇ": "she",
+	"ちゃ": "cha", "ちゅ": "chu", "ちょ": "cho", "ちぇ": "che",
+	"にゃ": "nya", "にゅ": "nyu", "にょ": "nyo",
+	"ひゃ": "hya", "ひゅ": "hyu", "ひょ": "hyo",
+	"みゃ": "mya", "みゅ": "myu", "みょ": "myo",
+	"りゃ": "rya", "りゅ": "ryu", "りょ": "ryo",
+	"ぎゃ": "gya", "ぎゅ": "gyu", "ぎょ": "gyo",
+	"じゃ": "ja", "じゅ": "ju", "じょ": "jo", "じぇ": "je",
+	"びゃ": "bya", "びゅ": "byu", "びょ": "byo",
+	"ぴゃ": "pya", "ぴゅ": "pyu", "ぴょ": "pyo",
+	"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo",
+	"てぃ": "ti", "でぃ": "di", "とぅ": "tu", "どぅ": "du",
+	"うぃ": "wi", "うぇ": "we", "うぉ": "wo",
+	"ゔぁ": "va", "ゔぃ": "vi", "ゔぇ": "ve", "ゔぉ": "vo",
+}
+
+// kanaPunctuation 日文标点转写
+var kanaPunctuation = map[rune]string{
+	'。': ".", '、': ",", '「': "\"", '」': "\"", '・': " ", '　': " ",
+	'？': "?", '！': "!", '（': "(", '）': ")", '：': ":",
+}
+
+// KanaToRomaji 将平假名和片假名转写为平文式罗马字，汉字等其他字符保持不变
+func KanaToRomaji(text string) string {
+	runes := []rune(text)
+	for i, r := range runes {
+		runes[i] = katakanaToHiragana(r)
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(text))
+	geminate := false // 促音，下一个辅音重复
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == 'っ':
+			geminate = true
+			continue
+		case r == 'ー':
+			// 长音重复前一个元音
+			if vowel := lastVowel(sb.String()); vowel != 0 {
+				sb.WriteByte(vowel)
+			}
+			continue
+		}
+
+		romaji, width := lookupKana(runes[i:])
+		if width == 0 {
+			if geminate {
+				sb.WriteString("tsu")
+				geminate = false
+			}
+			if punct, ok := kanaPunctuation[r]; ok {
+				sb.WriteString(punct)
+			} else {
+				sb.WriteRune(r)
+			}
+			continue
+		}
+		i += width - 1
+
+		if geminate {
+			if strings.HasPrefix(romaji, "ch") {
+				sb.WriteByte('t')
+			} else if !strings.ContainsRune("aiueon", rune(romaji[0])) {
+				sb.WriteByte(romaji[0])
+			}
+			geminate = false
+		}
+		// 撥音后接元音或 y 时加撇号区分，如 kan'i
+		if romaji == "n" && i+1 < len(runes) {
+			if next, w := lookupKana(runes[i+1:]); w > 0 && strings.ContainsRune("aiueoy", rune(next[0])) {
+				romaji = "n'"
+			}
+		}
+		sb.WriteString(romaji)
+	}
+	if geminate {
+		sb.WriteString("tsu")
+	}
+	return sb.String()
+}
+
+// ContainsKanji 文本中是否包含汉字
+func ContainsKanji(text string) bool {
+	return strings.IndexFunc(text, func(r rune) bool { return unicode.Is(unicode.Han, r) }) >= 0
+}
+
+// lookupKana 优先匹配拗音等两字组合
+func lookupKana(runes []rune) (string, int) {
+	if len(runes) >= 2 {
+		if romaji, ok := kanaTable[string(runes[:2])]; ok {
+			return romaji, 2
+		}
+	}
+	if len(runes) >= 1 {
+		if romaji, ok := kanaTable[string(runes[:1])]; ok {
+			return romaji, 1
+		}
+	}
+	return "", 0
+}
+
+// katakanaToHiragana 片假名转为对应的平假名
+func katakanaToHiragana(r rune) rune {
+	if r >= 'ァ' && r <= 'ヴ' {
+		return r - 0x60
+	}
+	return r
+}
+
+// lastVowel 已转写文本的最后一个元音
+func lastVowel(s string) byte {
+	if s == "" {
+		return 0
+	}
+	if c := s[len(s)-1]; strings.IndexByte("aiueo", c) >= 0 {
+		return c
+	}
+	return 0
+}
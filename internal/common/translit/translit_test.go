@@ -0,0 +1,37 @@
+package translit
+
+import "testing"
+
+func TestCyrillicToLatin(t *testing.T) {
+	tests := map[string]string{
+		"Привет, мир":  "Privet, mir",
+		"Щука и ёжик":  "Shchuka i yozhik",
+		"СССР":         "SSSR",
+		"Жук":          "Zhuk",
+		"ЖУК":          "ZHUK",
+		"Київ і Львів": "Kiyiv i Lviv",
+	}
+	for in, want := range tests {
+		if got := CyrillicToLatin(in); got != want {
+			t.Errorf("CyrillicToLatin(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestKanaToRomaji(t *testing.T) {
+	tests := map[string]string{
+		"こんにちは":  "konnichiha",
+		"きょうと":   "kyouto",
+		"がっこう":   "gakkou",
+		"まっちゃ":   "matcha",
+		"コーヒー":   "koohii",
+		"かんい":    "kan'i",
+		"ありがとう。": "arigatou.",
+		"東京タワー":  "東京tawaa",
+	}
+	for in, want := range tests {
+		if got := KanaToRomaji(in); got != want {
+			t.Errorf("KanaToRomaji(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
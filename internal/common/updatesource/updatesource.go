@@ -0,0 +1,191 @@
+// Package updatesource 提供匿名的发布源：不读取任何令牌环境变量、不携带 Cookie，
+// 只以固定的 User-Agent 访问 GitHub / Gitea 的公开发布接口
+package updatesource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"voidraft/internal/common/netclient"
+
+	"github.com/creativeprojects/go-selfupdate"
+)
+
+// UserAgent 所有请求使用的固定标识，不包含版本、系统等可区分用户的信息
+const UserAgent = "voidraft-updater"
+
+// DefaultGitHubAPI GitHub 公共 API 地址
+const DefaultGitHubAPI = "https://api.github.com"
+
+// Source 匿名发布源，实现 selfupdate.Source
+type Source struct {
+	listURL string
+	client  *http.Client
+}
+
+// GitHub 创建 GitHub 发布源，apiBase 为空时使用公共 API
+func GitHub(apiBase string) *Source {
+	if apiBase == "" {
+		apiBase = DefaultGitHubAPI
+	}
+	return &Source{listURL: strings.TrimRight(apiBase, "/") + "/repos/%s/%s/releases", client: newClient()}
+}
+
+// Gitea 创建 Gitea 发布源
+func Gitea(baseURL string) (*Source, error) {
+	if baseURL == "" {
+		return nil, errors.New("gitea base url must be set")
+	}
+	return &Source{listURL: strings.TrimRight(baseURL, "/") + "/api/v1/repos/%s/%s/releases", client: newClient()}, nil
+}
+
+// newClient 创建不带 Cookie 的客户端，重定向时不转发任何认证头；使用共用的代理和证书设置
+func newClient() *http.Client {
+	return &http.Client{
+		Transport: netclient.NewTransport(netclient.Config{Service: "updates", UserAgent: UserAgent, Retries: 2}),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			req.Header.Del("Authorization")
+			req.Header.Del("Cookie")
+			return nil
+		},
+	}
+}
+
+// ListReleases 列出仓库的发布版本
+func (s *Source) ListReleases(ctx context.Context, repository selfupdate.Repository) ([]selfupdate.SourceRelease, error) {
+	owner, repo, err := repository.GetSlug()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.get(ctx, fmt.Sprintf(s.listURL, owner, repo), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("list releases: %s", resp.Status)
+	}
+
+	var rels []*release
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 16<<20)).Decode(&rels); err != nil {
+		return nil, fmt.Errorf("decode releases: %w", err)
+	}
+	out := make([]selfupdate.SourceRelease, len(rels))
+	for i, rel := range rels {
+		out[i] = rel
+	}
+	return out, nil
+}
+
+// DownloadReleaseAsset 通过公开下载地址获取资源，调用方负责关闭
+func (s *Source) DownloadReleaseAsset(ctx context.Context, rel *selfupdate.Release, assetID int64) (io.ReadCloser, error) {
+	if rel == nil {
+		return nil, selfupdate.ErrInvalidRelease
+	}
+	url := assetURL(rel, assetID)
+	if url == "" {
+		return nil, fmt.Errorf("asset %d not found in release", assetID)
+	}
+
+	resp, err := s.get(ctx, url, "application/octet-stream")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("download asset %d: %s", assetID, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// assetURL 在发布信息中查找资源的下载地址
+func assetURL(rel *selfupdate.Release, assetID int64) string {
+	switch assetID {
+	case rel.AssetID:
+		return rel.AssetURL
+	case rel.ValidationAssetID:
+		return rel.ValidationAssetURL
+	}
+	for _, v := range rel.ValidationChain {
+		if v.ValidationAssetID == assetID {
+			return v.ValidationAssetURL
+		}
+	}
+	return ""
+}
+
+// get 发起只带固定头的 GET 请求
+func (s *Source) get(ctx context.Context, url, accept string) (*http.Response, error) {
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return nil, fmt.Errorf("unsupported url: %s", url)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", accept)
+	return s.client.Do(req)
+}
+
+// release GitHub 与 Gitea 共用的发布字段
+type release struct {
+	ID          int64     `json:"id"`
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+	HTMLURL     string    `json:"html_url"`
+	Assets      []*asset  `json:"assets"`      // GitHub
+	Attachments []*asset  `json:"attachments"` // Gitea
+}
+
+func (r *release) GetID() int64              { return r.ID }
+func (r *release) GetTagName() string        { return r.TagName }
+func (r *release) GetDraft() bool            { return r.Draft }
+func (r *release) GetPrerelease() bool       { return r.Prerelease }
+func (r *release) GetPublishedAt() time.Time { return r.PublishedAt }
+func (r *release) GetReleaseNotes() string   { return r.Body }
+func (r *release) GetName() string           { return r.Name }
+func (r *release) GetURL() string            { return r.HTMLURL }
+
+func (r *release) GetAssets() []selfupdate.SourceAsset {
+	list := r.Assets
+	if len(list) == 0 {
+		list = r.Attachments
+	}
+	out := make([]selfupdate.SourceAsset, len(list))
+	for i, a := range list {
+		out[i] = a
+	}
+	return out
+}
+
+// asset 发布附件
+type asset struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Size        int    `json:"size"`
+	DownloadURL string `json:"browser_download_url"`
+}
+
+func (a *asset) GetID() int64                  { return a.ID }
+func (a *asset) GetName() string               { return a.Name }
+func (a *asset) GetSize() int                  { return a.Size }
+func (a *asset) GetBrowserDownloadURL() string { return a.DownloadURL }
+
+var _ selfupdate.Source = (*Source)(nil)
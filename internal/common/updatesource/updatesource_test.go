@@ -0,0 +1,91 @@
+package updatesource
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/creativeprojects/go-selfupdate"
+)
+
+func newServer(t *testing.T, releases string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" || r.Header.Get("Cookie") != "" {
+			t.Errorf("request carried credentials: %v", r.Header)
+		}
+		if ua := r.Header.Get("User-Agent"); ua != UserAgent {
+			t.Errorf("User-Agent = %q", ua)
+		}
+		switch r.URL.Path {
+		case "/repos/o/r/releases", "/api/v1/repos/o/r/releases":
+			io.WriteString(w, releases)
+		case "/download/app.tar.gz":
+			io.WriteString(w, "binary")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGitHubListReleases(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "secret")
+	srv := newServer(t, `[{"id":1,"tag_name":"v1.2.0","name":"1.2","body":"notes","assets":[{"id":7,"name":"app.tar.gz","size":6,"browser_download_url":"x"}]}]`)
+
+	rels, err := GitHub(srv.URL).ListReleases(context.Background(), selfupdate.NewRepositorySlug("o", "r"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rels) != 1 || rels[0].GetTagName() != "v1.2.0" || rels[0].GetReleaseNotes() != "notes" {
+		t.Fatalf("releases = %+v", rels)
+	}
+	assets := rels[0].GetAssets()
+	if len(assets) != 1 || assets[0].GetID() != 7 || assets[0].GetSize() != 6 {
+		t.Fatalf("assets = %+v", assets)
+	}
+}
+
+func TestGiteaListReleases(t *testing.T) {
+	t.Setenv("GITEA_TOKEN", "secret")
+	srv := newServer(t, `[{"id":2,"tag_name":"v2.0.0","attachments":[{"id":9,"name":"app.zip","browser_download_url":"y"}]}]`)
+
+	src, err := Gitea(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rels, err := src.ListReleases(context.Background(), selfupdate.NewRepositorySlug("o", "r"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rels) != 1 || len(rels[0].GetAssets()) != 1 || rels[0].GetAssets()[0].GetBrowserDownloadURL() != "y" {
+		t.Fatalf("releases = %+v", rels)
+	}
+
+	missing, err := src.ListReleases(context.Background(), selfupdate.NewRepositorySlug("o", "missing"))
+	if err != nil || missing != nil {
+		t.Fatalf("missing repo = %v, %v", missing, err)
+	}
+}
+
+func TestDownloadReleaseAsset(t *testing.T) {
+	srv := newServer(t, `[]`)
+	rel := &selfupdate.Release{AssetID: 7, AssetURL: srv.URL + "/download/app.tar.gz"}
+
+	rc, err := GitHub(srv.URL).DownloadReleaseAsset(context.Background(), rel, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	data, _ := io.ReadAll(rc)
+	if string(data) != "binary" {
+		t.Fatalf("data = %q", data)
+	}
+
+	if _, err := GitHub(srv.URL).DownloadReleaseAsset(context.Background(), rel, 8); err == nil {
+		t.Fatal("expected error for unknown asset")
+	}
+}
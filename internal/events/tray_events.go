@@ -21,7 +21,7 @@ func RegisterTrayEvents(systray *application.SystemTray, mainWindow *application
 		trayService.AutoShowHide()
 	})
 
-	// 处理窗口关闭事件 - 根据配置决定是隐藏到托盘还是直接退出
+	// 处理窗口关闭事件 - 按配置的关闭按钮行为处理
 	mainWindow.RegisterHook(wailsevents.Common.WindowClosing, func(event *application.WindowEvent) {
 		// 取消默认关闭行为
 		event.Cancel()
@@ -32,13 +32,38 @@ func RegisterTrayEvents(systray *application.SystemTray, mainWindow *application
 }
 
 // RegisterTrayMenuEvents 注册系统托盘菜单事件
-func RegisterTrayMenuEvents(app *application.App, menu *application.Menu, mainWindow *application.WebviewWindow) {
+func RegisterTrayMenuEvents(app *application.App, menu *application.Menu, mainWindow *application.WebviewWindow, trayService *services.TrayService, appLockService *services.AppLockService, quickTranslateService *services.QuickTranslateService, totpService *services.TOTPService, documentService *services.DocumentService, windowService *services.WindowService) {
 	// 为主窗口菜单项添加点击事件处理函数
 	// 参数 data: 应用程序上下文信息
 	menu.Add("Main window").OnClick(func(data *application.Context) {
 		mainWindow.Show()
 	})
 
+	// 最近打开或编辑的文档子菜单，点击在文档窗口中打开，失败时显示主窗口
+	documentService.AttachRecentMenu(menu.AddSubmenu("Recent documents"), func(id int64) {
+		if err := windowService.OpenDocumentWindow(id); err != nil {
+			mainWindow.Show()
+		}
+	})
+
+	// 立即锁定应用，未设置口令时显示主窗口以便前往设置
+	menu.Add("Lock").OnClick(func(data *application.Context) {
+		if err := appLockService.LockApp(); err != nil {
+			mainWindow.Show()
+		}
+	})
+
+	// 固定片段子菜单，点击复制到剪贴板
+	trayService.AttachSnippetMenu(menu.AddSubmenu("Snippets"))
+
+	// 一次性密码子菜单，点击复制当前密码
+	totpService.AttachTrayMenu(menu.AddSubmenu("TOTP codes"))
+
+	// 翻译剪贴板文本，结果以通知显示
+	menu.Add("Translate clipboard").OnClick(func(data *application.Context) {
+		go quickTranslateService.TranslateClipboard()
+	})
+
 	// 添加菜单分隔符
 	menu.AddSeparator()
 
@@ -0,0 +1,44 @@
+package models
+
+// AccessibilityOverride 辅助功能偏好的覆盖方式
+type AccessibilityOverride string
+
+const (
+	// AccessibilityAuto 跟随系统设置
+	AccessibilityAuto AccessibilityOverride = "auto"
+	// AccessibilityOn 始终开启
+	AccessibilityOn AccessibilityOverride = "on"
+	// AccessibilityOff 始终关闭
+	AccessibilityOff AccessibilityOverride = "off"
+)
+
+// Resolve 根据覆盖方式和系统检测结果得出最终取值
+func (o AccessibilityOverride) Resolve(detected bool) bool {
+	switch o {
+	case AccessibilityOn:
+		return true
+	case AccessibilityOff:
+		return false
+	}
+	return detected
+}
+
+// AccessibilityConfig 辅助功能设置，每一项可跟随系统或强制开关
+type AccessibilityConfig struct {
+	ReducedMotion AccessibilityOverride `json:"reducedMotion"` // 减少动态效果
+	HighContrast  AccessibilityOverride `json:"highContrast"`  // 高对比度
+	ScreenReader  AccessibilityOverride `json:"screenReader"`  // 屏幕阅读器
+}
+
+// AccessibilityPreferences 辅助功能偏好
+type AccessibilityPreferences struct {
+	ReducedMotion bool `json:"reducedMotion"`
+	HighContrast  bool `json:"highContrast"`
+	ScreenReader  bool `json:"screenReader"`
+}
+
+// AccessibilityState 系统检测结果和应用配置覆盖后的最终偏好
+type AccessibilityState struct {
+	System    AccessibilityPreferences `json:"system"`    // 系统检测结果，无法检测的项为 false
+	Effective AccessibilityPreferences `json:"effective"` // 应用配置覆盖后的结果，前端和后端按此调整
+}
@@ -0,0 +1,54 @@
+package models
+
+// ApiTokenScope 本地API令牌权限范围
+type ApiTokenScope string
+
+const (
+	ApiScopeReadOnly   ApiTokenScope = "read-only"   // 只读：列出和读取文档
+	ApiScopeAppendOnly ApiTokenScope = "append-only" // 仅追加：向已有文档追加块，不能读取
+	ApiScopeFull       ApiTokenScope = "full"        // 完全访问
+)
+
+// IsValid 权限范围是否有效
+func (s ApiTokenScope) IsValid() bool {
+	switch s {
+	case ApiScopeReadOnly, ApiScopeAppendOnly, ApiScopeFull:
+		return true
+	}
+	return false
+}
+
+// Allows 是否允许执行需要 required 权限的操作
+func (s ApiTokenScope) Allows(required ApiTokenScope) bool {
+	return s == ApiScopeFull || s == required
+}
+
+// ApiToken 本地API访问令牌，仅保存哈希
+type ApiToken struct {
+	ID         int64         `json:"id" db:"id"`
+	Name       string        `json:"name" db:"name"`
+	Prefix     string        `json:"prefix" db:"prefix"` // 令牌前缀，便于识别
+	TokenHash  string        `json:"-" db:"token_hash"`
+	Scope      ApiTokenScope `json:"scope" db:"scope"`
+	RateLimit  int           `json:"rateLimit" db:"rate_limit"` // 每分钟请求数上限，0表示不限制
+	CreatedAt  string        `json:"createdAt" db:"created_at"`
+	LastUsedAt string        `json:"lastUsedAt" db:"last_used_at"`
+}
+
+// ApiTokenCreated 新建令牌的结果，明文令牌只返回这一次
+type ApiTokenCreated struct {
+	Token string    `json:"token"`
+	Info  *ApiToken `json:"info"`
+}
+
+// ApiAuditEntry 本地API调用审计记录
+type ApiAuditEntry struct {
+	ID         int64  `json:"id" db:"id"`
+	TokenID    int64  `json:"tokenId" db:"token_id"` // 0表示未通过认证
+	TokenName  string `json:"tokenName" db:"token_name"`
+	Method     string `json:"method" db:"method"`
+	Path       string `json:"path" db:"path"`
+	Status     int    `json:"status" db:"status"`
+	RemoteAddr string `json:"remoteAddr" db:"remote_addr"`
+	CreatedAt  string `json:"createdAt" db:"created_at"`
+}
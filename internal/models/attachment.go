@@ -0,0 +1,13 @@
+package models
+
+// Attachment 文档附件，文件按内容哈希保存在数据目录的 attachments 目录下
+type Attachment struct {
+	ID         int64  `json:"id" db:"id"`
+	DocumentID int64  `json:"documentId" db:"document_id"`
+	Name       string `json:"name" db:"name"`          // 原始文件名
+	MimeType   string `json:"mimeType" db:"mime_type"` // 内容类型
+	Size       int64  `json:"size" db:"size"`          // 文件大小（字节）
+	Hash       string `json:"hash" db:"hash"`          // 内容的 SHA-256，相同内容只保存一份
+	CreatedAt  string `json:"createdAt" db:"created_at"`
+	URL        string `json:"url"` // 资源地址，可写入文档内容，加载时需追加 token 参数，见 AttachmentService.GetAttachmentToken
+}
@@ -0,0 +1,8 @@
+package models
+
+// Backlink 链接到某个文档的其他文档
+type Backlink struct {
+	DocumentID int64  `json:"documentId"`
+	Title      string `json:"title"`
+	UpdatedAt  string `json:"updatedAt"`
+}
@@ -0,0 +1,26 @@
+package models
+
+// CaptureTarget 后台粘贴的目标文档
+type CaptureTarget string
+
+const (
+	// CaptureTargetRecent 最近获得焦点的文档，没有记录时使用收集箱文档
+	CaptureTargetRecent CaptureTarget = "recent"
+	// CaptureTargetInbox 始终使用指定的收集箱文档
+	CaptureTargetInbox CaptureTarget = "inbox"
+)
+
+// CaptureConfig 后台粘贴设置
+type CaptureConfig struct {
+	EnableHotkey    bool          `json:"enableHotkey"`    // 是否启用后台粘贴全局热键
+	Hotkey          HotkeyCombo   `json:"hotkey"`          // 后台粘贴全局热键
+	Target          CaptureTarget `json:"target"`          // 目标文档
+	InboxDocumentID int64         `json:"inboxDocumentId"` // 收集箱文档ID，0 表示未指定
+	TimestampFormat string        `json:"timestampFormat"` // 时间戳格式（Go 时间布局）
+}
+
+// CaptureResult 后台粘贴结果
+type CaptureResult struct {
+	DocumentID int64 `json:"documentId"` // 追加到的文档
+	Length     int   `json:"length"`     // 追加的字符数
+}
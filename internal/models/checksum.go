@@ -0,0 +1,11 @@
+package models
+
+// FileChecksum 文件校验结果
+type FileChecksum struct {
+	Path       string            `json:"path"`
+	Name       string            `json:"name"`
+	Size       int64             `json:"size"`
+	Sums       map[string]string `json:"sums"`       // 算法到十六进制校验和
+	DocumentID int64             `json:"documentId"` // 写入结果块的文档，0 表示未写入
+	Block      int               `json:"block"`      // 结果块的序号（从 0 开始）
+}
@@ -0,0 +1,24 @@
+package models
+
+// ClipboardFormat 复制文档到剪贴板的格式
+type ClipboardFormat string
+
+const (
+	// ClipboardMarkdown Markdown 源文本，代码块包裹为围栏代码块
+	ClipboardMarkdown ClipboardFormat = "markdown"
+	// ClipboardHTML 富文本，同时写入 HTML 和纯文本，粘贴到文档编辑器时保留格式
+	ClipboardHTML ClipboardFormat = "html"
+	// ClipboardText 纯文本，去掉块分隔符
+	ClipboardText ClipboardFormat = "text"
+	// ClipboardSlack Slack 消息，代码围栏不带语言标记
+	ClipboardSlack ClipboardFormat = "slack"
+	// ClipboardDiscord Discord 消息，代码围栏带语言标记以启用高亮
+	ClipboardDiscord ClipboardFormat = "discord"
+)
+
+// ClipboardCopyResult 复制结果
+type ClipboardCopyResult struct {
+	Format   ClipboardFormat `json:"format"`
+	Length   int             `json:"length"`   // 写入的纯文本字符数
+	RichText bool            `json:"richText"` // 是否写入了 HTML，系统不支持时只写入纯文本
+}
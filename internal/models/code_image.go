@@ -0,0 +1,20 @@
+package models
+
+// CodeImageOptions 文档截图选项
+type CodeImageOptions struct {
+	Block       int     `json:"block"`       // 只渲染指定块（从 1 开始），0 表示整个文档
+	ShowTitle   bool    `json:"showTitle"`   // 是否在标题栏显示文档标题
+	LineNumbers bool    `json:"lineNumbers"` // 是否显示行号
+	Theme       string  `json:"theme"`       // 主题名称，为空时使用当前主题
+	Background  string  `json:"background"`  // 窗口外的背景色，为空时按主题生成
+	FontSize    float64 `json:"fontSize"`    // 字号，0 使用默认值
+	Padding     int     `json:"padding"`     // 窗口四周的留白，0 使用默认值，负数表示不留白
+	Scale       int     `json:"scale"`       // 像素倍率，0 使用默认值
+}
+
+// CodeImage 文档截图
+type CodeImage struct {
+	Data   []byte `json:"data"`   // PNG 数据
+	Width  int    `json:"width"`  // 宽度（像素）
+	Height int    `json:"height"` // 高度（像素）
+}
@@ -0,0 +1,40 @@
+package models
+
+// Collection 文档文件夹，可以嵌套
+type Collection struct {
+	ID        int64  `json:"id" db:"id"`
+	ParentID  int64  `json:"parentId" db:"parent_id"` // 上级文件夹，0 表示顶层
+	Name      string `json:"name" db:"name"`
+	CreatedAt string `json:"createdAt" db:"created_at"`
+	UpdatedAt string `json:"updatedAt" db:"updated_at"`
+}
+
+// CollectionNode 文件夹树节点
+type CollectionNode struct {
+	Collection
+	DocumentCount int               `json:"documentCount"` // 直接包含的文档数，不含子文件夹和回收站中的文档
+	Children      []*CollectionNode `json:"children"`
+}
+
+// CollectionDeleteMode 删除文件夹时如何处理其中的内容
+type CollectionDeleteMode string
+
+const (
+	// CollectionDeleteMoveUp 子文件夹和文档移到被删除文件夹的上级
+	CollectionDeleteMoveUp CollectionDeleteMode = "move_up"
+	// CollectionDeleteTrash 一并删除子文件夹，其中的文档移入回收站；锁定的文档和默认文档移到上级
+	CollectionDeleteTrash CollectionDeleteMode = "trash"
+)
+
+// CollectionsConfig 文件夹设置
+type CollectionsConfig struct {
+	DeleteMode CollectionDeleteMode `json:"deleteMode"` // 删除文件夹的默认处理方式
+}
+
+// CollectionDeleteResult 删除文件夹的结果
+type CollectionDeleteResult struct {
+	Mode        CollectionDeleteMode `json:"mode"`
+	Collections int                  `json:"collections"` // 删除的文件夹数，含子文件夹
+	Moved       int                  `json:"moved"`       // 移到上级的文档数
+	Trashed     int                  `json:"trashed"`     // 移入回收站的文档数
+}
@@ -0,0 +1,39 @@
+package models
+
+// CommandParam 预设命令的参数
+// Allowed 不为空时只接受列出的取值；否则取值须匹配 Pattern，Pattern 为空时只允许字母、数字和 ._:/@=- 且不能以 - 开头
+type CommandParam struct {
+	Name    string   `json:"name"`    // 占位符名称，命令中写作 {{name}}
+	Label   string   `json:"label"`   // 输入框提示，为空时显示名称
+	Default string   `json:"default"` // 默认值
+	Allowed []string `json:"allowed"` // 允许的取值
+	Pattern string   `json:"pattern"` // 取值须完整匹配的正则表达式
+}
+
+// ShellCommand 命令面板中的预设命令
+// 命令按空白拆分，不经过 shell，占位符只能出现在参数中，替换后的取值不会产生新的参数
+type ShellCommand struct {
+	ID      string         `json:"id"`      // 唯一标识
+	Name    string         `json:"name"`    // 命令面板中显示的名称
+	Enabled bool           `json:"enabled"` // 是否启用
+	Command string         `json:"command"` // 命令模板，如 kubectl get pods -n {{ns}}
+	Params  []CommandParam `json:"params"`  // 参数
+	Confirm bool           `json:"confirm"` // 执行前是否需要确认
+	Timeout int            `json:"timeout"` // 超时（秒），0 表示使用默认值
+}
+
+// CommandsConfig 命令面板设置
+type CommandsConfig struct {
+	Enabled  bool           `json:"enabled"` // 总开关
+	Commands []ShellCommand `json:"commands"`
+}
+
+// CommandResult 预设命令的执行结果
+type CommandResult struct {
+	Args      []string `json:"args"`      // 实际执行的命令和参数
+	Output    string   `json:"output"`    // 合并的标准输出和错误输出，已去除 ANSI 转义序列
+	ExitCode  int      `json:"exitCode"`  // 退出码，未能启动时为 -1
+	Duration  int64    `json:"duration"`  // 耗时（毫秒）
+	TimedOut  bool     `json:"timedOut"`  // 是否超时被终止
+	Truncated bool     `json:"truncated"` // 输出是否被截断
+}
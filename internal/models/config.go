@@ -17,6 +17,18 @@ const (
 	TabTypeTab TabType = "tab"
 )
 
+// RichPasteMode 粘贴富文本（HTML、RTF）时的处理方式
+type RichPasteMode string
+
+const (
+	// RichPasteConvert 转换为 Markdown 后插入
+	RichPasteConvert RichPasteMode = "convert"
+	// RichPastePlain 只插入纯文本
+	RichPastePlain RichPasteMode = "plain"
+	// RichPasteAsk 每次粘贴时询问
+	RichPasteAsk RichPasteMode = "ask"
+)
+
 // LanguageType 语言类型定义
 type LanguageType string
 
@@ -49,6 +61,20 @@ const (
 	UpdateSourceGitea UpdateSourceType = "gitea"
 )
 
+// UpdateMode 更新模式
+type UpdateMode string
+
+const (
+	// UpdateModeAuto 启动时检查，发现新版本后自动下载安装
+	UpdateModeAuto UpdateMode = "auto"
+	// UpdateModeNotify 启动时检查，只提示不安装
+	UpdateModeNotify UpdateMode = "notify"
+	// UpdateModeManual 只在用户手动检查时联网
+	UpdateModeManual UpdateMode = "manual"
+	// UpdateModeOffline 完全离线，不访问任何更新源
+	UpdateModeOffline UpdateMode = "offline"
+)
+
 // GithubConfig GitHub配置
 type GithubConfig struct {
 	Owner string `json:"owner"` // 仓库所有者
@@ -70,7 +96,18 @@ type GeneralConfig struct {
 	StartAtLogin     bool   `json:"startAtLogin"`     // 开机启动设置
 
 	// 窗口吸附设置
-	EnableWindowSnap bool `json:"enableWindowSnap"` // 是否启用窗口吸附功能（阈值现在是自适应的）
+	EnableWindowSnap  bool `json:"enableWindowSnap"`  // 是否启用窗口吸附功能（阈值现在是自适应的）
+	AnimateWindowSnap bool `json:"animateWindowSnap"` // 吸附和跟随主窗口时平滑移动，系统开启减少动态效果时不生效
+
+	// 拖动已吸附子窗口时解除吸附，或带动主窗口整组移动
+	WindowSnapDragMode WindowSnapDragMode `json:"windowSnapDragMode"`
+
+	// 新建文档窗口时直接吸附到主窗口的一侧，关闭时在屏幕居中打开
+	SnapNewWindows    bool              `json:"snapNewWindows"`
+	NewWindowSnapEdge NewWindowSnapEdge `json:"newWindowSnapEdge"`
+
+	// 应用已在运行时再次启动的行为，命令行参数 --focus、--capture 等优先
+	SecondInstanceAction SecondInstanceAction `json:"secondInstanceAction"`
 
 	// 全局热键设置
 	EnableGlobalHotkey bool        `json:"enableGlobalHotkey"` // 是否启用全局热键
@@ -79,6 +116,24 @@ type GeneralConfig struct {
 	// 界面设置
 	EnableLoadingAnimation bool `json:"enableLoadingAnimation"` // 是否启用加载动画
 	EnableTabs             bool `json:"enableTabs"`             // 是否启用标签页模式
+
+	// 托盘菜单中固定的文本片段，点击后复制到剪贴板
+	TraySnippets []TraySnippet `json:"traySnippets"`
+
+	// 固定到 macOS 菜单栏的文档，0 表示未固定
+	MenuBarDocumentID int64 `json:"menuBarDocumentId"`
+
+	// 标题栏关闭、最小化按钮的行为
+	WindowButtons WindowButtonsConfig `json:"windowButtons"`
+
+	// 窗口吸附与系统窗口排列功能的兼容开关
+	WindowSnapCompat WindowSnapCompatConfig `json:"windowSnapCompat"`
+}
+
+// TraySnippet 托盘菜单中固定的文本片段
+type TraySnippet struct {
+	Title   string `json:"title"`   // 菜单中显示的名称，为空时取内容首行
+	Content string `json:"content"` // 片段内容
 }
 
 // HotkeyCombo 热键组合定义
@@ -105,6 +160,13 @@ type EditingConfig struct {
 
 	// 保存选项
 	AutoSaveDelay int `json:"autoSaveDelay"` // 自动保存延迟（毫秒）
+
+	// 粘贴选项
+	RichPaste RichPasteMode `json:"richPaste"` // 在 Markdown 块中粘贴富文本时的处理方式
+
+	// 外部编辑器
+	ExternalEditor string `json:"externalEditor"` // 外部编辑器命令，{file} 为文件路径占位符，为空时使用系统默认程序
+	SourceEditor   string `json:"sourceEditor"`   // 打开堆栈源码位置的编辑器命令，{file}、{line} 为占位符，为空时优先使用 VS Code
 }
 
 // AppearanceConfig 外观设置配置
@@ -117,23 +179,65 @@ type AppearanceConfig struct {
 // UpdatesConfig 更新设置配置
 type UpdatesConfig struct {
 	Version            string           `json:"version"`            // 当前版本号
-	AutoUpdate         bool             `json:"autoUpdate"`         // 是否自动更新
+	Mode               UpdateMode       `json:"mode"`               // 更新模式
+	AutoUpdate         bool             `json:"autoUpdate"`         // 是否启动时检查更新，关闭时 auto/notify 模式按 manual 处理
 	PrimarySource      UpdateSourceType `json:"primarySource"`      // 主要更新源
 	BackupSource       UpdateSourceType `json:"backupSource"`       // 备用更新源
 	BackupBeforeUpdate bool             `json:"backupBeforeUpdate"` // 更新前是否备份
 	UpdateTimeout      int              `json:"updateTimeout"`      // 更新超时时间(秒)
 	Github             GithubConfig     `json:"github"`             // GitHub配置
 	Gitea              GiteaConfig      `json:"gitea"`              // Gitea配置
+
+	// 升级后的更新说明
+	WhatsNew            bool   `json:"whatsNew"`            // 版本变化后是否提示更新说明
+	AcknowledgedVersion string `json:"acknowledgedVersion"` // 用户已查看过更新说明的版本
+}
+
+// SecurityConfig 安全设置配置
+type SecurityConfig struct {
+	SecretDetection      bool `json:"secretDetection"`      // 保存时检测疑似敏感信息（密钥、私钥、JWT等）
+	MaskSecretsInExports bool `json:"maskSecretsInExports"` // 导出和分享时自动脱敏
+
+	// 应用锁设置
+	AppLockEnabled  bool `json:"appLockEnabled"`  // 是否启用应用锁（口令哈希保存在系统钥匙串中）
+	AutoLockMinutes int  `json:"autoLockMinutes"` // 空闲多少分钟后自动锁定，0表示不自动锁定
+
+	TOTPAccounts []TOTPAccount `json:"totpAccounts"` // 一次性密码账户，密钥保存在系统钥匙串中
+}
+
+// LocalServerConfig 本地HTTP服务配置
+type LocalServerConfig struct {
+	Enabled            bool `json:"enabled"`            // 是否启用本地HTTP服务
+	Port               int  `json:"port"`               // 监听端口
+	AllowLAN           bool `json:"allowLAN"`           // 是否监听所有网卡以允许局域网访问（分享链接需要）
+	DefaultShareExpiry int  `json:"defaultShareExpiry"` // 分享链接默认有效期（分钟）
 }
 
 // AppConfig 应用配置 - 按照前端设置页面分类组织
 type AppConfig struct {
-	General    GeneralConfig    `json:"general"`    // 通用设置
-	Editing    EditingConfig    `json:"editing"`    // 编辑设置
-	Appearance AppearanceConfig `json:"appearance"` // 外观设置
-	Updates    UpdatesConfig    `json:"updates"`    // 更新设置
-	Backup     GitBackupConfig  `json:"backup"`     // Git备份设置
-	Metadata   ConfigMetadata   `json:"metadata"`   // 配置元数据
+	General       GeneralConfig       `json:"general"`       // 通用设置
+	Editing       EditingConfig       `json:"editing"`       // 编辑设置
+	Appearance    AppearanceConfig    `json:"appearance"`    // 外观设置
+	Updates       UpdatesConfig       `json:"updates"`       // 更新设置
+	Backup        GitBackupConfig     `json:"backup"`        // Git备份设置
+	Security      SecurityConfig      `json:"security"`      // 安全设置
+	Server        LocalServerConfig   `json:"server"`        // 本地HTTP服务设置
+	Integrations  IntegrationsConfig  `json:"integrations"`  // 第三方集成设置
+	Translation   TranslationConfig   `json:"translation"`   // 翻译设置
+	Accessibility AccessibilityConfig `json:"accessibility"` // 辅助功能设置
+	Notifications NotificationConfig  `json:"notifications"` // 通知设置
+	Publish       PublishConfig       `json:"publish"`       // 发布目标设置
+	Gallery       GalleryConfig       `json:"gallery"`       // 模板库设置
+	Capture       CaptureConfig       `json:"capture"`       // 后台粘贴设置
+	Retention     RetentionConfig     `json:"retention"`     // 文档保留策略
+	Hooks         HooksConfig         `json:"hooks"`         // 事件命令设置
+	Commands      CommandsConfig      `json:"commands"`      // 命令面板设置
+	Export        ExportConfig        `json:"export"`        // 导出设置
+	Trash         TrashConfig         `json:"trash"`         // 回收站设置
+	Collections   CollectionsConfig   `json:"collections"`   // 文件夹设置
+	Snapshots     SnapshotConfig      `json:"snapshots"`     // 文档自动快照设置
+	Network       NetworkConfig       `json:"network"`       // 对外网络请求设置
+	Metadata      ConfigMetadata      `json:"metadata"`      // 配置元数据
 }
 
 // ConfigMetadata 配置元数据
@@ -155,9 +259,15 @@ func NewDefaultAppConfig() *AppConfig {
 			EnableSystemTray:       true,
 			StartAtLogin:           false,
 			EnableWindowSnap:       true, // 默认启用窗口吸附
+			AnimateWindowSnap:      true,
+			WindowSnapDragMode:     WindowSnapDragDetach,
+			SnapNewWindows:         false,
+			NewWindowSnapEdge:      NewWindowSnapRight,
+			SecondInstanceAction:   SecondInstanceFocus,
 			EnableGlobalHotkey:     false,
 			EnableLoadingAnimation: true,  // 默认启用加载动画
 			EnableTabs:             false, // 默认不启用标签页模式
+			TraySnippets:           []TraySnippet{},
 			GlobalHotkey: HotkeyCombo{
 				Ctrl:  false,
 				Shift: false,
@@ -165,6 +275,17 @@ func NewDefaultAppConfig() *AppConfig {
 				Win:   false,
 				Key:   "X",
 			},
+			WindowButtons: WindowButtonsConfig{
+				// 与以往行为一致：主窗口关闭时隐藏到托盘
+				Main:     WindowButtonBehavior{Close: WindowActionTray, Minimize: WindowActionMinimize},
+				Document: WindowButtonBehavior{Close: WindowActionClose, Minimize: WindowActionMinimize},
+			},
+			WindowSnapCompat: WindowSnapCompatConfig{
+				SnapLayouts:  true,
+				FancyZones:   true,
+				StageManager: true,
+				TilingWM:     true,
+			},
 		},
 		Editing: EditingConfig{
 			// 字体设置
@@ -178,6 +299,11 @@ func NewDefaultAppConfig() *AppConfig {
 			TabType:         TabTypeTab,
 			// 保存选项
 			AutoSaveDelay: 2000,
+			// 粘贴选项
+			RichPaste: RichPasteConvert,
+			// 外部编辑器
+			ExternalEditor: "",
+			SourceEditor:   "",
 		},
 		Appearance: AppearanceConfig{
 			Language:     LangEnUS,
@@ -186,6 +312,7 @@ func NewDefaultAppConfig() *AppConfig {
 		},
 		Updates: UpdatesConfig{
 			Version:            version.Version,
+			Mode:               UpdateModeNotify,
 			AutoUpdate:         true,
 			PrimarySource:      UpdateSourceGitea,
 			BackupSource:       UpdateSourceGithub,
@@ -200,6 +327,8 @@ func NewDefaultAppConfig() *AppConfig {
 				Owner:   "landaiqing",
 				Repo:    "voidraft",
 			},
+			WhatsNew:            true,
+			AcknowledgedVersion: "",
 		},
 		Backup: GitBackupConfig{
 			Enabled:        false,
@@ -212,6 +341,108 @@ func NewDefaultAppConfig() *AppConfig {
 			BackupInterval: 60,
 			AutoBackup:     false,
 		},
+		Security: SecurityConfig{
+			SecretDetection:      true,
+			MaskSecretsInExports: false,
+			AppLockEnabled:       false,
+			AutoLockMinutes:      15,
+			TOTPAccounts:         []TOTPAccount{},
+		},
+		Server: LocalServerConfig{
+			Enabled:            false,
+			Port:               17300,
+			AllowLAN:           false,
+			DefaultShareExpiry: 60,
+		},
+		Integrations: IntegrationsConfig{
+			Jira: JiraIntegrationConfig{
+				Enabled:  false,
+				BaseURL:  "",
+				Email:    "",
+				Projects: []string{},
+			},
+			Github: GithubIntegrationConfig{
+				Enabled: false,
+				APIURL:  "https://api.github.com",
+			},
+			CacheTTL: 30,
+		},
+		Translation: TranslationConfig{
+			DefaultTranslator: "bing",
+			DefaultTarget:     "en",
+			TargetRules: []TranslationTargetRule{
+				{Source: "en", Target: "zh"},
+			},
+			EnableQuickTranslateHotkey: false,
+			QuickTranslateHotkey: HotkeyCombo{
+				Ctrl:  false,
+				Shift: true,
+				Alt:   true,
+				Win:   false,
+				Key:   "T",
+			},
+		},
+		Accessibility: AccessibilityConfig{
+			ReducedMotion: AccessibilityAuto,
+			HighContrast:  AccessibilityAuto,
+			ScreenReader:  AccessibilityAuto,
+		},
+		Notifications: NotificationConfig{
+			RespectDoNotDisturb: true,
+			BreakThrough:        []NotificationCategory{NotificationCategoryBackupFailure},
+		},
+		Publish: PublishConfig{
+			Targets: []PublishTarget{},
+		},
+		Gallery: GalleryConfig{
+			Enabled:   false,
+			IndexURL:  "",
+			PublicKey: "",
+		},
+		Capture: CaptureConfig{
+			EnableHotkey: false,
+			Hotkey: HotkeyCombo{
+				Ctrl:  false,
+				Shift: true,
+				Alt:   true,
+				Win:   false,
+				Key:   "V",
+			},
+			Target:          CaptureTargetRecent,
+			InboxDocumentID: 0,
+			TimestampFormat: "2006-01-02 15:04:05",
+		},
+		Retention: RetentionConfig{
+			Enabled:             false,
+			ArchiveAfterDays:    90,
+			PurgeTrashAfterDays: 30,
+			Overrides:           []RetentionOverride{},
+		},
+		Hooks: HooksConfig{
+			Enabled: false,
+			Hooks:   []EventHook{},
+		},
+		Commands: CommandsConfig{
+			Enabled:  false,
+			Commands: []ShellCommand{},
+		},
+		Export: ExportConfig{
+			Templates: []ExportTemplate{},
+		},
+		Trash: TrashConfig{
+			AutoPurgeDays: 0,
+		},
+		Collections: CollectionsConfig{
+			DeleteMode: CollectionDeleteMoveUp,
+		},
+		Snapshots: SnapshotConfig{
+			Enabled:         true,
+			IntervalMinutes: 10,
+		},
+		Network: NetworkConfig{
+			CABundles:       []string{},
+			CertificatePins: []CertificatePin{},
+		},
 		Metadata: ConfigMetadata{
 			LastUpdated: time.Now().Format(time.RFC3339),
 			Version:     version.Version,
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// DashboardData 工作区统计面板数据，一次调用返回面板所需的全部统计
+type DashboardData struct {
+	Documents   DashboardDocumentCounts `json:"documents"`
+	Tags        []*Tag                  `json:"tags"`        // 按标签统计，按名称排序
+	Languages   []DashboardCount        `json:"languages"`   // 按块语言统计，按文档数从多到少
+	Activity    []DashboardActivityDay  `json:"activity"`    // 活动热力图，按日期从旧到新，没有活动的日期计数为 0
+	Storage     DashboardStorage        `json:"storage"`     // 存储占用
+	Sync        DashboardSync           `json:"sync"`        // Git 备份状态
+	MostOpened  []DashboardDocument     `json:"mostOpened"`  // 打开次数最多的文档
+	Largest     []DashboardDocument     `json:"largest"`     // 内容最大的文档
+	GeneratedAt time.Time               `json:"generatedAt"` // 统计时间
+}
+
+// DashboardDocumentCounts 文档数量统计，Total 不含回收站和已归档的文档
+type DashboardDocumentCounts struct {
+	Total    int `json:"total"`
+	Locked   int `json:"locked"`
+	Journals int `json:"journals"` // 日志和日志归档
+	Archived int `json:"archived"`
+	Trashed  int `json:"trashed"`
+}
+
+// DashboardCount 分组计数
+type DashboardCount struct {
+	Name      string `json:"name"`
+	Documents int    `json:"documents"` // 包含该分组的文档数
+	Blocks    int    `json:"blocks"`    // 该分组的块数
+}
+
+// DashboardActivityDay 某一天的活动
+type DashboardActivityDay struct {
+	Date    string `json:"date"`    // YYYY-MM-DD
+	Created int    `json:"created"` // 当天创建的文档数
+	Updated int    `json:"updated"` // 最后一次修改在当天的文档数
+}
+
+// DashboardStorage 存储占用（字节）
+type DashboardStorage struct {
+	DatabaseBytes   int64 `json:"databaseBytes"`   // 数据库文件，含 WAL
+	ContentBytes    int64 `json:"contentBytes"`    // 文档内容
+	AttachmentBytes int64 `json:"attachmentBytes"` // 附件
+	AttachmentCount int   `json:"attachmentCount"`
+}
+
+// DashboardSync Git 备份状态
+type DashboardSync struct {
+	Enabled      bool      `json:"enabled"`
+	AutoBackup   bool      `json:"autoBackup"`
+	Initialized  bool      `json:"initialized"`  // 仓库已初始化
+	Syncing      bool      `json:"syncing"`      // 正在推送
+	LastBackupAt time.Time `json:"lastBackupAt"` // 最近一次备份提交的时间，没有提交时为零值
+}
+
+// DashboardDocument 面板中列出的文档
+type DashboardDocument struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	OpenCount   int    `json:"openCount"`
+	ContentSize int64  `json:"contentSize"`
+	UpdatedAt   string `json:"updatedAt"`
+}
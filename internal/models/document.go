@@ -12,7 +12,66 @@ type Document struct {
 	CreatedAt string `json:"createdAt" db:"created_at"`
 	UpdatedAt string `json:"updatedAt" db:"updated_at"`
 	IsDeleted bool   `json:"is_deleted" db:"is_deleted"`
-	IsLocked  bool   `json:"is_locked" db:"is_locked"` // 锁定标志，锁定的文档无法被删除
+	IsLocked  bool   `json:"is_locked" db:"is_locked"` // 锁定标志，锁定的文档只读且无法被删除
+
+	OpenCount    int64  `json:"openCount" db:"open_count"`        // 打开次数，用于常用度排序
+	LastOpenedAt string `json:"lastOpenedAt" db:"last_opened_at"` // 最近打开时间
+	Preview      string `json:"preview" db:"preview"`             // 纯文本预览摘要，保存时生成
+	Size         int64  `json:"size" db:"content_size"`           // 内容长度（字符），保存时更新
+
+	IsArchived    bool         `json:"isArchived" db:"is_archived"`       // 是否已归档，归档的文档不出现在文档列表中
+	Kind          DocumentKind `json:"kind" db:"kind"`                    // 文档类型，普通文档为空
+	JournalPeriod string       `json:"journalPeriod" db:"journal_period"` // 日志文档当前记录的月份，如 2026-10
+	DeletedAt     string       `json:"deletedAt" db:"deleted_at"`         // 移入回收站的时间，不在回收站时为空
+	CollectionID  int64        `json:"collectionId" db:"collection_id"`   // 所在文件夹，0 表示根目录
+	IsPinned      bool         `json:"isPinned" db:"is_pinned"`           // 是否置顶，置顶的文档排在列表最前
+	SortOrder     int64        `json:"sortOrder" db:"sort_order"`         // 手动排序的位置，0 表示未手动排序，排在已排序的文档之前
+	TitleAuto     bool         `json:"titleAuto" db:"title_auto"`         // 标题由内容自动生成，用户手动设置标题后不再更新
+
+	IsFavorite bool          `json:"isFavorite" db:"is_favorite"` // 是否收藏
+	ColorLabel DocumentColor `json:"colorLabel" db:"color_label"` // 颜色标签，为空表示无
+	Icon       string        `json:"icon" db:"icon"`              // emoji 图标，为空表示无
+}
+
+// DocumentKind 文档类型
+type DocumentKind string
+
+const (
+	// DocumentKindNote 普通文档
+	DocumentKindNote DocumentKind = ""
+	// DocumentKindJournal 只能追加的日志文档，按月归档
+	DocumentKindJournal DocumentKind = "journal"
+	// DocumentKindJournalArchive 日志文档归档出的历史月份，只读
+	DocumentKindJournalArchive DocumentKind = "journal_archive"
+)
+
+// IsAppendOnly 文档是否只能在末尾追加内容
+func (k DocumentKind) IsAppendOnly() bool {
+	return k == DocumentKindJournal || k == DocumentKindJournalArchive
+}
+
+// DocumentColor 文档的颜色标签
+type DocumentColor string
+
+const (
+	DocumentColorNone   DocumentColor = ""
+	DocumentColorRed    DocumentColor = "red"
+	DocumentColorOrange DocumentColor = "orange"
+	DocumentColorYellow DocumentColor = "yellow"
+	DocumentColorGreen  DocumentColor = "green"
+	DocumentColorBlue   DocumentColor = "blue"
+	DocumentColorPurple DocumentColor = "purple"
+	DocumentColorGray   DocumentColor = "gray"
+)
+
+// Valid 是否为支持的颜色标签
+func (c DocumentColor) Valid() bool {
+	switch c {
+	case DocumentColorNone, DocumentColorRed, DocumentColorOrange, DocumentColorYellow,
+		DocumentColorGreen, DocumentColorBlue, DocumentColorPurple, DocumentColorGray:
+		return true
+	}
+	return false
 }
 
 // NewDocument 创建新文档
@@ -32,3 +91,52 @@ func NewDocument(title, content string) *Document {
 func NewDefaultDocument() *Document {
 	return NewDocument("default", "\n∞∞∞text-a\n")
 }
+
+// DocumentChangeType 文档变更类型
+type DocumentChangeType string
+
+const (
+	DocumentChangeCreated  DocumentChangeType = "created"  // 新建文档
+	DocumentChangeContent  DocumentChangeType = "content"  // 内容更新
+	DocumentChangeTitle    DocumentChangeType = "title"    // 标题更新
+	DocumentChangeDeleted  DocumentChangeType = "deleted"  // 删除文档
+	DocumentChangeRestored DocumentChangeType = "restored" // 恢复文档
+	DocumentChangeLocked   DocumentChangeType = "locked"   // 锁定状态变化
+	DocumentChangeArchived DocumentChangeType = "archived" // 归档状态变化
+	DocumentChangeTags     DocumentChangeType = "tags"     // 标签变化
+	DocumentChangeMoved    DocumentChangeType = "moved"    // 移动到其他文件夹
+	DocumentChangePinned   DocumentChangeType = "pinned"   // 置顶状态变化
+	DocumentChangeMetadata DocumentChangeType = "metadata" // 收藏、颜色标签或图标变化
+)
+
+// DocumentChangeEvent 文档变更事件
+type DocumentChangeEvent struct {
+	Type       DocumentChangeType `json:"type"`
+	DocumentID int64              `json:"documentId"`
+	Title      string             `json:"title,omitempty"` // 标题变更时的新标题
+	Content    string             `json:"-"`               // 内容变更时的新内容，仅供后端监听器使用
+}
+
+// TextRange 文档中的文本范围，偏移量以 UTF-16 码元计，与编辑器的位置一致
+type TextRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// DocumentPatch 文档编辑补丁，用 Insert 替换 [From, To) 范围的内容
+type DocumentPatch struct {
+	From   int    `json:"from"`
+	To     int    `json:"to"`
+	Insert string `json:"insert"`
+	Expect string `json:"expect,omitempty"` // 范围内应有的原文，非空时校验，防止覆盖并发修改
+	Label  string `json:"label"`            // 撤销记录中显示的操作名称
+}
+
+// DocumentPatchResult 补丁应用结果
+type DocumentPatchResult struct {
+	DocumentID int64         `json:"documentId"`
+	Patch      DocumentPatch `json:"patch"`     // 实际应用的补丁
+	Selection  TextRange     `json:"selection"` // 插入内容所在的范围
+	UndoDepth  int           `json:"undoDepth"` // 剩余可撤销的次数
+	Undo       bool          `json:"undo"`      // 是否为撤销操作
+}
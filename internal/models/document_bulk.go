@@ -0,0 +1,33 @@
+package models
+
+// BulkOperation 批量文档操作类型
+type BulkOperation string
+
+const (
+	BulkDelete BulkOperation = "delete" // 移入回收站
+	BulkMove   BulkOperation = "move"   // 移动到文件夹
+	BulkTag    BulkOperation = "tag"    // 添加或移除标签
+	BulkExport BulkOperation = "export" // 导出为 Markdown 文件
+)
+
+// BulkProgress 批量操作进度，通过事件推送给前端
+type BulkProgress struct {
+	Operation BulkOperation `json:"operation"`
+	Done      int           `json:"done"`  // 已处理的文档数
+	Total     int           `json:"total"` // 需要处理的文档数
+	Finished  bool          `json:"finished"`
+}
+
+// BulkSkip 批量操作中被跳过的文档
+type BulkSkip struct {
+	DocumentID int64  `json:"documentId"`
+	Reason     string `json:"reason"`
+}
+
+// BulkResult 批量操作结果，被跳过的文档不影响其他文档
+type BulkResult struct {
+	Operation BulkOperation `json:"operation"`
+	Succeeded []int64       `json:"succeeded"`       // 处理成功的文档
+	Skipped   []BulkSkip    `json:"skipped"`         // 跳过的文档及原因
+	Files     []string      `json:"files,omitempty"` // 导出时写入的文件
+}
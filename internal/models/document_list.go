@@ -0,0 +1,45 @@
+package models
+
+// DocumentSortField 文档列表排序字段
+type DocumentSortField string
+
+const (
+	DocumentSortTitle    DocumentSortField = "title"    // 按标题
+	DocumentSortCreated  DocumentSortField = "created"  // 按创建时间
+	DocumentSortUpdated  DocumentSortField = "updated"  // 按更新时间
+	DocumentSortSize     DocumentSortField = "size"     // 按内容长度
+	DocumentSortFrecency DocumentSortField = "frecency" // 按打开频率和最近打开时间综合排序
+	DocumentSortManual   DocumentSortField = "manual"   // 置顶文档在前，其余按手动排序
+)
+
+// SortDirection 排序方向
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+// DocumentListFilter 文档列表过滤条件
+type DocumentListFilter struct {
+	Query        string `json:"query"`        // 标题包含的文本，不区分大小写
+	LockedOnly   bool   `json:"lockedOnly"`   // 仅列出锁定的文档
+	Tag          string `json:"tag"`          // 仅列出带有该标签的文档，为空时不限
+	CollectionID *int64 `json:"collectionId"` // 仅列出文件夹中的文档（不含子文件夹），0 为根目录，为空时不限
+
+	FavoritesOnly bool          `json:"favoritesOnly"` // 仅列出收藏的文档
+	ColorLabel    DocumentColor `json:"colorLabel"`    // 仅列出带有该颜色标签的文档，为空时不限
+}
+
+// Pagination 键集分页参数
+type Pagination struct {
+	Limit  int    `json:"limit"`  // 每页数量
+	Cursor string `json:"cursor"` // 上一页返回的游标，为空表示第一页
+}
+
+// DocumentPage 文档列表分页结果
+type DocumentPage struct {
+	Items      []*Document `json:"items"`
+	NextCursor string      `json:"nextCursor"` // 为空表示没有下一页
+	Total      int         `json:"total"`      // 满足过滤条件的文档总数
+}
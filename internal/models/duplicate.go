@@ -0,0 +1,17 @@
+package models
+
+// DuplicateDocument 重复分组中的文档
+type DuplicateDocument struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	UpdatedAt   string `json:"updatedAt"`
+	ContentSize int    `json:"contentSize"` // 内容字符数
+	IsLocked    bool   `json:"isLocked"`
+}
+
+// DuplicateCluster 一组内容相同或相近的文档，可以合并或删除多余的文档
+type DuplicateCluster struct {
+	Documents  []DuplicateDocument `json:"documents"`  // 按 ID 升序
+	Exact      bool                `json:"exact"`      // 忽略大小写、空白和标点后内容完全相同
+	Similarity float64             `json:"similarity"` // 相近文档的最低相似度，0 到 1
+}
@@ -0,0 +1,15 @@
+package models
+
+// ExportTemplate 用户定义的导出模板，使用 Go text/template 语法
+// 模板中可使用文档的 .Title、.Tags、.Created、.Updated、.Blocks、.Markdown 和元数据，见 ExportService.RenderDocumentTemplate
+type ExportTemplate struct {
+	ID        string `json:"id"`        // 唯一标识
+	Name      string `json:"name"`      // 显示名称
+	Extension string `json:"extension"` // 导出文件的扩展名，不含点，为空时为 md
+	Template  string `json:"template"`  // 模板内容
+}
+
+// ExportConfig 导出设置
+type ExportConfig struct {
+	Templates []ExportTemplate `json:"templates"` // 自定义导出模板
+}
@@ -0,0 +1,42 @@
+package models
+
+// GalleryConfig 模板库设置
+type GalleryConfig struct {
+	Enabled   bool   `json:"enabled"`   // 是否启用模板库
+	IndexURL  string `json:"indexURL"`  // 索引地址，必须为 HTTPS
+	PublicKey string `json:"publicKey"` // 索引签名公钥（Ed25519，Base64 或十六进制）
+}
+
+// GalleryItem 模板库中的条目
+type GalleryItem struct {
+	ID          string `json:"id"`
+	Kind        string `json:"kind"` // template 或 theme
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Author      string `json:"author"`
+	Size        int64  `json:"size"`
+	Installed   bool   `json:"installed"` // 是否已安装到本地
+}
+
+// GalleryListing 模板库列表
+type GalleryListing struct {
+	Items   []GalleryItem `json:"items"`
+	Updated string        `json:"updated"` // 索引更新时间
+	Cached  bool          `json:"cached"`  // 是否来自离线缓存
+}
+
+// GalleryPreview 条目预览，内容仅用于显示
+type GalleryPreview struct {
+	Item    GalleryItem `json:"item"`
+	Content string      `json:"content"`
+}
+
+// DocumentTemplate 已安装的文档模板
+type DocumentTemplate struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Author      string `json:"author"`
+	Content     string `json:"content"`
+	InstalledAt string `json:"installedAt"`
+}
@@ -0,0 +1,30 @@
+package models
+
+// HookEvent 可触发用户命令的事件
+type HookEvent string
+
+const (
+	// HookEventDocumentSaved 文档内容保存，连续保存合并为一次
+	HookEventDocumentSaved HookEvent = "document.saved"
+	// HookEventExportFinished 导出任务结束，成功或失败都会触发
+	HookEventExportFinished HookEvent = "export.finished"
+	// HookEventSyncConflict 外部编辑器修改与应用内修改冲突
+	HookEventSyncConflict HookEvent = "sync.conflict"
+)
+
+// EventHook 事件触发的用户命令
+// 命令按空白拆分，不经过 shell；参数中的 {name} 占位符替换为事件变量，如 {event}、{documentId}、{title}
+type EventHook struct {
+	Name    string    `json:"name"`    // 名称，用于日志
+	Enabled bool      `json:"enabled"` // 是否启用
+	Event   HookEvent `json:"event"`   // 触发事件
+	Tags    []string  `json:"tags"`    // 文档带有其中任一标签时才触发，为空时匹配所有事件；非文档事件不匹配设置了标签的命令
+	Command string    `json:"command"` // 要执行的命令
+	Timeout int       `json:"timeout"` // 超时（秒），0 表示使用默认值
+}
+
+// HooksConfig 事件命令设置
+type HooksConfig struct {
+	Enabled bool        `json:"enabled"` // 总开关
+	Hooks   []EventHook `json:"hooks"`
+}
@@ -0,0 +1,48 @@
+package models
+
+// IssueProvider 问题追踪平台类型
+type IssueProvider string
+
+const (
+	// IssueProviderJira Jira问题
+	IssueProviderJira IssueProvider = "jira"
+	// IssueProviderGithub GitHub Issue/PR
+	IssueProviderGithub IssueProvider = "github"
+)
+
+// IsValid 是否为支持的平台
+func (p IssueProvider) IsValid() bool {
+	return p == IssueProviderJira || p == IssueProviderGithub
+}
+
+// JiraIntegrationConfig Jira集成配置
+type JiraIntegrationConfig struct {
+	Enabled  bool     `json:"enabled"`  // 是否启用
+	BaseURL  string   `json:"baseURL"`  // Jira服务器地址，如 https://example.atlassian.net
+	Email    string   `json:"email"`    // 账户邮箱（Cloud使用邮箱+API Token，留空则使用Bearer Token）
+	Projects []string `json:"projects"` // 限定识别的项目Key，为空时识别所有形如 ABC-123 的引用
+}
+
+// GithubIntegrationConfig GitHub集成配置
+type GithubIntegrationConfig struct {
+	Enabled bool   `json:"enabled"` // 是否启用
+	APIURL  string `json:"apiURL"`  // API地址，GitHub Enterprise可修改
+}
+
+// IntegrationsConfig 第三方集成配置，访问令牌保存在系统钥匙串中
+type IntegrationsConfig struct {
+	Jira     JiraIntegrationConfig   `json:"jira"`     // Jira配置
+	Github   GithubIntegrationConfig `json:"github"`   // GitHub配置
+	CacheTTL int                     `json:"cacheTTL"` // 引用解析结果缓存时间（分钟）
+}
+
+// IssueReference 文档中识别出的问题引用
+type IssueReference struct {
+	Key      string        `json:"key"`             // 原始引用文本，如 ABC-123、org/repo#456
+	Provider IssueProvider `json:"provider"`        // 所属平台
+	Title    string        `json:"title"`           // 问题标题
+	Status   string        `json:"status"`          // 问题状态
+	URL      string        `json:"url"`             // 问题链接
+	Resolved bool          `json:"resolved"`        // 是否解析成功
+	Error    string        `json:"error,omitempty"` // 解析失败原因
+}
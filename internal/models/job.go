@@ -0,0 +1,44 @@
+package models
+
+// JobStatus 后台任务状态
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"    // 排队中
+	JobRunning   JobStatus = "running"   // 执行中
+	JobSucceeded JobStatus = "succeeded" // 已完成
+	JobFailed    JobStatus = "failed"    // 失败
+	JobCanceled  JobStatus = "canceled"  // 已取消
+)
+
+// IsFinished 任务是否已结束
+func (s JobStatus) IsFinished() bool {
+	return s == JobSucceeded || s == JobFailed || s == JobCanceled
+}
+
+// JobKind 后台任务类型
+type JobKind string
+
+const (
+	JobKindExport    JobKind = "export"    // 导出归档
+	JobKindImport    JobKind = "import"    // 导入归档
+	JobKindIndex     JobKind = "index"     // 重建搜索索引
+	JobKindChecksum  JobKind = "checksum"  // 计算文件校验和
+	JobKindPublish   JobKind = "publish"   // 上传到发布目标
+	JobKindRetention JobKind = "retention" // 执行文档保留策略
+)
+
+// Job 后台任务，导入、导出、索引等耗时操作统一通过任务执行
+type Job struct {
+	ID         int64     `json:"id" db:"id"`
+	Kind       JobKind   `json:"kind" db:"kind"`
+	Title      string    `json:"title" db:"title"`
+	Status     JobStatus `json:"status" db:"status"`
+	Progress   float64   `json:"progress" db:"progress"` // 进度，0到1
+	Message    string    `json:"message" db:"message"`   // 当前步骤说明
+	Error      string    `json:"error" db:"error"`
+	Result     string    `json:"result" db:"result"` // 任务结果，JSON 格式
+	CreatedAt  string    `json:"createdAt" db:"created_at"`
+	StartedAt  string    `json:"startedAt" db:"started_at"`
+	FinishedAt string    `json:"finishedAt" db:"finished_at"`
+}
@@ -73,6 +73,9 @@ const (
 	HistoryRedoCommand          KeyBindingCommand = "historyRedo"          // 重做
 	HistoryUndoSelectionCommand KeyBindingCommand = "historyUndoSelection" // 撤销选择
 	HistoryRedoSelectionCommand KeyBindingCommand = "historyRedoSelection" // 重做选择
+
+	// 应用相关
+	LockAppCommand KeyBindingCommand = "lockApp" // 锁定应用
 )
 
 // KeyBindingMetadata 快捷键配置元数据
@@ -453,6 +456,15 @@ func NewDefaultKeyBindings() []KeyBinding {
 			Enabled:   true,
 			IsDefault: true,
 		},
+
+		// 应用快捷键
+		{
+			Command:   LockAppCommand,
+			Extension: ExtensionEditor,
+			Key:       "Mod-Alt-l",
+			Enabled:   true,
+			IsDefault: true,
+		},
 	}
 }
 
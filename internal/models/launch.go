@@ -0,0 +1,32 @@
+package models
+
+import "strings"
+
+// SecondInstanceAction 应用已在运行时再次启动的行为
+type SecondInstanceAction string
+
+const (
+	SecondInstanceFocus          SecondInstanceAction = "focus"           // 显示并聚焦主窗口
+	SecondInstanceCapture        SecondInstanceAction = "capture"         // 在窗口中打开收集箱文档
+	SecondInstanceNewDocument    SecondInstanceAction = "new-document"    // 新建文档并在窗口中打开
+	SecondInstanceRestoreSession SecondInstanceAction = "restore-session" // 显示主窗口并重新打开最近的文档窗口
+)
+
+// Valid 是否为支持的行为
+func (a SecondInstanceAction) Valid() bool {
+	switch a {
+	case SecondInstanceFocus, SecondInstanceCapture, SecondInstanceNewDocument, SecondInstanceRestoreSession:
+		return true
+	}
+	return false
+}
+
+// SecondInstanceActionFromArgs 命令行参数指定的行为，如 --new-document，未指定时返回空
+func SecondInstanceActionFromArgs(args []string) SecondInstanceAction {
+	for _, arg := range args {
+		if action := SecondInstanceAction(strings.TrimPrefix(arg, "--")); arg != string(action) && action.Valid() {
+			return action
+		}
+	}
+	return ""
+}
@@ -0,0 +1,21 @@
+package models
+
+// LogFilterOptions 日志整理选项
+type LogFilterOptions struct {
+	SourceZone string   `json:"sourceZone"` // 时间戳没有时区时使用的时区，为空表示本地时区
+	TargetZone string   `json:"targetZone"` // 统一换算到的时区，为空时不改写时间戳
+	Levels     []string `json:"levels"`     // 保留的级别：trace、debug、info、warn、error、fatal，为空时不过滤
+	Include    string   `json:"include"`    // 保留任一行匹配的记录（正则）
+	Exclude    string   `json:"exclude"`    // 丢弃任一行匹配的记录（正则）
+	Since      string   `json:"since"`      // 起始时间（含），支持时间戳、ISO 时间和 "2 hours ago" 等短语
+	Until      string   `json:"until"`      // 结束时间（不含）
+}
+
+// LogFilterResult 日志整理结果
+type LogFilterResult struct {
+	Records    int `json:"records"`    // 记录总数，带缩进的延续行计入所属记录
+	Kept       int `json:"kept"`       // 保留的记录数
+	Lines      int `json:"lines"`      // 写入新块的行数
+	Normalized int `json:"normalized"` // 改写的时间戳数量
+	BlockIndex int `json:"blockIndex"` // 新块的序号，没有保留任何记录时为 -1
+}
@@ -0,0 +1,15 @@
+package models
+
+// NetworkConfig 对外网络请求设置，翻译、备份、更新、模板库和集成等所有对外请求共用
+// 用于经过 TLS 拦截代理的企业网络
+type NetworkConfig struct {
+	Proxy           string           `json:"proxy"`           // 代理地址，如 http://proxy:8080，为空时使用 HTTP_PROXY 等环境变量
+	CABundles       []string         `json:"caBundles"`       // 额外信任的 PEM 证书文件
+	CertificatePins []CertificatePin `json:"certificatePins"` // 证书校验的例外
+}
+
+// CertificatePin 证书校验的例外：主机的证书链无法校验时，服务器证书的指纹匹配，或由指纹匹配的 CA 证书签发即信任
+type CertificatePin struct {
+	Host   string `json:"host"`   // 主机名，不含端口
+	SHA256 string `json:"sha256"` // 证书的 SHA-256 指纹，十六进制，可带冒号
+}
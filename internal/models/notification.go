@@ -0,0 +1,22 @@
+package models
+
+// NotificationCategory 通知分类，用于勿扰模式下决定是否放行
+type NotificationCategory string
+
+const (
+	NotificationCategoryUpdate        NotificationCategory = "update"         // 发现新版本
+	NotificationCategoryBackupFailure NotificationCategory = "backup-failure" // 自动备份失败
+	NotificationCategoryWhatsNew      NotificationCategory = "whats-new"      // 升级后的更新说明
+)
+
+// NotificationConfig 通知设置
+type NotificationConfig struct {
+	RespectDoNotDisturb bool                   `json:"respectDoNotDisturb"` // 系统处于勿扰/专注模式时暂缓通知，结束后再发送
+	BreakThrough        []NotificationCategory `json:"breakThrough"`        // 勿扰模式下仍立即发送的分类
+}
+
+// NotificationStatus 通知状态
+type NotificationStatus struct {
+	DoNotDisturb bool `json:"doNotDisturb"` // 系统当前是否处于勿扰/专注模式
+	Queued       int  `json:"queued"`       // 等待勿扰结束后发送的通知数量
+}
@@ -0,0 +1,40 @@
+package models
+
+// PublishProtocol 发布上传协议
+type PublishProtocol string
+
+const (
+	PublishProtocolSFTP PublishProtocol = "sftp"
+	PublishProtocolSCP  PublishProtocol = "scp"
+)
+
+// PublishFormat 发布的文件格式
+type PublishFormat string
+
+const (
+	PublishFormatHTML     PublishFormat = "html"     // 独立 HTML 页面
+	PublishFormatMarkdown PublishFormat = "markdown" // Markdown 源文本
+)
+
+// PublishTarget SSH 发布目标，使用 SSH agent 中的密钥认证
+type PublishTarget struct {
+	Name      string          `json:"name"`      // 唯一名称
+	Host      string          `json:"host"`      // 主机
+	Port      int             `json:"port"`      // 端口，0 表示 22
+	User      string          `json:"user"`      // 用户名
+	RemoteDir string          `json:"remoteDir"` // 远程目录，需已存在
+	Protocol  PublishProtocol `json:"protocol"`  // sftp 或 scp
+}
+
+// PublishConfig 发布设置
+type PublishConfig struct {
+	Targets    []PublishTarget `json:"targets"`    // 发布目标
+	KnownHosts string          `json:"knownHosts"` // known_hosts 路径，为空时使用 ~/.ssh/known_hosts
+}
+
+// PublishResult 发布结果
+type PublishResult struct {
+	Target     string `json:"target"`     // 发布目标名称
+	RemotePath string `json:"remotePath"` // 远程文件路径
+	Size       int64  `json:"size"`       // 文件大小（字节）
+}
@@ -0,0 +1,55 @@
+package models
+
+// RetentionConfig 文档保留策略
+// 天数为 0 表示不执行对应操作
+type RetentionConfig struct {
+	Enabled             bool                `json:"enabled"`             // 是否由维护任务每天自动执行
+	ArchiveAfterDays    int                 `json:"archiveAfterDays"`    // 超过天数未修改也未打开的文档自动归档
+	PurgeTrashAfterDays int                 `json:"purgeTrashAfterDays"` // 回收站中超过天数的文档永久删除
+	Overrides           []RetentionOverride `json:"overrides"`           // 按文档分组覆盖默认规则，先匹配的生效
+}
+
+// TrashConfig 回收站设置
+// 自动清理时天数作为默认规则，保留策略中匹配的分组规则优先，可以让部分文档更早或从不被清理
+type TrashConfig struct {
+	AutoPurgeDays int `json:"autoPurgeDays"` // 回收站中超过天数的文档自动永久删除，0 表示不自动清理
+}
+
+// RetentionOverride 一组文档的保留规则
+// 文档按类型、标题前缀和文件夹分组，设置多个条件时需同时满足
+// 天数为 0 表示沿用默认规则，-1 表示该组文档从不执行对应操作
+type RetentionOverride struct {
+	Name                string       `json:"name"`                // 分组名称，显示在报告中
+	Kind                DocumentKind `json:"kind"`                // 文档类型，为空时不限
+	TitlePrefix         string       `json:"titlePrefix"`         // 标题前缀（不区分大小写），为空时不限
+	CollectionID        *int64       `json:"collectionId"`        // 文件夹，包含其子文件夹，0 表示不在文件夹中的文档，为空时不限
+	ArchiveAfterDays    int          `json:"archiveAfterDays"`    // 覆盖自动归档天数
+	PurgeTrashAfterDays int          `json:"purgeTrashAfterDays"` // 覆盖回收站清理天数
+}
+
+// RetentionActionType 保留策略执行的操作
+type RetentionActionType string
+
+const (
+	RetentionArchive RetentionActionType = "archive" // 归档长期未使用的文档
+	RetentionPurge   RetentionActionType = "purge"   // 永久删除回收站中的文档
+)
+
+// RetentionAction 对单个文档执行的操作
+type RetentionAction struct {
+	DocumentID int64               `json:"documentId"`
+	Title      string              `json:"title"`
+	Action     RetentionActionType `json:"action"`
+	IdleDays   int                 `json:"idleDays"` // 未使用或在回收站中的天数
+	Rule       string              `json:"rule"`     // 生效的分组名称，默认规则为空
+	Error      string              `json:"error,omitempty"`
+}
+
+// RetentionReport 保留策略执行报告
+type RetentionReport struct {
+	DryRun      bool              `json:"dryRun"` // 是否只预览，未实际执行
+	GeneratedAt string            `json:"generatedAt"`
+	Actions     []RetentionAction `json:"actions"`
+	Archived    int               `json:"archived"` // 已归档的文档数
+	Purged      int               `json:"purged"`   // 已永久删除的文档数
+}
@@ -0,0 +1,29 @@
+package models
+
+import "voidraft/internal/common/excerpt"
+
+// SearchResult 全文搜索结果，每个结果对应文档中的一个块
+type SearchResult struct {
+	DocumentID      int64           `json:"documentId"`
+	Title           string          `json:"title"`
+	TitleHighlights []excerpt.Range `json:"titleHighlights"` // 标题中的匹配区间
+	BlockIndex      int             `json:"blockIndex"`      // 块序号，从0开始
+	Language        string          `json:"language"`        // 块语言，用于预览的语法高亮
+	TextLanguage    string          `json:"textLanguage"`    // 文本块识别出的自然语言，如 en、zh，无法识别时为空
+	Excerpt         string          `json:"excerpt"`         // 匹配位置附近的上下文摘录
+	Highlights      []excerpt.Range `json:"highlights"`      // 摘录中的匹配区间
+	Score           float64         `json:"score"`           // 相关度，越大越相关
+	Matches         int             `json:"matches"`         // 按文档搜索时文档中匹配的块数，结果为最相关的块
+}
+
+// SearchPage 按文档分组的搜索结果页
+type SearchPage struct {
+	Items []*SearchResult `json:"items"`
+	Total int             `json:"total"` // 匹配的文档总数
+}
+
+// BlockLanguage 文本块识别出的自然语言
+type BlockLanguage struct {
+	BlockIndex int    `json:"blockIndex"` // 块序号，从0开始
+	Language   string `json:"language"`   // ISO 639-1 代码，如 en、zh
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// SessionState 上一次运行的会话状态，保存在数据目录的 session.json 中
+type SessionState struct {
+	CleanExit bool      `json:"cleanExit"` // 是否正常退出
+	StartedAt time.Time `json:"startedAt"` // 启动时间
+	UpdatedAt time.Time `json:"updatedAt"` // 最后一次记录时间
+	Windows   []int64   `json:"windows"`   // 打开的文档窗口
+}
+
+// JournalEntry 自动保存日志中尚未写入数据库的文档内容
+type JournalEntry struct {
+	DocumentID int64     `json:"documentId"` // 文档ID
+	Title      string    `json:"title"`      // 文档标题
+	Content    string    `json:"content"`    // 未保存的内容
+	UpdatedAt  time.Time `json:"updatedAt"`  // 记录时间
+}
+
+// SessionRecovery 上次异常退出后可恢复的会话
+type SessionRecovery struct {
+	CrashedAt time.Time      `json:"crashedAt"` // 上次会话最后记录的时间
+	Windows   []int64        `json:"windows"`   // 可恢复的文档窗口
+	Journal   []JournalEntry `json:"journal"`   // 可恢复的未保存内容
+}
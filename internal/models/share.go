@@ -0,0 +1,12 @@
+package models
+
+// ShareLink 文档只读分享链接
+type ShareLink struct {
+	Token      string `json:"token"`      // 访问令牌
+	DocumentID int64  `json:"documentId"` // 分享的文档ID
+	Title      string `json:"title"`      // 文档标题
+	URL        string `json:"url"`        // 访问地址
+	CreatedAt  string `json:"createdAt"`  // 创建时间
+	ExpiresAt  string `json:"expiresAt"`  // 过期时间
+	Views      int    `json:"views"`      // 访问次数
+}
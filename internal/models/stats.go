@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// DocumentStats 单个文档的文本统计
+type DocumentStats struct {
+	DocumentID         int64  `json:"documentId"`
+	Title              string `json:"title"`
+	Words              int    `json:"words"`              // 单词数，中日韩文字每个字计为一个词
+	Characters         int    `json:"characters"`         // 字符数，不含换行
+	CharactersNoSpaces int    `json:"charactersNoSpaces"` // 字符数，不含空白
+	Lines              int    `json:"lines"`
+	Blocks             int    `json:"blocks"`
+	CodeBlocks         int    `json:"codeBlocks"` // 代码块，含 Markdown 中的围栏代码块
+	UpdatedAt          string `json:"updatedAt"`  // 最后编辑时间
+}
+
+// StatsPeriod 某一天或某一周新建的文档数
+type StatsPeriod struct {
+	Start   string `json:"start"` // 周期开始日期 YYYY-MM-DD，按周统计时为周一
+	Created int    `json:"created"`
+}
+
+// StatsOverview 全部文档的汇总统计，不含回收站和已归档的文档
+type StatsOverview struct {
+	Notes          int           `json:"notes"`
+	Words          int           `json:"words"`
+	Characters     int           `json:"characters"`
+	Lines          int           `json:"lines"`
+	CodeBlocks     int           `json:"codeBlocks"`
+	CreatedPerDay  []StatsPeriod `json:"createdPerDay"`  // 最近若干天，按日期从旧到新
+	CreatedPerWeek []StatsPeriod `json:"createdPerWeek"` // 最近若干周，按日期从旧到新
+	GeneratedAt    time.Time     `json:"generatedAt"`
+}
+
+// CodeLanguageShare 代码统计中一种语言所占的行数
+type CodeLanguageShare struct {
+	Language string  `json:"language"`
+	Lines    int     `json:"lines"`
+	Share    float64 `json:"share"` // 占总行数的比例，0 到 1
+}
+
+// CodeStats 文档或块的代码统计，用于粗看粘贴进来的代码和差异
+type CodeStats struct {
+	DocumentID   int64               `json:"documentId"`
+	BlockIndex   int                 `json:"blockIndex"` // 块序号，统计整个文档时为 -1
+	Lines        int                 `json:"lines"`
+	CodeLines    int                 `json:"codeLines"`    // 代码行，含行尾带注释的行
+	CommentLines int                 `json:"commentLines"` // 只有注释的行
+	BlankLines   int                 `json:"blankLines"`   // 代码中的空行
+	ProseLines   int                 `json:"proseLines"`   // 文本和 Markdown 的正文行
+	CommentRatio float64             `json:"commentRatio"` // 注释行占代码和注释行的比例
+	Languages    []CodeLanguageShare `json:"languages"`    // 按行数从多到少
+}
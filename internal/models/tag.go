@@ -0,0 +1,9 @@
+package models
+
+// Tag 文档标签，与文档多对多关联
+type Tag struct {
+	ID        int64  `json:"id" db:"id"`
+	Name      string `json:"name" db:"name"` // 标签名，不区分大小写唯一
+	CreatedAt string `json:"createdAt" db:"created_at"`
+	Count     int    `json:"count"` // 使用该标签的文档数，不含回收站中的文档
+}
@@ -62,6 +62,62 @@ func (tc *ThemeColorConfig) Scan(value interface{}) error {
 	return nil
 }
 
+// ThemeTokenGroupDefault 适用于所有语言的语法颜色分组
+const ThemeTokenGroupDefault = "default"
+
+// ThemeTokenColors 语法标签到颜色的映射，键与前端 ThemeColors 中的语法标签一致
+type ThemeTokenColors map[string]string
+
+// ThemeTokens 按语言分组的语法颜色，default 分组适用于所有语言，其他分组（如 markdown、python）覆盖 default
+type ThemeTokens map[string]ThemeTokenColors
+
+// Value 实现 driver.Valuer 接口
+func (tt ThemeTokens) Value() (driver.Value, error) {
+	if tt == nil {
+		return json.Marshal(map[string]interface{}{})
+	}
+	return json.Marshal(tt)
+}
+
+// Scan 实现 sql.Scanner 接口，空值解析为空分组
+func (tt *ThemeTokens) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*tt = ThemeTokens{}
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ThemeTokens", value)
+	}
+
+	data := ThemeTokens{}
+	if len(bytes) > 0 {
+		if err := json.Unmarshal(bytes, &data); err != nil {
+			return err
+		}
+	}
+	*tt = data
+	return nil
+}
+
+// Resolve 返回指定语言分组生效的语法颜色
+func (tt ThemeTokens) Resolve(group string) ThemeTokenColors {
+	colors := ThemeTokenColors{}
+	for tag, color := range tt[ThemeTokenGroupDefault] {
+		colors[tag] = color
+	}
+	if group != ThemeTokenGroupDefault {
+		for tag, color := range tt[group] {
+			colors[tag] = color
+		}
+	}
+	return colors
+}
+
 // Theme 主题配置结构体，用于定义系统的主题样式
 // 包含主题的基本信息、类型、颜色配置以及默认状态等属性
 type Theme struct {
@@ -69,6 +125,7 @@ type Theme struct {
 	Name      string           `db:"name" json:"name"`            // 主题名称
 	Type      ThemeType        `db:"type" json:"type"`            // 主题类型
 	Colors    ThemeColorConfig `db:"colors" json:"colors"`        // 主题颜色配置
+	Tokens    ThemeTokens      `db:"tokens" json:"tokens"`        // 按语言分组的语法颜色
 	IsDefault bool             `db:"is_default" json:"isDefault"` // 是否为默认主题
 	CreatedAt string           `db:"created_at" json:"createdAt"` // 创建时间
 	UpdatedAt string           `db:"updated_at" json:"updatedAt"` // 更新时间
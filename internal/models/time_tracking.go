@@ -0,0 +1,25 @@
+package models
+
+// TimeEntry 文档计时记录
+type TimeEntry struct {
+	ID         int64  `json:"id" db:"id"`
+	DocumentID int64  `json:"documentId" db:"document_id"`
+	StartedAt  string `json:"startedAt" db:"started_at"`
+	EndedAt    string `json:"endedAt" db:"ended_at"`  // 为空表示计时进行中
+	Duration   int64  `json:"duration" db:"duration"` // 持续时间（秒）
+	Note       string `json:"note" db:"note"`
+}
+
+// IsRunning 计时是否进行中
+func (e *TimeEntry) IsRunning() bool {
+	return e.EndedAt == ""
+}
+
+// TimeSummary 计时汇总
+type TimeSummary struct {
+	DocumentID int64  `json:"documentId"`
+	Title      string `json:"title"`
+	Date       string `json:"date,omitempty"` // 日期（YYYY-MM-DD），按文档汇总时为空
+	Duration   int64  `json:"duration"`       // 累计时长（秒）
+	EntryCount int    `json:"entryCount"`     // 计时记录条数
+}
@@ -0,0 +1,18 @@
+package models
+
+// TOTPAccount 一次性密码账户，密钥保存在系统钥匙串中，这里只保存显示信息和参数
+type TOTPAccount struct {
+	Name      string `json:"name"`      // 唯一名称
+	Issuer    string `json:"issuer"`    // 发行方
+	Account   string `json:"account"`   // 账户
+	Algorithm string `json:"algorithm"` // SHA1、SHA256 或 SHA512
+	Digits    int    `json:"digits"`    // 密码位数
+	Period    int    `json:"period"`    // 有效期（秒）
+}
+
+// TOTPCode 当前的一次性密码
+type TOTPCode struct {
+	Code      string `json:"code"`
+	Remaining int    `json:"remaining"` // 剩余有效秒数
+	Period    int    `json:"period"`
+}
@@ -0,0 +1,45 @@
+package models
+
+// TranslateSelectionMode 选区翻译结果的写入方式
+type TranslateSelectionMode string
+
+const (
+	TranslateModeReplace    TranslateSelectionMode = "replace"      // 用译文替换选区
+	TranslateModeAppend     TranslateSelectionMode = "append"       // 在选区后另起一行追加译文
+	TranslateModeSideBySide TranslateSelectionMode = "side-by-side" // 原文与译文逐行对照
+)
+
+// IsValid 写入方式是否有效
+func (m TranslateSelectionMode) IsValid() bool {
+	switch m {
+	case TranslateModeReplace, TranslateModeAppend, TranslateModeSideBySide:
+		return true
+	}
+	return false
+}
+
+// TranslationTargetRule 按源语言选择目标语言的规则，如 zh → en
+type TranslationTargetRule struct {
+	Source string `json:"source"` // 检测到的源语言，如 zh、de；只写主语言时匹配全部地区变体
+	Target string `json:"target"` // 目标语言
+}
+
+// TranslationConfig 翻译设置
+type TranslationConfig struct {
+	DefaultTranslator string                  `json:"defaultTranslator"` // 默认翻译器
+	DefaultTarget     string                  `json:"defaultTarget"`     // 没有匹配规则时的目标语言
+	TargetRules       []TranslationTargetRule `json:"targetRules"`       // 源语言到目标语言的映射，按顺序匹配
+
+	// 快速翻译剪贴板
+	EnableQuickTranslateHotkey bool        `json:"enableQuickTranslateHotkey"` // 是否启用快速翻译全局热键
+	QuickTranslateHotkey       HotkeyCombo `json:"quickTranslateHotkey"`       // 快速翻译全局热键组合
+}
+
+// QuickTranslateResult 剪贴板快速翻译结果
+type QuickTranslateResult struct {
+	Text        string `json:"text"`        // 原文
+	Translation string `json:"translation"` // 译文
+	From        string `json:"from"`        // 推测的源语言
+	To          string `json:"to"`          // 目标语言
+	Translator  string `json:"translator"`  // 使用的翻译器
+}
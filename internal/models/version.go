@@ -0,0 +1,19 @@
+package models
+
+// SnapshotConfig 文档自动快照设置
+// 快照按保留策略清理：一天内每小时保留一个，一个月内每天保留一个
+type SnapshotConfig struct {
+	Enabled         bool `json:"enabled"`         // 是否定期为修改过的文档保存快照
+	IntervalMinutes int  `json:"intervalMinutes"` // 快照间隔（分钟）
+}
+
+// DocumentVersion 文档的历史版本
+type DocumentVersion struct {
+	ID          int64  `json:"id" db:"id"`
+	DocumentID  int64  `json:"documentId" db:"document_id"`
+	Title       string `json:"title" db:"title"`
+	Content     string `json:"content,omitempty" db:"content"` // 列表中不返回内容
+	ContentSize int    `json:"contentSize" db:"content_size"`
+	CreatedAt   string `json:"createdAt" db:"created_at"`
+	Pinned      bool   `json:"pinned" db:"pinned"` // 合并、拆分和恢复时保存的快照，不按区间清理
+}
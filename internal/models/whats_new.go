@@ -0,0 +1,11 @@
+package models
+
+import "voidraft/internal/common/changelog"
+
+// WhatsNew 升级后尚未查看的更新说明
+type WhatsNew struct {
+	PreviousVersion string            `json:"previousVersion"` // 上次运行的版本
+	CurrentVersion  string            `json:"currentVersion"`  // 当前版本
+	Entries         []changelog.Entry `json:"entries"`         // 两个版本之间的更新说明，从新到旧
+	Offline         bool              `json:"offline"`         // 未能获取发布说明，仅包含版本信息
+}
@@ -17,6 +17,106 @@ const (
 	SnapEdgeTopLeft                     // 吸附到左上角
 )
 
+// String 边缘名称，供前端显示吸附方向
+func (e SnapEdge) String() string {
+	switch e {
+	case SnapEdgeTop:
+		return "top"
+	case SnapEdgeRight:
+		return "right"
+	case SnapEdgeBottom:
+		return "bottom"
+	case SnapEdgeLeft:
+		return "left"
+	case SnapEdgeTopRight:
+		return "topRight"
+	case SnapEdgeBottomRight:
+		return "bottomRight"
+	case SnapEdgeBottomLeft:
+		return "bottomLeft"
+	case SnapEdgeTopLeft:
+		return "topLeft"
+	}
+	return "none"
+}
+
+// WindowMode 文档窗口的显示方式，前端通过 URL 参数 mode 区分
+type WindowMode string
+
+const (
+	WindowModeDocument WindowMode = "document" // 独立的文档窗口
+	WindowModeMenuBar  WindowMode = "menubar"  // 固定在 macOS 菜单栏的弹出窗口
+)
+
+// WindowKind 窗口类型，不同类型的窗口可以分别设置标题栏按钮行为
+type WindowKind string
+
+const (
+	WindowKindMain     WindowKind = "main"     // 主窗口
+	WindowKindDocument WindowKind = "document" // 独立的文档窗口
+)
+
+// WindowButton 标题栏按钮
+type WindowButton string
+
+const (
+	WindowButtonClose    WindowButton = "close"    // 关闭按钮
+	WindowButtonMinimize WindowButton = "minimize" // 最小化按钮
+)
+
+// IsValid 按钮是否有效
+func (b WindowButton) IsValid() bool {
+	return b == WindowButtonClose || b == WindowButtonMinimize
+}
+
+// WindowButtonAction 点击标题栏按钮时执行的操作
+type WindowButtonAction string
+
+const (
+	WindowActionTray     WindowButtonAction = "tray"     // 隐藏到托盘，未启用托盘时主窗口退出应用、文档窗口关闭
+	WindowActionMinimize WindowButtonAction = "minimize" // 最小化
+	WindowActionClose    WindowButtonAction = "close"    // 关闭窗口，主窗口关闭即退出应用
+	WindowActionQuit     WindowButtonAction = "quit"     // 退出应用
+	WindowActionAsk      WindowButtonAction = "ask"      // 首次点击时询问，之后记住选择
+)
+
+// IsValid 操作是否有效
+func (a WindowButtonAction) IsValid() bool {
+	switch a {
+	case WindowActionTray, WindowActionMinimize, WindowActionClose, WindowActionQuit, WindowActionAsk:
+		return true
+	}
+	return false
+}
+
+// WindowButtonBehavior 一类窗口的标题栏按钮行为
+type WindowButtonBehavior struct {
+	Close    WindowButtonAction `json:"close"`    // 关闭按钮
+	Minimize WindowButtonAction `json:"minimize"` // 最小化按钮
+}
+
+// Action 获取按钮对应的操作
+func (b WindowButtonBehavior) Action(button WindowButton) WindowButtonAction {
+	if button == WindowButtonMinimize {
+		return b.Minimize
+	}
+	return b.Close
+}
+
+// WindowButtonsConfig 主窗口和文档窗口的标题栏按钮行为
+type WindowButtonsConfig struct {
+	Main     WindowButtonBehavior `json:"main"`     // 主窗口
+	Document WindowButtonBehavior `json:"document"` // 文档窗口
+}
+
+// Behavior 获取指定类型窗口的按钮行为
+func (c WindowButtonsConfig) Behavior(kind WindowKind) WindowButtonBehavior {
+	if kind == WindowKindDocument {
+		return c.Document
+	}
+	return c.Main
+}
+
 // WindowPosition 窗口位置
 type WindowPosition struct {
 	X int `json:"x"` // X坐标
@@ -31,10 +131,98 @@ type SnapPosition struct {
 
 // WindowInfo 窗口信息
 type WindowInfo struct {
-	DocumentID int64          `json:"documentID"` // 文档ID
+	WindowID   int64          `json:"windowID"`   // 窗口ID，一个窗口可包含多个文档标签
 	IsSnapped  bool           `json:"isSnapped"`  // 是否处于吸附状态
-	SnapOffset SnapPosition   `json:"snapOffset"` // 与主窗口的相对位置偏移
+	SnapTarget int64          `json:"snapTarget"` // 吸附目标窗口ID，0 表示主窗口
+	SnapOffset SnapPosition   `json:"snapOffset"` // 与吸附目标的相对位置偏移
 	SnapEdge   SnapEdge       `json:"snapEdge"`   // 吸附的边缘类型
 	LastPos    WindowPosition `json:"lastPos"`    // 上一次记录的窗口位置
 	MoveTime   time.Time      `json:"moveTime"`   // 上次移动时间，用于判断移动速度
+
+	ResizeTime    time.Time `json:"resizeTime"`    // 上次尺寸变化时间，与移动几乎同时发生时视为系统排列窗口
+	ArrangedUntil time.Time `json:"arrangedUntil"` // 系统排列窗口后暂停吸附的截止时间
+	Released      bool      `json:"released"`      // 用户手动解除吸附，移出吸附范围前不再自动吸附
+}
+
+// WindowSnapState 文档窗口的吸附状态，前端据此在标题栏显示吸附标记和解除吸附按钮
+type WindowSnapState struct {
+	WindowID  int64  `json:"windowId"`  // 文档窗口ID
+	IsSnapped bool   `json:"isSnapped"` // 是否处于吸附状态
+	Edge      string `json:"edge"`      // 吸附在目标的哪条边或哪个角，如 right、topLeft，未吸附时为 none
+	Target    int64  `json:"target"`    // 吸附目标窗口ID，0 表示主窗口
+}
+
+// WindowSnapDragMode 拖动已吸附的子窗口时的行为
+type WindowSnapDragMode string
+
+const (
+	// WindowSnapDragDetach 拖开子窗口即解除吸附，主窗口不动
+	WindowSnapDragDetach WindowSnapDragMode = "detach"
+	// WindowSnapDragCluster 拖动任一已吸附的窗口时整组一起移动，主窗口跟随子窗口
+	WindowSnapDragCluster WindowSnapDragMode = "cluster"
+)
+
+// NewWindowSnapEdge 新建文档窗口自动吸附到主窗口的哪一侧
+type NewWindowSnapEdge string
+
+const (
+	NewWindowSnapRight  NewWindowSnapEdge = "right"  // 主窗口右侧
+	NewWindowSnapLeft   NewWindowSnapEdge = "left"   // 主窗口左侧
+	NewWindowSnapBottom NewWindowSnapEdge = "bottom" // 主窗口下方
+	NewWindowSnapTop    NewWindowSnapEdge = "top"    // 主窗口上方
+)
+
+// SnapEdge 对应的吸附边缘，无效值按右侧处理
+func (e NewWindowSnapEdge) SnapEdge() SnapEdge {
+	switch e {
+	case NewWindowSnapLeft:
+		return SnapEdgeLeft
+	case NewWindowSnapBottom:
+		return SnapEdgeBottom
+	case NewWindowSnapTop:
+		return SnapEdgeTop
+	}
+	return SnapEdgeRight
+}
+
+// TilingFeature 会自行排列窗口的系统功能
+type TilingFeature string
+
+const (
+	TilingFeatureSnapLayouts  TilingFeature = "snapLayouts"  // Windows 贴靠布局
+	TilingFeatureFancyZones   TilingFeature = "fancyZones"   // PowerToys FancyZones
+	TilingFeatureStageManager TilingFeature = "stageManager" // macOS 台前调度
+	TilingFeatureTilingWM     TilingFeature = "tilingWM"     // Linux 平铺式窗口管理器
+)
+
+// WindowSnapCompatConfig 窗口吸附与系统窗口排列功能的兼容开关
+// 开启时检测到对应功能后，由系统排列的窗口不再被吸附调整，避免与系统争夺窗口位置
+type WindowSnapCompatConfig struct {
+	SnapLayouts  bool `json:"snapLayouts"`  // Windows 贴靠布局
+	FancyZones   bool `json:"fancyZones"`   // PowerToys FancyZones
+	StageManager bool `json:"stageManager"` // macOS 台前调度
+	TilingWM     bool `json:"tilingWM"`     // Linux 平铺式窗口管理器，检测到时完全停用吸附
+}
+
+// Enabled 某项功能的兼容开关是否开启
+func (c WindowSnapCompatConfig) Enabled(feature TilingFeature) bool {
+	switch feature {
+	case TilingFeatureSnapLayouts:
+		return c.SnapLayouts
+	case TilingFeatureFancyZones:
+		return c.FancyZones
+	case TilingFeatureStageManager:
+		return c.StageManager
+	case TilingFeatureTilingWM:
+		return c.TilingWM
+	}
+	return false
+}
+
+// TilingStatus 当前系统上某项窗口排列功能的检测结果
+type TilingStatus struct {
+	Feature  TilingFeature `json:"feature"`  // 功能
+	Detected bool          `json:"detected"` // 是否检测到功能已开启或正在运行
+	Name     string        `json:"name"`     // 检测到的具体名称，如平铺式窗口管理器 sway
+	Compat   bool          `json:"compat"`   // 兼容开关是否开启
 }
@@ -0,0 +1,34 @@
+package models
+
+// FollowMode 主窗口与文档窗口同时打开同一文档时的协作方式
+type FollowMode string
+
+const (
+	// FollowModeMirror 主窗口编辑，文档窗口只读跟随
+	FollowModeMirror FollowMode = "mirror"
+	// FollowModeTakeover 文档窗口编辑，主窗口只读跟随
+	FollowModeTakeover FollowMode = "takeover"
+)
+
+// FollowView 打开文档的视图
+type FollowView string
+
+const (
+	FollowViewMain   FollowView = "main"   // 主窗口
+	FollowViewWindow FollowView = "window" // 独立文档窗口
+)
+
+// FollowState 文档的跟随状态
+type FollowState struct {
+	DocumentID int64      `json:"documentId"` // 文档ID
+	Duplicated bool       `json:"duplicated"` // 是否同时在主窗口和文档窗口中打开
+	Mode       FollowMode `json:"mode"`       // 跟随模式，未重复打开时为空
+	Editor     FollowView `json:"editor"`     // 可编辑的视图，另一视图只读，未重复打开时为空
+}
+
+// FollowChanges 可编辑视图发出的编辑，只读视图据此同步
+type FollowChanges struct {
+	DocumentID int64      `json:"documentId"` // 文档ID
+	Source     FollowView `json:"source"`     // 发出编辑的视图
+	Changes    string     `json:"changes"`    // 编辑器变更集或完整内容的 JSON
+}
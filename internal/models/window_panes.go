@@ -0,0 +1,25 @@
+package models
+
+// PaneSide 主窗口分屏中的位置
+type PaneSide string
+
+const (
+	PaneSideLeft  PaneSide = "left"  // 左侧（未分屏时唯一的编辑区）
+	PaneSideRight PaneSide = "right" // 右侧的第二个文档
+)
+
+// MainPane 主窗口中的一个编辑区
+type MainPane struct {
+	Side       PaneSide `json:"side"`       // 位置
+	DocumentID int64    `json:"documentId"` // 打开的文档ID，0 表示未打开文档
+	Title      string   `json:"title"`      // 文档标题
+	Dirty      bool     `json:"dirty"`      // 是否有未保存的修改
+}
+
+// MainWindowLayout 主窗口的分屏布局
+type MainWindowLayout struct {
+	Split  bool       `json:"split"`  // 是否分屏显示两个文档
+	Active PaneSide   `json:"active"` // 当前获得焦点的编辑区
+	Panes  []MainPane `json:"panes"`  // 编辑区，左侧在前
+	Title  string     `json:"title"`  // 由各编辑区组合的窗口标题
+}
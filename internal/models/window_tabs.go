@@ -0,0 +1,8 @@
+package models
+
+// DocumentWindow 文档窗口及其中以标签打开的文档
+type DocumentWindow struct {
+	WindowID  int64   `json:"windowId"`  // 窗口ID
+	Documents []int64 `json:"documents"` // 按标签顺序排列的文档ID
+	Active    int64   `json:"active"`    // 当前显示的文档ID
+}
@@ -0,0 +1,28 @@
+//go:build darwin
+
+package services
+
+import (
+	"os/exec"
+	"strings"
+	"voidraft/internal/models"
+)
+
+// detectAccessibility 读取 com.apple.universalaccess 中的辅助功能设置
+func detectAccessibility() models.AccessibilityPreferences {
+	return models.AccessibilityPreferences{
+		ReducedMotion: readDefaultsBool("com.apple.universalaccess", "reduceMotion"),
+		HighContrast:  readDefaultsBool("com.apple.universalaccess", "increaseContrast"),
+		ScreenReader:  readDefaultsBool("com.apple.universalaccess", "voiceOverOnOffKey"),
+	}
+}
+
+// readDefaultsBool 读取 defaults 中的布尔值，键不存在时视为 false
+func readDefaultsBool(domain, key string) bool {
+	out, err := exec.Command("defaults", "read", domain, key).Output()
+	if err != nil {
+		return false
+	}
+	value := strings.TrimSpace(string(out))
+	return value == "1" || strings.EqualFold(value, "true")
+}
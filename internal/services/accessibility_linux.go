@@ -0,0 +1,36 @@
+//go:build linux
+
+package services
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"voidraft/internal/models"
+)
+
+// detectAccessibility 通过 gsettings 读取 GNOME 辅助功能设置，其他桌面环境只检测 GTK 主题名称
+func detectAccessibility() models.AccessibilityPreferences {
+	prefs := models.AccessibilityPreferences{
+		ReducedMotion: readGSetting("org.gnome.desktop.interface", "enable-animations") == "false",
+		HighContrast:  readGSetting("org.gnome.desktop.a11y.interface", "high-contrast") == "true",
+		ScreenReader:  readGSetting("org.gnome.desktop.a11y.applications", "screen-reader-enabled") == "true",
+	}
+	if !prefs.HighContrast {
+		theme := os.Getenv("GTK_THEME")
+		if theme == "" {
+			theme = strings.Trim(readGSetting("org.gnome.desktop.interface", "gtk-theme"), "'")
+		}
+		prefs.HighContrast = strings.Contains(strings.ToLower(theme), "highcontrast")
+	}
+	return prefs
+}
+
+// readGSetting 读取 gsettings 键值，gsettings 不可用或键不存在时返回空字符串
+func readGSetting(schema, key string) string {
+	out, err := exec.Command("gsettings", "get", schema, key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
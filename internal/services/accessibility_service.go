@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// accessibilityPollInterval 系统辅助功能设置的轮询间隔，各平台没有统一的变更通知
+const accessibilityPollInterval = 30 * time.Second
+
+// AccessibilityService 辅助功能偏好服务
+// 检测系统的减少动态效果、高对比度和屏幕阅读器设置，与应用配置的覆盖项合并后提供给前端和其他服务
+type AccessibilityService struct {
+	logger        *log.LogService
+	configService *ConfigService
+
+	mu     sync.RWMutex
+	system models.AccessibilityPreferences
+	state  models.AccessibilityState
+
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+	cancelObservers []CancelFunc
+}
+
+// NewAccessibilityService 创建辅助功能偏好服务实例
+func NewAccessibilityService(configService *ConfigService, logger *log.LogService) *AccessibilityService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &AccessibilityService{
+		logger:        logger,
+		configService: configService,
+	}
+}
+
+// ServiceStartup 检测系统设置并开始轮询
+func (as *AccessibilityService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	as.system = detectAccessibility()
+	as.state = as.resolve(as.system)
+	as.logger.Info("Accessibility preferences detected", "system", as.system, "effective", as.state.Effective)
+
+	onConfigChange := func(oldValue, newValue interface{}) { as.refresh(false) }
+	as.cancelObservers = []CancelFunc{
+		as.configService.Watch("accessibility.reducedMotion", onConfigChange),
+		as.configService.Watch("accessibility.highContrast", onConfigChange),
+		as.configService.Watch("accessibility.screenReader", onConfigChange),
+	}
+
+	pollCtx, cancel := context.WithCancel(context.Background())
+	as.cancel = cancel
+	as.wg.Add(1)
+	go func() {
+		defer as.wg.Done()
+		ticker := time.NewTicker(accessibilityPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-pollCtx.Done():
+				return
+			case <-ticker.C:
+				as.refresh(true)
+			}
+		}
+	}()
+	return nil
+}
+
+// GetAccessibilityPreferences 获取系统检测结果和最终生效的辅助功能偏好
+func (as *AccessibilityService) GetAccessibilityPreferences() models.AccessibilityState {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+	return as.state
+}
+
+// RefreshAccessibilityPreferences 立即重新检测系统设置
+func (as *AccessibilityService) RefreshAccessibilityPreferences() models.AccessibilityState {
+	as.refresh(true)
+	return as.GetAccessibilityPreferences()
+}
+
+// refresh 重新计算偏好，最终结果变化时通知前端
+func (as *AccessibilityService) refresh(detect bool) {
+	as.mu.Lock()
+	if detect {
+		as.system = detectAccessibility()
+	}
+	state := as.resolve(as.system)
+	changed := state != as.state
+	as.state = state
+	as.mu.Unlock()
+
+	if changed {
+		as.logger.Info("Accessibility preferences changed", "effective", state.Effective)
+		helper.EmitEvent(constant.EVENT_ACCESSIBILITY_CHANGED, state)
+	}
+}
+
+// resolve 用应用配置覆盖系统检测结果
+func (as *AccessibilityService) resolve(system models.AccessibilityPreferences) models.AccessibilityState {
+	overrides := models.NewDefaultAppConfig().Accessibility
+	if config, err := as.configService.GetConfig(); err == nil {
+		overrides = config.Accessibility
+	}
+	return models.AccessibilityState{
+		System: system,
+		Effective: models.AccessibilityPreferences{
+			ReducedMotion: overrides.ReducedMotion.Resolve(system.ReducedMotion),
+			HighContrast:  overrides.HighContrast.Resolve(system.HighContrast),
+			ScreenReader:  overrides.ScreenReader.Resolve(system.ScreenReader),
+		},
+	}
+}
+
+// ServiceShutdown 停止轮询和配置监听
+func (as *AccessibilityService) ServiceShutdown() error {
+	for _, cancel := range as.cancelObservers {
+		cancel()
+	}
+	if as.cancel != nil {
+		as.cancel()
+	}
+	as.wg.Wait()
+	return nil
+}
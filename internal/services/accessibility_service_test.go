@@ -0,0 +1,53 @@
+package services
+
+import (
+	"testing"
+	"voidraft/internal/models"
+)
+
+// TestAccessibilityResolve 应用配置覆盖系统检测结果，auto 跟随系统
+func TestAccessibilityResolve(t *testing.T) {
+	config := models.NewDefaultAppConfig()
+	config.Accessibility = models.AccessibilityConfig{
+		ReducedMotion: models.AccessibilityOn,
+		HighContrast:  models.AccessibilityOff,
+		ScreenReader:  models.AccessibilityAuto,
+	}
+	as := NewAccessibilityService(newTestConfigService(t, config), nil)
+
+	for _, system := range []models.AccessibilityPreferences{
+		{},
+		{ReducedMotion: true, HighContrast: true, ScreenReader: true},
+	} {
+		state := as.resolve(system)
+		if state.System != system {
+			t.Errorf("System = %+v, want %+v", state.System, system)
+		}
+		want := models.AccessibilityPreferences{ReducedMotion: true, HighContrast: false, ScreenReader: system.ScreenReader}
+		if state.Effective != want {
+			t.Errorf("system %+v: Effective = %+v, want %+v", system, state.Effective, want)
+		}
+	}
+}
+
+// TestAccessibilityRefreshOnConfigChange 修改覆盖项后不重新检测系统也能得到新的结果
+func TestAccessibilityRefreshOnConfigChange(t *testing.T) {
+	configService := newTestConfigService(t, models.NewDefaultAppConfig())
+	as := NewAccessibilityService(configService, nil)
+	as.system = models.AccessibilityPreferences{HighContrast: true}
+	as.refresh(false)
+	if got := as.GetAccessibilityPreferences().Effective; !got.HighContrast || got.ReducedMotion {
+		t.Fatalf("default Effective = %+v, want system values", got)
+	}
+
+	configService.koanf.Set("accessibility.highContrast", string(models.AccessibilityOff))
+	configService.koanf.Set("accessibility.reducedMotion", string(models.AccessibilityOn))
+	as.refresh(false)
+	state := as.GetAccessibilityPreferences()
+	if state.Effective.HighContrast || !state.Effective.ReducedMotion {
+		t.Errorf("overridden Effective = %+v", state.Effective)
+	}
+	if !state.System.HighContrast {
+		t.Errorf("System = %+v, detection result should be kept", state.System)
+	}
+}
@@ -0,0 +1,56 @@
+//go:build windows
+
+package services
+
+import (
+	"syscall"
+	"unsafe"
+	"voidraft/internal/models"
+)
+
+const (
+	spiGetHighContrast        = 0x0042
+	spiGetScreenReader        = 0x0046
+	spiGetClientAreaAnimation = 0x1042
+	hcfHighContrastOn         = 0x0001
+)
+
+// highContrastInfo 对应 Win32 HIGHCONTRASTW
+type highContrastInfo struct {
+	size          uint32
+	flags         uint32
+	defaultScheme *uint16
+}
+
+var procSystemParametersInfo = syscall.NewLazyDLL("user32.dll").NewProc("SystemParametersInfoW")
+
+// detectAccessibility 通过 SystemParametersInfo 读取系统辅助功能设置
+func detectAccessibility() models.AccessibilityPreferences {
+	var prefs models.AccessibilityPreferences
+
+	var animation int32
+	if systemParametersInfo(spiGetClientAreaAnimation, 0, unsafe.Pointer(&animation)) {
+		prefs.ReducedMotion = animation == 0
+	}
+
+	hc := highContrastInfo{}
+	hc.size = uint32(unsafe.Sizeof(hc))
+	if systemParametersInfo(spiGetHighContrast, hc.size, unsafe.Pointer(&hc)) {
+		prefs.HighContrast = hc.flags&hcfHighContrastOn != 0
+	}
+
+	var screenReader int32
+	if systemParametersInfo(spiGetScreenReader, 0, unsafe.Pointer(&screenReader)) {
+		prefs.ScreenReader = screenReader != 0
+	}
+	return prefs
+}
+
+// systemParametersInfo 调用 SystemParametersInfoW
+func systemParametersInfo(action, param uint32, value unsafe.Pointer) bool {
+	if procSystemParametersInfo.Find() != nil {
+		return false
+	}
+	ret, _, _ := procSystemParametersInfo.Call(uintptr(action), uintptr(param), uintptr(value), 0)
+	return ret != 0
+}
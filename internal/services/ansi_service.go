@@ -0,0 +1,54 @@
+package services
+
+import (
+	"fmt"
+	"voidraft/internal/common/ansi"
+	"voidraft/internal/common/blocks"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// AnsiService 终端输出导入服务，处理粘贴或通过 API 传入的带 ANSI 颜色的文本
+type AnsiService struct {
+	logger          *log.LogService
+	documentService *DocumentService
+}
+
+// NewAnsiService 创建终端输出导入服务实例
+func NewAnsiService(documentService *DocumentService, logger *log.LogService) *AnsiService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &AnsiService{
+		logger:          logger,
+		documentService: documentService,
+	}
+}
+
+// ContainsANSI 文本中是否包含 ANSI 转义序列
+func (as *AnsiService) ContainsANSI(text string) bool {
+	return ansi.Contains(text)
+}
+
+// StripANSI 去除转义序列得到纯文本
+func (as *AnsiService) StripANSI(text string) string {
+	return ansi.Strip(text)
+}
+
+// RenderANSI 将终端输出转换为保留颜色的 HTML 片段，用于预览
+func (as *AnsiService) RenderANSI(text string) string {
+	return ansi.ToHTML(text)
+}
+
+// ImportANSI 去除转义序列后作为文本块追加到文档末尾
+func (as *AnsiService) ImportANSI(documentID int64, text string) error {
+	doc, err := as.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil || doc.IsDeleted {
+		return fmt.Errorf("document not found: %d", documentID)
+	}
+	content := doc.Content + blocks.Delimiter(blocks.DefaultLanguage, false) + ansi.Strip(text)
+	return as.documentService.UpdateDocumentContent(documentID, content)
+}
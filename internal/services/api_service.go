@@ -0,0 +1,592 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"voidraft/internal/common/ansi"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+	"golang.org/x/time/rate"
+)
+
+// SQL 查询语句
+const (
+	sqlInsertApiToken = `
+INSERT INTO api_tokens (name, prefix, token_hash, scope, rate_limit, created_at, last_used_at)
+VALUES (?, ?, ?, ?, ?, ?, '')`
+
+	sqlGetApiTokenByHash = `
+SELECT id, name, prefix, token_hash, scope, rate_limit, created_at, last_used_at
+FROM api_tokens
+WHERE token_hash = ?`
+
+	sqlGetApiTokenByID = `
+SELECT id, name, prefix, token_hash, scope, rate_limit, created_at, last_used_at
+FROM api_tokens
+WHERE id = ?`
+
+	sqlListApiTokens = `
+SELECT id, name, prefix, token_hash, scope, rate_limit, created_at, last_used_at
+FROM api_tokens
+ORDER BY id DESC`
+
+	sqlDeleteApiToken = `
+DELETE FROM api_tokens WHERE id = ?`
+
+	sqlUpdateApiTokenRateLimit = `
+UPDATE api_tokens SET rate_limit = ? WHERE id = ?`
+
+	sqlTouchApiToken = `
+UPDATE api_tokens SET last_used_at = ? WHERE id = ?`
+
+	sqlInsertApiAudit = `
+INSERT INTO api_audit_log (token_id, token_name, method, path, status, remote_addr, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	sqlListApiAudit = `
+SELECT id, token_id, token_name, method, path, status, remote_addr, created_at
+FROM api_audit_log
+ORDER BY id DESC
+LIMIT ?`
+
+	sqlPruneApiAudit = `
+DELETE FROM api_audit_log WHERE id <= (SELECT MAX(id) FROM api_audit_log) - ?`
+
+	sqlClearApiAudit = `
+DELETE FROM api_audit_log`
+)
+
+const (
+	// apiTokenPrefix 令牌前缀，便于在脚本和日志中识别
+	apiTokenPrefix = "vdr_"
+	// apiMaxBodyBytes 请求体大小上限
+	apiMaxBodyBytes = 4 << 20
+	// apiAuditRetention 审计记录保留条数
+	apiAuditRetention = 10000
+	// apiTimeLayout API相关时间格式，与其他表保持一致
+	apiTimeLayout = "2006-01-02 15:04:05"
+)
+
+// apiLanguagePattern 追加块时允许的语言标记
+var apiLanguagePattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// errApiTokenNotFound 令牌不存在或已撤销
+var errApiTokenNotFound = errors.New("token not found")
+
+// apiTokenKey 请求上下文中保存令牌的键
+type apiTokenKey struct{}
+
+// apiLimiter 单个令牌的限流器
+type apiLimiter struct {
+	limit   int
+	limiter *rate.Limiter
+}
+
+// ApiService 本地HTTP API服务，提供基于令牌权限的文档读写
+type ApiService struct {
+	logger             *log.LogService
+	localServerService *LocalServerService
+	databaseService    *DatabaseService
+	documentService    *DocumentService
+
+	mu       sync.Mutex
+	limiters map[int64]*apiLimiter
+}
+
+// NewApiService 创建本地API服务
+func NewApiService(localServerService *LocalServerService, databaseService *DatabaseService, documentService *DocumentService, logger *log.LogService) *ApiService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	as := &ApiService{
+		logger:             logger,
+		localServerService: localServerService,
+		databaseService:    databaseService,
+		documentService:    documentService,
+		limiters:           make(map[int64]*apiLimiter),
+	}
+
+	localServerService.handle("GET /api/v1/documents", as.withAuth(models.ApiScopeReadOnly, as.handleListDocuments))
+	localServerService.handle("GET /api/v1/documents/{id}", as.withAuth(models.ApiScopeReadOnly, as.handleGetDocument))
+	localServerService.handle("POST /api/v1/documents/{id}/append", as.withAuth(models.ApiScopeAppendOnly, as.handleAppendDocument))
+	localServerService.handle("POST /api/v1/documents", as.withAuth(models.ApiScopeFull, as.handleCreateDocument))
+	localServerService.handle("PUT /api/v1/documents/{id}", as.withAuth(models.ApiScopeFull, as.handleUpdateDocument))
+	return as
+}
+
+// ServiceStartup 服务启动，清理过旧的审计记录
+func (as *ApiService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	if as.databaseService == nil || as.databaseService.getDB() == nil || as.databaseService.ensureWritable() != nil {
+		return nil
+	}
+	if _, err := as.databaseService.getDB().Exec(sqlPruneApiAudit, apiAuditRetention); err != nil {
+		as.logger.Error("Failed to prune api audit log", "error", err)
+	}
+	return nil
+}
+
+// CreateApiToken 创建访问令牌，明文令牌只在此时返回
+func (as *ApiService) CreateApiToken(name string, scope models.ApiTokenScope, rateLimit int) (*models.ApiTokenCreated, error) {
+	if as.databaseService == nil || as.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+	if err := as.databaseService.ensureWritable(); err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("token name is required")
+	}
+	if !scope.IsValid() {
+		return nil, fmt.Errorf("invalid token scope: %s", scope)
+	}
+	if rateLimit < 0 {
+		return nil, errors.New("rate limit must not be negative")
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := apiTokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	info := &models.ApiToken{
+		Name:      name,
+		Prefix:    token[:len(apiTokenPrefix)+6],
+		TokenHash: hashApiToken(token),
+		Scope:     scope,
+		RateLimit: rateLimit,
+		CreatedAt: time.Now().Format(apiTimeLayout),
+	}
+
+	result, err := as.databaseService.getDB().Exec(sqlInsertApiToken,
+		info.Name, info.Prefix, info.TokenHash, string(info.Scope), info.RateLimit, info.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api token: %w", err)
+	}
+	if info.ID, err = result.LastInsertId(); err != nil {
+		return nil, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	as.logger.Info("Api token created", "id", info.ID, "name", info.Name, "scope", info.Scope)
+	return &models.ApiTokenCreated{Token: token, Info: info}, nil
+}
+
+// ListApiTokens 列出全部访问令牌
+func (as *ApiService) ListApiTokens() ([]*models.ApiToken, error) {
+	if as.databaseService == nil || as.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	rows, err := as.databaseService.getDB().Query(sqlListApiTokens)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*models.ApiToken
+	for rows.Next() {
+		token, err := scanApiToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+// RevokeApiToken 撤销访问令牌
+func (as *ApiService) RevokeApiToken(id int64) error {
+	if as.databaseService == nil || as.databaseService.getDB() == nil {
+		return errors.New("database service not available")
+	}
+	if err := as.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	if _, err := as.databaseService.getDB().Exec(sqlDeleteApiToken, id); err != nil {
+		return fmt.Errorf("failed to revoke api token: %w", err)
+	}
+
+	as.mu.Lock()
+	delete(as.limiters, id)
+	as.mu.Unlock()
+	return nil
+}
+
+// UpdateApiTokenRateLimit 修改令牌的每分钟请求上限，0表示不限制
+func (as *ApiService) UpdateApiTokenRateLimit(id int64, rateLimit int) error {
+	if as.databaseService == nil || as.databaseService.getDB() == nil {
+		return errors.New("database service not available")
+	}
+	if err := as.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+	if rateLimit < 0 {
+		return errors.New("rate limit must not be negative")
+	}
+
+	if _, err := as.databaseService.getDB().Exec(sqlUpdateApiTokenRateLimit, rateLimit, id); err != nil {
+		return fmt.Errorf("failed to update api token rate limit: %w", err)
+	}
+	return nil
+}
+
+// ListApiAuditLog 获取最近的API调用记录
+func (as *ApiService) ListApiAuditLog(limit int) ([]*models.ApiAuditEntry, error) {
+	if as.databaseService == nil || as.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+	if limit <= 0 || limit > apiAuditRetention {
+		limit = 200
+	}
+
+	rows, err := as.databaseService.getDB().Query(sqlListApiAudit, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.ApiAuditEntry
+	for rows.Next() {
+		entry := &models.ApiAuditEntry{}
+		if err := rows.Scan(&entry.ID, &entry.TokenID, &entry.TokenName, &entry.Method,
+			&entry.Path, &entry.Status, &entry.RemoteAddr, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan api audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// ClearApiAuditLog 清空API调用记录
+func (as *ApiService) ClearApiAuditLog() error {
+	if as.databaseService == nil || as.databaseService.getDB() == nil {
+		return errors.New("database service not available")
+	}
+	if err := as.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+	if _, err := as.databaseService.getDB().Exec(sqlClearApiAudit); err != nil {
+		return fmt.Errorf("failed to clear api audit log: %w", err)
+	}
+	return nil
+}
+
+// withAuth 认证、权限检查、限流和审计
+func (as *ApiService) withAuth(required models.ApiTokenScope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recorder := &apiStatusRecorder{ResponseWriter: w, status: http.StatusOK}
+		var token *models.ApiToken
+		defer func() {
+			as.audit(token, r, recorder.status)
+		}()
+
+		token, err := as.authenticate(r)
+		if err != nil {
+			recorder.Header().Set("WWW-Authenticate", `Bearer realm="voidraft"`)
+			writeAPIError(recorder, http.StatusUnauthorized, "invalid or missing token")
+			return
+		}
+		if !token.Scope.Allows(required) {
+			writeAPIError(recorder, http.StatusForbidden, fmt.Sprintf("token scope %q does not allow this operation", token.Scope))
+			return
+		}
+		if !as.allow(token) {
+			recorder.Header().Set("Retry-After", "60")
+			writeAPIError(recorder, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+
+		r.Body = http.MaxBytesReader(recorder, r.Body, apiMaxBodyBytes)
+		next(recorder, r.WithContext(context.WithValue(r.Context(), apiTokenKey{}, token)))
+	}
+}
+
+// authenticate 校验 Authorization 头中的 Bearer 令牌
+func (as *ApiService) authenticate(r *http.Request) (*models.ApiToken, error) {
+	if as.databaseService == nil || as.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || !strings.HasPrefix(raw, apiTokenPrefix) {
+		return nil, errors.New("missing bearer token")
+	}
+
+	row := as.databaseService.getDB().QueryRow(sqlGetApiTokenByHash, hashApiToken(strings.TrimSpace(raw)))
+	token, err := scanApiToken(row)
+	if err != nil {
+		return nil, err
+	}
+
+	// 只读时不记录最近使用时间
+	if as.databaseService.ensureWritable() != nil {
+		return token, nil
+	}
+	if _, err := as.databaseService.getDB().Exec(sqlTouchApiToken, time.Now().Format(apiTimeLayout), token.ID); err != nil {
+		as.logger.Error("Failed to update api token usage", "error", err)
+	}
+	return token, nil
+}
+
+// getApiToken 按ID读取令牌的最新状态，令牌已撤销时返回 errApiTokenNotFound
+func (as *ApiService) getApiToken(id int64) (*models.ApiToken, error) {
+	if as.databaseService == nil || as.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+	return scanApiToken(as.databaseService.getDB().QueryRow(sqlGetApiTokenByID, id))
+}
+
+// allow 按令牌限流，令牌桶容量等于每分钟上限
+func (as *ApiService) allow(token *models.ApiToken) bool {
+	if token.RateLimit <= 0 {
+		return true
+	}
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	entry, ok := as.limiters[token.ID]
+	if !ok || entry.limit != token.RateLimit {
+		entry = &apiLimiter{
+			limit:   token.RateLimit,
+			limiter: rate.NewLimiter(rate.Limit(float64(token.RateLimit)/60), token.RateLimit),
+		}
+		as.limiters[token.ID] = entry
+	}
+	return entry.limiter.Allow()
+}
+
+// audit 记录一次API调用
+func (as *ApiService) audit(token *models.ApiToken, r *http.Request, status int) {
+	as.recordAudit(token, r.Method, r.URL.Path, r.RemoteAddr, status)
+}
+
+// recordAudit 写入审计记录
+func (as *ApiService) recordAudit(token *models.ApiToken, method, path, remoteAddr string, status int) {
+	if as.databaseService == nil || as.databaseService.getDB() == nil || as.databaseService.ensureWritable() != nil {
+		return
+	}
+
+	var tokenID int64
+	var tokenName string
+	if token != nil {
+		tokenID, tokenName = token.ID, token.Name
+	}
+	if _, err := as.databaseService.getDB().Exec(sqlInsertApiAudit, tokenID, tokenName, method,
+		path, status, remoteAddr, time.Now().Format(apiTimeLayout)); err != nil {
+		as.logger.Error("Failed to write api audit log", "error", err)
+	}
+}
+
+// handleListDocuments 列出文档元数据
+func (as *ApiService) handleListDocuments(w http.ResponseWriter, r *http.Request) {
+	docs, err := as.documentService.ListAllDocumentsMeta()
+	if err != nil {
+		writeDocumentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, docs)
+}
+
+// handleGetDocument 读取单个文档
+func (as *ApiService) handleGetDocument(w http.ResponseWriter, r *http.Request) {
+	doc, ok := as.lookupDocument(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+// handleAppendDocument 向文档末尾追加一个块，不返回文档内容
+func (as *ApiService) handleAppendDocument(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Content  string `json:"content"`
+		Language string `json:"language"`
+	}
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	if req.Content == "" {
+		writeAPIError(w, http.StatusBadRequest, "content is required")
+		return
+	}
+	if req.Language == "" {
+		req.Language = blocks.DefaultLanguage
+	}
+	if !apiLanguagePattern.MatchString(req.Language) {
+		writeAPIError(w, http.StatusBadRequest, "invalid language")
+		return
+	}
+
+	doc, ok := as.lookupDocument(w, r)
+	if !ok {
+		return
+	}
+
+	// 通过管道传入的终端输出可能带有颜色，去除转义序列
+	req.Content = ansi.Strip(req.Content)
+	// 以补丁追加，与编辑器中的修改串行，并可在编辑器中撤销
+	if _, err := as.documentService.AppendDocumentContent(doc.ID, blocks.Delimiter(req.Language, false)+req.Content, "Append from API"); err != nil {
+		writeDocumentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"id": doc.ID, "appended": len(req.Content)})
+}
+
+// handleCreateDocument 新建文档
+func (as *ApiService) handleCreateDocument(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Title   string `json:"title"`
+		Content string `json:"content"`
+	}
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Title) == "" {
+		writeAPIError(w, http.StatusBadRequest, "title is required")
+		return
+	}
+
+	doc, err := as.documentService.CreateDocument(req.Title)
+	if err != nil {
+		writeDocumentError(w, err)
+		return
+	}
+	if req.Content != "" {
+		doc.Content = blocks.Delimiter(blocks.DefaultLanguage, true) + ansi.Strip(req.Content)
+		if err := as.documentService.UpdateDocumentContent(doc.ID, doc.Content); err != nil {
+			writeDocumentError(w, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusCreated, doc)
+}
+
+// handleUpdateDocument 替换文档内容
+func (as *ApiService) handleUpdateDocument(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Content string `json:"content"`
+	}
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	doc, ok := as.lookupDocument(w, r)
+	if !ok {
+		return
+	}
+	if err := as.documentService.UpdateDocumentContent(doc.ID, req.Content); err != nil {
+		writeDocumentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"id": doc.ID})
+}
+
+// lookupDocument 按路径中的ID查找未删除的文档，失败时写入错误响应
+func (as *ApiService) lookupDocument(w http.ResponseWriter, r *http.Request) (*models.Document, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid document id")
+		return nil, false
+	}
+
+	doc, err := as.documentService.GetDocumentByID(id)
+	if err != nil {
+		writeDocumentError(w, err)
+		return nil, false
+	}
+	if doc == nil || doc.IsDeleted {
+		writeAPIError(w, http.StatusNotFound, "document not found")
+		return nil, false
+	}
+	return doc, true
+}
+
+// apiStatusRecorder 记录响应状态码以便审计
+type apiStatusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader 记录状态码
+func (rec *apiStatusRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+// scanApiToken 扫描令牌行
+func scanApiToken(row interface{ Scan(...any) error }) (*models.ApiToken, error) {
+	token := &models.ApiToken{}
+	var scope string
+	if err := row.Scan(&token.ID, &token.Name, &token.Prefix, &token.TokenHash,
+		&scope, &token.RateLimit, &token.CreatedAt, &token.LastUsedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errApiTokenNotFound
+		}
+		return nil, err
+	}
+	token.Scope = models.ApiTokenScope(scope)
+	return token, nil
+}
+
+// hashApiToken 计算令牌哈希，数据库中不保存明文
+func hashApiToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// decodeJSONBody 解析JSON请求体，失败时写入错误响应
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+	return true
+}
+
+// writeDocumentError 将文档服务错误映射为HTTP响应
+func writeDocumentError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrAppLocked) {
+		writeAPIError(w, http.StatusLocked, "application is locked")
+		return
+	}
+	writeAPIError(w, http.StatusInternalServerError, err.Error())
+}
+
+// writeJSON 写入JSON响应
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeAPIError 写入JSON错误响应
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// ServiceShutdown 服务关闭
+func (as *ApiService) ServiceShutdown() error {
+	return nil
+}
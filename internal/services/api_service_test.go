@@ -0,0 +1,115 @@
+package services
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// newTestDatabaseService 使用内存数据库并创建指定的表
+func newTestDatabaseService(t *testing.T, tables ...string) *DatabaseService {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+	for _, table := range tables {
+		if _, err := db.Exec(table); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ds := &DatabaseService{logger: log.New()}
+	ds.db.Store(db)
+	return ds
+}
+
+func newTestApiService(t *testing.T) *ApiService {
+	return &ApiService{
+		logger:          log.New(),
+		databaseService: newTestDatabaseService(t, sqlCreateApiTokensTable, sqlCreateApiAuditLogTable),
+		limiters:        make(map[int64]*apiLimiter),
+	}
+}
+
+func apiRequest(handler http.HandlerFunc, token string) int {
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/documents", nil)
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w.Code
+}
+
+func TestApiScope(t *testing.T) {
+	as := newTestApiService(t)
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }
+
+	tokens := make(map[models.ApiTokenScope]string)
+	for _, scope := range []models.ApiTokenScope{models.ApiScopeReadOnly, models.ApiScopeAppendOnly, models.ApiScopeFull} {
+		created, err := as.CreateApiToken(string(scope), scope, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tokens[scope] = created.Token
+	}
+
+	tests := []struct {
+		required models.ApiTokenScope
+		token    string
+		want     int
+	}{
+		{models.ApiScopeReadOnly, "", http.StatusUnauthorized},
+		{models.ApiScopeReadOnly, apiTokenPrefix + "forged", http.StatusUnauthorized},
+		{models.ApiScopeReadOnly, tokens[models.ApiScopeReadOnly], http.StatusNoContent},
+		{models.ApiScopeReadOnly, tokens[models.ApiScopeAppendOnly], http.StatusForbidden},
+		{models.ApiScopeAppendOnly, tokens[models.ApiScopeReadOnly], http.StatusForbidden},
+		{models.ApiScopeAppendOnly, tokens[models.ApiScopeAppendOnly], http.StatusNoContent},
+		{models.ApiScopeFull, tokens[models.ApiScopeAppendOnly], http.StatusForbidden},
+		{models.ApiScopeFull, tokens[models.ApiScopeFull], http.StatusNoContent},
+		{models.ApiScopeReadOnly, tokens[models.ApiScopeFull], http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		if got := apiRequest(as.withAuth(tt.required, ok), tt.token); got != tt.want {
+			t.Errorf("%s route with token %.12q: status = %d, want %d", tt.required, tt.token, got, tt.want)
+		}
+	}
+
+	entries, err := as.ListApiAuditLog(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(tests) {
+		t.Errorf("audit entries = %d, want %d", len(entries), len(tests))
+	}
+}
+
+func TestApiRateLimit(t *testing.T) {
+	as := newTestApiService(t)
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }
+	created, err := as.CreateApiToken("limited", models.ApiScopeReadOnly, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := as.withAuth(models.ApiScopeReadOnly, ok)
+	for i, want := range []int{http.StatusNoContent, http.StatusNoContent, http.StatusTooManyRequests} {
+		if got := apiRequest(handler, created.Token); got != want {
+			t.Errorf("request %d: status = %d, want %d", i+1, got, want)
+		}
+	}
+
+	// 调高上限后按新的上限重新计数
+	if err := as.UpdateApiTokenRateLimit(created.Info.ID, 10); err != nil {
+		t.Fatal(err)
+	}
+	if got := apiRequest(handler, created.Token); got != http.StatusNoContent {
+		t.Errorf("after raising the limit: status = %d, want %d", got, http.StatusNoContent)
+	}
+}
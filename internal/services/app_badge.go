@@ -0,0 +1,81 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/wailsapp/wails/v3/pkg/services/dock"
+)
+
+// appBadgeLabel 徽章显示的内容
+const appBadgeLabel = "●"
+
+// badgeSource 需要显示徽章的来源
+type badgeSource string
+
+const (
+	badgeSourceUpdate  badgeSource = "update"  // 有可用的更新
+	badgeSourceUnsaved badgeSource = "unsaved" // 有窗口存在未保存的修改
+)
+
+// appBadge 程序坞和任务栏徽章，多个来源共用同一个徽章，任一来源需要时显示
+// macOS 显示在程序坞图标上，Windows 显示为任务栏按钮的叠加图标
+type appBadge struct {
+	dock *dock.DockService
+
+	mu       sync.Mutex
+	sources  map[badgeSource]bool
+	progress string // 后台任务进度，不为空时代替默认内容显示
+	shown    string // 当前显示的内容，为空表示未显示
+}
+
+// newAppBadge 创建共用的徽章
+func newAppBadge(dockService *dock.DockService) *appBadge {
+	return &appBadge{
+		dock:    dockService,
+		sources: make(map[badgeSource]bool),
+	}
+}
+
+// set 更新来源的徽章状态，只在徽章内容变化时调用系统接口
+func (b *appBadge) set(source badgeSource, on bool) error {
+	if b == nil || b.dock == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if on {
+		b.sources[source] = true
+	} else {
+		delete(b.sources, source)
+	}
+	return b.applyLocked()
+}
+
+// setProgress 显示后台任务进度，如 42%，为空时恢复默认内容
+func (b *appBadge) setProgress(label string) error {
+	if b == nil || b.dock == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.progress = label
+	return b.applyLocked()
+}
+
+// applyLocked 按来源和进度更新徽章，调用方需持有 mu
+func (b *appBadge) applyLocked() error {
+	label := b.progress
+	if label == "" && len(b.sources) > 0 {
+		label = appBadgeLabel
+	}
+	if label == b.shown {
+		return nil
+	}
+	b.shown = label
+	if label == "" {
+		return b.dock.RemoveBadge()
+	}
+	return b.dock.SetBadge(label)
+}
@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/common/keystore"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+const (
+	// appLockKeyringKey 钥匙串中保存口令哈希的键
+	appLockKeyringKey = "app-lock-passphrase"
+	// appLockCheckInterval 空闲检测间隔
+	appLockCheckInterval = 15 * time.Second
+	// appLockMaxBackoff 连续输错口令后的最长等待时间
+	appLockMaxBackoff = 5 * time.Minute
+)
+
+// ErrAppLocked 应用已锁定，拒绝访问文档内容
+var ErrAppLocked = errors.New("application is locked")
+
+// AppLockService 应用锁服务，支持手动锁定和空闲自动锁定
+type AppLockService struct {
+	logger        *log.LogService
+	configService *ConfigService
+	windowHelper  *helper.WindowHelper
+
+	mu             sync.RWMutex
+	locked         bool
+	lastActivity   time.Time
+	failedAttempts int
+	retryAfter     time.Time
+	hiddenWindows  []application.Window // 锁定时隐藏的文档窗口，解锁后恢复
+
+	// 锁定状态变化监听器
+	listenersMu sync.RWMutex
+	listeners   []lockChangedListener
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// lockChangedListener 锁定状态变化监听器
+type lockChangedListener func(locked bool)
+
+// NewAppLockService 创建应用锁服务
+func NewAppLockService(configService *ConfigService, logger *log.LogService) *AppLockService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	return &AppLockService{
+		logger:        logger,
+		configService: configService,
+		windowHelper:  helper.NewWindowHelper(),
+		lastActivity:  time.Now(),
+	}
+}
+
+// ServiceStartup 服务启动，启用应用锁时启动即锁定并开始空闲检测
+func (as *AppLockService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	if as.isEnabled() {
+		as.mu.Lock()
+		as.locked = true
+		as.mu.Unlock()
+	}
+
+	idleCtx, cancel := context.WithCancel(context.Background())
+	as.cancel = cancel
+	as.wg.Add(1)
+	go as.watchIdle(idleCtx)
+	return nil
+}
+
+// IsLockConfigured 是否已设置解锁口令
+func (as *AppLockService) IsLockConfigured() (bool, error) {
+	return keystore.Exists(appLockKeyringKey)
+}
+
+// IsLocked 应用当前是否锁定
+func (as *AppLockService) IsLocked() bool {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+	return as.locked
+}
+
+// SetLockPassphrase 设置或修改解锁口令，已设置时需提供当前口令
+func (as *AppLockService) SetLockPassphrase(current, passphrase string) error {
+	if len(passphrase) < 4 {
+		return errors.New("passphrase must be at least 4 characters")
+	}
+
+	if err := as.verifyIfConfigured(current); err != nil {
+		return err
+	}
+
+	hash, err := keystore.HashPassphrase(passphrase)
+	if err != nil {
+		return err
+	}
+	if err := keystore.Set(appLockKeyringKey, hash); err != nil {
+		return fmt.Errorf("failed to store passphrase hash: %w", err)
+	}
+	return nil
+}
+
+// ClearLockPassphrase 清除解锁口令并关闭应用锁
+func (as *AppLockService) ClearLockPassphrase(current string) error {
+	if err := as.verifyIfConfigured(current); err != nil {
+		return err
+	}
+
+	if err := keystore.Delete(appLockKeyringKey); err != nil {
+		return fmt.Errorf("failed to delete passphrase hash: %w", err)
+	}
+	return as.configService.Set("security.appLockEnabled", false)
+}
+
+// LockApp 立即锁定应用
+func (as *AppLockService) LockApp() error {
+	configured, err := as.IsLockConfigured()
+	if err != nil {
+		return fmt.Errorf("failed to read keyring: %w", err)
+	}
+	if !configured {
+		return errors.New("lock passphrase is not set")
+	}
+
+	as.mu.Lock()
+	if as.locked {
+		as.mu.Unlock()
+		return nil
+	}
+	as.locked = true
+	as.mu.Unlock()
+
+	as.blankWindows()
+	helper.EmitEvent(constant.EVENT_APP_LOCKED)
+	as.notifyLockChanged(true)
+	as.logger.Info("Application locked")
+	return nil
+}
+
+// UnlockApp 使用口令解锁应用，连续输错时指数退避
+func (as *AppLockService) UnlockApp(passphrase string) error {
+	as.mu.Lock()
+	if !as.locked {
+		as.mu.Unlock()
+		return nil
+	}
+	if wait := time.Until(as.retryAfter); wait > 0 {
+		as.mu.Unlock()
+		return fmt.Errorf("too many failed attempts, retry in %d seconds", int(wait.Seconds())+1)
+	}
+	as.mu.Unlock()
+
+	hash, err := keystore.Get(appLockKeyringKey)
+	if err != nil {
+		return fmt.Errorf("failed to read passphrase hash: %w", err)
+	}
+	ok, err := keystore.VerifyPassphrase(passphrase, hash)
+	if err != nil {
+		return err
+	}
+
+	as.mu.Lock()
+	if !ok {
+		as.failedAttempts++
+		if as.failedAttempts >= 3 {
+			backoff := time.Duration(1<<min(as.failedAttempts-3, 8)) * time.Second
+			as.retryAfter = time.Now().Add(min(backoff, appLockMaxBackoff))
+		}
+		as.mu.Unlock()
+		return errors.New("incorrect passphrase")
+	}
+
+	as.locked = false
+	as.failedAttempts = 0
+	as.retryAfter = time.Time{}
+	as.lastActivity = time.Now()
+	hidden := as.hiddenWindows
+	as.hiddenWindows = nil
+	as.mu.Unlock()
+
+	for _, window := range hidden {
+		window.Show()
+	}
+	helper.EmitEvent(constant.EVENT_APP_UNLOCKED)
+	as.notifyLockChanged(false)
+	as.logger.Info("Application unlocked")
+	return nil
+}
+
+// ReportActivity 前端上报用户活动，重置空闲计时
+func (as *AppLockService) ReportActivity() {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.lastActivity = time.Now()
+}
+
+// onLockChanged 注册锁定状态变化监听器，供显示文档信息的后端服务订阅
+func (as *AppLockService) onLockChanged(listener lockChangedListener) {
+	as.listenersMu.Lock()
+	defer as.listenersMu.Unlock()
+	as.listeners = append(as.listeners, listener)
+}
+
+// notifyLockChanged 通知所有监听器，监听器异步执行
+func (as *AppLockService) notifyLockChanged(locked bool) {
+	as.listenersMu.RLock()
+	listeners := make([]lockChangedListener, len(as.listeners))
+	copy(listeners, as.listeners)
+	as.listenersMu.RUnlock()
+
+	for _, listener := range listeners {
+		go listener(locked)
+	}
+}
+
+// ensureUnlocked 文档内容访问前的检查
+func (as *AppLockService) ensureUnlocked() error {
+	if as == nil {
+		return nil
+	}
+	if as.IsLocked() {
+		return ErrAppLocked
+	}
+	return nil
+}
+
+// verifyIfConfigured 已设置口令时校验当前口令
+func (as *AppLockService) verifyIfConfigured(current string) error {
+	hash, err := keystore.Get(appLockKeyringKey)
+	if errors.Is(err, keystore.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read passphrase hash: %w", err)
+	}
+
+	ok, err := keystore.VerifyPassphrase(current, hash)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("incorrect passphrase")
+	}
+	return nil
+}
+
+// blankWindows 隐藏文档窗口并显示主窗口，主窗口由前端遮挡内容并显示解锁界面
+func (as *AppLockService) blankWindows() {
+	windows := as.windowHelper.GetAllDocumentWindows()
+
+	as.mu.Lock()
+	for _, window := range windows {
+		if window.IsVisible() {
+			window.Hide()
+			as.hiddenWindows = append(as.hiddenWindows, window)
+		}
+	}
+	as.mu.Unlock()
+
+	as.windowHelper.ShowMainWindow()
+}
+
+// watchIdle 定期检查空闲时间，超时后自动锁定
+func (as *AppLockService) watchIdle(ctx context.Context) {
+	defer as.wg.Done()
+
+	ticker := time.NewTicker(appLockCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			timeout := as.idleTimeout()
+			if timeout <= 0 {
+				continue
+			}
+
+			as.mu.RLock()
+			idle := !as.locked && time.Since(as.lastActivity) >= timeout
+			as.mu.RUnlock()
+
+			if idle {
+				if err := as.LockApp(); err != nil {
+					as.logger.Error("Failed to auto lock application", "error", err)
+				}
+			}
+		}
+	}
+}
+
+// isEnabled 是否启用应用锁
+func (as *AppLockService) isEnabled() bool {
+	config, err := as.configService.GetConfig()
+	if err != nil {
+		return false
+	}
+	if !config.Security.AppLockEnabled {
+		return false
+	}
+	configured, err := as.IsLockConfigured()
+	return err == nil && configured
+}
+
+// idleTimeout 自动锁定的空闲时间，未启用时返回0
+func (as *AppLockService) idleTimeout() time.Duration {
+	if !as.isEnabled() {
+		return 0
+	}
+	config, err := as.configService.GetConfig()
+	if err != nil || config.Security.AutoLockMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(config.Security.AutoLockMinutes) * time.Minute
+}
+
+// ServiceShutdown 服务关闭
+func (as *AppLockService) ServiceShutdown() error {
+	if as.cancel != nil {
+		as.cancel()
+	}
+	as.wg.Wait()
+	return nil
+}
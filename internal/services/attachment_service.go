@@ -0,0 +1,370 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// SQL 查询语句
+const (
+	sqlInsertAttachment = `
+INSERT INTO attachments (document_id, name, mime_type, size, hash, created_at)
+VALUES (?, ?, ?, ?, ?, ?)`
+
+	sqlGetAttachment = `
+SELECT id, document_id, name, mime_type, size, hash, created_at
+FROM attachments WHERE id = ?`
+
+	sqlListAttachments = `
+SELECT id, document_id, name, mime_type, size, hash, created_at
+FROM attachments WHERE document_id = ?
+ORDER BY id`
+
+	sqlDeleteAttachment = `
+DELETE FROM attachments WHERE id = ?`
+
+	sqlCountAttachmentHash = `
+SELECT COUNT(*) FROM attachments WHERE hash = ?`
+)
+
+const (
+	// attachmentDirName 数据目录下保存附件的目录
+	attachmentDirName = "attachments"
+	// attachmentRoutePrefix 资源处理器中的附件路由前缀
+	attachmentRoutePrefix = "/attachments/"
+	// attachmentMaxBytes 单个附件大小上限
+	attachmentMaxBytes = 2 << 30
+)
+
+// AttachmentService 文档附件服务，附件通过资源处理器按需流式加载，不经过 JS 桥传输
+// 附件文件不受数据库加密保护，启用加密后仍以明文保存，见 DatabaseEncryptionStatus.Attachments
+type AttachmentService struct {
+	logger          *log.LogService
+	configService   *ConfigService
+	databaseService *DatabaseService
+	appLockService  *AppLockService
+
+	// token 本次运行的随机令牌，加载附件时需携带，防止其他页面直接引用
+	// 令牌不写入附件地址，前端渲染时追加，文档内容中的地址重启后仍然有效
+	token string
+}
+
+// NewAttachmentService 创建附件服务
+func NewAttachmentService(configService *ConfigService, databaseService *DatabaseService, appLockService *AppLockService, logger *log.LogService) *AttachmentService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	buf := make([]byte, 16)
+	rand.Read(buf)
+
+	return &AttachmentService{
+		logger:          logger,
+		configService:   configService,
+		databaseService: databaseService,
+		appLockService:  appLockService,
+		token:           hex.EncodeToString(buf),
+	}
+}
+
+// ServiceStartup 服务启动
+func (as *AttachmentService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	return nil
+}
+
+// GetAttachmentToken 本次运行的附件令牌，前端加载附件时以 token 参数追加到附件地址
+func (as *AttachmentService) GetAttachmentToken() string {
+	return as.token
+}
+
+// AddAttachment 将本地文件添加为文档附件，相同内容只保存一份
+func (as *AttachmentService) AddAttachment(documentID int64, sourcePath string) (*models.Attachment, error) {
+	if as.databaseService == nil || as.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+	if err := as.databaseService.ensureWritable(); err != nil {
+		return nil, err
+	}
+
+	src, err := os.Open(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		return nil, errors.New("attachment must be a file")
+	}
+	if info.Size() > attachmentMaxBytes {
+		return nil, fmt.Errorf("attachment is larger than %d bytes", attachmentMaxBytes)
+	}
+
+	dir, err := as.attachmentDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// 边复制边计算哈希，复制完成后按哈希重命名
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hasher := sha256.New()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(src, head)
+	head = head[:n]
+	size, err := io.Copy(io.MultiWriter(tmp, hasher), io.MultiReader(bytes.NewReader(head), src))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy attachment: %w", err)
+	}
+
+	hash := hex.EncodeToString(hasher.Sum(nil))
+	name := filepath.Base(sourcePath)
+	storePath := as.storePath(dir, hash)
+	if _, err := os.Stat(storePath); errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(storePath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create attachment directory: %w", err)
+		}
+		if err := os.Rename(tmp.Name(), storePath); err != nil {
+			return nil, fmt.Errorf("failed to store attachment: %w", err)
+		}
+	}
+
+	mimeType := mime.TypeByExtension(filepath.Ext(name))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(head)
+	}
+
+	attachment := &models.Attachment{
+		DocumentID: documentID,
+		Name:       name,
+		MimeType:   mimeType,
+		Size:       size,
+		Hash:       hash,
+		CreatedAt:  time.Now().Format("2006-01-02 15:04:05"),
+	}
+	result, err := as.databaseService.getDB().Exec(sqlInsertAttachment,
+		attachment.DocumentID, attachment.Name, attachment.MimeType, attachment.Size, attachment.Hash, attachment.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save attachment: %w", err)
+	}
+	if attachment.ID, err = result.LastInsertId(); err != nil {
+		return nil, fmt.Errorf("failed to get attachment id: %w", err)
+	}
+	attachment.URL = attachmentURL(attachment)
+	return attachment, nil
+}
+
+// ListAttachments 列出文档的附件
+func (as *AttachmentService) ListAttachments(documentID int64) ([]*models.Attachment, error) {
+	if as.databaseService == nil || as.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	rows, err := as.databaseService.getDB().Query(sqlListAttachments, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := []*models.Attachment{}
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachment.URL = attachmentURL(attachment)
+		attachments = append(attachments, attachment)
+	}
+	return attachments, rows.Err()
+}
+
+// DeleteAttachment 删除附件，没有其他引用时同时删除文件
+func (as *AttachmentService) DeleteAttachment(id int64) error {
+	if as.databaseService == nil || as.databaseService.getDB() == nil {
+		return errors.New("database service not available")
+	}
+	if err := as.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	attachment, err := as.getAttachment(id)
+	if err != nil {
+		return err
+	}
+	if _, err := as.databaseService.getDB().Exec(sqlDeleteAttachment, id); err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	var refs int
+	if err := as.databaseService.getDB().QueryRow(sqlCountAttachmentHash, attachment.Hash).Scan(&refs); err != nil {
+		return fmt.Errorf("failed to count attachment references: %w", err)
+	}
+	if refs == 0 {
+		dir, err := as.attachmentDir()
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(as.storePath(dir, attachment.Hash)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			as.logger.Warning("Failed to remove attachment file", "hash", attachment.Hash, "error", err)
+		}
+	}
+	return nil
+}
+
+// middleware 资源处理器中间件，处理附件路由，其余请求交给前端资源
+func (as *AttachmentService) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, attachmentRoutePrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		as.serveAttachment(w, r)
+	})
+}
+
+// serveAttachment 流式返回附件内容，支持 Range 请求以便大文件分段加载
+func (as *AttachmentService) serveAttachment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token := r.URL.Query().Get("token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(as.token)) != 1 {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	if err := as.appLockService.ensureUnlocked(); err != nil {
+		http.Error(w, err.Error(), http.StatusLocked)
+		return
+	}
+
+	// 路径格式：/attachments/{id}/{name}，文件名仅用于显示
+	rest := strings.TrimPrefix(r.URL.Path, attachmentRoutePrefix)
+	idPart, _, _ := strings.Cut(rest, "/")
+	id, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	attachment, err := as.getAttachment(id)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	dir, err := as.attachmentDir()
+	if err != nil {
+		http.Error(w, "attachment storage unavailable", http.StatusInternalServerError)
+		return
+	}
+	serveAttachmentFile(w, r, attachment, as.storePath(dir, attachment.Hash))
+}
+
+// serveAttachmentFile 返回附件文件内容和安全相关的响应头
+func serveAttachmentFile(w http.ResponseWriter, r *http.Request, attachment *models.Attachment, path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(w, "failed to read attachment", http.StatusInternalServerError)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", attachment.MimeType)
+	header.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": attachment.Name}))
+	header.Set("X-Content-Type-Options", "nosniff")
+	// 附件可能是 HTML 或 SVG，禁止其中的脚本执行
+	header.Set("Content-Security-Policy", "default-src 'none'; img-src 'self' data:; style-src 'unsafe-inline'; media-src 'self'; sandbox")
+	header.Set("Cache-Control", "private, max-age=3600")
+	http.ServeContent(w, r, attachment.Name, info.ModTime(), file)
+}
+
+// getAttachment 按ID获取附件
+func (as *AttachmentService) getAttachment(id int64) (*models.Attachment, error) {
+	if as.databaseService == nil || as.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+	attachment, err := scanAttachment(as.databaseService.getDB().QueryRow(sqlGetAttachment, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("attachment not found: %d", id)
+	}
+	return attachment, err
+}
+
+// attachmentURL 生成附件的资源地址，不含令牌，可以写入文档内容
+func attachmentURL(attachment *models.Attachment) string {
+	return fmt.Sprintf("%s%d/%s", attachmentRoutePrefix, attachment.ID, url.PathEscape(attachment.Name))
+}
+
+// attachmentDir 附件根目录
+func (as *AttachmentService) attachmentDir() (string, error) {
+	config, err := as.configService.GetConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config: %w", err)
+	}
+	dir := filepath.Join(config.General.DataPath, attachmentDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create attachment directory: %w", err)
+	}
+	return dir, nil
+}
+
+// storePath 附件文件路径，按哈希前两位分目录
+func (as *AttachmentService) storePath(dir, hash string) string {
+	return filepath.Join(dir, hash[:2], hash)
+}
+
+// scanAttachment 扫描附件行
+func scanAttachment(row interface{ Scan(dest ...any) error }) (*models.Attachment, error) {
+	attachment := &models.Attachment{}
+	err := row.Scan(&attachment.ID, &attachment.DocumentID, &attachment.Name, &attachment.MimeType,
+		&attachment.Size, &attachment.Hash, &attachment.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// ServiceShutdown 服务关闭
+func (as *AttachmentService) ServiceShutdown() error {
+	return nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"voidraft/internal/models"
+)
+
+func TestAttachmentURL(t *testing.T) {
+	got := attachmentURL(&models.Attachment{ID: 12, Name: "my photo.png"})
+	if want := "/attachments/12/my%20photo.png"; got != want {
+		t.Errorf("attachmentURL = %q, want %q", got, want)
+	}
+}
+
+func TestServeAttachmentToken(t *testing.T) {
+	as := NewAttachmentService(nil, nil, nil, nil)
+	for _, target := range []string{"/attachments/1/a.txt", "/attachments/1/a.txt?token=wrong"} {
+		w := httptest.NewRecorder()
+		as.serveAttachment(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != http.StatusForbidden {
+			t.Errorf("GET %s status = %d, want %d", target, w.Code, http.StatusForbidden)
+		}
+	}
+}
+
+func TestServeAttachmentFileRange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blob")
+	if err := os.WriteFile(path, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	attachment := &models.Attachment{Name: "a.txt", MimeType: "text/plain"}
+
+	r := httptest.NewRequest(http.MethodGet, "/attachments/1/a.txt", nil)
+	r.Header.Set("Range", "bytes=2-5")
+	w := httptest.NewRecorder()
+	serveAttachmentFile(w, r, attachment, path)
+	if w.Code != http.StatusPartialContent {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusPartialContent)
+	}
+	if got := w.Body.String(); got != "2345" {
+		t.Errorf("body = %q, want %q", got, "2345")
+	}
+	if got := w.Header().Get("Content-Range"); got != "bytes 2-5/10" {
+		t.Errorf("Content-Range = %q", got)
+	}
+	if got := w.Header().Get("Content-Security-Policy"); got == "" {
+		t.Error("Content-Security-Policy header is missing")
+	}
+
+	w = httptest.NewRecorder()
+	serveAttachmentFile(w, r, attachment, filepath.Join(t.TempDir(), "missing"))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("missing file status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
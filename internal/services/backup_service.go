@@ -19,6 +19,7 @@ import (
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 	"github.com/wailsapp/wails/v3/pkg/services/log"
+	"github.com/wailsapp/wails/v3/pkg/services/notifications"
 
 	"voidraft/internal/models"
 
@@ -30,10 +31,14 @@ const (
 	dbSerializeFile = "voidraft_data.bin"
 )
 
+// errNoBackupChanges 没有需要备份的变更
+var errNoBackupChanges = errors.New("no changes to backup")
+
 // BackupService 提供基于Git的备份功能
 type BackupService struct {
 	configService    *ConfigService
 	dbService        *DatabaseService
+	notifications    *NotificationCenterService
 	repository       *git.Repository
 	logger           *log.LogService
 	isInitialized    bool
@@ -50,14 +55,16 @@ type BackupService struct {
 // 参数:
 //   - configService: 配置服务，用于获取备份相关配置
 //   - dbService: 数据库服务，用于序列化数据库
+//   - notificationCenter: 通知中心，用于提示自动备份失败
 //   - logger: 日志服务，用于记录备份操作日志
 //
 // 返回值:
 //   - *BackupService: 返回初始化的备份服务实例
-func NewBackupService(configService *ConfigService, dbService *DatabaseService, logger *log.LogService) *BackupService {
+func NewBackupService(configService *ConfigService, dbService *DatabaseService, notificationCenter *NotificationCenterService, logger *log.LogService) *BackupService {
 	return &BackupService{
 		configService: configService,
 		dbService:     dbService,
+		notifications: notificationCenter,
 		logger:        logger,
 	}
 }
@@ -232,7 +239,7 @@ func (s *BackupService) getAuthMethod(config *models.GitBackupConfig) (transport
 
 // serializeDatabase 序列化数据库到文件
 func (s *BackupService) serializeDatabase(repoPath string) error {
-	if s.dbService == nil || s.dbService.db == nil {
+	if s.dbService == nil || s.dbService.getDB() == nil {
 		return errors.New("database service not available")
 	}
 
@@ -240,7 +247,7 @@ func (s *BackupService) serializeDatabase(repoPath string) error {
 
 	// 使用 VACUUM INTO 创建数据库副本，不影响现有连接
 	s.dbService.mu.RLock()
-	_, err := s.dbService.db.Exec(fmt.Sprintf("VACUUM INTO '%s'", binFilePath))
+	_, err := s.dbService.getDB().Exec(fmt.Sprintf("VACUUM INTO '%s'", binFilePath))
 	s.dbService.mu.RUnlock()
 
 	if err != nil {
@@ -307,7 +314,7 @@ func (s *BackupService) PushToRemote() error {
 		// 如果没有变化，删除文件并返回
 		if status.IsClean() {
 			os.Remove(binFilePath)
-			return errors.New("no changes to backup")
+			return errNoBackupChanges
 		}
 
 		// 创建提交
@@ -321,7 +328,7 @@ func (s *BackupService) PushToRemote() error {
 		if err != nil {
 			os.Remove(binFilePath)
 			if strings.Contains(err.Error(), "cannot create empty commit") {
-				return errors.New("no changes to backup")
+				return errNoBackupChanges
 			}
 			return fmt.Errorf("creating commit: %w", err)
 		}
@@ -407,7 +414,14 @@ func (s *BackupService) StartAutoBackup() error {
 		for {
 			select {
 			case <-s.autoBackupTicker.C:
-				s.PushToRemote()
+				if err := s.PushToRemote(); err != nil && !errors.Is(err, errNoBackupChanges) {
+					s.logger.Error("Auto backup failed", "error", err)
+					s.notifications.notify(models.NotificationCategoryBackupFailure, notifications.NotificationOptions{
+						ID:    "backup_failed",
+						Title: "Voidraft Backup Failed",
+						Body:  err.Error(),
+					})
+				}
 			case <-s.autoBackupStop:
 				return
 			}
@@ -476,3 +490,30 @@ func (s *BackupService) ServiceShutdown() {
 	}
 	s.StopAutoBackup()
 }
+
+// syncStatus 备份状态，不访问远程仓库；正在推送时不等待推送完成
+func (s *BackupService) syncStatus() models.DashboardSync {
+	var status models.DashboardSync
+	if config, _, err := s.getConfigAndPath(); err == nil {
+		status.Enabled = config.Enabled
+		status.AutoBackup = config.AutoBackup
+	}
+	if !s.mu.TryLock() {
+		status.Syncing = true
+		return status
+	}
+	defer s.mu.Unlock()
+
+	status.Initialized = s.isInitialized
+	if s.repository == nil {
+		return status
+	}
+	head, err := s.repository.Head()
+	if err != nil {
+		return status
+	}
+	if commit, err := s.repository.CommitObject(head.Hash()); err == nil {
+		status.LastBackupAt = commit.Committer.When
+	}
+	return status
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+	"voidraft/internal/models"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// PerfBudget 核心路径的性能预算（每次操作耗时上限），超出即视为性能回归
+type PerfBudget map[string]time.Duration
+
+// DefaultPerfBudget 默认性能预算
+var DefaultPerfBudget = PerfBudget{
+	"DocumentSave": 5 * time.Millisecond,
+	"Search/1k":    5 * time.Millisecond,
+	"Search/10k":   20 * time.Millisecond,
+	"Search/100k":  100 * time.Millisecond,
+	"SnapDetect":   20 * time.Microsecond,
+}
+
+// ErrOverBudget 存在超出预算的基准项
+var ErrOverBudget = errors.New("performance budget exceeded")
+
+// DefaultBenchSizes 搜索基准的文档数量
+var DefaultBenchSizes = []int{1_000, 10_000, 100_000}
+
+// BenchResult 单项基准结果
+type BenchResult struct {
+	Name       string        `json:"name"`
+	Iterations int           `json:"iterations"`
+	PerOp      time.Duration `json:"perOp"`
+	Budget     time.Duration `json:"budget"` // 为0表示未设置预算
+	OverBudget bool          `json:"overBudget"`
+}
+
+// benchVocabulary 生成测试文档的词表
+var benchVocabulary = strings.Fields(`alpha beta gamma delta epsilon meeting notes todo fixme release
+	budget invoice server deploy config kubernetes golang rust python query index cache window
+	snap sidebar theme plugin export import sync backup archive journal idea draft review`)
+
+// RunBenchmarks 运行核心服务基准并与预算比较，供隐藏的 bench 命令使用
+func RunBenchmarks(sizes []int, budget PerfBudget) ([]BenchResult, error) {
+	if budget == nil {
+		budget = DefaultPerfBudget
+	}
+
+	var results []BenchResult
+	record := func(name string, r testing.BenchmarkResult) {
+		result := BenchResult{Name: name, Iterations: r.N, Budget: budget[name]}
+		if r.N > 0 {
+			result.PerOp = time.Duration(r.NsPerOp())
+		}
+		result.OverBudget = result.Budget > 0 && result.PerOp > result.Budget
+		results = append(results, result)
+	}
+
+	env, err := newBenchEnv()
+	if err != nil {
+		return nil, err
+	}
+	defer env.close()
+
+	if err := env.seedDocuments(1); err != nil {
+		return nil, err
+	}
+	record("DocumentSave", testing.Benchmark(env.benchDocumentSave))
+
+	for _, size := range sizes {
+		if err := env.seedDocuments(size); err != nil {
+			return nil, err
+		}
+		record("Search/"+benchSizeLabel(size), testing.Benchmark(env.benchSearch))
+	}
+
+	record("SnapDetect", testing.Benchmark(benchSnapDetect))
+	return results, nil
+}
+
+// benchEnv 基于临时数据目录的服务环境
+type benchEnv struct {
+	dir      string
+	database *DatabaseService
+	document *DocumentService
+	search   *SearchService
+	seeded   int
+}
+
+// newBenchEnv 创建临时数据目录并启动数据库、文档和搜索服务
+func newBenchEnv() (*benchEnv, error) {
+	dir, err := os.MkdirTemp("", "voidraft-bench-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bench directory: %w", err)
+	}
+
+	logger := log.New()
+	configService := &ConfigService{logger: logger, koanf: koanf.New(".")}
+	if err := configService.setDefaults(); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	configService.koanf.Set("general.dataPath", dir)
+
+	env := &benchEnv{dir: dir}
+	env.database = NewDatabaseService(configService, logger)
+	if err := env.database.ServiceStartup(context.Background(), application.ServiceOptions{}); err != nil {
+		env.close()
+		return nil, fmt.Errorf("failed to start database: %w", err)
+	}
+	env.document = NewDocumentService(configService, env.database, nil, logger)
+	env.search = NewSearchService(env.database, env.document, nil, logger)
+	return env, nil
+}
+
+// close 关闭服务并删除临时目录
+func (env *benchEnv) close() {
+	if env.database != nil {
+		env.database.ServiceShutdown()
+	}
+	os.RemoveAll(env.dir)
+}
+
+// seedDocuments 补充文档到指定数量并重建搜索索引
+func (env *benchEnv) seedDocuments(total int) error {
+	if total <= env.seeded {
+		return nil
+	}
+
+	db := env.database.getDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(sqlInsertDocument)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	rng := rand.New(rand.NewSource(int64(total)))
+	now := time.Now().Format("2006-01-02 15:04:05")
+	for i := env.seeded; i < total; i++ {
+		content := benchDocumentContent(rng)
+		if _, err := stmt.Exec(fmt.Sprintf("Bench %d", i), content, now, now, utf8.RuneCountInString(content)); err != nil {
+			return fmt.Errorf("failed to seed document: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	env.seeded = total
+	env.document.invalidateCaches()
+	return env.search.RebuildSearchIndex()
+}
+
+// benchDocumentSave 测量保存文档内容的延迟
+func (env *benchEnv) benchDocumentSave(b *testing.B) {
+	docs, err := env.document.ListAllDocumentsMeta()
+	if err != nil || len(docs) == 0 {
+		b.Fatalf("no document to save: %v", err)
+	}
+	id := docs[0].ID
+	rng := rand.New(rand.NewSource(1))
+	contents := make([]string, 16)
+	for i := range contents {
+		contents[i] = benchDocumentContent(rng)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := env.document.UpdateDocumentContent(id, contents[i%len(contents)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchSearch 测量全文搜索的延迟
+func (env *benchEnv) benchSearch(b *testing.B) {
+	queries := []string{"meeting notes", "deploy", "kube", "invoice budget", "snap win"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := env.search.Search(queries[i%len(queries)], searchDefaultLimit); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchSnapDetect 测量子窗口移动时的吸附检测吞吐
+func benchSnapDetect(b *testing.B) {
+	wss := &WindowSnapService{
+		logger:             log.New(),
+		snapEnabled:        true,
+		baseThresholdRatio: 0.025,
+		minThreshold:       8,
+		maxThreshold:       40,
+		managedWindows:     make(map[int64]*models.WindowInfo),
+		windowSizeCache:    make(map[int64][2]int),
+		snapGraph:          newSnapGraph(),
+		lastMainWindowPos:  models.WindowPosition{X: 100, Y: 100},
+		lastMainWindowSize: [2]int{1280, 800},
+	}
+	const windows = 32
+	for id := int64(1); id <= windows; id++ {
+		wss.managedWindows[id] = &models.WindowInfo{WindowID: id}
+		wss.windowSizeCache[id] = [2]int{640, 480}
+	}
+	lastMove := time.Now().Add(-time.Second)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		id := int64(i%windows + 1)
+		pos := models.WindowPosition{X: 1380 + i%60 - 30, Y: 100 + i%400}
+		wss.mu.Lock()
+		wss.shouldSnapWindow(nil, wss.managedWindows[id], pos, lastMove)
+		wss.mu.Unlock()
+	}
+}
+
+// benchDocumentContent 生成若干块随机文本
+func benchDocumentContent(rng *rand.Rand) string {
+	var sb strings.Builder
+	blocks := 1 + rng.Intn(4)
+	for i := 0; i < blocks; i++ {
+		sb.WriteString("\n∞∞∞text-a\n")
+		words := 20 + rng.Intn(200)
+		for j := 0; j < words; j++ {
+			if j > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(benchVocabulary[rng.Intn(len(benchVocabulary))])
+		}
+	}
+	return sb.String()
+}
+
+// benchSizeLabel 文档数量标签，如 1k、100k
+func benchSizeLabel(size int) string {
+	if size >= 1000 && size%1000 == 0 {
+		return fmt.Sprintf("%dk", size/1000)
+	}
+	return fmt.Sprint(size)
+}
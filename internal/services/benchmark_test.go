@@ -0,0 +1,52 @@
+package services
+
+import (
+	"testing"
+)
+
+// BenchmarkDocumentSave 基准测试：保存文档内容
+func BenchmarkDocumentSave(b *testing.B) {
+	env, err := newBenchEnv()
+	if err != nil {
+		b.Skipf("bench environment unavailable: %v", err)
+	}
+	defer env.close()
+	if err := env.seedDocuments(1); err != nil {
+		b.Fatal(err)
+	}
+
+	env.benchDocumentSave(b)
+}
+
+// BenchmarkSearch 基准测试：不同文档规模下的全文搜索
+func BenchmarkSearch(b *testing.B) {
+	env, err := newBenchEnv()
+	if err != nil {
+		b.Skipf("bench environment unavailable: %v", err)
+	}
+	defer env.close()
+
+	for _, size := range DefaultBenchSizes {
+		if testing.Short() && size > 10_000 {
+			break
+		}
+		if err := env.seedDocuments(size); err != nil {
+			b.Fatal(err)
+		}
+		b.Run(benchSizeLabel(size), env.benchSearch)
+	}
+}
+
+// BenchmarkSnapDetect 基准测试：吸附检测
+func BenchmarkSnapDetect(b *testing.B) {
+	benchSnapDetect(b)
+}
+
+// TestBenchSizeLabel 测试文档数量标签
+func TestBenchSizeLabel(t *testing.T) {
+	for size, want := range map[int]string{1000: "1k", 100_000: "100k", 1500: "1500", 10: "10"} {
+		if got := benchSizeLabel(size); got != want {
+			t.Errorf("benchSizeLabel(%d) = %q, want %q", size, got, want)
+		}
+	}
+}
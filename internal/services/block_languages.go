@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/langdetect"
+	"voidraft/internal/models"
+)
+
+const (
+	sqlDeleteBlockLanguages = `
+DELETE FROM block_languages WHERE document_id = ?`
+
+	sqlInsertBlockLanguage = `
+INSERT OR REPLACE INTO block_languages (document_id, block_index, language) VALUES (?, ?, ?)`
+
+	sqlListBlockLanguages = `
+SELECT block_index, language FROM block_languages WHERE document_id = ? ORDER BY block_index`
+
+	sqlListBlockLanguageSources = `
+SELECT id, content FROM documents WHERE is_deleted = 0`
+
+	sqlClearBlockLanguages = `
+DELETE FROM block_languages`
+)
+
+// languageFilterPrefix 搜索词中按块的自然语言筛选的前缀，如 lang:de
+const languageFilterPrefix = "lang:"
+
+// GetBlockLanguages 获取文档中识别出自然语言的文本块，按块序号排列
+func (ss *SearchService) GetBlockLanguages(documentID int64) ([]*models.BlockLanguage, error) {
+	if err := ss.documentService.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	if ss.databaseService == nil || ss.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	rows, err := ss.databaseService.getDB().Query(sqlListBlockLanguages, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list block languages: %w", err)
+	}
+	defer rows.Close()
+
+	languages := []*models.BlockLanguage{}
+	for rows.Next() {
+		language := &models.BlockLanguage{}
+		if err := rows.Scan(&language.BlockIndex, &language.Language); err != nil {
+			return nil, fmt.Errorf("failed to scan block language: %w", err)
+		}
+		languages = append(languages, language)
+	}
+	return languages, rows.Err()
+}
+
+// replaceBlockLanguages 在事务中重写文档各文本块的自然语言，parsed 为空时只清除
+func replaceBlockLanguages(ctx context.Context, tx *sql.Tx, id int64, parsed []blocks.Block) error {
+	if _, err := tx.ExecContext(ctx, sqlDeleteBlockLanguages, id); err != nil {
+		return fmt.Errorf("failed to clear block languages: %w", err)
+	}
+	for index, language := range detectBlockLanguages(parsed) {
+		if _, err := tx.ExecContext(ctx, sqlInsertBlockLanguage, id, index, language); err != nil {
+			return fmt.Errorf("failed to insert block language: %w", err)
+		}
+	}
+	return nil
+}
+
+// detectBlockLanguages 识别文本块的自然语言，返回块序号 -> 语言；代码块和无法识别的块不包括在内
+func detectBlockLanguages(parsed []blocks.Block) map[int]string {
+	languages := make(map[int]string)
+	for i, block := range parsed {
+		if i >= searchMaxBlocks {
+			break
+		}
+		if !blocks.IsProse(block.Language) {
+			continue
+		}
+		if language := langdetect.Detect(block.Content); language != "" {
+			languages[i] = language
+		}
+	}
+	return languages
+}
+
+// parseLanguageFilter 从搜索词中取出 lang:xx 筛选，返回其余的搜索词和语言；有多个时以最后一个为准
+func parseLanguageFilter(query string) (string, string) {
+	var terms []string
+	language := ""
+	for _, term := range strings.Fields(query) {
+		if len(term) > len(languageFilterPrefix) && strings.EqualFold(term[:len(languageFilterPrefix)], languageFilterPrefix) {
+			language = strings.ToLower(term[len(languageFilterPrefix):])
+			continue
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " "), language
+}
+
+// migrateBlockLanguages 数据迁移：识别已有文档中文本块的自然语言
+func migrateBlockLanguages(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, sqlListBlockLanguageSources)
+	if err != nil {
+		return err
+	}
+	sources := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var content string
+		if err := rows.Scan(&id, &content); err != nil {
+			rows.Close()
+			return err
+		}
+		sources[id] = content
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, content := range sources {
+		if err := replaceBlockLanguages(ctx, tx, id, blocks.Parse(content)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"voidraft/internal/common/blocks"
+)
+
+func TestParseLanguageFilter(t *testing.T) {
+	tests := []struct {
+		query    string
+		rest     string
+		language string
+	}{
+		{"", "", ""},
+		{"foo bar", "foo bar", ""},
+		{"lang:de haus", "haus", "de"},
+		{"haus LANG:DE", "haus", "de"},
+		{"lang:de lang:fr maison", "maison", "fr"},
+		{"lang: foo", "lang: foo", ""},
+	}
+
+	for _, tt := range tests {
+		rest, language := parseLanguageFilter(tt.query)
+		if rest != tt.rest || language != tt.language {
+			t.Errorf("parseLanguageFilter(%q) = %q, %q, want %q, %q", tt.query, rest, language, tt.rest, tt.language)
+		}
+	}
+}
+
+func TestDetectBlockLanguages(t *testing.T) {
+	parsed := []blocks.Block{
+		{Language: "text", Content: "The cat is on the table and it is sleeping."},
+		{Language: "go", Content: "// the value of the thing is in the map"},
+		{Language: "md", Content: "# Notizen\n\nDas ist nicht der Plan, und wir werden sehen."},
+		{Language: "text", Content: "ok"},
+		{Language: "text", Content: "今天天气很好，我们去公园散步吧。"},
+	}
+
+	want := map[int]string{0: "en", 2: "de", 4: "zh"}
+	if got := detectBlockLanguages(parsed); !reflect.DeepEqual(got, want) {
+		t.Errorf("detectBlockLanguages() = %v, want %v", got, want)
+	}
+}
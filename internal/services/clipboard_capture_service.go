@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// ErrNoCaptureTarget 没有最近的文档，也没有指定收集箱文档
+var ErrNoCaptureTarget = errors.New("no document to paste into: focus a document or set an inbox document")
+
+// ClipboardCaptureService 后台粘贴服务
+// 由全局热键触发，把剪贴板文本带时间戳追加到最近获得焦点的文档或收集箱文档，不显示任何窗口
+type ClipboardCaptureService struct {
+	logger          *log.LogService
+	configService   *ConfigService
+	documentService *DocumentService
+	windowService   *WindowService
+	hotkeyService   *HotkeyService
+
+	mu      sync.Mutex
+	binding *hotkeyBinding // 后台粘贴热键
+	running atomic.Bool
+
+	cancelObservers []CancelFunc
+}
+
+// NewClipboardCaptureService 创建后台粘贴服务实例
+func NewClipboardCaptureService(configService *ConfigService, documentService *DocumentService, windowService *WindowService, hotkeyService *HotkeyService, logger *log.LogService) *ClipboardCaptureService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &ClipboardCaptureService{
+		logger:          logger,
+		configService:   configService,
+		documentService: documentService,
+		windowService:   windowService,
+		hotkeyService:   hotkeyService,
+	}
+}
+
+// ServiceStartup 按配置注册全局热键
+func (cs *ClipboardCaptureService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	cs.cancelObservers = []CancelFunc{
+		cs.configService.Watch("capture.enableHotkey", cs.onHotkeyConfigChange),
+		cs.configService.Watch("capture.hotkey", cs.onHotkeyConfigChange),
+	}
+	cs.onHotkeyConfigChange(nil, nil)
+	return nil
+}
+
+// CaptureClipboard 将剪贴板文本追加到目标文档，打开该文档的编辑器通过 document:patched 事件同步
+func (cs *ClipboardCaptureService) CaptureClipboard() (*models.CaptureResult, error) {
+	if !cs.running.CompareAndSwap(false, true) {
+		return nil, errors.New("clipboard capture is already running")
+	}
+	defer cs.running.Store(false)
+
+	text, ok := application.Get().Clipboard.Text()
+	if !ok || strings.TrimSpace(text) == "" {
+		return nil, ErrClipboardEmpty
+	}
+
+	config, err := cs.configService.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("get config: %w", err)
+	}
+	documentID := resolveCaptureTarget(config.Capture, cs.windowService.GetRecentDocument())
+	if documentID == 0 {
+		return nil, ErrNoCaptureTarget
+	}
+
+	entry := formatCaptureEntry(text, time.Now(), config.Capture.TimestampFormat)
+	if _, err := cs.documentService.AppendDocumentContent(documentID, entry, "Paste from clipboard"); err != nil {
+		cs.logger.Error("Clipboard capture failed", "document", documentID, "error", err)
+		return nil, fmt.Errorf("append to document %d: %w", documentID, err)
+	}
+	return &models.CaptureResult{DocumentID: documentID, Length: utf8.RuneCountInString(text)}, nil
+}
+
+// resolveCaptureTarget 选择目标文档，没有可用文档时返回 0
+func resolveCaptureTarget(config models.CaptureConfig, recentDocumentID int64) int64 {
+	if config.Target != models.CaptureTargetInbox && recentDocumentID != 0 {
+		return recentDocumentID
+	}
+	return config.InboxDocumentID
+}
+
+// formatCaptureEntry 生成追加的内容：新建文本块，首行为时间戳
+func formatCaptureEntry(text string, now time.Time, layout string) string {
+	if layout == "" {
+		layout = time.DateTime
+	}
+	text = strings.TrimRight(text, "\r\n")
+	return blocks.Delimiter(blocks.DefaultLanguage, false) + "— " + now.Format(layout) + " —\n" + text
+}
+
+// onHotkeyConfigChange 按配置重新注册后台粘贴热键
+func (cs *ClipboardCaptureService) onHotkeyConfigChange(oldValue, newValue interface{}) {
+	cs.unregisterHotkey()
+
+	config, err := cs.configService.GetConfig()
+	if err != nil || !config.Capture.EnableHotkey {
+		return
+	}
+	if err := cs.registerHotkey(&config.Capture.Hotkey); err != nil {
+		cs.logger.Error("Failed to register clipboard capture hotkey", "error", err)
+	}
+}
+
+// registerHotkey 注册后台粘贴热键
+func (cs *ClipboardCaptureService) registerHotkey(combo *models.HotkeyCombo) error {
+	binding, err := cs.hotkeyService.bindHotkey(combo, func() {
+		if _, err := cs.CaptureClipboard(); err != nil {
+			cs.logger.Warning("Clipboard capture skipped", "error", err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	cs.mu.Lock()
+	cs.binding = binding
+	cs.mu.Unlock()
+	return nil
+}
+
+// unregisterHotkey 取消注册后台粘贴热键
+func (cs *ClipboardCaptureService) unregisterHotkey() {
+	cs.mu.Lock()
+	binding := cs.binding
+	cs.binding = nil
+	cs.mu.Unlock()
+	binding.Close()
+}
+
+// ServiceShutdown 取消热键和配置监听
+func (cs *ClipboardCaptureService) ServiceShutdown() error {
+	for _, cancel := range cs.cancelObservers {
+		cancel()
+	}
+	cs.unregisterHotkey()
+	return nil
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"voidraft/internal/models"
+)
+
+func TestResolveCaptureTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		config models.CaptureConfig
+		recent int64
+		want   int64
+	}{
+		{"recent document", models.CaptureConfig{Target: models.CaptureTargetRecent, InboxDocumentID: 9}, 3, 3},
+		{"no recent falls back to inbox", models.CaptureConfig{Target: models.CaptureTargetRecent, InboxDocumentID: 9}, 0, 9},
+		{"inbox ignores recent", models.CaptureConfig{Target: models.CaptureTargetInbox, InboxDocumentID: 9}, 3, 9},
+		{"nothing available", models.CaptureConfig{Target: models.CaptureTargetInbox}, 3, 0},
+	}
+	for _, tt := range tests {
+		if got := resolveCaptureTarget(tt.config, tt.recent); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatCaptureEntry(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 5, 0, 0, time.UTC)
+	got := formatCaptureEntry("hello\n", now, "")
+	want := "\n∞∞∞text\n— 2026-10-16 09:05:00 —\nhello"
+	if got != want {
+		t.Fatalf("formatCaptureEntry = %q, want %q", got, want)
+	}
+}
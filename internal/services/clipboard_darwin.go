@@ -0,0 +1,20 @@
+//go:build darwin
+
+package services
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// writeClipboardHTML 通过 AppleScript 同时写入 HTML 和 UTF-8 文本，内容以十六进制传递避免转义
+func writeClipboardHTML(html, text string) error {
+	script := fmt.Sprintf("set the clipboard to {«class HTML»:«data HTML%X», «class utf8»:«data utf8%X»}", html, text)
+	cmd := exec.Command("osascript", "-")
+	cmd.Stdin = strings.NewReader(script)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("osascript: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+//go:build linux
+
+package services
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// writeClipboardHTML 通过 wl-copy 或 xclip 写入 text/html
+// 这两个工具一次只提供一种类型，粘贴到只接受纯文本的程序时得不到内容，因此只在富文本格式下使用
+func writeClipboardHTML(html, text string) error {
+	var cmd *exec.Cmd
+	switch {
+	case os.Getenv("WAYLAND_DISPLAY") != "":
+		if _, err := exec.LookPath("wl-copy"); err != nil {
+			return err
+		}
+		cmd = exec.Command("wl-copy", "--type", "text/html")
+	default:
+		if _, err := exec.LookPath("xclip"); err != nil {
+			return err
+		}
+		cmd = exec.Command("xclip", "-selection", "clipboard", "-t", "text/html")
+	}
+	// 两个工具都会在后台进程中继续提供剪贴板内容，不捕获输出以免等待后台进程退出
+	cmd.Stdin = strings.NewReader(html)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", cmd.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+//go:build windows
+
+package services
+
+import (
+	"errors"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	cfUnicodeText = 13
+	gmemMoveable  = 0x0002
+)
+
+var (
+	clipboardUser32              = syscall.NewLazyDLL("user32.dll")
+	clipboardKernel32            = syscall.NewLazyDLL("kernel32.dll")
+	procOpenClipboard            = clipboardUser32.NewProc("OpenClipboard")
+	procCloseClipboard           = clipboardUser32.NewProc("CloseClipboard")
+	procEmptyClipboard           = clipboardUser32.NewProc("EmptyClipboard")
+	procSetClipboardData         = clipboardUser32.NewProc("SetClipboardData")
+	procRegisterClipboardFormatW = clipboardUser32.NewProc("RegisterClipboardFormatW")
+	procGlobalAlloc              = clipboardKernel32.NewProc("GlobalAlloc")
+	procGlobalFree               = clipboardKernel32.NewProc("GlobalFree")
+	procGlobalLock               = clipboardKernel32.NewProc("GlobalLock")
+	procGlobalUnlock             = clipboardKernel32.NewProc("GlobalUnlock")
+	procRtlMoveMemory            = clipboardKernel32.NewProc("RtlMoveMemory")
+)
+
+// writeClipboardHTML 同时写入 HTML Format 和 CF_UNICODETEXT
+func writeClipboardHTML(html, text string) error {
+	format, _, err := procRegisterClipboardFormatW.Call(uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr("HTML Format"))))
+	if format == 0 {
+		return err
+	}
+	utf16, err := syscall.UTF16FromString(text)
+	if err != nil {
+		return err
+	}
+
+	// 剪贴板由打开它的线程持有
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if r, _, err := procOpenClipboard.Call(0); r == 0 {
+		return err
+	}
+	defer procCloseClipboard.Call()
+	if r, _, err := procEmptyClipboard.Call(); r == 0 {
+		return err
+	}
+
+	if err := setClipboardData(cfUnicodeText, unsafe.Pointer(&utf16[0]), len(utf16)*2); err != nil {
+		return err
+	}
+	data := append(cfHTML(html), 0)
+	return setClipboardData(format, unsafe.Pointer(&data[0]), len(data))
+}
+
+// setClipboardData 复制数据到全局内存并交给剪贴板，成功后内存归剪贴板所有
+func setClipboardData(format uintptr, data unsafe.Pointer, size int) error {
+	handle, _, err := procGlobalAlloc.Call(gmemMoveable, uintptr(size))
+	if handle == 0 {
+		return err
+	}
+	ptr, _, err := procGlobalLock.Call(handle)
+	if ptr == 0 {
+		procGlobalFree.Call(handle)
+		return err
+	}
+	procRtlMoveMemory.Call(ptr, uintptr(data), uintptr(size))
+	procGlobalUnlock.Call(handle)
+
+	if r, _, err := procSetClipboardData.Call(format, handle); r == 0 {
+		procGlobalFree.Call(handle)
+		if err == nil {
+			err = errors.New("SetClipboardData failed")
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"voidraft/internal/common/ansi"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+const (
+	// commandDefaultTimeout 未配置超时时预设命令的最长运行时间
+	commandDefaultTimeout = 60 * time.Second
+	// commandMaxOutput 保留的命令输出上限
+	commandMaxOutput = 1 << 20
+)
+
+var (
+	// commandPlaceholder 命令参数中的 {{name}} 占位符
+	commandPlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+	// commandSafeValue 未配置取值规则的参数允许的字符，不能以 - 开头以免被当作选项
+	commandSafeValue = regexp.MustCompile(`^[A-Za-z0-9._:/@=][A-Za-z0-9._:/@=-]*$`)
+)
+
+// ErrCommandNotConfirmed 需要确认的命令未经确认
+var ErrCommandNotConfirmed = errors.New("command requires confirmation")
+
+// CommandService 命令面板服务
+// 执行用户预设的参数化命令，命令不经过 shell，参数取值按每个命令的规则校验，输出可作为文本块写入文档
+type CommandService struct {
+	logger          *log.LogService
+	configService   *ConfigService
+	documentService *DocumentService
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewCommandService 创建命令面板服务实例
+func NewCommandService(configService *ConfigService, documentService *DocumentService, logger *log.LogService) *CommandService {
+	if logger == nil {
+		logger = log.New()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &CommandService{
+		logger:          logger,
+		configService:   configService,
+		documentService: documentService,
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+}
+
+// ListCommands 列出启用的预设命令，命令面板关闭时为空
+func (cs *CommandService) ListCommands() []models.ShellCommand {
+	config, err := cs.configService.GetConfig()
+	if err != nil || !config.Commands.Enabled {
+		return []models.ShellCommand{}
+	}
+	commands := []models.ShellCommand{}
+	for _, command := range config.Commands.Commands {
+		if command.Enabled && strings.TrimSpace(command.Command) != "" {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+// PreviewCommand 返回替换参数后将要执行的命令和参数，用于确认提示
+func (cs *CommandService) PreviewCommand(id string, params map[string]string) ([]string, error) {
+	command, err := cs.findCommand(id)
+	if err != nil {
+		return nil, err
+	}
+	return buildCommandArgs(command, params)
+}
+
+// RunCommand 执行预设命令；documentID 大于 0 时把命令和输出作为文本块追加到文档末尾
+// 配置了确认的命令须在前端确认后以 confirmed 为 true 调用
+func (cs *CommandService) RunCommand(documentID int64, id string, params map[string]string, confirmed bool) (*models.CommandResult, error) {
+	command, err := cs.findCommand(id)
+	if err != nil {
+		return nil, err
+	}
+	if command.Confirm && !confirmed {
+		return nil, ErrCommandNotConfirmed
+	}
+	args, err := buildCommandArgs(command, params)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := commandDefaultTimeout
+	if command.Timeout > 0 {
+		timeout = time.Duration(command.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(cs.ctx, timeout)
+	defer cancel()
+
+	output := &cappedBuffer{limit: commandMaxOutput}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	start := time.Now()
+	err = cmd.Run()
+	result := &models.CommandResult{
+		Args:      args,
+		Output:    ansi.Strip(output.buf.String()),
+		Duration:  time.Since(start).Milliseconds(),
+		TimedOut:  errors.Is(ctx.Err(), context.DeadlineExceeded),
+		Truncated: output.truncated,
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		return nil, fmt.Errorf("failed to start command: %w", err)
+	}
+	cs.logger.Info("Command finished", "command", command.Name, "exitCode", result.ExitCode, "duration", result.Duration, "timedOut", result.TimedOut)
+
+	if documentID > 0 {
+		if err := cs.appendOutput(documentID, result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// findCommand 查找启用的预设命令
+func (cs *CommandService) findCommand(id string) (models.ShellCommand, error) {
+	for _, command := range cs.ListCommands() {
+		if command.ID == id {
+			return command, nil
+		}
+	}
+	return models.ShellCommand{}, fmt.Errorf("command not found: %s", id)
+}
+
+// appendOutput 把命令行和输出作为文本块追加到文档末尾
+func (cs *CommandService) appendOutput(documentID int64, result *models.CommandResult) error {
+	doc, err := cs.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil || doc.IsDeleted {
+		return fmt.Errorf("document not found: %d", documentID)
+	}
+
+	var b strings.Builder
+	b.WriteString("$ " + formatCommandLine(result.Args) + "\n")
+	b.WriteString(strings.TrimRight(result.Output, "\n"))
+	switch {
+	case result.TimedOut:
+		b.WriteString("\n[timed out]")
+	case result.ExitCode != 0:
+		fmt.Fprintf(&b, "\n[exit %d]", result.ExitCode)
+	}
+	content := doc.Content + blocks.Delimiter(blocks.DefaultLanguage, false) + b.String()
+	return cs.documentService.UpdateDocumentContent(documentID, content)
+}
+
+// buildCommandArgs 拆分命令模板并替换参数，取值不符合参数规则或出现未声明的占位符时返回错误
+func buildCommandArgs(command models.ShellCommand, params map[string]string) ([]string, error) {
+	// 先去掉占位符内的空白，避免拆分时被截断
+	args := splitCommandLine(commandPlaceholder.ReplaceAllString(command.Command, "{{$1}}"))
+	if len(args) == 0 {
+		return nil, fmt.Errorf("command %s is empty", command.Name)
+	}
+	if commandPlaceholder.MatchString(args[0]) {
+		return nil, fmt.Errorf("command %s: the program cannot be a parameter", command.Name)
+	}
+
+	values := make(map[string]string, len(command.Params))
+	for _, param := range command.Params {
+		value, ok := params[param.Name]
+		if !ok {
+			value = param.Default
+		}
+		if err := validateCommandParam(param, value); err != nil {
+			return nil, err
+		}
+		values[param.Name] = value
+	}
+
+	var missing string
+	for i, arg := range args[1:] {
+		args[i+1] = commandPlaceholder.ReplaceAllStringFunc(arg, func(m string) string {
+			name := commandPlaceholder.FindStringSubmatch(m)[1]
+			value, ok := values[name]
+			if !ok && missing == "" {
+				missing = name
+			}
+			return value
+		})
+	}
+	if missing != "" {
+		return nil, fmt.Errorf("command %s: parameter %s is not declared", command.Name, missing)
+	}
+	return args, nil
+}
+
+// validateCommandParam 按参数的取值列表或正则校验取值
+func validateCommandParam(param models.CommandParam, value string) error {
+	switch {
+	case len(param.Allowed) > 0:
+		if !slices.Contains(param.Allowed, value) {
+			return fmt.Errorf("parameter %s: %q is not an allowed value", param.Name, value)
+		}
+	case param.Pattern != "":
+		re, err := regexp.Compile(`^(?:` + param.Pattern + `)$`)
+		if err != nil {
+			return fmt.Errorf("parameter %s: invalid pattern: %w", param.Name, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("parameter %s: %q does not match %s", param.Name, value, param.Pattern)
+		}
+	default:
+		if !commandSafeValue.MatchString(value) {
+			return fmt.Errorf("parameter %s: %q contains characters that are not allowed", param.Name, value)
+		}
+	}
+	return nil
+}
+
+// formatCommandLine 把参数拼回命令行，含空白或引号的参数加引号
+func formatCommandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
+
+// cappedBuffer 超出上限后丢弃后续输出，命令仍可正常结束
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		b.buf.Write(p[:max(room, 0)])
+		b.truncated = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// ServiceShutdown 终止运行中的命令
+func (cs *CommandService) ServiceShutdown() error {
+	cs.cancel()
+	return nil
+}
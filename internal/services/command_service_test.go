@@ -0,0 +1,53 @@
+package services
+
+import (
+	"slices"
+	"testing"
+	"voidraft/internal/models"
+)
+
+func TestBuildCommandArgs(t *testing.T) {
+	command := models.ShellCommand{
+		Name:    "pods",
+		Command: "kubectl get pods -n {{ns}} -o {{ format }}",
+		Params: []models.CommandParam{
+			{Name: "ns"},
+			{Name: "format", Default: "wide", Allowed: []string{"wide", "yaml"}},
+		},
+	}
+	args, err := buildCommandArgs(command, map[string]string{"ns": "kube-system"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"kubectl", "get", "pods", "-n", "kube-system", "-o", "wide"}; !slices.Equal(args, want) {
+		t.Errorf("args = %q, want %q", args, want)
+	}
+
+	for _, params := range []map[string]string{
+		{"ns": "a b"},
+		{"ns": "--all"},
+		{"ns": "x; rm -rf /"},
+		{"ns": "default", "format": "json"},
+	} {
+		if _, err := buildCommandArgs(command, params); err == nil {
+			t.Errorf("buildCommandArgs(%v): want error", params)
+		}
+	}
+
+	command.Params = append(command.Params[:1], models.CommandParam{Name: "format", Pattern: "[a-z]+"})
+	if _, err := buildCommandArgs(command, map[string]string{"ns": "dev", "format": "JSON"}); err == nil {
+		t.Error("pattern mismatch: want error")
+	}
+	if _, err := buildCommandArgs(models.ShellCommand{Command: "echo {{undeclared}}"}, nil); err == nil {
+		t.Error("undeclared placeholder: want error")
+	}
+	if _, err := buildCommandArgs(models.ShellCommand{Command: "{{cmd}} x", Params: []models.CommandParam{{Name: "cmd", Default: "ls"}}}, nil); err == nil {
+		t.Error("placeholder as program: want error")
+	}
+}
+
+func TestFormatCommandLine(t *testing.T) {
+	if got, want := formatCommandLine([]string{"echo", "a b", "it's", ""}), `echo 'a b' 'it'\''s' ''`; got != want {
+		t.Errorf("formatCommandLine() = %s, want %s", got, want)
+	}
+}
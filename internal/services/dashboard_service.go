@@ -0,0 +1,215 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// SQL 查询语句
+const (
+	sqlDashboardDocumentCounts = `
+SELECT
+    COALESCE(SUM(CASE WHEN is_deleted = 0 AND is_archived = 0 THEN 1 ELSE 0 END), 0),
+    COALESCE(SUM(CASE WHEN is_deleted = 0 AND is_archived = 0 AND is_locked = 1 THEN 1 ELSE 0 END), 0),
+    COALESCE(SUM(CASE WHEN is_deleted = 0 AND kind IN ('journal', 'journal_archive') THEN 1 ELSE 0 END), 0),
+    COALESCE(SUM(CASE WHEN is_deleted = 0 AND is_archived = 1 THEN 1 ELSE 0 END), 0),
+    COALESCE(SUM(CASE WHEN is_deleted = 1 THEN 1 ELSE 0 END), 0),
+    COALESCE(SUM(CASE WHEN is_deleted = 0 THEN content_size ELSE 0 END), 0)
+FROM documents`
+
+	// 语言统计来自搜索索引，与文档内容的块划分一致
+	sqlDashboardLanguages = `
+SELECT s.language, COUNT(DISTINCT s.document_id), COUNT(*)
+FROM search_blocks s
+JOIN documents d ON d.id = s.document_id
+WHERE d.is_deleted = 0
+GROUP BY s.language
+ORDER BY COUNT(DISTINCT s.document_id) DESC, s.language`
+
+	sqlDashboardActivity = `
+SELECT day, SUM(created), SUM(updated)
+FROM (
+    SELECT substr(created_at, 1, 10) AS day, 1 AS created, 0 AS updated FROM documents WHERE is_deleted = 0
+    UNION ALL
+    SELECT substr(updated_at, 1, 10) AS day, 0 AS created, 1 AS updated FROM documents WHERE is_deleted = 0
+)
+WHERE day >= ?
+GROUP BY day`
+
+	sqlDashboardAttachments = `
+SELECT COUNT(*), COALESCE(SUM(a.size), 0)
+FROM attachments a
+JOIN documents d ON d.id = a.document_id
+WHERE d.is_deleted = 0`
+
+	sqlDashboardMostOpened = `
+SELECT id, title, open_count, content_size, updated_at
+FROM documents
+WHERE is_deleted = 0 AND is_archived = 0 AND open_count > 0
+ORDER BY open_count DESC, last_opened_at DESC
+LIMIT ?`
+
+	sqlDashboardLargest = `
+SELECT id, title, open_count, content_size, updated_at
+FROM documents
+WHERE is_deleted = 0 AND is_archived = 0
+ORDER BY content_size DESC, id
+LIMIT ?`
+)
+
+const (
+	// dashboardHeatmapDays 活动热力图覆盖的天数，含今天
+	dashboardHeatmapDays = 365
+	// dashboardTopDocuments 每个文档排行列出的数量
+	dashboardTopDocuments = 10
+)
+
+// DashboardService 工作区统计面板
+// 汇总文档、语言、活动、存储和备份状态，面板只需一次调用
+type DashboardService struct {
+	logger          *log.LogService
+	databaseService *DatabaseService
+	documentService *DocumentService
+	backupService   *BackupService
+}
+
+// NewDashboardService 创建统计面板服务实例
+func NewDashboardService(databaseService *DatabaseService, documentService *DocumentService, backupService *BackupService, logger *log.LogService) *DashboardService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &DashboardService{
+		logger:          logger,
+		databaseService: databaseService,
+		documentService: documentService,
+		backupService:   backupService,
+	}
+}
+
+// GetDashboardData 获取统计面板数据
+func (ds *DashboardService) GetDashboardData() (*models.DashboardData, error) {
+	if err := ds.documentService.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+	db := ds.databaseService.getDB()
+	now := time.Now()
+
+	data := &models.DashboardData{GeneratedAt: now}
+	counts := &data.Documents
+	if err := db.QueryRow(sqlDashboardDocumentCounts).Scan(
+		&counts.Total, &counts.Locked, &counts.Journals, &counts.Archived, &counts.Trashed, &data.Storage.ContentBytes,
+	); err != nil {
+		return nil, fmt.Errorf("failed to count documents: %w", err)
+	}
+	if err := db.QueryRow(sqlDashboardAttachments).Scan(&data.Storage.AttachmentCount, &data.Storage.AttachmentBytes); err != nil {
+		return nil, fmt.Errorf("failed to count attachments: %w", err)
+	}
+	data.Storage.DatabaseBytes = ds.databaseService.databaseSize()
+
+	var err error
+	if data.Tags, err = ds.documentService.ListTags(); err != nil {
+		return nil, err
+	}
+	if data.Languages, err = ds.languageCounts(db); err != nil {
+		return nil, err
+	}
+	if data.Activity, err = ds.activity(db, now); err != nil {
+		return nil, err
+	}
+	if data.MostOpened, err = ds.topDocuments(db, sqlDashboardMostOpened); err != nil {
+		return nil, err
+	}
+	if data.Largest, err = ds.topDocuments(db, sqlDashboardLargest); err != nil {
+		return nil, err
+	}
+	if ds.backupService != nil {
+		data.Sync = ds.backupService.syncStatus()
+	}
+	return data, nil
+}
+
+// languageCounts 按块语言统计
+func (ds *DashboardService) languageCounts(db *sql.DB) ([]models.DashboardCount, error) {
+	rows, err := db.Query(sqlDashboardLanguages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count languages: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []models.DashboardCount{}
+	for rows.Next() {
+		var count models.DashboardCount
+		if err := rows.Scan(&count.Name, &count.Documents, &count.Blocks); err != nil {
+			return nil, fmt.Errorf("failed to scan language count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// activity 最近 dashboardHeatmapDays 天的活动
+func (ds *DashboardService) activity(db *sql.DB, now time.Time) ([]models.DashboardActivityDay, error) {
+	from := now.AddDate(0, 0, 1-dashboardHeatmapDays)
+	rows, err := db.Query(sqlDashboardActivity, from.Format(time.DateOnly))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load activity: %w", err)
+	}
+	defer rows.Close()
+
+	days := map[string]models.DashboardActivityDay{}
+	for rows.Next() {
+		var day models.DashboardActivityDay
+		if err := rows.Scan(&day.Date, &day.Created, &day.Updated); err != nil {
+			return nil, fmt.Errorf("failed to scan activity: %w", err)
+		}
+		days[day.Date] = day
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return fillActivityDays(days, from, now), nil
+}
+
+// topDocuments 按给定查询列出排行前 dashboardTopDocuments 的文档
+func (ds *DashboardService) topDocuments(db *sql.DB, query string) ([]models.DashboardDocument, error) {
+	rows, err := db.Query(query, dashboardTopDocuments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list top documents: %w", err)
+	}
+	defer rows.Close()
+
+	docs := []models.DashboardDocument{}
+	for rows.Next() {
+		var doc models.DashboardDocument
+		if err := rows.Scan(&doc.ID, &doc.Title, &doc.OpenCount, &doc.ContentSize, &doc.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan top document: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+// fillActivityDays 生成 from 到 to（含）的连续日期，没有活动的日期计数为 0
+func fillActivityDays(days map[string]models.DashboardActivityDay, from, to time.Time) []models.DashboardActivityDay {
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+
+	var out []models.DashboardActivityDay
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		date := d.Format(time.DateOnly)
+		day, ok := days[date]
+		if !ok {
+			day = models.DashboardActivityDay{Date: date}
+		}
+		out = append(out, day)
+	}
+	return out
+}
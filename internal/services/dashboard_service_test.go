@@ -0,0 +1,34 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"voidraft/internal/models"
+)
+
+func TestFillActivityDays(t *testing.T) {
+	from := time.Date(2024, 2, 27, 15, 0, 0, 0, time.Local)
+	to := time.Date(2024, 3, 2, 9, 0, 0, 0, time.Local)
+	days := map[string]models.DashboardActivityDay{
+		"2024-02-29": {Date: "2024-02-29", Created: 1, Updated: 2},
+		"2024-03-02": {Date: "2024-03-02", Updated: 1},
+		"2024-01-01": {Date: "2024-01-01", Created: 5},
+	}
+
+	got := fillActivityDays(days, from, to)
+	want := []string{"2024-02-27", "2024-02-28", "2024-02-29", "2024-03-01", "2024-03-02"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d days, want %d", len(got), len(want))
+	}
+	for i, date := range want {
+		if got[i].Date != date {
+			t.Errorf("day %d = %s, want %s", i, got[i].Date, date)
+		}
+	}
+	if got[2].Created != 1 || got[2].Updated != 2 {
+		t.Errorf("2024-02-29 = %+v, want counts kept", got[2])
+	}
+	if got[3].Created != 0 || got[3].Updated != 0 {
+		t.Errorf("2024-03-01 = %+v, want zero counts", got[3])
+	}
+}
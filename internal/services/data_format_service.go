@@ -0,0 +1,30 @@
+package services
+
+import (
+	"voidraft/internal/common/dataformat"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// DataFormatService JSON/YAML/TOML 转换与校验服务
+type DataFormatService struct {
+	logger *log.LogService
+}
+
+// NewDataFormatService 创建格式转换服务实例
+func NewDataFormatService(logger *log.LogService) *DataFormatService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &DataFormatService{logger: logger}
+}
+
+// ConvertConfigFormat 在 json、yaml、toml 之间转换文本，解析错误信息中带有行列位置
+func (ds *DataFormatService) ConvertConfigFormat(text string, from dataformat.Format, to dataformat.Format) (string, error) {
+	return dataformat.Convert(text, from, to)
+}
+
+// ValidateFormat 校验文本格式，不合法时返回错误信息和行列位置
+func (ds *DataFormatService) ValidateFormat(text string, format dataformat.Format) dataformat.ValidationResult {
+	return dataformat.Validate(text, format)
+}
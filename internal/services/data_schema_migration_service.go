@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// DataMigration 一个版本化的数据迁移步骤
+// 版本号记录在 SQLite 的 user_version 中，每个步骤在独立事务中执行，失败时整步回滚
+type DataMigration struct {
+	Version     int                                         // 迁移后的数据版本，必须从 1 开始连续递增
+	Description string                                      // 迁移说明
+	Rollback    string                                      // 手动回滚说明，写入迁移结果
+	Up          func(ctx context.Context, tx *sql.Tx) error // 迁移操作，为空表示只记录版本
+}
+
+// dataMigrations 所有数据迁移步骤，新增步骤追加到末尾
+var dataMigrations = []DataMigration{
+	{
+		Version:     1,
+		Description: "Record the data layout version",
+		Rollback:    "No data was changed; older versions ignore the recorded version.",
+	},
+	{
+		Version:     2,
+		Description: "Index links between documents for backlinks",
+		Rollback:    "Run DELETE FROM document_links; older versions ignore the table.",
+		Up:          migrateDocumentLinks,
+	},
+	{
+		Version:     3,
+		Description: "Detect the natural language of prose blocks",
+		Rollback:    "Run DELETE FROM block_languages; older versions ignore the table.",
+		Up:          migrateBlockLanguages,
+	},
+}
+
+// DataMigrationStep 已执行的迁移步骤
+type DataMigrationStep struct {
+	Version     int    `json:"version"`     // 迁移后的数据版本
+	Description string `json:"description"` // 迁移说明
+	Rollback    string `json:"rollback"`    // 手动回滚说明
+}
+
+// DataMigrationResult 数据迁移结果
+type DataMigrationResult struct {
+	Migrated    bool                `json:"migrated"`    // 是否执行了迁移
+	FromVersion int                 `json:"fromVersion"` // 迁移前的数据版本
+	ToVersion   int                 `json:"toVersion"`   // 迁移后的数据版本
+	BackupPath  string              `json:"backupPath"`  // 迁移前的数据库备份
+	Steps       []DataMigrationStep `json:"steps"`       // 已执行的步骤
+	Error       string              `json:"error"`       // 失败原因
+	Rollback    string              `json:"rollback"`    // 回滚说明
+	FinishedAt  time.Time           `json:"finishedAt"`  // 完成时间
+}
+
+// DataMigrationProgress 数据迁移进度
+type DataMigrationProgress struct {
+	Current     int    `json:"current"`     // 当前步骤序号，从 1 开始
+	Total       int    `json:"total"`       // 需要执行的步骤数
+	Version     int    `json:"version"`     // 当前步骤的目标版本
+	Description string `json:"description"` // 当前步骤说明
+	Done        bool   `json:"done"`        // 是否全部完成
+}
+
+// DataMigrationService 数据库结构与数据布局迁移，与 ConfigMigrator 对应
+// 打开数据库后比较 user_version 与最新步骤，依次执行缺失的步骤，执行前备份数据库
+type DataMigrationService struct {
+	logger     *log.LogService
+	migrations []DataMigration
+
+	mu   sync.RWMutex
+	last *DataMigrationResult
+}
+
+// NewDataMigrationService 创建数据迁移服务实例
+func NewDataMigrationService(migrations []DataMigration, logger *log.LogService) *DataMigrationService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &DataMigrationService{
+		logger:     logger,
+		migrations: migrations,
+	}
+}
+
+// GetLastResult 获取最近一次迁移的结果，本次运行未迁移时返回 nil
+func (dm *DataMigrationService) GetLastResult() *DataMigrationResult {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	return dm.last
+}
+
+// LatestVersion 当前程序支持的数据版本
+func (dm *DataMigrationService) LatestVersion() int {
+	if len(dm.migrations) == 0 {
+		return 0
+	}
+	return dm.migrations[len(dm.migrations)-1].Version
+}
+
+// Migrate 执行缺失的迁移步骤，backup 在第一步之前调用，返回备份文件路径
+func (dm *DataMigrationService) Migrate(ctx context.Context, db *sql.DB, backup func(fromVersion int) (string, error)) (*DataMigrationResult, error) {
+	if err := validateDataMigrations(dm.migrations); err != nil {
+		return nil, err
+	}
+
+	var current int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&current); err != nil {
+		return nil, fmt.Errorf("read data version: %w", err)
+	}
+
+	latest := dm.LatestVersion()
+	result := &DataMigrationResult{FromVersion: current, ToVersion: current}
+	if current > latest {
+		// 数据由更新的版本写入，保持原样，由用户决定是否升级
+		dm.logger.Warning("Data version is newer than this build", "data", current, "supported", latest)
+		return result, nil
+	}
+
+	pending := pendingDataMigrations(dm.migrations, current)
+	if len(pending) == 0 {
+		return result, nil
+	}
+
+	defer func() {
+		result.FinishedAt = time.Now()
+		dm.mu.Lock()
+		dm.last = result
+		dm.mu.Unlock()
+	}()
+
+	backupPath, err := backup(current)
+	if err != nil {
+		result.Error = err.Error()
+		return result, fmt.Errorf("backup before migration: %w", err)
+	}
+	result.BackupPath = backupPath
+
+	for i, m := range pending {
+		helper.EmitEvent(constant.EVENT_DATA_MIGRATION_PROGRESS, DataMigrationProgress{
+			Current:     i + 1,
+			Total:       len(pending),
+			Version:     m.Version,
+			Description: m.Description,
+		})
+		dm.logger.Info("Applying data migration", "version", m.Version, "description", m.Description)
+
+		if err := applyDataMigration(ctx, db, m); err != nil {
+			result.Error = err.Error()
+			result.Rollback = dataRollbackInstructions(result)
+			return result, fmt.Errorf("data migration %d: %w", m.Version, err)
+		}
+		result.Migrated = true
+		result.ToVersion = m.Version
+		result.Steps = append(result.Steps, DataMigrationStep{
+			Version:     m.Version,
+			Description: m.Description,
+			Rollback:    m.Rollback,
+		})
+	}
+
+	result.Rollback = dataRollbackInstructions(result)
+	helper.EmitEvent(constant.EVENT_DATA_MIGRATION_PROGRESS, DataMigrationProgress{
+		Current: len(pending),
+		Total:   len(pending),
+		Version: result.ToVersion,
+		Done:    true,
+	})
+	return result, nil
+}
+
+// applyDataMigration 在事务中执行一个步骤并更新 user_version
+func applyDataMigration(ctx context.Context, db *sql.DB, m DataMigration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if m.Up != nil {
+		if err := m.Up(ctx, tx); err != nil {
+			return err
+		}
+	}
+	// PRAGMA 不支持参数绑定，版本号为程序内定义的整数
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", m.Version)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// validateDataMigrations 检查步骤版本从 1 开始连续递增
+func validateDataMigrations(migrations []DataMigration) error {
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return fmt.Errorf("data migration at index %d has version %d, want %d", i, m.Version, i+1)
+		}
+	}
+	return nil
+}
+
+// pendingDataMigrations 返回版本高于 current 的步骤
+func pendingDataMigrations(migrations []DataMigration, current int) []DataMigration {
+	for i, m := range migrations {
+		if m.Version > current {
+			return migrations[i:]
+		}
+	}
+	return nil
+}
+
+// dataRollbackInstructions 生成手动回滚说明
+func dataRollbackInstructions(result *DataMigrationResult) string {
+	var b strings.Builder
+	if result.BackupPath != "" {
+		fmt.Fprintf(&b, "To roll back, quit voidraft, replace the database with the backup %s, and reinstall the previous version.", result.BackupPath)
+	} else {
+		b.WriteString("No backup was created; restore the database from your own backups before downgrading.")
+	}
+	if result.Error != "" {
+		fmt.Fprintf(&b, " The failed step was rolled back; data is at version %d.", result.ToVersion)
+	}
+	for i := len(result.Steps) - 1; i >= 0; i-- {
+		step := result.Steps[i]
+		if step.Rollback != "" {
+			fmt.Fprintf(&b, "\nv%d: %s", step.Version, step.Rollback)
+		}
+	}
+	return b.String()
+}
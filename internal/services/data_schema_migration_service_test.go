@@ -0,0 +1,61 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateDataMigrations(t *testing.T) {
+	if err := validateDataMigrations(dataMigrations); err != nil {
+		t.Fatalf("registered migrations: %v", err)
+	}
+	if err := validateDataMigrations([]DataMigration{{Version: 1}, {Version: 3}}); err == nil {
+		t.Fatal("expected error for a gap in versions")
+	}
+	if err := validateDataMigrations([]DataMigration{{Version: 0}}); err == nil {
+		t.Fatal("expected error for version 0")
+	}
+}
+
+func TestPendingDataMigrations(t *testing.T) {
+	migrations := []DataMigration{{Version: 1}, {Version: 2}, {Version: 3}}
+	tests := []struct {
+		current int
+		want    int
+	}{
+		{0, 3},
+		{1, 2},
+		{3, 0},
+		{5, 0},
+	}
+	for _, tt := range tests {
+		if got := pendingDataMigrations(migrations, tt.current); len(got) != tt.want {
+			t.Errorf("pendingDataMigrations(%d) = %d steps, want %d", tt.current, len(got), tt.want)
+		}
+	}
+}
+
+func TestDataRollbackInstructions(t *testing.T) {
+	result := &DataMigrationResult{
+		BackupPath: "/data/voidraft.db.pre-migration.v1.20260101000000",
+		ToVersion:  3,
+		Steps: []DataMigrationStep{
+			{Version: 2, Rollback: "drop column a"},
+			{Version: 3, Rollback: "drop table b"},
+		},
+	}
+	got := dataRollbackInstructions(result)
+	if !strings.Contains(got, result.BackupPath) {
+		t.Errorf("instructions missing backup path: %q", got)
+	}
+	if strings.Index(got, "v3: drop table b") > strings.Index(got, "v2: drop column a") {
+		t.Errorf("steps should be listed newest first: %q", got)
+	}
+
+	result.Error = "boom"
+	result.BackupPath = ""
+	got = dataRollbackInstructions(result)
+	if !strings.Contains(got, "No backup") || !strings.Contains(got, "version 3") {
+		t.Errorf("failure instructions = %q", got)
+	}
+}
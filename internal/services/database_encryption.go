@@ -0,0 +1,550 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"voidraft/internal/common/dbcrypt"
+	"voidraft/internal/common/keystore"
+)
+
+// 加密数据库：数据目录中只保存密文 voidraft.db.enc 和口令包装后的密钥 voidraft.db.key，
+// 运行时解密到用户缓存目录下的私有工作副本，定期和退出时写回密文。
+// 这不是页级加密：应用运行期间明文工作副本一直留在本机磁盘上，异常退出后留到下次启动时恢复或清除，
+// 加密只保护数据目录（如同步盘、备份）中的文件，不防范能读取本机用户目录的人。
+// 附件不在加密范围内，仍以明文保存在数据目录的 attachments 目录下。
+// 数据密钥保存在系统钥匙串中，钥匙串中没有密钥时数据库处于锁定状态，需输入口令解锁。
+const (
+	encryptedDBName = dbName + ".enc"
+	dbKeyFileName   = dbName + ".key"
+
+	// dbCheckpointInterval 工作副本写回密文的间隔
+	dbCheckpointInterval = 5 * time.Minute
+	// dbKeyringPrefix 钥匙串中数据密钥的键名前缀
+	dbKeyringPrefix = "db-key-"
+	// dbMinPassphrase 口令最小长度
+	dbMinPassphrase = 8
+	// dbWorkDirPrefix 工作目录名前缀，后接数据目录路径的哈希
+	dbWorkDirPrefix = "dbwork-"
+	// dbWorkOwnerFile 工作目录中记录所属数据目录的文件
+	dbWorkOwnerFile = "datadir"
+)
+
+var (
+	// ErrDatabaseLocked 加密数据库尚未解锁
+	ErrDatabaseLocked = errors.New("encrypted database is locked, unlock it with the passphrase")
+	// ErrDatabaseEncrypted 数据库已加密
+	ErrDatabaseEncrypted = errors.New("database is already encrypted")
+	// ErrDatabaseNotEncrypted 数据库未加密
+	ErrDatabaseNotEncrypted = errors.New("database is not encrypted")
+)
+
+// DatabaseEncryptionStatus 数据库加密状态
+type DatabaseEncryptionStatus struct {
+	Encrypted   bool   `json:"encrypted"`   // 是否启用加密
+	Locked      bool   `json:"locked"`      // 是否等待输入口令解锁，解锁后需重启应用
+	WorkingCopy string `json:"workingCopy"` // 运行期间明文工作副本的路径，设置页据此提示明文留在本机
+	Attachments string `json:"attachments"` // 启用加密时仍以明文保存的附件目录，设置页据此提示附件未加密
+}
+
+// GetDatabaseEncryptionStatus 获取数据库加密状态
+func (ds *DatabaseService) GetDatabaseEncryptionStatus() *DatabaseEncryptionStatus {
+	ds.encMu.Lock()
+	status := &DatabaseEncryptionStatus{Encrypted: ds.encrypted, Locked: ds.encLocked, WorkingCopy: ds.workPath}
+	ds.encMu.Unlock()
+
+	if status.Encrypted {
+		if dbPath, err := ds.getDatabasePath(); err == nil {
+			status.Attachments = filepath.Join(filepath.Dir(dbPath), attachmentDirName)
+		}
+	}
+	return status
+}
+
+// EnableDatabaseEncryption 将现有的明文数据库迁移为加密数据库，完成后删除明文文件并重新打开
+func (ds *DatabaseService) EnableDatabaseEncryption(passphrase string) error {
+	if ds.getDB() == nil {
+		return errors.New("database service not available")
+	}
+	if err := ds.ensureWritable(); err != nil {
+		return err
+	}
+	if len(passphrase) < dbMinPassphrase {
+		return fmt.Errorf("passphrase must be at least %d characters", dbMinPassphrase)
+	}
+
+	ds.encMu.Lock()
+	encrypted := ds.encrypted
+	ds.encMu.Unlock()
+	if encrypted {
+		return ErrDatabaseEncrypted
+	}
+
+	dbPath, err := ds.getDatabasePath()
+	if err != nil {
+		return fmt.Errorf("failed to get database path: %w", err)
+	}
+	dataDir := filepath.Dir(dbPath)
+
+	key, err := dbcrypt.NewKey()
+	if err != nil {
+		return err
+	}
+	kf, err := dbcrypt.WrapKey(key, passphrase)
+	if err != nil {
+		return err
+	}
+
+	// 导出一致的快照后加密，快照写在私有工作目录中
+	workDir, err := databaseWorkDir(dataDir)
+	if err != nil {
+		return err
+	}
+	snapshot := filepath.Join(workDir, "migrate.db")
+	if err := dbcrypt.Shred(snapshot); err != nil {
+		return fmt.Errorf("failed to remove stale snapshot: %w", err)
+	}
+	if _, err := ds.getDB().Exec("VACUUM INTO ?", snapshot); err != nil {
+		return fmt.Errorf("failed to snapshot database: %w", err)
+	}
+	defer dbcrypt.Shred(snapshot)
+
+	// 密钥先于密文落盘：密文存在即表示迁移已完成，启动时可安全清除残留明文
+	if err := dbcrypt.WriteKeyFile(filepath.Join(dataDir, dbKeyFileName), kf); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	if err := keystore.Set(dbKeyringKey(kf), base64.StdEncoding.EncodeToString(key)); err != nil {
+		return fmt.Errorf("failed to store database key: %w", err)
+	}
+	if err := dbcrypt.EncryptFile(snapshot, filepath.Join(dataDir, encryptedDBName), key); err != nil {
+		return fmt.Errorf("failed to encrypt database: %w", err)
+	}
+
+	// 关闭明文数据库并删除，随后从密文重新打开
+	ds.stopCheckpointLoop()
+	if err := ds.getDB().Close(); err != nil {
+		return fmt.Errorf("failed to close database: %w", err)
+	}
+	for _, path := range sqliteFiles(dbPath) {
+		if err := dbcrypt.Shred(path); err != nil {
+			ds.logger.Error("Failed to remove plaintext database", "path", path, "error", err)
+		}
+	}
+
+	ds.logger.Info("Database encryption enabled")
+	ds.logger.Warning("Attachments are not covered by database encryption", "path", filepath.Join(dataDir, attachmentDirName))
+	return ds.openDatabase(dbPath, false)
+}
+
+// UnlockDatabase 使用口令解锁加密数据库，密钥写入钥匙串，重启应用后生效
+func (ds *DatabaseService) UnlockDatabase(passphrase string) error {
+	dataDir, kf, err := ds.readDatabaseKeyFile()
+	if err != nil {
+		return err
+	}
+	key, err := dbcrypt.UnwrapKey(kf, passphrase)
+	if err != nil {
+		return err
+	}
+	// 校验密钥能解开当前的密文，避免钥匙串中写入不匹配的密钥
+	if err := dbcrypt.VerifyFile(filepath.Join(dataDir, encryptedDBName), key); err != nil {
+		return err
+	}
+
+	if err := keystore.Set(dbKeyringKey(kf), base64.StdEncoding.EncodeToString(key)); err != nil {
+		return fmt.Errorf("failed to store database key: %w", err)
+	}
+	ds.logger.Info("Encrypted database unlocked, restart required")
+	return nil
+}
+
+// ChangeDatabasePassphrase 修改加密数据库口令，只重新包装数据密钥，不重新加密数据
+func (ds *DatabaseService) ChangeDatabasePassphrase(current, passphrase string) error {
+	if len(passphrase) < dbMinPassphrase {
+		return fmt.Errorf("passphrase must be at least %d characters", dbMinPassphrase)
+	}
+	dataDir, kf, err := ds.readDatabaseKeyFile()
+	if err != nil {
+		return err
+	}
+	key, err := dbcrypt.UnwrapKey(kf, current)
+	if err != nil {
+		return err
+	}
+	newKf, err := dbcrypt.WrapKey(key, passphrase)
+	if err != nil {
+		return err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(key)
+	if err := keystore.Set(dbKeyringKey(newKf), encoded); err != nil {
+		return fmt.Errorf("failed to store database key: %w", err)
+	}
+	if err := dbcrypt.WriteKeyFile(filepath.Join(dataDir, dbKeyFileName), newKf); err != nil {
+		keystore.Delete(dbKeyringKey(newKf))
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	if err := keystore.Delete(dbKeyringKey(kf)); err != nil {
+		ds.logger.Warning("Failed to remove old database key from keyring", "error", err)
+	}
+	return nil
+}
+
+// prepareEncryptedDatabase 检测加密数据库并准备工作副本，返回实际打开的路径
+func (ds *DatabaseService) prepareEncryptedDatabase(dbPath string, readOnly bool) (string, error) {
+	dataDir := filepath.Dir(dbPath)
+	if !readOnly {
+		ds.wipeStaleWorkDirs(dataDir)
+	}
+	encPath := filepath.Join(dataDir, encryptedDBName)
+	encInfo, err := os.Stat(encPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return dbPath, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to stat encrypted database: %w", err)
+	}
+
+	ds.encMu.Lock()
+	defer ds.encMu.Unlock()
+	ds.encrypted = true
+	ds.encLocked = false
+
+	// 迁移中断时可能残留明文数据库，密文已完整写入，直接清除
+	if !readOnly {
+		for _, path := range sqliteFiles(dbPath) {
+			if err := dbcrypt.Shred(path); err != nil {
+				ds.logger.Error("Failed to remove plaintext database", "path", path, "error", err)
+			}
+		}
+	}
+
+	kf, err := dbcrypt.ReadKeyFile(filepath.Join(dataDir, dbKeyFileName))
+	if err != nil {
+		return "", fmt.Errorf("failed to read database key file: %w", err)
+	}
+	key, err := loadDatabaseKey(kf)
+	if errors.Is(err, keystore.ErrNotFound) {
+		// 未解锁时使用空的内存数据库，保证其他服务正常启动
+		ds.encLocked = true
+		ds.logger.Warning("Encrypted database is locked, waiting for passphrase")
+		return ":memory:", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load database key: %w", err)
+	}
+	ds.dbKey = key
+
+	// 只读实例使用独立的副本，不写回密文
+	// 副本目录不记录所属数据目录，异常退出后由下次启动的可写实例清除
+	if readOnly {
+		root, err := databaseWorkRoot()
+		if err != nil {
+			return "", err
+		}
+		if err := os.MkdirAll(root, 0700); err != nil {
+			return "", fmt.Errorf("failed to create working directory: %w", err)
+		}
+		dir, err := os.MkdirTemp(root, dbWorkDirPrefix+"ro-*")
+		if err != nil {
+			return "", fmt.Errorf("failed to create working directory: %w", err)
+		}
+		ds.workPath = filepath.Join(dir, dbName)
+		if err := dbcrypt.DecryptFile(encPath, ds.workPath, key); err != nil {
+			return "", fmt.Errorf("failed to decrypt database: %w", err)
+		}
+		return ds.workPath, nil
+	}
+
+	workDir, err := databaseWorkDir(dataDir)
+	if err != nil {
+		return "", err
+	}
+	ds.workPath = filepath.Join(workDir, dbName)
+
+	// 工作副本比密文新说明上次未正常退出，继续使用工作副本避免丢失数据
+	// 最后一次检查点之后的修改可能只在 WAL 中，按数据库及其附属文件中最新的修改时间比较
+	if _, err := os.Stat(ds.workPath); err == nil && latestModTime(sqliteFiles(ds.workPath)).After(encInfo.ModTime()) {
+		ds.logger.Warning("Recovering encrypted database from working copy")
+		return ds.workPath, nil
+	}
+	for _, path := range sqliteFiles(ds.workPath) {
+		if err := dbcrypt.Shred(path); err != nil {
+			return "", fmt.Errorf("failed to remove stale working copy: %w", err)
+		}
+	}
+	if err := dbcrypt.DecryptFile(encPath, ds.workPath, key); err != nil {
+		return "", fmt.Errorf("failed to decrypt database: %w", err)
+	}
+	return ds.workPath, nil
+}
+
+// startCheckpoint 启动定期写回密文
+func (ds *DatabaseService) startCheckpoint() {
+	ds.encMu.Lock()
+	defer ds.encMu.Unlock()
+
+	if !ds.encrypted || ds.encLocked || ds.dbKey == nil || ds.stopCheckpoint != nil || ds.IsReadOnly() {
+		return
+	}
+	ds.stopCheckpoint = make(chan struct{})
+	go ds.checkpointLoop(ds.stopCheckpoint)
+}
+
+// stopCheckpointLoop 停止定期写回
+func (ds *DatabaseService) stopCheckpointLoop() {
+	ds.encMu.Lock()
+	defer ds.encMu.Unlock()
+
+	if ds.stopCheckpoint != nil {
+		close(ds.stopCheckpoint)
+		ds.stopCheckpoint = nil
+	}
+}
+
+// checkpointLoop 定期将工作副本快照加密写回数据目录
+func (ds *DatabaseService) checkpointLoop(stop chan struct{}) {
+	ticker := time.NewTicker(dbCheckpointInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := ds.checkpoint(); err != nil {
+				ds.logger.Error("Failed to checkpoint encrypted database", "error", err)
+			}
+		}
+	}
+}
+
+// checkpoint 导出快照并加密写回
+func (ds *DatabaseService) checkpoint() error {
+	if err := ds.ensureWritable(); err != nil {
+		return err
+	}
+	dbPath, err := ds.getDatabasePath()
+	if err != nil {
+		return err
+	}
+
+	snapshot := ds.workPath + ".snapshot"
+	if err := dbcrypt.Shred(snapshot); err != nil {
+		return err
+	}
+	defer dbcrypt.Shred(snapshot)
+	if _, err := ds.getDB().Exec("VACUUM INTO ?", snapshot); err != nil {
+		return fmt.Errorf("failed to snapshot database: %w", err)
+	}
+	return dbcrypt.EncryptFile(snapshot, filepath.Join(filepath.Dir(dbPath), encryptedDBName), ds.dbKey)
+}
+
+// backupBeforeMigration 数据迁移前在数据目录中备份数据库，加密数据库的备份同样加密
+func (ds *DatabaseService) backupBeforeMigration(dbPath string, fromVersion int) (string, error) {
+	name := fmt.Sprintf("%s.pre-migration.v%d.%s", dbName, fromVersion, time.Now().Format("20060102150405"))
+	backupPath := filepath.Join(filepath.Dir(dbPath), name)
+	if !ds.encrypted {
+		if _, err := ds.getDB().Exec("VACUUM INTO ?", backupPath); err != nil {
+			return "", fmt.Errorf("failed to back up database: %w", err)
+		}
+		return backupPath, nil
+	}
+
+	snapshot := ds.workPath + ".snapshot"
+	if err := dbcrypt.Shred(snapshot); err != nil {
+		return "", err
+	}
+	defer dbcrypt.Shred(snapshot)
+	if _, err := ds.getDB().Exec("VACUUM INTO ?", snapshot); err != nil {
+		return "", fmt.Errorf("failed to snapshot database: %w", err)
+	}
+	backupPath += ".enc"
+	if err := dbcrypt.EncryptFile(snapshot, backupPath, ds.dbKey); err != nil {
+		return "", err
+	}
+	return backupPath, nil
+}
+
+// sealWorkingCopy 数据库关闭后加密写回并删除明文工作副本
+func (ds *DatabaseService) sealWorkingCopy() error {
+	ds.encMu.Lock()
+	defer ds.encMu.Unlock()
+
+	if !ds.encrypted || ds.workPath == "" {
+		return nil
+	}
+
+	var err error
+	if !ds.IsReadOnly() {
+		dbPath, pathErr := ds.getDatabasePath()
+		if pathErr != nil {
+			return pathErr
+		}
+		err = dbcrypt.EncryptFile(ds.workPath, filepath.Join(filepath.Dir(dbPath), encryptedDBName), ds.dbKey)
+		if err != nil {
+			// 写回失败时保留工作副本，下次启动时恢复
+			return fmt.Errorf("failed to encrypt database: %w", err)
+		}
+	}
+
+	for _, path := range sqliteFiles(ds.workPath) {
+		if shredErr := dbcrypt.Shred(path); shredErr != nil {
+			ds.logger.Error("Failed to remove working copy", "path", path, "error", shredErr)
+		}
+	}
+	// 只读实例的副本目录只属于本实例
+	if ds.IsReadOnly() {
+		os.Remove(filepath.Dir(ds.workPath))
+	}
+	ds.workPath = ""
+	return err
+}
+
+// isEncryptionLocked 加密数据库是否等待解锁
+func (ds *DatabaseService) isEncryptionLocked() bool {
+	ds.encMu.Lock()
+	defer ds.encMu.Unlock()
+	return ds.encLocked
+}
+
+// readDatabaseKeyFile 读取数据目录中的密钥文件
+func (ds *DatabaseService) readDatabaseKeyFile() (string, *dbcrypt.KeyFile, error) {
+	dbPath, err := ds.getDatabasePath()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get database path: %w", err)
+	}
+	dataDir := filepath.Dir(dbPath)
+	kf, err := dbcrypt.ReadKeyFile(filepath.Join(dataDir, dbKeyFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil, ErrDatabaseNotEncrypted
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read database key file: %w", err)
+	}
+	return dataDir, kf, nil
+}
+
+// loadDatabaseKey 从钥匙串读取数据密钥
+func loadDatabaseKey(kf *dbcrypt.KeyFile) ([]byte, error) {
+	encoded, err := keystore.Get(dbKeyringKey(kf))
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != dbcrypt.KeySize {
+		return nil, errors.New("invalid database key in keyring")
+	}
+	return key, nil
+}
+
+// dbKeyringKey 钥匙串键名，由密钥文件的盐值派生，数据目录移动后仍可找到
+func dbKeyringKey(kf *dbcrypt.KeyFile) string {
+	sum := sha256.Sum256(kf.Salt)
+	return dbKeyringPrefix + hex.EncodeToString(sum[:8])
+}
+
+// databaseWorkDir 明文工作副本所在的私有目录，位于用户缓存目录而非可能被同步的数据目录
+func databaseWorkDir(dataDir string) (string, error) {
+	root, err := databaseWorkRoot()
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(dataDir)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(root, databaseWorkDirName(abs))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create working directory: %w", err)
+	}
+	// 记录所属数据目录，供其他数据目录启动时判断是否为遗留的工作副本
+	if err := os.WriteFile(filepath.Join(dir, dbWorkOwnerFile), []byte(abs), 0600); err != nil {
+		return "", fmt.Errorf("failed to write working directory owner: %w", err)
+	}
+	return dir, nil
+}
+
+// databaseWorkDirName 数据目录对应的工作目录名，abs 为数据目录的绝对路径
+func databaseWorkDirName(abs string) string {
+	sum := sha256.Sum256([]byte(abs))
+	return dbWorkDirPrefix + hex.EncodeToString(sum[:8])
+}
+
+// databaseWorkRoot 全部工作目录的上级目录
+func databaseWorkRoot() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get cache directory: %w", err)
+	}
+	return filepath.Join(cacheDir, "voidraft"), nil
+}
+
+// wipeStaleWorkDirs 清除遗留的明文工作副本：所属数据目录已没有加密数据库，或无法确定所属数据目录（如只读实例的副本）
+// 所属数据目录仍有加密数据库时，工作副本可能属于正在运行的其他实例，留给该数据目录启动时恢复或清除
+func (ds *DatabaseService) wipeStaleWorkDirs(dataDir string) {
+	root, err := databaseWorkRoot()
+	if err != nil {
+		return
+	}
+	abs, err := filepath.Abs(dataDir)
+	if err != nil {
+		return
+	}
+	// 当前数据目录的工作副本可能需要恢复，由 prepareEncryptedDatabase 处理
+	current := databaseWorkDirName(abs)
+	dirs, _ := filepath.Glob(filepath.Join(root, dbWorkDirPrefix+"*"))
+	for _, dir := range dirs {
+		if filepath.Base(dir) == current {
+			continue
+		}
+		owner, err := os.ReadFile(filepath.Join(dir, dbWorkOwnerFile))
+		if err == nil {
+			if _, err := os.Stat(filepath.Join(string(owner), encryptedDBName)); err == nil {
+				continue
+			}
+		}
+		if err := shredDir(dir); err != nil {
+			ds.logger.Error("Failed to remove stale working copy", "dir", dir, "error", err)
+			continue
+		}
+		ds.logger.Info("Removed stale plaintext working copy", "dir", dir)
+	}
+}
+
+// shredDir 覆写并删除目录中的文件，再删除目录
+func shredDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			if err := dbcrypt.Shred(filepath.Join(dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return os.RemoveAll(dir)
+}
+
+// latestModTime 文件中最新的修改时间，不存在的文件忽略
+func latestModTime(paths []string) time.Time {
+	var latest time.Time
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+// sqliteFiles 数据库文件及其 WAL 附属文件
+func sqliteFiles(path string) []string {
+	return []string{path, path + "-wal", path + "-shm"}
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLatestModTime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), dbName)
+	if got := latestModTime(sqliteFiles(path)); !got.IsZero() {
+		t.Errorf("latestModTime of missing files = %v, want zero", got)
+	}
+
+	checkpoint := time.Now().Add(-time.Hour)
+	for _, file := range sqliteFiles(path) {
+		if err := os.WriteFile(file, []byte("x"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(file, checkpoint.Add(-time.Hour), checkpoint.Add(-time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 检查点之后的修改只写入了 WAL
+	walTime := checkpoint.Add(time.Minute)
+	if err := os.Chtimes(path+"-wal", walTime, walTime); err != nil {
+		t.Fatal(err)
+	}
+
+	got := latestModTime(sqliteFiles(path))
+	if !got.Equal(walTime) {
+		t.Errorf("latestModTime = %v, want %v", got, walTime)
+	}
+	if !got.After(checkpoint) {
+		t.Error("working copy with newer WAL should be recovered")
+	}
+}
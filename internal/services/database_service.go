@@ -3,11 +3,17 @@ package services
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
 	"sync"
+	"sync/atomic"
+	"time"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/datalock"
+	"voidraft/internal/common/helper"
 	"voidraft/internal/models"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
@@ -18,6 +24,11 @@ import (
 const (
 	dbName = "voidraft.db"
 
+	// dataLockHeartbeat 数据目录锁心跳间隔
+	dataLockHeartbeat = 30 * time.Second
+	// dataLockStaleAfter 超过该时间未更新心跳的锁视为失效，需考虑同步盘的同步延迟
+	dataLockStaleAfter = 5 * time.Minute
+
 	// SQLite performance optimization settings
 	sqlOptimizationSettings = `
 PRAGMA journal_mode = WAL;
@@ -35,7 +46,22 @@ CREATE TABLE IF NOT EXISTS documents (
     created_at TEXT NOT NULL,
     updated_at TEXT NOT NULL,
     is_deleted INTEGER DEFAULT 0,
-    is_locked INTEGER DEFAULT 0
+    is_locked INTEGER DEFAULT 0,
+    open_count INTEGER DEFAULT 0,
+    last_opened_at TEXT DEFAULT '',
+    preview TEXT DEFAULT '',
+    content_size INTEGER DEFAULT 0,
+    kind TEXT DEFAULT '',
+    journal_period TEXT DEFAULT '',
+    is_archived INTEGER DEFAULT 0,
+    deleted_at TEXT DEFAULT '',
+    collection_id INTEGER DEFAULT 0,
+    is_pinned INTEGER DEFAULT 0,
+    sort_order INTEGER DEFAULT 0,
+    title_auto INTEGER DEFAULT 0,
+    is_favorite INTEGER DEFAULT 0,
+    color_label TEXT DEFAULT '',
+    icon TEXT DEFAULT ''
 )`
 
 	// Extensions table
@@ -73,6 +99,140 @@ CREATE TABLE IF NOT EXISTS themes (
     created_at TEXT NOT NULL,
     updated_at TEXT NOT NULL
 )`
+
+	// Time entries table
+	sqlCreateTimeEntriesTable = `
+CREATE TABLE IF NOT EXISTS time_entries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    document_id INTEGER NOT NULL,
+    started_at TEXT NOT NULL,
+    ended_at TEXT NOT NULL DEFAULT '',
+    duration INTEGER NOT NULL DEFAULT 0,
+    note TEXT NOT NULL DEFAULT ''
+)`
+
+	// Full-text search index, one row per document block.
+	// rowid = document_id << 20 | block_index, so a document's rows can be removed by range.
+	// The trigram tokenizer matches substrings, so text without spaces such as Chinese or Japanese can be searched
+	sqlCreateSearchBlocksTable = `
+CREATE VIRTUAL TABLE IF NOT EXISTS search_blocks USING fts5(
+    title,
+    content,
+    document_id UNINDEXED,
+    block_index UNINDEXED,
+    language UNINDEXED,
+    tokenize = 'trigram remove_diacritics 1'
+)`
+
+	// API tokens table
+	sqlCreateApiTokensTable = `
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    scope TEXT NOT NULL,
+    rate_limit INTEGER NOT NULL DEFAULT 60,
+    created_at TEXT NOT NULL,
+    last_used_at TEXT NOT NULL DEFAULT ''
+)`
+
+	// API audit log table
+	sqlCreateApiAuditLogTable = `
+CREATE TABLE IF NOT EXISTS api_audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token_id INTEGER NOT NULL DEFAULT 0,
+    token_name TEXT NOT NULL DEFAULT '',
+    method TEXT NOT NULL,
+    path TEXT NOT NULL,
+    status INTEGER NOT NULL,
+    remote_addr TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+)`
+
+	// Background jobs history table
+	sqlCreateJobsTable = `
+CREATE TABLE IF NOT EXISTS jobs (
+    id INTEGER PRIMARY KEY,
+    kind TEXT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    progress REAL NOT NULL DEFAULT 0,
+    message TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    result TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    started_at TEXT NOT NULL DEFAULT '',
+    finished_at TEXT NOT NULL DEFAULT ''
+)`
+
+	// Attachments table
+	sqlCreateAttachmentsTable = `
+CREATE TABLE IF NOT EXISTS attachments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    document_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    mime_type TEXT NOT NULL,
+    size INTEGER NOT NULL DEFAULT 0,
+    hash TEXT NOT NULL,
+    created_at TEXT NOT NULL
+)`
+
+	// Tags table
+	sqlCreateTagsTable = `
+CREATE TABLE IF NOT EXISTS tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    created_at TEXT NOT NULL
+)`
+
+	// Collections table, parent_id = 0 for top-level folders
+	sqlCreateCollectionsTable = `
+CREATE TABLE IF NOT EXISTS collections (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    parent_id INTEGER NOT NULL DEFAULT 0,
+    name TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+)`
+
+	// Document tags table
+	sqlCreateDocumentTagsTable = `
+CREATE TABLE IF NOT EXISTS document_tags (
+    document_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    PRIMARY KEY (document_id, tag_id)
+)`
+
+	// Links between documents, target_id = 0 for [[title]] links which are resolved by title when queried
+	sqlCreateDocumentLinksTable = `
+CREATE TABLE IF NOT EXISTS document_links (
+    source_id INTEGER NOT NULL,
+    target_id INTEGER NOT NULL DEFAULT 0,
+    target_title TEXT NOT NULL DEFAULT '' COLLATE NOCASE,
+    PRIMARY KEY (source_id, target_id, target_title)
+)`
+
+	// Detected natural language of prose blocks, maintained with the search index
+	sqlCreateBlockLanguagesTable = `
+CREATE TABLE IF NOT EXISTS block_languages (
+    document_id INTEGER NOT NULL,
+    block_index INTEGER NOT NULL,
+    language TEXT NOT NULL,
+    PRIMARY KEY (document_id, block_index)
+)`
+
+	// Document version history, written by scheduled snapshots
+	sqlCreateDocumentVersionsTable = `
+CREATE TABLE IF NOT EXISTS document_versions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    document_id INTEGER NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL DEFAULT '',
+    content_size INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL,
+    pinned INTEGER NOT NULL DEFAULT 0
+)`
 )
 
 // ColumnInfo 存储列的信息
@@ -91,13 +251,42 @@ type TableModel struct {
 type DatabaseService struct {
 	configService *ConfigService
 	logger        *log.LogService
-	db            *sql.DB
+	db            atomic.Pointer[sql.DB] // 当前连接池，锁被接管后切换为只读连接池，通过 getDB 读取
+	openPath      string                 // 当前打开的数据库文件，加密时为工作副本
 	mu            sync.RWMutex
 	ctx           context.Context
 	tableModels   []TableModel // 注册的表模型
 
+	// 版本化数据迁移
+	dataMigrations *DataMigrationService
+
 	// 配置观察者取消函数
 	cancelObserver CancelFunc
+
+	// 数据目录锁，多个实例共享同步目录时检测并发写入
+	lockMu        sync.RWMutex
+	dataLock      *datalock.Lock
+	readOnly      bool
+	lockHolder    *datalock.Holder
+	stopHeartbeat chan struct{}
+
+	// 数据库加密状态，见 database_encryption.go
+	encMu          sync.Mutex
+	encrypted      bool
+	encLocked      bool
+	dbKey          []byte
+	workPath       string
+	stopCheckpoint chan struct{}
+}
+
+// ErrReadOnly 数据目录被其他实例占用，当前实例只读
+var ErrReadOnly = errors.New("database is read-only because another instance is using the data directory")
+
+// DataLockStatus 数据目录锁状态
+type DataLockStatus struct {
+	ReadOnly bool             `json:"readOnly"` // 当前实例是否只读
+	Reason   string           `json:"reason"`   // 只读原因
+	Holder   *datalock.Holder `json:"holder"`   // 占用数据目录的其他实例
 }
 
 // NewDatabaseService creates a new database service
@@ -107,8 +296,9 @@ func NewDatabaseService(configService *ConfigService, logger *log.LogService) *D
 	}
 
 	ds := &DatabaseService{
-		configService: configService,
-		logger:        logger,
+		configService:  configService,
+		logger:         logger,
+		dataMigrations: NewDataMigrationService(dataMigrations, logger),
 	}
 
 	// 注册所有模型
@@ -127,6 +317,22 @@ func (ds *DatabaseService) registerAllModels() {
 	ds.RegisterModel("key_bindings", &models.KeyBinding{})
 	// 主题表
 	ds.RegisterModel("themes", &models.Theme{})
+	// 计时记录表
+	ds.RegisterModel("time_entries", &models.TimeEntry{})
+	// API令牌表
+	ds.RegisterModel("api_tokens", &models.ApiToken{})
+	// API审计表
+	ds.RegisterModel("api_audit_log", &models.ApiAuditEntry{})
+	// 后台任务表
+	ds.RegisterModel("jobs", &models.Job{})
+	// 附件表
+	ds.RegisterModel("attachments", &models.Attachment{})
+	// 标签表
+	ds.RegisterModel("tags", &models.Tag{})
+	// 文件夹表
+	ds.RegisterModel("collections", &models.Collection{})
+	// 文档历史版本表
+	ds.RegisterModel("document_versions", &models.DocumentVersion{})
 }
 
 // ServiceStartup initializes the service when the application starts
@@ -148,39 +354,225 @@ func (ds *DatabaseService) initDatabase() error {
 		return fmt.Errorf("failed to create database directory: %w", err)
 	}
 
+	// 获取数据目录锁，被其他实例占用时以只读方式打开
+	readOnly, err := ds.acquireDataLock(dbDir)
+	if err != nil {
+		return err
+	}
+
+	return ds.openDatabase(dbPath, readOnly)
+}
+
+// openDatabase 打开数据库并同步表结构，加密数据库先解密到工作副本
+func (ds *DatabaseService) openDatabase(dbPath string, readOnly bool) error {
+	openPath, err := ds.prepareEncryptedDatabase(dbPath, readOnly)
+	if err != nil {
+		return err
+	}
+
+	// 新建的数据库无需在迁移前备份
+	_, statErr := os.Stat(openPath)
+	existed := statErr == nil
+
 	// 打开数据库连接
-	ds.db, err = sql.Open("sqlite", dbPath)
+	dsn := openPath
+	if readOnly {
+		dsn += "?_pragma=query_only(1)"
+	}
+	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
+	if openPath == ":memory:" {
+		// 内存数据库每个连接独立，限制为单连接
+		db.SetMaxOpenConns(1)
+	}
+	ds.db.Store(db)
+	ds.openPath = openPath
 
 	// 测试连接
-	if err := ds.db.Ping(); err != nil {
+	if err := ds.getDB().Ping(); err != nil {
 		return fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	// 只读实例不修改表结构，由持有锁的实例负责
+	if readOnly {
+		return nil
+	}
+
 	// 应用性能优化设置
-	if _, err := ds.db.Exec(sqlOptimizationSettings); err != nil {
+	if _, err := ds.getDB().Exec(sqlOptimizationSettings); err != nil {
 		return fmt.Errorf("failed to apply optimization settings: %w", err)
 	}
 
-	// 创建表和索引
+	// 创建表
 	if err := ds.createTables(); err != nil {
 		return fmt.Errorf("failed to create tables: %w", err)
 	}
 
+	// 执行模型与表结构同步，需在创建索引前完成，以便索引可以引用新增的列
+	if err := ds.syncAllModelTables(); err != nil {
+		return fmt.Errorf("failed to sync model tables: %w", err)
+	}
+
+	// 执行版本化数据迁移，锁定的加密数据库使用临时内存库，解锁重新打开后再迁移
+	if openPath != ":memory:" {
+		backup := func(fromVersion int) (string, error) {
+			if !existed {
+				return "", nil
+			}
+			return ds.backupBeforeMigration(dbPath, fromVersion)
+		}
+		if _, err := ds.dataMigrations.Migrate(context.Background(), ds.getDB(), backup); err != nil {
+			return fmt.Errorf("failed to migrate data: %w", err)
+		}
+	}
+
 	if err := ds.createIndexes(); err != nil {
 		return fmt.Errorf("failed to create indexes: %w", err)
 	}
 
-	// 执行模型与表结构同步
-	if err := ds.syncAllModelTables(); err != nil {
-		return fmt.Errorf("failed to sync model tables: %w", err)
+	ds.startCheckpoint()
+	return nil
+}
+
+// acquireDataLock 获取数据目录锁并启动心跳，返回是否只读
+func (ds *DatabaseService) acquireDataLock(dir string) (bool, error) {
+	lock, holder, err := datalock.Acquire(dir, dataLockStaleAfter)
+	if err != nil && !errors.Is(err, datalock.ErrLocked) {
+		return false, fmt.Errorf("failed to acquire data directory lock: %w", err)
 	}
 
+	ds.lockMu.Lock()
+	defer ds.lockMu.Unlock()
+
+	ds.dataLock = lock
+	ds.lockHolder = holder
+	ds.readOnly = lock == nil
+	if ds.readOnly {
+		ds.logger.Warning("Data directory is in use by another instance, opening read-only",
+			"hostname", holder.Hostname, "pid", holder.PID, "heartbeat", holder.Heartbeat)
+		helper.EmitEvent(constant.EVENT_DATA_READ_ONLY, ds.statusLocked())
+		return true, nil
+	}
+
+	ds.stopHeartbeat = make(chan struct{})
+	go ds.heartbeat(lock, ds.stopHeartbeat)
+	return false, nil
+}
+
+// heartbeat 定期刷新锁，发现被其他实例接管时切换为只读
+func (ds *DatabaseService) heartbeat(lock *datalock.Lock, stop chan struct{}) {
+	ticker := time.NewTicker(dataLockHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			holder, err := lock.Refresh()
+			if errors.Is(err, datalock.ErrLost) {
+				ds.lockMu.Lock()
+				ds.readOnly = true
+				ds.lockHolder = holder
+				status := ds.statusLocked()
+				ds.lockMu.Unlock()
+
+				ds.logger.Error("Data directory lock was taken over, switching to read-only",
+					"hostname", holder.Hostname, "pid", holder.PID)
+				ds.stopCheckpointLoop()
+				if err := ds.reopenReadOnly(); err != nil {
+					ds.logger.Error("Failed to reopen database read-only", "error", err)
+				}
+				helper.EmitEvent(constant.EVENT_DATA_READ_ONLY, status)
+				return
+			}
+			if err != nil {
+				ds.logger.Error("Failed to refresh data directory lock", "error", err)
+			}
+		}
+	}
+}
+
+// reopenReadOnly 锁被接管后以只读方式重新打开数据库，切换后再关闭原连接池
+// 原连接池关闭时等待进行中的查询完成，之后的查询都使用只读连接池，不会覆盖新持有者的数据
+func (ds *DatabaseService) reopenReadOnly() error {
+	if ds.getDB() == nil || ds.openPath == "" || ds.openPath == ":memory:" {
+		return nil
+	}
+	db, err := sql.Open("sqlite", ds.openPath+"?_pragma=query_only(1)")
+	if err != nil {
+		return err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return err
+	}
+	return ds.db.Swap(db).Close()
+}
+
+// getDB 当前的数据库连接池，未打开时为 nil
+func (ds *DatabaseService) getDB() *sql.DB {
+	return ds.db.Load()
+}
+
+// GetDataLockStatus 获取数据目录锁状态
+func (ds *DatabaseService) GetDataLockStatus() *DataLockStatus {
+	ds.lockMu.RLock()
+	defer ds.lockMu.RUnlock()
+	return ds.statusLocked()
+}
+
+// IsReadOnly 当前实例是否只读
+func (ds *DatabaseService) IsReadOnly() bool {
+	ds.lockMu.RLock()
+	defer ds.lockMu.RUnlock()
+	return ds.readOnly
+}
+
+// ensureWritable 写入前检查，只读时返回 ErrReadOnly，加密数据库未解锁时返回 ErrDatabaseLocked
+func (ds *DatabaseService) ensureWritable() error {
+	if ds == nil {
+		return nil
+	}
+	if ds.IsReadOnly() {
+		return ErrReadOnly
+	}
+	if ds.isEncryptionLocked() {
+		return ErrDatabaseLocked
+	}
 	return nil
 }
 
+// statusLocked 生成锁状态，调用方需持有 lockMu
+func (ds *DatabaseService) statusLocked() *DataLockStatus {
+	status := &DataLockStatus{ReadOnly: ds.readOnly, Holder: ds.lockHolder}
+	if ds.readOnly && ds.lockHolder != nil {
+		status.Reason = fmt.Sprintf("The data directory is being used by voidraft on %s (pid %d, last seen %s). "+
+			"This window is read-only to avoid corrupting the database; close the other instance and restart to edit.",
+			ds.lockHolder.Hostname, ds.lockHolder.PID, ds.lockHolder.Heartbeat)
+	}
+	return status
+}
+
+// releaseDataLock 停止心跳并释放锁
+func (ds *DatabaseService) releaseDataLock() {
+	ds.lockMu.Lock()
+	defer ds.lockMu.Unlock()
+
+	if ds.stopHeartbeat != nil {
+		close(ds.stopHeartbeat)
+		ds.stopHeartbeat = nil
+	}
+	if ds.dataLock != nil && !ds.readOnly {
+		if err := ds.dataLock.Release(); err != nil {
+			ds.logger.Error("Failed to release data directory lock", "error", err)
+		}
+	}
+	ds.dataLock = nil
+}
+
 // getDatabasePath gets the database file path
 func (ds *DatabaseService) getDatabasePath() (string, error) {
 	config, err := ds.configService.GetConfig()
@@ -197,10 +589,22 @@ func (ds *DatabaseService) createTables() error {
 		sqlCreateExtensionsTable,
 		sqlCreateKeyBindingsTable,
 		sqlCreateThemesTable,
+		sqlCreateTimeEntriesTable,
+		sqlCreateApiTokensTable,
+		sqlCreateApiAuditLogTable,
+		sqlCreateJobsTable,
+		sqlCreateAttachmentsTable,
+		sqlCreateTagsTable,
+		sqlCreateCollectionsTable,
+		sqlCreateDocumentTagsTable,
+		sqlCreateDocumentLinksTable,
+		sqlCreateBlockLanguagesTable,
+		sqlCreateDocumentVersionsTable,
+		sqlCreateSearchBlocksTable,
 	}
 
 	for _, table := range tables {
-		if _, err := ds.db.Exec(table); err != nil {
+		if _, err := ds.getDB().Exec(table); err != nil {
 			return err
 		}
 	}
@@ -214,6 +618,11 @@ func (ds *DatabaseService) createIndexes() error {
 		`CREATE INDEX IF NOT EXISTS idx_documents_updated_at ON documents(updated_at DESC)`,
 		`CREATE INDEX IF NOT EXISTS idx_documents_title ON documents(title)`,
 		`CREATE INDEX IF NOT EXISTS idx_documents_is_deleted ON documents(is_deleted)`,
+		`CREATE INDEX IF NOT EXISTS idx_documents_created_at ON documents(created_at DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_documents_last_opened_at ON documents(last_opened_at DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_documents_content_size ON documents(content_size DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_documents_collection_id ON documents(collection_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_documents_sort_order ON documents(is_pinned DESC, sort_order)`,
 		// Extensions indexes
 		`CREATE INDEX IF NOT EXISTS idx_extensions_enabled ON extensions(enabled)`,
 		// Key bindings indexes
@@ -223,10 +632,33 @@ func (ds *DatabaseService) createIndexes() error {
 		// Themes indexes
 		`CREATE INDEX IF NOT EXISTS idx_themes_type ON themes(type)`,
 		`CREATE INDEX IF NOT EXISTS idx_themes_is_default ON themes(is_default)`,
+		// Time entries indexes
+		`CREATE INDEX IF NOT EXISTS idx_time_entries_document_id ON time_entries(document_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_time_entries_started_at ON time_entries(started_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_time_entries_ended_at ON time_entries(ended_at)`,
+		// API audit log indexes
+		`CREATE INDEX IF NOT EXISTS idx_api_audit_log_created_at ON api_audit_log(created_at DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_api_audit_log_token_id ON api_audit_log(token_id)`,
+		// Jobs indexes
+		`CREATE INDEX IF NOT EXISTS idx_jobs_created_at ON jobs(created_at DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status)`,
+		// Attachments indexes
+		`CREATE INDEX IF NOT EXISTS idx_attachments_document_id ON attachments(document_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_attachments_hash ON attachments(hash)`,
+		// Collections indexes
+		`CREATE INDEX IF NOT EXISTS idx_collections_parent_id ON collections(parent_id)`,
+		// Document tags indexes
+		`CREATE INDEX IF NOT EXISTS idx_document_tags_tag_id ON document_tags(tag_id)`,
+		// Document links indexes
+		`CREATE INDEX IF NOT EXISTS idx_document_links_target_id ON document_links(target_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_document_links_target_title ON document_links(target_title)`,
+		`CREATE INDEX IF NOT EXISTS idx_block_languages_language ON block_languages(language)`,
+		// Document versions indexes
+		`CREATE INDEX IF NOT EXISTS idx_document_versions_document_id ON document_versions(document_id, created_at DESC)`,
 	}
 
 	for _, index := range indexes {
-		if _, err := ds.db.Exec(index); err != nil {
+		if _, err := ds.getDB().Exec(index); err != nil {
 			return err
 		}
 	}
@@ -274,7 +706,7 @@ func (ds *DatabaseService) syncModelTable(tableName string, model interface{}) e
 			// 执行添加列的SQL
 			alterSQL := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s DEFAULT %s",
 				tableName, colName, colInfo.SQLType, colInfo.DefaultValue)
-			if _, err := ds.db.Exec(alterSQL); err != nil {
+			if _, err := ds.getDB().Exec(alterSQL); err != nil {
 				return fmt.Errorf("failed to add column %s: %w", colName, err)
 			}
 		}
@@ -286,7 +718,7 @@ func (ds *DatabaseService) syncModelTable(tableName string, model interface{}) e
 // getTableColumns 获取表的列信息
 func (ds *DatabaseService) getTableColumns(table string) (map[string]string, error) {
 	query := fmt.Sprintf("PRAGMA table_info(%s)", table)
-	rows, err := ds.db.Query(query)
+	rows, err := ds.getDB().Query(query)
 	if err != nil {
 		return nil, err
 	}
@@ -374,8 +806,33 @@ func (ds *DatabaseService) ServiceShutdown() error {
 		ds.cancelObserver()
 	}
 
-	if ds.db != nil {
-		return ds.db.Close()
+	defer ds.releaseDataLock()
+
+	if ds.getDB() == nil {
+		return nil
 	}
-	return nil
+	ds.stopCheckpointLoop()
+	if err := ds.getDB().Close(); err != nil {
+		return err
+	}
+	// 加密数据库关闭后写回密文并清除明文工作副本
+	return ds.sealWorkingCopy()
+}
+
+// databaseSize 数据库文件占用的字节数，加密时统计解密后的工作副本
+func (ds *DatabaseService) databaseSize() int64 {
+	path := ds.workPath
+	if path == "" {
+		var err error
+		if path, err = ds.getDatabasePath(); err != nil {
+			return 0
+		}
+	}
+	var size int64
+	for _, file := range sqliteFiles(path) {
+		if info, err := os.Stat(file); err == nil {
+			size += info.Size()
+		}
+	}
+	return size
 }
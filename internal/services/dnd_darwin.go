@@ -0,0 +1,36 @@
+//go:build darwin
+
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// detectDoNotDisturb 读取专注模式的断言记录，存在有效记录时表示专注模式已开启
+func detectDoNotDisturb() bool {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(home, "Library", "DoNotDisturb", "DB", "Assertions.json"))
+	if err != nil {
+		return false
+	}
+
+	var assertions struct {
+		Data []struct {
+			StoreAssertionRecords []json.RawMessage `json:"storeAssertionRecords"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &assertions); err != nil {
+		return false
+	}
+	for _, entry := range assertions.Data {
+		if len(entry.StoreAssertionRecords) > 0 {
+			return true
+		}
+	}
+	return false
+}
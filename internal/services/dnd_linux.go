@@ -0,0 +1,8 @@
+//go:build linux
+
+package services
+
+// detectDoNotDisturb 读取 GNOME 的通知横幅设置，关闭横幅即为勿扰模式
+func detectDoNotDisturb() bool {
+	return readGSetting("org.gnome.desktop.notifications", "show-banners") == "false"
+}
@@ -0,0 +1,35 @@
+//go:build windows
+
+package services
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// SHQueryUserNotificationState 返回值中表示不应打扰用户的状态
+const (
+	qunsBusy                 = 2 // 全屏应用
+	qunsRunningD3DFullScreen = 3 // 全屏 Direct3D 应用
+	qunsPresentationMode     = 4 // 演示模式
+	qunsQuietTime            = 6 // 专注助手/免打扰
+)
+
+var procSHQueryUserNotificationState = syscall.NewLazyDLL("shell32.dll").NewProc("SHQueryUserNotificationState")
+
+// detectDoNotDisturb 通过 SHQueryUserNotificationState 判断系统是否处于免打扰、演示或全屏状态
+func detectDoNotDisturb() bool {
+	if procSHQueryUserNotificationState.Find() != nil {
+		return false
+	}
+	var state int32
+	ret, _, _ := procSHQueryUserNotificationState.Call(uintptr(unsafe.Pointer(&state)))
+	if ret != 0 {
+		return false
+	}
+	switch state {
+	case qunsBusy, qunsRunningD3DFullScreen, qunsPresentationMode, qunsQuietTime:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"voidraft/internal/models"
+)
+
+const (
+	sqlSetDocumentArchived = `
+UPDATE documents
+SET is_archived = ?
+WHERE id = ? AND is_deleted = 0`
+
+	sqlListArchivedDocumentsMeta = `
+SELECT id, title, created_at, updated_at, is_locked, kind 
+FROM documents 
+WHERE is_deleted = 0 AND is_archived = 1
+ORDER BY updated_at DESC`
+
+	sqlListRetentionCandidates = `
+SELECT id, title, kind, collection_id, is_deleted, is_locked, is_archived, updated_at, last_opened_at, deleted_at
+FROM documents`
+
+	sqlListCollectionParents = `
+SELECT id, parent_id FROM collections`
+)
+
+// retentionCandidate 保留策略评估所需的文档信息
+type retentionCandidate struct {
+	ID           int64
+	Title        string
+	Kind         models.DocumentKind
+	Collections  []int64 // 所在文件夹及其所有上级文件夹，不在文件夹中时为空
+	Deleted      bool
+	Locked       bool
+	Archived     bool
+	UpdatedAt    string
+	LastOpenedAt string
+	DeletedAt    string
+}
+
+// ArchiveDocument 归档文档，归档后不出现在文档列表中，内容保持不变
+func (ds *DocumentService) ArchiveDocument(id int64) error {
+	if id == sqlDefaultDocumentID {
+		return fmt.Errorf("cannot archive the default document")
+	}
+	return ds.setDocumentArchived(id, true)
+}
+
+// UnarchiveDocument 取消归档，文档重新出现在文档列表中
+func (ds *DocumentService) UnarchiveDocument(id int64) error {
+	return ds.setDocumentArchived(id, false)
+}
+
+// setDocumentArchived 更新归档状态，不改变修改时间
+func (ds *DocumentService) setDocumentArchived(id int64, archived bool) error {
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return errors.New("database service not available")
+	}
+
+	value := 0
+	if archived {
+		value = 1
+	}
+	result, err := ds.databaseService.getDB().Exec(sqlSetDocumentArchived, value, id)
+	if err != nil {
+		return fmt.Errorf("failed to update archive state: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("document not found: %d", id)
+	}
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeArchived, DocumentID: id})
+	return nil
+}
+
+// ListArchivedDocumentsMeta 列出已归档的文档元数据
+func (ds *DocumentService) ListArchivedDocumentsMeta() ([]*models.Document, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	rows, err := ds.databaseService.getDB().Query(sqlListArchivedDocumentsMeta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archived document meta: %w", err)
+	}
+	defer rows.Close()
+
+	var documents []*models.Document
+	for rows.Next() {
+		doc := &models.Document{IsArchived: true}
+		var isLocked int
+		if err := rows.Scan(&doc.ID, &doc.Title, &doc.CreatedAt, &doc.UpdatedAt, &isLocked, &doc.Kind); err != nil {
+			return nil, fmt.Errorf("failed to scan document row: %w", err)
+		}
+		doc.IsLocked = isLocked == 1
+		documents = append(documents, doc)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating archived document rows: %w", err)
+	}
+	return documents, nil
+}
+
+// listRetentionCandidates 读取所有文档的保留策略评估信息
+func (ds *DocumentService) listRetentionCandidates() ([]retentionCandidate, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	parents := make(map[int64]int64)
+	parentRows, err := ds.databaseService.getDB().Query(sqlListCollectionParents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	defer parentRows.Close()
+	for parentRows.Next() {
+		var id, parentID int64
+		if err := parentRows.Scan(&id, &parentID); err != nil {
+			return nil, fmt.Errorf("failed to scan collection row: %w", err)
+		}
+		parents[id] = parentID
+	}
+	if err := parentRows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err := ds.databaseService.getDB().Query(sqlListRetentionCandidates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []retentionCandidate
+	for rows.Next() {
+		var c retentionCandidate
+		var collectionID int64
+		var deleted, locked, archived int
+		if err := rows.Scan(&c.ID, &c.Title, &c.Kind, &collectionID, &deleted, &locked, &archived, &c.UpdatedAt, &c.LastOpenedAt, &c.DeletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan document row: %w", err)
+		}
+		c.Collections = collectionAncestors(parents, collectionID)
+		c.Deleted, c.Locked, c.Archived = deleted == 1, locked == 1, archived == 1
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// collectionAncestors 文件夹及其所有上级文件夹，数据异常出现环时在重复处停止
+func collectionAncestors(parents map[int64]int64, id int64) []int64 {
+	var ids []int64
+	for id != 0 && !slices.Contains(ids, id) {
+		ids = append(ids, id)
+		id = parents[id]
+	}
+	return ids
+}
+
+// parseDocumentTime 解析文档表中的时间，新写入的为 2006-01-02 15:04:05，早期文档为 time.Time.String 格式
+func parseDocumentTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local); err == nil {
+		return t, true
+	}
+	// time.Time.String 可能带有单调时钟读数
+	if i := strings.Index(value, " m="); i > 0 {
+		value = value[:i]
+	}
+	if t, err := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", value); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/excerpt"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
+)
+
+// untitledDocumentTitle 未设置标题且没有文字内容的文档使用的标题
+const untitledDocumentTitle = "Untitled"
+
+// SQL 查询语句
+const (
+	sqlGetAutoTitleDocument = `
+SELECT title, content FROM documents WHERE id = ? AND title_auto = 1 AND is_deleted = 0`
+
+	// 用户在生成期间手动设置了标题时不再覆盖
+	sqlUpdateAutoTitle = `
+UPDATE documents SET title = ? WHERE id = ? AND title_auto = 1 AND is_deleted = 0`
+
+	sqlResetDocumentTitle = `
+UPDATE documents
+SET title = ?, title_auto = 1, updated_at = ?
+WHERE id = ? AND is_deleted = 0`
+
+	sqlGetDocumentContent = `
+SELECT content FROM documents WHERE id = ? AND is_deleted = 0`
+)
+
+// autoTitle 由内容生成的标题，没有文字内容时为 untitledDocumentTitle
+func autoTitle(content string) string {
+	if title := excerpt.Title(content, excerpt.TitleLength); title != "" {
+		return title
+	}
+	return untitledDocumentTitle
+}
+
+// handleAutoTitleChange 内容变化时更新自动生成的标题
+func (ds *DocumentService) handleAutoTitleChange(event models.DocumentChangeEvent) {
+	if event.Type == models.DocumentChangeContent {
+		ds.refreshAutoTitle(event.DocumentID)
+	}
+}
+
+// refreshAutoTitle 内容保存后重新生成未手动设置标题的文档标题
+// 监听器异步执行，事件可能乱序到达，因此以数据库中的最新内容为准
+func (ds *DocumentService) refreshAutoTitle(id int64) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil || ds.databaseService.ensureWritable() != nil {
+		return
+	}
+
+	var title, content string
+	err := ds.databaseService.getDB().QueryRow(sqlGetAutoTitleDocument, id).Scan(&title, &content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+	if err != nil {
+		ds.logger.Error("Failed to load document for auto title", "documentID", id, "error", err)
+		return
+	}
+
+	generated := autoTitle(content)
+	if generated == title {
+		return
+	}
+	if _, err := ds.databaseService.getDB().Exec(sqlUpdateAutoTitle, generated, id); err != nil {
+		ds.logger.Error("Failed to update auto title", "documentID", id, "error", err)
+		return
+	}
+	ds.notifyTitleGenerated(id, generated)
+	// 其他文档中的 [[旧标题]] 链接改为新标题
+	if title != "" && !strings.EqualFold(title, generated) {
+		go ds.renameTitleLinks(id, title, generated)
+	}
+}
+
+// resetDocumentTitleLocked 清除手动设置的标题，恢复为由内容生成，调用方需持有 mu
+func (ds *DocumentService) resetDocumentTitleLocked(id int64, oldTitle, now string) error {
+	var content string
+	err := ds.databaseService.getDB().QueryRow(sqlGetDocumentContent, id).Scan(&content)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	title := autoTitle(content)
+	if _, err := ds.databaseService.getDB().Exec(sqlResetDocumentTitle, title, now, id); err != nil {
+		return err
+	}
+	ds.notifyTitleGenerated(id, title)
+	if oldTitle != "" && !strings.EqualFold(oldTitle, title) {
+		go ds.renameTitleLinks(id, oldTitle, title)
+	}
+	return nil
+}
+
+// notifyTitleGenerated 通知监听器和前端标题已按内容重新生成
+func (ds *DocumentService) notifyTitleGenerated(id int64, title string) {
+	event := models.DocumentChangeEvent{Type: models.DocumentChangeTitle, DocumentID: id, Title: title}
+	ds.notifyDocumentChange(event)
+	helper.EmitEvent(constant.EVENT_DOCUMENT_TITLE_GENERATED, event)
+}
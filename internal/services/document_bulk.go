@@ -0,0 +1,384 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/common/markdown"
+	"voidraft/internal/models"
+)
+
+const (
+	sqlGetDocumentState = `
+SELECT is_deleted, is_locked FROM documents WHERE id = ?`
+
+	sqlGetDocumentForExport = `
+SELECT title, content, is_deleted, is_locked FROM documents WHERE id = ?`
+
+	// bulkProgressStep 每处理多少个文档推送一次进度
+	bulkProgressStep = 25
+)
+
+// 批量操作跳过文档的原因
+const (
+	bulkSkipNotFound = "document not found"
+	bulkSkipDeleted  = "document is in the trash"
+	bulkSkipLocked   = "document is locked"
+	bulkSkipDefault  = "the default document cannot be deleted"
+)
+
+// BulkDelete 把多个文档移入回收站，在同一事务中执行
+// 默认文档、锁定和不存在的文档被跳过并记录原因，不影响其他文档
+func (ds *DocumentService) BulkDelete(ids []int64) (*models.BulkResult, error) {
+	ids, err := normalizeBulkIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return nil, err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	tx, err := ds.databaseService.getDB().Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := &models.BulkResult{Operation: models.BulkDelete}
+	progress := newBulkProgress(models.BulkDelete, len(ids))
+	now := time.Now().Format("2006-01-02 15:04:05")
+	for i, id := range ids {
+		progress(i)
+		if id == sqlDefaultDocumentID {
+			result.Skipped = append(result.Skipped, models.BulkSkip{DocumentID: id, Reason: bulkSkipDefault})
+			continue
+		}
+		if reason, err := bulkDocumentState(tx, id, true); err != nil {
+			return nil, err
+		} else if reason != "" {
+			result.Skipped = append(result.Skipped, models.BulkSkip{DocumentID: id, Reason: reason})
+			continue
+		}
+		if _, err := tx.Exec(sqlMarkDocumentAsDeleted, now, id); err != nil {
+			return nil, fmt.Errorf("failed to move document %d to trash: %w", id, err)
+		}
+		result.Succeeded = append(result.Succeeded, id)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to delete documents: %w", err)
+	}
+
+	progress(len(ids))
+	ds.notifyBulkChange(models.DocumentChangeDeleted, result.Succeeded)
+	return result, nil
+}
+
+// BulkMove 把多个文档移到文件夹，collectionID 为 0 时移到根目录
+func (ds *DocumentService) BulkMove(ids []int64, collectionID int64) (*models.BulkResult, error) {
+	ids, err := normalizeBulkIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return nil, err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	db := ds.databaseService.getDB()
+	if collectionID != 0 {
+		if _, err := getCollection(db, collectionID); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := &models.BulkResult{Operation: models.BulkMove}
+	progress := newBulkProgress(models.BulkMove, len(ids))
+	for i, id := range ids {
+		progress(i)
+		if reason, err := bulkDocumentState(tx, id, false); err != nil {
+			return nil, err
+		} else if reason != "" {
+			result.Skipped = append(result.Skipped, models.BulkSkip{DocumentID: id, Reason: reason})
+			continue
+		}
+		if _, err := tx.Exec(sqlMoveDocumentToCollection, collectionID, id); err != nil {
+			return nil, fmt.Errorf("failed to move document %d: %w", id, err)
+		}
+		result.Succeeded = append(result.Succeeded, id)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to move documents: %w", err)
+	}
+
+	progress(len(ids))
+	ds.notifyBulkChange(models.DocumentChangeMoved, result.Succeeded)
+	return result, nil
+}
+
+// BulkTag 为多个文档添加和移除标签，添加的标签不存在时创建，不再使用的标签一并删除
+func (ds *DocumentService) BulkTag(ids []int64, add []string, remove []string) (*models.BulkResult, error) {
+	ids, err := normalizeBulkIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	addNames := make([]string, 0, len(add))
+	for _, name := range add {
+		name, err := validateTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		addNames = append(addNames, name)
+	}
+	removeNames := make([]string, 0, len(remove))
+	for _, name := range remove {
+		if name = normalizeTagName(name); name != "" {
+			removeNames = append(removeNames, name)
+		}
+	}
+	if len(addNames) == 0 && len(removeNames) == 0 {
+		return nil, errors.New("no tags to add or remove")
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return nil, err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	tx, err := ds.databaseService.getDB().Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Format("2006-01-02 15:04:05")
+	tagIDs := make([]int64, 0, len(addNames))
+	for _, name := range addNames {
+		if _, err := tx.Exec(sqlInsertTag, name, now); err != nil {
+			return nil, fmt.Errorf("failed to create tag: %w", err)
+		}
+		var tag models.Tag
+		if err := tx.QueryRow(sqlGetTagByName, name).Scan(&tag.ID, &tag.Name, &tag.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to get tag: %w", err)
+		}
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	result := &models.BulkResult{Operation: models.BulkTag}
+	progress := newBulkProgress(models.BulkTag, len(ids))
+	for i, id := range ids {
+		progress(i)
+		if reason, err := bulkDocumentState(tx, id, false); err != nil {
+			return nil, err
+		} else if reason != "" {
+			result.Skipped = append(result.Skipped, models.BulkSkip{DocumentID: id, Reason: reason})
+			continue
+		}
+		for _, tagID := range tagIDs {
+			if _, err := tx.Exec(sqlAddDocumentTag, id, tagID); err != nil {
+				return nil, fmt.Errorf("failed to tag document %d: %w", id, err)
+			}
+		}
+		for _, name := range removeNames {
+			if _, err := tx.Exec(sqlRemoveDocumentTag, id, name); err != nil {
+				return nil, fmt.Errorf("failed to remove tag from document %d: %w", id, err)
+			}
+		}
+		result.Succeeded = append(result.Succeeded, id)
+	}
+	if len(removeNames) > 0 {
+		if _, err := tx.Exec(sqlDeleteUnusedTags); err != nil {
+			return nil, fmt.Errorf("failed to delete unused tags: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to tag documents: %w", err)
+	}
+
+	progress(len(ids))
+	ds.notifyBulkChange(models.DocumentChangeTags, result.Succeeded)
+	helper.EmitEvent(constant.EVENT_TAGS_CHANGED, int64(0))
+	return result, nil
+}
+
+// BulkExport 把多个文档导出为目录中的 Markdown 文件，文件名取自标题，重名时追加序号
+// 文档在同一读事务中读取，导出的是同一时刻的内容；敏感信息按配置遮盖，锁定的文档跳过
+func (ds *DocumentService) BulkExport(ids []int64, dir string) (*models.BulkResult, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	ids, err := normalizeBulkIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return nil, errors.New("export directory is empty")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	type exportEntry struct {
+		id      int64
+		title   string
+		content string
+	}
+
+	result := &models.BulkResult{Operation: models.BulkExport}
+	entries, err := func() ([]exportEntry, error) {
+		ds.mu.RLock()
+		defer ds.mu.RUnlock()
+
+		if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+			return nil, errors.New("database service not available")
+		}
+		tx, err := ds.databaseService.getDB().Begin()
+		if err != nil {
+			return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		entries := make([]exportEntry, 0, len(ids))
+		for _, id := range ids {
+			var entry exportEntry
+			var deleted, locked bool
+			err := tx.QueryRow(sqlGetDocumentForExport, id).Scan(&entry.title, &entry.content, &deleted, &locked)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				result.Skipped = append(result.Skipped, models.BulkSkip{DocumentID: id, Reason: bulkSkipNotFound})
+				continue
+			case err != nil:
+				return nil, fmt.Errorf("failed to read document %d: %w", id, err)
+			case deleted:
+				result.Skipped = append(result.Skipped, models.BulkSkip{DocumentID: id, Reason: bulkSkipDeleted})
+				continue
+			case locked:
+				result.Skipped = append(result.Skipped, models.BulkSkip{DocumentID: id, Reason: bulkSkipLocked})
+				continue
+			}
+			entry.id = id
+			entries = append(entries, entry)
+		}
+		return entries, nil
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	progress := newBulkProgress(models.BulkExport, len(entries))
+	used := make(map[string]bool, len(entries))
+	for i, entry := range entries {
+		progress(i)
+		path := filepath.Join(dir, uniqueExportFileName(entry.title, used))
+		source := markdown.Source(blocks.Parse(maskSecretsForExport(ds.configService, entry.content)))
+		if err := writeFileAtomic(path, []byte(source), 0644); err != nil {
+			return nil, fmt.Errorf("failed to export document %d: %w", entry.id, err)
+		}
+		result.Succeeded = append(result.Succeeded, entry.id)
+		result.Files = append(result.Files, path)
+	}
+	progress(len(entries))
+
+	ds.logger.Info("Documents exported as markdown", "dir", dir, "count", len(result.Succeeded))
+	return result, nil
+}
+
+// bulkDocumentState 检查文档能否参与批量操作，返回跳过的原因，可以处理时返回空字符串
+func bulkDocumentState(tx *sql.Tx, id int64, rejectLocked bool) (string, error) {
+	var deleted, locked bool
+	err := tx.QueryRow(sqlGetDocumentState, id).Scan(&deleted, &locked)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return bulkSkipNotFound, nil
+	case err != nil:
+		return "", fmt.Errorf("failed to get document %d: %w", id, err)
+	case deleted:
+		return bulkSkipDeleted, nil
+	case locked && rejectLocked:
+		return bulkSkipLocked, nil
+	}
+	return "", nil
+}
+
+// notifyBulkChange 批量操作提交后逐个通知监听器，搜索索引等按文档更新
+func (ds *DocumentService) notifyBulkChange(changeType models.DocumentChangeType, ids []int64) {
+	for _, id := range ids {
+		ds.notifyDocumentChange(models.DocumentChangeEvent{Type: changeType, DocumentID: id})
+	}
+}
+
+// newBulkProgress 创建进度上报函数，按步长推送，处理完成时推送最终进度
+func newBulkProgress(operation models.BulkOperation, total int) func(done int) {
+	return func(done int) {
+		finished := done >= total
+		if !finished && done%bulkProgressStep != 0 {
+			return
+		}
+		helper.EmitEvent(constant.EVENT_DOCUMENT_BULK_PROGRESS, models.BulkProgress{
+			Operation: operation,
+			Done:      done,
+			Total:     total,
+			Finished:  finished,
+		})
+	}
+}
+
+// normalizeBulkIDs 去掉重复的文档ID并保持原顺序
+func normalizeBulkIDs(ids []int64) ([]int64, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("no documents selected")
+	}
+	seen := make(map[int64]bool, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique, nil
+}
+
+// uniqueExportFileName 根据标题生成 Markdown 导出文件名，与已使用的文件名重复时追加序号
+// 比较不区分大小写以兼容 Windows 和 macOS
+func uniqueExportFileName(title string, used map[string]bool) string {
+	name := exportFileName(title, ExportFormatMarkdown)
+	base := strings.TrimSuffix(name, "."+ExportFormatMarkdown)
+	for n := 2; used[strings.ToLower(name)]; n++ {
+		name = base + " (" + strconv.Itoa(n) + ")." + ExportFormatMarkdown
+	}
+	used[strings.ToLower(name)] = true
+	return name
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"voidraft/internal/models"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// newTestDocumentService 基于内存数据库创建文档服务，表结构与正式库一致
+func newTestDocumentService(t *testing.T) *DocumentService {
+	t.Helper()
+	databaseService := newTestDatabaseService(t)
+	if err := databaseService.createTables(); err != nil {
+		t.Fatal(err)
+	}
+	return NewDocumentService(&ConfigService{koanf: koanf.New(".")}, databaseService, nil, nil)
+}
+
+// createTestDocument 创建带内容的测试文档
+func createTestDocument(t *testing.T, ds *DocumentService, title, content string) int64 {
+	t.Helper()
+	doc, err := ds.CreateDocument(title)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.UpdateDocumentContent(doc.ID, content); err != nil {
+		t.Fatal(err)
+	}
+	return doc.ID
+}
+
+func TestNormalizeBulkIDs(t *testing.T) {
+	got, err := normalizeBulkIDs([]int64{3, 1, 3, 2, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{3, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeBulkIDs = %v, want %v", got, want)
+	}
+	if _, err := normalizeBulkIDs(nil); err == nil {
+		t.Error("normalizeBulkIDs(nil) should fail")
+	}
+}
+
+func TestUniqueExportFileName(t *testing.T) {
+	used := map[string]bool{}
+	titles := []string{"Notes", "notes", "a/b:c", "  ", "Notes"}
+	want := []string{"Notes.md", "notes (2).md", "a_b_c.md", "document.md", "Notes (3).md"}
+	for i, title := range titles {
+		if got := uniqueExportFileName(title, used); got != want[i] {
+			t.Errorf("uniqueExportFileName(%q) = %q, want %q", title, got, want[i])
+		}
+	}
+}
+
+func TestBulkExportSkipsLockedDocuments(t *testing.T) {
+	ds := newTestDocumentService(t)
+	open := createTestDocument(t, ds, "Open", "\n∞∞∞text-a\nvisible")
+	locked := createTestDocument(t, ds, "Locked", "\n∞∞∞text-a\nsecret")
+	trashed := createTestDocument(t, ds, "Trashed", "\n∞∞∞text-a\ngone")
+	if err := ds.LockDocument(locked); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.DeleteDocument(trashed); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	result, err := ds.BulkExport([]int64{open, locked, trashed, 999}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{open}; !reflect.DeepEqual(result.Succeeded, want) {
+		t.Errorf("succeeded = %v, want %v", result.Succeeded, want)
+	}
+	wantSkipped := []models.BulkSkip{
+		{DocumentID: locked, Reason: bulkSkipLocked},
+		{DocumentID: trashed, Reason: bulkSkipDeleted},
+		{DocumentID: 999, Reason: bulkSkipNotFound},
+	}
+	if !reflect.DeepEqual(result.Skipped, wantSkipped) {
+		t.Errorf("skipped = %v, want %v", result.Skipped, wantSkipped)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name() != "Open.md" {
+		t.Fatalf("exported files = %v, want only Open.md", files)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "Open.md")); len(data) == 0 {
+		t.Error("exported file is empty")
+	}
+}
+
+// bulkFixture 批量操作测试用的文档：默认文档、两个普通文档、锁定和回收站中的文档
+type bulkFixture struct {
+	ds                        *DocumentService
+	defaultDoc, first, second int64
+	locked, trashed           int64
+}
+
+func newBulkFixture(t *testing.T) bulkFixture {
+	t.Helper()
+	ds := newTestDocumentService(t)
+	f := bulkFixture{ds: ds}
+	f.defaultDoc = createTestDocument(t, ds, "Default", "\n∞∞∞text-a\ndefault")
+	f.first = createTestDocument(t, ds, "Notes", "\n∞∞∞text-a\nfirst")
+	f.second = createTestDocument(t, ds, "Notes", "\n∞∞∞text-a\nsecond")
+	f.locked = createTestDocument(t, ds, "Locked", "\n∞∞∞text-a\nlocked")
+	f.trashed = createTestDocument(t, ds, "Trashed", "\n∞∞∞text-a\ntrashed")
+	if err := ds.LockDocument(f.locked); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.DeleteDocument(f.trashed); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// TestBulkDeletePartial 可删除的文档移入回收站，其余跳过并记录原因
+func TestBulkDeletePartial(t *testing.T) {
+	f := newBulkFixture(t)
+	result, err := f.ds.BulkDelete([]int64{f.first, f.defaultDoc, f.locked, f.trashed, 999, f.second, f.first})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{f.first, f.second}; !reflect.DeepEqual(result.Succeeded, want) {
+		t.Errorf("succeeded = %v, want %v", result.Succeeded, want)
+	}
+	wantSkipped := []models.BulkSkip{
+		{DocumentID: f.defaultDoc, Reason: bulkSkipDefault},
+		{DocumentID: f.locked, Reason: bulkSkipLocked},
+		{DocumentID: f.trashed, Reason: bulkSkipDeleted},
+		{DocumentID: 999, Reason: bulkSkipNotFound},
+	}
+	if !reflect.DeepEqual(result.Skipped, wantSkipped) {
+		t.Errorf("skipped = %v, want %v", result.Skipped, wantSkipped)
+	}
+
+	trashed, err := f.ds.ListTrashed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := map[int64]bool{}
+	for _, doc := range trashed {
+		ids[doc.ID] = true
+	}
+	if want := map[int64]bool{f.first: true, f.second: true, f.trashed: true}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("trash = %v, want %v", ids, want)
+	}
+	for _, id := range []int64{f.defaultDoc, f.locked} {
+		if doc, err := f.ds.GetDocumentByID(id); err != nil || doc.IsDeleted {
+			t.Errorf("document %d = %+v, %v, want it kept", id, doc, err)
+		}
+	}
+}
+
+// TestBulkMovePartial 锁定的文档也可以移动，回收站中和不存在的文档跳过
+func TestBulkMovePartial(t *testing.T) {
+	f := newBulkFixture(t)
+	collection, err := f.ds.CreateCollection("Projects", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.ds.BulkMove([]int64{f.first}, 999); err == nil {
+		t.Error("moving into a missing collection should fail")
+	}
+
+	result, err := f.ds.BulkMove([]int64{f.first, f.locked, f.trashed, 999}, collection.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{f.first, f.locked}; !reflect.DeepEqual(result.Succeeded, want) {
+		t.Errorf("succeeded = %v, want %v", result.Succeeded, want)
+	}
+	wantSkipped := []models.BulkSkip{
+		{DocumentID: f.trashed, Reason: bulkSkipDeleted},
+		{DocumentID: 999, Reason: bulkSkipNotFound},
+	}
+	if !reflect.DeepEqual(result.Skipped, wantSkipped) {
+		t.Errorf("skipped = %v, want %v", result.Skipped, wantSkipped)
+	}
+
+	collectionOf := func(id int64) int64 {
+		t.Helper()
+		doc, err := f.ds.GetDocumentByID(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return doc.CollectionID
+	}
+	for id, want := range map[int64]int64{f.first: collection.ID, f.locked: collection.ID, f.second: 0, f.trashed: 0} {
+		if got := collectionOf(id); got != want {
+			t.Errorf("collection of %d = %d, want %d", id, got, want)
+		}
+	}
+
+	// 移回根目录
+	if _, err := f.ds.BulkMove([]int64{f.first}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := collectionOf(f.first); got != 0 {
+		t.Errorf("collection after moving to root = %d, want 0", got)
+	}
+}
+
+// TestBulkExportDuplicateTitles 同名文档导出为不同的文件
+func TestBulkExportDuplicateTitles(t *testing.T) {
+	f := newBulkFixture(t)
+	dir := t.TempDir()
+	result, err := f.ds.BulkExport([]int64{f.first, f.second, f.locked}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{f.first, f.second}; !reflect.DeepEqual(result.Succeeded, want) {
+		t.Errorf("succeeded = %v, want %v", result.Succeeded, want)
+	}
+	want := []string{filepath.Join(dir, "Notes.md"), filepath.Join(dir, "Notes (2).md")}
+	if !reflect.DeepEqual(result.Files, want) {
+		t.Fatalf("files = %v, want %v", result.Files, want)
+	}
+	for i, content := range []string{"first", "second"} {
+		data, err := os.ReadFile(want[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), content) {
+			t.Errorf("%s = %q, want it to contain %q", want[i], data, content)
+		}
+	}
+}
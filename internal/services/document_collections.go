@@ -0,0 +1,390 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"voidraft/internal/models"
+)
+
+const (
+	// sqlCollectionTree 文件夹及其所有子文件夹，UNION 去重保证数据异常出现环时也能结束
+	sqlCollectionTree = `
+WITH RECURSIVE tree(id) AS (
+    SELECT :root
+    UNION
+    SELECT c.id FROM collections c JOIN tree t ON c.parent_id = t.id
+)`
+
+	sqlInsertCollection = `
+INSERT INTO collections (parent_id, name, created_at, updated_at)
+VALUES (?, ?, ?, ?)`
+
+	sqlGetCollection = `
+SELECT id, parent_id, name, created_at, updated_at FROM collections WHERE id = ?`
+
+	sqlListCollections = `
+SELECT id, parent_id, name, created_at, updated_at
+FROM collections
+ORDER BY name COLLATE NOCASE, id`
+
+	sqlCountCollectionDocuments = `
+SELECT collection_id, COUNT(*)
+FROM documents
+WHERE is_deleted = 0 AND is_archived = 0 AND collection_id != 0
+GROUP BY collection_id`
+
+	sqlRenameCollection = `
+UPDATE collections SET name = ?, updated_at = ? WHERE id = ?`
+
+	sqlMoveCollection = `
+UPDATE collections SET parent_id = ?, updated_at = ? WHERE id = ?`
+
+	sqlIsCollectionDescendant = sqlCollectionTree + `
+SELECT COUNT(*) FROM tree WHERE id = :target`
+
+	sqlMoveDocumentToCollection = `
+UPDATE documents SET collection_id = ? WHERE id = ? AND is_deleted = 0`
+
+	sqlMoveChildCollectionsUp = `
+UPDATE collections SET parent_id = :parent, updated_at = :now WHERE parent_id = :root`
+
+	sqlMoveCollectionDocumentsUp = `
+UPDATE documents SET collection_id = :parent WHERE collection_id = :root`
+
+	sqlDeleteCollection = `
+DELETE FROM collections WHERE id = :root`
+
+	sqlListTrashableCollectionDocuments = sqlCollectionTree + `
+SELECT id FROM documents
+WHERE collection_id IN (SELECT id FROM tree) AND is_deleted = 0 AND is_locked = 0 AND id != :default_id`
+
+	sqlTrashCollectionDocuments = sqlCollectionTree + `
+UPDATE documents
+SET is_deleted = 1, deleted_at = :now, updated_at = :now, collection_id = 0
+WHERE collection_id IN (SELECT id FROM tree) AND is_deleted = 0 AND is_locked = 0 AND id != :default_id`
+
+	sqlMoveTreeDocumentsUp = sqlCollectionTree + `
+UPDATE documents SET collection_id = :parent WHERE collection_id IN (SELECT id FROM tree)`
+
+	sqlDeleteCollectionTree = sqlCollectionTree + `
+DELETE FROM collections WHERE id IN (SELECT id FROM tree)`
+
+	// collectionNameMaxLength 文件夹名的最大长度（字符）
+	collectionNameMaxLength = 128
+)
+
+// ErrCollectionNotFound 文件夹不存在
+var ErrCollectionNotFound = errors.New("collection not found")
+
+// CreateCollection 创建文件夹，parentID 为 0 时创建在顶层
+func (ds *DocumentService) CreateCollection(name string, parentID int64) (*models.Collection, error) {
+	name, err := validateCollectionName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return nil, err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	db := ds.databaseService.getDB()
+	if parentID != 0 {
+		if _, err := getCollection(db, parentID); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now().Format("2006-01-02 15:04:05")
+	result, err := db.Exec(sqlInsertCollection, parentID, name, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create collection: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection ID: %w", err)
+	}
+	return &models.Collection{ID: id, ParentID: parentID, Name: name, CreatedAt: now, UpdatedAt: now}, nil
+}
+
+// RenameCollection 重命名文件夹
+func (ds *DocumentService) RenameCollection(id int64, name string) error {
+	name, err := validateCollectionName(name)
+	if err != nil {
+		return err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	result, err := ds.databaseService.getDB().Exec(sqlRenameCollection, name, time.Now().Format("2006-01-02 15:04:05"), id)
+	if err != nil {
+		return fmt.Errorf("failed to rename collection: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("%w: %d", ErrCollectionNotFound, id)
+	}
+	return nil
+}
+
+// MoveCollection 把文件夹移到另一个文件夹下，parentID 为 0 时移到顶层；不能移到自身或其子文件夹下
+func (ds *DocumentService) MoveCollection(id, parentID int64) error {
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	db := ds.databaseService.getDB()
+	if _, err := getCollection(db, id); err != nil {
+		return err
+	}
+	if parentID != 0 {
+		if _, err := getCollection(db, parentID); err != nil {
+			return err
+		}
+		var inside int
+		if err := db.QueryRow(sqlIsCollectionDescendant, sql.Named("root", id), sql.Named("target", parentID)).Scan(&inside); err != nil {
+			return fmt.Errorf("failed to check collection tree: %w", err)
+		}
+		if inside > 0 {
+			return errors.New("cannot move a collection into itself or its subfolders")
+		}
+	}
+	if _, err := db.Exec(sqlMoveCollection, parentID, time.Now().Format("2006-01-02 15:04:05"), id); err != nil {
+		return fmt.Errorf("failed to move collection: %w", err)
+	}
+	return nil
+}
+
+// MoveDocumentToCollection 把文档移到文件夹，collectionID 为 0 时移到根目录
+func (ds *DocumentService) MoveDocumentToCollection(documentID, collectionID int64) error {
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	db := ds.databaseService.getDB()
+	if collectionID != 0 {
+		if _, err := getCollection(db, collectionID); err != nil {
+			return err
+		}
+	}
+	result, err := db.Exec(sqlMoveDocumentToCollection, collectionID, documentID)
+	if err != nil {
+		return fmt.Errorf("failed to move document: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("document not found: %d", documentID)
+	}
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeMoved, DocumentID: documentID})
+	return nil
+}
+
+// ListCollectionTree 列出文件夹树，同级按名称排序
+func (ds *DocumentService) ListCollectionTree() ([]*models.CollectionNode, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+	db := ds.databaseService.getDB()
+
+	rows, err := db.Query(sqlListCollections)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	defer rows.Close()
+	var collections []models.Collection
+	for rows.Next() {
+		var c models.Collection
+		if err := rows.Scan(&c.ID, &c.ParentID, &c.Name, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan collection row: %w", err)
+		}
+		collections = append(collections, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int64]int)
+	countRows, err := db.Query(sqlCountCollectionDocuments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count collection documents: %w", err)
+	}
+	defer countRows.Close()
+	for countRows.Next() {
+		var id int64
+		var count int
+		if err := countRows.Scan(&id, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan collection count: %w", err)
+		}
+		counts[id] = count
+	}
+	if err := countRows.Err(); err != nil {
+		return nil, err
+	}
+	return buildCollectionTree(collections, counts), nil
+}
+
+// DeleteCollection 删除文件夹，mode 为空时使用设置中的处理方式
+func (ds *DocumentService) DeleteCollection(id int64, mode models.CollectionDeleteMode) (*models.CollectionDeleteResult, error) {
+	if mode == "" {
+		mode = models.CollectionDeleteMoveUp
+		if ds.configService != nil {
+			if config, err := ds.configService.GetConfig(); err == nil && config.Collections.DeleteMode != "" {
+				mode = config.Collections.DeleteMode
+			}
+		}
+	}
+	if mode != models.CollectionDeleteMoveUp && mode != models.CollectionDeleteTrash {
+		return nil, fmt.Errorf("unsupported delete mode: %s", mode)
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return nil, err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	db := ds.databaseService.getDB()
+	collection, err := getCollection(db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Format("2006-01-02 15:04:05")
+	root, parent := sql.Named("root", id), sql.Named("parent", collection.ParentID)
+	result := &models.CollectionDeleteResult{Mode: mode}
+	var trashed []int64
+
+	if mode == models.CollectionDeleteTrash {
+		if trashed, err = queryIDs(tx, sqlListTrashableCollectionDocuments, root, sql.Named("default_id", sqlDefaultDocumentID)); err != nil {
+			return nil, fmt.Errorf("failed to list collection documents: %w", err)
+		}
+		if _, err := tx.Exec(sqlTrashCollectionDocuments, root, sql.Named("now", now), sql.Named("default_id", sqlDefaultDocumentID)); err != nil {
+			return nil, fmt.Errorf("failed to move documents to trash: %w", err)
+		}
+		// 锁定的文档、默认文档和已在回收站中的文档移到上级，恢复后仍有所属文件夹
+		moved, err := tx.Exec(sqlMoveTreeDocumentsUp, root, parent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to move documents: %w", err)
+		}
+		deleted, err := tx.Exec(sqlDeleteCollectionTree, root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete collections: %w", err)
+		}
+		result.Moved = int(rowsAffected(moved))
+		result.Collections = int(rowsAffected(deleted))
+		result.Trashed = len(trashed)
+	} else {
+		if _, err := tx.Exec(sqlMoveChildCollectionsUp, root, parent, sql.Named("now", now)); err != nil {
+			return nil, fmt.Errorf("failed to move subfolders: %w", err)
+		}
+		moved, err := tx.Exec(sqlMoveCollectionDocumentsUp, root, parent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to move documents: %w", err)
+		}
+		if _, err := tx.Exec(sqlDeleteCollection, root); err != nil {
+			return nil, fmt.Errorf("failed to delete collection: %w", err)
+		}
+		result.Moved = int(rowsAffected(moved))
+		result.Collections = 1
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to delete collection: %w", err)
+	}
+
+	for _, docID := range trashed {
+		ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeDeleted, DocumentID: docID})
+	}
+	ds.invalidateCaches()
+	return result, nil
+}
+
+// getCollection 按ID查询文件夹
+func getCollection(db *sql.DB, id int64) (*models.Collection, error) {
+	c := &models.Collection{}
+	err := db.QueryRow(sqlGetCollection, id).Scan(&c.ID, &c.ParentID, &c.Name, &c.CreatedAt, &c.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %d", ErrCollectionNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+	return c, nil
+}
+
+// queryIDs 查询单列ID
+func queryIDs(tx *sql.Tx, query string, args ...any) ([]int64, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// rowsAffected 受影响的行数，驱动不支持时为 0
+func rowsAffected(result sql.Result) int64 {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// buildCollectionTree 按上级关系组装文件夹树，上级不存在的文件夹放在顶层
+func buildCollectionTree(collections []models.Collection, counts map[int64]int) []*models.CollectionNode {
+	nodes := make(map[int64]*models.CollectionNode, len(collections))
+	for _, c := range collections {
+		nodes[c.ID] = &models.CollectionNode{Collection: c, DocumentCount: counts[c.ID], Children: []*models.CollectionNode{}}
+	}
+
+	roots := []*models.CollectionNode{}
+	for _, c := range collections {
+		node := nodes[c.ID]
+		if parent, ok := nodes[c.ParentID]; ok && c.ParentID != c.ID {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	return roots
+}
+
+// validateCollectionName 去掉首尾空白并校验文件夹名
+func validateCollectionName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	switch {
+	case name == "":
+		return "", errors.New("collection name is empty")
+	case len([]rune(name)) > collectionNameMaxLength:
+		return "", fmt.Errorf("collection name is longer than %d characters", collectionNameMaxLength)
+	}
+	return name, nil
+}
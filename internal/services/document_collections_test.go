@@ -0,0 +1,32 @@
+package services
+
+import (
+	"testing"
+	"voidraft/internal/models"
+)
+
+func TestBuildCollectionTree(t *testing.T) {
+	collections := []models.Collection{
+		{ID: 1, Name: "Work"},
+		{ID: 2, ParentID: 1, Name: "Snippets"},
+		{ID: 3, ParentID: 2, Name: "Go"},
+		{ID: 4, ParentID: 99, Name: "Orphan"},
+		{ID: 5, Name: "Personal"},
+	}
+	roots := buildCollectionTree(collections, map[int64]int{3: 7})
+
+	var names []string
+	for _, root := range roots {
+		names = append(names, root.Name)
+	}
+	if len(roots) != 3 || names[0] != "Work" || names[1] != "Orphan" || names[2] != "Personal" {
+		t.Fatalf("roots = %v, want [Work Orphan Personal]", names)
+	}
+	goNode := roots[0].Children[0].Children[0]
+	if goNode.Name != "Go" || goNode.DocumentCount != 7 {
+		t.Errorf("nested node = %+v, want Go with 7 documents", goNode)
+	}
+	if roots[2].Children == nil {
+		t.Error("leaf children should be an empty list")
+	}
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/dedupe"
+	"voidraft/internal/common/excerpt"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
+)
+
+const (
+	sqlListDocumentsForDedupe = `
+SELECT id, title, content, updated_at, content_size, is_locked
+FROM documents
+WHERE is_deleted = 0 AND is_archived = 0 AND kind = ''`
+
+	sqlGetDocumentForMerge = `
+SELECT title, content, is_deleted, is_locked, kind FROM documents WHERE id = ?`
+
+	sqlCopyDocumentTags = `
+INSERT OR IGNORE INTO document_tags (document_id, tag_id)
+SELECT ?, tag_id FROM document_tags WHERE document_id = ?`
+)
+
+// FindDuplicateDocuments 查找内容相同或相近的文档，不含回收站和已归档的文档
+// 相同的分组排在前面，相近按词组分片的相似度判断
+func (ds *DocumentService) FindDuplicateDocuments() ([]*models.DuplicateCluster, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	rows, err := ds.databaseService.getDB().Query(sqlListDocumentsForDedupe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	defer rows.Close()
+
+	var items []dedupe.Item
+	docs := make(map[int64]models.DuplicateDocument)
+	for rows.Next() {
+		var doc models.DuplicateDocument
+		var content string
+		if err := rows.Scan(&doc.ID, &doc.Title, &content, &doc.UpdatedAt, &doc.ContentSize, &doc.IsLocked); err != nil {
+			return nil, fmt.Errorf("failed to scan document: %w", err)
+		}
+		items = append(items, dedupe.Item{ID: doc.ID, Content: content})
+		docs[doc.ID] = doc
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	clusters := []*models.DuplicateCluster{}
+	for _, found := range dedupe.Find(items, dedupe.DefaultThreshold) {
+		cluster := &models.DuplicateCluster{Exact: found.Exact, Similarity: found.Similarity}
+		for _, id := range found.IDs {
+			cluster.Documents = append(cluster.Documents, docs[id])
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+// MergeDocuments 把 ids 中其余文档的内容依次追加到第一个文档，合并标签，然后把其余文档移入回收站
+// separator 不为空时在相邻文档之间插入一个内容为 separator 的文本块
+// 合并前为各文档保存快照，合并后的内容也记入历史版本；在同一事务中执行，任一文档不存在或已锁定时不做任何修改
+func (ds *DocumentService) MergeDocuments(ids []int64, separator string) (*models.Document, error) {
+	ids, err := normalizeBulkIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) < 2 {
+		return nil, errors.New("at least two documents are required to merge")
+	}
+	targetID, sources := ids[0], ids[1:]
+	for _, id := range sources {
+		if id == sqlDefaultDocumentID {
+			return nil, fmt.Errorf("cannot merge the default document into another document")
+		}
+	}
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return nil, err
+	}
+
+	merged, err := ds.mergeDocuments(targetID, sources, separator)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeContent, DocumentID: targetID, Content: merged})
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeTags, DocumentID: targetID})
+	ds.notifyBulkChange(models.DocumentChangeDeleted, sources)
+	helper.EmitEvent(constant.EVENT_TAGS_CHANGED, targetID)
+	return ds.GetDocumentByID(targetID)
+}
+
+// mergeDocuments 在事务中合并文档，返回合并后的内容
+func (ds *DocumentService) mergeDocuments(targetID int64, sources []int64, separator string) (string, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return "", errors.New("database service not available")
+	}
+
+	tx, err := ds.databaseService.getDB().Begin()
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	target, err := documentForMerge(tx, targetID)
+	if err != nil {
+		return "", err
+	}
+	contents := make([]string, 0, 2*len(sources))
+	for _, id := range sources {
+		doc, err := documentForMerge(tx, id)
+		if err != nil {
+			return "", err
+		}
+		if _, err := ds.saveVersionLocked(tx, id, doc.Title, doc.Content, now, true); err != nil {
+			return "", fmt.Errorf("failed to snapshot document %d: %w", id, err)
+		}
+		if strings.TrimSpace(separator) != "" {
+			contents = append(contents, separator)
+		}
+		contents = append(contents, doc.Content)
+	}
+	if _, err := ds.saveVersionLocked(tx, targetID, target.Title, target.Content, now, true); err != nil {
+		return "", fmt.Errorf("failed to snapshot document %d: %w", targetID, err)
+	}
+
+	merged := mergeDocumentContent(target.Content, contents)
+	updatedAt := now.Format("2006-01-02 15:04:05")
+	preview := excerpt.Plain(merged, excerpt.DefaultLength)
+	result, err := tx.Exec(sqlUpdateDocumentContent, merged, preview, utf8.RuneCountInString(merged), updatedAt, targetID)
+	if err != nil {
+		return "", fmt.Errorf("failed to update document content: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return "", ErrDocumentAppendOnly
+	}
+	if _, err := ds.saveVersionLocked(tx, targetID, target.Title, merged, now, true); err != nil {
+		return "", fmt.Errorf("failed to snapshot document %d: %w", targetID, err)
+	}
+	for _, id := range sources {
+		if _, err := tx.Exec(sqlCopyDocumentTags, targetID, id); err != nil {
+			return "", fmt.Errorf("failed to merge tags of document %d: %w", id, err)
+		}
+		if _, err := tx.Exec(sqlMarkDocumentAsDeleted, updatedAt, id); err != nil {
+			return "", fmt.Errorf("failed to move document %d to trash: %w", id, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to merge documents: %w", err)
+	}
+	return merged, nil
+}
+
+// documentForMerge 读取参与合并的文档标题和内容，不存在、已删除、已锁定或为日志文档时返回错误
+func documentForMerge(tx *sql.Tx, id int64) (*models.Document, error) {
+	doc := &models.Document{ID: id}
+	var deleted, locked bool
+	err := tx.QueryRow(sqlGetDocumentForMerge, id).Scan(&doc.Title, &doc.Content, &deleted, &locked, &doc.Kind)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("document not found: %d", id)
+	case err != nil:
+		return nil, fmt.Errorf("failed to get document %d: %w", id, err)
+	}
+	if err := checkMergeable(id, deleted, locked, doc.Kind); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// checkMergeable 日志文档只能追加，合并会破坏其内容或把它移入回收站，因此与锁定的文档一样不参与合并
+func checkMergeable(id int64, deleted, locked bool, kind models.DocumentKind) error {
+	switch {
+	case deleted:
+		return fmt.Errorf("document not found: %d", id)
+	case locked:
+		return fmt.Errorf("cannot merge locked document: %d", id)
+	case kind.IsAppendOnly():
+		return fmt.Errorf("cannot merge journal document %d: %w", id, ErrDocumentAppendOnly)
+	}
+	return nil
+}
+
+// mergeDocumentContent 按顺序拼接文档内容，来源文档开头没有块分隔符时补一个文本块分隔符
+func mergeDocumentContent(target string, sources []string) string {
+	var b strings.Builder
+	b.WriteString(target)
+	for _, content := range sources {
+		if !strings.HasPrefix(content, blocks.DelimiterPrefix) {
+			b.WriteString(blocks.Delimiter(blocks.DefaultLanguage, true))
+		}
+		b.WriteString(content)
+	}
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"voidraft/internal/models"
+)
+
+func TestMergeDocumentContent(t *testing.T) {
+	target := "\n∞∞∞text-a\nfirst"
+	sources := []string{"\n∞∞∞md\n# second", "legacy without delimiter"}
+	want := "\n∞∞∞text-a\nfirst\n∞∞∞md\n# second\n∞∞∞text-a\nlegacy without delimiter"
+	if got := mergeDocumentContent(target, sources); got != want {
+		t.Errorf("mergeDocumentContent = %q, want %q", got, want)
+	}
+}
+
+func TestCheckMergeable(t *testing.T) {
+	if err := checkMergeable(1, false, false, models.DocumentKindNote); err != nil {
+		t.Errorf("checkMergeable(note) = %v, want nil", err)
+	}
+	for _, kind := range []models.DocumentKind{models.DocumentKindJournal, models.DocumentKindJournalArchive} {
+		if err := checkMergeable(1, false, false, kind); !errors.Is(err, ErrDocumentAppendOnly) {
+			t.Errorf("checkMergeable(%s) = %v, want ErrDocumentAppendOnly", kind, err)
+		}
+	}
+	if err := checkMergeable(1, true, false, models.DocumentKindNote); err == nil {
+		t.Error("checkMergeable(deleted) = nil, want error")
+	}
+	if err := checkMergeable(1, false, true, models.DocumentKindNote); err == nil {
+		t.Error("checkMergeable(locked) = nil, want error")
+	}
+}
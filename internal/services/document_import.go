@@ -0,0 +1,253 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/excerpt"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+const sqlInsertImportedDocument = `
+INSERT INTO documents (title, content, preview, created_at, updated_at, is_deleted, is_locked, content_size, title_auto)
+VALUES (?, ?, ?, ?, ?, 0, 0, ?, 0)`
+
+// importFileLanguages 目录导入支持的扩展名 -> 块语言
+var importFileLanguages = map[string]string{
+	".md":       "md",
+	".markdown": "md",
+	".txt":      blocks.DefaultLanguage,
+	".text":     blocks.DefaultLanguage,
+}
+
+// importedFile 从文件中读取的文档
+type importedFile struct {
+	title     string
+	content   string
+	tags      []string
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// ImportDirectory 导入目录中的 Markdown 和纯文本文件，每个文件创建一个文档
+// 标题和标签取自 front-matter，没有标题时使用文件名；时间取 front-matter 的 created、updated，否则取文件修改时间
+// recursive 为 false 时不进入子目录，隐藏文件和目录（如 .obsidian、.git）总是跳过；单个文件的错误记入结果，不中断导入
+func (ds *DocumentService) ImportDirectory(path string, recursive bool) (*ImportResult, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, errors.New("path is not a directory")
+	}
+
+	result := &ImportResult{DocumentIDs: []int64{}, Errors: []string{}}
+	tagged := false
+	err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		rel, _ := filepath.Rel(path, file)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", rel, err))
+			if entry != nil && entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if file == path {
+			return nil
+		}
+		if strings.HasPrefix(entry.Name(), ".") || (entry.IsDir() && !recursive) {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		language, ok := importFileLanguages[strings.ToLower(filepath.Ext(file))]
+		if entry.IsDir() || !ok {
+			return nil
+		}
+
+		imported, err := readImportFile(file, language)
+		if err == nil {
+			var doc *models.Document
+			if doc, err = ds.insertImportedDocument(imported); err == nil {
+				result.Imported++
+				result.DocumentIDs = append(result.DocumentIDs, doc.ID)
+				tagged = tagged || len(imported.tags) > 0
+			}
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", rel, err))
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+	if tagged {
+		helper.EmitEvent(constant.EVENT_TAGS_CHANGED, int64(0))
+	}
+
+	ds.logger.Info("Directory imported", "path", path, "imported", result.Imported, "failed", len(result.Errors))
+	return result, nil
+}
+
+// readImportFile 读取文件并解析 front-matter
+func readImportFile(path string, language string) (*importedFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > textImportDocumentBytes {
+		return nil, fmt.Errorf("file is larger than %d MB, use text file import instead", textImportDocumentBytes>>20)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.Valid(data) {
+		return nil, errors.New("file is not valid UTF-8 text")
+	}
+
+	text := strings.ReplaceAll(strings.TrimPrefix(string(data), "\ufeff"), "\r\n", "\n")
+	meta, body := splitFrontMatter(text)
+	file := &importedFile{
+		title:     strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		tags:      frontMatterTags(meta["tags"]),
+		createdAt: info.ModTime(),
+		updatedAt: info.ModTime(),
+	}
+	if title, ok := meta["title"].(string); ok && strings.TrimSpace(title) != "" {
+		file.title = strings.TrimSpace(title)
+	}
+	if created, ok := frontMatterTime(meta["created"]); ok {
+		file.createdAt = created
+	}
+	if updated, ok := frontMatterTime(meta["updated"]); ok {
+		file.updatedAt = updated
+	}
+
+	// 从本应用导出的原始内容已带有块分隔符
+	if strings.HasPrefix(body, blocks.DelimiterPrefix) {
+		file.content = body
+	} else {
+		file.content = blocks.Delimiter(language, false) + body
+	}
+	return file, nil
+}
+
+// insertImportedDocument 在事务中创建文档并添加标签，保留文件的时间
+func (ds *DocumentService) insertImportedDocument(file *importedFile) (*models.Document, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+	tx, err := ds.databaseService.getDB().Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	doc := models.NewDocument(file.title, file.content)
+	doc.CreatedAt = file.createdAt.Local().Format("2006-01-02 15:04:05")
+	doc.UpdatedAt = file.updatedAt.Local().Format("2006-01-02 15:04:05")
+	result, err := tx.Exec(sqlInsertImportedDocument, doc.Title, doc.Content, excerpt.Plain(doc.Content, excerpt.DefaultLength),
+		doc.CreatedAt, doc.UpdatedAt, utf8.RuneCountInString(doc.Content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create document: %w", err)
+	}
+	if doc.ID, err = result.LastInsertId(); err != nil {
+		return nil, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	now := time.Now().Format("2006-01-02 15:04:05")
+	for _, name := range file.tags {
+		if name, err = validateTagName(name); err != nil {
+			continue
+		}
+		if _, err := tx.Exec(sqlInsertTag, name, now); err != nil {
+			return nil, fmt.Errorf("failed to create tag: %w", err)
+		}
+		var tag models.Tag
+		if err := tx.QueryRow(sqlGetTagByName, name).Scan(&tag.ID, &tag.Name, &tag.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to get tag: %w", err)
+		}
+		if _, err := tx.Exec(sqlAddDocumentTag, doc.ID, tag.ID); err != nil {
+			return nil, fmt.Errorf("failed to tag document: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to import document: %w", err)
+	}
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeCreated, DocumentID: doc.ID, Title: doc.Title})
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeContent, DocumentID: doc.ID, Content: doc.Content})
+	return doc, nil
+}
+
+// splitFrontMatter 拆出文件开头 --- 之间的 YAML 元数据，没有或无法解析时元数据为空、正文为原文
+func splitFrontMatter(text string) (map[string]any, string) {
+	if !strings.HasPrefix(text, "---\n") {
+		return nil, text
+	}
+	lines := strings.SplitAfter(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		if line := strings.TrimRight(lines[i], "\n"); line != "---" && line != "..." {
+			continue
+		}
+		meta := map[string]any{}
+		if err := yaml.Unmarshal([]byte(strings.Join(lines[1:i], "")), &meta); err != nil {
+			return nil, text
+		}
+		return meta, strings.TrimLeft(strings.Join(lines[i+1:], ""), "\n")
+	}
+	return nil, text
+}
+
+// frontMatterTags 标签可以是列表，也可以是逗号或空白分隔的字符串
+func frontMatterTags(value any) []string {
+	var tags []string
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			if s := strings.TrimSpace(fmt.Sprint(item)); s != "" {
+				tags = append(tags, s)
+			}
+		}
+	case string:
+		tags = strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	}
+	return tags
+}
+
+// frontMatterTime 解析 front-matter 中的日期，支持 YAML 时间和常见的日期格式
+func frontMatterTime(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+			if t, err := time.ParseInLocation(layout, strings.TrimSpace(v), time.Local); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
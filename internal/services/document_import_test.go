@@ -0,0 +1,51 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestReadImportFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Meeting notes.md")
+	text := "---\r\ntitle: Weekly sync\r\ntags: [work, \"#planning\"]\r\ncreated: 2024-03-01\r\n---\r\n\r\n# Agenda\r\n"
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2024, 5, 6, 7, 8, 9, 0, time.Local)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := readImportFile(path, "md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.title != "Weekly sync" {
+		t.Errorf("title = %q", file.title)
+	}
+	if !slices.Equal(file.tags, []string{"work", "#planning"}) {
+		t.Errorf("tags = %q", file.tags)
+	}
+	if file.createdAt.Format("2006-01-02") != "2024-03-01" || !file.updatedAt.Equal(mtime) {
+		t.Errorf("times = %v, %v", file.createdAt, file.updatedAt)
+	}
+	if want := "\n∞∞∞md\n# Agenda\n"; file.content != want {
+		t.Errorf("content = %q, want %q", file.content, want)
+	}
+}
+
+func TestSplitFrontMatter(t *testing.T) {
+	for _, text := range []string{"no front matter", "---\nnot: closed\n", "---\n: [bad yaml\n---\nbody"} {
+		if meta, body := splitFrontMatter(text); meta != nil || body != text {
+			t.Errorf("splitFrontMatter(%q) = %v, %q", text, meta, body)
+		}
+	}
+	meta, body := splitFrontMatter("---\ntags: a, b c\n...\nbody")
+	if body != "body" || !slices.Equal(frontMatterTags(meta["tags"]), []string{"a", "b", "c"}) {
+		t.Errorf("splitFrontMatter() = %v, %q", meta, body)
+	}
+}
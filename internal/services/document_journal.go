@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+	"voidraft/internal/common/excerpt"
+	"voidraft/internal/models"
+)
+
+// ErrDocumentAppendOnly 日志文档只能在末尾追加，归档的日志只读
+var ErrDocumentAppendOnly = errors.New("journal documents can only be appended to")
+
+const (
+	sqlInsertJournal = `
+INSERT INTO documents (title, content, created_at, updated_at, is_deleted, is_locked, preview, content_size, kind, journal_period)
+VALUES (?, ?, ?, ?, 0, 0, ?, ?, ?, ?)`
+
+	sqlListStaleJournals = `
+SELECT id, title, content, journal_period 
+FROM documents 
+WHERE is_deleted = 0 AND kind = 'journal' AND journal_period != ?`
+
+	// 轮换时清空日志文档，不受只能追加的限制
+	sqlResetJournal = `
+UPDATE documents 
+SET content = ?, preview = '', content_size = ?, updated_at = ?, journal_period = ?
+WHERE id = ? AND kind = 'journal'`
+)
+
+const (
+	// journalEmptyContent 日志文档轮换后的初始内容
+	journalEmptyContent = "\n∞∞∞text-a\n"
+	// journalRotateInterval 检查是否需要轮换的间隔，跨月或休眠唤醒后最迟在该间隔内归档
+	journalRotateInterval = time.Hour
+)
+
+// CreateJournal 新建只能追加的日志文档，每月自动归档
+func (ds *DocumentService) CreateJournal(title string) (*models.Document, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return nil, err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	doc := models.NewDocument(title, journalEmptyContent)
+	doc.Kind = models.DocumentKindJournal
+	doc.JournalPeriod = journalPeriod(time.Now())
+	id, err := ds.insertJournalLocked(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create journal: %w", err)
+	}
+	doc.ID = id
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeCreated, DocumentID: doc.ID, Title: doc.Title})
+	return doc, nil
+}
+
+// rotateJournals 将不属于当前月份的日志内容归档为只读文档并清空日志，返回新建的归档文档
+func (ds *DocumentService) rotateJournals(now time.Time) ([]*models.Document, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return nil, err
+	}
+
+	// 先于 mu 获取 patchMu，与补丁应用的加锁顺序一致
+	ds.patchMu.Lock()
+	defer ds.patchMu.Unlock()
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	period := journalPeriod(now)
+	stale, err := ds.staleJournalsLocked(period)
+	if err != nil {
+		return nil, err
+	}
+
+	var archived []*models.Document
+	for _, journal := range stale {
+		archive, err := ds.rotateJournalLocked(journal, period, now)
+		if err != nil {
+			return archived, fmt.Errorf("failed to rotate journal %d: %w", journal.ID, err)
+		}
+		if archive != nil {
+			archived = append(archived, archive)
+		}
+	}
+	return archived, nil
+}
+
+// rotateJournalsLoop 启动时及之后定期归档过期的日志文档
+func (ds *DocumentService) rotateJournalsLoop(ctx context.Context) {
+	ticker := time.NewTicker(journalRotateInterval)
+	defer ticker.Stop()
+	for {
+		archived, err := ds.rotateJournals(time.Now())
+		switch {
+		case errors.Is(err, ErrAppLocked):
+			// 应用锁定期间不读取文档，解锁后的下一次检查再归档
+		case err != nil:
+			ds.logger.Error("Failed to rotate journals", "error", err)
+		case len(archived) > 0:
+			ds.logger.Info("Journals rotated", "archived", len(archived))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// staleJournalsLocked 查询需要轮换的日志文档，调用方需持有 mu
+func (ds *DocumentService) staleJournalsLocked(period string) ([]*models.Document, error) {
+	rows, err := ds.databaseService.getDB().Query(sqlListStaleJournals, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journals: %w", err)
+	}
+	defer rows.Close()
+
+	var journals []*models.Document
+	for rows.Next() {
+		doc := &models.Document{Kind: models.DocumentKindJournal}
+		if err := rows.Scan(&doc.ID, &doc.Title, &doc.Content, &doc.JournalPeriod); err != nil {
+			return nil, fmt.Errorf("failed to scan journal row: %w", err)
+		}
+		journals = append(journals, doc)
+	}
+	return journals, rows.Err()
+}
+
+// rotateJournalLocked 归档并清空一个日志文档，没有记录时只更新月份，调用方需持有 patchMu 和 mu
+func (ds *DocumentService) rotateJournalLocked(journal *models.Document, period string, now time.Time) (*models.Document, error) {
+	var archive *models.Document
+	if journal.Content != journalEmptyContent && journal.Content != "" {
+		archive = models.NewDocument(journalArchiveTitle(journal.Title, journal.JournalPeriod), journal.Content)
+		archive.Kind = models.DocumentKindJournalArchive
+		archive.JournalPeriod = journal.JournalPeriod
+		id, err := ds.insertJournalLocked(archive)
+		if err != nil {
+			return nil, err
+		}
+		archive.ID = id
+	}
+
+	_, err := ds.databaseService.getDB().Exec(sqlResetJournal, journalEmptyContent, utf8.RuneCountInString(journalEmptyContent),
+		now.Format("2006-01-02 15:04:05"), period, journal.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 旧的撤销记录指向归档前的内容，全部失效；打开的编辑器以整体替换同步
+	delete(ds.undoStacks, journal.ID)
+	ds.emitPatched(journal.ID, models.DocumentPatch{From: 0, To: utf16Len(journal.Content), Insert: journalEmptyContent}, 0, false)
+
+	if archive != nil {
+		ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeCreated, DocumentID: archive.ID, Title: archive.Title})
+	}
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeContent, DocumentID: journal.ID, Content: journalEmptyContent})
+	return archive, nil
+}
+
+// insertJournalLocked 插入日志或归档文档，调用方需持有 mu
+func (ds *DocumentService) insertJournalLocked(doc *models.Document) (int64, error) {
+	result, err := ds.databaseService.getDB().Exec(sqlInsertJournal,
+		doc.Title, doc.Content, doc.CreatedAt, doc.UpdatedAt,
+		excerpt.Plain(doc.Content, excerpt.DefaultLength), utf8.RuneCountInString(doc.Content), doc.Kind, doc.JournalPeriod)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// journalPeriod 日志文档按月轮换的周期标识
+func journalPeriod(t time.Time) string {
+	return t.Format("2006-01")
+}
+
+// journalArchiveTitle 归档文档的标题
+func journalArchiveTitle(title, period string) string {
+	if period == "" {
+		return title + " (archive)"
+	}
+	return fmt.Sprintf("%s (%s)", title, period)
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"voidraft/internal/models"
+)
+
+func TestJournalPeriodAndArchiveTitle(t *testing.T) {
+	now := time.Date(2026, 1, 31, 23, 59, 0, 0, time.Local)
+	if got := journalPeriod(now); got != "2026-01" {
+		t.Fatalf("journalPeriod = %q", got)
+	}
+	if got := journalArchiveTitle("Worklog", "2025-12"); got != "Worklog (2025-12)" {
+		t.Fatalf("journalArchiveTitle = %q", got)
+	}
+	if got := journalArchiveTitle("Worklog", ""); got != "Worklog (archive)" {
+		t.Fatalf("journalArchiveTitle without period = %q", got)
+	}
+}
+
+func TestDocumentKindIsAppendOnly(t *testing.T) {
+	if models.DocumentKindNote.IsAppendOnly() {
+		t.Fatal("notes should be editable")
+	}
+	if !models.DocumentKindJournal.IsAppendOnly() || !models.DocumentKindJournalArchive.IsAppendOnly() {
+		t.Fatal("journals and archives should be append-only")
+	}
+}
@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"voidraft/internal/common/doclinks"
+	"voidraft/internal/models"
+)
+
+const (
+	sqlDeleteDocumentLinks = `
+DELETE FROM document_links WHERE source_id = ?`
+
+	sqlInsertDocumentLink = `
+INSERT OR IGNORE INTO document_links (source_id, target_id, target_title) VALUES (?, ?, ?)`
+
+	sqlGetLinkSource = `
+SELECT content, is_deleted FROM documents WHERE id = ?`
+
+	sqlListLinkSources = `
+SELECT id, content FROM documents`
+
+	// 按 ID 链接，或按标题链接且标题与目标文档当前标题相同（document_links.target_title 不区分大小写）
+	sqlGetBacklinks = `
+SELECT DISTINCT d.id, d.title, d.updated_at
+FROM document_links l
+JOIN documents d ON d.id = l.source_id
+WHERE d.is_deleted = 0 AND d.id != ?
+  AND (l.target_id = ? OR (l.target_id = 0 AND l.target_title = (SELECT title FROM documents WHERE id = ?)))
+ORDER BY d.updated_at DESC`
+
+	sqlResolveLinkTitle = `
+SELECT id FROM documents WHERE title = ? COLLATE NOCASE AND is_deleted = 0
+ORDER BY updated_at DESC LIMIT 1`
+
+	sqlResolveLinkID = `
+SELECT id FROM documents WHERE id = ? AND is_deleted = 0`
+
+	sqlCountOtherDocumentsWithTitle = `
+SELECT COUNT(*) FROM documents WHERE title = ? COLLATE NOCASE AND is_deleted = 0 AND id != ?`
+
+	sqlListTitleLinkSources = `
+SELECT DISTINCT d.id
+FROM document_links l
+JOIN documents d ON d.id = l.source_id
+WHERE l.target_id = 0 AND l.target_title = ? AND d.is_deleted = 0 AND d.is_locked = 0`
+
+	sqlGetDocumentTitle = `
+SELECT title FROM documents WHERE id = ?`
+)
+
+// ErrLinkNotFound 链接指向的文档不存在或已删除
+var ErrLinkNotFound = errors.New("linked document not found")
+
+// GetBacklinks 获取链接到指定文档的其他文档，按最后编辑时间倒序
+// 包括 voidraft://doc/<id> 链接和标题与文档当前标题相同的 [[标题]] 链接
+func (ds *DocumentService) GetBacklinks(documentID int64) ([]*models.Backlink, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	rows, err := ds.databaseService.getDB().Query(sqlGetBacklinks, documentID, documentID, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backlinks: %w", err)
+	}
+	defer rows.Close()
+
+	backlinks := []*models.Backlink{}
+	for rows.Next() {
+		link := &models.Backlink{}
+		if err := rows.Scan(&link.DocumentID, &link.Title, &link.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan backlink: %w", err)
+		}
+		backlinks = append(backlinks, link)
+	}
+	return backlinks, rows.Err()
+}
+
+// ResolveDocumentLink 解析 [[标题]]、标题或 voidraft://doc/<id> 链接，返回目标文档 ID
+// 多个文档标题相同时返回最近编辑的一个
+func (ds *DocumentService) ResolveDocumentLink(link string) (int64, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return 0, err
+	}
+	target, ok := doclinks.Resolve(link)
+	if !ok {
+		return 0, fmt.Errorf("invalid document link: %q", link)
+	}
+
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return 0, errors.New("database service not available")
+	}
+
+	var id int64
+	var err error
+	if target.DocumentID != 0 {
+		err = ds.databaseService.getDB().QueryRow(sqlResolveLinkID, target.DocumentID).Scan(&id)
+	} else {
+		err = ds.databaseService.getDB().QueryRow(sqlResolveLinkTitle, target.Title).Scan(&id)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrLinkNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve document link: %w", err)
+	}
+	return id, nil
+}
+
+// handleLinkChange 文档新建、内容变化、恢复或彻底删除后更新它发出的链接
+func (ds *DocumentService) handleLinkChange(event models.DocumentChangeEvent) {
+	switch event.Type {
+	case models.DocumentChangeCreated, models.DocumentChangeContent, models.DocumentChangeRestored, models.DocumentChangeDeleted:
+	default:
+		return
+	}
+	if err := ds.updateDocumentLinks(event.DocumentID); err != nil {
+		ds.logger.Error("Failed to update document links", "documentId", event.DocumentID, "error", err)
+	}
+}
+
+// updateDocumentLinks 按数据库中的最新内容重建文档发出的链接
+// 移入回收站的文档保留链接以便恢复，彻底删除后清除
+func (ds *DocumentService) updateDocumentLinks(id int64) error {
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return errors.New("database service not available")
+	}
+	db := ds.databaseService.getDB()
+
+	var content string
+	var isDeleted bool
+	err := db.QueryRow(sqlGetLinkSource, id).Scan(&content, &isDeleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		_, err = db.Exec(sqlDeleteDocumentLinks, id)
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read document: %w", err)
+	}
+	if isDeleted {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := replaceDocumentLinks(context.Background(), tx, id, content); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// replaceDocumentLinks 在事务中重写文档发出的链接
+func replaceDocumentLinks(ctx context.Context, tx *sql.Tx, id int64, content string) error {
+	if _, err := tx.ExecContext(ctx, sqlDeleteDocumentLinks, id); err != nil {
+		return fmt.Errorf("failed to clear document links: %w", err)
+	}
+	for _, link := range doclinks.Parse(content) {
+		if _, err := tx.ExecContext(ctx, sqlInsertDocumentLink, id, link.DocumentID, link.Title); err != nil {
+			return fmt.Errorf("failed to insert document link: %w", err)
+		}
+	}
+	return nil
+}
+
+// migrateDocumentLinks 数据迁移：为已有文档建立链接索引
+func migrateDocumentLinks(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, sqlListLinkSources)
+	if err != nil {
+		return err
+	}
+	sources := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var content string
+		if err := rows.Scan(&id, &content); err != nil {
+			rows.Close()
+			return err
+		}
+		sources[id] = content
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, content := range sources {
+		if err := replaceDocumentLinks(ctx, tx, id, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renameTitleLinks 文档改名后把其他文档中指向旧标题的 [[标题]] 链接改为新标题
+// 仍有其他文档使用旧标题时链接保持不变；以可撤销的补丁修改，打开的编辑器同步更新；锁定的文档不修改
+func (ds *DocumentService) renameTitleLinks(id int64, oldTitle, newTitle string) {
+	if ds.databaseService.ensureWritable() != nil {
+		return
+	}
+
+	sources, err := ds.titleLinkSources(id, oldTitle)
+	if err != nil {
+		ds.logger.Error("Failed to find links to renamed document", "documentId", id, "error", err)
+		return
+	}
+
+	for _, sourceID := range sources {
+		if err := ds.renameTitleLinksIn(sourceID, oldTitle, newTitle); err != nil {
+			ds.logger.Error("Failed to update links to renamed document", "documentId", id, "sourceId", sourceID, "error", err)
+		}
+	}
+}
+
+// titleLinkSources 以旧标题链接到文档的其他文档，旧标题仍属于其他文档时返回空
+func (ds *DocumentService) titleLinkSources(id int64, oldTitle string) ([]int64, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+	db := ds.databaseService.getDB()
+
+	var others int
+	if err := db.QueryRow(sqlCountOtherDocumentsWithTitle, oldTitle, id).Scan(&others); err != nil {
+		return nil, err
+	}
+	if others > 0 {
+		return nil, nil
+	}
+
+	rows, err := db.Query(sqlListTitleLinkSources, oldTitle)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var sourceID int64
+		if err := rows.Scan(&sourceID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, sourceID)
+	}
+	return ids, rows.Err()
+}
+
+// renameTitleLinksIn 从后往前替换文档中指向旧标题的链接，保留显示文字
+func (ds *DocumentService) renameTitleLinksIn(sourceID int64, oldTitle, newTitle string) error {
+	ds.patchMu.Lock()
+	defer ds.patchMu.Unlock()
+
+	doc, err := ds.GetDocumentByID(sourceID)
+	if err != nil || doc == nil || doc.IsDeleted {
+		return err
+	}
+
+	spans := doclinks.TitleSpans(doc.Content)
+	for i := len(spans) - 1; i >= 0; i-- {
+		span := spans[i]
+		if !strings.EqualFold(span.Title, oldTitle) {
+			continue
+		}
+		patch := models.DocumentPatch{
+			From:   utf16Len(doc.Content[:span.Start]),
+			To:     utf16Len(doc.Content[:span.End]),
+			Insert: doclinks.Format(newTitle, span.Label),
+			Expect: doc.Content[span.Start:span.End],
+			Label:  "Rename link",
+		}
+		if _, err := ds.applyDocumentPatchLocked(doc, patch); err != nil {
+			return err
+		}
+		doc.Content = doc.Content[:span.Start] + patch.Insert + doc.Content[span.End:]
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"voidraft/internal/models"
+)
+
+// documentIconMaxRunes 文档图标的最大字符数，足以容纳国旗和 ZWJ 组合的 emoji
+const documentIconMaxRunes = 8
+
+const (
+	sqlSetDocumentFavorite = `
+UPDATE documents SET is_favorite = ? WHERE id = ? AND is_deleted = 0`
+
+	sqlSetDocumentColorLabel = `
+UPDATE documents SET color_label = ? WHERE id = ? AND is_deleted = 0`
+
+	sqlSetDocumentIcon = `
+UPDATE documents SET icon = ? WHERE id = ? AND is_deleted = 0`
+)
+
+// SetDocumentFavorite 收藏或取消收藏文档
+func (ds *DocumentService) SetDocumentFavorite(id int64, favorite bool) error {
+	value := 0
+	if favorite {
+		value = 1
+	}
+	return ds.setDocumentMetadata(sqlSetDocumentFavorite, id, value)
+}
+
+// SetDocumentColorLabel 设置文档的颜色标签，为空时清除
+func (ds *DocumentService) SetDocumentColorLabel(id int64, color models.DocumentColor) error {
+	if !color.Valid() {
+		return fmt.Errorf("invalid color label: %s", color)
+	}
+	return ds.setDocumentMetadata(sqlSetDocumentColorLabel, id, color)
+}
+
+// SetDocumentIcon 设置文档的 emoji 图标，为空时清除
+func (ds *DocumentService) SetDocumentIcon(id int64, icon string) error {
+	icon = strings.TrimSpace(icon)
+	if err := validateDocumentIcon(icon); err != nil {
+		return err
+	}
+	return ds.setDocumentMetadata(sqlSetDocumentIcon, id, icon)
+}
+
+// setDocumentMetadata 修改收藏、颜色标签或图标，不更新修改时间
+func (ds *DocumentService) setDocumentMetadata(query string, id int64, value any) error {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return errors.New("database service not available")
+	}
+
+	result, err := ds.databaseService.getDB().Exec(query, value, id)
+	if err != nil {
+		return fmt.Errorf("failed to update document metadata: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("document not found: %d", id)
+	}
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeMetadata, DocumentID: id})
+	return nil
+}
+
+// validateDocumentIcon 图标只能是单个 emoji，不能包含字母、空白或控制字符
+// 键帽 emoji 以数字或 # * 开头，因此允许数字和符号
+func validateDocumentIcon(icon string) error {
+	if icon == "" {
+		return nil
+	}
+	if utf8.RuneCountInString(icon) > documentIconMaxRunes {
+		return errors.New("icon must be a single emoji")
+	}
+	for _, r := range icon {
+		if unicode.IsLetter(r) || unicode.IsSpace(r) || unicode.IsControl(r) || r == utf8.RuneError {
+			return errors.New("icon must be a single emoji")
+		}
+	}
+	return nil
+}
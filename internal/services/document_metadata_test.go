@@ -0,0 +1,25 @@
+package services
+
+import "testing"
+
+func TestValidateDocumentIcon(t *testing.T) {
+	tests := []struct {
+		icon  string
+		valid bool
+	}{
+		{"", true},
+		{"📝", true},
+		{"👩‍💻", true},
+		{"🇨🇳", true},
+		{"1️⃣", true},
+		{"ab", false},
+		{"📝 📝", false},
+		{"中", false},
+		{"🎉🎉🎉🎉🎉🎉🎉🎉🎉", false},
+	}
+	for _, tt := range tests {
+		if err := validateDocumentIcon(tt.icon); (err == nil) != tt.valid {
+			t.Errorf("validateDocumentIcon(%q) = %v, want valid %t", tt.icon, err, tt.valid)
+		}
+	}
+}
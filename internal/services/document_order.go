@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"voidraft/internal/models"
+)
+
+const (
+	sqlSetDocumentPinned = `
+UPDATE documents SET is_pinned = ? WHERE id = ? AND is_deleted = 0`
+
+	sqlSetDocumentSortOrder = `
+UPDATE documents SET sort_order = ? WHERE id = ? AND is_deleted = 0`
+)
+
+// PinDocument 置顶文档
+func (ds *DocumentService) PinDocument(id int64) error {
+	return ds.setDocumentPinned(id, true)
+}
+
+// UnpinDocument 取消置顶，文档回到手动排序中原来的位置
+func (ds *DocumentService) UnpinDocument(id int64) error {
+	return ds.setDocumentPinned(id, false)
+}
+
+// setDocumentPinned 修改置顶状态，不更新修改时间
+func (ds *DocumentService) setDocumentPinned(id int64, pinned bool) error {
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return errors.New("database service not available")
+	}
+
+	value := 0
+	if pinned {
+		value = 1
+	}
+	result, err := ds.databaseService.getDB().Exec(sqlSetDocumentPinned, value, id)
+	if err != nil {
+		return fmt.Errorf("failed to pin document: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("document not found: %d", id)
+	}
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangePinned, DocumentID: id})
+	return nil
+}
+
+// ReorderDocuments 按拖动后的顺序保存手动排序，ids 为列表中从上到下的文档
+// 通常传入完整列表，未包含的文档保留原来的排序值，已删除的文档会被忽略
+func (ds *DocumentService) ReorderDocuments(ids []int64) error {
+	if err := validateDocumentOrder(ids); err != nil {
+		return err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return errors.New("database service not available")
+	}
+
+	tx, err := ds.databaseService.getDB().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, id := range ids {
+		if _, err := tx.Exec(sqlSetDocumentSortOrder, i+1, id); err != nil {
+			return fmt.Errorf("failed to reorder documents: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to reorder documents: %w", err)
+	}
+
+	// 排序只影响列表，不通知内容相关的监听器
+	ds.invalidateCaches()
+	return nil
+}
+
+// validateDocumentOrder 排序列表不能为空或包含重复的文档
+func validateDocumentOrder(ids []int64) error {
+	if len(ids) == 0 {
+		return errors.New("document order is empty")
+	}
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return fmt.Errorf("document %d appears more than once", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
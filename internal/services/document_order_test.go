@@ -0,0 +1,86 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"voidraft/internal/models"
+)
+
+// TestPinAndReorderDocuments 置顶文档排在最前，手动排序在重新打开数据库后保留
+func TestPinAndReorderDocuments(t *testing.T) {
+	ds := newTestDocumentService(t)
+	a := createTestDocument(t, ds, "a", "\n∞∞∞text-a\na")
+	b := createTestDocument(t, ds, "b", "\n∞∞∞text-a\nb")
+	c := createTestDocument(t, ds, "c", "\n∞∞∞text-a\nc")
+	d := createTestDocument(t, ds, "d", "\n∞∞∞text-a\nd")
+
+	listOrder := func(ds *DocumentService) []int64 {
+		t.Helper()
+		docs, err := ds.ListAllDocumentsMeta()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return documentIDs(docs)
+	}
+	manualOrder := func(ds *DocumentService) []int64 {
+		t.Helper()
+		page, err := ds.ListDocuments(models.DocumentSortManual, models.SortAsc, models.DocumentListFilter{}, models.Pagination{Limit: 100})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return documentIDs(page.Items)
+	}
+
+	if err := ds.ReorderDocuments([]int64{c, a, d, b}); err != nil {
+		t.Fatal(err)
+	}
+	want := []int64{c, a, d, b}
+	if got := listOrder(ds); !reflect.DeepEqual(got, want) {
+		t.Errorf("after reorder = %v, want %v", got, want)
+	}
+	if got := manualOrder(ds); !reflect.DeepEqual(got, want) {
+		t.Errorf("manual sort after reorder = %v, want %v", got, want)
+	}
+
+	if err := ds.PinDocument(d); err != nil {
+		t.Fatal(err)
+	}
+	want = []int64{d, c, a, b}
+	if got := listOrder(ds); !reflect.DeepEqual(got, want) {
+		t.Errorf("after pin = %v, want %v", got, want)
+	}
+	if got := manualOrder(ds); !reflect.DeepEqual(got, want) {
+		t.Errorf("manual sort after pin = %v, want %v", got, want)
+	}
+
+	// 模拟重启：新的服务实例读取同一个数据库
+	reopened := NewDocumentService(ds.configService, ds.databaseService, nil, nil)
+	if got := listOrder(reopened); !reflect.DeepEqual(got, want) {
+		t.Errorf("after reopen = %v, want %v", got, want)
+	}
+
+	// 取消置顶后回到原来的手动排序位置
+	if err := reopened.UnpinDocument(d); err != nil {
+		t.Fatal(err)
+	}
+	want = []int64{c, a, d, b}
+	if got := listOrder(reopened); !reflect.DeepEqual(got, want) {
+		t.Errorf("after unpin = %v, want %v", got, want)
+	}
+}
+
+func TestReorderDocumentsInvalid(t *testing.T) {
+	ds := newTestDocumentService(t)
+	a := createTestDocument(t, ds, "a", "\n∞∞∞text-a\na")
+	b := createTestDocument(t, ds, "b", "\n∞∞∞text-a\nb")
+
+	if err := ds.ReorderDocuments(nil); err == nil {
+		t.Error("empty order should fail")
+	}
+	if err := ds.ReorderDocuments([]int64{a, b, a}); err == nil {
+		t.Error("duplicate ids should fail")
+	}
+	if err := ds.PinDocument(b + 100); err == nil {
+		t.Error("pinning a missing document should fail")
+	}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"unicode/utf16"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
+)
+
+// 每个文档保留的撤销记录数量
+const documentUndoLimit = 50
+
+// 错误定义
+var (
+	ErrPatchOutOfRange = errors.New("patch range is out of document bounds")
+	ErrPatchConflict   = errors.New("document changed in the patched range")
+	ErrNothingToUndo   = errors.New("nothing to undo")
+	ErrUndoStale       = errors.New("document changed since the edit, undo is no longer possible")
+)
+
+// patchUndoEntry 撤销记录，保存逆补丁和应用后的内容摘要
+type patchUndoEntry struct {
+	inverse models.DocumentPatch
+	after   [sha256.Size]byte
+}
+
+// ApplyDocumentPatch 对文档应用编辑补丁并记录撤销信息，前端通过 document:patched 事件同步到编辑器
+func (ds *DocumentService) ApplyDocumentPatch(id int64, patch models.DocumentPatch) (*models.DocumentPatchResult, error) {
+	ds.patchMu.Lock()
+	defer ds.patchMu.Unlock()
+
+	doc, err := ds.GetDocumentByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil || doc.IsDeleted {
+		return nil, fmt.Errorf("document not found: %d", id)
+	}
+	return ds.applyDocumentPatchLocked(doc, patch)
+}
+
+// AppendDocumentContent 在文档末尾追加内容，作为可撤销的补丁应用
+func (ds *DocumentService) AppendDocumentContent(id int64, text, label string) (*models.DocumentPatchResult, error) {
+	ds.patchMu.Lock()
+	defer ds.patchMu.Unlock()
+
+	doc, err := ds.GetDocumentByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil || doc.IsDeleted {
+		return nil, fmt.Errorf("document not found: %d", id)
+	}
+	end := utf16Len(doc.Content)
+	return ds.applyDocumentPatchLocked(doc, models.DocumentPatch{From: end, To: end, Insert: text, Label: label})
+}
+
+// applyDocumentPatchLocked 应用补丁并记录撤销信息，调用方需持有 patchMu
+func (ds *DocumentService) applyDocumentPatchLocked(doc *models.Document, patch models.DocumentPatch) (*models.DocumentPatchResult, error) {
+	id := doc.ID
+	content, inverse, err := applyPatch(doc.Content, patch)
+	if err != nil {
+		return nil, err
+	}
+	if err := ds.UpdateDocumentContent(id, content); err != nil {
+		return nil, err
+	}
+
+	stack := append(ds.undoStacks[id], patchUndoEntry{inverse: inverse, after: sha256.Sum256([]byte(content))})
+	if len(stack) > documentUndoLimit {
+		stack = stack[len(stack)-documentUndoLimit:]
+	}
+	ds.undoStacks[id] = stack
+
+	return ds.emitPatched(id, patch, len(stack), false), nil
+}
+
+// UndoDocumentPatch 撤销最近一次后端补丁，文档在此之后被修改过时拒绝撤销
+func (ds *DocumentService) UndoDocumentPatch(id int64) (*models.DocumentPatchResult, error) {
+	ds.patchMu.Lock()
+	defer ds.patchMu.Unlock()
+
+	stack := ds.undoStacks[id]
+	if len(stack) == 0 {
+		return nil, ErrNothingToUndo
+	}
+	entry := stack[len(stack)-1]
+
+	doc, err := ds.GetDocumentByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil || doc.IsDeleted {
+		return nil, fmt.Errorf("document not found: %d", id)
+	}
+	if sha256.Sum256([]byte(doc.Content)) != entry.after {
+		// 内容已被编辑器修改，旧的撤销记录全部失效
+		delete(ds.undoStacks, id)
+		return nil, ErrUndoStale
+	}
+
+	content, _, err := applyPatch(doc.Content, entry.inverse)
+	if err != nil {
+		return nil, err
+	}
+	if err := ds.UpdateDocumentContent(id, content); err != nil {
+		return nil, err
+	}
+
+	stack = stack[:len(stack)-1]
+	if len(stack) == 0 {
+		delete(ds.undoStacks, id)
+	} else {
+		ds.undoStacks[id] = stack
+	}
+	return ds.emitPatched(id, entry.inverse, len(stack), true), nil
+}
+
+// GetDocumentUndoDepth 获取文档可撤销的后端补丁数量
+func (ds *DocumentService) GetDocumentUndoDepth(id int64) int {
+	ds.patchMu.Lock()
+	defer ds.patchMu.Unlock()
+	return len(ds.undoStacks[id])
+}
+
+// emitPatched 构造补丁结果并通知前端
+func (ds *DocumentService) emitPatched(id int64, patch models.DocumentPatch, depth int, undo bool) *models.DocumentPatchResult {
+	result := &models.DocumentPatchResult{
+		DocumentID: id,
+		Patch:      patch,
+		Selection:  models.TextRange{From: patch.From, To: patch.From + utf16Len(patch.Insert)},
+		UndoDepth:  depth,
+		Undo:       undo,
+	}
+	helper.EmitEvent(constant.EVENT_DOCUMENT_PATCHED, result)
+	return result
+}
+
+// applyPatch 应用补丁，返回新内容和用于撤销的逆补丁
+func applyPatch(content string, patch models.DocumentPatch) (string, models.DocumentPatch, error) {
+	if patch.From < 0 || patch.To < patch.From {
+		return "", models.DocumentPatch{}, ErrPatchOutOfRange
+	}
+	start, ok := utf16ByteOffset(content, patch.From)
+	if !ok {
+		return "", models.DocumentPatch{}, ErrPatchOutOfRange
+	}
+	end, ok := utf16ByteOffset(content, patch.To)
+	if !ok {
+		return "", models.DocumentPatch{}, ErrPatchOutOfRange
+	}
+
+	if patch.Expect != "" && content[start:end] != patch.Expect {
+		return "", models.DocumentPatch{}, ErrPatchConflict
+	}
+
+	inverse := models.DocumentPatch{
+		From:   patch.From,
+		To:     patch.From + utf16Len(patch.Insert),
+		Insert: content[start:end],
+		Expect: patch.Insert,
+		Label:  patch.Label,
+	}
+	return content[:start] + patch.Insert + content[end:], inverse, nil
+}
+
+// utf16ByteOffset 将 UTF-16 偏移转换为字节偏移，落在代理对中间或越界时返回 false
+func utf16ByteOffset(s string, pos int) (int, bool) {
+	units := 0
+	for i, r := range s {
+		if units == pos {
+			return i, true
+		}
+		if units > pos {
+			return 0, false
+		}
+		units += utf16RuneLen(r)
+	}
+	if units == pos {
+		return len(s), true
+	}
+	return 0, false
+}
+
+// utf16Len 字符串的 UTF-16 长度
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16RuneLen(r)
+	}
+	return n
+}
+
+// utf16RuneLen 单个字符的 UTF-16 长度，无效字符按替换字符计
+func utf16RuneLen(r rune) int {
+	if n := utf16.RuneLen(r); n > 0 {
+		return n
+	}
+	return 1
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"voidraft/internal/models"
+)
+
+func TestApplyPatchUTF16(t *testing.T) {
+	content := "a😀b中c"
+	// 😀 占两个 UTF-16 码元，"b" 位于 3
+	got, inverse, err := applyPatch(content, models.DocumentPatch{From: 3, To: 4, Insert: "XY", Expect: "b"})
+	if err != nil || got != "a😀XY中c" {
+		t.Fatalf("applyPatch = %q, %v", got, err)
+	}
+	if inverse.From != 3 || inverse.To != 5 || inverse.Insert != "b" {
+		t.Fatalf("inverse = %+v", inverse)
+	}
+	if undone, _, err := applyPatch(got, inverse); err != nil || undone != content {
+		t.Fatalf("undo = %q, %v", undone, err)
+	}
+
+	if _, _, err := applyPatch(content, models.DocumentPatch{From: 2, To: 3}); !errors.Is(err, ErrPatchOutOfRange) {
+		t.Fatalf("patch inside surrogate pair: %v", err)
+	}
+	if _, _, err := applyPatch(content, models.DocumentPatch{From: 3, To: 4, Expect: "z"}); !errors.Is(err, ErrPatchConflict) {
+		t.Fatalf("stale expect: %v", err)
+	}
+}
+
+func TestComposeTranslation(t *testing.T) {
+	tests := []struct {
+		mode       models.TranslateSelectionMode
+		original   string
+		translated string
+		want       string
+	}{
+		{models.TranslateModeReplace, "hello", "你好\n", "你好"},
+		{models.TranslateModeAppend, "hello\n", "你好", "hello\n你好\n"},
+		{models.TranslateModeSideBySide, "one\n\ntwo", "一\n\n二", "one\n一\n\ntwo\n二"},
+		{models.TranslateModeSideBySide, "one\ntwo", "一二", "one\ntwo\n一二"},
+	}
+	for _, tt := range tests {
+		if got := composeTranslation(tt.original, tt.translated, tt.mode); got != tt.want {
+			t.Errorf("%s(%q) = %q, want %q", tt.mode, tt.original, got, tt.want)
+		}
+	}
+}
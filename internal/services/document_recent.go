@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+const sqlListRecentDocuments = `
+SELECT id, title, created_at, updated_at, is_locked, kind, open_count, last_opened_at, collection_id, is_favorite, color_label, icon
+FROM documents
+WHERE is_deleted = 0 AND is_archived = 0
+ORDER BY MAX(last_opened_at, updated_at) DESC, id DESC
+LIMIT ?`
+
+const (
+	// recentDocumentsDefaultLimit 未指定数量时返回的最近文档数
+	recentDocumentsDefaultLimit = 20
+	// recentDocumentsMaxLimit 一次最多返回的最近文档数
+	recentDocumentsMaxLimit = 200
+	// recentMenuSize 托盘子菜单中的最近文档数
+	recentMenuSize = 10
+	// recentMenuRefreshDelay 文档变化后重建托盘子菜单的延迟，期间的变化合并为一次重建
+	recentMenuRefreshDelay = 2 * time.Second
+	// recentMenuLabelLength 托盘菜单项名称的最大字符数
+	recentMenuLabelLength = 40
+)
+
+// GetRecentDocuments 获取最近打开或编辑的文档，按最近一次打开或编辑的时间从新到旧，不含内容和回收站、已归档的文档
+// limit 不大于 0 时使用默认数量
+func (ds *DocumentService) GetRecentDocuments(limit int) ([]*models.Document, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = recentDocumentsDefaultLimit
+	}
+	limit = min(limit, recentDocumentsMaxLimit)
+
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	rows, err := ds.databaseService.getDB().Query(sqlListRecentDocuments, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent documents: %w", err)
+	}
+	defer rows.Close()
+
+	documents := []*models.Document{}
+	for rows.Next() {
+		doc := &models.Document{}
+		if err := rows.Scan(&doc.ID, &doc.Title, &doc.CreatedAt, &doc.UpdatedAt, &doc.IsLocked, &doc.Kind,
+			&doc.OpenCount, &doc.LastOpenedAt, &doc.CollectionID, &doc.IsFavorite, &doc.ColorLabel, &doc.Icon); err != nil {
+			return nil, fmt.Errorf("failed to scan document: %w", err)
+		}
+		documents = append(documents, doc)
+	}
+	return documents, rows.Err()
+}
+
+// AttachRecentMenu 关联托盘中的最近文档子菜单，点击菜单项时以文档ID调用 open
+// 文档打开或修改、应用锁定或解锁后重建；锁定期间不显示文档标题，服务启动前关联时在启动后填充
+func (ds *DocumentService) AttachRecentMenu(menu *application.Menu, open func(id int64)) {
+	ds.recentMu.Lock()
+	first := ds.recentMenu == nil
+	ds.recentMenu = menu
+	ds.recentOpen = open
+	ds.recentMu.Unlock()
+
+	if first {
+		ds.onDocumentChange(ds.handleRecentChange)
+		ds.appLockService.onLockChanged(func(locked bool) {
+			ds.rebuildRecentMenu()
+		})
+	}
+	if ds.ctx != nil {
+		ds.rebuildRecentMenu()
+	}
+}
+
+// handleRecentChange 影响最近文档顺序或名称的变化
+func (ds *DocumentService) handleRecentChange(event models.DocumentChangeEvent) {
+	switch event.Type {
+	case models.DocumentChangeLocked, models.DocumentChangeTags, models.DocumentChangeMoved, models.DocumentChangePinned:
+		return
+	}
+	ds.scheduleRecentMenuRefresh()
+}
+
+// scheduleRecentMenuRefresh 延迟重建托盘子菜单，已在等待时不重复安排
+func (ds *DocumentService) scheduleRecentMenuRefresh() {
+	ds.recentMu.Lock()
+	defer ds.recentMu.Unlock()
+	if ds.recentMenu == nil || ds.recentPending {
+		return
+	}
+	ds.recentPending = true
+	time.AfterFunc(recentMenuRefreshDelay, func() {
+		ds.recentMu.Lock()
+		ds.recentPending = false
+		ds.recentMu.Unlock()
+		ds.rebuildRecentMenu()
+	})
+}
+
+// rebuildRecentMenu 按最近文档重建托盘子菜单
+func (ds *DocumentService) rebuildRecentMenu() {
+	// 先查询再加锁，避免与持有 mu 时安排重建的操作互相等待
+	documents, err := ds.GetRecentDocuments(recentMenuSize)
+
+	ds.recentMu.Lock()
+	defer ds.recentMu.Unlock()
+	if ds.recentMenu == nil {
+		return
+	}
+
+	ds.recentMenu.Clear()
+	switch {
+	case errors.Is(err, ErrAppLocked):
+		ds.recentMenu.Add("Locked").SetEnabled(false)
+	case err != nil:
+		ds.logger.Error("Failed to list recent documents", "error", err)
+		ds.recentMenu.Add("No recent documents").SetEnabled(false)
+	case len(documents) == 0:
+		ds.recentMenu.Add("No recent documents").SetEnabled(false)
+	}
+	open := ds.recentOpen
+	for _, doc := range documents {
+		id := doc.ID
+		ds.recentMenu.Add(recentMenuLabel(doc)).OnClick(func(data *application.Context) {
+			open(id)
+		})
+	}
+	ds.recentMenu.Update()
+}
+
+// recentMenuLabel 最近文档菜单项名称，带图标，过长时截断
+func recentMenuLabel(doc *models.Document) string {
+	label := strings.TrimSpace(doc.Title)
+	if label == "" {
+		label = untitledDocumentTitle
+	}
+	if runes := []rune(label); len(runes) > recentMenuLabelLength {
+		label = string(runes[:recentMenuLabelLength-1]) + "…"
+	}
+	if doc.Icon != "" {
+		label = doc.Icon + " " + label
+	}
+	return label
+}
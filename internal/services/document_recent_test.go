@@ -0,0 +1,25 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"voidraft/internal/models"
+)
+
+func TestRecentMenuLabel(t *testing.T) {
+	long := strings.Repeat("长", recentMenuLabelLength+5)
+	tests := []struct {
+		doc  models.Document
+		want string
+	}{
+		{models.Document{Title: "  Notes "}, "Notes"},
+		{models.Document{Title: ""}, untitledDocumentTitle},
+		{models.Document{Title: "Plan", Icon: "📌"}, "📌 Plan"},
+		{models.Document{Title: long}, strings.Repeat("长", recentMenuLabelLength-1) + "…"},
+	}
+	for _, tt := range tests {
+		if got := recentMenuLabel(&tt.doc); got != tt.want {
+			t.Errorf("recentMenuLabel(%q) = %q, want %q", tt.doc.Title, got, tt.want)
+		}
+	}
+}
@@ -3,60 +3,77 @@ package services
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
+	"voidraft/internal/common/cache"
+	"voidraft/internal/common/excerpt"
 	"voidraft/internal/models"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
 	"github.com/wailsapp/wails/v3/pkg/services/log"
 )
 
+// ErrDocumentLocked 锁定的文档只读，解锁后才能修改内容
+var ErrDocumentLocked = errors.New("document is locked")
+
 // SQL constants for document operations
 const (
 
 	// Document operations
 	sqlGetDocumentByID = `
-SELECT id, title, content, created_at, updated_at, is_deleted, is_locked 
+SELECT id, title, content, created_at, updated_at, is_deleted, is_locked, is_archived, kind, journal_period, deleted_at, collection_id, is_pinned, sort_order, title_auto, is_favorite, color_label, icon 
 FROM documents 
 WHERE id = ?`
 
 	sqlInsertDocument = `
-INSERT INTO documents (title, content, created_at, updated_at, is_deleted, is_locked)
-VALUES (?, ?, ?, ?, 0, 0)`
+INSERT INTO documents (title, content, created_at, updated_at, is_deleted, is_locked, content_size, title_auto)
+VALUES (?, ?, ?, ?, 0, 0, ?, ?)`
 
+	// 锁定的文档只读；日志文档只接受以原内容为前缀的新内容，即只能追加
 	sqlUpdateDocumentContent = `
 UPDATE documents 
-SET content = ?, updated_at = ?
-WHERE id = ? AND is_deleted = 0`
+SET content = ?1, preview = ?2, content_size = ?3, updated_at = ?4
+WHERE id = ?5 AND is_deleted = 0 AND is_locked = 0
+  AND (kind = '' OR (kind = 'journal' AND substr(?1, 1, length(content)) = content))`
+
+	sqlGetDocumentWriteState = `
+SELECT kind, is_locked FROM documents WHERE id = ? AND is_deleted = 0`
 
 	sqlUpdateDocumentTitle = `
 UPDATE documents 
-SET title = ?, updated_at = ?
+SET title = ?, updated_at = ?, title_auto = 0
 WHERE id = ? AND is_deleted = 0`
 
 	sqlMarkDocumentAsDeleted = `
 UPDATE documents 
-SET is_deleted = 1, updated_at = ?
-WHERE id = ? AND is_locked = 0`
+SET is_deleted = 1, updated_at = ?1, deleted_at = ?1
+WHERE id = ?2 AND is_locked = 0`
 
 	sqlRestoreDocument = `
 UPDATE documents 
-SET is_deleted = 0, updated_at = ?
-WHERE id = ?`
+SET is_deleted = 0, updated_at = ?, deleted_at = ''
+WHERE id = ? AND is_deleted = 1`
 
+	// 置顶文档在前，未手动排序的文档（sort_order 为 0）按修改时间排在已排序的文档之前
 	sqlListAllDocumentsMeta = `
-SELECT id, title, created_at, updated_at, is_locked 
+SELECT id, title, created_at, updated_at, is_locked, kind, collection_id, is_pinned, sort_order, is_favorite, color_label, icon 
 FROM documents 
-WHERE is_deleted = 0
-ORDER BY updated_at DESC`
+WHERE is_deleted = 0 AND is_archived = 0
+ORDER BY is_pinned DESC, sort_order, updated_at DESC`
 
+	// 升级前移入回收站的文档没有删除时间，移入时更新了修改时间，以此代替
 	sqlListDeletedDocumentsMeta = `
-SELECT id, title, created_at, updated_at, is_locked 
+SELECT id, title, created_at, updated_at, is_locked, kind, COALESCE(NULLIF(deleted_at, ''), updated_at) AS trashed_at
 FROM documents 
 WHERE is_deleted = 1
-ORDER BY updated_at DESC`
+ORDER BY trashed_at DESC`
 
 	sqlGetFirstDocumentID = `
 SELECT id FROM documents WHERE is_deleted = 0 ORDER BY id LIMIT 1`
@@ -73,26 +90,105 @@ UPDATE documents
 SET is_locked = 0, updated_at = ?
 WHERE id = ?`
 
+	sqlRecordDocumentOpened = `
+UPDATE documents
+SET open_count = open_count + 1, last_opened_at = ?
+WHERE id = ?`
+
+	sqlListDocumentsWithoutPreview = `
+SELECT id, content
+FROM documents
+WHERE (preview = '' OR content_size = 0) AND id > ?
+ORDER BY id
+LIMIT ?`
+
+	sqlUpdateDocumentPreview = `
+UPDATE documents SET preview = ?, content_size = ? WHERE id = ?`
+
 	sqlDefaultDocumentID = 1 // 默认文档的ID
 )
 
+// documentPreviewBatchSize 回填预览摘要时每批处理的文档数
+const documentPreviewBatchSize = 200
+
+// 文档列表分页
+const (
+	documentListDefaultLimit = 50
+	documentListMaxLimit     = 500
+)
+
+// 列表缓存有效期，变更事件会立即失效，有效期只作为兜底；常用度排序依赖当前时间，分页缓存有效期较短
+const (
+	documentMetaCacheTTL = 10 * time.Minute
+	documentPageCacheTTL = time.Minute
+)
+
+// documentSortExpressions 排序字段对应的SQL表达式，:ref 为常用度计算的参考时间
+var documentSortExpressions = map[models.DocumentSortField]string{
+	models.DocumentSortTitle:   "title COLLATE NOCASE",
+	models.DocumentSortCreated: "created_at",
+	models.DocumentSortUpdated: "updated_at",
+	models.DocumentSortSize:    "content_size",
+	// 打开次数按距上次打开的天数衰减，从未打开的文档视为一年前打开
+	models.DocumentSortFrecency: "(open_count * 1.0 / (1 + MAX(0, julianday(:ref) - COALESCE(julianday(NULLIF(last_opened_at, '')), julianday(:ref) - 365))))",
+	// 合成单个排序键以便键集分页，置顶文档的键整体小于未置顶文档
+	models.DocumentSortManual: "((1 - is_pinned) * 1000000000000 + sort_order)",
+}
+
+// documentCursor 键集分页游标
+type documentCursor struct {
+	Sort      models.DocumentSortField `json:"s"`
+	Direction models.SortDirection     `json:"d"`
+	Value     any                      `json:"v"`
+	ID        int64                    `json:"id"`
+	Ref       string                   `json:"ref"` // 首页查询时的参考时间，保证翻页时常用度不漂移
+}
+
 // DocumentService provides document management functionality
 type DocumentService struct {
+	configService   *ConfigService
 	databaseService *DatabaseService
+	appLockService  *AppLockService
 	logger          *log.LogService
 	mu              sync.RWMutex
 	ctx             context.Context
+
+	// 文档变更监听器
+	listenersMu sync.RWMutex
+	listeners   []documentChangeListener
+
+	// 热点列表查询缓存，文档变更时失效，减少侧边栏刷新时的重复查询
+	metaCache *cache.Cache[bool, []*models.Document]
+	pageCache *cache.Cache[string, *models.DocumentPage]
+
+	// 后端补丁的撤销记录，按文档保存
+	patchMu    sync.Mutex
+	undoStacks map[int64][]patchUndoEntry
+
+	// 托盘中的最近文档子菜单，见 document_recent.go
+	recentMu      sync.Mutex
+	recentMenu    *application.Menu
+	recentOpen    func(id int64)
+	recentPending bool
 }
 
+// documentChangeListener 文档变更监听器
+type documentChangeListener func(event models.DocumentChangeEvent)
+
 // NewDocumentService creates a new document service
-func NewDocumentService(databaseService *DatabaseService, logger *log.LogService) *DocumentService {
+func NewDocumentService(configService *ConfigService, databaseService *DatabaseService, appLockService *AppLockService, logger *log.LogService) *DocumentService {
 	if logger == nil {
 		logger = log.New()
 	}
 
 	ds := &DocumentService{
+		configService:   configService,
 		databaseService: databaseService,
+		appLockService:  appLockService,
 		logger:          logger,
+		metaCache:       cache.New[bool, []*models.Document](documentMetaCacheTTL),
+		pageCache:       cache.New[string, *models.DocumentPage](documentPageCacheTTL),
+		undoStacks:      make(map[int64][]patchUndoEntry),
 	}
 
 	return ds
@@ -102,22 +198,121 @@ func NewDocumentService(databaseService *DatabaseService, logger *log.LogService
 func (ds *DocumentService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
 	ds.ctx = ctx
 
+	// 内容变化时更新文档之间的链接和自动生成的标题
+	ds.onDocumentChange(ds.handleLinkChange)
+	ds.onDocumentChange(ds.handleAutoTitleChange)
+
+	// 预热文档列表缓存，应用锁定时也可预热，读取时再检查
+	go ds.metaCache.Get(false, ds.loadAllDocumentsMeta)
+
+	// 托盘菜单在服务启动前关联，启动后再填充最近文档
+	go ds.rebuildRecentMenu()
+
+	// 只读实例不做任何写入
+	if ds.databaseService.ensureWritable() != nil {
+		return nil
+	}
+
 	// 确保默认文档存在
 	if err := ds.ensureDefaultDocument(); err != nil {
 		return fmt.Errorf("failed to ensure default document: %w", err)
 	}
+
+	// 为升级前的文档回填预览摘要，不阻塞启动
+	go ds.backfillPreviews()
+
+	// 按月归档日志文档
+	go ds.rotateJournalsLoop(ctx)
+
+	// 按回收站保留天数自动清理
+	go ds.purgeTrashLoop(ctx)
+
+	// 定期为修改过的文档保存快照
+	go ds.snapshotLoop(ctx)
 	return nil
 }
 
+// backfillPreviews 为缺少预览摘要的文档分批生成摘要
+func (ds *DocumentService) backfillPreviews() {
+	if ds.databaseService.ensureWritable() != nil {
+		return
+	}
+	var lastID int64
+	filled := 0
+	for {
+		previews, nextID, err := ds.collectMissingPreviews(lastID)
+		if err != nil {
+			ds.logger.Error("Failed to generate document previews", "error", err)
+			return
+		}
+		if nextID == lastID {
+			break
+		}
+		lastID = nextID
+
+		ds.mu.Lock()
+		for id, preview := range previews {
+			if _, err := ds.databaseService.getDB().Exec(sqlUpdateDocumentPreview, preview.text, preview.size, id); err != nil {
+				ds.logger.Error("Failed to update document preview", "id", id, "error", err)
+			}
+		}
+		ds.mu.Unlock()
+		filled += len(previews)
+	}
+
+	if filled > 0 {
+		ds.logger.Info("Document previews generated", "count", filled)
+	}
+}
+
+// documentPreview 回填时生成的摘要和内容长度
+type documentPreview struct {
+	text string
+	size int
+}
+
+// collectMissingPreviews 读取一批缺少摘要的文档并生成摘要，返回本批最大的文档ID
+func (ds *DocumentService) collectMissingPreviews(afterID int64) (map[int64]documentPreview, int64, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, afterID, errors.New("database service not available")
+	}
+
+	rows, err := ds.databaseService.getDB().Query(sqlListDocumentsWithoutPreview, afterID, documentPreviewBatchSize)
+	if err != nil {
+		return nil, afterID, err
+	}
+	defer rows.Close()
+
+	previews := make(map[int64]documentPreview)
+	lastID := afterID
+	for rows.Next() {
+		var id int64
+		var content string
+		if err := rows.Scan(&id, &content); err != nil {
+			return nil, afterID, err
+		}
+		lastID = id
+		// 空文档的摘要仍为空，依靠ID递增避免重复处理
+		previews[id] = documentPreview{
+			text: excerpt.Plain(content, excerpt.DefaultLength),
+			size: utf8.RuneCountInString(content),
+		}
+	}
+	return previews, lastID, rows.Err()
+}
+
 // ensureDefaultDocument ensures a default document exists
 func (ds *DocumentService) ensureDefaultDocument() error {
-	if ds.databaseService == nil || ds.databaseService.db == nil {
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
 		return errors.New("database service not available")
 	}
 
 	// Check if any document exists
 	var count int64
-	err := ds.databaseService.db.QueryRow(sqlCountDocuments).Scan(&count)
+	err := ds.databaseService.getDB().QueryRow(sqlCountDocuments).Scan(&count)
 	if err != nil {
 		return fmt.Errorf("failed to query document count: %w", err)
 	}
@@ -133,17 +328,21 @@ func (ds *DocumentService) ensureDefaultDocument() error {
 
 // GetDocumentByID gets a document by ID
 func (ds *DocumentService) GetDocumentByID(id int64) (*models.Document, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+
 	ds.mu.RLock()
 	defer ds.mu.RUnlock()
 
-	if ds.databaseService == nil || ds.databaseService.db == nil {
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
 		return nil, errors.New("database service not available")
 	}
 
 	doc := &models.Document{}
-	var isDeleted, isLocked int
+	var isDeleted, isLocked, isArchived, isPinned, titleAuto, isFavorite int
 
-	err := ds.databaseService.db.QueryRow(sqlGetDocumentByID, id).Scan(
+	err := ds.databaseService.getDB().QueryRow(sqlGetDocumentByID, id).Scan(
 		&doc.ID,
 		&doc.Title,
 		&doc.Content,
@@ -151,6 +350,17 @@ func (ds *DocumentService) GetDocumentByID(id int64) (*models.Document, error) {
 		&doc.UpdatedAt,
 		&isDeleted,
 		&isLocked,
+		&isArchived,
+		&doc.Kind,
+		&doc.JournalPeriod,
+		&doc.DeletedAt,
+		&doc.CollectionID,
+		&isPinned,
+		&doc.SortOrder,
+		&titleAuto,
+		&isFavorite,
+		&doc.ColorLabel,
+		&doc.Icon,
 	)
 
 	if err != nil {
@@ -163,22 +373,38 @@ func (ds *DocumentService) GetDocumentByID(id int64) (*models.Document, error) {
 	// 转换布尔字段
 	doc.IsDeleted = isDeleted == 1
 	doc.IsLocked = isLocked == 1
+	doc.IsArchived = isArchived == 1
+	doc.IsPinned = isPinned == 1
+	doc.TitleAuto = titleAuto == 1
+	doc.IsFavorite = isFavorite == 1
 
 	return doc, nil
 }
 
 // CreateDocument creates a new document and returns the created document with ID
+// 标题为空时由内容自动生成，保存内容后随之更新
 func (ds *DocumentService) CreateDocument(title string) (*models.Document, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return nil, err
+	}
+
 	ds.mu.Lock()
 	defer ds.mu.Unlock()
 
-	if ds.databaseService == nil || ds.databaseService.db == nil {
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
 		return nil, errors.New("database service not available")
 	}
 	doc := models.NewDocument(title, "\n∞∞∞text-a\n")
+	if strings.TrimSpace(doc.Title) == "" {
+		doc.Title = autoTitle(doc.Content)
+		doc.TitleAuto = true
+	}
 	// 执行插入操作
-	result, err := ds.databaseService.db.Exec(sqlInsertDocument,
-		doc.Title, doc.Content, doc.CreatedAt, doc.UpdatedAt)
+	result, err := ds.databaseService.getDB().Exec(sqlInsertDocument,
+		doc.Title, doc.Content, doc.CreatedAt, doc.UpdatedAt, utf8.RuneCountInString(doc.Content), doc.TitleAuto)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create document: %w", err)
 	}
@@ -191,12 +417,17 @@ func (ds *DocumentService) CreateDocument(title string) (*models.Document, error
 
 	// 返回带ID的文档
 	doc.ID = lastID
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeCreated, DocumentID: doc.ID, Title: doc.Title})
 	return doc, nil
 }
 
-// LockDocument 锁定文档，防止删除
+// LockDocument 锁定文档，锁定后内容只读且无法删除
 func (ds *DocumentService) LockDocument(id int64) error {
-	if ds.databaseService == nil || ds.databaseService.db == nil {
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
 		return errors.New("database service not available")
 	}
 
@@ -221,16 +452,21 @@ func (ds *DocumentService) LockDocument(id int64) error {
 	ds.mu.Lock()
 	defer ds.mu.Unlock()
 
-	_, err = ds.databaseService.db.Exec(sqlSetDocumentLocked, time.Now().Format("2006-01-02 15:04:05"), id)
+	_, err = ds.databaseService.getDB().Exec(sqlSetDocumentLocked, time.Now().Format("2006-01-02 15:04:05"), id)
 	if err != nil {
 		return fmt.Errorf("failed to lock document: %w", err)
 	}
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeLocked, DocumentID: id})
 	return nil
 }
 
 // UnlockDocument 解锁文档
 func (ds *DocumentService) UnlockDocument(id int64) error {
-	if ds.databaseService == nil || ds.databaseService.db == nil {
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
 		return errors.New("database service not available")
 	}
 
@@ -252,48 +488,110 @@ func (ds *DocumentService) UnlockDocument(id int64) error {
 	ds.mu.Lock()
 	defer ds.mu.Unlock()
 
-	_, err = ds.databaseService.db.Exec(sqlSetDocumentUnlocked, time.Now().Format("2006-01-02 15:04:05"), id)
+	_, err = ds.databaseService.getDB().Exec(sqlSetDocumentUnlocked, time.Now().Format("2006-01-02 15:04:05"), id)
 	if err != nil {
 		return fmt.Errorf("failed to unlock document: %w", err)
 	}
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeLocked, DocumentID: id})
 	return nil
 }
 
 // UpdateDocumentContent updates the content of a document
+// 锁定的文档返回 ErrDocumentLocked，日志文档只能追加，否则返回 ErrDocumentAppendOnly
 func (ds *DocumentService) UpdateDocumentContent(id int64, content string) error {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
 	ds.mu.Lock()
 	defer ds.mu.Unlock()
 
-	if ds.databaseService == nil || ds.databaseService.db == nil {
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
 		return errors.New("database service not available")
 	}
 
-	_, err := ds.databaseService.db.Exec(sqlUpdateDocumentContent, content, time.Now().Format("2006-01-02 15:04:05"), id)
+	preview := excerpt.Plain(content, excerpt.DefaultLength)
+	size := utf8.RuneCountInString(content)
+	result, err := ds.databaseService.getDB().Exec(sqlUpdateDocumentContent, content, preview, size, time.Now().Format("2006-01-02 15:04:05"), id)
 	if err != nil {
 		return fmt.Errorf("failed to update document content: %w", err)
 	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		if err := contentWriteError(ds.databaseService.getDB().QueryRow(sqlGetDocumentWriteState, id)); err != nil {
+			return err
+		}
+	}
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeContent, DocumentID: id, Content: content})
+	return nil
+}
+
+// contentWriteError 内容更新未修改任何行时，按文档状态给出原因；文档不存在时返回 nil
+func contentWriteError(row *sql.Row) error {
+	var kind models.DocumentKind
+	var locked bool
+	if row.Scan(&kind, &locked) != nil {
+		return nil
+	}
+	switch {
+	case locked:
+		return ErrDocumentLocked
+	case kind.IsAppendOnly():
+		return ErrDocumentAppendOnly
+	}
 	return nil
 }
 
 // UpdateDocumentTitle updates the title of a document
+// 设置标题后不再自动生成；标题为空时恢复为由内容自动生成
 func (ds *DocumentService) UpdateDocumentTitle(id int64, title string) error {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
 	ds.mu.Lock()
 	defer ds.mu.Unlock()
 
-	if ds.databaseService == nil || ds.databaseService.db == nil {
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
 		return errors.New("database service not available")
 	}
 
-	_, err := ds.databaseService.db.Exec(sqlUpdateDocumentTitle, title, time.Now().Format("2006-01-02 15:04:05"), id)
+	var oldTitle string
+	_ = ds.databaseService.getDB().QueryRow(sqlGetDocumentTitle, id).Scan(&oldTitle)
+
+	now := time.Now().Format("2006-01-02 15:04:05")
+	if strings.TrimSpace(title) == "" {
+		if err := ds.resetDocumentTitleLocked(id, oldTitle, now); err != nil {
+			return fmt.Errorf("failed to reset document title: %w", err)
+		}
+		return nil
+	}
+
+	_, err := ds.databaseService.getDB().Exec(sqlUpdateDocumentTitle, title, now, id)
 	if err != nil {
 		return fmt.Errorf("failed to update document title: %w", err)
 	}
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeTitle, DocumentID: id, Title: title})
+
+	// 其他文档中的 [[旧标题]] 链接改为新标题
+	if oldTitle != "" && !strings.EqualFold(oldTitle, title) {
+		go ds.renameTitleLinks(id, oldTitle, title)
+	}
 	return nil
 }
 
-// DeleteDocument marks a document as deleted (default document with ID=1 cannot be deleted)
+// DeleteDocument moves a document to the trash (default document with ID=1 cannot be deleted)
 func (ds *DocumentService) DeleteDocument(id int64) error {
-	if ds.databaseService == nil || ds.databaseService.db == nil {
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
 		ds.logger.Error("database service not available")
 		return errors.New("database service not available")
 	}
@@ -319,39 +617,58 @@ func (ds *DocumentService) DeleteDocument(id int64) error {
 	ds.mu.Lock()
 	defer ds.mu.Unlock()
 
-	_, err = ds.databaseService.db.Exec(sqlMarkDocumentAsDeleted, time.Now().Format("2006-01-02 15:04:05"), id)
+	_, err = ds.databaseService.getDB().Exec(sqlMarkDocumentAsDeleted, time.Now().Format("2006-01-02 15:04:05"), id)
 	if err != nil {
-		return fmt.Errorf("failed to mark document as deleted: %w", err)
+		return fmt.Errorf("failed to move document to trash: %w", err)
 	}
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeDeleted, DocumentID: id})
 	return nil
 }
 
-// RestoreDocument restores a deleted document
+// RestoreDocument restores a document from the trash
 func (ds *DocumentService) RestoreDocument(id int64) error {
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
 	ds.mu.Lock()
 	defer ds.mu.Unlock()
 
-	if ds.databaseService == nil || ds.databaseService.db == nil {
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
 		return errors.New("database service not available")
 	}
 
-	_, err := ds.databaseService.db.Exec(sqlRestoreDocument, time.Now().Format("2006-01-02 15:04:05"), id)
+	result, err := ds.databaseService.getDB().Exec(sqlRestoreDocument, time.Now().Format("2006-01-02 15:04:05"), id)
 	if err != nil {
 		return fmt.Errorf("failed to restore document: %w", err)
 	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("document %d is not in the trash", id)
+	}
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeRestored, DocumentID: id})
 	return nil
 }
 
 // ListAllDocumentsMeta lists all active (non-deleted) document metadata
 func (ds *DocumentService) ListAllDocumentsMeta() ([]*models.Document, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+
+	docs, err := ds.metaCache.Get(false, ds.loadAllDocumentsMeta)
+	return cloneDocuments(docs), err
+}
+
+// loadAllDocumentsMeta 从数据库查询未删除的文档元数据
+func (ds *DocumentService) loadAllDocumentsMeta() ([]*models.Document, error) {
 	ds.mu.RLock()
 	defer ds.mu.RUnlock()
 
-	if ds.databaseService == nil || ds.databaseService.db == nil {
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
 		return nil, errors.New("database service not available")
 	}
 
-	rows, err := ds.databaseService.db.Query(sqlListAllDocumentsMeta)
+	rows, err := ds.databaseService.getDB().Query(sqlListAllDocumentsMeta)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list document meta: %w", err)
 	}
@@ -360,7 +677,7 @@ func (ds *DocumentService) ListAllDocumentsMeta() ([]*models.Document, error) {
 	var documents []*models.Document
 	for rows.Next() {
 		doc := &models.Document{IsDeleted: false}
-		var isLocked int
+		var isLocked, isPinned, isFavorite int
 
 		err := rows.Scan(
 			&doc.ID,
@@ -368,6 +685,13 @@ func (ds *DocumentService) ListAllDocumentsMeta() ([]*models.Document, error) {
 			&doc.CreatedAt,
 			&doc.UpdatedAt,
 			&isLocked,
+			&doc.Kind,
+			&doc.CollectionID,
+			&isPinned,
+			&doc.SortOrder,
+			&isFavorite,
+			&doc.ColorLabel,
+			&doc.Icon,
 		)
 
 		if err != nil {
@@ -375,6 +699,8 @@ func (ds *DocumentService) ListAllDocumentsMeta() ([]*models.Document, error) {
 		}
 
 		doc.IsLocked = isLocked == 1
+		doc.IsPinned = isPinned == 1
+		doc.IsFavorite = isFavorite == 1
 		documents = append(documents, doc)
 	}
 
@@ -385,16 +711,26 @@ func (ds *DocumentService) ListAllDocumentsMeta() ([]*models.Document, error) {
 	return documents, nil
 }
 
-// ListDeletedDocumentsMeta lists all deleted document metadata
-func (ds *DocumentService) ListDeletedDocumentsMeta() ([]*models.Document, error) {
+// ListTrashed lists the metadata of documents in the trash, most recently deleted first
+func (ds *DocumentService) ListTrashed() ([]*models.Document, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+
+	docs, err := ds.metaCache.Get(true, ds.loadDeletedDocumentsMeta)
+	return cloneDocuments(docs), err
+}
+
+// loadDeletedDocumentsMeta 从数据库查询已删除的文档元数据
+func (ds *DocumentService) loadDeletedDocumentsMeta() ([]*models.Document, error) {
 	ds.mu.RLock()
 	defer ds.mu.RUnlock()
 
-	if ds.databaseService == nil || ds.databaseService.db == nil {
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
 		return nil, errors.New("database service not available")
 	}
 
-	rows, err := ds.databaseService.db.Query(sqlListDeletedDocumentsMeta)
+	rows, err := ds.databaseService.getDB().Query(sqlListDeletedDocumentsMeta)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list deleted document meta: %w", err)
 	}
@@ -411,6 +747,8 @@ func (ds *DocumentService) ListDeletedDocumentsMeta() ([]*models.Document, error
 			&doc.CreatedAt,
 			&doc.UpdatedAt,
 			&isLocked,
+			&doc.Kind,
+			&doc.DeletedAt,
 		)
 
 		if err != nil {
@@ -427,3 +765,239 @@ func (ds *DocumentService) ListDeletedDocumentsMeta() ([]*models.Document, error
 
 	return documents, nil
 }
+
+// ListDocuments 按排序策略分页列出文档元数据，使用键集分页避免一次加载全部文档
+// 结果含内容预览，应用锁定时拒绝访问
+func (ds *DocumentService) ListDocuments(sort models.DocumentSortField, direction models.SortDirection, filter models.DocumentListFilter, pagination models.Pagination) (*models.DocumentPage, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	expr, ok := documentSortExpressions[sort]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort field: %s", sort)
+	}
+	if direction != models.SortAsc && direction != models.SortDesc {
+		return nil, fmt.Errorf("invalid sort direction: %s", direction)
+	}
+	if !filter.ColorLabel.Valid() {
+		return nil, fmt.Errorf("invalid color label: %s", filter.ColorLabel)
+	}
+
+	limit := pagination.Limit
+	if limit <= 0 {
+		limit = documentListDefaultLimit
+	}
+	limit = min(limit, documentListMaxLimit)
+
+	collection := "*"
+	if filter.CollectionID != nil {
+		collection = strconv.FormatInt(*filter.CollectionID, 10)
+	}
+	key := fmt.Sprintf("%s|%s|%t|%d|%s|%s|%s|%s|%t|%s", sort, direction, filter.LockedOnly, limit, pagination.Cursor, strings.TrimSpace(filter.Query), normalizeTagName(filter.Tag), collection, filter.FavoritesOnly, filter.ColorLabel)
+	page, err := ds.pageCache.Get(key, func() (*models.DocumentPage, error) {
+		return ds.loadDocumentPage(sort, direction, expr, filter, limit, pagination.Cursor)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.DocumentPage{Items: cloneDocuments(page.Items), NextCursor: page.NextCursor, Total: page.Total}, nil
+}
+
+// loadDocumentPage 从数据库查询一页文档元数据
+func (ds *DocumentService) loadDocumentPage(sort models.DocumentSortField, direction models.SortDirection, expr string, filter models.DocumentListFilter, limit int, cursorValue string) (*models.DocumentPage, error) {
+
+	cursor := &documentCursor{Sort: sort, Direction: direction, Ref: time.Now().Format("2006-01-02 15:04:05")}
+	if cursorValue != "" {
+		decoded, err := decodeDocumentCursor(cursorValue)
+		if err != nil {
+			return nil, err
+		}
+		if decoded.Sort != sort || decoded.Direction != direction {
+			return nil, errors.New("cursor does not match sort order")
+		}
+		cursor = decoded
+	}
+
+	var where strings.Builder
+	where.WriteString("is_deleted = 0 AND is_archived = 0")
+	var args []any
+	if query := strings.TrimSpace(filter.Query); query != "" {
+		where.WriteString(` AND title LIKE :query ESCAPE '\'`)
+		args = append(args, sql.Named("query", "%"+escapeLike(query)+"%"))
+	}
+	if filter.LockedOnly {
+		where.WriteString(" AND is_locked = 1")
+	}
+	if filter.FavoritesOnly {
+		where.WriteString(" AND is_favorite = 1")
+	}
+	if filter.ColorLabel != models.DocumentColorNone {
+		where.WriteString(" AND color_label = :color")
+		args = append(args, sql.Named("color", filter.ColorLabel))
+	}
+	if filter.CollectionID != nil {
+		where.WriteString(" AND collection_id = :collection")
+		args = append(args, sql.Named("collection", *filter.CollectionID))
+	}
+	if tag := normalizeTagName(filter.Tag); tag != "" {
+		where.WriteString(" AND id IN (" + sqlDocumentIDsByTag + ")")
+		args = append(args, sql.Named("tag", tag))
+	}
+	countWhere, countArgs := where.String(), args
+	if sort == models.DocumentSortFrecency {
+		args = append(args, sql.Named("ref", cursor.Ref))
+	}
+
+	op, order := ">", "ASC"
+	if direction == models.SortDesc {
+		op, order = "<", "DESC"
+	}
+	if cursorValue != "" {
+		fmt.Fprintf(&where, " AND (%[1]s %[2]s :cursor_value OR (%[1]s = :cursor_value AND id %[2]s :cursor_id))", expr, op)
+		args = append(args, sql.Named("cursor_value", cursor.Value), sql.Named("cursor_id", cursor.ID))
+	}
+
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	page := &models.DocumentPage{Items: []*models.Document{}}
+	countSQL := "SELECT COUNT(*) FROM documents WHERE " + countWhere
+	if err := ds.databaseService.getDB().QueryRow(countSQL, countArgs...).Scan(&page.Total); err != nil {
+		return nil, fmt.Errorf("failed to count documents: %w", err)
+	}
+
+	listSQL := fmt.Sprintf(`
+SELECT id, title, created_at, updated_at, is_locked, open_count, last_opened_at, preview, content_size, collection_id, is_pinned, sort_order, is_favorite, color_label, icon, %s
+FROM documents
+WHERE %s
+ORDER BY %s %s, id %s
+LIMIT %d`, expr, where.String(), expr, order, order, limit+1)
+
+	rows, err := ds.databaseService.getDB().Query(listSQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	defer rows.Close()
+
+	var lastKey any
+	for rows.Next() {
+		doc := &models.Document{}
+		var isLocked, isPinned, isFavorite int
+		var key any
+		if err := rows.Scan(&doc.ID, &doc.Title, &doc.CreatedAt, &doc.UpdatedAt, &isLocked,
+			&doc.OpenCount, &doc.LastOpenedAt, &doc.Preview, &doc.Size, &doc.CollectionID, &isPinned, &doc.SortOrder,
+			&isFavorite, &doc.ColorLabel, &doc.Icon, &key); err != nil {
+			return nil, fmt.Errorf("failed to scan document row: %w", err)
+		}
+		doc.IsLocked = isLocked == 1
+		doc.IsPinned = isPinned == 1
+		doc.IsFavorite = isFavorite == 1
+
+		if len(page.Items) == limit {
+			// 多查询的一行仅用于判断是否还有下一页
+			next := *cursor
+			next.Value = lastKey
+			next.ID = page.Items[len(page.Items)-1].ID
+			page.NextCursor = encodeDocumentCursor(&next)
+			break
+		}
+		page.Items = append(page.Items, doc)
+		lastKey = key
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating document rows: %w", err)
+	}
+
+	return page, nil
+}
+
+// RecordDocumentOpened 记录文档被打开，用于常用度排序和最近文档
+func (ds *DocumentService) RecordDocumentOpened(id int64) error {
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return errors.New("database service not available")
+	}
+
+	if _, err := ds.databaseService.getDB().Exec(sqlRecordDocumentOpened, time.Now().Format("2006-01-02 15:04:05"), id); err != nil {
+		return fmt.Errorf("failed to record document opened: %w", err)
+	}
+	// 打开记录只影响常用度排序和最近文档
+	ds.pageCache.Invalidate()
+	ds.scheduleRecentMenuRefresh()
+	return nil
+}
+
+// encodeDocumentCursor 编码分页游标
+func encodeDocumentCursor(cursor *documentCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeDocumentCursor 解码分页游标
+func decodeDocumentCursor(value string) (*documentCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	cursor := &documentCursor{}
+	if err := json.Unmarshal(data, cursor); err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return cursor, nil
+}
+
+// invalidateCaches 失效所有列表缓存
+func (ds *DocumentService) invalidateCaches() {
+	ds.metaCache.Invalidate()
+	ds.pageCache.Invalidate()
+}
+
+// cloneDocuments 复制缓存中的文档，避免调用方修改共享数据
+func cloneDocuments(docs []*models.Document) []*models.Document {
+	if docs == nil {
+		return nil
+	}
+	cloned := make([]*models.Document, len(docs))
+	for i, doc := range docs {
+		copied := *doc
+		cloned[i] = &copied
+	}
+	return cloned
+}
+
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// onDocumentChange 注册文档变更监听器，供其他后端服务订阅
+func (ds *DocumentService) onDocumentChange(listener documentChangeListener) {
+	ds.listenersMu.Lock()
+	defer ds.listenersMu.Unlock()
+	ds.listeners = append(ds.listeners, listener)
+}
+
+// notifyDocumentChange 通知所有监听器，监听器异步执行，不阻塞写入操作
+func (ds *DocumentService) notifyDocumentChange(event models.DocumentChangeEvent) {
+	// 缓存同步失效，保证变更后的下一次查询读到最新数据
+	ds.invalidateCaches()
+
+	ds.listenersMu.RLock()
+	listeners := make([]documentChangeListener, len(ds.listeners))
+	copy(listeners, ds.listeners)
+	ds.listenersMu.RUnlock()
+
+	for _, listener := range listeners {
+		go listener(event)
+	}
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"voidraft/internal/models"
+)
+
+// TestListDocumentsKeysetPaging 逐页读取各排序方式的全部文档，排序键大量相同时也不能重复或遗漏
+func TestListDocumentsKeysetPaging(t *testing.T) {
+	ds := newTestDocumentService(t)
+	rows := []struct {
+		title      string
+		created    string
+		size       int
+		openCount  int
+		lastOpened string
+		pinned     bool
+		sortOrder  int
+	}{
+		{"alpha", "2026-01-01 00:00:00", 10, 3, "2026-10-01 00:00:00", false, 2},
+		{"Alpha", "2026-01-01 00:00:00", 10, 3, "2026-10-01 00:00:00", true, 2},
+		{"ALPHA", "2026-01-01 00:00:00", 10, 0, "", false, 2},
+		{"beta", "2026-02-01 00:00:00", 10, 0, "", true, 2},
+		{"alpha", "2026-02-01 00:00:00", 20, 3, "2026-10-01 00:00:00", false, 1},
+		{"gamma", "2026-01-01 00:00:00", 20, 0, "", false, 2},
+		{"beta", "2026-02-01 00:00:00", 10, 1, "2026-10-10 00:00:00", false, 1},
+	}
+	db := ds.databaseService.getDB()
+	for _, row := range rows {
+		id := createTestDocument(t, ds, row.title, "\n∞∞∞text-a\n")
+		_, err := db.Exec(`UPDATE documents SET title = ?, created_at = ?, updated_at = ?, content_size = ?, open_count = ?,
+last_opened_at = ?, is_pinned = ?, sort_order = ? WHERE id = ?`,
+			row.title, row.created, row.created, row.size, row.openCount, row.lastOpened, row.pinned, row.sortOrder, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sorts := []models.DocumentSortField{
+		models.DocumentSortTitle,
+		models.DocumentSortCreated,
+		models.DocumentSortUpdated,
+		models.DocumentSortSize,
+		models.DocumentSortFrecency,
+		models.DocumentSortManual,
+	}
+	for _, sort := range sorts {
+		for _, direction := range []models.SortDirection{models.SortAsc, models.SortDesc} {
+			t.Run(string(sort)+"/"+string(direction), func(t *testing.T) {
+				// 一次取完的结果作为基准顺序
+				full, err := ds.ListDocuments(sort, direction, models.DocumentListFilter{}, models.Pagination{Limit: 100})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(full.Items) != len(rows) || full.Total != len(rows) || full.NextCursor != "" {
+					t.Fatalf("single page = %d items, total %d, cursor %q", len(full.Items), full.Total, full.NextCursor)
+				}
+				want := documentIDs(full.Items)
+
+				var got []int64
+				cursor := ""
+				for pages := 0; ; pages++ {
+					if pages > len(rows) {
+						t.Fatal("paging did not terminate")
+					}
+					page, err := ds.ListDocuments(sort, direction, models.DocumentListFilter{}, models.Pagination{Limit: 2, Cursor: cursor})
+					if err != nil {
+						t.Fatal(err)
+					}
+					if page.Total != len(rows) {
+						t.Errorf("page total = %d, want %d", page.Total, len(rows))
+					}
+					got = append(got, documentIDs(page.Items)...)
+					if page.NextCursor == "" {
+						break
+					}
+					cursor = page.NextCursor
+				}
+
+				if len(got) != len(want) {
+					t.Fatalf("paged ids = %v, want %v", got, want)
+				}
+				for i := range want {
+					if got[i] != want[i] {
+						t.Fatalf("paged ids = %v, want %v", got, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestListDocumentsOrder 检查排序键相同时按 ID 排序，以及置顶文档在手动排序中靠前
+func TestListDocumentsOrder(t *testing.T) {
+	ds := newTestDocumentService(t)
+	var ids []int64
+	for _, title := range []string{"b", "A", "a", "B"} {
+		ids = append(ids, createTestDocument(t, ds, title, "\n∞∞∞text-a\n"))
+	}
+	db := ds.databaseService.getDB()
+	if _, err := db.Exec(`UPDATE documents SET sort_order = 5, is_pinned = 0`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`UPDATE documents SET is_pinned = 1 WHERE id = ?`, ids[3]); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		sort      models.DocumentSortField
+		direction models.SortDirection
+		want      []int64
+	}{
+		// 标题不区分大小写，相同标题按 ID 排序
+		{models.DocumentSortTitle, models.SortAsc, []int64{ids[1], ids[2], ids[0], ids[3]}},
+		{models.DocumentSortTitle, models.SortDesc, []int64{ids[3], ids[0], ids[2], ids[1]}},
+		{models.DocumentSortManual, models.SortAsc, []int64{ids[3], ids[0], ids[1], ids[2]}},
+	}
+	for _, tt := range tests {
+		page, err := ds.ListDocuments(tt.sort, tt.direction, models.DocumentListFilter{}, models.Pagination{Limit: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := documentIDs(page.Items)
+		for page.NextCursor != "" {
+			if page, err = ds.ListDocuments(tt.sort, tt.direction, models.DocumentListFilter{}, models.Pagination{Limit: 1, Cursor: page.NextCursor}); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, documentIDs(page.Items)...)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s %s = %v, want %v", tt.sort, tt.direction, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s %s = %v, want %v", tt.sort, tt.direction, got, tt.want)
+				break
+			}
+		}
+	}
+
+	if _, err := ds.ListDocuments(models.DocumentSortTitle, models.SortDesc, models.DocumentListFilter{},
+		models.Pagination{Limit: 1, Cursor: encodeDocumentCursor(&documentCursor{Sort: models.DocumentSortTitle, Direction: models.SortAsc})}); err == nil {
+		t.Error("cursor from another sort direction should be rejected")
+	}
+}
+
+// TestLockedDocumentIsReadOnly 锁定的文档拒绝内容修改、拆分、合并和删除，批量操作跳过它们
+func TestLockedDocumentIsReadOnly(t *testing.T) {
+	ds := newTestDocumentService(t)
+	const content = "\n∞∞∞text-a\nfirst\n∞∞∞text-a\nsecond"
+	// 第一个文档是默认文档，不可删除，先占位
+	createTestDocument(t, ds, "Default", "\n∞∞∞text-a\n")
+	locked := createTestDocument(t, ds, "Reference", content)
+	other := createTestDocument(t, ds, "Other", "\n∞∞∞text-a\nother")
+	if err := ds.LockDocument(locked); err != nil {
+		t.Fatal(err)
+	}
+	countDocuments := func() int {
+		var n int
+		if err := ds.databaseService.getDB().QueryRow(`SELECT COUNT(*) FROM documents WHERE is_deleted = 0`).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	before := countDocuments()
+
+	if err := ds.UpdateDocumentContent(locked, "changed"); !errors.Is(err, ErrDocumentLocked) {
+		t.Errorf("UpdateDocumentContent error = %v, want ErrDocumentLocked", err)
+	}
+	if _, err := ds.AppendDocumentContent(locked, "\nmore", "append"); !errors.Is(err, ErrDocumentLocked) {
+		t.Errorf("AppendDocumentContent error = %v, want ErrDocumentLocked", err)
+	}
+	if _, err := ds.SplitDocument(locked, ""); !errors.Is(err, ErrDocumentLocked) {
+		t.Errorf("SplitDocument error = %v, want ErrDocumentLocked", err)
+	}
+	if _, err := ds.MergeDocuments([]int64{other, locked}, ""); err == nil {
+		t.Error("merging a locked document into another should fail")
+	}
+	if _, err := ds.MergeDocuments([]int64{locked, other}, ""); err == nil {
+		t.Error("merging into a locked document should fail")
+	}
+	if err := ds.DeleteDocument(locked); err == nil {
+		t.Error("DeleteDocument of a locked document should fail")
+	}
+
+	result, err := ds.BulkDelete([]int64{locked})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Succeeded) != 0 || len(result.Skipped) != 1 || result.Skipped[0].Reason != bulkSkipLocked {
+		t.Errorf("BulkDelete result = %+v, want locked document skipped", result)
+	}
+	result, err = ds.BulkExport([]int64{locked}, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Succeeded) != 0 || len(result.Skipped) != 1 || result.Skipped[0].Reason != bulkSkipLocked {
+		t.Errorf("BulkExport result = %+v, want locked document skipped", result)
+	}
+
+	// 所有操作都不应留下修改
+	doc, err := ds.GetDocumentByID(locked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Content != content || doc.IsDeleted {
+		t.Errorf("locked document changed: deleted=%t content=%q", doc.IsDeleted, doc.Content)
+	}
+	if got := countDocuments(); got != before {
+		t.Errorf("documents = %d, want %d", got, before)
+	}
+
+	// 解锁后可以正常修改
+	if err := ds.UnlockDocument(locked); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.UpdateDocumentContent(locked, "\n∞∞∞text-a\nchanged"); err != nil {
+		t.Errorf("UpdateDocumentContent after unlock error = %v", err)
+	}
+}
+
+// documentIDs 文档ID列表
+func documentIDs(docs []*models.Document) []int64 {
+	ids := make([]int64, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return ids
+}
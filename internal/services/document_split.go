@@ -0,0 +1,178 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/excerpt"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
+)
+
+const (
+	sqlGetDocumentForSplit = `
+SELECT title, content, is_deleted, collection_id FROM documents WHERE id = ?`
+
+	sqlInsertSplitDocument = `
+INSERT INTO documents (title, content, preview, created_at, updated_at, is_deleted, is_locked, content_size, title_auto, collection_id)
+VALUES (?, ?, ?, ?, ?, 0, 0, ?, 1, ?)`
+)
+
+// SplitDocument 按分隔行把文档拆分为多个文档，第一部分留在原文档，其余部分依次创建为新文档
+// delimiter 为空时每个块拆为一个文档；新文档继承原文档的标签和所在文件夹，标题由内容生成
+// 锁定的文档不能拆分；拆分前为原文档保存快照，拆分后的内容也记入历史版本；返回原文档和新建的文档
+func (ds *DocumentService) SplitDocument(id int64, delimiter string) ([]*models.Document, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return nil, err
+	}
+
+	first, created, err := ds.splitDocument(id, delimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeContent, DocumentID: id, Content: first})
+	for _, doc := range created {
+		ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeCreated, DocumentID: doc.ID, Title: doc.Title})
+		ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeContent, DocumentID: doc.ID, Content: doc.Content})
+	}
+	helper.EmitEvent(constant.EVENT_TAGS_CHANGED, int64(0))
+
+	doc, err := ds.GetDocumentByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*models.Document{doc}, created...), nil
+}
+
+// splitDocument 在事务中拆分文档，返回原文档的新内容和新建的文档
+func (ds *DocumentService) splitDocument(id int64, delimiter string) (string, []*models.Document, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return "", nil, errors.New("database service not available")
+	}
+
+	tx, err := ds.databaseService.getDB().Begin()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var title, content string
+	var deleted bool
+	var collectionID int64
+	err = tx.QueryRow(sqlGetDocumentForSplit, id).Scan(&title, &content, &deleted, &collectionID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows) || (err == nil && deleted):
+		return "", nil, fmt.Errorf("document not found: %d", id)
+	case err != nil:
+		return "", nil, fmt.Errorf("failed to get document %d: %w", id, err)
+	}
+
+	parts := splitContent(content, delimiter)
+	if len(parts) < 2 {
+		return "", nil, errors.New("nothing to split: the document has only one part")
+	}
+
+	now := time.Now()
+	if _, err := ds.saveVersionLocked(tx, id, title, content, now, true); err != nil {
+		return "", nil, fmt.Errorf("failed to snapshot document %d: %w", id, err)
+	}
+
+	updatedAt := now.Format("2006-01-02 15:04:05")
+	first := parts[0]
+	result, err := tx.Exec(sqlUpdateDocumentContent,
+		first, excerpt.Plain(first, excerpt.DefaultLength), utf8.RuneCountInString(first), updatedAt, id)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to update document content: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		if err := contentWriteError(tx.QueryRow(sqlGetDocumentWriteState, id)); err != nil {
+			return "", nil, err
+		}
+		return "", nil, fmt.Errorf("document not found: %d", id)
+	}
+	if _, err := ds.saveVersionLocked(tx, id, title, first, now, true); err != nil {
+		return "", nil, fmt.Errorf("failed to snapshot document %d: %w", id, err)
+	}
+
+	created := make([]*models.Document, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		doc := models.NewDocument(autoTitle(part), part)
+		doc.TitleAuto = true
+		doc.CollectionID = collectionID
+		result, err := tx.Exec(sqlInsertSplitDocument,
+			doc.Title, doc.Content, excerpt.Plain(part, excerpt.DefaultLength), updatedAt, updatedAt, utf8.RuneCountInString(part), collectionID)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to create document: %w", err)
+		}
+		if doc.ID, err = result.LastInsertId(); err != nil {
+			return "", nil, fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+		if _, err := tx.Exec(sqlCopyDocumentTags, doc.ID, id); err != nil {
+			return "", nil, fmt.Errorf("failed to copy tags to document %d: %w", doc.ID, err)
+		}
+		if _, err := ds.saveVersionLocked(tx, doc.ID, doc.Title, part, now, true); err != nil {
+			return "", nil, fmt.Errorf("failed to snapshot document %d: %w", doc.ID, err)
+		}
+		created = append(created, doc)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", nil, fmt.Errorf("failed to split document: %w", err)
+	}
+	return first, created, nil
+}
+
+// splitContent 按分隔行拆分文档内容，delimiter 为空白时每个块为一部分
+// 分隔行是去掉首尾空白后等于 delimiter 的行，本身不保留；被分隔行截断的块在下一部分沿用原块的语言
+// 只含空白的块和部分都会丢弃
+func splitContent(content, delimiter string) []string {
+	delimiter = strings.TrimSpace(delimiter)
+
+	var parts []string
+	var current []blocks.Block
+	flush := func() {
+		var kept []blocks.Block
+		for _, block := range current {
+			if strings.TrimSpace(block.Content) != "" {
+				kept = append(kept, block)
+			}
+		}
+		if len(kept) > 0 {
+			parts = append(parts, blocks.Serialize(kept))
+		}
+		current = nil
+	}
+
+	for _, block := range blocks.Parse(content) {
+		if delimiter == "" {
+			current = append(current, block)
+			flush()
+			continue
+		}
+		lines := strings.Split(block.Content, "\n")
+		start := 0
+		for i, line := range lines {
+			if strings.TrimSpace(line) != delimiter {
+				continue
+			}
+			current = append(current, blocks.Block{Language: block.Language, Auto: block.Auto, Content: strings.Join(lines[start:i], "\n")})
+			flush()
+			start = i + 1
+		}
+		current = append(current, blocks.Block{Language: block.Language, Auto: block.Auto, Content: strings.Join(lines[start:], "\n")})
+	}
+	flush()
+	return parts
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitContent(t *testing.T) {
+	content := "\n∞∞∞text-a\nfirst\n---\nsecond\n∞∞∞md\n# third\n  ---  \n\n∞∞∞text-a\n  \n"
+	tests := []struct {
+		name      string
+		delimiter string
+		want      []string
+	}{
+		{
+			name:      "by block",
+			delimiter: "",
+			want:      []string{"\n∞∞∞text-a\nfirst\n---\nsecond", "\n∞∞∞md\n# third\n  ---  \n"},
+		},
+		{
+			name:      "by delimiter line",
+			delimiter: "---",
+			want:      []string{"\n∞∞∞text-a\nfirst", "\n∞∞∞text-a\nsecond\n∞∞∞md\n# third"},
+		},
+		{
+			name:      "delimiter not found",
+			delimiter: "===",
+			want:      []string{"\n∞∞∞text-a\nfirst\n---\nsecond\n∞∞∞md\n# third\n  ---  \n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitContent(content, tt.delimiter); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitContent(%q) = %q, want %q", tt.delimiter, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
+)
+
+const (
+	// sqlDocumentIDsByTag 带有 :tag 标签的文档ID，标签名不区分大小写
+	sqlDocumentIDsByTag = `
+SELECT dt.document_id FROM document_tags dt JOIN tags t ON t.id = dt.tag_id WHERE t.name = :tag`
+
+	sqlInsertTag = `
+INSERT INTO tags (name, created_at) VALUES (?, ?)
+ON CONFLICT(name) DO NOTHING`
+
+	sqlGetTagByName = `
+SELECT id, name, created_at FROM tags WHERE name = ?`
+
+	sqlAddDocumentTag = `
+INSERT OR IGNORE INTO document_tags (document_id, tag_id) VALUES (?, ?)`
+
+	sqlRemoveDocumentTag = `
+DELETE FROM document_tags
+WHERE document_id = ? AND tag_id = (SELECT id FROM tags WHERE name = ?)`
+
+	sqlDeleteUnusedTags = `
+DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM document_tags)`
+
+	sqlListTags = `
+SELECT t.id, t.name, t.created_at, COUNT(d.id)
+FROM tags t
+LEFT JOIN document_tags dt ON dt.tag_id = t.id
+LEFT JOIN documents d ON d.id = dt.document_id AND d.is_deleted = 0
+GROUP BY t.id
+ORDER BY t.name COLLATE NOCASE`
+
+	sqlListDocumentTags = `
+SELECT t.name
+FROM tags t
+JOIN document_tags dt ON dt.tag_id = t.id
+WHERE dt.document_id = ?
+ORDER BY t.name COLLATE NOCASE`
+
+	sqlListDocumentsByTag = `
+SELECT id, title, created_at, updated_at, is_locked, kind
+FROM documents
+WHERE is_deleted = 0 AND is_archived = 0 AND id IN (` + sqlDocumentIDsByTag + `)
+ORDER BY updated_at DESC`
+
+	sqlRenameTag = `
+UPDATE tags SET name = ? WHERE id = ?`
+
+	sqlMergeDocumentTags = `
+INSERT OR IGNORE INTO document_tags (document_id, tag_id)
+SELECT document_id, ? FROM document_tags WHERE tag_id = ?`
+
+	sqlDeleteTagLinks = `
+DELETE FROM document_tags WHERE tag_id = ?`
+
+	sqlDeleteTag = `
+DELETE FROM tags WHERE id = ?`
+
+	// tagMaxLength 标签名的最大长度（字符）
+	tagMaxLength = 64
+)
+
+var (
+	// ErrTagNotFound 标签不存在
+	ErrTagNotFound = errors.New("tag not found")
+	// ErrTagExists 重命名的目标标签已存在，应使用合并
+	ErrTagExists = errors.New("tag already exists, merge the tags instead")
+)
+
+// AddTag 为文档添加标签，标签不存在时创建
+func (ds *DocumentService) AddTag(documentID int64, name string) (*models.Tag, error) {
+	name, err := validateTagName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return nil, err
+	}
+	doc, err := ds.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil || doc.IsDeleted {
+		return nil, fmt.Errorf("document not found: %d", documentID)
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	db := ds.databaseService.getDB()
+	if _, err := db.Exec(sqlInsertTag, name, time.Now().Format("2006-01-02 15:04:05")); err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+	tag := &models.Tag{}
+	if err := db.QueryRow(sqlGetTagByName, name).Scan(&tag.ID, &tag.Name, &tag.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	if _, err := db.Exec(sqlAddDocumentTag, documentID, tag.ID); err != nil {
+		return nil, fmt.Errorf("failed to tag document: %w", err)
+	}
+
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeTags, DocumentID: documentID})
+	helper.EmitEvent(constant.EVENT_TAGS_CHANGED, documentID)
+	return tag, nil
+}
+
+// RemoveTag 移除文档的标签，标签不再被任何文档使用时一并删除
+func (ds *DocumentService) RemoveTag(documentID int64, name string) error {
+	name = normalizeTagName(name)
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	result, err := ds.databaseService.getDB().Exec(sqlRemoveDocumentTag, documentID, name)
+	if err != nil {
+		return fmt.Errorf("failed to remove tag: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return nil
+	}
+	if _, err := ds.databaseService.getDB().Exec(sqlDeleteUnusedTags); err != nil {
+		ds.logger.Warning("Failed to delete unused tags", "error", err)
+	}
+
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeTags, DocumentID: documentID})
+	helper.EmitEvent(constant.EVENT_TAGS_CHANGED, documentID)
+	return nil
+}
+
+// ListTags 列出所有标签及使用的文档数，按名称排序
+func (ds *DocumentService) ListTags() ([]*models.Tag, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	rows, err := ds.databaseService.getDB().Query(sqlListTags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []*models.Tag{}
+	for rows.Next() {
+		tag := &models.Tag{}
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.CreatedAt, &tag.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag row: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// GetDocumentTags 获取文档的标签名
+func (ds *DocumentService) GetDocumentTags(documentID int64) ([]string, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	rows, err := ds.databaseService.getDB().Query(sqlListDocumentTags, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list document tags: %w", err)
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan tag row: %w", err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// GetDocumentsByTag 列出带有标签的文档元数据，不含回收站和已归档的文档
+func (ds *DocumentService) GetDocumentsByTag(name string) ([]*models.Document, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	if ds.databaseService == nil || ds.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	rows, err := ds.databaseService.getDB().Query(sqlListDocumentsByTag, sql.Named("tag", normalizeTagName(name)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents by tag: %w", err)
+	}
+	defer rows.Close()
+
+	documents := []*models.Document{}
+	for rows.Next() {
+		doc := &models.Document{}
+		var isLocked int
+		if err := rows.Scan(&doc.ID, &doc.Title, &doc.CreatedAt, &doc.UpdatedAt, &isLocked, &doc.Kind); err != nil {
+			return nil, fmt.Errorf("failed to scan document row: %w", err)
+		}
+		doc.IsLocked = isLocked == 1
+		documents = append(documents, doc)
+	}
+	return documents, rows.Err()
+}
+
+// RenameTag 重命名标签，只改变大小写也可以；目标名称已被其他标签使用时返回 ErrTagExists
+func (ds *DocumentService) RenameTag(oldName, newName string) error {
+	newName, err := validateTagName(newName)
+	if err != nil {
+		return err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	db := ds.databaseService.getDB()
+	source, err := tagIDByName(db, normalizeTagName(oldName))
+	if err != nil {
+		return err
+	}
+	target, err := tagIDByName(db, newName)
+	switch {
+	case err == nil && target != source:
+		return ErrTagExists
+	case err != nil && !errors.Is(err, ErrTagNotFound):
+		return err
+	}
+	if _, err := db.Exec(sqlRenameTag, newName, source); err != nil {
+		return fmt.Errorf("failed to rename tag: %w", err)
+	}
+
+	ds.invalidateCaches()
+	helper.EmitEvent(constant.EVENT_TAGS_CHANGED, int64(0))
+	return nil
+}
+
+// MergeTags 把 source 标签的文档并入 target 标签并删除 source
+func (ds *DocumentService) MergeTags(source, target string) error {
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	db := ds.databaseService.getDB()
+	sourceID, err := tagIDByName(db, normalizeTagName(source))
+	if err != nil {
+		return err
+	}
+	targetID, err := tagIDByName(db, normalizeTagName(target))
+	if err != nil {
+		return err
+	}
+	if sourceID == targetID {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(sqlMergeDocumentTags, targetID, sourceID); err != nil {
+		return fmt.Errorf("failed to merge tags: %w", err)
+	}
+	if _, err := tx.Exec(sqlDeleteTagLinks, sourceID); err != nil {
+		return fmt.Errorf("failed to merge tags: %w", err)
+	}
+	if _, err := tx.Exec(sqlDeleteTag, sourceID); err != nil {
+		return fmt.Errorf("failed to delete merged tag: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to merge tags: %w", err)
+	}
+
+	ds.invalidateCaches()
+	helper.EmitEvent(constant.EVENT_TAGS_CHANGED, int64(0))
+	return nil
+}
+
+// tagIDByName 按名称查询标签ID
+func tagIDByName(db *sql.DB, name string) (int64, error) {
+	var id int64
+	var tagName, createdAt string
+	err := db.QueryRow(sqlGetTagByName, name).Scan(&id, &tagName, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("%w: %s", ErrTagNotFound, name)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tag: %w", err)
+	}
+	return id, nil
+}
+
+// normalizeTagName 去掉首尾空白和 # 前缀，合并连续空白
+func normalizeTagName(name string) string {
+	return strings.Join(strings.Fields(strings.TrimPrefix(strings.TrimSpace(name), "#")), " ")
+}
+
+// validateTagName 规范化并校验标签名
+func validateTagName(name string) (string, error) {
+	name = normalizeTagName(name)
+	switch {
+	case name == "":
+		return "", errors.New("tag name is empty")
+	case len([]rune(name)) > tagMaxLength:
+		return "", fmt.Errorf("tag name is longer than %d characters", tagMaxLength)
+	}
+	return name, nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateTagName(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{"  #work  ", "work", false},
+		{"code   snippets", "code snippets", false},
+		{"#", "", true},
+		{"   ", "", true},
+		{strings.Repeat("标", tagMaxLength), strings.Repeat("标", tagMaxLength), false},
+		{strings.Repeat("a", tagMaxLength+1), "", true},
+	}
+	for _, tt := range tests {
+		got, err := validateTagName(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("validateTagName(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// addTestTags 为文档添加标签
+func addTestTags(t *testing.T, ds *DocumentService, documentID int64, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if _, err := ds.AddTag(documentID, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// tagCounts 标签名到文档数
+func tagCounts(t *testing.T, ds *DocumentService) map[string]int {
+	t.Helper()
+	tags, err := ds.ListTags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int, len(tags))
+	for _, tag := range tags {
+		counts[tag.Name] = tag.Count
+	}
+	return counts
+}
+
+// TestRenameTag 标签名不区分大小写，可以只改大小写，但不能改成已有的其他标签
+func TestRenameTag(t *testing.T) {
+	ds := newTestDocumentService(t)
+	a := createTestDocument(t, ds, "a", "\n∞∞∞text-a\na")
+	b := createTestDocument(t, ds, "b", "\n∞∞∞text-a\nb")
+	addTestTags(t, ds, a, "Work", "personal")
+	addTestTags(t, ds, b, "#work")
+
+	if got, want := tagCounts(t, ds), map[string]int{"Work": 2, "personal": 1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("tags = %v, want %v", got, want)
+	}
+
+	if err := ds.RenameTag("work", "WORK"); err != nil {
+		t.Fatalf("case-only rename: %v", err)
+	}
+	if got, err := ds.GetDocumentTags(b); err != nil || !reflect.DeepEqual(got, []string{"WORK"}) {
+		t.Errorf("tags of b = %v, %v, want [WORK]", got, err)
+	}
+
+	if err := ds.RenameTag("WORK", "Personal"); !errors.Is(err, ErrTagExists) {
+		t.Errorf("rename onto an existing tag error = %v, want ErrTagExists", err)
+	}
+	if err := ds.RenameTag("missing", "other"); !errors.Is(err, ErrTagNotFound) {
+		t.Errorf("rename of a missing tag error = %v, want ErrTagNotFound", err)
+	}
+	if got, want := tagCounts(t, ds), map[string]int{"WORK": 2, "personal": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tags after failed renames = %v, want %v", got, want)
+	}
+}
+
+// TestMergeTags 合并后文档转到目标标签，同时带有两个标签的文档不重复计数
+func TestMergeTags(t *testing.T) {
+	ds := newTestDocumentService(t)
+	a := createTestDocument(t, ds, "a", "\n∞∞∞text-a\na")
+	b := createTestDocument(t, ds, "b", "\n∞∞∞text-a\nb")
+	c := createTestDocument(t, ds, "c", "\n∞∞∞text-a\nc")
+	addTestTags(t, ds, a, "draft", "notes")
+	addTestTags(t, ds, b, "draft")
+	addTestTags(t, ds, c, "notes")
+
+	if err := ds.MergeTags("DRAFT", "Notes"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tagCounts(t, ds), map[string]int{"notes": 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tags after merge = %v, want %v", got, want)
+	}
+	for _, id := range []int64{a, b, c} {
+		if got, err := ds.GetDocumentTags(id); err != nil || !reflect.DeepEqual(got, []string{"notes"}) {
+			t.Errorf("tags of %d = %v, %v, want [notes]", id, got, err)
+		}
+	}
+	docs, err := ds.GetDocumentsByTag("notes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 3 {
+		t.Errorf("documents tagged notes = %d, want 3", len(docs))
+	}
+
+	// 同一标签只是大小写不同时不做任何事
+	if err := ds.MergeTags("notes", "NOTES"); err != nil {
+		t.Errorf("merging a tag into itself: %v", err)
+	}
+	if err := ds.MergeTags("draft", "notes"); !errors.Is(err, ErrTagNotFound) {
+		t.Errorf("merging a deleted tag error = %v, want ErrTagNotFound", err)
+	}
+}
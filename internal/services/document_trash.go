@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"voidraft/internal/models"
+)
+
+const (
+	sqlPurgeDeletedDocument = `
+DELETE FROM documents WHERE id = ? AND is_deleted = 1 AND is_locked = 0`
+
+	sqlDeleteDocumentTags = `
+DELETE FROM document_tags WHERE document_id = ?`
+
+	// trashPurgeInterval 检查回收站过期文档的间隔
+	trashPurgeInterval = time.Hour
+)
+
+// PurgeDocument 永久删除回收站中的文档，锁定的文档不删除
+func (ds *DocumentService) PurgeDocument(id int64) error {
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	result, err := ds.databaseService.getDB().Exec(sqlPurgeDeletedDocument, id)
+	if err != nil {
+		return fmt.Errorf("failed to purge document: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("document %d is not in the trash", id)
+	}
+	if _, err := ds.databaseService.getDB().Exec(sqlDeleteDocumentTags, id); err != nil {
+		ds.logger.Warning("Failed to remove tags of purged document", "document", id, "error", err)
+	}
+	if _, err := ds.databaseService.getDB().Exec(sqlDeleteDocumentVersions, id); err != nil {
+		ds.logger.Warning("Failed to remove versions of purged document", "document", id, "error", err)
+	}
+	ds.notifyDocumentChange(models.DocumentChangeEvent{Type: models.DocumentChangeDeleted, DocumentID: id})
+	return nil
+}
+
+// purgeTrashLoop 定期清理回收站中超过保留天数的文档，修改回收站设置或保留策略后立即检查一次
+func (ds *DocumentService) purgeTrashLoop(ctx context.Context) {
+	changed := make(chan struct{}, 1)
+	if ds.configService != nil {
+		notify := func(oldValue, newValue interface{}) {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+		cancelTrash := ds.configService.Watch("trash", notify)
+		defer cancelTrash()
+		cancelRetention := ds.configService.Watch("retention", notify)
+		defer cancelRetention()
+	}
+
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+	for {
+		if purged, err := ds.purgeExpiredTrash(time.Now()); err != nil {
+			ds.logger.Error("Failed to purge trash", "error", err)
+		} else if purged > 0 {
+			ds.logger.Info("Trash purged", "documents", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-changed:
+		}
+	}
+}
+
+// purgeExpiredTrash 永久删除在回收站中超过保留天数的文档，返回删除的数量
+func (ds *DocumentService) purgeExpiredTrash(now time.Time) (int, error) {
+	if ds.configService == nil {
+		return 0, nil
+	}
+	config, err := ds.configService.GetConfig()
+	if err != nil {
+		return 0, err
+	}
+	if config.Trash.AutoPurgeDays <= 0 {
+		return 0, nil
+	}
+
+	candidates, err := ds.listRetentionCandidates()
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, action := range trashPurgeActions(candidates, config.Retention, config.Trash.AutoPurgeDays, now) {
+		if err := ds.PurgeDocument(action.DocumentID); err != nil {
+			ds.logger.Warning("Failed to purge trashed document", "document", action.DocumentID, "error", err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// trashPurgeActions 以回收站设置的天数作为默认规则计划清理，保留策略的分组规则同样生效
+func trashPurgeActions(candidates []retentionCandidate, retention models.RetentionConfig, days int, now time.Time) []models.RetentionAction {
+	retention.PurgeTrashAfterDays = days
+	actions := []models.RetentionAction{}
+	for _, action := range planRetention(candidates, retention, now) {
+		if action.Action == models.RetentionPurge {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// trashedAt 移入回收站的时间，升级前删除的文档没有记录，移入时更新了修改时间，以此代替
+func trashedAt(deletedAt, updatedAt string) string {
+	if deletedAt != "" {
+		return deletedAt
+	}
+	return updatedAt
+}
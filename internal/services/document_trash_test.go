@@ -0,0 +1,44 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"voidraft/internal/models"
+)
+
+func TestTrashPurgeActions(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	candidates := []retentionCandidate{
+		{ID: 2, Title: "expired", Deleted: true, DeletedAt: "2026-09-16 12:00:00"},
+		{ID: 3, Title: "recent", Deleted: true, DeletedAt: "2026-09-20 12:00:00"},
+		{ID: 4, Title: "no time", Deleted: true},
+		{ID: 5, Title: "bad time", Deleted: true, DeletedAt: "not a time"},
+		{ID: 6, Title: "locked", Deleted: true, Locked: true, DeletedAt: "2026-01-01 00:00:00"},
+		{ID: 7, Title: "Keep: contract", Deleted: true, DeletedAt: "2026-01-01 00:00:00"},
+		{ID: 8, Title: "Scratch note", Deleted: true, DeletedAt: "2026-10-10 12:00:00"},
+		{ID: 9, Title: "old but active", UpdatedAt: "2020-01-01 00:00:00"},
+	}
+	retention := models.RetentionConfig{
+		ArchiveAfterDays: 30,
+		Overrides: []models.RetentionOverride{
+			{Name: "keep", TitlePrefix: "keep:", PurgeTrashAfterDays: -1},
+			{Name: "scratch", TitlePrefix: "scratch", PurgeTrashAfterDays: 1},
+		},
+	}
+
+	var got []int64
+	for _, action := range trashPurgeActions(candidates, retention, 30, now) {
+		if action.Action != models.RetentionPurge {
+			t.Errorf("action for %d = %s, want purge", action.DocumentID, action.Action)
+		}
+		got = append(got, action.DocumentID)
+	}
+	want := []int64{2, 8}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("purged documents = %v, want %v", got, want)
+	}
+
+	if got := trashedAt("", "2026-01-01 00:00:00"); got != "2026-01-01 00:00:00" {
+		t.Errorf("trashedAt fallback = %q", got)
+	}
+}
@@ -0,0 +1,365 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+	"voidraft/internal/models"
+)
+
+const (
+	// 自上次快照以来修改过的文档，没有快照的文档也包括在内
+	sqlListSnapshotCandidates = `
+SELECT d.id, d.title, d.content
+FROM documents d
+WHERE d.is_deleted = 0
+  AND d.updated_at > COALESCE((SELECT MAX(v.created_at) FROM document_versions v WHERE v.document_id = d.id), '')`
+
+	sqlGetSnapshotDocument = `
+SELECT title, content FROM documents WHERE id = ? AND is_deleted = 0`
+
+	sqlGetLatestVersionContent = `
+SELECT id, content FROM document_versions WHERE document_id = ? ORDER BY created_at DESC, id DESC LIMIT 1`
+
+	sqlInsertDocumentVersion = `
+INSERT INTO document_versions (document_id, title, content, content_size, created_at, pinned)
+VALUES (?, ?, ?, ?, ?, ?)`
+
+	sqlPinDocumentVersion = `
+UPDATE document_versions SET pinned = 1 WHERE id = ?`
+
+	sqlListDocumentVersions = `
+SELECT id, document_id, title, content_size, created_at, pinned
+FROM document_versions
+WHERE document_id = ?
+ORDER BY created_at DESC, id DESC`
+
+	sqlGetDocumentVersion = `
+SELECT id, document_id, title, content, content_size, created_at, pinned
+FROM document_versions
+WHERE id = ?`
+
+	sqlListVersionStamps = `
+SELECT id, document_id, created_at, pinned
+FROM document_versions
+ORDER BY document_id, created_at DESC, id DESC`
+
+	sqlDeleteDocumentVersion = `
+DELETE FROM document_versions WHERE id = ?`
+
+	sqlDeleteDocumentVersions = `
+DELETE FROM document_versions WHERE document_id = ?`
+)
+
+const (
+	// snapshotDefaultInterval 未设置快照间隔时使用的间隔
+	snapshotDefaultInterval = 10 * time.Minute
+	// snapshotHourlyWindow 每小时保留一个快照的时长
+	snapshotHourlyWindow = 24 * time.Hour
+	// snapshotDailyWindow 每天保留一个快照的时长，更早的快照删除
+	snapshotDailyWindow = 30 * 24 * time.Hour
+)
+
+// versionStore 可写入历史版本的数据库或事务
+type versionStore interface {
+	QueryRow(query string, args ...any) *sql.Row
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// versionStamp 参与保留策略计算的版本
+type versionStamp struct {
+	id        int64
+	createdAt time.Time
+	pinned    bool
+}
+
+// ListDocumentVersions 列出文档的历史版本，按时间从新到旧，不含内容
+func (ds *DocumentService) ListDocumentVersions(documentID int64) ([]*models.DocumentVersion, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	rows, err := ds.databaseService.getDB().Query(sqlListDocumentVersions, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list document versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := []*models.DocumentVersion{}
+	for rows.Next() {
+		version := &models.DocumentVersion{}
+		if err := rows.Scan(&version.ID, &version.DocumentID, &version.Title, &version.ContentSize, &version.CreatedAt, &version.Pinned); err != nil {
+			return nil, fmt.Errorf("failed to scan document version: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+// GetDocumentVersion 获取历史版本及其内容
+func (ds *DocumentService) GetDocumentVersion(versionID int64) (*models.DocumentVersion, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	return ds.getDocumentVersionLocked(versionID)
+}
+
+// RestoreDocumentVersion 用历史版本的内容替换文档内容，替换前先为当前内容保存固定的快照
+func (ds *DocumentService) RestoreDocumentVersion(versionID int64) error {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	version, err := ds.getDocumentVersionLocked(versionID)
+	if err == nil {
+		err = ds.snapshotDocumentLocked(version.DocumentID, time.Now())
+	}
+	ds.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return ds.UpdateDocumentContent(version.DocumentID, version.Content)
+}
+
+// getDocumentVersionLocked 查询历史版本，调用方需持有 mu
+func (ds *DocumentService) getDocumentVersionLocked(versionID int64) (*models.DocumentVersion, error) {
+	version := &models.DocumentVersion{}
+	err := ds.databaseService.getDB().QueryRow(sqlGetDocumentVersion, versionID).Scan(
+		&version.ID, &version.DocumentID, &version.Title, &version.Content, &version.ContentSize, &version.CreatedAt, &version.Pinned)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("document version not found: %d", versionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document version: %w", err)
+	}
+	return version, nil
+}
+
+// snapshotLoop 按设置的间隔为修改过的文档保存快照并清理过期的快照，修改快照设置后重新计时
+func (ds *DocumentService) snapshotLoop(ctx context.Context) {
+	changed := make(chan struct{}, 1)
+	if ds.configService != nil {
+		cancel := ds.configService.Watch("snapshots", func(oldValue, newValue interface{}) {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		})
+		defer cancel()
+	}
+
+	for {
+		config := ds.snapshotConfig()
+		timer := time.NewTimer(snapshotInterval(config))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-changed:
+			timer.Stop()
+			continue
+		case <-timer.C:
+		}
+
+		if !config.Enabled {
+			continue
+		}
+		saved, pruned, err := ds.runSnapshots(time.Now())
+		switch {
+		case errors.Is(err, ErrAppLocked):
+			// 应用锁定期间不读取文档，解锁后的下一次检查再保存
+		case err != nil:
+			ds.logger.Error("Failed to snapshot documents", "error", err)
+		case saved > 0 || pruned > 0:
+			ds.logger.Info("Documents snapshotted", "saved", saved, "pruned", pruned)
+		}
+	}
+}
+
+// snapshotConfig 当前的快照设置，读取失败时使用默认设置
+func (ds *DocumentService) snapshotConfig() models.SnapshotConfig {
+	if ds.configService != nil {
+		if config, err := ds.configService.GetConfig(); err == nil {
+			return config.Snapshots
+		}
+	}
+	return models.NewDefaultAppConfig().Snapshots
+}
+
+// runSnapshots 为自上次快照以来修改过的文档保存快照，再按保留策略清理，返回保存和删除的快照数
+func (ds *DocumentService) runSnapshots(now time.Time) (int, int, error) {
+	if err := ds.appLockService.ensureUnlocked(); err != nil {
+		return 0, 0, err
+	}
+	if err := ds.databaseService.ensureWritable(); err != nil {
+		return 0, 0, err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	rows, err := ds.databaseService.getDB().Query(sqlListSnapshotCandidates)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list changed documents: %w", err)
+	}
+	var candidates []*models.Document
+	for rows.Next() {
+		doc := &models.Document{}
+		if err := rows.Scan(&doc.ID, &doc.Title, &doc.Content); err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("failed to scan document: %w", err)
+		}
+		candidates = append(candidates, doc)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	saved := 0
+	for _, doc := range candidates {
+		ok, err := ds.saveVersionLocked(ds.databaseService.getDB(), doc.ID, doc.Title, doc.Content, now, false)
+		if err != nil {
+			return saved, 0, fmt.Errorf("failed to snapshot document %d: %w", doc.ID, err)
+		}
+		if ok {
+			saved++
+		}
+	}
+
+	pruned, err := ds.pruneVersionsLocked(now)
+	return saved, pruned, err
+}
+
+// snapshotDocumentLocked 为单个文档的当前内容保存固定的快照，调用方需持有 mu
+func (ds *DocumentService) snapshotDocumentLocked(documentID int64, now time.Time) error {
+	var title, content string
+	err := ds.databaseService.getDB().QueryRow(sqlGetSnapshotDocument, documentID).Scan(&title, &content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("document not found: %d", documentID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get document: %w", err)
+	}
+	if _, err := ds.saveVersionLocked(ds.databaseService.getDB(), documentID, title, content, now, true); err != nil {
+		return fmt.Errorf("failed to snapshot document %d: %w", documentID, err)
+	}
+	return nil
+}
+
+// saveVersionLocked 内容与最近的快照不同时保存快照，返回是否保存；调用方需持有 mu
+// 在事务中修改文档时传入事务，使快照与修改一起提交
+// pinned 用于合并、拆分和恢复前后的安全快照，内容未变时固定最近的快照，保证修改可以撤销
+func (ds *DocumentService) saveVersionLocked(store versionStore, documentID int64, title, content string, now time.Time, pinned bool) (bool, error) {
+	var latestID int64
+	var latest string
+	err := store.QueryRow(sqlGetLatestVersionContent, documentID).Scan(&latestID, &latest)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return false, err
+	case latest == content:
+		if pinned {
+			_, err = store.Exec(sqlPinDocumentVersion, latestID)
+		}
+		return false, err
+	}
+
+	_, err = store.Exec(sqlInsertDocumentVersion,
+		documentID, title, content, utf8.RuneCountInString(content), now.Format("2006-01-02 15:04:05"), pinned)
+	return err == nil, err
+}
+
+// pruneVersionsLocked 按保留策略删除过期的快照，返回删除的数量；调用方需持有 mu
+func (ds *DocumentService) pruneVersionsLocked(now time.Time) (int, error) {
+	rows, err := ds.databaseService.getDB().Query(sqlListVersionStamps)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list document versions: %w", err)
+	}
+	byDocument := make(map[int64][]versionStamp)
+	for rows.Next() {
+		var documentID int64
+		var stamp versionStamp
+		var createdAt string
+		if err := rows.Scan(&stamp.id, &documentID, &createdAt, &stamp.pinned); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan document version: %w", err)
+		}
+		stamp.createdAt, _ = parseDocumentTime(createdAt)
+		byDocument[documentID] = append(byDocument[documentID], stamp)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+	for _, stamps := range byDocument {
+		for _, id := range versionsToPrune(stamps, now) {
+			if _, err := ds.databaseService.getDB().Exec(sqlDeleteDocumentVersion, id); err != nil {
+				return pruned, fmt.Errorf("failed to delete document version %d: %w", id, err)
+			}
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+// versionsToPrune 按保留策略选出一个文档要删除的快照，stamps 需按时间从新到旧排列
+// 一天内每小时保留最新的一个，一个月内每天保留最新的一个，更早的删除；最新的快照总是保留
+// 固定的快照不占用区间，一个月内全部保留
+func versionsToPrune(stamps []versionStamp, now time.Time) []int64 {
+	var prune []int64
+	kept := make(map[string]bool)
+	for i, stamp := range stamps {
+		if i == 0 {
+			kept[versionBucket(stamp.createdAt, now)] = true
+			continue
+		}
+		bucket := versionBucket(stamp.createdAt, now)
+		if stamp.pinned && bucket != "" {
+			continue
+		}
+		if bucket == "" || kept[bucket] {
+			prune = append(prune, stamp.id)
+			continue
+		}
+		kept[bucket] = true
+	}
+	return prune
+}
+
+// versionBucket 快照所属的保留区间，超出保留时长或时间无法解析时返回空
+func versionBucket(createdAt, now time.Time) string {
+	age := now.Sub(createdAt)
+	switch {
+	case createdAt.IsZero():
+		return ""
+	case age < snapshotHourlyWindow:
+		return createdAt.Format("2006-01-02 15")
+	case age < snapshotDailyWindow:
+		return createdAt.Format("2006-01-02")
+	}
+	return ""
+}
+
+// snapshotInterval 快照间隔，未设置时使用默认间隔
+func snapshotInterval(config models.SnapshotConfig) time.Duration {
+	if config.IntervalMinutes <= 0 {
+		return snapshotDefaultInterval
+	}
+	return time.Duration(config.IntervalMinutes) * time.Minute
+}
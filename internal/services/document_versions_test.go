@@ -0,0 +1,59 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestVersionsToPrune(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	at := func(d time.Duration) time.Time { return now.Add(-d) }
+	stamps := []versionStamp{
+		{1, at(5 * time.Minute), false},
+		{2, at(15 * time.Minute), false},    // same hour as 1
+		{3, at(65 * time.Minute), false},    // previous hour
+		{4, at(75 * time.Minute), false},    // same hour as 3
+		{5, at(30 * time.Hour), false},      // daily
+		{6, at(31 * time.Hour), false},      // same day as 5
+		{7, at(3 * 24 * time.Hour), false},  // another day
+		{8, at(40 * 24 * time.Hour), false}, // older than a month
+		{9, time.Time{}, false},             // unparsable
+	}
+	got := versionsToPrune(stamps, now)
+	want := []int64{2, 4, 6, 8, 9}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestVersionsToPruneKeepsLatest(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	stamps := []versionStamp{
+		{1, now.AddDate(0, -3, 0), false},
+		{2, now.AddDate(0, -4, 0), false},
+	}
+	if got := versionsToPrune(stamps, now); !reflect.DeepEqual(got, []int64{2}) {
+		t.Errorf("got %v, want [2]", got)
+	}
+}
+
+func TestVersionsToPrunePinned(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	at := func(d time.Duration) time.Time { return now.Add(-d) }
+	// 合并前后的快照在同一小时内，定时快照在其后
+	stamps := []versionStamp{
+		{1, at(5 * time.Minute), false},
+		{2, at(20 * time.Minute), true},     // after merge
+		{3, at(20 * time.Minute), true},     // before merge
+		{4, at(25 * time.Minute), false},    // same hour as 1
+		{5, at(10 * 24 * time.Hour), true},  // daily window
+		{6, at(10 * 24 * time.Hour), false}, // same day as 5
+		{7, at(40 * 24 * time.Hour), true},  // older than a month
+	}
+	got := versionsToPrune(stamps, now)
+	want := []int64{4, 7}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/markdown"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// ErrClipboardUnavailable 系统剪贴板不可用
+var ErrClipboardUnavailable = errors.New("clipboard is not available")
+
+// CopyDocumentAs 按格式把文档复制到剪贴板，敏感信息已遮盖
+// HTML 格式同时写入 HTML 和纯文本，系统不支持写入 HTML 时只写入纯文本
+func (es *ExportService) CopyDocumentAs(documentID int64, format models.ClipboardFormat) (*models.ClipboardCopyResult, error) {
+	doc, err := es.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil || doc.IsDeleted {
+		return nil, fmt.Errorf("document not found: %d", documentID)
+	}
+	list := blocks.Parse(es.secretScanService.maskForExport(doc.Content))
+
+	var text, html string
+	switch format {
+	case models.ClipboardMarkdown:
+		text = markdown.Source(list)
+	case models.ClipboardText:
+		text = blocks.Text(list)
+	case models.ClipboardSlack:
+		text = markdown.ChatSource(list, false)
+	case models.ClipboardDiscord:
+		text = markdown.ChatSource(list, true)
+	case models.ClipboardHTML:
+		rendered, err := markdown.RenderBlocks(list, markdown.StyleLight)
+		if err != nil {
+			return nil, err
+		}
+		text = blocks.Text(list)
+		html = "<style>" + rendered.CSS + "</style>" + rendered.HTML
+	default:
+		return nil, fmt.Errorf("unsupported clipboard format: %s", format)
+	}
+
+	result := &models.ClipboardCopyResult{Format: format, Length: utf8.RuneCountInString(text)}
+	if html != "" {
+		err := writeClipboardHTML(html, text)
+		if err == nil {
+			result.RichText = true
+			return result, nil
+		}
+		es.logger.Warning("Failed to write HTML to clipboard, falling back to plain text", "error", err)
+	}
+	app := application.Get()
+	if app == nil || !app.Clipboard.SetText(text) {
+		return nil, ErrClipboardUnavailable
+	}
+	return result, nil
+}
+
+// cfHTML 生成 Windows 剪贴板 HTML Format 数据，头部记录 HTML 和片段的字节偏移
+func cfHTML(fragment string) []byte {
+	const header = "Version:0.9\r\nStartHTML:%010d\r\nEndHTML:%010d\r\nStartFragment:%010d\r\nEndFragment:%010d\r\n"
+	const prefix = "<html><body>\r\n<!--StartFragment-->"
+	const suffix = "<!--EndFragment-->\r\n</body></html>"
+
+	headerLen := len(fmt.Sprintf(header, 0, 0, 0, 0))
+	startHTML := headerLen
+	startFragment := startHTML + len(prefix)
+	endFragment := startFragment + len(fragment)
+	endHTML := endFragment + len(suffix)
+	return []byte(fmt.Sprintf(header, startHTML, endHTML, startFragment, endFragment) + prefix + fragment + suffix)
+}
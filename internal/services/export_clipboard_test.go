@@ -0,0 +1,27 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCFHTMLOffsets(t *testing.T) {
+	fragment := "<b>粗体</b>"
+	data := string(cfHTML(fragment))
+
+	offset := func(name string) int {
+		i := strings.Index(data, name+":")
+		n, err := strconv.Atoi(data[i+len(name)+1 : i+len(name)+11])
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		return n
+	}
+	if got := data[offset("StartFragment"):offset("EndFragment")]; got != fragment {
+		t.Errorf("fragment = %q, want %q", got, fragment)
+	}
+	if !strings.HasPrefix(data[offset("StartHTML"):], "<html>") || offset("EndHTML") != len(data) {
+		t.Errorf("html offsets do not match: %q", data)
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/common/markdown"
+	"voidraft/internal/common/pdfrender"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// 单个文档的导出格式
+const (
+	ExportFormatMarkdown = "md"
+	ExportFormatHTML     = "html"
+	ExportFormatPDF      = "pdf"
+)
+
+// exportFormatFilters 保存对话框中各格式的文件类型
+var exportFormatFilters = map[string]application.FileFilter{
+	ExportFormatMarkdown: {DisplayName: "Markdown (*.md)", Pattern: "*.md"},
+	ExportFormatHTML:     {DisplayName: "HTML (*.html)", Pattern: "*.html"},
+	ExportFormatPDF:      {DisplayName: "PDF (*.pdf)", Pattern: "*.pdf"},
+}
+
+// ExportDocument 将单个文档导出为 md、html 或 pdf 文件，敏感信息已遮盖
+// path 为空时弹出保存对话框，用户取消时返回空路径；返回写入的文件路径
+func (es *ExportService) ExportDocument(documentID int64, format string, path string) (string, error) {
+	format = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(format), "."))
+	filter, ok := exportFormatFilters[format]
+	if !ok {
+		return "", fmt.Errorf("unsupported export format: %s", format)
+	}
+
+	if path == "" {
+		doc, err := es.documentService.GetDocumentByID(documentID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get document: %w", err)
+		}
+		if doc == nil || doc.IsDeleted {
+			return "", fmt.Errorf("document not found: %d", documentID)
+		}
+		if path, err = promptExportPath(exportFileName(doc.Title, format), filter); err != nil || path == "" {
+			return "", err
+		}
+		if !strings.HasSuffix(strings.ToLower(path), "."+format) {
+			path += "." + format
+		}
+	}
+
+	var err error
+	switch format {
+	case ExportFormatMarkdown:
+		err = es.ExportDocumentMarkdown(documentID, path)
+	case ExportFormatHTML:
+		err = es.ExportDocumentHTML(documentID, markdown.StyleLight, path)
+	case ExportFormatPDF:
+		err = es.ExportDocumentPDF(documentID, path)
+	}
+	if err != nil {
+		return "", err
+	}
+	es.logger.Info("Document exported", "id", documentID, "format", format)
+	return path, nil
+}
+
+// RenderDocumentPDF 将文档排版为 PDF，代码块按语言高亮，敏感信息已遮盖
+func (es *ExportService) RenderDocumentPDF(documentID int64) ([]byte, error) {
+	doc, err := es.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil || doc.IsDeleted {
+		return nil, fmt.Errorf("document not found: %d", documentID)
+	}
+
+	data, err := pdfrender.Render(doc.Title, blocks.Parse(es.secretScanService.maskForExport(doc.Content)), pdfrender.Options{Style: markdown.StyleLight})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render pdf: %w", err)
+	}
+	return data, nil
+}
+
+// ExportDocumentPDF 将文档导出为 PDF 文件
+func (es *ExportService) ExportDocumentPDF(documentID int64, path string) error {
+	if path == "" {
+		return errors.New("export path is empty")
+	}
+	data, err := es.RenderDocumentPDF(documentID)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write pdf: %w", err)
+	}
+	return nil
+}
+
+// promptExportPath 弹出保存对话框，用户取消时返回空路径
+func promptExportPath(filename string, filter application.FileFilter) (string, error) {
+	dialog := application.SaveFileDialog()
+	dialog.SetOptions(&application.SaveFileDialogOptions{
+		CanCreateDirectories: true,
+		Title:                "Export Document",
+		Filename:             filename,
+		ButtonText:           "Export",
+		Filters:              []application.FileFilter{filter},
+		Window:               helper.NewWindowHelper().MustGetMainWindow(),
+	})
+	return dialog.PromptForSingleSelection()
+}
+
+// exportFileName 根据标题生成导出文件名
+func exportFileName(title string, format string) string {
+	base := strings.TrimSpace(externalFileNameInvalid.ReplaceAllString(title, "_"))
+	if base == "" || base == "." || base == ".." {
+		base = "document"
+	}
+	return base + "." + format
+}
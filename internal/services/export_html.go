@@ -0,0 +1,83 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/markdown"
+)
+
+// htmlExportTemplate 独立 HTML 页面模板，可直接在浏览器中打开或打印为 PDF
+var htmlExportTemplate = template.Must(template.New("export").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body{margin:0;padding:24px;color:#222;font:15px/1.6 -apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,sans-serif}
+main{max-width:960px;margin:0 auto}
+h1{font-size:20px;margin:0 0 16px}
+pre{font:13px/1.5 ui-monospace,SFMono-Regular,Menlo,Consolas,monospace}
+@media print{body{padding:0}pre{white-space:pre-wrap;word-wrap:break-word}.block{break-inside:avoid}}
+{{.CSS}}
+</style>
+</head>
+<body>
+<main>
+<h1>{{.Title}}</h1>
+{{.Body}}
+</main>
+</body>
+</html>
+`))
+
+// htmlExportData HTML 导出页面数据
+type htmlExportData struct {
+	Title string
+	Body  template.HTML
+	CSS   template.CSS
+}
+
+// RenderDocumentHTML 将文档渲染为独立 HTML 页面，敏感信息已遮盖，用于打印和 HTML 导出
+func (es *ExportService) RenderDocumentHTML(documentID int64, style string) (string, error) {
+	doc, err := es.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil || doc.IsDeleted {
+		return "", fmt.Errorf("document not found: %d", documentID)
+	}
+
+	rendered, err := markdown.RenderBlocks(blocks.Parse(es.secretScanService.maskForExport(doc.Content)), style)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = htmlExportTemplate.Execute(&buf, htmlExportData{
+		Title: doc.Title,
+		Body:  template.HTML(rendered.HTML),
+		CSS:   template.CSS(rendered.CSS),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render page: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// ExportDocumentHTML 将文档导出为 HTML 文件
+func (es *ExportService) ExportDocumentHTML(documentID int64, style string, path string) error {
+	if path == "" {
+		return errors.New("export path is empty")
+	}
+	page, err := es.RenderDocumentHTML(documentID, style)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, []byte(page), 0644); err != nil {
+		return fmt.Errorf("failed to write html: %w", err)
+	}
+	return nil
+}
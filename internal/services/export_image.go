@@ -0,0 +1,137 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/codeimage"
+	"voidraft/internal/common/themecolor"
+	"voidraft/internal/models"
+)
+
+// CaptureWindowImage 将文档或其中一个块渲染为带窗口外框的 PNG，用于分享代码截图
+func (es *ExportService) CaptureWindowImage(documentID int64, options models.CodeImageOptions) (*models.CodeImage, error) {
+	doc, err := es.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil || doc.IsDeleted {
+		return nil, fmt.Errorf("document not found: %d", documentID)
+	}
+
+	parsed := blocks.Parse(es.secretScanService.maskForExport(doc.Content))
+	var text string
+	if options.Block > 0 {
+		if options.Block > len(parsed) {
+			return nil, fmt.Errorf("block not found: %d", options.Block)
+		}
+		text = parsed[options.Block-1].Content
+	} else {
+		contents := make([]string, 0, len(parsed))
+		for _, block := range parsed {
+			contents = append(contents, strings.TrimRight(block.Content, "\n"))
+		}
+		text = strings.Join(contents, "\n\n")
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("nothing to capture")
+	}
+
+	renderOptions := codeimage.Options{
+		LineNumbers: options.LineNumbers,
+		FontSize:    options.FontSize,
+		Padding:     options.Padding,
+		Scale:       options.Scale,
+		Style:       es.codeImageStyle(options),
+	}
+	if options.ShowTitle {
+		renderOptions.Title = doc.Title
+	}
+	img, err := codeimage.Render(text, renderOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render image: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return &models.CodeImage{Data: buf.Bytes(), Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}, nil
+}
+
+// SaveWindowImage 渲染文档截图并保存为 PNG 文件
+func (es *ExportService) SaveWindowImage(documentID int64, options models.CodeImageOptions, path string) error {
+	if path == "" {
+		return errors.New("export path is empty")
+	}
+	image, err := es.CaptureWindowImage(documentID, options)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, image.Data, 0644); err != nil {
+		return fmt.Errorf("failed to write image: %w", err)
+	}
+	return nil
+}
+
+// codeImageStyle 按主题生成截图配色，主题没有保存在数据库中时使用默认配色
+func (es *ExportService) codeImageStyle(options models.CodeImageOptions) codeimage.Style {
+	name := options.Theme
+	if name == "" {
+		if config, err := es.configService.GetConfig(); err == nil {
+			name = config.Appearance.CurrentTheme
+		}
+	}
+
+	var colors models.ThemeColorConfig
+	if name != "" {
+		if theme, err := es.themeService.GetThemeByName(name); err == nil && theme != nil {
+			colors = theme.Colors
+		}
+	}
+	parse := func(key string) (themecolor.Color, bool) {
+		value, ok := colors[key].(string)
+		if !ok {
+			return themecolor.Color{}, false
+		}
+		c, err := themecolor.Parse(value)
+		return c, err == nil
+	}
+
+	var style codeimage.Style
+	window, hasWindow := parse("background")
+	if hasWindow {
+		style.Window = nrgba(window)
+	}
+	if c, ok := parse("foreground"); ok {
+		style.Foreground = nrgba(c)
+	}
+	if c, ok := parse("lineNumber"); ok {
+		style.LineNumber = nrgba(c)
+	}
+
+	if c, err := themecolor.Parse(options.Background); options.Background != "" && err == nil {
+		style.Background = nrgba(c)
+	} else {
+		if !hasWindow {
+			window = themecolor.MustParse("#252b37")
+		}
+		// 外框背景取窗口色向黑或白偏移，让窗口边缘清晰可见
+		edge := themecolor.MustParse("#ffffff")
+		if !window.IsDark() {
+			edge = themecolor.MustParse("#000000")
+		}
+		style.Background = nrgba(themecolor.Mix(window, edge, 0.25))
+	}
+	return style
+}
+
+// nrgba 转换为标准库颜色
+func nrgba(c themecolor.Color) color.NRGBA {
+	return color.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A}
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/markdown"
+)
+
+// RenderDocumentSource 将文档转换为 Markdown 源文本，代码块包裹为围栏代码块，敏感信息已遮盖
+func (es *ExportService) RenderDocumentSource(documentID int64) (string, error) {
+	doc, err := es.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil || doc.IsDeleted {
+		return "", fmt.Errorf("document not found: %d", documentID)
+	}
+	return markdown.Source(blocks.Parse(es.secretScanService.maskForExport(doc.Content))), nil
+}
+
+// ExportDocumentMarkdown 将文档导出为 Markdown 文件
+func (es *ExportService) ExportDocumentMarkdown(documentID int64, path string) error {
+	if path == "" {
+		return errors.New("export path is empty")
+	}
+	source, err := es.RenderDocumentSource(documentID)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, []byte(source), 0644); err != nil {
+		return fmt.Errorf("failed to write markdown: %w", err)
+	}
+	return nil
+}
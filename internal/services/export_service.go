@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"voidraft/internal/common/archive"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/models"
+	"voidraft/internal/version"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// ExportResult 导出结果
+type ExportResult struct {
+	Path          string `json:"path"`          // 导出文件路径
+	DocumentCount int    `json:"documentCount"` // 导出的文档数量
+	Encrypted     bool   `json:"encrypted"`     // 是否加密
+	Size          int64  `json:"size"`          // 文件大小（字节）
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	Imported    int      `json:"imported"`    // 成功导入的文档数量
+	DocumentIDs []int64  `json:"documentIds"` // 新建文档的ID
+	Errors      []string `json:"errors"`      // 单个文档的导入错误
+}
+
+// 大文本导入的拆分阈值
+const (
+	textImportBlockBytes    = 1 << 20  // 单个块上限
+	textImportDocumentBytes = 32 << 20 // 单个文档上限，超出后拆分为多个文档
+)
+
+// ExportService 文档导入导出服务
+type ExportService struct {
+	logger            *log.LogService
+	documentService   *DocumentService
+	secretScanService *SecretScanService
+	jobService        *JobService
+	themeService      *ThemeService
+	configService     *ConfigService
+}
+
+// NewExportService 创建导入导出服务
+func NewExportService(documentService *DocumentService, secretScanService *SecretScanService, jobService *JobService, themeService *ThemeService, configService *ConfigService, logger *log.LogService) *ExportService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	return &ExportService{
+		logger:            logger,
+		documentService:   documentService,
+		secretScanService: secretScanService,
+		jobService:        jobService,
+		themeService:      themeService,
+		configService:     configService,
+	}
+}
+
+// ServiceStartup 服务启动
+func (es *ExportService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	return nil
+}
+
+// ExportAll 导出全部文档为归档，passphrase 非空时加密
+func (es *ExportService) ExportAll(path string, passphrase string) (*ExportResult, error) {
+	ids, err := es.allDocumentIDs()
+	if err != nil {
+		return nil, err
+	}
+	return es.ExportDocuments(ids, path, passphrase)
+}
+
+// ExportDocuments 导出指定文档为归档，passphrase 非空时加密
+func (es *ExportService) ExportDocuments(ids []int64, path string, passphrase string) (*ExportResult, error) {
+	return es.exportDocuments(context.Background(), ids, path, passphrase, nil)
+}
+
+// StartExportAll 以后台任务导出全部文档，进度通过任务事件推送
+func (es *ExportService) StartExportAll(path string, passphrase string) (*models.Job, error) {
+	ids, err := es.allDocumentIDs()
+	if err != nil {
+		return nil, err
+	}
+	return es.StartExportDocuments(ids, path, passphrase)
+}
+
+// StartExportDocuments 以后台任务导出指定文档
+func (es *ExportService) StartExportDocuments(ids []int64, path string, passphrase string) (*models.Job, error) {
+	return es.jobService.submit(models.JobKindExport, "Export "+filepath.Base(path), func(ctx context.Context, progress jobProgress) (any, error) {
+		return es.exportDocuments(ctx, ids, path, passphrase, progress)
+	})
+}
+
+// allDocumentIDs 列出全部未删除文档的ID
+func (es *ExportService) allDocumentIDs() ([]int64, error) {
+	docs, err := es.documentService.ListAllDocumentsMeta()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	ids := make([]int64, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+	return ids, nil
+}
+
+// exportDocuments 导出文档，progress 为空时不上报进度
+func (es *ExportService) exportDocuments(ctx context.Context, ids []int64, path string, passphrase string, progress jobProgress) (*ExportResult, error) {
+	if path == "" {
+		return nil, errors.New("export path is empty")
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("no documents to export")
+	}
+
+	entries := make([]archive.Entry, 0, len(ids))
+	for i, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if progress != nil {
+			progress(i, len(ids), "Reading documents")
+		}
+		doc, err := es.documentService.GetDocumentByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get document %d: %w", id, err)
+		}
+		if doc == nil || doc.IsDeleted {
+			continue
+		}
+		entries = append(entries, archive.Entry{
+			ID:        doc.ID,
+			Title:     doc.Title,
+			CreatedAt: doc.CreatedAt,
+			UpdatedAt: doc.UpdatedAt,
+			IsLocked:  doc.IsLocked,
+			Content:   es.secretScanService.maskForExport(doc.Content),
+		})
+	}
+
+	if progress != nil {
+		progress(len(ids), len(ids), "Writing archive")
+	}
+	data, err := archive.Build(entries, version.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := passphrase != ""
+	if encrypted {
+		if data, err = archive.Encrypt(data, passphrase); err != nil {
+			return nil, fmt.Errorf("failed to encrypt archive: %w", err)
+		}
+	}
+
+	if err := writeFileAtomic(path, data, 0600); err != nil {
+		return nil, err
+	}
+
+	es.logger.Info("Documents exported", "path", path, "count", len(entries), "encrypted", encrypted)
+	return &ExportResult{
+		Path:          path,
+		DocumentCount: len(entries),
+		Encrypted:     encrypted,
+		Size:          int64(len(data)),
+	}, nil
+}
+
+// IsArchiveEncrypted 检查归档是否加密，便于前端决定是否询问口令
+func (es *ExportService) IsArchiveEncrypted(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	header := make([]byte, 32)
+	n, _ := file.Read(header)
+	return archive.IsEncrypted(header[:n]), nil
+}
+
+// ImportArchive 导入归档中的文档，加密归档自动使用口令解密
+func (es *ExportService) ImportArchive(path string, passphrase string) (*ImportResult, error) {
+	return es.importArchive(context.Background(), path, passphrase, nil)
+}
+
+// StartImportArchive 以后台任务导入归档
+func (es *ExportService) StartImportArchive(path string, passphrase string) (*models.Job, error) {
+	return es.jobService.submit(models.JobKindImport, "Import "+filepath.Base(path), func(ctx context.Context, progress jobProgress) (any, error) {
+		return es.importArchive(ctx, path, passphrase, progress)
+	})
+}
+
+// importArchive 导入归档，取消时保留已导入的文档
+func (es *ExportService) importArchive(ctx context.Context, path string, passphrase string, progress jobProgress) (*ImportResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	if archive.IsEncrypted(data) {
+		if data, err = archive.Decrypt(data, passphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest, err := archive.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{}
+	for i, entry := range manifest.Documents {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if progress != nil {
+			progress(i, len(manifest.Documents), entry.Title)
+		}
+		doc, err := es.documentService.CreateDocument(entry.Title)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", entry.Title, err))
+			continue
+		}
+		if err := es.documentService.UpdateDocumentContent(doc.ID, entry.Content); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", entry.Title, err))
+			continue
+		}
+		result.Imported++
+		result.DocumentIDs = append(result.DocumentIDs, doc.ID)
+	}
+
+	es.logger.Info("Archive imported", "path", path, "imported", result.Imported, "failed", len(result.Errors))
+	return result, nil
+}
+
+// StartImportTextFile 以后台任务流式导入大文本文件，超过单文档上限时拆分为多个文档
+func (es *ExportService) StartImportTextFile(path string, language string) (*models.Job, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	if info.IsDir() {
+		return nil, errors.New("path is a directory")
+	}
+	if language != "" && !apiLanguagePattern.MatchString(language) {
+		return nil, fmt.Errorf("invalid language: %s", language)
+	}
+
+	return es.jobService.submit(models.JobKindImport, "Import "+filepath.Base(path), func(ctx context.Context, progress jobProgress) (any, error) {
+		return es.importTextFile(ctx, path, language, info.Size(), progress)
+	})
+}
+
+// importTextFile 逐块读取文本文件写入文档，内存占用不超过单个文档的大小
+func (es *ExportService) importTextFile(ctx context.Context, path string, language string, size int64, progress jobProgress) (*ImportResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	title := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	split := size > textImportDocumentBytes
+	result := &ImportResult{}
+
+	emit := func(content string) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		name := title
+		if split {
+			name = fmt.Sprintf("%s (part %d)", title, len(result.DocumentIDs)+1)
+		}
+		doc, err := es.documentService.CreateDocument(name)
+		if err != nil {
+			return err
+		}
+		result.DocumentIDs = append(result.DocumentIDs, doc.ID)
+		if err := es.documentService.UpdateDocumentContent(doc.ID, content); err != nil {
+			return err
+		}
+		result.Imported++
+		return nil
+	}
+	read := func(total int64) {
+		progress.bytes(total, size)
+	}
+
+	limits := blocks.StreamLimits{BlockBytes: textImportBlockBytes, DocumentBytes: textImportDocumentBytes}
+	if err := blocks.SplitStream(file, language, limits, emit, read); err != nil {
+		// 已写入的文档保留，调用方可从结果中看到
+		return result, err
+	}
+
+	es.logger.Info("Text file imported", "path", path, "size", size, "documents", result.Imported)
+	return result, nil
+}
+
+// writeFileAtomic 先写临时文件再重命名，避免导出中断留下损坏文件
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".voidraft-export-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close export file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return fmt.Errorf("failed to set export file permission: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to move export file: %w", err)
+	}
+	return nil
+}
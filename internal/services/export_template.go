@@ -0,0 +1,198 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/markdown"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// exportTemplateData 导出模板中可用的文档数据
+type exportTemplateData struct {
+	ID       int64
+	Title    string
+	Tags     []string
+	Created  time.Time
+	Updated  time.Time
+	Blocks   []exportTemplateBlock
+	Markdown string // 全文转换的 Markdown，代码块为围栏代码块
+	Text     string // 全文纯文本，块之间以空行分隔
+	Favorite bool
+	Pinned   bool
+	Locked   bool
+	Color    string
+	Icon     string
+	Now      time.Time
+}
+
+// exportTemplateBlock 导出模板中的块
+type exportTemplateBlock struct {
+	Index    int
+	Language string
+	Content  string
+	Prose    bool // 是否为文本或 Markdown 块
+}
+
+// exportTemplateFuncs 导出模板中可用的函数
+var exportTemplateFuncs = template.FuncMap{
+	"join":    func(sep string, list []string) string { return strings.Join(list, sep) },
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"trim":    strings.TrimSpace,
+	"replace": func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"date":    func(layout string, t time.Time) string { return t.Format(layout) },
+	"fence": func(language, content string) string {
+		return markdown.Source([]blocks.Block{{Language: language, Content: content}})
+	},
+	"indent": func(n int, s string) string {
+		pad := strings.Repeat(" ", n)
+		return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+	},
+}
+
+// ListExportTemplates 列出自定义导出模板
+func (es *ExportService) ListExportTemplates() []models.ExportTemplate {
+	config, err := es.configService.GetConfig()
+	if err != nil {
+		return []models.ExportTemplate{}
+	}
+	return append([]models.ExportTemplate{}, config.Export.Templates...)
+}
+
+// ValidateExportTemplate 用示例文档执行模板，返回语法或字段错误，供设置页保存前检查
+func (es *ExportService) ValidateExportTemplate(text string) error {
+	tmpl, err := parseExportTemplate("validate", text)
+	if err != nil {
+		return err
+	}
+	sample := exportTemplateData{
+		ID:      1,
+		Title:   "Example",
+		Tags:    []string{"example"},
+		Created: time.Now(),
+		Updated: time.Now(),
+		Blocks:  []exportTemplateBlock{{Language: "md", Content: "# Example\n", Prose: true}, {Index: 1, Language: "go", Content: "package main\n"}},
+		Now:     time.Now(),
+	}
+	return tmpl.Execute(&strings.Builder{}, sample)
+}
+
+// RenderDocumentTemplate 用自定义模板渲染文档，敏感信息已遮盖
+func (es *ExportService) RenderDocumentTemplate(documentID int64, templateID string) (string, error) {
+	exportTemplate, err := es.findExportTemplate(templateID)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := parseExportTemplate(exportTemplate.Name, exportTemplate.Template)
+	if err != nil {
+		return "", err
+	}
+
+	doc, err := es.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil || doc.IsDeleted {
+		return "", fmt.Errorf("document not found: %d", documentID)
+	}
+	tags, err := es.documentService.GetDocumentTags(documentID)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, newExportTemplateData(doc, tags, es.secretScanService.maskForExport(doc.Content))); err != nil {
+		return "", fmt.Errorf("failed to render export template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// ExportDocumentTemplate 用自定义模板导出文档，path 为空时弹出保存对话框，用户取消时返回空路径
+func (es *ExportService) ExportDocumentTemplate(documentID int64, templateID string, path string) (string, error) {
+	exportTemplate, err := es.findExportTemplate(templateID)
+	if err != nil {
+		return "", err
+	}
+	output, err := es.RenderDocumentTemplate(documentID, templateID)
+	if err != nil {
+		return "", err
+	}
+
+	if path == "" {
+		extension := strings.TrimPrefix(strings.TrimSpace(exportTemplate.Extension), ".")
+		if extension == "" {
+			extension = ExportFormatMarkdown
+		}
+		doc, err := es.documentService.GetDocumentByID(documentID)
+		if err != nil || doc == nil {
+			return "", fmt.Errorf("document not found: %d", documentID)
+		}
+		filter := application.FileFilter{DisplayName: fmt.Sprintf("%s (*.%s)", exportTemplate.Name, extension), Pattern: "*." + extension}
+		if path, err = promptExportPath(exportFileName(doc.Title, extension), filter); err != nil || path == "" {
+			return "", err
+		}
+		if !strings.HasSuffix(strings.ToLower(path), "."+strings.ToLower(extension)) {
+			path += "." + extension
+		}
+	}
+
+	if err := writeFileAtomic(path, []byte(output), 0644); err != nil {
+		return "", err
+	}
+	es.logger.Info("Document exported", "id", documentID, "template", exportTemplate.Name)
+	return path, nil
+}
+
+// findExportTemplate 按 ID 查找自定义导出模板
+func (es *ExportService) findExportTemplate(id string) (models.ExportTemplate, error) {
+	for _, exportTemplate := range es.ListExportTemplates() {
+		if exportTemplate.ID == id {
+			return exportTemplate, nil
+		}
+	}
+	return models.ExportTemplate{}, fmt.Errorf("export template not found: %s", id)
+}
+
+// parseExportTemplate 解析模板，缺少的字段视为错误
+func parseExportTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(exportTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid export template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// newExportTemplateData 组装模板数据，content 为已遮盖的文档内容
+func newExportTemplateData(doc *models.Document, tags []string, content string) exportTemplateData {
+	list := blocks.Parse(content)
+	data := exportTemplateData{
+		ID:       doc.ID,
+		Title:    doc.Title,
+		Tags:     tags,
+		Blocks:   make([]exportTemplateBlock, 0, len(list)),
+		Markdown: markdown.Source(list),
+		Text:     blocks.Text(list),
+		Favorite: doc.IsFavorite,
+		Pinned:   doc.IsPinned,
+		Locked:   doc.IsLocked,
+		Color:    string(doc.ColorLabel),
+		Icon:     doc.Icon,
+		Now:      time.Now(),
+	}
+	data.Created, _ = parseDocumentTime(doc.CreatedAt)
+	data.Updated, _ = parseDocumentTime(doc.UpdatedAt)
+	for i, block := range list {
+		data.Blocks = append(data.Blocks, exportTemplateBlock{
+			Index:    i,
+			Language: block.Language,
+			Content:  block.Content,
+			Prose:    blocks.IsProse(block.Language),
+		})
+	}
+	return data
+}
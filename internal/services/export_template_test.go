@@ -0,0 +1,37 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"voidraft/internal/models"
+)
+
+func TestExportTemplate(t *testing.T) {
+	doc := &models.Document{ID: 7, Title: "Runbook", CreatedAt: "2024-03-01 08:00:00", Icon: "📘"}
+	data := newExportTemplateData(doc, []string{"ops", "k8s"}, "\n∞∞∞md\n# Restart\n\n∞∞∞sh\nkubectl rollout restart\n")
+
+	tmpl, err := parseExportTemplate("wiki", `h1. {{.Icon}} {{.Title}}
+{{date "2006-01-02" .Created}} [{{join ", " .Tags}}]
+{{range .Blocks}}{{if .Prose}}{{trim .Content}}{{else}}{code:{{.Language}}}
+{{trim .Content}}
+{code}{{end}}
+{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		t.Fatal(err)
+	}
+	want := "h1. 📘 Runbook\n2024-03-01 [ops, k8s]\n# Restart\n{code:sh}\nkubectl rollout restart\n{code}\n"
+	if b.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	if _, err := parseExportTemplate("bad", "{{.Title"); err == nil {
+		t.Error("unclosed action: want error")
+	}
+	if err := (&ExportService{}).ValidateExportTemplate("{{.Missing}}"); err == nil {
+		t.Error("unknown field: want error")
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/markdown"
+	"voidraft/internal/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// workspaceFrontMatter 导出 Markdown 文件头部的 YAML 元数据，字段名与 Obsidian 习惯一致
+type workspaceFrontMatter struct {
+	Title   string   `yaml:"title"`
+	Tags    []string `yaml:"tags,omitempty"`
+	Created string   `yaml:"created,omitempty"`
+	Updated string   `yaml:"updated,omitempty"`
+}
+
+// ExportWorkspace 把全部文档导出到目录，每个文档一个带 YAML 头的 .md 文件，敏感信息已遮盖
+func (es *ExportService) ExportWorkspace(dir string) (*ExportResult, error) {
+	return es.exportWorkspace(context.Background(), dir, nil)
+}
+
+// StartExportWorkspace 以后台任务导出全部文档到目录，进度通过任务事件推送
+func (es *ExportService) StartExportWorkspace(dir string) (*models.Job, error) {
+	return es.jobService.submit(models.JobKindExport, "Export to "+filepath.Base(dir), func(ctx context.Context, progress jobProgress) (any, error) {
+		return es.exportWorkspace(ctx, dir, progress)
+	})
+}
+
+// exportWorkspace 逐个写出文档，progress 为空时不上报进度
+func (es *ExportService) exportWorkspace(ctx context.Context, dir string, progress jobProgress) (*ExportResult, error) {
+	if dir == "" {
+		return nil, errors.New("export directory is empty")
+	}
+	ids, err := es.allDocumentIDs()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("no documents to export")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	result := &ExportResult{Path: dir}
+	used := make(map[string]bool, len(ids))
+	for i, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		doc, err := es.documentService.GetDocumentByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get document %d: %w", id, err)
+		}
+		if doc == nil || doc.IsDeleted {
+			continue
+		}
+		if progress != nil {
+			progress(i, len(ids), doc.Title)
+		}
+
+		tags, err := es.documentService.GetDocumentTags(id)
+		if err != nil {
+			return nil, err
+		}
+		data, err := workspaceMarkdown(doc, tags, es.secretScanService.maskForExport(doc.Content))
+		if err != nil {
+			return nil, err
+		}
+
+		path := filepath.Join(dir, uniqueExportFileName(doc.Title, used))
+		if err := writeFileAtomic(path, data, 0644); err != nil {
+			return nil, err
+		}
+		// 文件修改时间与文档一致，便于在其他工具中按时间排序
+		if updated, ok := parseDocumentTime(doc.UpdatedAt); ok {
+			_ = os.Chtimes(path, updated, updated)
+		}
+		result.DocumentCount++
+		result.Size += int64(len(data))
+	}
+	if progress != nil {
+		progress(len(ids), len(ids), "")
+	}
+
+	es.logger.Info("Workspace exported", "dir", dir, "count", result.DocumentCount)
+	return result, nil
+}
+
+// workspaceMarkdown 生成带 YAML 头的 Markdown 文件内容
+func workspaceMarkdown(doc *models.Document, tags []string, content string) ([]byte, error) {
+	meta := workspaceFrontMatter{Title: doc.Title, Tags: tags}
+	if created, ok := parseDocumentTime(doc.CreatedAt); ok {
+		meta.Created = created.Format(time.RFC3339)
+	}
+	if updated, ok := parseDocumentTime(doc.UpdatedAt); ok {
+		meta.Updated = updated.Format(time.RFC3339)
+	}
+	header, err := yaml.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode front matter: %w", err)
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	b.Write(header)
+	b.WriteString("---\n\n")
+	b.WriteString(markdown.Source(blocks.Parse(content)))
+	return []byte(b.String()), nil
+}
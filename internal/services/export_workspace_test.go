@@ -0,0 +1,24 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"voidraft/internal/models"
+)
+
+func TestWorkspaceMarkdown(t *testing.T) {
+	doc := &models.Document{Title: "Notes: draft", CreatedAt: "2024-03-01 08:00:00", UpdatedAt: "bad"}
+	data, err := workspaceMarkdown(doc, []string{"work", "go"}, "\n∞∞∞text-a\nhello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{"---\ntitle: 'Notes: draft'\n", "tags:\n    - work\n    - go\n", "created: \"2024-03-01T08:00:00", "---\n\nhello"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("markdown missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "updated:") {
+		t.Errorf("unparsable time should be omitted:\n%s", got)
+	}
+}
@@ -104,13 +104,17 @@ func (es *ExtensionService) initDatabase() error {
 	es.mu.Lock()
 	defer es.mu.Unlock()
 
-	if es.databaseService == nil || es.databaseService.db == nil {
+	if es.databaseService == nil || es.databaseService.getDB() == nil {
 		return &ExtensionError{"check_db", "", errors.New("database service not available")}
 	}
+	// 只读时不补充或清理扩展，由持有锁的实例负责
+	if es.databaseService.ensureWritable() != nil {
+		return nil
+	}
 
 	// 检查是否已有扩展数据
 	var count int64
-	err := es.databaseService.db.QueryRow("SELECT COUNT(*) FROM extensions").Scan(&count)
+	err := es.databaseService.getDB().QueryRow("SELECT COUNT(*) FROM extensions").Scan(&count)
 	if err != nil {
 		return &ExtensionError{"check_extensions_count", "", err}
 	}
@@ -143,7 +147,7 @@ func (es *ExtensionService) insertDefaultExtensions() error {
 			return &ExtensionError{"marshal_config", string(ext.ID), err}
 		}
 
-		_, err = es.databaseService.db.Exec(sqlInsertExtension,
+		_, err = es.databaseService.getDB().Exec(sqlInsertExtension,
 			string(ext.ID),
 			ext.Enabled,
 			ext.IsDefault,
@@ -173,7 +177,7 @@ func (es *ExtensionService) syncExtensions() error {
 	// 1. 添加缺失的扩展
 	for _, ext := range defaultSettings.Extensions {
 		var exists int
-		err := es.databaseService.db.QueryRow("SELECT COUNT(*) FROM extensions WHERE id = ?", string(ext.ID)).Scan(&exists)
+		err := es.databaseService.getDB().QueryRow("SELECT COUNT(*) FROM extensions WHERE id = ?", string(ext.ID)).Scan(&exists)
 		if err != nil {
 			return &ExtensionError{"check_extension_exists", string(ext.ID), err}
 		}
@@ -184,7 +188,7 @@ func (es *ExtensionService) syncExtensions() error {
 				return &ExtensionError{"marshal_config", string(ext.ID), err}
 			}
 
-			_, err = es.databaseService.db.Exec(sqlInsertExtension,
+			_, err = es.databaseService.getDB().Exec(sqlInsertExtension,
 				string(ext.ID),
 				ext.Enabled,
 				ext.IsDefault,
@@ -200,7 +204,7 @@ func (es *ExtensionService) syncExtensions() error {
 	}
 
 	// 2. 删除数据库中已不存在于代码定义的扩展
-	rows, err := es.databaseService.db.Query("SELECT id FROM extensions")
+	rows, err := es.databaseService.getDB().Query("SELECT id FROM extensions")
 	if err != nil {
 		return &ExtensionError{"query_all_extension_ids", "", err}
 	}
@@ -223,7 +227,7 @@ func (es *ExtensionService) syncExtensions() error {
 
 	// 删除不再定义的扩展
 	for _, id := range toDelete {
-		_, err := es.databaseService.db.Exec("DELETE FROM extensions WHERE id = ?", id)
+		_, err := es.databaseService.getDB().Exec("DELETE FROM extensions WHERE id = ?", id)
 		if err != nil {
 			return &ExtensionError{"delete_obsolete_extension", id, err}
 		}
@@ -253,11 +257,11 @@ func (es *ExtensionService) GetAllExtensions() ([]models.Extension, error) {
 	es.mu.RLock()
 	defer es.mu.RUnlock()
 
-	if es.databaseService == nil || es.databaseService.db == nil {
+	if es.databaseService == nil || es.databaseService.getDB() == nil {
 		return nil, &ExtensionError{"query_db", "", errors.New("database service not available")}
 	}
 
-	rows, err := es.databaseService.db.Query(sqlGetAllExtensions)
+	rows, err := es.databaseService.getDB().Query(sqlGetAllExtensions)
 	if err != nil {
 		return nil, &ExtensionError{"query_extensions", "", err}
 	}
@@ -311,9 +315,12 @@ func (es *ExtensionService) UpdateExtensionState(id models.ExtensionID, enabled
 	es.mu.Lock()
 	defer es.mu.Unlock()
 
-	if es.databaseService == nil || es.databaseService.db == nil {
+	if es.databaseService == nil || es.databaseService.getDB() == nil {
 		return &ExtensionError{"check_db", string(id), errors.New("database service not available")}
 	}
+	if err := es.databaseService.ensureWritable(); err != nil {
+		return &ExtensionError{"check_writable", string(id), err}
+	}
 
 	var configJSON []byte
 	var err error
@@ -326,14 +333,14 @@ func (es *ExtensionService) UpdateExtensionState(id models.ExtensionID, enabled
 	} else {
 		// 如果没有提供配置，保持原有配置
 		var currentConfigJSON string
-		err := es.databaseService.db.QueryRow("SELECT config FROM extensions WHERE id = ?", string(id)).Scan(&currentConfigJSON)
+		err := es.databaseService.getDB().QueryRow("SELECT config FROM extensions WHERE id = ?", string(id)).Scan(&currentConfigJSON)
 		if err != nil {
 			return &ExtensionError{"query_current_config", string(id), err}
 		}
 		configJSON = []byte(currentConfigJSON)
 	}
 
-	_, err = es.databaseService.db.Exec(sqlUpdateExtension,
+	_, err = es.databaseService.getDB().Exec(sqlUpdateExtension,
 		enabled,
 		string(configJSON),
 		time.Now().Format("2006-01-02 15:04:05"),
@@ -362,12 +369,15 @@ func (es *ExtensionService) ResetAllExtensionsToDefault() error {
 	es.mu.Lock()
 	defer es.mu.Unlock()
 
-	if es.databaseService == nil || es.databaseService.db == nil {
+	if es.databaseService == nil || es.databaseService.getDB() == nil {
 		return &ExtensionError{"check_db", "", errors.New("database service not available")}
 	}
+	if err := es.databaseService.ensureWritable(); err != nil {
+		return &ExtensionError{"check_writable", "", err}
+	}
 
 	// 删除所有现有扩展
-	_, err := es.databaseService.db.Exec(sqlDeleteAllExtensions)
+	_, err := es.databaseService.getDB().Exec(sqlDeleteAllExtensions)
 	if err != nil {
 		return &ExtensionError{"delete_all_extensions", "", err}
 	}
@@ -0,0 +1,476 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// externalEditDebounce 文件变更后的同步延迟，合并编辑器保存时的多次写入
+const externalEditDebounce = 300 * time.Millisecond
+
+// externalFileNameInvalid 文件名中不允许的字符
+var externalFileNameInvalid = regexp.MustCompile(`[^\p{L}\p{N}._ -]+`)
+
+// ExternalEditSession 外部编辑会话
+type ExternalEditSession struct {
+	DocumentID int64  `json:"documentId"` // 文档ID
+	Path       string `json:"path"`       // 临时文件路径
+	StartedAt  string `json:"startedAt"`  // 开始时间
+	Conflict   bool   `json:"conflict"`   // 是否存在待解决的冲突
+}
+
+// ExternalEditConflict 外部编辑冲突，随事件发送给前端
+type ExternalEditConflict struct {
+	DocumentID int64  `json:"documentId"`
+	Path       string `json:"path"`
+}
+
+// externalEdit 内部会话状态
+type externalEdit struct {
+	session  ExternalEditSession
+	dir      string
+	baseline string // 最近一次同步时双方一致的内容
+	pending  string // 冲突时外部文件的内容
+	watcher  *fsnotify.Watcher
+	timer    *time.Timer
+	done     chan struct{}
+}
+
+// ExternalEditorService 在外部编辑器中打开文档并双向同步
+type ExternalEditorService struct {
+	logger          *log.LogService
+	configService   *ConfigService
+	documentService *DocumentService
+
+	mu       sync.Mutex
+	sessions map[int64]*externalEdit
+
+	// 冲突监听器
+	listenersMu sync.RWMutex
+	listeners   []func(conflict ExternalEditConflict)
+}
+
+// NewExternalEditorService 创建外部编辑器服务
+func NewExternalEditorService(configService *ConfigService, documentService *DocumentService, logger *log.LogService) *ExternalEditorService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	return &ExternalEditorService{
+		logger:          logger,
+		configService:   configService,
+		documentService: documentService,
+		sessions:        make(map[int64]*externalEdit),
+	}
+}
+
+// ServiceStartup 服务启动
+func (es *ExternalEditorService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	return nil
+}
+
+// OpenInExternalEditor 将文档导出到临时文件并用外部编辑器打开，保存后同步回文档
+func (es *ExternalEditorService) OpenInExternalEditor(documentID int64) (*ExternalEditSession, error) {
+	es.mu.Lock()
+	edit, ok := es.sessions[documentID]
+	es.mu.Unlock()
+
+	if !ok {
+		var err error
+		if edit, err = es.startSession(documentID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := es.launchEditor(edit.session.Path); err != nil {
+		return nil, err
+	}
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	session := edit.session
+	return &session, nil
+}
+
+// ListExternalEdits 列出进行中的外部编辑会话
+func (es *ExternalEditorService) ListExternalEdits() []ExternalEditSession {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	sessions := make([]ExternalEditSession, 0, len(es.sessions))
+	for _, edit := range es.sessions {
+		sessions = append(sessions, edit.session)
+	}
+	return sessions
+}
+
+// ResolveExternalEditConflict 解决冲突，keepExternal 为真时用外部文件覆盖文档，否则用文档覆盖外部文件
+func (es *ExternalEditorService) ResolveExternalEditConflict(documentID int64, keepExternal bool) error {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	edit, ok := es.sessions[documentID]
+	if !ok {
+		return fmt.Errorf("no external edit session for document %d", documentID)
+	}
+	if !edit.session.Conflict {
+		return nil
+	}
+
+	if keepExternal {
+		if err := es.documentService.UpdateDocumentContent(documentID, edit.pending); err != nil {
+			return fmt.Errorf("failed to update document: %w", err)
+		}
+		edit.baseline = edit.pending
+		helper.EmitEvent(constant.EVENT_EXTERNAL_EDIT_SYNCED, documentID)
+	} else {
+		doc, err := es.documentService.GetDocumentByID(documentID)
+		if err != nil {
+			return fmt.Errorf("failed to get document: %w", err)
+		}
+		if doc == nil {
+			return fmt.Errorf("document not found: %d", documentID)
+		}
+		if err := os.WriteFile(edit.session.Path, []byte(doc.Content), 0600); err != nil {
+			return fmt.Errorf("failed to write external file: %w", err)
+		}
+		edit.baseline = doc.Content
+	}
+
+	edit.pending = ""
+	edit.session.Conflict = false
+	return nil
+}
+
+// StopExternalEdit 结束外部编辑会话并删除临时文件
+func (es *ExternalEditorService) StopExternalEdit(documentID int64) error {
+	es.mu.Lock()
+	edit, ok := es.sessions[documentID]
+	delete(es.sessions, documentID)
+	es.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	es.closeSession(edit)
+	return nil
+}
+
+// startSession 导出文档并开始监听临时文件
+func (es *ExternalEditorService) startSession(documentID int64) (*externalEdit, error) {
+	doc, err := es.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil || doc.IsDeleted {
+		return nil, fmt.Errorf("document not found: %d", documentID)
+	}
+
+	dir, err := os.MkdirTemp("", "voidraft-edit-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	path := filepath.Join(dir, externalFileName(doc.Title))
+	if err := os.WriteFile(path, []byte(doc.Content), 0600); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to write external file: %w", err)
+	}
+
+	// 监听目录而不是文件，兼容先写临时文件再重命名的编辑器
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to watch directory: %w", err)
+	}
+
+	edit := &externalEdit{
+		session: ExternalEditSession{
+			DocumentID: documentID,
+			Path:       path,
+			StartedAt:  time.Now().Format(time.RFC3339),
+		},
+		dir:      dir,
+		baseline: doc.Content,
+		watcher:  watcher,
+		done:     make(chan struct{}),
+	}
+
+	es.mu.Lock()
+	if existing, ok := es.sessions[documentID]; ok {
+		es.mu.Unlock()
+		es.closeSession(edit)
+		return existing, nil
+	}
+	es.sessions[documentID] = edit
+	es.mu.Unlock()
+
+	go es.watch(edit)
+	es.logger.Info("External edit session started", "documentId", documentID, "path", path)
+	return edit, nil
+}
+
+// watch 处理文件变更事件
+func (es *ExternalEditorService) watch(edit *externalEdit) {
+	for {
+		select {
+		case <-edit.done:
+			return
+		case event, ok := <-edit.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != edit.session.Path || !event.Has(fsnotify.Write|fsnotify.Create) {
+				continue
+			}
+			es.mu.Lock()
+			if edit.timer != nil {
+				edit.timer.Stop()
+			}
+			edit.timer = time.AfterFunc(externalEditDebounce, func() { es.syncBack(edit) })
+			es.mu.Unlock()
+		case err, ok := <-edit.watcher.Errors:
+			if !ok {
+				return
+			}
+			es.logger.Error("External edit watcher error", "error", err)
+		}
+	}
+}
+
+// syncBack 将外部文件的修改同步回文档，应用内同时有修改时标记冲突
+func (es *ExternalEditorService) syncBack(edit *externalEdit) {
+	data, err := os.ReadFile(edit.session.Path)
+	if err != nil {
+		es.logger.Error("Failed to read external file", "path", edit.session.Path, "error", err)
+		return
+	}
+	external := string(data)
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	if es.sessions[edit.session.DocumentID] != edit || external == edit.baseline {
+		return
+	}
+
+	doc, err := es.documentService.GetDocumentByID(edit.session.DocumentID)
+	if err != nil || doc == nil || doc.IsDeleted {
+		es.logger.Error("Failed to get document for external edit", "documentId", edit.session.DocumentID, "error", err)
+		return
+	}
+
+	if doc.Content != edit.baseline && doc.Content != external {
+		edit.pending = external
+		edit.session.Conflict = true
+		conflict := ExternalEditConflict{
+			DocumentID: edit.session.DocumentID,
+			Path:       edit.session.Path,
+		}
+		helper.EmitEvent(constant.EVENT_EXTERNAL_EDIT_CONFLICT, conflict)
+		es.notifyConflict(conflict)
+		return
+	}
+
+	if err := es.documentService.UpdateDocumentContent(edit.session.DocumentID, external); err != nil {
+		es.logger.Error("Failed to sync external edit", "documentId", edit.session.DocumentID, "error", err)
+		return
+	}
+	edit.baseline = external
+	edit.pending = ""
+	edit.session.Conflict = false
+	helper.EmitEvent(constant.EVENT_EXTERNAL_EDIT_SYNCED, edit.session.DocumentID)
+}
+
+// onConflict 注册外部修改冲突监听器，供其他后端服务订阅
+func (es *ExternalEditorService) onConflict(listener func(conflict ExternalEditConflict)) {
+	es.listenersMu.Lock()
+	defer es.listenersMu.Unlock()
+	es.listeners = append(es.listeners, listener)
+}
+
+// notifyConflict 异步通知冲突监听器
+func (es *ExternalEditorService) notifyConflict(conflict ExternalEditConflict) {
+	es.listenersMu.RLock()
+	defer es.listenersMu.RUnlock()
+	for _, listener := range es.listeners {
+		go listener(conflict)
+	}
+}
+
+// launchEditor 按配置启动外部编辑器，未配置时使用系统默认程序
+func (es *ExternalEditorService) launchEditor(path string) error {
+	config, err := es.configService.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	args := splitCommandLine(config.Editing.ExternalEditor)
+	if len(args) == 0 {
+		args = defaultOpenCommand()
+	}
+	return es.startEditor(expandEditorArgs(args, path, 0))
+}
+
+// OpenInEditor 在源码编辑器中打开文件并定位到指定行，用于堆栈中的 file:line 链接
+func (es *ExternalEditorService) OpenInEditor(path string, line int) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("file not found: %s", path)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("path is a directory: %s", path)
+	}
+	config, err := es.configService.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	args := splitCommandLine(config.Editing.SourceEditor)
+	if len(args) == 0 {
+		args = defaultSourceEditor(config.Editing.ExternalEditor)
+	}
+	return es.startEditor(expandEditorArgs(args, path, line))
+}
+
+// startEditor 启动编辑器进程，不等待其退出
+func (es *ExternalEditorService) startEditor(args []string) error {
+	cmd := exec.Command(args[0], args[1:]...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to launch external editor: %w", err)
+	}
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			es.logger.Error("External editor exited with error", "command", args[0], "error", err)
+		}
+	}()
+	return nil
+}
+
+// expandEditorArgs 替换 {file} 和 {line} 占位符，命令中没有 {file} 时将路径追加到末尾
+func expandEditorArgs(args []string, path string, line int) []string {
+	line = max(line, 1)
+	expanded := make([]string, len(args))
+	replaced := false
+	for i, arg := range args {
+		if strings.Contains(arg, "{file}") {
+			replaced = true
+		}
+		expanded[i] = strings.NewReplacer("{file}", path, "{line}", strconv.Itoa(line)).Replace(arg)
+	}
+	if !replaced {
+		expanded = append(expanded, path)
+	}
+	return expanded
+}
+
+// defaultSourceEditor 未配置源码编辑器时优先使用 VS Code 跳转到行，其次使用外部编辑器或系统默认程序
+func defaultSourceEditor(externalEditor string) []string {
+	if _, err := exec.LookPath("code"); err == nil {
+		return []string{"code", "-g", "{file}:{line}"}
+	}
+	if args := splitCommandLine(externalEditor); len(args) > 0 {
+		return args
+	}
+	return defaultOpenCommand()
+}
+
+// closeSession 停止监听并删除临时目录
+func (es *ExternalEditorService) closeSession(edit *externalEdit) {
+	close(edit.done)
+	if edit.timer != nil {
+		edit.timer.Stop()
+	}
+	edit.watcher.Close()
+	if err := os.RemoveAll(edit.dir); err != nil {
+		es.logger.Error("Failed to remove external edit directory", "path", edit.dir, "error", err)
+	}
+}
+
+// externalFileName 根据文档标题生成临时文件名
+func externalFileName(title string) string {
+	name := strings.TrimSpace(externalFileNameInvalid.ReplaceAllString(title, "_"))
+	if name == "" || name == "." || name == ".." {
+		name = "document"
+	}
+	return name + ".txt"
+}
+
+// defaultOpenCommand 系统默认打开方式
+func defaultOpenCommand() []string {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"open", "-t"}
+	case "windows":
+		return []string{"cmd", "/c", "start", ""}
+	default:
+		return []string{"xdg-open"}
+	}
+}
+
+// splitCommandLine 按空白拆分命令行，支持单双引号
+func splitCommandLine(command string) []string {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+
+	for _, r := range command {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+// ServiceShutdown 服务关闭，清理所有会话
+func (es *ExternalEditorService) ServiceShutdown() error {
+	es.mu.Lock()
+	sessions := es.sessions
+	es.sessions = make(map[int64]*externalEdit)
+	es.mu.Unlock()
+
+	for _, edit := range sessions {
+		es.closeSession(edit)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"", nil},
+		{"code --wait", []string{"code", "--wait"}},
+		{`"C:\Program Files\Sublime Text\subl.exe" -w {file}`, []string{`C:\Program Files\Sublime Text\subl.exe`, "-w", "{file}"}},
+		{`gvim  -c 'set ft=markdown'`, []string{"gvim", "-c", "set ft=markdown"}},
+		{`emacsclient ""`, []string{"emacsclient", ""}},
+	}
+
+	for _, tt := range tests {
+		if got := splitCommandLine(tt.command); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCommandLine(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestExternalFileName(t *testing.T) {
+	if got := externalFileName("notes/2024: plan?"); got != "notes_2024_ plan_.txt" {
+		t.Errorf("externalFileName() = %q", got)
+	}
+	if got := externalFileName(".."); got != "document.txt" {
+		t.Errorf("externalFileName(\"..\") = %q", got)
+	}
+}
+
+func TestExpandEditorArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		line int
+		want []string
+	}{
+		{[]string{"code", "-g", "{file}:{line}"}, 42, []string{"code", "-g", "/src/main.go:42"}},
+		{[]string{"vim", "+{line}"}, 0, []string{"vim", "+1", "/src/main.go"}},
+		{[]string{"subl"}, 7, []string{"subl", "/src/main.go"}},
+	}
+
+	for _, tt := range tests {
+		if got := expandEditorArgs(tt.args, "/src/main.go", tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("expandEditorArgs(%q, %d) = %q, want %q", tt.args, tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/gallery"
+	"voidraft/internal/common/netclient"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+const (
+	// galleryCacheDirName 索引和内容缓存目录
+	galleryCacheDirName = "gallery"
+	// templateDirName 已安装模板目录
+	templateDirName = "templates"
+	// galleryRequestTimeout 下载超时时间
+	galleryRequestTimeout = 30 * time.Second
+)
+
+// ErrGalleryDisabled 模板库未启用
+var ErrGalleryDisabled = errors.New("template gallery is disabled")
+
+// GalleryService 模板库服务，获取签名索引，预览并安装社区模板和主题
+// 模板只作为文本插入文档，主题按颜色方案导入，不会执行其中的任何内容
+type GalleryService struct {
+	logger          *log.LogService
+	configService   *ConfigService
+	documentService *DocumentService
+	themeService    *ThemeService
+	httpClient      *http.Client
+}
+
+// NewGalleryService 创建模板库服务
+func NewGalleryService(configService *ConfigService, documentService *DocumentService, themeService *ThemeService, logger *log.LogService) *GalleryService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	return &GalleryService{
+		logger:          logger,
+		configService:   configService,
+		documentService: documentService,
+		themeService:    themeService,
+		httpClient:      netclient.New(netclient.Config{Service: "gallery", Timeout: galleryRequestTimeout, Retries: 2}),
+	}
+}
+
+// GetGallery 获取模板库列表，refresh 为假时优先使用本地缓存，网络不可用时回退到缓存
+func (gs *GalleryService) GetGallery(refresh bool) (*models.GalleryListing, error) {
+	idx, cached, err := gs.index(refresh)
+	if err != nil {
+		return nil, err
+	}
+	listing := &models.GalleryListing{
+		Items:   make([]models.GalleryItem, 0, len(idx.Items)),
+		Updated: idx.Updated,
+		Cached:  cached,
+	}
+	for i := range idx.Items {
+		listing.Items = append(listing.Items, gs.galleryItem(&idx.Items[i]))
+	}
+	return listing, nil
+}
+
+// PreviewGalleryItem 下载并校验条目内容用于预览，不安装
+func (gs *GalleryService) PreviewGalleryItem(id string) (*models.GalleryPreview, error) {
+	item, data, err := gs.fetchItem(id)
+	if err != nil {
+		return nil, err
+	}
+	return &models.GalleryPreview{Item: gs.galleryItem(item), Content: string(data)}, nil
+}
+
+// InstallGalleryItem 安装条目：模板保存到本地模板目录，主题导入为自定义主题
+func (gs *GalleryService) InstallGalleryItem(id string) error {
+	item, data, err := gs.fetchItem(id)
+	if err != nil {
+		return err
+	}
+
+	switch item.Kind {
+	case gallery.KindTheme:
+		if _, err := gs.themeService.ImportThemeData(string(data), item.Name); err != nil {
+			return err
+		}
+	case gallery.KindTemplate:
+		dir, err := gs.templateDir()
+		if err != nil {
+			return err
+		}
+		template := models.DocumentTemplate{
+			ID:          item.ID,
+			Name:        item.Name,
+			Description: item.Description,
+			Author:      item.Author,
+			Content:     string(data),
+			InstalledAt: time.Now().Format(time.RFC3339),
+		}
+		encoded, err := json.MarshalIndent(template, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(filepath.Join(dir, item.ID+".json"), encoded, 0644); err != nil {
+			return fmt.Errorf("failed to save template: %w", err)
+		}
+	}
+	gs.logger.Info("Gallery item installed", "id", item.ID, "kind", item.Kind)
+	return nil
+}
+
+// ListTemplates 列出已安装的模板
+func (gs *GalleryService) ListTemplates() ([]models.DocumentTemplate, error) {
+	dir, err := gs.templateDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template directory: %w", err)
+	}
+
+	templates := []models.DocumentTemplate{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		template, err := gs.readTemplate(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			gs.logger.Error("Failed to read template", "file", entry.Name(), "error", err)
+			continue
+		}
+		templates = append(templates, *template)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// CreateDocumentFromTemplate 以模板内容新建文档，title 为空时使用模板名称
+func (gs *GalleryService) CreateDocumentFromTemplate(id string, title string) (*models.Document, error) {
+	dir, err := gs.templateDir()
+	if err != nil {
+		return nil, err
+	}
+	if !gallery.ValidID(id) {
+		return nil, fmt.Errorf("template not found: %s", id)
+	}
+	template, err := gs.readTemplate(filepath.Join(dir, id+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("template not found: %s", id)
+	}
+	if strings.TrimSpace(title) == "" {
+		title = template.Name
+	}
+
+	doc, err := gs.documentService.CreateDocument(title)
+	if err != nil {
+		return nil, err
+	}
+	// 模板自带块分隔符时原样使用，否则作为一个自动识别语言的块
+	content := template.Content
+	if !strings.HasPrefix(content, blocks.DelimiterPrefix) {
+		content = blocks.Delimiter(blocks.DefaultLanguage, true) + content
+	}
+	if err := gs.documentService.UpdateDocumentContent(doc.ID, content); err != nil {
+		return nil, err
+	}
+	doc.Content = content
+	return doc, nil
+}
+
+// RemoveTemplate 删除已安装的模板
+func (gs *GalleryService) RemoveTemplate(id string) error {
+	dir, err := gs.templateDir()
+	if err != nil {
+		return err
+	}
+	if !gallery.ValidID(id) {
+		return fmt.Errorf("template not found: %s", id)
+	}
+	if err := os.Remove(filepath.Join(dir, id+".json")); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("template not found: %s", id)
+		}
+		return err
+	}
+	return nil
+}
+
+// index 获取索引，refresh 为假且缓存有效时不访问网络
+func (gs *GalleryService) index(refresh bool) (*gallery.Index, bool, error) {
+	config, client, key, err := gs.client()
+	if err != nil {
+		return nil, false, err
+	}
+	if !refresh {
+		if idx, err := client.CachedIndex(key); err == nil {
+			return idx, true, nil
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), galleryRequestTimeout)
+	defer cancel()
+	return client.FetchIndex(ctx, config.IndexURL, key)
+}
+
+// fetchItem 按 ID 获取并校验条目内容
+func (gs *GalleryService) fetchItem(id string) (*gallery.Item, []byte, error) {
+	idx, _, err := gs.index(false)
+	if err != nil {
+		return nil, nil, err
+	}
+	item, ok := idx.Find(id)
+	if !ok {
+		return nil, nil, fmt.Errorf("gallery item not found: %s", id)
+	}
+
+	config, client, _, err := gs.client()
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), galleryRequestTimeout)
+	defer cancel()
+	data, err := client.FetchItem(ctx, config.IndexURL, item)
+	if err != nil {
+		return nil, nil, err
+	}
+	return item, data, nil
+}
+
+// client 按配置创建下载客户端
+func (gs *GalleryService) client() (*models.GalleryConfig, *gallery.Client, ed25519.PublicKey, error) {
+	config, err := gs.configService.GetConfig()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get config: %w", err)
+	}
+	if !config.Gallery.Enabled {
+		return nil, nil, nil, ErrGalleryDisabled
+	}
+	if config.Gallery.IndexURL == "" {
+		return nil, nil, nil, errors.New("gallery index url is not configured")
+	}
+	key, err := gallery.ParsePublicKey(config.Gallery.PublicKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	client := &gallery.Client{
+		HTTP:     gs.httpClient,
+		CacheDir: filepath.Join(config.General.DataPath, galleryCacheDirName),
+	}
+	return &config.Gallery, client, key, nil
+}
+
+// galleryItem 转换为前端显示的条目，并标记是否已安装
+func (gs *GalleryService) galleryItem(item *gallery.Item) models.GalleryItem {
+	result := models.GalleryItem{
+		ID:          item.ID,
+		Kind:        string(item.Kind),
+		Name:        item.Name,
+		Description: item.Description,
+		Author:      item.Author,
+		Size:        item.Size,
+	}
+	switch item.Kind {
+	case gallery.KindTheme:
+		theme, err := gs.themeService.GetThemeByName(item.Name)
+		result.Installed = err == nil && theme != nil
+	case gallery.KindTemplate:
+		if dir, err := gs.templateDir(); err == nil {
+			_, err := os.Stat(filepath.Join(dir, item.ID+".json"))
+			result.Installed = err == nil
+		}
+	}
+	return result
+}
+
+// templateDir 已安装模板目录
+func (gs *GalleryService) templateDir() (string, error) {
+	config, err := gs.configService.GetConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config: %w", err)
+	}
+	dir := filepath.Join(config.General.DataPath, templateDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create template directory: %w", err)
+	}
+	return dir, nil
+}
+
+// readTemplate 读取模板文件
+func (gs *GalleryService) readTemplate(path string) (*models.DocumentTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var template models.DocumentTemplate
+	if err := json.Unmarshal(data, &template); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
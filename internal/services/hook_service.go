@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+const (
+	// hookDefaultTimeout 未配置超时时命令的最长运行时间
+	hookDefaultTimeout = 30 * time.Second
+	// hookSaveDebounce 连续保存合并为一次触发，避免自动保存频繁执行命令
+	hookSaveDebounce = 2 * time.Second
+	// hookMaxOutput 写入日志的命令输出上限
+	hookMaxOutput = 4096
+)
+
+// hookPlaceholder 命令参数中的 {name} 占位符
+var hookPlaceholder = regexp.MustCompile(`\{([A-Za-z]+)\}`)
+
+// HookService 事件命令服务
+// 订阅文档保存、导出结束和外部编辑冲突，按配置执行用户命令，输出写入日志，是插件系统之外的轻量扩展方式
+type HookService struct {
+	logger                *log.LogService
+	configService         *ConfigService
+	documentService       *DocumentService
+	jobService            *JobService
+	externalEditorService *ExternalEditorService
+
+	mu     sync.Mutex
+	saves  map[int64]*time.Timer // 等待合并的文档保存
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewHookService 创建事件命令服务实例
+func NewHookService(configService *ConfigService, documentService *DocumentService, jobService *JobService, externalEditorService *ExternalEditorService, logger *log.LogService) *HookService {
+	if logger == nil {
+		logger = log.New()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &HookService{
+		logger:                logger,
+		configService:         configService,
+		documentService:       documentService,
+		jobService:            jobService,
+		externalEditorService: externalEditorService,
+		saves:                 make(map[int64]*time.Timer),
+		ctx:                   ctx,
+		cancel:                cancel,
+	}
+}
+
+// ServiceStartup 订阅事件
+func (hs *HookService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	hs.documentService.onDocumentChange(hs.handleDocumentChange)
+	hs.jobService.onJobFinished(hs.handleJobFinished)
+	hs.externalEditorService.onConflict(hs.handleConflict)
+	return nil
+}
+
+// handleDocumentChange 文档内容保存后延迟触发，期间的再次保存重新计时
+func (hs *HookService) handleDocumentChange(event models.DocumentChangeEvent) {
+	if event.Type != models.DocumentChangeContent || !hs.hasHooks(models.HookEventDocumentSaved) {
+		return
+	}
+
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	if hs.ctx.Err() != nil {
+		return
+	}
+	if timer, ok := hs.saves[event.DocumentID]; ok {
+		timer.Reset(hookSaveDebounce)
+		return
+	}
+	hs.saves[event.DocumentID] = time.AfterFunc(hookSaveDebounce, func() {
+		hs.mu.Lock()
+		delete(hs.saves, event.DocumentID)
+		hs.mu.Unlock()
+		hs.fireDocumentSaved(event.DocumentID)
+	})
+}
+
+// fireDocumentSaved 读取文档标题和标签并触发保存事件
+func (hs *HookService) fireDocumentSaved(documentID int64) {
+	doc, err := hs.documentService.GetDocumentByID(documentID)
+	if err != nil || doc == nil {
+		return
+	}
+	tags, err := hs.documentService.GetDocumentTags(documentID)
+	if err != nil {
+		hs.logger.Error("Failed to get document tags for hooks", "documentId", documentID, "error", err)
+		return
+	}
+	hs.fire(models.HookEventDocumentSaved, tags, map[string]string{
+		"documentId": strconv.FormatInt(doc.ID, 10),
+		"title":      doc.Title,
+		"updatedAt":  doc.UpdatedAt,
+	})
+}
+
+// handleJobFinished 导出任务结束时触发
+func (hs *HookService) handleJobFinished(job models.Job) {
+	if job.Kind != models.JobKindExport {
+		return
+	}
+	hs.fire(models.HookEventExportFinished, nil, map[string]string{
+		"jobId":  strconv.FormatInt(job.ID, 10),
+		"title":  job.Title,
+		"status": string(job.Status),
+		"error":  job.Error,
+		"result": job.Result,
+	})
+}
+
+// handleConflict 外部编辑冲突时触发
+func (hs *HookService) handleConflict(conflict ExternalEditConflict) {
+	vars := map[string]string{
+		"documentId": strconv.FormatInt(conflict.DocumentID, 10),
+		"path":       conflict.Path,
+	}
+	var tags []string
+	if doc, err := hs.documentService.GetDocumentByID(conflict.DocumentID); err == nil && doc != nil {
+		vars["title"] = doc.Title
+		tags, _ = hs.documentService.GetDocumentTags(conflict.DocumentID)
+	}
+	hs.fire(models.HookEventSyncConflict, tags, vars)
+}
+
+// hasHooks 是否有启用的命令订阅了该事件，不考虑标签过滤，用于决定是否需要读取文档
+func (hs *HookService) hasHooks(event models.HookEvent) bool {
+	return len(hs.subscribedHooks(event)) > 0
+}
+
+// subscribedHooks 订阅该事件的启用命令
+func (hs *HookService) subscribedHooks(event models.HookEvent) []models.EventHook {
+	config, err := hs.configService.GetConfig()
+	if err != nil || !config.Hooks.Enabled {
+		return nil
+	}
+	var hooks []models.EventHook
+	for _, hook := range config.Hooks.Hooks {
+		if !hook.Enabled || hook.Event != event || strings.TrimSpace(hook.Command) == "" {
+			continue
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks
+}
+
+// hooksFor 订阅该事件且标签匹配的命令，设置了标签的命令要求文档带有其中任一标签
+func (hs *HookService) hooksFor(event models.HookEvent, tags []string) []models.EventHook {
+	var hooks []models.EventHook
+	for _, hook := range hs.subscribedHooks(event) {
+		if len(hook.Tags) > 0 && !hookTagsMatch(hook.Tags, tags) {
+			continue
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks
+}
+
+// hookTagsMatch 文档标签中是否包含任一要求的标签，标签名不区分大小写
+func hookTagsMatch(want, tags []string) bool {
+	for _, w := range want {
+		w = strings.TrimSpace(w)
+		for _, tag := range tags {
+			if w != "" && strings.EqualFold(w, tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fire 执行订阅该事件的所有命令，tags 为事件所属文档的标签，非文档事件为空
+func (hs *HookService) fire(event models.HookEvent, tags []string, vars map[string]string) {
+	hooks := hs.hooksFor(event, tags)
+	if len(hooks) == 0 || hs.ctx.Err() != nil {
+		return
+	}
+	vars["event"] = string(event)
+	for _, hook := range hooks {
+		hs.wg.Add(1)
+		go func(hook models.EventHook) {
+			defer hs.wg.Done()
+			hs.run(hook, vars)
+		}(hook)
+	}
+}
+
+// run 执行命令并把输出写入日志
+func (hs *HookService) run(hook models.EventHook, vars map[string]string) {
+	args := expandHookArgs(splitCommandLine(hook.Command), vars)
+	if len(args) == 0 {
+		return
+	}
+	timeout := hookDefaultTimeout
+	if hook.Timeout > 0 {
+		timeout = time.Duration(hook.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(hs.ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	output, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput()
+	attrs := []any{
+		"hook", hook.Name,
+		"event", hook.Event,
+		"command", args[0],
+		"duration", time.Since(start).Round(time.Millisecond),
+		"output", truncateHookOutput(output),
+	}
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		hs.logger.Error("Hook command timed out", append(attrs, "timeout", timeout)...)
+	case err != nil:
+		hs.logger.Error("Hook command failed", append(attrs, "error", err)...)
+	default:
+		hs.logger.Info("Hook command finished", attrs...)
+	}
+}
+
+// expandHookArgs 替换参数中的 {name} 占位符，未知变量替换为空字符串
+// 替换在拆分之后进行，变量中的空白和引号不会产生新的参数
+func expandHookArgs(args []string, vars map[string]string) []string {
+	expanded := make([]string, len(args))
+	for i, arg := range args {
+		expanded[i] = hookPlaceholder.ReplaceAllStringFunc(arg, func(m string) string {
+			return vars[m[1:len(m)-1]]
+		})
+	}
+	return expanded
+}
+
+// truncateHookOutput 截断过长的输出
+func truncateHookOutput(output []byte) string {
+	text := strings.TrimSpace(string(output))
+	if len(text) > hookMaxOutput {
+		return fmt.Sprintf("%s… (%d bytes truncated)", text[:hookMaxOutput], len(text)-hookMaxOutput)
+	}
+	return text
+}
+
+// ServiceShutdown 取消等待中的触发并终止运行中的命令
+func (hs *HookService) ServiceShutdown() error {
+	hs.mu.Lock()
+	hs.cancel()
+	for id, timer := range hs.saves {
+		timer.Stop()
+		delete(hs.saves, id)
+	}
+	hs.mu.Unlock()
+	hs.wg.Wait()
+	return nil
+}
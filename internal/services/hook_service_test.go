@@ -0,0 +1,150 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"voidraft/internal/models"
+
+	"github.com/knadh/koanf/providers/structs"
+	"github.com/knadh/koanf/v2"
+)
+
+// hookHelperEnv 辅助进程把参数写入该环境变量指定的文件
+const hookHelperEnv = "VOIDRAFT_HOOK_HELPER_OUTPUT"
+
+// TestHookHelperProcess 作为命令被事件触发时执行，不是真正的测试
+func TestHookHelperProcess(t *testing.T) {
+	output := os.Getenv(hookHelperEnv)
+	if output == "" {
+		return
+	}
+	args := os.Args
+	for i, arg := range args {
+		if arg == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+	file, err := os.OpenFile(output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		os.Exit(1)
+	}
+	fmt.Fprintln(file, strings.Join(args, " "))
+	file.Close()
+	os.Exit(0)
+}
+
+// newTestConfigService 创建只在内存中保存给定配置的配置服务
+func newTestConfigService(t *testing.T, config *models.AppConfig) *ConfigService {
+	t.Helper()
+	configService := &ConfigService{koanf: koanf.New(".")}
+	if err := configService.koanf.Load(structs.Provider(config, "json"), nil); err != nil {
+		t.Fatal(err)
+	}
+	return configService
+}
+
+// newTestHookService 使用给定的命令配置创建事件命令服务
+func newTestHookService(t *testing.T, documentService *DocumentService, hooks ...models.EventHook) *HookService {
+	t.Helper()
+	config := models.NewDefaultAppConfig()
+	config.Hooks = models.HooksConfig{Enabled: true, Hooks: hooks}
+	hs := NewHookService(newTestConfigService(t, config), documentService, nil, nil, nil)
+	t.Cleanup(func() { hs.ServiceShutdown() })
+	return hs
+}
+
+// hookHelperCommand 调用辅助进程并输出给定参数的命令
+func hookHelperCommand(t *testing.T, args string) (string, string) {
+	t.Helper()
+	output := filepath.Join(t.TempDir(), "hook.out")
+	t.Setenv(hookHelperEnv, output)
+	return fmt.Sprintf(`"%s" -test.run=^TestHookHelperProcess$ -- %s`, os.Args[0], args), output
+}
+
+func TestExpandHookArgs(t *testing.T) {
+	args := splitCommandLine(`notify-send "Saved {title}" --id={documentId} {unknown}`)
+	got := expandHookArgs(args, map[string]string{
+		"title":      `My "draft" notes`,
+		"documentId": "42",
+	})
+	want := []string{"notify-send", `Saved My "draft" notes`, "--id=42", ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expandHookArgs() = %q, want %q", got, want)
+	}
+}
+
+func TestHooksFor(t *testing.T) {
+	hs := newTestHookService(t, nil,
+		models.EventHook{Name: "all", Enabled: true, Event: models.HookEventDocumentSaved, Command: "all"},
+		models.EventHook{Name: "work", Enabled: true, Event: models.HookEventDocumentSaved, Command: "work", Tags: []string{"Work", "urgent"}},
+		models.EventHook{Name: "disabled", Event: models.HookEventDocumentSaved, Command: "disabled"},
+		models.EventHook{Name: "empty", Enabled: true, Event: models.HookEventDocumentSaved, Command: "  "},
+		models.EventHook{Name: "export", Enabled: true, Event: models.HookEventExportFinished, Command: "export", Tags: []string{"work"}},
+	)
+
+	names := func(hooks []models.EventHook) []string {
+		var got []string
+		for _, hook := range hooks {
+			got = append(got, hook.Name)
+		}
+		return got
+	}
+	tests := []struct {
+		name  string
+		event models.HookEvent
+		tags  []string
+		want  []string
+	}{
+		{"untagged document", models.HookEventDocumentSaved, nil, []string{"all"}},
+		{"other tag", models.HookEventDocumentSaved, []string{"home"}, []string{"all"}},
+		{"matching tag ignores case", models.HookEventDocumentSaved, []string{"home", "work"}, []string{"all", "work"}},
+		{"tagged hook on non-document event", models.HookEventExportFinished, nil, nil},
+		{"no subscribers", models.HookEventSyncConflict, []string{"work"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(hs.hooksFor(tt.event, tt.tags)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("hooksFor(%s, %v) = %v, want %v", tt.event, tt.tags, got, tt.want)
+			}
+		})
+	}
+
+	// 带标签过滤的命令也算订阅，保存事件需要读取文档后再过滤
+	if !hs.hasHooks(models.HookEventExportFinished) {
+		t.Error("hasHooks(export.finished) = false, want true")
+	}
+	if hs.hasHooks(models.HookEventSyncConflict) {
+		t.Error("hasHooks(sync.conflict) = true, want false")
+	}
+}
+
+func TestFireDocumentSaved(t *testing.T) {
+	ds := newTestDocumentService(t)
+	tagged := createTestDocument(t, ds, "Plan", "\n∞∞∞text-a\nplan")
+	untagged := createTestDocument(t, ds, "", "\n∞∞∞text-a\n")
+	if _, err := ds.AddTag(tagged, "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	command, output := hookHelperCommand(t, "{event} {documentId} {title}")
+	hs := newTestHookService(t, ds, models.EventHook{
+		Name: "work", Enabled: true, Event: models.HookEventDocumentSaved, Command: command, Tags: []string{"Work"},
+	})
+
+	hs.fireDocumentSaved(untagged)
+	hs.fireDocumentSaved(tagged)
+	hs.wg.Wait()
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), fmt.Sprintf("document.saved %d Plan\n", tagged); got != want {
+		t.Errorf("hook output = %q, want %q", got, want)
+	}
+}
@@ -356,6 +356,58 @@ func (hs *HotkeyService) IsRegistered() bool {
 	return hs.registered.Load()
 }
 
+// hotkeyBinding 其他服务注册的全局热键，Close 后不再触发回调
+type hotkeyBinding struct {
+	hk     *hotkey.Hotkey
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// bindHotkey 注册全局热键，按下时在新的 goroutine 中调用 onKeydown，按键转换规则与主窗口热键一致
+func (hs *HotkeyService) bindHotkey(combo *models.HotkeyCombo, onKeydown func()) (*hotkeyBinding, error) {
+	if !hs.isValidHotkey(combo) {
+		return nil, errors.New("invalid hotkey combination")
+	}
+	key, mods, err := hs.convertHotkey(combo)
+	if err != nil {
+		return nil, fmt.Errorf("convert hotkey: %w", err)
+	}
+
+	hk := hotkey.New(mods, key)
+	if err := hk.Register(); err != nil {
+		return nil, fmt.Errorf("register hotkey: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	binding := &hotkeyBinding{hk: hk, cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(binding.done)
+		keydown := hk.Keydown()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-keydown:
+				if !ok {
+					return
+				}
+				go onKeydown()
+			}
+		}
+	}()
+	return binding, nil
+}
+
+// Close 取消注册热键并等待监听结束
+func (b *hotkeyBinding) Close() {
+	if b == nil {
+		return
+	}
+	b.cancel()
+	_ = b.hk.Close()
+	<-b.done
+}
+
 // ServiceShutdown 关闭服务
 func (hs *HotkeyService) ServiceShutdown() error {
 	hs.isShutdown.Store(true)
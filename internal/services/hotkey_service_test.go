@@ -368,6 +368,27 @@ func BenchmarkHotkeyRegistration(b *testing.B) {
 	}
 }
 
+// TestBindHotkeyInvalid 测试其他服务注册无效热键
+func TestBindHotkeyInvalid(t *testing.T) {
+	service := NewHotkeyService(&ConfigService{}, log.New())
+
+	invalid := []*models.HotkeyCombo{
+		nil,
+		{Key: "A"},
+		{Ctrl: true},
+	}
+	for _, combo := range invalid {
+		binding, err := service.bindHotkey(combo, func() { t.Error("callback should not run") })
+		if err == nil || binding != nil {
+			t.Errorf("bindHotkey(%+v) = %v, %v, want error", combo, binding, err)
+		}
+	}
+
+	// 未注册时取消注册不应出错
+	var binding *hotkeyBinding
+	binding.Close()
+}
+
 // BenchmarkHotkeyConversion 基准测试：热键转换
 func BenchmarkHotkeyConversion(b *testing.B) {
 	logger := log.New()
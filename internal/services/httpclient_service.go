@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"strings"
 	"time"
+	"voidraft/internal/common/netclient"
 
 	"github.com/wailsapp/wails/v3/pkg/services/log"
 	"resty.dev/v3"
@@ -43,6 +44,7 @@ func NewHttpClientService(logger *log.LogService) *HttpClientService {
 	client := resty.New().
 		SetTimeout(30 * time.Second).
 		SetRetryCount(0).
+		SetTransport(netclient.NewTransport(netclient.Config{Service: "http-client"})).
 		EnableTrace().
 		SetHeaders(map[string]string{
 			"Access-Control-Allow-Origin":      "*",
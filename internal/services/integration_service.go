@@ -0,0 +1,384 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"voidraft/internal/common/keystore"
+	"voidraft/internal/common/netclient"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+const (
+	// integrationRequestTimeout 单次引用解析的请求超时
+	integrationRequestTimeout = 10 * time.Second
+	// integrationMaxReferences 单个文档最多解析的引用数量，避免大文档触发大量请求
+	integrationMaxReferences = 50
+	// integrationDefaultCacheTTL 未配置时的默认缓存时间
+	integrationDefaultCacheTTL = 30 * time.Minute
+)
+
+var (
+	// jiraKeyPattern 匹配 ABC-123 形式的Jira问题Key
+	jiraKeyPattern = regexp.MustCompile(`\b([A-Z][A-Z0-9]{1,9})-([1-9][0-9]{0,6})\b`)
+	// githubRefPattern 匹配 org/repo#456 形式的GitHub引用
+	githubRefPattern = regexp.MustCompile(`(?:^|[^\w/.-])([A-Za-z0-9][A-Za-z0-9-]{0,38})/([A-Za-z0-9._-]{1,100})#([1-9][0-9]{0,7})\b`)
+)
+
+// jiraKeyStopList 常见的、形似Jira Key但并非问题引用的写法
+var jiraKeyStopList = map[string]bool{
+	"UTF": true, "SHA": true, "ISO": true, "RFC": true, "CVE": true,
+	"TLS": true, "SSL": true, "AES": true, "HTTP": true, "MD": true,
+	"WIN": true, "GMT": true, "UTC": true, "PEP": true,
+}
+
+// integrationKeyringKey 钥匙串中保存集成访问令牌的键
+func integrationKeyringKey(provider models.IssueProvider) string {
+	return "integration:" + string(provider)
+}
+
+// cachedIssueReference 缓存的引用解析结果
+type cachedIssueReference struct {
+	reference *models.IssueReference
+	expiresAt time.Time
+}
+
+// IntegrationService 第三方问题追踪集成服务
+type IntegrationService struct {
+	logger          *log.LogService
+	configService   *ConfigService
+	documentService *DocumentService
+	httpClient      *http.Client
+
+	mu    sync.RWMutex
+	cache map[string]*cachedIssueReference
+}
+
+// NewIntegrationService 创建集成服务
+func NewIntegrationService(configService *ConfigService, documentService *DocumentService, logger *log.LogService) *IntegrationService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	return &IntegrationService{
+		logger:          logger,
+		configService:   configService,
+		documentService: documentService,
+		httpClient:      netclient.New(netclient.Config{Service: "integration", Timeout: integrationRequestTimeout, Retries: 2}),
+		cache:           make(map[string]*cachedIssueReference),
+	}
+}
+
+// ServiceStartup 服务启动
+func (is *IntegrationService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	return nil
+}
+
+// SetIntegrationToken 将访问令牌保存到系统钥匙串，token 为空时删除
+func (is *IntegrationService) SetIntegrationToken(provider models.IssueProvider, token string) error {
+	if !provider.IsValid() {
+		return fmt.Errorf("unknown integration provider: %s", provider)
+	}
+
+	var err error
+	if token = strings.TrimSpace(token); token == "" {
+		err = keystore.Delete(integrationKeyringKey(provider))
+	} else {
+		err = keystore.Set(integrationKeyringKey(provider), token)
+	}
+	if err != nil {
+		return fmt.Errorf("store %s token: %w", provider, err)
+	}
+	is.ClearReferenceCache()
+	return nil
+}
+
+// HasIntegrationToken 是否已保存访问令牌，不返回令牌本身
+func (is *IntegrationService) HasIntegrationToken(provider models.IssueProvider) (bool, error) {
+	if !provider.IsValid() {
+		return false, fmt.Errorf("unknown integration provider: %s", provider)
+	}
+	return keystore.Exists(integrationKeyringKey(provider))
+}
+
+// integrationToken 读取钥匙串中的访问令牌，未保存或读取失败时返回空，按匿名访问
+func (is *IntegrationService) integrationToken(provider models.IssueProvider) string {
+	token, err := keystore.Get(integrationKeyringKey(provider))
+	if err != nil && !errors.Is(err, keystore.ErrNotFound) {
+		is.logger.Warning("Failed to read integration token", "provider", provider, "error", err)
+	}
+	return token
+}
+
+// ResolveReferences 识别文档中的问题引用并解析标题和状态
+func (is *IntegrationService) ResolveReferences(documentID int64) ([]*models.IssueReference, error) {
+	doc, err := is.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("document not found: %d", documentID)
+	}
+
+	config, err := is.configService.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+
+	keys := extractIssueReferences(doc.Content, config.Integrations.Jira.Projects)
+	if len(keys) > integrationMaxReferences {
+		keys = keys[:integrationMaxReferences]
+	}
+
+	references := make([]*models.IssueReference, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func(i int, key string) {
+			defer wg.Done()
+			references[i] = is.resolve(&config.Integrations, key)
+		}(i, key)
+	}
+	wg.Wait()
+
+	return references, nil
+}
+
+// ResolveReference 解析单个问题引用，供悬浮卡片使用
+func (is *IntegrationService) ResolveReference(key string) (*models.IssueReference, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, errors.New("reference key is empty")
+	}
+
+	config, err := is.configService.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+
+	return is.resolve(&config.Integrations, key), nil
+}
+
+// ClearReferenceCache 清空引用解析缓存
+func (is *IntegrationService) ClearReferenceCache() {
+	is.mu.Lock()
+	defer is.mu.Unlock()
+	is.cache = make(map[string]*cachedIssueReference)
+}
+
+// resolve 解析引用，优先使用缓存
+func (is *IntegrationService) resolve(config *models.IntegrationsConfig, key string) *models.IssueReference {
+	if cached := is.getCached(key); cached != nil {
+		return cached
+	}
+
+	var ref *models.IssueReference
+	if strings.Contains(key, "#") {
+		ref = is.resolveGithub(&config.Github, key)
+	} else {
+		ref = is.resolveJira(&config.Jira, key)
+	}
+
+	// 仅缓存解析成功的结果，失败的引用下次重试
+	if ref.Resolved {
+		ttl := time.Duration(config.CacheTTL) * time.Minute
+		if ttl <= 0 {
+			ttl = integrationDefaultCacheTTL
+		}
+		is.mu.Lock()
+		is.cache[key] = &cachedIssueReference{reference: ref, expiresAt: time.Now().Add(ttl)}
+		is.mu.Unlock()
+	}
+	return ref
+}
+
+// getCached 获取未过期的缓存结果
+func (is *IntegrationService) getCached(key string) *models.IssueReference {
+	is.mu.RLock()
+	defer is.mu.RUnlock()
+
+	entry, ok := is.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil
+	}
+	ref := *entry.reference
+	return &ref
+}
+
+// resolveJira 通过Jira REST API解析问题
+func (is *IntegrationService) resolveJira(config *models.JiraIntegrationConfig, key string) *models.IssueReference {
+	ref := &models.IssueReference{Key: key, Provider: models.IssueProviderJira}
+	if !config.Enabled || config.BaseURL == "" {
+		ref.Error = "jira integration is not configured"
+		return ref
+	}
+
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	apiURL := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=summary,status", baseURL, url.PathEscape(key))
+
+	var result struct {
+		Fields struct {
+			Summary string `json:"summary"`
+			Status  struct {
+				Name string `json:"name"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+
+	token := is.integrationToken(models.IssueProviderJira)
+	err := is.getJSON(apiURL, func(req *http.Request) {
+		if config.Email != "" {
+			req.SetBasicAuth(config.Email, token)
+		} else if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}, &result)
+	if err != nil {
+		ref.Error = err.Error()
+		return ref
+	}
+
+	ref.Title = result.Fields.Summary
+	ref.Status = result.Fields.Status.Name
+	ref.URL = fmt.Sprintf("%s/browse/%s", baseURL, key)
+	ref.Resolved = true
+	return ref
+}
+
+// resolveGithub 通过GitHub REST API解析Issue或Pull Request
+func (is *IntegrationService) resolveGithub(config *models.GithubIntegrationConfig, key string) *models.IssueReference {
+	ref := &models.IssueReference{Key: key, Provider: models.IssueProviderGithub}
+	if !config.Enabled {
+		ref.Error = "github integration is not configured"
+		return ref
+	}
+
+	repo, number, ok := strings.Cut(key, "#")
+	if !ok {
+		ref.Error = "invalid github reference"
+		return ref
+	}
+
+	apiBase := strings.TrimRight(config.APIURL, "/")
+	if apiBase == "" {
+		apiBase = "https://api.github.com"
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/issues/%s", apiBase, repo, number)
+
+	var result struct {
+		Title   string `json:"title"`
+		State   string `json:"state"`
+		HTMLURL string `json:"html_url"`
+	}
+
+	token := is.integrationToken(models.IssueProviderGithub)
+	err := is.getJSON(apiURL, func(req *http.Request) {
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}, &result)
+	if err != nil {
+		ref.Error = err.Error()
+		return ref
+	}
+
+	ref.Title = result.Title
+	ref.Status = result.State
+	ref.URL = result.HTMLURL
+	ref.Resolved = true
+	return ref
+}
+
+// getJSON 发送GET请求并解析JSON响应
+func (is *IntegrationService) getJSON(apiURL string, prepare func(req *http.Request), out interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), integrationRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	prepare(req)
+
+	resp, err := is.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errors.New("issue not found")
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("access denied: %s", resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// extractIssueReferences 从文本中提取去重后的问题引用，保持出现顺序
+func extractIssueReferences(content string, jiraProjects []string) []string {
+	var allowed map[string]bool
+	if len(jiraProjects) > 0 {
+		allowed = make(map[string]bool, len(jiraProjects))
+		for _, project := range jiraProjects {
+			allowed[strings.ToUpper(strings.TrimSpace(project))] = true
+		}
+	}
+
+	type match struct {
+		pos int
+		key string
+	}
+	var matches []match
+
+	for _, m := range githubRefPattern.FindAllStringSubmatchIndex(content, -1) {
+		key := content[m[2]:m[3]] + "/" + content[m[4]:m[5]] + "#" + content[m[6]:m[7]]
+		matches = append(matches, match{pos: m[2], key: key})
+	}
+
+	for _, m := range jiraKeyPattern.FindAllStringSubmatchIndex(content, -1) {
+		project := content[m[2]:m[3]]
+		if allowed != nil {
+			if !allowed[project] {
+				continue
+			}
+		} else if jiraKeyStopList[project] {
+			continue
+		}
+		matches = append(matches, match{pos: m[0], key: content[m[0]:m[1]]})
+	}
+
+	// 按出现位置排序
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].pos < matches[j].pos
+	})
+
+	seen := make(map[string]bool, len(matches))
+	keys := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if seen[m.key] {
+			continue
+		}
+		seen[m.key] = true
+		keys = append(keys, m.key)
+	}
+	return keys
+}
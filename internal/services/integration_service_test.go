@@ -0,0 +1,52 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestExtractIssueReferences 测试问题引用识别
+func TestExtractIssueReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		projects []string
+		expected []string
+	}{
+		{
+			name:     "jira and github references in order",
+			content:  "fix ABC-123 see landaiqing/voidraft#42 and ABC-123 again",
+			expected: []string{"ABC-123", "landaiqing/voidraft#42"},
+		},
+		{
+			name:     "stop list filters encodings",
+			content:  "file is UTF-8, hash SHA-256, ticket OPS-7",
+			expected: []string{"OPS-7"},
+		},
+		{
+			name:     "project allow list",
+			content:  "OPS-7 DEV-12 UTF-8",
+			projects: []string{"dev"},
+			expected: []string{"DEV-12"},
+		},
+		{
+			name:     "urls are not github references",
+			content:  "https://github.com/org/repo#readme and path/to/file#L10",
+			expected: []string{},
+		},
+		{
+			name:     "lowercase keys ignored",
+			content:  "abc-123",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractIssueReferences(tt.content, tt.projects)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("extractIssueReferences() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
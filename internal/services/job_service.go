@@ -0,0 +1,464 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// SQL 查询语句
+const (
+	sqlUpsertJob = `
+INSERT OR REPLACE INTO jobs (id, kind, title, status, progress, message, error, result, created_at, started_at, finished_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	sqlListJobs = `
+SELECT id, kind, title, status, progress, message, error, result, created_at, started_at, finished_at
+FROM jobs
+ORDER BY id DESC
+LIMIT ?`
+
+	sqlMaxJobID = `
+SELECT COALESCE(MAX(id), 0) FROM jobs`
+
+	sqlInterruptJobs = `
+UPDATE jobs SET status = 'failed', error = 'interrupted by application exit', finished_at = ?
+WHERE status IN ('queued', 'running')`
+
+	sqlClearJobHistory = `
+DELETE FROM jobs WHERE status IN ('succeeded', 'failed', 'canceled')`
+
+	sqlPruneJobHistory = `
+DELETE FROM jobs WHERE id <= (SELECT id FROM jobs ORDER BY id DESC LIMIT 1 OFFSET ?)`
+)
+
+const (
+	// jobWorkers 并发执行的任务数
+	jobWorkers = 2
+	// jobQueueSize 排队任务数上限
+	jobQueueSize = 64
+	// jobHistoryLimit 保留的任务历史条数
+	jobHistoryLimit = 200
+	// jobProgressInterval 进度事件的最小间隔，避免频繁推送
+	jobProgressInterval = 200 * time.Millisecond
+	// jobTimeLayout 任务时间格式
+	jobTimeLayout = "2006-01-02 15:04:05"
+)
+
+// ErrJobQueueFull 任务队列已满
+var ErrJobQueueFull = errors.New("too many jobs queued, try again later")
+
+// jobFunc 任务执行函数，应定期检查 ctx 以支持取消，返回值序列化为任务结果
+type jobFunc func(ctx context.Context, progress jobProgress) (any, error)
+
+// jobProgress 上报任务进度，total 为0时只更新说明
+type jobProgress func(done, total int, message string)
+
+// bytes 按字节上报进度，progress 为 nil 时不做任何事
+// 进度按字节计算，换算为KB避免 int 溢出
+func (p jobProgress) bytes(done, total int64) {
+	if p != nil {
+		p(int(done/1024), int(max(total/1024, 1)), "")
+	}
+}
+
+// jobFinishedListener 任务结束监听器
+type jobFinishedListener func(job models.Job)
+
+// jobUpdatedListener 任务状态或进度变化监听器，与推送给前端的事件同步调用，不应阻塞
+type jobUpdatedListener func(job models.Job)
+
+// jobEntry 排队或执行中的任务
+type jobEntry struct {
+	job      *models.Job
+	run      jobFunc
+	ctx      context.Context
+	cancel   context.CancelFunc
+	lastEmit time.Time
+}
+
+// JobService 后台任务服务，导入、导出、索引等耗时操作通过有界工作池执行，支持取消和进度推送
+type JobService struct {
+	logger          *log.LogService
+	databaseService *DatabaseService
+
+	mu     sync.Mutex
+	active map[int64]*jobEntry
+	nextID int64
+	queue  chan *jobEntry
+
+	// 任务结束监听器
+	listenersMu      sync.RWMutex
+	listeners        []jobFinishedListener
+	updatedListeners []jobUpdatedListener
+
+	// loadOnce 首次提交任务或启动时加载任务历史，其他服务可能在本服务启动前提交任务
+	loadOnce sync.Once
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewJobService 创建后台任务服务
+func NewJobService(databaseService *DatabaseService, logger *log.LogService) *JobService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &JobService{
+		logger:          logger,
+		databaseService: databaseService,
+		active:          make(map[int64]*jobEntry),
+		queue:           make(chan *jobEntry, jobQueueSize),
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+}
+
+// ServiceStartup 服务启动，启动工作池
+func (js *JobService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	js.loadOnce.Do(js.loadHistory)
+
+	for i := 0; i < jobWorkers; i++ {
+		js.wg.Add(1)
+		go js.worker()
+	}
+	return nil
+}
+
+// loadHistory 将上次退出时未完成的任务标记为中断，并确定下一个任务ID
+func (js *JobService) loadHistory() {
+	if js.databaseService == nil || js.databaseService.getDB() == nil || js.databaseService.ensureWritable() != nil {
+		return
+	}
+
+	if _, err := js.databaseService.getDB().Exec(sqlInterruptJobs, time.Now().Format(jobTimeLayout)); err != nil {
+		js.logger.Error("Failed to mark interrupted jobs", "error", err)
+	}
+
+	var maxID int64
+	if err := js.databaseService.getDB().QueryRow(sqlMaxJobID).Scan(&maxID); err != nil {
+		js.logger.Error("Failed to load job history", "error", err)
+		return
+	}
+	js.mu.Lock()
+	js.nextID = max(js.nextID, maxID)
+	js.mu.Unlock()
+}
+
+// submit 提交任务到队列，返回任务快照
+func (js *JobService) submit(kind models.JobKind, title string, run jobFunc) (*models.Job, error) {
+	js.loadOnce.Do(js.loadHistory)
+
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	// 只有 submit 写入队列且持有锁，检查容量后写入不会阻塞
+	if len(js.queue) == cap(js.queue) {
+		return nil, ErrJobQueueFull
+	}
+
+	js.nextID++
+	job := &models.Job{
+		ID:        js.nextID,
+		Kind:      kind,
+		Title:     title,
+		Status:    models.JobQueued,
+		CreatedAt: time.Now().Format(jobTimeLayout),
+	}
+	ctx, cancel := context.WithCancel(js.ctx)
+	entry := &jobEntry{job: job, run: run, ctx: ctx, cancel: cancel}
+	snapshot := *job
+
+	// 先落盘再入队，避免排队状态覆盖工作协程写入的执行状态
+	js.persist(&snapshot)
+	js.active[job.ID] = entry
+	js.queue <- entry
+
+	helper.EmitEvent(constant.EVENT_JOB_UPDATED, &snapshot)
+	js.notifyJobUpdated(snapshot)
+	return &snapshot, nil
+}
+
+// worker 从队列中取出任务执行
+func (js *JobService) worker() {
+	defer js.wg.Done()
+
+	for {
+		select {
+		case <-js.ctx.Done():
+			return
+		case entry := <-js.queue:
+			js.execute(entry)
+		}
+	}
+}
+
+// execute 执行单个任务并记录结果
+func (js *JobService) execute(entry *jobEntry) {
+	// 排队期间已取消
+	if entry.ctx.Err() != nil {
+		return
+	}
+
+	js.update(entry, true, func(job *models.Job) {
+		job.Status = models.JobRunning
+		job.StartedAt = time.Now().Format(jobTimeLayout)
+	})
+
+	result, err := js.run(entry)
+
+	js.update(entry, true, func(job *models.Job) {
+		job.FinishedAt = time.Now().Format(jobTimeLayout)
+		switch {
+		case entry.ctx.Err() != nil:
+			job.Status = models.JobCanceled
+		case err != nil:
+			job.Status = models.JobFailed
+			job.Error = err.Error()
+		default:
+			job.Status = models.JobSucceeded
+			job.Progress = 1
+			if result != nil {
+				if data, err := json.Marshal(result); err == nil {
+					job.Result = string(data)
+				}
+			}
+		}
+	})
+	entry.cancel()
+
+	js.mu.Lock()
+	delete(js.active, entry.job.ID)
+	finished := *entry.job
+	js.mu.Unlock()
+	js.notifyJobFinished(finished)
+
+	if err != nil && entry.ctx.Err() == nil {
+		js.logger.Error("Job failed", "id", entry.job.ID, "kind", entry.job.Kind, "error", err)
+	}
+	js.pruneHistory()
+}
+
+// onJobFinished 注册任务结束监听器，供其他后端服务订阅
+func (js *JobService) onJobFinished(listener jobFinishedListener) {
+	js.listenersMu.Lock()
+	defer js.listenersMu.Unlock()
+	js.listeners = append(js.listeners, listener)
+}
+
+// notifyJobFinished 通知所有监听器，监听器异步执行，不占用工作池
+func (js *JobService) notifyJobFinished(job models.Job) {
+	js.listenersMu.RLock()
+	listeners := make([]jobFinishedListener, len(js.listeners))
+	copy(listeners, js.listeners)
+	js.listenersMu.RUnlock()
+
+	for _, listener := range listeners {
+		go listener(job)
+	}
+}
+
+// onJobUpdated 注册任务状态或进度变化监听器
+func (js *JobService) onJobUpdated(listener jobUpdatedListener) {
+	js.listenersMu.Lock()
+	defer js.listenersMu.Unlock()
+	js.updatedListeners = append(js.updatedListeners, listener)
+}
+
+// notifyJobUpdated 按变化顺序同步通知监听器，保证结束状态不会被之前的进度覆盖
+func (js *JobService) notifyJobUpdated(job models.Job) {
+	js.listenersMu.RLock()
+	listeners := make([]jobUpdatedListener, len(js.updatedListeners))
+	copy(listeners, js.updatedListeners)
+	js.listenersMu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(job)
+	}
+}
+
+// run 执行任务函数，捕获 panic 避免拖垮工作池
+func (js *JobService) run(entry *jobEntry) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	progress := func(done, total int, message string) {
+		js.update(entry, false, func(job *models.Job) {
+			if total > 0 {
+				job.Progress = min(float64(done)/float64(total), 1)
+			}
+			if message != "" {
+				job.Message = message
+			}
+		})
+	}
+	return entry.run(entry.ctx, progress)
+}
+
+// update 修改任务状态并推送事件；进度更新按间隔节流且不落盘
+func (js *JobService) update(entry *jobEntry, persist bool, mutate func(job *models.Job)) {
+	js.mu.Lock()
+	mutate(entry.job)
+	now := time.Now()
+	emit := persist || now.Sub(entry.lastEmit) >= jobProgressInterval
+	if emit {
+		entry.lastEmit = now
+	}
+	snapshot := *entry.job
+	js.mu.Unlock()
+
+	if persist {
+		js.persist(&snapshot)
+	}
+	if emit {
+		helper.EmitEvent(constant.EVENT_JOB_UPDATED, &snapshot)
+		js.notifyJobUpdated(snapshot)
+	}
+}
+
+// persist 保存任务到历史，只读实例只保留在内存中
+func (js *JobService) persist(job *models.Job) {
+	if js.databaseService == nil || js.databaseService.getDB() == nil || js.databaseService.ensureWritable() != nil {
+		return
+	}
+	_, err := js.databaseService.getDB().Exec(sqlUpsertJob,
+		job.ID, job.Kind, job.Title, job.Status, job.Progress, job.Message, job.Error, job.Result,
+		job.CreatedAt, job.StartedAt, job.FinishedAt)
+	if err != nil {
+		js.logger.Error("Failed to save job", "id", job.ID, "error", err)
+	}
+}
+
+// pruneHistory 只保留最近的任务历史
+func (js *JobService) pruneHistory() {
+	if js.databaseService == nil || js.databaseService.getDB() == nil || js.databaseService.ensureWritable() != nil {
+		return
+	}
+	if _, err := js.databaseService.getDB().Exec(sqlPruneJobHistory, jobHistoryLimit); err != nil {
+		js.logger.Error("Failed to prune job history", "error", err)
+	}
+}
+
+// ListJobs 列出最近的任务，包括排队和执行中的任务
+func (js *JobService) ListJobs(limit int) ([]*models.Job, error) {
+	if limit <= 0 || limit > jobHistoryLimit {
+		limit = jobHistoryLimit
+	}
+
+	jobs := []*models.Job{}
+	seen := make(map[int64]bool)
+
+	// 内存中的任务状态最新，优先使用
+	js.mu.Lock()
+	for id, entry := range js.active {
+		snapshot := *entry.job
+		jobs = append(jobs, &snapshot)
+		seen[id] = true
+	}
+	js.mu.Unlock()
+
+	if js.databaseService != nil && js.databaseService.getDB() != nil {
+		rows, err := js.databaseService.getDB().Query(sqlListJobs, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list jobs: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			job := &models.Job{}
+			if err := rows.Scan(&job.ID, &job.Kind, &job.Title, &job.Status, &job.Progress, &job.Message,
+				&job.Error, &job.Result, &job.CreatedAt, &job.StartedAt, &job.FinishedAt); err != nil {
+				return nil, fmt.Errorf("failed to scan job: %w", err)
+			}
+			if !seen[job.ID] {
+				jobs = append(jobs, job)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error iterating jobs: %w", err)
+		}
+	}
+
+	// 按ID倒序，最新的在前
+	slices.SortFunc(jobs, func(a, b *models.Job) int {
+		return cmp.Compare(b.ID, a.ID)
+	})
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+// GetJob 获取排队或执行中的任务，已结束的任务从 ListJobs 获取
+func (js *JobService) GetJob(id int64) (*models.Job, error) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	entry, ok := js.active[id]
+	if !ok {
+		return nil, fmt.Errorf("job %d is not active", id)
+	}
+	snapshot := *entry.job
+	return &snapshot, nil
+}
+
+// CancelJob 取消任务，排队中的任务立即结束，执行中的任务在下一次检查时停止
+func (js *JobService) CancelJob(id int64) error {
+	js.mu.Lock()
+	entry, ok := js.active[id]
+	if !ok {
+		js.mu.Unlock()
+		return fmt.Errorf("job %d is not active", id)
+	}
+	queued := entry.job.Status == models.JobQueued
+	if queued {
+		delete(js.active, id)
+	}
+	js.mu.Unlock()
+
+	entry.cancel()
+	if queued {
+		js.update(entry, true, func(job *models.Job) {
+			job.Status = models.JobCanceled
+			job.FinishedAt = time.Now().Format(jobTimeLayout)
+		})
+	}
+	return nil
+}
+
+// ClearJobHistory 清除已结束的任务历史
+func (js *JobService) ClearJobHistory() error {
+	if js.databaseService == nil || js.databaseService.getDB() == nil {
+		return errors.New("database service not available")
+	}
+	if err := js.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+	if _, err := js.databaseService.getDB().Exec(sqlClearJobHistory); err != nil {
+		return fmt.Errorf("failed to clear job history: %w", err)
+	}
+	return nil
+}
+
+// ServiceShutdown 服务关闭，取消所有任务并等待工作池退出
+func (js *JobService) ServiceShutdown() error {
+	js.cancel()
+	js.wg.Wait()
+	return nil
+}
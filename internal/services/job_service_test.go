@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// waitJob 等待任务结束
+func waitJob(t *testing.T, js *JobService, id int64) *models.Job {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		jobs, err := js.ListJobs(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, job := range jobs {
+			if job.ID == id && job.Status.IsFinished() {
+				return job
+			}
+		}
+		if _, err := js.GetJob(id); err != nil {
+			// 无数据库时结束的任务不保留历史
+			return nil
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %d did not finish", id)
+	return nil
+}
+
+func TestJobServiceRunsJobs(t *testing.T) {
+	js := NewJobService(nil, nil)
+	js.ServiceStartup(context.Background(), application.ServiceOptions{})
+	defer js.ServiceShutdown()
+
+	done := make(chan int, 1)
+	job, err := js.submit(models.JobKindIndex, "test", func(ctx context.Context, progress jobProgress) (any, error) {
+		progress(1, 2, "half")
+		done <- 42
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != models.JobQueued {
+		t.Errorf("submitted job status = %s", job.Status)
+	}
+	if v := <-done; v != 42 {
+		t.Fatal("job did not run")
+	}
+	waitJob(t, js, job.ID)
+}
+
+func TestJobServiceCancel(t *testing.T) {
+	js := NewJobService(nil, nil)
+	js.ServiceStartup(context.Background(), application.ServiceOptions{})
+	defer js.ServiceShutdown()
+
+	started := make(chan struct{})
+	canceled := make(chan error, 1)
+	job, err := js.submit(models.JobKindExport, "slow", func(ctx context.Context, progress jobProgress) (any, error) {
+		close(started)
+		<-ctx.Done()
+		canceled <- ctx.Err()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	if err := js.CancelJob(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-canceled; !errors.Is(err, context.Canceled) {
+		t.Errorf("job context error = %v", err)
+	}
+	waitJob(t, js, job.ID)
+	if err := js.CancelJob(job.ID); err == nil {
+		t.Error("canceling a finished job should fail")
+	}
+}
+
+func TestJobServiceQueueFull(t *testing.T) {
+	// 不启动工作池，任务只会排队
+	js := NewJobService(nil, nil)
+	noop := func(ctx context.Context, progress jobProgress) (any, error) { return nil, nil }
+	for i := 0; i < jobQueueSize; i++ {
+		if _, err := js.submit(models.JobKindIndex, "noop", noop); err != nil {
+			t.Fatalf("submit %d: %v", i, err)
+		}
+	}
+	if _, err := js.submit(models.JobKindIndex, "noop", noop); !errors.Is(err, ErrJobQueueFull) {
+		t.Errorf("submit on full queue error = %v", err)
+	}
+}
+
+func TestJobProgressBytes(t *testing.T) {
+	var done, total int
+	progress := jobProgress(func(d, tot int, message string) { done, total = d, tot })
+	progress.bytes(3<<40, 6<<40)
+	if done != 3<<30 || total != 6<<30 {
+		t.Errorf("progress = %d/%d, want %d/%d", done, total, 3<<30, 6<<30)
+	}
+	// 小于 1KB 的文件总量按 1 计算
+	progress.bytes(100, 500)
+	if done != 0 || total != 1 {
+		t.Errorf("progress = %d/%d, want 0/1", done, total)
+	}
+
+	var none jobProgress
+	none.bytes(1, 2)
+}
@@ -105,13 +105,17 @@ func (kbs *KeyBindingService) initDatabase() error {
 	kbs.mu.Lock()
 	defer kbs.mu.Unlock()
 
-	if kbs.databaseService == nil || kbs.databaseService.db == nil {
+	if kbs.databaseService == nil || kbs.databaseService.getDB() == nil {
 		return &KeyBindingError{"check_db", "", errors.New("database service not available")}
 	}
+	// 只读时不写入默认配置，由持有锁的实例负责
+	if kbs.databaseService.ensureWritable() != nil {
+		return nil
+	}
 
 	// 检查是否已有快捷键数据
 	var count int64
-	err := kbs.databaseService.db.QueryRow("SELECT COUNT(*) FROM key_bindings").Scan(&count)
+	err := kbs.databaseService.getDB().QueryRow("SELECT COUNT(*) FROM key_bindings").Scan(&count)
 	if err != nil {
 		return &KeyBindingError{"check_keybindings_count", "", err}
 	}
@@ -133,7 +137,7 @@ func (kbs *KeyBindingService) insertDefaultKeyBindings() error {
 	now := time.Now().Format("2006-01-02 15:04:05")
 
 	for _, kb := range defaultConfig.KeyBindings {
-		_, err := kbs.databaseService.db.Exec(sqlInsertKeyBinding,
+		_, err := kbs.databaseService.getDB().Exec(sqlInsertKeyBinding,
 			string(kb.Command),   // 转换为字符串存储
 			string(kb.Extension), // 转换为字符串存储
 			kb.Key,
@@ -155,11 +159,11 @@ func (kbs *KeyBindingService) GetAllKeyBindings() ([]models.KeyBinding, error) {
 	kbs.mu.RLock()
 	defer kbs.mu.RUnlock()
 
-	if kbs.databaseService == nil || kbs.databaseService.db == nil {
+	if kbs.databaseService == nil || kbs.databaseService.getDB() == nil {
 		return nil, &KeyBindingError{"query_db", "", errors.New("database service not available")}
 	}
 
-	rows, err := kbs.databaseService.db.Query(sqlGetAllKeyBindings)
+	rows, err := kbs.databaseService.getDB().Query(sqlGetAllKeyBindings)
 	if err != nil {
 		return nil, &KeyBindingError{"query_keybindings", "", err}
 	}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// LocalServerStatus 本地HTTP服务状态
+type LocalServerStatus struct {
+	Running   bool     `json:"running"`   // 是否正在运行
+	Address   string   `json:"address"`   // 监听地址
+	Port      int      `json:"port"`      // 监听端口
+	BaseURLs  []string `json:"baseURLs"`  // 可访问的基础地址
+	LastError string   `json:"lastError"` // 最近一次启动错误
+}
+
+// LocalServerService 本地HTTP服务，承载分享链接和本地API
+type LocalServerService struct {
+	logger        *log.LogService
+	configService *ConfigService
+
+	mux       *http.ServeMux
+	mu        sync.RWMutex
+	server    *http.Server
+	listener  net.Listener
+	lastError error
+
+	cancelObservers []CancelFunc
+}
+
+// NewLocalServerService 创建本地HTTP服务
+func NewLocalServerService(configService *ConfigService, logger *log.LogService) *LocalServerService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	return &LocalServerService{
+		logger:        logger,
+		configService: configService,
+		mux:           http.NewServeMux(),
+	}
+}
+
+// ServiceStartup 服务启动
+func (ls *LocalServerService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	ls.cancelObservers = []CancelFunc{
+		ls.configService.Watch("server.enabled", ls.onServerConfigChange),
+		ls.configService.Watch("server.port", ls.onServerConfigChange),
+		ls.configService.Watch("server.allowLAN", ls.onServerConfigChange),
+	}
+
+	// 启动失败不影响应用运行，错误通过状态暴露给前端
+	if err := ls.RestartServer(); err != nil {
+		ls.logger.Error("Failed to start local server", "error", err)
+	}
+	return nil
+}
+
+// handle 注册路由，供其他服务在构造时调用
+func (ls *LocalServerService) handle(pattern string, handler http.HandlerFunc) {
+	ls.mux.HandleFunc(pattern, handler)
+}
+
+// RestartServer 按当前配置重启服务，未启用时仅停止
+func (ls *LocalServerService) RestartServer() error {
+	ls.stop()
+
+	config, err := ls.configService.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+	if !config.Server.Enabled {
+		return nil
+	}
+
+	host := "127.0.0.1"
+	if config.Server.AllowLAN {
+		host = "0.0.0.0"
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(config.Server.Port))
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		ls.mu.Lock()
+		ls.lastError = err
+		ls.mu.Unlock()
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	server := &http.Server{
+		Handler:           ls.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      60 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	ls.mu.Lock()
+	ls.server = server
+	ls.listener = listener
+	ls.lastError = nil
+	ls.mu.Unlock()
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			ls.logger.Error("Local server stopped unexpectedly", "error", err)
+			ls.mu.Lock()
+			ls.lastError = err
+			ls.mu.Unlock()
+		}
+	}()
+
+	ls.logger.Info("Local server started", "address", listener.Addr().String())
+	return nil
+}
+
+// GetServerStatus 获取服务状态
+func (ls *LocalServerService) GetServerStatus() *LocalServerStatus {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+
+	status := &LocalServerStatus{}
+	if ls.lastError != nil {
+		status.LastError = ls.lastError.Error()
+	}
+	if ls.listener == nil {
+		return status
+	}
+
+	tcpAddr, _ := ls.listener.Addr().(*net.TCPAddr)
+	status.Running = true
+	status.Address = ls.listener.Addr().String()
+	if tcpAddr != nil {
+		status.Port = tcpAddr.Port
+		status.BaseURLs = baseURLsFor(tcpAddr)
+	}
+	return status
+}
+
+// baseURL 获取首选的访问地址，允许局域网访问时优先使用局域网IP
+func (ls *LocalServerService) baseURL() (string, error) {
+	status := ls.GetServerStatus()
+	if !status.Running || len(status.BaseURLs) == 0 {
+		return "", errors.New("local server is not running")
+	}
+	return status.BaseURLs[0], nil
+}
+
+// onServerConfigChange 服务配置变更时重启
+func (ls *LocalServerService) onServerConfigChange(oldValue, newValue interface{}) {
+	if err := ls.RestartServer(); err != nil {
+		ls.logger.Error("Failed to restart local server", "error", err)
+	}
+}
+
+// stop 停止服务
+func (ls *LocalServerService) stop() {
+	ls.mu.Lock()
+	server := ls.server
+	ls.server = nil
+	ls.listener = nil
+	ls.mu.Unlock()
+
+	if server == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		ls.logger.Error("Failed to shutdown local server", "error", err)
+	}
+}
+
+// baseURLsFor 根据监听地址生成可访问的基础地址列表
+func baseURLsFor(addr *net.TCPAddr) []string {
+	port := strconv.Itoa(addr.Port)
+	if !addr.IP.IsUnspecified() {
+		return []string{"http://" + net.JoinHostPort(addr.IP.String(), port)}
+	}
+
+	var urls []string
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, a := range addrs {
+			ipNet, ok := a.(*net.IPNet)
+			if !ok || ipNet.IP.IsLoopback() || ipNet.IP.To4() == nil {
+				continue
+			}
+			urls = append(urls, "http://"+net.JoinHostPort(ipNet.IP.String(), port))
+		}
+	}
+	return append(urls, "http://"+net.JoinHostPort("127.0.0.1", port))
+}
+
+// ServiceShutdown 服务关闭
+func (ls *LocalServerService) ServiceShutdown() error {
+	for _, cancel := range ls.cancelObservers {
+		if cancel != nil {
+			cancel()
+		}
+	}
+	ls.stop()
+	return nil
+}
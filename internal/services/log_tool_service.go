@@ -0,0 +1,116 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/logtool"
+	"voidraft/internal/common/timeparse"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// LogToolService 日志整理服务，在后端处理大段日志，结果写入新块，避免前端处理大文本
+type LogToolService struct {
+	logger          *log.LogService
+	documentService *DocumentService
+}
+
+// NewLogToolService 创建日志整理服务实例
+func NewLogToolService(documentService *DocumentService, logger *log.LogService) *LogToolService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &LogToolService{
+		logger:          logger,
+		documentService: documentService,
+	}
+}
+
+// ProcessLogBlock 整理文档中指定块的日志：统一时间戳时区，按级别、正则和时间范围过滤，结果插入到该块之后
+func (ls *LogToolService) ProcessLogBlock(documentID int64, blockIndex int, options models.LogFilterOptions) (*models.LogFilterResult, error) {
+	opts, err := logToolOptions(options, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	doc, err := ls.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil || doc.IsDeleted {
+		return nil, fmt.Errorf("document not found: %d", documentID)
+	}
+	list := blocks.Parse(doc.Content)
+	if blockIndex < 0 || blockIndex >= len(list) {
+		return nil, fmt.Errorf("block not found: %d", blockIndex)
+	}
+	block := list[blockIndex]
+
+	var out strings.Builder
+	stats, err := logtool.Process(strings.NewReader(block.Content), &out, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process log: %w", err)
+	}
+	result := &models.LogFilterResult{
+		Records:    stats.Records,
+		Kept:       stats.Kept,
+		Lines:      stats.Lines,
+		Normalized: stats.Normalized,
+		BlockIndex: -1,
+	}
+	if stats.Kept == 0 {
+		return result, nil
+	}
+
+	content := doc.Content[:block.End] + blocks.Delimiter(block.Language, false) + strings.TrimSuffix(out.String(), "\n") + doc.Content[block.End:]
+	if err := ls.documentService.UpdateDocumentContent(documentID, content); err != nil {
+		return nil, err
+	}
+	result.BlockIndex = blockIndex + 1
+	return result, nil
+}
+
+// logToolOptions 校验并转换整理选项
+func logToolOptions(options models.LogFilterOptions, now time.Time) (logtool.Options, error) {
+	var opts logtool.Options
+	var err error
+	if opts.SourceZone, err = timeparse.LoadZone(options.SourceZone); err != nil {
+		return opts, err
+	}
+	if options.TargetZone != "" {
+		if opts.TargetZone, err = timeparse.LoadZone(options.TargetZone); err != nil {
+			return opts, err
+		}
+	}
+	for _, name := range options.Levels {
+		level := logtool.NormalizeLevel(name)
+		if level == "" {
+			return opts, fmt.Errorf("unknown log level: %s", name)
+		}
+		opts.Levels = append(opts.Levels, level)
+	}
+	if options.Include != "" {
+		if opts.Include, err = regexp.Compile(options.Include); err != nil {
+			return opts, fmt.Errorf("invalid include pattern: %w", err)
+		}
+	}
+	if options.Exclude != "" {
+		if opts.Exclude, err = regexp.Compile(options.Exclude); err != nil {
+			return opts, fmt.Errorf("invalid exclude pattern: %w", err)
+		}
+	}
+	if options.Since != "" {
+		if opts.Since, err = timeparse.Parse(options.Since, now, opts.SourceZone); err != nil {
+			return opts, fmt.Errorf("invalid since: %w", err)
+		}
+	}
+	if options.Until != "" {
+		if opts.Until, err = timeparse.Parse(options.Until, now, opts.SourceZone); err != nil {
+			return opts, fmt.Errorf("invalid until: %w", err)
+		}
+	}
+	return opts, nil
+}
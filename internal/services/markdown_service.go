@@ -0,0 +1,59 @@
+package services
+
+import (
+	"fmt"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/markdown"
+	"voidraft/internal/common/richtext"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// MarkdownService Markdown 渲染服务
+// 应用内预览与 HTML/打印导出、分享页面使用同一套渲染和清理规则
+type MarkdownService struct {
+	logger          *log.LogService
+	documentService *DocumentService
+}
+
+// NewMarkdownService 创建 Markdown 渲染服务实例
+func NewMarkdownService(documentService *DocumentService, logger *log.LogService) *MarkdownService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &MarkdownService{
+		logger:          logger,
+		documentService: documentService,
+	}
+}
+
+// RenderMarkdown 渲染 Markdown 文本，style 为代码高亮样式，如 github、github-dark
+func (ms *MarkdownService) RenderMarkdown(text string, style string) (*markdown.Result, error) {
+	return markdown.Render(text, style)
+}
+
+// RenderDocumentMarkdown 渲染文档的全部块，用于预览窗格
+func (ms *MarkdownService) RenderDocumentMarkdown(documentID int64, style string) (*markdown.Result, error) {
+	doc, err := ms.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("document not found: %d", documentID)
+	}
+	return markdown.RenderBlocks(blocks.Parse(doc.Content), style)
+}
+
+// ConvertRichText 将粘贴的富文本转换为 Markdown，同时提供时优先使用 HTML
+// 转换结果为空时返回空字符串，由前端改为粘贴纯文本
+func (ms *MarkdownService) ConvertRichText(html string, rtf string) string {
+	if html != "" {
+		if text := richtext.HTMLToMarkdown(html); text != "" {
+			return text
+		}
+	}
+	if rtf != "" {
+		return richtext.RTFToMarkdown(rtf)
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"crypto/x509"
+	"voidraft/internal/common/netclient"
+	"voidraft/internal/models"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// NetworkService 对外网络请求设置
+// 按设置配置所有对外 HTTP 请求共用的代理和证书校验，设置变化时立即生效；记录各服务的请求日志和统计
+type NetworkService struct {
+	logger        *log.LogService
+	configService *ConfigService
+
+	cancelObserver CancelFunc
+}
+
+// NewNetworkService 创建网络设置服务实例
+func NewNetworkService(configService *ConfigService, logger *log.LogService) *NetworkService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &NetworkService{
+		logger:        logger,
+		configService: configService,
+	}
+}
+
+// ServiceStartup 应用网络设置，备份推送也改用共用的传输层
+func (ns *NetworkService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	netclient.SetObserver(ns.logRequest)
+	gitClient := githttp.NewClient(netclient.New(netclient.Config{Service: "backup"}))
+	client.InstallProtocol("https", gitClient)
+	client.InstallProtocol("http", gitClient)
+
+	ns.apply()
+	ns.cancelObserver = ns.configService.Watch("network", func(oldValue, newValue interface{}) {
+		ns.apply()
+	})
+	return nil
+}
+
+// apply 按当前设置重建传输层，设置有误时保留之前的设置
+func (ns *NetworkService) apply() {
+	config, err := ns.configService.GetConfig()
+	if err != nil {
+		ns.logger.Error("Failed to get network config", "error", err)
+		return
+	}
+	if err := netclient.Configure(networkOptions(config.Network)); err != nil {
+		ns.logger.Error("Failed to apply network config", "error", err)
+	}
+}
+
+// logRequest 记录对外请求，只记录主机不记录路径；失败的请求记为警告
+func (ns *NetworkService) logRequest(event netclient.Event) {
+	args := []any{
+		"service", event.Service,
+		"method", event.Method,
+		"host", event.Host,
+		"status", event.Status,
+		"duration", event.Duration,
+		"retries", event.Retries,
+	}
+	if event.Failed() {
+		if event.Err != nil {
+			args = append(args, "error", event.Err)
+		}
+		ns.logger.Warning("Outbound request failed", args...)
+		return
+	}
+	ns.logger.Debug("Outbound request", args...)
+}
+
+// GetNetworkStats 获取各服务访问各目标主机的请求统计
+func (ns *NetworkService) GetNetworkStats() []netclient.Stat {
+	return netclient.Stats()
+}
+
+// ResetNetworkStats 清空请求统计
+func (ns *NetworkService) ResetNetworkStats() {
+	netclient.ResetStats()
+}
+
+// CheckCABundle 检查证书文件，返回其中的证书数
+func (ns *NetworkService) CheckCABundle(path string) (int, error) {
+	return netclient.LoadCABundle(x509.NewCertPool(), path)
+}
+
+// ServiceShutdown 取消配置观察
+func (ns *NetworkService) ServiceShutdown() error {
+	netclient.SetObserver(nil)
+	if ns.cancelObserver != nil {
+		ns.cancelObserver()
+	}
+	return nil
+}
+
+// networkOptions 把网络设置转换为传输层设置
+func networkOptions(config models.NetworkConfig) netclient.Options {
+	options := netclient.Options{Proxy: config.Proxy, CABundles: config.CABundles}
+	for _, pin := range config.CertificatePins {
+		options.Pins = append(options.Pins, netclient.Pin{Host: pin.Host, SHA256: pin.SHA256})
+	}
+	return options
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+	"github.com/wailsapp/wails/v3/pkg/services/notifications"
+)
+
+const (
+	// doNotDisturbPollInterval 有暂缓的通知时检测勿扰模式是否结束的间隔
+	doNotDisturbPollInterval = 30 * time.Second
+	// maxQueuedNotifications 暂缓通知的数量上限，超出时丢弃最早的通知
+	maxQueuedNotifications = 20
+)
+
+// queuedNotification 勿扰期间暂缓的通知
+type queuedNotification struct {
+	category models.NotificationCategory
+	options  notifications.NotificationOptions
+}
+
+// NotificationCenterService 通知中心
+// 后端服务统一通过它发送系统通知，系统处于勿扰/专注模式时暂缓非紧急通知，勿扰结束后再依次发送
+type NotificationCenterService struct {
+	logger              *log.LogService
+	configService       *ConfigService
+	notificationService *notifications.NotificationService
+	doNotDisturb        func() bool // 勿扰模式检测
+
+	mu     sync.Mutex
+	queue  []queuedNotification
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewNotificationCenterService 创建通知中心实例
+func NewNotificationCenterService(configService *ConfigService, notificationService *notifications.NotificationService, logger *log.LogService) *NotificationCenterService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &NotificationCenterService{
+		logger:              logger,
+		configService:       configService,
+		notificationService: notificationService,
+		doNotDisturb:        detectDoNotDisturb,
+	}
+}
+
+// ServiceStartup 启动勿扰模式检测
+func (nc *NotificationCenterService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	pollCtx, cancel := context.WithCancel(context.Background())
+	nc.cancel = cancel
+	nc.wg.Add(1)
+	go func() {
+		defer nc.wg.Done()
+		ticker := time.NewTicker(doNotDisturbPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-pollCtx.Done():
+				return
+			case <-ticker.C:
+				nc.flush()
+			}
+		}
+	}()
+	return nil
+}
+
+// GetNotificationStatus 获取勿扰模式检测结果和暂缓的通知数量
+func (nc *NotificationCenterService) GetNotificationStatus() models.NotificationStatus {
+	nc.mu.Lock()
+	queued := len(nc.queue)
+	nc.mu.Unlock()
+	return models.NotificationStatus{DoNotDisturb: nc.doNotDisturb(), Queued: queued}
+}
+
+// notify 发送通知，勿扰模式下未放行的分类暂缓发送；相同 ID 的暂缓通知只保留最新一条
+func (nc *NotificationCenterService) notify(category models.NotificationCategory, options notifications.NotificationOptions) {
+	if nc == nil || nc.notificationService == nil {
+		return
+	}
+	if nc.shouldHold(category) {
+		nc.hold(category, options)
+		return
+	}
+	nc.send(options)
+}
+
+// hold 暂缓通知，超出上限时丢弃最早的通知
+func (nc *NotificationCenterService) hold(category models.NotificationCategory, options notifications.NotificationOptions) {
+	nc.mu.Lock()
+	nc.queue = slices.DeleteFunc(nc.queue, func(q queuedNotification) bool { return q.options.ID == options.ID })
+	nc.queue = append(nc.queue, queuedNotification{category: category, options: options})
+	if len(nc.queue) > maxQueuedNotifications {
+		nc.queue = nc.queue[len(nc.queue)-maxQueuedNotifications:]
+	}
+	nc.mu.Unlock()
+	nc.logger.Debug("Notification held during do not disturb", "category", category, "id", options.ID)
+}
+
+// shouldHold 是否需要暂缓该分类的通知
+func (nc *NotificationCenterService) shouldHold(category models.NotificationCategory) bool {
+	config, err := nc.configService.GetConfig()
+	if err != nil || !config.Notifications.RespectDoNotDisturb {
+		return false
+	}
+	if slices.Contains(config.Notifications.BreakThrough, category) {
+		return false
+	}
+	return nc.doNotDisturb()
+}
+
+// flush 勿扰模式结束或关闭勿扰设置后发送暂缓的通知
+func (nc *NotificationCenterService) flush() {
+	nc.mu.Lock()
+	if len(nc.queue) == 0 {
+		nc.mu.Unlock()
+		return
+	}
+	nc.mu.Unlock()
+
+	config, err := nc.configService.GetConfig()
+	if err == nil && config.Notifications.RespectDoNotDisturb && nc.doNotDisturb() {
+		return
+	}
+
+	nc.mu.Lock()
+	queue := nc.queue
+	nc.queue = nil
+	nc.mu.Unlock()
+	for _, q := range queue {
+		nc.send(q.options)
+	}
+}
+
+// send 检查授权后发送通知，带分类的通知附带操作按钮
+func (nc *NotificationCenterService) send(options notifications.NotificationOptions) {
+	authorized, err := nc.notificationService.CheckNotificationAuthorization()
+	if err != nil || !authorized {
+		authorized, err = nc.notificationService.RequestNotificationAuthorization()
+		if err != nil || !authorized {
+			return
+		}
+	}
+
+	if options.CategoryID != "" {
+		err = nc.notificationService.SendNotificationWithActions(options)
+	} else {
+		err = nc.notificationService.SendNotification(options)
+	}
+	if err != nil {
+		nc.logger.Warning("Failed to send notification", "id", options.ID, "error", err)
+	}
+}
+
+// ServiceShutdown 停止检测，暂缓的通知丢弃
+func (nc *NotificationCenterService) ServiceShutdown() error {
+	if nc.cancel != nil {
+		nc.cancel()
+	}
+	nc.wg.Wait()
+	return nil
+}
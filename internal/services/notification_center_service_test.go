@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/services/notifications"
+)
+
+// newTestNotificationCenter 创建勿扰状态由 dnd 决定的通知中心
+func newTestNotificationCenter(t *testing.T, respect bool, dnd *bool, breakThrough ...models.NotificationCategory) *NotificationCenterService {
+	t.Helper()
+	config := models.NewDefaultAppConfig()
+	config.Notifications = models.NotificationConfig{RespectDoNotDisturb: respect, BreakThrough: breakThrough}
+	nc := NewNotificationCenterService(newTestConfigService(t, config), nil, nil)
+	nc.doNotDisturb = func() bool { return *dnd }
+	return nc
+}
+
+func TestNotificationShouldHold(t *testing.T) {
+	dnd := true
+	nc := newTestNotificationCenter(t, true, &dnd, models.NotificationCategoryBackupFailure)
+	if !nc.shouldHold(models.NotificationCategoryUpdate) {
+		t.Error("update notification should be held during do not disturb")
+	}
+	if nc.shouldHold(models.NotificationCategoryBackupFailure) {
+		t.Error("break-through category should not be held")
+	}
+
+	dnd = false
+	if nc.shouldHold(models.NotificationCategoryUpdate) {
+		t.Error("notification should not be held when do not disturb is off")
+	}
+
+	dnd = true
+	ignoring := newTestNotificationCenter(t, false, &dnd)
+	if ignoring.shouldHold(models.NotificationCategoryUpdate) {
+		t.Error("notification should not be held when respecting do not disturb is disabled")
+	}
+}
+
+// TestNotificationHoldQueue 相同 ID 只保留最新一条，超出上限丢弃最早的通知
+func TestNotificationHoldQueue(t *testing.T) {
+	dnd := true
+	nc := newTestNotificationCenter(t, true, &dnd)
+
+	nc.hold(models.NotificationCategoryUpdate, notifications.NotificationOptions{ID: "update", Body: "v1"})
+	nc.hold(models.NotificationCategoryWhatsNew, notifications.NotificationOptions{ID: "whats-new"})
+	nc.hold(models.NotificationCategoryUpdate, notifications.NotificationOptions{ID: "update", Body: "v2"})
+	if status := nc.GetNotificationStatus(); status.Queued != 2 || !status.DoNotDisturb {
+		t.Fatalf("status = %+v, want 2 queued during do not disturb", status)
+	}
+	if last := nc.queue[len(nc.queue)-1].options; last.ID != "update" || last.Body != "v2" {
+		t.Errorf("last queued = %+v, want the newest update", last)
+	}
+
+	for i := 0; i < maxQueuedNotifications; i++ {
+		nc.hold(models.NotificationCategoryUpdate, notifications.NotificationOptions{ID: fmt.Sprintf("n%d", i)})
+	}
+	if len(nc.queue) != maxQueuedNotifications || nc.queue[0].options.ID != "n0" {
+		t.Errorf("queue = %d items starting with %q, want %d starting with n0", len(nc.queue), nc.queue[0].options.ID, maxQueuedNotifications)
+	}
+
+	// 勿扰仍在进行时不发送
+	nc.flush()
+	if len(nc.queue) != maxQueuedNotifications {
+		t.Errorf("flush during do not disturb sent notifications, %d left", len(nc.queue))
+	}
+}
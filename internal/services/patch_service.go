@@ -0,0 +1,38 @@
+package services
+
+import (
+	"fmt"
+	"voidraft/internal/common/textdiff"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// PatchService 补丁服务，生成 git 风格的统一格式补丁，或把粘贴到笔记中的补丁应用到其他块
+type PatchService struct {
+	logger *log.LogService
+}
+
+// NewPatchService 创建补丁服务实例
+func NewPatchService(logger *log.LogService) *PatchService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &PatchService{
+		logger: logger,
+	}
+}
+
+// GeneratePatch 生成把 oldText 变为 newText 的补丁，文本相同时返回空
+func (ps *PatchService) GeneratePatch(oldText, newText string) string {
+	return textdiff.Unified("a/block", "b/block", oldText, newText, textdiff.DefaultContext)
+}
+
+// ApplyPatch 把补丁应用到 targetText 并返回结果；有任何一段无法应用时返回错误
+func (ps *PatchService) ApplyPatch(targetText, patchText string) (string, error) {
+	result, err := textdiff.Apply(targetText, patchText)
+	if err != nil {
+		ps.logger.Debug("Patch does not apply", "error", err)
+		return "", fmt.Errorf("failed to apply patch: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,440 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/textstats"
+	"voidraft/internal/models"
+	"voidraft/internal/version"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+const (
+	// pluginProtocolName 升级协议名称
+	pluginProtocolName = "voidraft-line"
+	// pluginProtocolVersion 协议版本
+	pluginProtocolVersion = 1
+	// pluginIdleTimeout 连接空闲超时
+	pluginIdleTimeout = 10 * time.Minute
+	// pluginSearchLimit 搜索结果默认数量
+	pluginSearchLimit = 20
+)
+
+// 插件能力
+const (
+	pluginCapPush   = "push"   // 推送选中内容到文档
+	pluginCapSearch = "search" // 模糊搜索片段
+	pluginCapGet    = "get"    // 读取片段全文
+)
+
+// pluginCapabilities 服务端支持的能力
+var pluginCapabilities = []string{pluginCapPush, pluginCapSearch, pluginCapGet}
+
+// pluginSession 单个插件连接的状态
+type pluginSession struct {
+	token        *models.ApiToken
+	remoteAddr   string
+	client       string
+	capabilities []string
+}
+
+// pluginHello HELLO 参数
+type pluginHello struct {
+	Client       string   `json:"client"`
+	Version      string   `json:"version"`
+	Protocol     int      `json:"protocol"`
+	Capabilities []string `json:"capabilities"`
+}
+
+// pluginPush PUSH 参数
+type pluginPush struct {
+	DocumentID int64  `json:"documentId"`
+	Language   string `json:"language"`
+	Text       string `json:"text"`
+	Source     string `json:"source"` // 来源，如文件路径，按块语言的注释写法写在块首行，语言没有注释时忽略
+}
+
+// pluginSearch SEARCH 参数
+type pluginSearch struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit"`
+}
+
+// pluginGet GET 参数
+type pluginGet struct {
+	DocumentID int64 `json:"documentId"`
+	Block      int   `json:"block"`
+}
+
+// PluginSnippet 搜索到的片段
+type PluginSnippet struct {
+	DocumentID int64   `json:"documentId"`
+	Title      string  `json:"title"`
+	Block      int     `json:"block"` // 块序号，从0开始
+	Language   string  `json:"language"`
+	Preview    string  `json:"preview"` // 匹配位置附近的摘录
+	Score      float64 `json:"score"`   // 相关度，越大越相关
+}
+
+// pluginError 协议错误
+type pluginError struct {
+	code    string
+	message string
+}
+
+func (e *pluginError) Error() string {
+	return e.code + " " + e.message
+}
+
+// PluginProtocolService 编辑器插件行协议服务
+// 连接通过 HTTP Upgrade 建立，之后每行一条命令：`VERB {json}`，响应为 `OK {json}` 或 `ERR CODE message`
+type PluginProtocolService struct {
+	logger          *log.LogService
+	apiService      *ApiService
+	documentService *DocumentService
+	searchService   *SearchService
+}
+
+// NewPluginProtocolService 创建插件协议服务
+func NewPluginProtocolService(localServerService *LocalServerService, apiService *ApiService, documentService *DocumentService, searchService *SearchService, logger *log.LogService) *PluginProtocolService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	ps := &PluginProtocolService{
+		logger:          logger,
+		apiService:      apiService,
+		documentService: documentService,
+		searchService:   searchService,
+	}
+	localServerService.handle("GET /api/v1/plugin", ps.handleUpgrade)
+	return ps
+}
+
+// ServiceStartup 服务启动
+func (ps *PluginProtocolService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	return nil
+}
+
+// handleUpgrade 认证后将HTTP连接升级为行协议连接
+func (ps *PluginProtocolService) handleUpgrade(w http.ResponseWriter, r *http.Request) {
+	token, err := ps.apiService.authenticate(r)
+	if err != nil {
+		ps.apiService.audit(nil, r, http.StatusUnauthorized)
+		w.Header().Set("WWW-Authenticate", `Bearer realm="voidraft"`)
+		writeAPIError(w, http.StatusUnauthorized, "invalid or missing token")
+		return
+	}
+	if !strings.EqualFold(r.Header.Get("Upgrade"), pluginProtocolName) {
+		ps.apiService.audit(token, r, http.StatusUpgradeRequired)
+		w.Header().Set("Upgrade", pluginProtocolName)
+		writeAPIError(w, http.StatusUpgradeRequired, "upgrade to "+pluginProtocolName+" required")
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		writeAPIError(w, http.StatusInternalServerError, "connection upgrade not supported")
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		ps.logger.Error("Failed to hijack plugin connection", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	ps.apiService.audit(token, r, http.StatusSwitchingProtocols)
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: %s\r\nConnection: Upgrade\r\n\r\n", pluginProtocolName)
+	if err := rw.Flush(); err != nil {
+		return
+	}
+
+	session := &pluginSession{token: token, remoteAddr: r.RemoteAddr}
+	ps.serve(conn, rw, session)
+}
+
+// serve 逐行处理命令直到连接关闭
+func (ps *PluginProtocolService) serve(conn net.Conn, rw *bufio.ReadWriter, session *pluginSession) {
+	scanner := bufio.NewScanner(rw.Reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), apiMaxBodyBytes)
+
+	for {
+		_ = conn.SetDeadline(time.Now().Add(pluginIdleTimeout))
+		if !scanner.Scan() {
+			return
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		verb, args, _ := strings.Cut(line, " ")
+		verb = strings.ToUpper(verb)
+		if verb == "BYE" {
+			ps.writeLine(rw, "OK {}")
+			return
+		}
+
+		result, err := ps.dispatch(session, verb, args)
+		ps.auditCommand(session, verb, err)
+		if err != nil {
+			var perr *pluginError
+			if !errors.As(err, &perr) {
+				perr = &pluginError{code: "INTERNAL", message: err.Error()}
+			}
+			ps.writeLine(rw, "ERR "+perr.code+" "+strings.ReplaceAll(perr.message, "\n", " "))
+			if perr.code == "UNAUTHORIZED" {
+				return
+			}
+			continue
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			ps.writeLine(rw, "ERR INTERNAL failed to encode response")
+			continue
+		}
+		ps.writeLine(rw, "OK "+string(data))
+	}
+}
+
+// dispatch 分发命令，HELLO 之前只允许 HELLO 和 PING
+func (ps *PluginProtocolService) dispatch(session *pluginSession, verb, args string) (any, error) {
+	if verb == "PING" {
+		return map[string]string{"pong": time.Now().Format(time.RFC3339)}, nil
+	}
+	// 连接可能保持很久，每条命令前重新读取令牌，撤销或修改权限后立即生效
+	token, err := ps.apiService.getApiToken(session.token.ID)
+	if errors.Is(err, errApiTokenNotFound) {
+		return nil, &pluginError{"UNAUTHORIZED", "token has been revoked"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	session.token = token
+
+	if verb == "HELLO" {
+		return ps.hello(session, args)
+	}
+	if session.capabilities == nil {
+		return nil, &pluginError{"HANDSHAKE", "send HELLO first"}
+	}
+	if !ps.apiService.allow(session.token) {
+		return nil, &pluginError{"RATE_LIMIT", "rate limit exceeded"}
+	}
+
+	switch verb {
+	case "PUSH":
+		if err := ps.require(session, pluginCapPush, models.ApiScopeAppendOnly); err != nil {
+			return nil, err
+		}
+		var req pluginPush
+		if err := decodePluginArgs(args, &req); err != nil {
+			return nil, err
+		}
+		return ps.push(req)
+	case "SEARCH":
+		if err := ps.require(session, pluginCapSearch, models.ApiScopeReadOnly); err != nil {
+			return nil, err
+		}
+		var req pluginSearch
+		if err := decodePluginArgs(args, &req); err != nil {
+			return nil, err
+		}
+		return ps.search(req)
+	case "GET":
+		if err := ps.require(session, pluginCapGet, models.ApiScopeReadOnly); err != nil {
+			return nil, err
+		}
+		var req pluginGet
+		if err := decodePluginArgs(args, &req); err != nil {
+			return nil, err
+		}
+		return ps.get(req)
+	}
+	return nil, &pluginError{"UNKNOWN", "unknown command " + verb}
+}
+
+// hello 能力协商，结果为双方能力与令牌权限的交集
+func (ps *PluginProtocolService) hello(session *pluginSession, args string) (any, error) {
+	var req pluginHello
+	if err := decodePluginArgs(args, &req); err != nil {
+		return nil, err
+	}
+	if req.Protocol != pluginProtocolVersion {
+		return nil, &pluginError{"PROTOCOL", fmt.Sprintf("unsupported protocol version %d, server speaks %d", req.Protocol, pluginProtocolVersion)}
+	}
+
+	session.client = req.Client
+	session.capabilities = []string{}
+	for _, capability := range pluginCapabilities {
+		if slices.Contains(req.Capabilities, capability) && session.token.Scope.Allows(pluginCapabilityScope(capability)) {
+			session.capabilities = append(session.capabilities, capability)
+		}
+	}
+
+	ps.logger.Info("Editor plugin connected", "client", req.Client, "version", req.Version, "capabilities", session.capabilities)
+	return map[string]any{
+		"server":       "voidraft",
+		"version":      version.Version,
+		"protocol":     pluginProtocolVersion,
+		"capabilities": session.capabilities,
+	}, nil
+}
+
+// require 检查能力已协商且令牌权限允许
+func (ps *PluginProtocolService) require(session *pluginSession, capability string, scope models.ApiTokenScope) error {
+	if !slices.Contains(session.capabilities, capability) {
+		return &pluginError{"CAPABILITY", capability + " was not negotiated"}
+	}
+	if !session.token.Scope.Allows(scope) {
+		return &pluginError{"FORBIDDEN", fmt.Sprintf("token scope %q does not allow %s", session.token.Scope, capability)}
+	}
+	return nil
+}
+
+// push 将选中内容作为新块追加到文档
+func (ps *PluginProtocolService) push(req pluginPush) (any, error) {
+	if req.Text == "" {
+		return nil, &pluginError{"INVALID", "text is required"}
+	}
+	if req.DocumentID == 0 {
+		req.DocumentID = sqlDefaultDocumentID
+	}
+	if req.Language == "" {
+		req.Language = blocks.DefaultLanguage
+	}
+	if !apiLanguagePattern.MatchString(req.Language) {
+		return nil, &pluginError{"INVALID", "invalid language"}
+	}
+
+	doc, err := ps.documentService.GetDocumentByID(req.DocumentID)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil || doc.IsDeleted {
+		return nil, &pluginError{"NOT_FOUND", "document not found"}
+	}
+
+	text := req.Text
+	if req.Source != "" {
+		if comment := textstats.CommentLine(req.Language, strings.ReplaceAll(req.Source, "\n", " ")); comment != "" {
+			text = comment + "\n" + text
+		}
+	}
+	// 以补丁追加，与编辑器中的修改串行，并可在编辑器中撤销
+	if _, err := ps.documentService.AppendDocumentContent(doc.ID, blocks.Delimiter(req.Language, false)+text, "Push from plugin"); err != nil {
+		return nil, err
+	}
+	return map[string]any{"documentId": doc.ID, "block": len(blocks.Parse(doc.Content))}, nil
+}
+
+// search 通过全文索引搜索所有文档的块
+func (ps *PluginProtocolService) search(req pluginSearch) (any, error) {
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, &pluginError{"INVALID", "query is required"}
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = pluginSearchLimit
+	}
+
+	results, err := ps.searchService.Search(req.Query, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	snippets := make([]PluginSnippet, 0, len(results))
+	for _, result := range results {
+		snippets = append(snippets, PluginSnippet{
+			DocumentID: result.DocumentID,
+			Title:      result.Title,
+			Block:      result.BlockIndex,
+			Language:   result.Language,
+			Preview:    result.Excerpt,
+			Score:      result.Score,
+		})
+	}
+	return snippets, nil
+}
+
+// get 读取单个块的全文
+func (ps *PluginProtocolService) get(req pluginGet) (any, error) {
+	doc, err := ps.documentService.GetDocumentByID(req.DocumentID)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil || doc.IsDeleted {
+		return nil, &pluginError{"NOT_FOUND", "document not found"}
+	}
+
+	parsed := blocks.Parse(doc.Content)
+	if req.Block < 0 || req.Block >= len(parsed) {
+		return nil, &pluginError{"NOT_FOUND", "block not found"}
+	}
+	return map[string]any{
+		"documentId": doc.ID,
+		"block":      req.Block,
+		"language":   parsed[req.Block].Language,
+		"text":       parsed[req.Block].Content,
+	}, nil
+}
+
+// auditCommand 记录命令调用，路径形如 /api/v1/plugin#SEARCH
+func (ps *PluginProtocolService) auditCommand(session *pluginSession, verb string, err error) {
+	status := http.StatusOK
+	var perr *pluginError
+	switch {
+	case errors.As(err, &perr) && perr.code == "RATE_LIMIT":
+		status = http.StatusTooManyRequests
+	case errors.As(err, &perr) && (perr.code == "FORBIDDEN" || perr.code == "CAPABILITY"):
+		status = http.StatusForbidden
+	case errors.As(err, &perr):
+		status = http.StatusBadRequest
+	case err != nil:
+		status = http.StatusInternalServerError
+	}
+
+	ps.apiService.recordAudit(session.token, "LINE", "/api/v1/plugin#"+verb, session.remoteAddr, status)
+}
+
+// writeLine 写入一行响应
+func (ps *PluginProtocolService) writeLine(rw *bufio.ReadWriter, line string) {
+	if _, err := rw.WriteString(line + "\n"); err == nil {
+		_ = rw.Flush()
+	}
+}
+
+// pluginCapabilityScope 能力所需的令牌权限
+func pluginCapabilityScope(capability string) models.ApiTokenScope {
+	if capability == pluginCapPush {
+		return models.ApiScopeAppendOnly
+	}
+	return models.ApiScopeReadOnly
+}
+
+// decodePluginArgs 解析命令的JSON参数
+func decodePluginArgs(args string, v any) error {
+	if strings.TrimSpace(args) == "" {
+		args = "{}"
+	}
+	if err := json.Unmarshal([]byte(args), v); err != nil {
+		return &pluginError{"INVALID", "invalid arguments: " + err.Error()}
+	}
+	return nil
+}
+
+// ServiceShutdown 服务关闭
+func (ps *PluginProtocolService) ServiceShutdown() error {
+	return nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+func TestPluginDispatchRechecksToken(t *testing.T) {
+	as := newTestApiService(t)
+	ps := &PluginProtocolService{logger: log.New(), apiService: as}
+	created, err := as.CreateApiToken("plugin", models.ApiScopeFull, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := &pluginSession{token: created.Info, capabilities: []string{pluginCapSearch}}
+
+	// 降低权限后，已建立的连接按新权限检查
+	if _, err := as.databaseService.getDB().Exec(`UPDATE api_tokens SET scope = ? WHERE id = ?`, models.ApiScopeAppendOnly, created.Info.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ps.dispatch(session, "SEARCH", `{"query":"x"}`)
+	var perr *pluginError
+	if !errors.As(err, &perr) || perr.code != "FORBIDDEN" {
+		t.Fatalf("SEARCH after scope change error = %v, want FORBIDDEN", err)
+	}
+
+	if err := as.RevokeApiToken(created.Info.ID); err != nil {
+		t.Fatal(err)
+	}
+	for _, verb := range []string{"HELLO", "SEARCH"} {
+		_, err := ps.dispatch(session, verb, `{}`)
+		if !errors.As(err, &perr) || perr.code != "UNAUTHORIZED" {
+			t.Errorf("%s after revoke error = %v, want UNAUTHORIZED", verb, err)
+		}
+	}
+	if _, err := ps.dispatch(session, "PING", ""); err != nil {
+		t.Errorf("PING after revoke error = %v", err)
+	}
+}
+
+func TestPluginPushAndSearch(t *testing.T) {
+	ds := newTestDocumentService(t)
+	id := createTestDocument(t, ds, "Snippets", "\n∞∞∞text-a\nnotes")
+	ss := NewSearchService(ds.databaseService, ds, nil, nil)
+	ps := &PluginProtocolService{logger: log.New(), documentService: ds, searchService: ss}
+
+	pushes := []pluginPush{
+		{DocumentID: id, Language: "py", Text: "print('hello')", Source: "src/main.py"},
+		{DocumentID: id, Language: "sql", Text: "SELECT 1", Source: "query.sql"},
+		{DocumentID: id, Language: "json", Text: `{"a": 1}`, Source: "data.json"},
+		{DocumentID: id, Language: "md", Text: "# Heading", Source: "README.md"},
+	}
+	for _, push := range pushes {
+		if _, err := ps.push(push); err != nil {
+			t.Fatal(err)
+		}
+	}
+	doc, err := ds.GetDocumentByID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"notes", "# src/main.py\nprint('hello')", "-- query.sql\nSELECT 1", `{"a": 1}`, "# Heading"}
+	parsed := blocks.Parse(doc.Content)
+	if len(parsed) != len(want) {
+		t.Fatalf("blocks = %d, want %d", len(parsed), len(want))
+	}
+	for i, block := range parsed {
+		if block.Content != want[i] {
+			t.Errorf("block %d = %q, want %q", i, block.Content, want[i])
+		}
+	}
+
+	if err := ss.RebuildSearchIndex(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ps.search(pluginSearch{Query: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snippets := got.([]PluginSnippet)
+	if len(snippets) != 1 || snippets[0].DocumentID != id || snippets[0].Block != 1 || snippets[0].Language != "py" {
+		t.Fatalf("search snippets = %+v, want block 1 of document %d", snippets, id)
+	}
+}
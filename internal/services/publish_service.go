@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"voidraft/internal/common/sshupload"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// PublishService 发布服务，将导出的文档通过 SFTP/SCP 上传到配置的主机
+type PublishService struct {
+	logger          *log.LogService
+	configService   *ConfigService
+	documentService *DocumentService
+	exportService   *ExportService
+	jobService      *JobService
+}
+
+// NewPublishService 创建发布服务
+func NewPublishService(configService *ConfigService, documentService *DocumentService, exportService *ExportService, jobService *JobService, logger *log.LogService) *PublishService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	return &PublishService{
+		logger:          logger,
+		configService:   configService,
+		documentService: documentService,
+		exportService:   exportService,
+		jobService:      jobService,
+	}
+}
+
+// ListPublishTargets 获取配置的发布目标
+func (ps *PublishService) ListPublishTargets() []models.PublishTarget {
+	config, err := ps.configService.GetConfig()
+	if err != nil || config.Publish.Targets == nil {
+		return []models.PublishTarget{}
+	}
+	return config.Publish.Targets
+}
+
+// TestPublishTarget 测试能否连接并认证到发布目标
+func (ps *PublishService) TestPublishTarget(name string) error {
+	target, knownHosts, err := ps.target(name)
+	if err != nil {
+		return err
+	}
+	client, err := sshupload.Dial(context.Background(), sshTarget(target, knownHosts))
+	if err != nil {
+		return err
+	}
+	return client.Close()
+}
+
+// StartPublishDocument 以后台任务将文档导出为 HTML 或 Markdown 并上传到发布目标
+// 文件名取自文档标题，远程已存在同名文件时覆盖
+func (ps *PublishService) StartPublishDocument(documentID int64, targetName string, format models.PublishFormat, style string) (*models.Job, error) {
+	target, knownHosts, err := ps.target(targetName)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := ps.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil || doc.IsDeleted {
+		return nil, fmt.Errorf("document not found: %d", documentID)
+	}
+
+	var content, ext string
+	switch format {
+	case models.PublishFormatHTML, "":
+		content, err = ps.exportService.RenderDocumentHTML(documentID, style)
+		ext = ".html"
+	case models.PublishFormatMarkdown:
+		content, err = ps.exportService.RenderDocumentSource(documentID)
+		ext = ".md"
+	default:
+		return nil, fmt.Errorf("unsupported publish format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	remotePath := path.Join(target.RemoteDir, publishFileName(doc.Title)+ext)
+	return ps.jobService.submit(models.JobKindPublish, "Publish to "+target.Name, func(ctx context.Context, progress jobProgress) (any, error) {
+		return ps.upload(ctx, target, knownHosts, remotePath, content, progress)
+	})
+}
+
+// upload 连接目标主机并上传内容
+func (ps *PublishService) upload(ctx context.Context, target models.PublishTarget, knownHosts string, remotePath string, content string, progress jobProgress) (*models.PublishResult, error) {
+	client, err := sshupload.Dial(ctx, sshTarget(target, knownHosts))
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	size := int64(len(content))
+	err = sshupload.Upload(ctx, client, sshupload.Protocol(target.Protocol), remotePath, strings.NewReader(content), size, func(written int64) {
+		progress.bytes(written, size)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload %s: %w", remotePath, err)
+	}
+
+	ps.logger.Info("Published document", "target", target.Name, "path", remotePath)
+	return &models.PublishResult{Target: target.Name, RemotePath: remotePath, Size: size}, nil
+}
+
+// target 按名称查找发布目标
+func (ps *PublishService) target(name string) (models.PublishTarget, string, error) {
+	config, err := ps.configService.GetConfig()
+	if err != nil {
+		return models.PublishTarget{}, "", err
+	}
+	for _, target := range config.Publish.Targets {
+		if target.Name == name {
+			if target.Host == "" || target.User == "" || target.RemoteDir == "" {
+				return models.PublishTarget{}, "", errors.New("publish target requires host, user and remote directory")
+			}
+			return target, config.Publish.KnownHosts, nil
+		}
+	}
+	return models.PublishTarget{}, "", fmt.Errorf("publish target not found: %s", name)
+}
+
+// sshTarget 转换为上传使用的主机参数
+func sshTarget(target models.PublishTarget, knownHosts string) sshupload.Target {
+	return sshupload.Target{
+		Host:       target.Host,
+		Port:       target.Port,
+		User:       target.User,
+		KnownHosts: knownHosts,
+	}
+}
+
+// publishFileName 由文档标题生成远程文件名，替换路径分隔符和常见文件系统不允许的字符
+func publishFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case strings.ContainsRune(`/\:*?"<>|`, r), r < 0x20:
+			return '-'
+		case r == ' ' || r == '\t':
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(title))
+	name = strings.Trim(name, ".-")
+	if name == "" {
+		return "document"
+	}
+	return name
+}
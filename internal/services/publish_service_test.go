@@ -0,0 +1,18 @@
+package services
+
+import "testing"
+
+func TestPublishFileName(t *testing.T) {
+	cases := map[string]string{
+		"Deploy runbook": "Deploy-runbook",
+		"a/b: c?":        "a-b--c",
+		"  ..hidden  ":   "hidden",
+		"":               "document",
+		"发布说明 v2.1":      "发布说明-v2.1",
+	}
+	for title, want := range cases {
+		if got := publishFileName(title); got != want {
+			t.Errorf("publishFileName(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"voidraft/internal/common/qr"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// QRCodeService 二维码服务，将片段分享到手机，或从截图中读取二维码文本
+type QRCodeService struct {
+	logger *log.LogService
+}
+
+// NewQRCodeService 创建二维码服务实例
+func NewQRCodeService(logger *log.LogService) *QRCodeService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &QRCodeService{logger: logger}
+}
+
+// GenerateQRCode 生成文本的二维码 PNG
+func (qs *QRCodeService) GenerateQRCode(text string) ([]byte, error) {
+	return qr.Generate(text)
+}
+
+// DecodeQRCode 识别图片（PNG、JPEG、GIF）中的二维码文本
+func (qs *QRCodeService) DecodeQRCode(imageBytes []byte) (string, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(imageBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	if config.Width*config.Height > qr.MaxDecodePixels {
+		return "", fmt.Errorf("image is too large: %dx%d", config.Width, config.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(imageBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	return qr.Decode(img)
+}
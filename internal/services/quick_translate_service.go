@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+	"github.com/wailsapp/wails/v3/pkg/services/notifications"
+)
+
+const (
+	// quickTranslateCategory 快速翻译通知分类，带复制按钮
+	quickTranslateCategory = "quick_translate"
+	// quickTranslateCopyAction 通知中的复制按钮
+	quickTranslateCopyAction = "copy"
+	// quickTranslateMaxLength 剪贴板文本长度上限，超出部分不翻译
+	quickTranslateMaxLength = 5000
+)
+
+// ErrClipboardEmpty 剪贴板中没有文本
+var ErrClipboardEmpty = errors.New("clipboard has no text")
+
+// QuickTranslateService 剪贴板快速翻译服务
+// 由托盘菜单或全局热键触发，使用默认翻译器和目标语言规则翻译剪贴板文本，结果以带复制按钮的通知显示
+type QuickTranslateService struct {
+	logger              *log.LogService
+	configService       *ConfigService
+	translationService  *TranslationService
+	hotkeyService       *HotkeyService
+	notificationService *notifications.NotificationService
+
+	mu         sync.Mutex
+	binding    *hotkeyBinding // 快速翻译热键
+	running    atomic.Bool
+	actionable bool // 通知分类是否注册成功，否则发送普通通知
+
+	cancelObservers []CancelFunc
+}
+
+// NewQuickTranslateService 创建剪贴板快速翻译服务实例
+func NewQuickTranslateService(configService *ConfigService, translationService *TranslationService, hotkeyService *HotkeyService, notificationService *notifications.NotificationService, logger *log.LogService) *QuickTranslateService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &QuickTranslateService{
+		logger:              logger,
+		configService:       configService,
+		translationService:  translationService,
+		hotkeyService:       hotkeyService,
+		notificationService: notificationService,
+	}
+}
+
+// ServiceStartup 注册通知分类并按配置注册全局热键
+func (qs *QuickTranslateService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	if qs.notificationService != nil {
+		err := qs.notificationService.RegisterNotificationCategory(notifications.NotificationCategory{
+			ID:      quickTranslateCategory,
+			Actions: []notifications.NotificationAction{{ID: quickTranslateCopyAction, Title: "Copy"}},
+		})
+		if err != nil {
+			qs.logger.Warning("Failed to register quick translate notification category", "error", err)
+		} else {
+			qs.actionable = true
+			qs.notificationService.OnNotificationResponse(qs.onNotificationResponse)
+		}
+	}
+
+	qs.cancelObservers = []CancelFunc{
+		qs.configService.Watch("translation.enableQuickTranslateHotkey", qs.onHotkeyConfigChange),
+		qs.configService.Watch("translation.quickTranslateHotkey", qs.onHotkeyConfigChange),
+	}
+	qs.onHotkeyConfigChange(nil, nil)
+	return nil
+}
+
+// TranslateClipboard 翻译剪贴板文本，结果通过通知和事件返回
+func (qs *QuickTranslateService) TranslateClipboard() (*models.QuickTranslateResult, error) {
+	if !qs.running.CompareAndSwap(false, true) {
+		return nil, errors.New("quick translate is already running")
+	}
+	defer qs.running.Store(false)
+
+	app := application.Get()
+	text, ok := app.Clipboard.Text()
+	text = strings.TrimSpace(text)
+	if !ok || text == "" {
+		qs.notify("Translate clipboard", ErrClipboardEmpty.Error(), "")
+		return nil, ErrClipboardEmpty
+	}
+	if runes := []rune(text); len(runes) > quickTranslateMaxLength {
+		text = string(runes[:quickTranslateMaxLength])
+	}
+
+	from := guessLanguage(text)
+	result := &models.QuickTranslateResult{
+		Text:       text,
+		From:       from,
+		To:         qs.translationService.ResolveTargetLanguage(from),
+		Translator: qs.translationService.defaultTranslator(),
+	}
+	translation, err := qs.translationService.TranslateWith(text, "auto", result.To, result.Translator)
+	if err != nil {
+		qs.logger.Error("Quick translate failed", "error", err)
+		qs.notify("Translation failed", err.Error(), "")
+		return nil, fmt.Errorf("failed to translate clipboard: %w", err)
+	}
+	result.Translation = translation
+
+	qs.notify(fmt.Sprintf("Translation (%s → %s)", result.From, result.To), translation, translation)
+	helper.EmitEvent(constant.EVENT_QUICK_TRANSLATE_RESULT, result)
+	return result, nil
+}
+
+// notify 发送通知，copyText 非空时附带复制按钮
+func (qs *QuickTranslateService) notify(title, body, copyText string) {
+	if qs.notificationService == nil {
+		return
+	}
+	authorized, err := qs.notificationService.CheckNotificationAuthorization()
+	if err != nil || !authorized {
+		authorized, err = qs.notificationService.RequestNotificationAuthorization()
+		if err != nil || !authorized {
+			return
+		}
+	}
+
+	options := notifications.NotificationOptions{
+		ID:    "quick_translate",
+		Title: title,
+		Body:  body,
+	}
+	if copyText != "" && qs.actionable {
+		options.CategoryID = quickTranslateCategory
+		options.Data = map[string]interface{}{"text": copyText}
+		err = qs.notificationService.SendNotificationWithActions(options)
+	} else {
+		err = qs.notificationService.SendNotification(options)
+	}
+	if err != nil {
+		qs.logger.Warning("Failed to send quick translate notification", "error", err)
+	}
+}
+
+// onNotificationResponse 点击复制按钮时将译文写入剪贴板
+func (qs *QuickTranslateService) onNotificationResponse(result notifications.NotificationResult) {
+	if result.Error != nil || result.Response.CategoryID != quickTranslateCategory {
+		return
+	}
+	if result.Response.ActionIdentifier != quickTranslateCopyAction {
+		return
+	}
+	if text, ok := result.Response.UserInfo["text"].(string); ok {
+		application.Get().Clipboard.SetText(text)
+	}
+}
+
+// onHotkeyConfigChange 按配置重新注册快速翻译热键
+func (qs *QuickTranslateService) onHotkeyConfigChange(oldValue, newValue interface{}) {
+	qs.unregisterHotkey()
+
+	config, err := qs.configService.GetConfig()
+	if err != nil || !config.Translation.EnableQuickTranslateHotkey {
+		return
+	}
+	if err := qs.registerHotkey(&config.Translation.QuickTranslateHotkey); err != nil {
+		qs.logger.Error("Failed to register quick translate hotkey", "error", err)
+	}
+}
+
+// registerHotkey 注册快速翻译热键
+func (qs *QuickTranslateService) registerHotkey(combo *models.HotkeyCombo) error {
+	binding, err := qs.hotkeyService.bindHotkey(combo, func() {
+		_, _ = qs.TranslateClipboard()
+	})
+	if err != nil {
+		return err
+	}
+	qs.mu.Lock()
+	qs.binding = binding
+	qs.mu.Unlock()
+	return nil
+}
+
+// unregisterHotkey 取消注册快速翻译热键
+func (qs *QuickTranslateService) unregisterHotkey() {
+	qs.mu.Lock()
+	binding := qs.binding
+	qs.binding = nil
+	qs.mu.Unlock()
+	binding.Close()
+}
+
+// guessLanguage 按文字系统粗略推测源语言，用于选择目标语言规则
+func guessLanguage(text string) string {
+	var latin, han, kana, hangul, cyrillic int
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+
+	switch {
+	case kana > 0:
+		return "ja"
+	case han > 0 && han >= latin:
+		return "zh"
+	case hangul > 0 && hangul >= latin:
+		return "ko"
+	case cyrillic > latin:
+		return "ru"
+	}
+	return "en"
+}
+
+// ServiceShutdown 取消热键和配置监听
+func (qs *QuickTranslateService) ServiceShutdown() error {
+	for _, cancel := range qs.cancelObservers {
+		cancel()
+	}
+	qs.unregisterHotkey()
+	return nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"testing"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+func TestGuessLanguage(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Hello world", "en"},
+		{"这是一段中文", "zh"},
+		{"中文 with some English words", "en"},
+		{"Go 语言很好用", "zh"},
+		{"ひらがなと漢字", "ja"},
+		{"カタカナ", "ja"},
+		{"안녕하세요", "ko"},
+		{"Привет, мир", "ru"},
+		{"12345 !?", "en"},
+	}
+	for _, tt := range tests {
+		if got := guessLanguage(tt.text); got != tt.want {
+			t.Errorf("guessLanguage(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+// TestQuickTranslateHotkeyConfig 未启用或热键无效时不注册热键
+func TestQuickTranslateHotkeyConfig(t *testing.T) {
+	config := models.NewDefaultAppConfig()
+	configService := newTestConfigService(t, config)
+	qs := NewQuickTranslateService(configService, nil, NewHotkeyService(configService, log.New()), nil, nil)
+
+	qs.onHotkeyConfigChange(nil, nil)
+	if qs.binding != nil {
+		t.Error("hotkey registered while disabled")
+	}
+
+	configService.koanf.Set("translation.enableQuickTranslateHotkey", true)
+	configService.koanf.Set("translation.quickTranslateHotkey.key", "")
+	qs.onHotkeyConfigChange(nil, nil)
+	if qs.binding != nil {
+		t.Error("invalid hotkey should not be registered")
+	}
+	if err := qs.ServiceShutdown(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestTranslateClipboardAlreadyRunning 上一次翻译未结束时直接返回错误
+func TestTranslateClipboardAlreadyRunning(t *testing.T) {
+	qs := NewQuickTranslateService(nil, nil, nil, nil, nil)
+	qs.running.Store(true)
+	if _, err := qs.TranslateClipboard(); err == nil {
+		t.Error("TranslateClipboard should fail while another translation is running")
+	}
+	if !qs.running.Load() {
+		t.Error("running flag of the active translation was cleared")
+	}
+}
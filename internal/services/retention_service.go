@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// retentionInterval 自动执行保留策略的间隔
+const retentionInterval = 24 * time.Hour
+
+// RetentionService 文档保留策略
+// 维护任务每天按规则归档长期未使用的文档、永久删除回收站中过期的文档，也可以先预览报告再手动执行
+type RetentionService struct {
+	logger          *log.LogService
+	configService   *ConfigService
+	documentService *DocumentService
+	jobService      *JobService
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRetentionService 创建文档保留策略服务实例
+func NewRetentionService(configService *ConfigService, documentService *DocumentService, jobService *JobService, logger *log.LogService) *RetentionService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &RetentionService{
+		logger:          logger,
+		configService:   configService,
+		documentService: documentService,
+		jobService:      jobService,
+	}
+}
+
+// ServiceStartup 启动每天一次的维护任务
+func (rs *RetentionService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	loopCtx, cancel := context.WithCancel(context.Background())
+	rs.cancel = cancel
+	rs.wg.Add(1)
+	go func() {
+		defer rs.wg.Done()
+		rs.maintenanceLoop(loopCtx)
+	}()
+	return nil
+}
+
+// maintenanceLoop 启用自动执行时按间隔提交维护任务
+func (rs *RetentionService) maintenanceLoop(ctx context.Context) {
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+	for {
+		if config, err := rs.configService.GetConfig(); err == nil && config.Retention.Enabled {
+			if _, err := rs.RunRetention(); err != nil {
+				rs.logger.Error("Failed to start retention job", "error", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PreviewRetention 按当前规则生成报告，不修改任何文档
+func (rs *RetentionService) PreviewRetention() (*models.RetentionReport, error) {
+	return rs.evaluate(context.Background(), true, nil)
+}
+
+// RunRetention 以后台任务执行保留策略，任务结果为执行报告
+func (rs *RetentionService) RunRetention() (*models.Job, error) {
+	return rs.jobService.submit(models.JobKindRetention, "Apply retention rules", func(ctx context.Context, progress jobProgress) (any, error) {
+		return rs.evaluate(ctx, false, progress)
+	})
+}
+
+// evaluate 评估规则，dryRun 为 false 时依次执行操作，单个文档失败不影响其他文档
+func (rs *RetentionService) evaluate(ctx context.Context, dryRun bool, progress jobProgress) (*models.RetentionReport, error) {
+	config, err := rs.configService.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("get config: %w", err)
+	}
+	candidates, err := rs.documentService.listRetentionCandidates()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &models.RetentionReport{
+		DryRun:      dryRun,
+		GeneratedAt: now.Format(time.DateTime),
+		Actions:     planRetention(candidates, config.Retention, now),
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	for i := range report.Actions {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		action := &report.Actions[i]
+		if progress != nil {
+			progress(i, len(report.Actions), action.Title)
+		}
+
+		switch action.Action {
+		case models.RetentionArchive:
+			err = rs.documentService.ArchiveDocument(action.DocumentID)
+			if err == nil {
+				report.Archived++
+			}
+		case models.RetentionPurge:
+			err = rs.documentService.PurgeDocument(action.DocumentID)
+			if err == nil {
+				report.Purged++
+			}
+		}
+		if err != nil {
+			action.Error = err.Error()
+			rs.logger.Warning("Retention action failed", "document", action.DocumentID, "action", action.Action, "error", err)
+		}
+	}
+	if report.Archived > 0 || report.Purged > 0 {
+		rs.logger.Info("Retention rules applied", "archived", report.Archived, "purged", report.Purged)
+	}
+	return report, nil
+}
+
+// planRetention 计算需要执行的操作，默认文档和锁定的文档不受影响
+func planRetention(candidates []retentionCandidate, config models.RetentionConfig, now time.Time) []models.RetentionAction {
+	actions := []models.RetentionAction{}
+	for _, c := range candidates {
+		if c.ID == sqlDefaultDocumentID || c.Locked {
+			continue
+		}
+		archiveDays, purgeDays, rule := retentionRuleFor(c, config)
+
+		if c.Deleted {
+			days, ok := idleDays(now, trashedAt(c.DeletedAt, c.UpdatedAt))
+			if ok && purgeDays > 0 && days >= purgeDays {
+				actions = append(actions, models.RetentionAction{DocumentID: c.ID, Title: c.Title, Action: models.RetentionPurge, IdleDays: days, Rule: rule})
+			}
+			continue
+		}
+		if c.Archived || archiveDays <= 0 {
+			continue
+		}
+		days, ok := idleDays(now, c.UpdatedAt, c.LastOpenedAt)
+		if ok && days >= archiveDays {
+			actions = append(actions, models.RetentionAction{DocumentID: c.ID, Title: c.Title, Action: models.RetentionArchive, IdleDays: days, Rule: rule})
+		}
+	}
+	return actions
+}
+
+// retentionRuleFor 文档适用的归档和清理天数，返回生效的分组名称
+func retentionRuleFor(c retentionCandidate, config models.RetentionConfig) (archiveDays, purgeDays int, rule string) {
+	archiveDays, purgeDays = config.ArchiveAfterDays, config.PurgeTrashAfterDays
+	for _, o := range config.Overrides {
+		if o.Kind != "" && o.Kind != c.Kind {
+			continue
+		}
+		if o.TitlePrefix != "" && !strings.HasPrefix(strings.ToLower(c.Title), strings.ToLower(o.TitlePrefix)) {
+			continue
+		}
+		if o.CollectionID != nil && !inCollection(c, *o.CollectionID) {
+			continue
+		}
+		if o.Kind == "" && o.TitlePrefix == "" && o.CollectionID == nil {
+			continue
+		}
+		if o.ArchiveAfterDays != 0 {
+			archiveDays = o.ArchiveAfterDays
+		}
+		if o.PurgeTrashAfterDays != 0 {
+			purgeDays = o.PurgeTrashAfterDays
+		}
+		return archiveDays, purgeDays, o.Name
+	}
+	return archiveDays, purgeDays, ""
+}
+
+// inCollection 文档是否在文件夹或其子文件夹中，0 表示不在任何文件夹中的文档
+func inCollection(c retentionCandidate, collectionID int64) bool {
+	if collectionID == 0 {
+		return len(c.Collections) == 0
+	}
+	return slices.Contains(c.Collections, collectionID)
+}
+
+// idleDays 距最近一次时间记录的整天数，所有时间都无法解析时返回 false
+func idleDays(now time.Time, values ...string) (int, bool) {
+	var latest time.Time
+	for _, value := range values {
+		if t, ok := parseDocumentTime(value); ok && t.After(latest) {
+			latest = t
+		}
+	}
+	if latest.IsZero() {
+		return 0, false
+	}
+	return int(now.Sub(latest) / (24 * time.Hour)), true
+}
+
+// ServiceShutdown 停止维护任务
+func (rs *RetentionService) ServiceShutdown() error {
+	if rs.cancel != nil {
+		rs.cancel()
+	}
+	rs.wg.Wait()
+	return nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"voidraft/internal/models"
+)
+
+func TestPlanRetention(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	daysAgo := func(days int) string {
+		return now.Add(-time.Duration(days) * 24 * time.Hour).Format("2006-01-02 15:04:05")
+	}
+
+	config := models.RetentionConfig{
+		ArchiveAfterDays:    90,
+		PurgeTrashAfterDays: 30,
+		Overrides: []models.RetentionOverride{
+			{Name: "scratch", TitlePrefix: "scratch", ArchiveAfterDays: 7},
+			{Name: "journals", Kind: models.DocumentKindJournal, ArchiveAfterDays: -1},
+		},
+	}
+	candidates := []retentionCandidate{
+		{ID: 1, Title: "default", UpdatedAt: daysAgo(400)},                                   // 默认文档
+		{ID: 2, Title: "Notes", UpdatedAt: daysAgo(120), LastOpenedAt: daysAgo(10)},          // 最近打开过
+		{ID: 3, Title: "Notes old", UpdatedAt: daysAgo(120)},                                 // 归档
+		{ID: 4, Title: "Scratch 1", UpdatedAt: daysAgo(8)},                                   // 分组规则归档
+		{ID: 5, Title: "Worklog", Kind: models.DocumentKindJournal, UpdatedAt: daysAgo(200)}, // 分组规则不归档
+		{ID: 6, Title: "Trash", Deleted: true, UpdatedAt: daysAgo(31)},                       // 清理
+		{ID: 7, Title: "Trash new", Deleted: true, UpdatedAt: daysAgo(3)},                    // 未到期
+		{ID: 8, Title: "Pinned", Locked: true, UpdatedAt: daysAgo(500)},                      // 锁定
+		{ID: 9, Title: "Archived", Archived: true, UpdatedAt: daysAgo(500)},                  // 已归档
+		{ID: 10, Title: "Legacy", UpdatedAt: "2025-01-01 08:00:00.123 +0800 CST m=+0.001"},   // 早期时间格式
+		{ID: 11, Title: "Unknown", UpdatedAt: "not a time"},                                  // 无法解析
+	}
+
+	actions := planRetention(candidates, config, now)
+	var got []string
+	for _, a := range actions {
+		got = append(got, string(a.Action)+":"+a.Title+":"+a.Rule)
+	}
+	want := []string{
+		"archive:Notes old:",
+		"archive:Scratch 1:scratch",
+		"purge:Trash:",
+		"archive:Legacy:",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("planRetention = %v, want %v", got, want)
+	}
+}
+
+func TestParseDocumentTime(t *testing.T) {
+	if _, ok := parseDocumentTime("2026-10-16 09:30:00"); !ok {
+		t.Fatal("expected database layout to parse")
+	}
+	if _, ok := parseDocumentTime(""); ok {
+		t.Fatal("empty value should not parse")
+	}
+}
+
+func TestRetentionRuleForCollection(t *testing.T) {
+	projects, unfiled := int64(3), int64(0)
+	config := models.RetentionConfig{
+		ArchiveAfterDays: 90,
+		Overrides: []models.RetentionOverride{
+			{Name: "projects", CollectionID: &projects, ArchiveAfterDays: -1},
+			{Name: "unfiled", CollectionID: &unfiled, ArchiveAfterDays: 14},
+		},
+	}
+	parents := map[int64]int64{3: 0, 5: 3, 7: 5, 8: 0}
+
+	tests := []struct {
+		collection int64
+		archive    int
+		rule       string
+	}{
+		{3, -1, "projects"},
+		{7, -1, "projects"}, // 子文件夹
+		{8, 90, ""},
+		{0, 14, "unfiled"},
+	}
+	for _, tt := range tests {
+		c := retentionCandidate{Title: "doc", Collections: collectionAncestors(parents, tt.collection)}
+		archive, _, rule := retentionRuleFor(c, config)
+		if archive != tt.archive || rule != tt.rule {
+			t.Errorf("collection %d: rule = %d %q, want %d %q", tt.collection, archive, rule, tt.archive, tt.rule)
+		}
+	}
+
+	if got := collectionAncestors(map[int64]int64{1: 2, 2: 1}, 1); !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Errorf("collectionAncestors with cycle = %v", got)
+	}
+}
@@ -0,0 +1,435 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/excerpt"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// SQL 查询语句
+const (
+	// 搜索词中的 lang:xx 只匹配识别为该语言的文本块
+	// %[1]s 为摘录表达式，%[2]s 为匹配条件，见 searchQuery
+	sqlSearchBlocks = `
+SELECT search_blocks.document_id, search_blocks.block_index, search_blocks.language, COALESCE(bl.language, ''),
+       highlight(search_blocks, 0, char(57344), char(57345)),
+       %[1]s,
+       bm25(search_blocks, 4.0, 1.0) AS score
+FROM search_blocks
+JOIN documents d ON d.id = search_blocks.document_id
+LEFT JOIN block_languages bl ON bl.document_id = search_blocks.document_id AND bl.block_index = search_blocks.block_index
+WHERE %[2]s AND d.is_deleted = 0 AND (? = '' OR bl.language = ?)
+ORDER BY score
+LIMIT ?`
+
+	// 每个文档只取最相关的块，bm25 等辅助函数只能直接作用于 FTS 表，先在内层查询中计算
+	sqlSearchDocuments = `
+SELECT document_id, block_index, language, text_language, title, snippet, score, matches
+FROM (
+    SELECT r.*,
+           ROW_NUMBER() OVER (PARTITION BY document_id ORDER BY score) AS rn,
+           COUNT(*) OVER (PARTITION BY document_id) AS matches
+    FROM (
+        SELECT search_blocks.document_id AS document_id, search_blocks.block_index AS block_index,
+               search_blocks.language AS language, COALESCE(bl.language, '') AS text_language,
+               highlight(search_blocks, 0, char(57344), char(57345)) AS title,
+               %[1]s AS snippet,
+               bm25(search_blocks, 4.0, 1.0) AS score
+        FROM search_blocks
+        JOIN documents d ON d.id = search_blocks.document_id
+        LEFT JOIN block_languages bl ON bl.document_id = search_blocks.document_id AND bl.block_index = search_blocks.block_index
+        WHERE %[2]s AND d.is_deleted = 0 AND (? = '' OR bl.language = ?)
+    ) r
+)
+WHERE rn = 1
+ORDER BY score
+LIMIT ? OFFSET ?`
+
+	sqlCountSearchDocuments = `
+SELECT COUNT(DISTINCT search_blocks.document_id)
+FROM search_blocks
+JOIN documents d ON d.id = search_blocks.document_id
+LEFT JOIN block_languages bl ON bl.document_id = search_blocks.document_id AND bl.block_index = search_blocks.block_index
+WHERE %[2]s AND d.is_deleted = 0 AND (? = '' OR bl.language = ?)`
+
+	sqlDeleteSearchDocument = `
+DELETE FROM search_blocks WHERE rowid >= ? AND rowid < ?`
+
+	sqlInsertSearchBlock = `
+INSERT INTO search_blocks (rowid, title, content, document_id, block_index, language)
+VALUES (?, ?, ?, ?, ?, ?)`
+
+	sqlGetSearchSource = `
+SELECT title, content, is_deleted FROM documents WHERE id = ?`
+
+	sqlListSearchSourceIDs = `
+SELECT id FROM documents WHERE is_deleted = 0 ORDER BY id`
+
+	sqlCountSearchBlocks = `
+SELECT COUNT(*) FROM search_blocks`
+
+	sqlClearSearchBlocks = `
+DELETE FROM search_blocks`
+)
+
+const (
+	// searchBlockBits rowid 中块序号占用的位数
+	searchBlockBits = 20
+	// searchMaxBlocks 单个文档参与索引的最大块数
+	searchMaxBlocks = 1 << searchBlockBits
+	// searchDefaultLimit 默认返回结果数
+	searchDefaultLimit = 50
+	// searchSnippetTokens 摘录包含的词数，trigram 分词时约等于字符数
+	searchSnippetTokens = 24
+	// searchMinMatchRunes trigram 索引能匹配的最短词长，更短的词用 LIKE 匹配
+	searchMinMatchRunes = 3
+)
+
+// SearchService 全文搜索服务，按块维护 FTS5 索引
+type SearchService struct {
+	logger          *log.LogService
+	databaseService *DatabaseService
+	documentService *DocumentService
+	jobService      *JobService
+
+	// indexMu 串行化索引写入，保证异步的变更事件按最新内容落盘
+	indexMu sync.Mutex
+}
+
+// NewSearchService 创建全文搜索服务
+func NewSearchService(databaseService *DatabaseService, documentService *DocumentService, jobService *JobService, logger *log.LogService) *SearchService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	return &SearchService{
+		logger:          logger,
+		databaseService: databaseService,
+		documentService: documentService,
+		jobService:      jobService,
+	}
+}
+
+// ServiceStartup 服务启动，订阅文档变更；索引为空时在后台重建
+func (ss *SearchService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	ss.documentService.onDocumentChange(ss.handleDocumentChange)
+
+	if ss.databaseService == nil || ss.databaseService.getDB() == nil || ss.databaseService.ensureWritable() != nil {
+		return nil
+	}
+	var count int
+	if err := ss.databaseService.getDB().QueryRow(sqlCountSearchBlocks).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check search index: %w", err)
+	}
+	if count == 0 {
+		if _, err := ss.StartRebuildSearchIndex(); err != nil {
+			ss.logger.Error("Failed to build search index", "error", err)
+		}
+	}
+	return nil
+}
+
+// Search 全文搜索，返回带上下文摘录和匹配区间的结果；搜索词中的 lang:xx 按文本块的自然语言筛选
+func (ss *SearchService) Search(query string, limit int) ([]*models.SearchResult, error) {
+	if err := ss.documentService.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	if ss.databaseService == nil || ss.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	query, language := parseLanguageFilter(query)
+	search := buildSearchQuery(query)
+	if search.empty() {
+		return []*models.SearchResult{}, nil
+	}
+	if limit <= 0 {
+		limit = searchDefaultLimit
+	}
+
+	rows, err := ss.databaseService.getDB().Query(search.sql(sqlSearchBlocks), search.args(language, language, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search documents: %w", err)
+	}
+	return scanSearchResults(rows, search, false)
+}
+
+// SearchDocuments 按文档分组的全文搜索，每个文档返回最相关的块，按相关度分页
+func (ss *SearchService) SearchDocuments(query string, limit, offset int) (*models.SearchPage, error) {
+	if err := ss.documentService.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	if ss.databaseService == nil || ss.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	page := &models.SearchPage{Items: []*models.SearchResult{}}
+	query, language := parseLanguageFilter(query)
+	search := buildSearchQuery(query)
+	if search.empty() {
+		return page, nil
+	}
+	if limit <= 0 {
+		limit = searchDefaultLimit
+	}
+	offset = max(offset, 0)
+
+	count := search
+	count.snippet = false
+	if err := ss.databaseService.getDB().QueryRow(count.sql(sqlCountSearchDocuments), count.args(language, language)...).Scan(&page.Total); err != nil {
+		return nil, fmt.Errorf("failed to count search results: %w", err)
+	}
+	if offset >= page.Total {
+		return page, nil
+	}
+
+	rows, err := ss.databaseService.getDB().Query(search.sql(sqlSearchDocuments), search.args(language, language, limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search documents: %w", err)
+	}
+	if page.Items, err = scanSearchResults(rows, search, true); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// scanSearchResults 读取搜索结果并解析高亮标记，withMatches 表示结果包含匹配块数列
+// 用 LIKE 匹配的短词没有全文索引的高亮，在这里补上标记
+func scanSearchResults(rows *sql.Rows, search searchQuery, withMatches bool) ([]*models.SearchResult, error) {
+	defer rows.Close()
+
+	results := []*models.SearchResult{}
+	for rows.Next() {
+		var result models.SearchResult
+		var title, snippet string
+		dest := []any{&result.DocumentID, &result.BlockIndex, &result.Language, &result.TextLanguage, &title, &snippet, &result.Score}
+		if withMatches {
+			dest = append(dest, &result.Matches)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		if len(search.likes) > 0 {
+			if search.match == "" {
+				snippet = excerpt.Around(snippet, search.likes, searchSnippetTokens)
+			}
+			title, snippet = excerpt.Mark(title, search.likes), excerpt.Mark(snippet, search.likes)
+		}
+		result.Title, result.TitleHighlights = excerpt.ParseMarked(title)
+		result.Excerpt, result.Highlights = excerpt.ParseMarked(snippet)
+		// bm25 越小越相关，取反便于前端理解
+		result.Score = -result.Score
+		results = append(results, &result)
+	}
+	return results, rows.Err()
+}
+
+// RebuildSearchIndex 重建全部文档的搜索索引
+func (ss *SearchService) RebuildSearchIndex() error {
+	return ss.rebuildSearchIndex(context.Background(), nil)
+}
+
+// StartRebuildSearchIndex 以后台任务重建搜索索引
+func (ss *SearchService) StartRebuildSearchIndex() (*models.Job, error) {
+	return ss.jobService.submit(models.JobKindIndex, "Rebuild search index", func(ctx context.Context, progress jobProgress) (any, error) {
+		return nil, ss.rebuildSearchIndex(ctx, progress)
+	})
+}
+
+// rebuildSearchIndex 重建索引，取消时已处理的文档保留在索引中
+func (ss *SearchService) rebuildSearchIndex(ctx context.Context, progress jobProgress) error {
+	if ss.databaseService == nil || ss.databaseService.getDB() == nil {
+		return errors.New("database service not available")
+	}
+	if err := ss.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	rows, err := ss.databaseService.getDB().Query(sqlListSearchSourceIDs)
+	if err != nil {
+		return fmt.Errorf("failed to list documents: %w", err)
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan document id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	ss.indexMu.Lock()
+	_, err = ss.databaseService.getDB().Exec(sqlClearSearchBlocks)
+	if err == nil {
+		_, err = ss.databaseService.getDB().Exec(sqlClearBlockLanguages)
+	}
+	ss.indexMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to clear search index: %w", err)
+	}
+
+	for i, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(i, len(ids), "")
+		}
+		if err := ss.indexDocument(id); err != nil {
+			return err
+		}
+	}
+	ss.logger.Info("Search index rebuilt", "documents", len(ids))
+	return nil
+}
+
+// handleDocumentChange 文档变更后更新索引
+func (ss *SearchService) handleDocumentChange(event models.DocumentChangeEvent) {
+	if event.Type == models.DocumentChangeLocked || event.Type == models.DocumentChangeTags || event.Type == models.DocumentChangeMoved ||
+		event.Type == models.DocumentChangePinned {
+		return
+	}
+	if err := ss.indexDocument(event.DocumentID); err != nil {
+		ss.logger.Error("Failed to update search index", "documentId", event.DocumentID, "error", err)
+	}
+}
+
+// indexDocument 按数据库中的最新内容重建单个文档的索引并识别文本块的自然语言，已删除的文档从索引中移除
+func (ss *SearchService) indexDocument(id int64) error {
+	ss.indexMu.Lock()
+	defer ss.indexMu.Unlock()
+
+	if ss.databaseService == nil || ss.databaseService.getDB() == nil {
+		return errors.New("database service not available")
+	}
+	if err := ss.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	var title, content string
+	var isDeleted int
+	err := ss.databaseService.getDB().QueryRow(sqlGetSearchSource, id).Scan(&title, &content, &isDeleted)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to read document: %w", err)
+	}
+
+	tx, err := ss.databaseService.getDB().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	base := id << searchBlockBits
+	if _, err := tx.Exec(sqlDeleteSearchDocument, base, base+searchMaxBlocks); err != nil {
+		return fmt.Errorf("failed to remove document from index: %w", err)
+	}
+
+	var parsed []blocks.Block
+	if isDeleted == 0 && title != "" {
+		indexed := 0
+		parsed = blocks.Parse(content)
+		for i, block := range parsed {
+			if i >= searchMaxBlocks {
+				break
+			}
+			if strings.TrimSpace(block.Content) == "" {
+				continue
+			}
+			if _, err := tx.Exec(sqlInsertSearchBlock, base+int64(i), title, block.Content, id, i, block.Language); err != nil {
+				return fmt.Errorf("failed to index document block: %w", err)
+			}
+			indexed++
+		}
+		// 空文档也保留一行，使其可以按标题搜索到
+		if indexed == 0 && len(parsed) > 0 {
+			if _, err := tx.Exec(sqlInsertSearchBlock, base, title, "", id, 0, parsed[0].Language); err != nil {
+				return fmt.Errorf("failed to index document block: %w", err)
+			}
+		}
+	}
+	if err := replaceBlockLanguages(context.Background(), tx, id, parsed); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// searchQuery 由用户输入生成的查询条件
+// trigram 分词器按子串匹配，至少三个字符的词走全文索引，更短的词（如两个字的中文词）用 LIKE 匹配
+type searchQuery struct {
+	match   string   // FTS5 查询，每个词加引号避免语法错误
+	likes   []string // 用 LIKE 匹配的短词
+	snippet bool     // 查询是否包含摘录列
+}
+
+// buildSearchQuery 将用户输入拆分为全文查询和 LIKE 短词
+func buildSearchQuery(query string) searchQuery {
+	search := searchQuery{snippet: true}
+	var quoted []string
+	for _, term := range strings.Fields(query) {
+		if utf8.RuneCountInString(term) < searchMinMatchRunes {
+			search.likes = append(search.likes, term)
+			continue
+		}
+		quoted = append(quoted, `"`+strings.ReplaceAll(term, `"`, `""`)+`"`)
+	}
+	search.match = strings.Join(quoted, " ")
+	return search
+}
+
+// empty 没有任何搜索词
+func (q searchQuery) empty() bool {
+	return q.match == "" && len(q.likes) == 0
+}
+
+// sql 填入摘录表达式和匹配条件；只有短词时全文索引无法定位匹配位置，摘录取整块内容，由 excerpt.Around 截取
+func (q searchQuery) sql(template string) string {
+	snippet := "search_blocks.content"
+	if q.match != "" {
+		snippet = "snippet(search_blocks, 1, char(57344), char(57345), '…', ?)"
+	}
+	var conditions []string
+	if q.match != "" {
+		conditions = append(conditions, "search_blocks MATCH ?")
+	}
+	for range q.likes {
+		conditions = append(conditions, `(search_blocks.title LIKE ? ESCAPE '\' OR search_blocks.content LIKE ? ESCAPE '\')`)
+	}
+	return fmt.Sprintf(template, snippet, strings.Join(conditions, " AND "))
+}
+
+// args 按 sql 中占位符的顺序组装参数，rest 为匹配条件之后的参数
+func (q searchQuery) args(rest ...any) []any {
+	var args []any
+	if q.snippet && q.match != "" {
+		args = append(args, searchSnippetTokens)
+	}
+	if q.match != "" {
+		args = append(args, q.match)
+	}
+	for _, term := range q.likes {
+		pattern := "%" + likeEscaper.Replace(term) + "%"
+		args = append(args, pattern, pattern)
+	}
+	return append(args, rest...)
+}
+
+// likeEscaper 转义 LIKE 模式中的通配符
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ServiceShutdown 服务关闭
+func (ss *SearchService) ServiceShutdown() error {
+	return nil
+}
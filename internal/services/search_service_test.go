@@ -0,0 +1,167 @@
+package services
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestBuildSearchQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		match string
+		likes []string
+	}{
+		{"", "", nil},
+		{"   ", "", nil},
+		{"foo", `"foo"`, nil},
+		{"foo bar", `"foo" "bar"`, nil},
+		{`say "hi" OR NOT`, `"say" """hi""" "NOT"`, []string{"OR"}},
+		{"测试 文本内容", `"文本内容"`, []string{"测试"}},
+	}
+
+	for _, tt := range tests {
+		got := buildSearchQuery(tt.query)
+		if got.match != tt.match || !reflect.DeepEqual(got.likes, tt.likes) {
+			t.Errorf("buildSearchQuery(%q) = %q %q, want %q %q", tt.query, got.match, got.likes, tt.match, tt.likes)
+		}
+	}
+}
+
+func TestSearchCJK(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	for _, query := range []string{sqlCreateDocumentsTable, sqlCreateBlockLanguagesTable, sqlCreateSearchBlocksTable} {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, doc := range []struct {
+		id             int64
+		title, content string
+	}{
+		{1, "笔记", "这是测试文本"},
+		{2, "Notes", "100% done with go"},
+	} {
+		if _, err := db.Exec(`INSERT INTO documents (id, title, created_at, updated_at) VALUES (?, ?, '', '')`, doc.id, doc.title); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(sqlInsertSearchBlock, doc.id<<searchBlockBits, doc.title, doc.content, doc.id, 0, "text"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		query   string
+		want    []int64
+		excerpt string
+	}{
+		{"测试", []int64{1}, "这是测试文本"},
+		{"测试文本", []int64{1}, "这是测试文本"},
+		{"文本 这是", []int64{1}, "这是测试文本"},
+		{"GO", []int64{2}, "100% done with go"},
+		{"0%", []int64{2}, "100% done with go"},
+		{"_", nil, ""},
+		{"没有", nil, ""},
+	}
+	for _, tt := range tests {
+		search := buildSearchQuery(tt.query)
+		rows, err := db.Query(search.sql(sqlSearchBlocks), search.args("", "", 10)...)
+		if err != nil {
+			t.Fatalf("search %q: %v", tt.query, err)
+		}
+		results, err := scanSearchResults(rows, search, false)
+		if err != nil {
+			t.Fatalf("search %q: %v", tt.query, err)
+		}
+		var ids []int64
+		for _, result := range results {
+			ids = append(ids, result.DocumentID)
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("search %q = %v, want %v", tt.query, ids, tt.want)
+			continue
+		}
+		if len(results) > 0 {
+			if results[0].Excerpt != tt.excerpt || len(results[0].Highlights) == 0 {
+				t.Errorf("search %q excerpt = %q %v, want %q with highlights", tt.query, results[0].Excerpt, results[0].Highlights, tt.excerpt)
+			}
+		}
+	}
+}
+
+// TestSearchDocumentsPaging 按文档分组分页，每个文档只出现一次，总数不受分页影响
+func TestSearchDocumentsPaging(t *testing.T) {
+	ds := newTestDocumentService(t)
+	ss := NewSearchService(ds.databaseService, ds, nil, nil)
+	createTestDocument(t, ds, "Default", "\n∞∞∞text-a\nnothing here")
+	multi := createTestDocument(t, ds, "Multi", "\n∞∞∞text-a\nneedle one\n∞∞∞text-a\nneedle two\n∞∞∞text-a\nneedle three")
+	want := map[int64]bool{multi: true}
+	for _, title := range []string{"b", "c", "d", "e"} {
+		want[createTestDocument(t, ds, title, "\n∞∞∞text-a\na needle in "+title)] = true
+	}
+	deleted := createTestDocument(t, ds, "Deleted", "\n∞∞∞text-a\nneedle deleted")
+	if err := ds.DeleteDocument(deleted); err != nil {
+		t.Fatal(err)
+	}
+	if err := ss.RebuildSearchIndex(); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[int64]bool{}
+	for offset, size := range map[int]int{0: 2, 2: 2, 4: 1} {
+		page, err := ss.SearchDocuments("needle", 2, offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != len(want) {
+			t.Errorf("offset %d: total = %d, want %d", offset, page.Total, len(want))
+		}
+		if len(page.Items) != size {
+			t.Errorf("offset %d: %d items, want %d", offset, len(page.Items), size)
+		}
+		for _, item := range page.Items {
+			if seen[item.DocumentID] {
+				t.Errorf("document %d returned twice", item.DocumentID)
+			}
+			seen[item.DocumentID] = true
+			wantMatches := 1
+			if item.DocumentID == multi {
+				wantMatches = 3
+			}
+			if item.Matches != wantMatches {
+				t.Errorf("document %d matches = %d, want %d", item.DocumentID, item.Matches, wantMatches)
+			}
+		}
+	}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("paged documents = %v, want %v", seen, want)
+	}
+
+	// 越过末尾返回空页但保留总数，负偏移按 0 处理
+	page, err := ss.SearchDocuments("needle", 2, len(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 0 || page.Total != len(want) {
+		t.Errorf("past end = %d items, total %d", len(page.Items), page.Total)
+	}
+	page, err = ss.SearchDocuments("needle", 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != len(want) || page.Total != len(want) {
+		t.Errorf("default limit = %d items, total %d", len(page.Items), page.Total)
+	}
+	page, err = ss.SearchDocuments("   ", 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 0 || page.Total != 0 {
+		t.Errorf("empty query = %d items, total %d", len(page.Items), page.Total)
+	}
+}
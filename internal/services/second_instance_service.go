@@ -0,0 +1,105 @@
+package services
+
+import (
+	"fmt"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// secondInstanceActionKey 第二个实例通过 AdditionalData 传递的行为
+const secondInstanceActionKey = "action"
+
+// SecondInstanceService 处理应用已在运行时再次启动
+// 命令行参数（如 --new-document）优先于设置中的行为
+type SecondInstanceService struct {
+	logger          *log.LogService
+	configService   *ConfigService
+	documentService *DocumentService
+	windowService   *WindowService
+	sessionService  *SessionService
+	windowHelper    *helper.WindowHelper
+}
+
+// NewSecondInstanceService 创建第二实例处理服务实例
+func NewSecondInstanceService(configService *ConfigService, documentService *DocumentService, windowService *WindowService, sessionService *SessionService, logger *log.LogService) *SecondInstanceService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &SecondInstanceService{
+		logger:          logger,
+		configService:   configService,
+		documentService: documentService,
+		windowService:   windowService,
+		sessionService:  sessionService,
+		windowHelper:    helper.NewWindowHelper(),
+	}
+}
+
+// HandleSecondInstance 执行第二个实例请求的行为，失败时退回到聚焦主窗口
+func (ss *SecondInstanceService) HandleSecondInstance(data application.SecondInstanceData) {
+	configured := models.SecondInstanceFocus
+	if config, err := ss.configService.GetConfig(); err == nil {
+		configured = config.General.SecondInstanceAction
+	} else {
+		ss.logger.Warning("Failed to get config for second instance", "error", err)
+	}
+
+	action := resolveSecondInstanceAction(data, configured)
+	if err := ss.run(action); err != nil {
+		ss.logger.Error("Second instance action failed", "action", action, "error", err)
+		ss.windowHelper.FocusMainWindow()
+	}
+}
+
+// run 执行行为
+func (ss *SecondInstanceService) run(action models.SecondInstanceAction) error {
+	switch action {
+	case models.SecondInstanceCapture:
+		return ss.openCaptureWindow()
+	case models.SecondInstanceNewDocument:
+		return ss.openNewDocument()
+	case models.SecondInstanceRestoreSession:
+		ss.windowHelper.FocusMainWindow()
+		return ss.sessionService.ReopenRecentWindows()
+	}
+	ss.windowHelper.FocusMainWindow()
+	return nil
+}
+
+// openCaptureWindow 在窗口中打开收集箱文档，未设置收集箱时新建文档
+func (ss *SecondInstanceService) openCaptureWindow() error {
+	config, err := ss.configService.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+	if documentID := config.Capture.InboxDocumentID; documentID != 0 {
+		return ss.windowService.OpenDocumentWindow(documentID)
+	}
+	return ss.openNewDocument()
+}
+
+// openNewDocument 新建文档并在窗口中打开，标题由内容生成
+func (ss *SecondInstanceService) openNewDocument() error {
+	doc, err := ss.documentService.CreateDocument("")
+	if err != nil {
+		return fmt.Errorf("failed to create document: %w", err)
+	}
+	return ss.windowService.OpenDocumentWindow(doc.ID)
+}
+
+// resolveSecondInstanceAction 依次取 AdditionalData、命令行参数和设置中的行为，都无效时聚焦主窗口
+func resolveSecondInstanceAction(data application.SecondInstanceData, configured models.SecondInstanceAction) models.SecondInstanceAction {
+	if action := models.SecondInstanceAction(data.AdditionalData[secondInstanceActionKey]); action.Valid() {
+		return action
+	}
+	if action := models.SecondInstanceActionFromArgs(data.Args); action != "" {
+		return action
+	}
+	if configured.Valid() {
+		return configured
+	}
+	return models.SecondInstanceFocus
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"testing"
+
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func TestResolveSecondInstanceAction(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       application.SecondInstanceData
+		configured models.SecondInstanceAction
+		want       models.SecondInstanceAction
+	}{
+		{"configured", application.SecondInstanceData{}, models.SecondInstanceCapture, models.SecondInstanceCapture},
+		{"additional data wins", application.SecondInstanceData{
+			Args:           []string{"voidraft", "--capture"},
+			AdditionalData: map[string]string{"action": "new-document"},
+		}, models.SecondInstanceFocus, models.SecondInstanceNewDocument},
+		{"args override config", application.SecondInstanceData{
+			Args: []string{"voidraft", "--restore-session"},
+		}, models.SecondInstanceCapture, models.SecondInstanceRestoreSession},
+		{"unknown values ignored", application.SecondInstanceData{
+			Args:           []string{"voidraft", "--bogus", "capture"},
+			AdditionalData: map[string]string{"action": ""},
+		}, "bogus", models.SecondInstanceFocus},
+	}
+	for _, tt := range tests {
+		if got := resolveSecondInstanceAction(tt.data, tt.configured); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
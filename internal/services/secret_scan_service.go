@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/common/secret"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// SecretWarning 敏感信息警告，随事件发送给前端
+type SecretWarning struct {
+	DocumentID int64            `json:"documentId"`
+	Findings   []secret.Finding `json:"findings"`
+}
+
+// SecretScanService 敏感信息检测服务
+type SecretScanService struct {
+	logger          *log.LogService
+	configService   *ConfigService
+	documentService *DocumentService
+}
+
+// NewSecretScanService 创建敏感信息检测服务
+func NewSecretScanService(configService *ConfigService, documentService *DocumentService, logger *log.LogService) *SecretScanService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	return &SecretScanService{
+		logger:          logger,
+		configService:   configService,
+		documentService: documentService,
+	}
+}
+
+// ServiceStartup 服务启动，订阅文档保存事件
+func (ss *SecretScanService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	ss.documentService.onDocumentChange(ss.handleDocumentChange)
+	return nil
+}
+
+// handleDocumentChange 文档内容保存后检测敏感信息并发出警告
+func (ss *SecretScanService) handleDocumentChange(event models.DocumentChangeEvent) {
+	if event.Type != models.DocumentChangeContent || !ss.detectionEnabled() {
+		return
+	}
+
+	findings := secret.Scan(event.Content)
+	if len(findings) == 0 {
+		return
+	}
+
+	ss.logger.Info("Possible secrets detected in document", "documentId", event.DocumentID, "count", len(findings))
+	helper.EmitEvent(constant.EVENT_SECRETS_DETECTED, SecretWarning{
+		DocumentID: event.DocumentID,
+		Findings:   findings,
+	})
+}
+
+// ScanDocument 检测文档中的疑似敏感信息
+func (ss *SecretScanService) ScanDocument(documentID int64) ([]secret.Finding, error) {
+	doc, err := ss.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("document not found: %d", documentID)
+	}
+	return secret.Scan(doc.Content), nil
+}
+
+// ScanText 检测文本中的疑似敏感信息
+func (ss *SecretScanService) ScanText(text string) []secret.Finding {
+	return secret.Scan(text)
+}
+
+// MaskSecrets 对文本中的疑似敏感信息进行脱敏
+func (ss *SecretScanService) MaskSecrets(text string) string {
+	return secret.Mask(text)
+}
+
+// maskForExport 按配置在导出和分享前脱敏，供导出类服务调用
+func (ss *SecretScanService) maskForExport(content string) string {
+	return maskSecretsForExport(ss.configService, content)
+}
+
+// maskSecretsForExport 按配置遮盖敏感信息，供不依赖检测服务的批量导出使用
+func maskSecretsForExport(configService *ConfigService, content string) string {
+	config, err := configService.GetConfig()
+	if err != nil || !config.Security.MaskSecretsInExports {
+		return content
+	}
+	return secret.Mask(content)
+}
+
+// detectionEnabled 是否启用保存时检测
+func (ss *SecretScanService) detectionEnabled() bool {
+	config, err := ss.configService.GetConfig()
+	if err != nil {
+		return false
+	}
+	return config.Security.SecretDetection
+}
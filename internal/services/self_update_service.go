@@ -10,9 +10,9 @@ import (
 	"time"
 
 	"github.com/creativeprojects/go-selfupdate"
-	"github.com/wailsapp/wails/v3/pkg/services/dock"
 	"github.com/wailsapp/wails/v3/pkg/services/log"
 	"github.com/wailsapp/wails/v3/pkg/services/notifications"
+	"voidraft/internal/common/updatesource"
 	"voidraft/internal/models"
 )
 
@@ -30,23 +30,23 @@ type SelfUpdateResult struct {
 
 // SelfUpdateService 自我更新服务
 type SelfUpdateService struct {
-	logger              *log.LogService
-	configService       *ConfigService
-	badgeService        *dock.DockService
-	notificationService *notifications.NotificationService
+	logger             *log.LogService
+	configService      *ConfigService
+	badge              *appBadge
+	notificationCenter *NotificationCenterService
 
 	mu         sync.Mutex // 保护更新状态
 	isUpdating bool
 }
 
 // NewSelfUpdateService 创建自我更新服务实例
-func NewSelfUpdateService(configService *ConfigService, badgeService *dock.DockService, notificationService *notifications.NotificationService, logger *log.LogService) *SelfUpdateService {
+func NewSelfUpdateService(configService *ConfigService, badge *appBadge, notificationCenter *NotificationCenterService, logger *log.LogService) *SelfUpdateService {
 	return &SelfUpdateService{
-		logger:              logger,
-		configService:       configService,
-		badgeService:        badgeService,
-		notificationService: notificationService,
-		isUpdating:          false,
+		logger:             logger,
+		configService:      configService,
+		badge:              badge,
+		notificationCenter: notificationCenter,
+		isUpdating:         false,
 	}
 }
 
@@ -59,13 +59,62 @@ func (s *SelfUpdateService) getConfig() (*models.AppConfig, error) {
 	return config, nil
 }
 
-// CheckForUpdates 检查更新
+// errUpdatesOffline 离线模式下拒绝访问更新源
+var errUpdatesOffline = errors.New("updates are disabled in offline mode")
+
+// effectiveUpdateMode 计算实际生效的更新模式，兼容旧版 autoUpdate 开关
+func effectiveUpdateMode(updates models.UpdatesConfig) models.UpdateMode {
+	mode := updates.Mode
+	switch mode {
+	case models.UpdateModeOffline, models.UpdateModeManual:
+		return mode
+	case models.UpdateModeAuto, models.UpdateModeNotify:
+	default:
+		mode = models.UpdateModeNotify
+	}
+	if !updates.AutoUpdate {
+		return models.UpdateModeManual
+	}
+	return mode
+}
+
+// GetUpdateMode 获取实际生效的更新模式
+func (s *SelfUpdateService) GetUpdateMode() (models.UpdateMode, error) {
+	config, err := s.getConfig()
+	if err != nil {
+		return "", err
+	}
+	return effectiveUpdateMode(config.Updates), nil
+}
+
+// CheckOnStartup 启动时检查更新，manual/offline 模式下不联网，返回 nil
+func (s *SelfUpdateService) CheckOnStartup(ctx context.Context) (*SelfUpdateResult, error) {
+	config, err := s.getConfig()
+	if err != nil {
+		return nil, err
+	}
+	switch effectiveUpdateMode(config.Updates) {
+	case models.UpdateModeAuto, models.UpdateModeNotify:
+		return s.checkForUpdates(ctx, config)
+	default:
+		return nil, nil
+	}
+}
+
+// CheckForUpdates 手动检查更新，仅离线模式下拒绝
 func (s *SelfUpdateService) CheckForUpdates(ctx context.Context) (*SelfUpdateResult, error) {
 	config, err := s.getConfig()
 	if err != nil {
 		return nil, err
 	}
+	if effectiveUpdateMode(config.Updates) == models.UpdateModeOffline {
+		return nil, errUpdatesOffline
+	}
+	return s.checkForUpdates(ctx, config)
+}
 
+// checkForUpdates 依次尝试主要源和备用源
+func (s *SelfUpdateService) checkForUpdates(ctx context.Context, config *models.AppConfig) (*SelfUpdateResult, error) {
 	result := &SelfUpdateResult{
 		CurrentVersion: config.Updates.Version,
 		HasUpdate:      false,
@@ -136,16 +185,14 @@ func (s *SelfUpdateService) checkSourceForUpdates(ctx context.Context, sourceTyp
 	return result, nil
 }
 
-// createGithubUpdater 创建GitHub更新器
+// createGithubUpdater 创建GitHub更新器，使用不携带令牌和身份信息的匿名源
 func (s *SelfUpdateService) createGithubUpdater() (*selfupdate.Updater, error) {
-	return selfupdate.NewUpdater(selfupdate.Config{})
+	return selfupdate.NewUpdater(selfupdate.Config{Source: updatesource.GitHub("")})
 }
 
-// createGiteaUpdater 创建Gitea更新器
+// createGiteaUpdater 创建Gitea更新器，使用不携带令牌和身份信息的匿名源
 func (s *SelfUpdateService) createGiteaUpdater(config *models.AppConfig) (*selfupdate.Updater, error) {
-	source, err := selfupdate.NewGiteaSource(selfupdate.GiteaConfig{
-		BaseURL: config.Updates.Gitea.BaseURL,
-	})
+	source, err := updatesource.Gitea(config.Updates.Gitea.BaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("create gitea source failed: %w", err)
 	}
@@ -195,6 +242,9 @@ func (s *SelfUpdateService) ApplyUpdate(ctx context.Context) (*SelfUpdateResult,
 	if err != nil {
 		return nil, err
 	}
+	if effectiveUpdateMode(config.Updates) == models.UpdateModeOffline {
+		return nil, errUpdatesOffline
+	}
 
 	exe, err := selfupdate.ExecutablePath()
 	if err != nil {
@@ -311,9 +361,7 @@ func (s *SelfUpdateService) handleUpdateSuccess(result *SelfUpdateResult) {
 	}
 
 	// 移除badge
-	if s.badgeService != nil {
-		s.badgeService.RemoveBadge()
-	}
+	s.badge.set(badgeSourceUpdate, false)
 }
 
 // createBackup 创建可执行文件备份
@@ -347,17 +395,13 @@ func (s *SelfUpdateService) RestartApplication() error {
 // handleUpdateBadge 处理更新徽章和通知
 func (s *SelfUpdateService) handleUpdateBadge(result *SelfUpdateResult) {
 	if result == nil || !result.HasUpdate {
-		if s.badgeService != nil {
-			s.badgeService.RemoveBadge()
-		}
+		s.badge.set(badgeSourceUpdate, false)
 		return
 	}
 
 	// 显示徽章
-	if s.badgeService != nil {
-		if err := s.badgeService.SetBadge("●"); err != nil {
-			s.logger.Error("set badge failed", "error", err)
-		}
+	if err := s.badge.set(badgeSourceUpdate, true); err != nil {
+		s.logger.Error("set badge failed", "error", err)
 	}
 
 	// 发送通知
@@ -366,21 +410,7 @@ func (s *SelfUpdateService) handleUpdateBadge(result *SelfUpdateResult) {
 
 // sendUpdateNotification 发送更新通知
 func (s *SelfUpdateService) sendUpdateNotification(result *SelfUpdateResult) {
-	if s.notificationService == nil {
-		return
-	}
-
-	// 检查授权
-	authorized, err := s.notificationService.CheckNotificationAuthorization()
-	if err != nil || !authorized {
-		authorized, err = s.notificationService.RequestNotificationAuthorization()
-		if err != nil || !authorized {
-			return
-		}
-	}
-
-	// 发送通知
-	s.notificationService.SendNotification(notifications.NotificationOptions{
+	s.notificationCenter.notify(models.NotificationCategoryUpdate, notifications.NotificationOptions{
 		ID:       "update_available",
 		Title:    "Voidraft Update Available",
 		Subtitle: "New version available",
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"testing"
+
+	"voidraft/internal/models"
+)
+
+func TestEffectiveUpdateMode(t *testing.T) {
+	tests := []struct {
+		mode       models.UpdateMode
+		autoUpdate bool
+		want       models.UpdateMode
+	}{
+		{models.UpdateModeAuto, true, models.UpdateModeAuto},
+		{models.UpdateModeNotify, true, models.UpdateModeNotify},
+		{models.UpdateModeAuto, false, models.UpdateModeManual},
+		{models.UpdateModeNotify, false, models.UpdateModeManual},
+		{models.UpdateModeManual, true, models.UpdateModeManual},
+		{models.UpdateModeOffline, true, models.UpdateModeOffline},
+		{models.UpdateModeOffline, false, models.UpdateModeOffline},
+		{"", true, models.UpdateModeNotify},
+		{"bogus", false, models.UpdateModeManual},
+	}
+	for _, tt := range tests {
+		got := effectiveUpdateMode(models.UpdatesConfig{Mode: tt.mode, AutoUpdate: tt.autoUpdate})
+		if got != tt.want {
+			t.Errorf("effectiveUpdateMode(%q, %v) = %q, want %q", tt.mode, tt.autoUpdate, got, tt.want)
+		}
+	}
+}
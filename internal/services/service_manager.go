@@ -1,6 +1,8 @@
 package services
 
 import (
+	"net/http"
+
 	"github.com/wailsapp/wails/v3/pkg/application"
 	"github.com/wailsapp/wails/v3/pkg/services/dock"
 	"github.com/wailsapp/wails/v3/pkg/services/log"
@@ -9,28 +11,69 @@ import (
 
 // ServiceManager 服务管理器，负责协调各个服务
 type ServiceManager struct {
-	configService       *ConfigService
-	databaseService     *DatabaseService
-	documentService     *DocumentService
-	windowService       *WindowService
-	windowSnapService   *WindowSnapService
-	migrationService    *MigrationService
-	systemService       *SystemService
-	hotkeyService       *HotkeyService
-	dialogService       *DialogService
-	trayService         *TrayService
-	keyBindingService   *KeyBindingService
-	extensionService    *ExtensionService
-	startupService      *StartupService
-	selfUpdateService   *SelfUpdateService
-	translationService  *TranslationService
-	themeService        *ThemeService
-	badgeService        *dock.DockService
-	notificationService *notifications.NotificationService
-	testService         *TestService // 测试服务（仅开发环境）
-	BackupService       *BackupService
-	httpClientService   *HttpClientService // HTTP客户端服务
-	logger              *log.LogService
+	configService             *ConfigService
+	networkService            *NetworkService
+	databaseService           *DatabaseService
+	documentService           *DocumentService
+	jobService                *JobService
+	windowService             *WindowService
+	windowSnapService         *WindowSnapService
+	migrationService          *MigrationService
+	systemService             *SystemService
+	hotkeyService             *HotkeyService
+	dialogService             *DialogService
+	trayService               *TrayService
+	keyBindingService         *KeyBindingService
+	extensionService          *ExtensionService
+	startupService            *StartupService
+	selfUpdateService         *SelfUpdateService
+	translationService        *TranslationService
+	themeService              *ThemeService
+	badgeService              *dock.DockService
+	notificationService       *notifications.NotificationService
+	notificationCenterService *NotificationCenterService
+	testService               *TestService // 测试服务（仅开发环境）
+	BackupService             *BackupService
+	httpClientService         *HttpClientService // HTTP客户端服务
+	integrationService        *IntegrationService
+	timeTrackingService       *TimeTrackingService
+	secretScanService         *SecretScanService
+	appLockService            *AppLockService
+	exportService             *ExportService
+	localServerService        *LocalServerService
+	shareService              *ShareService
+	apiService                *ApiService
+	externalEditorService     *ExternalEditorService
+	pluginProtocolService     *PluginProtocolService
+	searchService             *SearchService
+	attachmentService         *AttachmentService
+	accessibilityService      *AccessibilityService
+	quickTranslateService     *QuickTranslateService
+	sessionService            *SessionService
+	secondInstanceService     *SecondInstanceService
+	markdownService           *MarkdownService
+	tableService              *TableService
+	dataFormatService         *DataFormatService
+	timestampService          *TimestampService
+	checksumService           *ChecksumService
+	qrCodeService             *QRCodeService
+	totpService               *TOTPService
+	publishService            *PublishService
+	ansiService               *AnsiService
+	stackTraceService         *StackTraceService
+	logToolService            *LogToolService
+	galleryService            *GalleryService
+	whatsNewService           *WhatsNewService
+	dataMigrationService      *DataMigrationService
+	clipboardCaptureService   *ClipboardCaptureService
+	retentionService          *RetentionService
+	dashboardService          *DashboardService
+	hookService               *HookService
+	statsService              *StatsService
+	taskbarProgressService    *TaskbarProgressService
+	patchService              *PatchService
+	commandService            *CommandService
+	logger                    *log.LogService
 }
 
 // NewServiceManager 创建新的服务管理器实例
@@ -40,6 +83,8 @@ func NewServiceManager() *ServiceManager {
 
 	// 初始化badge服务
 	badgeService := dock.New()
+	// 更新提示和未保存提示共用徽章
+	badge := newAppBadge(badgeService)
 
 	// 初始化通知服务
 	notificationService := notifications.New()
@@ -47,20 +92,38 @@ func NewServiceManager() *ServiceManager {
 	// 初始化配置服务
 	configService := NewConfigService(logger)
 
+	// 初始化网络设置服务，先于其他服务启动，使对外请求使用设置的代理和证书
+	networkService := NewNetworkService(configService, logger)
+
+	// 初始化通知中心
+	notificationCenterService := NewNotificationCenterService(configService, notificationService, logger)
+
 	// 初始化数据库服务
 	databaseService := NewDatabaseService(configService, logger)
 
 	// 初始化迁移服务
 	migrationService := NewMigrationService(databaseService, logger)
 
+	// 初始化应用锁服务
+	appLockService := NewAppLockService(configService, logger)
+
 	// 初始化文档服务
-	documentService := NewDocumentService(databaseService, logger)
+	documentService := NewDocumentService(configService, databaseService, appLockService, logger)
+
+	// 初始化后台任务服务
+	jobService := NewJobService(databaseService, logger)
+
+	// 初始化辅助功能偏好服务
+	accessibilityService := NewAccessibilityService(configService, logger)
 
 	// 初始化窗口吸附服务
-	windowSnapService := NewWindowSnapService(logger, configService)
+	windowSnapService := NewWindowSnapService(logger, configService, accessibilityService)
+
+	// 初始化托盘服务
+	trayService := NewTrayService(logger, configService)
 
 	// 初始化窗口服务
-	windowService := NewWindowService(logger, documentService, windowSnapService)
+	windowService := NewWindowService(logger, documentService, windowSnapService, configService, trayService, badge)
 
 	// 初始化系统服务
 	systemService := NewSystemService(logger)
@@ -71,9 +134,6 @@ func NewServiceManager() *ServiceManager {
 	// 初始化对话服务
 	dialogService := NewDialogService(logger)
 
-	// 初始化托盘服务
-	trayService := NewTrayService(logger, configService)
-
 	// 初始化快捷键服务
 	keyBindingService := NewKeyBindingService(databaseService, logger)
 
@@ -84,55 +144,211 @@ func NewServiceManager() *ServiceManager {
 	startupService := NewStartupService(configService, logger)
 
 	// 初始化自我更新服务
-	selfUpdateService := NewSelfUpdateService(configService, badgeService, notificationService, logger)
+	selfUpdateService := NewSelfUpdateService(configService, badge, notificationCenterService, logger)
 
 	// 初始化翻译服务
-	translationService := NewTranslationService(logger)
+	translationService := NewTranslationService(configService, documentService, logger)
 
 	// 初始化主题服务
 	themeService := NewThemeService(databaseService, logger)
 
 	// 初始化备份服务
-	backupService := NewBackupService(configService, databaseService, logger)
+	backupService := NewBackupService(configService, databaseService, notificationCenterService, logger)
 
 	// 初始化HTTP客户端服务
 	httpClientService := NewHttpClientService(logger)
 
+	// 初始化第三方集成服务
+	integrationService := NewIntegrationService(configService, documentService, logger)
+
+	// 初始化文档计时服务
+	timeTrackingService := NewTimeTrackingService(databaseService, logger)
+
+	// 初始化敏感信息检测服务
+	secretScanService := NewSecretScanService(configService, documentService, logger)
+
+	// 初始化导入导出服务
+	exportService := NewExportService(documentService, secretScanService, jobService, themeService, configService, logger)
+
+	// 初始化本地HTTP服务
+	localServerService := NewLocalServerService(configService, logger)
+
+	// 初始化分享服务
+	shareService := NewShareService(localServerService, documentService, secretScanService, configService, logger)
+
+	// 初始化本地API服务
+	apiService := NewApiService(localServerService, databaseService, documentService, logger)
+
+	// 初始化外部编辑器服务
+	externalEditorService := NewExternalEditorService(configService, documentService, logger)
+
+	// 初始化全文搜索服务
+	searchService := NewSearchService(databaseService, documentService, jobService, logger)
+
+	// 初始化编辑器插件协议服务
+	pluginProtocolService := NewPluginProtocolService(localServerService, apiService, documentService, searchService, logger)
+
+	// 初始化附件服务
+	attachmentService := NewAttachmentService(configService, databaseService, appLockService, logger)
+
+	// 初始化剪贴板快速翻译服务
+	quickTranslateService := NewQuickTranslateService(configService, translationService, hotkeyService, notificationService, logger)
+
+	// 初始化会话恢复服务
+	sessionService := NewSessionService(configService, databaseService, documentService, windowService, logger)
+
+	// 初始化第二实例处理服务，由单实例回调调用，不需要绑定到前端
+	secondInstanceService := NewSecondInstanceService(configService, documentService, windowService, sessionService, logger)
+
+	// 初始化Markdown渲染服务
+	markdownService := NewMarkdownService(documentService, logger)
+
+	// 初始化表格块服务
+	tableService := NewTableService(logger)
+
+	// 初始化格式转换服务
+	dataFormatService := NewDataFormatService(logger)
+
+	// 初始化时间戳服务
+	timestampService := NewTimestampService(logger)
+
+	// 初始化文件校验和服务
+	checksumService := NewChecksumService(documentService, jobService, logger)
+
+	// 初始化二维码服务
+	qrCodeService := NewQRCodeService(logger)
+
+	// 初始化一次性密码服务
+	totpService := NewTOTPService(configService, appLockService, logger)
+
+	// 初始化发布服务
+	publishService := NewPublishService(configService, documentService, exportService, jobService, logger)
+
+	// 初始化终端输出导入服务
+	ansiService := NewAnsiService(documentService, logger)
+
+	// 初始化堆栈识别服务
+	stackTraceService := NewStackTraceService(documentService, logger)
+
+	// 初始化日志整理服务
+	logToolService := NewLogToolService(documentService, logger)
+
+	// 初始化模板库服务
+	galleryService := NewGalleryService(configService, documentService, themeService, logger)
+
+	// 初始化更新说明服务
+	whatsNewService := NewWhatsNewService(configService, notificationCenterService, logger)
+
+	// 初始化数据迁移服务（由数据库服务在打开数据库时执行）
+	dataMigrationService := databaseService.dataMigrations
+
+	// 初始化后台粘贴服务
+	clipboardCaptureService := NewClipboardCaptureService(configService, documentService, windowService, hotkeyService, logger)
+
+	// 初始化文档保留策略服务
+	retentionService := NewRetentionService(configService, documentService, jobService, logger)
+
+	// 初始化统计面板服务
+	dashboardService := NewDashboardService(databaseService, documentService, backupService, logger)
+
+	// 初始化事件命令服务
+	hookService := NewHookService(configService, documentService, jobService, externalEditorService, logger)
+
+	// 初始化文档统计服务
+	statsService := NewStatsService(databaseService, documentService, logger)
+
+	// 初始化任务栏进度服务
+	taskbarProgressService := NewTaskbarProgressService(jobService, badge, logger)
+
+	// 初始化补丁服务
+	patchService := NewPatchService(logger)
+
+	// 初始化命令面板服务
+	commandService := NewCommandService(configService, documentService, logger)
+
 	// 初始化测试服务（开发环境使用）
 	testService := NewTestService(badgeService, notificationService, logger)
 
 	return &ServiceManager{
-		configService:       configService,
-		databaseService:     databaseService,
-		documentService:     documentService,
-		windowSnapService:   windowSnapService,
-		windowService:       windowService,
-		migrationService:    migrationService,
-		systemService:       systemService,
-		hotkeyService:       hotkeyService,
-		dialogService:       dialogService,
-		trayService:         trayService,
-		keyBindingService:   keyBindingService,
-		extensionService:    extensionService,
-		startupService:      startupService,
-		selfUpdateService:   selfUpdateService,
-		translationService:  translationService,
-		themeService:        themeService,
-		badgeService:        badgeService,
-		notificationService: notificationService,
-		testService:         testService,
-		BackupService:       backupService,
-		httpClientService:   httpClientService,
-		logger:              logger,
+		configService:             configService,
+		networkService:            networkService,
+		databaseService:           databaseService,
+		documentService:           documentService,
+		jobService:                jobService,
+		windowSnapService:         windowSnapService,
+		windowService:             windowService,
+		migrationService:          migrationService,
+		systemService:             systemService,
+		hotkeyService:             hotkeyService,
+		dialogService:             dialogService,
+		trayService:               trayService,
+		keyBindingService:         keyBindingService,
+		extensionService:          extensionService,
+		startupService:            startupService,
+		selfUpdateService:         selfUpdateService,
+		translationService:        translationService,
+		themeService:              themeService,
+		badgeService:              badgeService,
+		notificationService:       notificationService,
+		notificationCenterService: notificationCenterService,
+		testService:               testService,
+		BackupService:             backupService,
+		httpClientService:         httpClientService,
+		integrationService:        integrationService,
+		timeTrackingService:       timeTrackingService,
+		secretScanService:         secretScanService,
+		appLockService:            appLockService,
+		exportService:             exportService,
+		localServerService:        localServerService,
+		shareService:              shareService,
+		apiService:                apiService,
+		externalEditorService:     externalEditorService,
+		pluginProtocolService:     pluginProtocolService,
+		searchService:             searchService,
+		attachmentService:         attachmentService,
+		accessibilityService:      accessibilityService,
+		quickTranslateService:     quickTranslateService,
+		sessionService:            sessionService,
+		secondInstanceService:     secondInstanceService,
+		markdownService:           markdownService,
+		tableService:              tableService,
+		dataFormatService:         dataFormatService,
+		timestampService:          timestampService,
+		checksumService:           checksumService,
+		qrCodeService:             qrCodeService,
+		totpService:               totpService,
+		publishService:            publishService,
+		ansiService:               ansiService,
+		stackTraceService:         stackTraceService,
+		logToolService:            logToolService,
+		galleryService:            galleryService,
+		whatsNewService:           whatsNewService,
+		dataMigrationService:      dataMigrationService,
+		clipboardCaptureService:   clipboardCaptureService,
+		retentionService:          retentionService,
+		dashboardService:          dashboardService,
+		hookService:               hookService,
+		statsService:              statsService,
+		taskbarProgressService:    taskbarProgressService,
+		patchService:              patchService,
+		commandService:            commandService,
+		logger:                    logger,
 	}
 }
 
+// AssetMiddleware 资源处理器中间件，在前端资源之外提供附件等本地资源
+func (sm *ServiceManager) AssetMiddleware() func(http.Handler) http.Handler {
+	return sm.attachmentService.middleware
+}
+
 // GetServices 获取所有wails服务列表
 func (sm *ServiceManager) GetServices() []application.Service {
 	services := []application.Service{
 		application.NewService(sm.configService),
+		application.NewService(sm.networkService),
 		application.NewService(sm.databaseService),
 		application.NewService(sm.documentService),
+		application.NewService(sm.jobService),
 		application.NewService(sm.windowService),
 		application.NewService(sm.keyBindingService),
 		application.NewService(sm.extensionService),
@@ -142,6 +358,7 @@ func (sm *ServiceManager) GetServices() []application.Service {
 		application.NewService(sm.dialogService),
 		application.NewService(sm.trayService),
 		application.NewService(sm.startupService),
+		application.NewService(sm.notificationCenterService),
 		application.NewService(sm.selfUpdateService),
 		application.NewService(sm.translationService),
 		application.NewService(sm.themeService),
@@ -150,6 +367,43 @@ func (sm *ServiceManager) GetServices() []application.Service {
 		application.NewService(sm.testService),
 		application.NewService(sm.BackupService),
 		application.NewService(sm.httpClientService),
+		application.NewService(sm.integrationService),
+		application.NewService(sm.timeTrackingService),
+		application.NewService(sm.secretScanService),
+		application.NewService(sm.appLockService),
+		application.NewService(sm.exportService),
+		application.NewService(sm.localServerService),
+		application.NewService(sm.shareService),
+		application.NewService(sm.apiService),
+		application.NewService(sm.externalEditorService),
+		application.NewService(sm.pluginProtocolService),
+		application.NewService(sm.searchService),
+		application.NewService(sm.attachmentService),
+		application.NewService(sm.accessibilityService),
+		application.NewService(sm.quickTranslateService),
+		application.NewService(sm.sessionService),
+		application.NewService(sm.markdownService),
+		application.NewService(sm.tableService),
+		application.NewService(sm.dataFormatService),
+		application.NewService(sm.timestampService),
+		application.NewService(sm.checksumService),
+		application.NewService(sm.qrCodeService),
+		application.NewService(sm.totpService),
+		application.NewService(sm.publishService),
+		application.NewService(sm.ansiService),
+		application.NewService(sm.stackTraceService),
+		application.NewService(sm.logToolService),
+		application.NewService(sm.galleryService),
+		application.NewService(sm.whatsNewService),
+		application.NewService(sm.dataMigrationService),
+		application.NewService(sm.clipboardCaptureService),
+		application.NewService(sm.retentionService),
+		application.NewService(sm.dashboardService),
+		application.NewService(sm.hookService),
+		application.NewService(sm.statsService),
+		application.NewService(sm.taskbarProgressService),
+		application.NewService(sm.patchService),
+		application.NewService(sm.commandService),
 	}
 	return services
 }
@@ -169,6 +423,11 @@ func (sm *ServiceManager) GetLogger() *log.LogService {
 	return sm.logger
 }
 
+// GetNetworkService 获取网络设置服务实例
+func (sm *ServiceManager) GetNetworkService() *NetworkService {
+	return sm.networkService
+}
+
 // GetConfigService 获取配置服务实例
 func (sm *ServiceManager) GetConfigService() *ConfigService {
 	return sm.configService
@@ -209,6 +468,11 @@ func (sm *ServiceManager) GetDatabaseService() *DatabaseService {
 	return sm.databaseService
 }
 
+// GetSecondInstanceService 获取第二实例处理服务实例
+func (sm *ServiceManager) GetSecondInstanceService() *SecondInstanceService {
+	return sm.secondInstanceService
+}
+
 // GetWindowService 获取窗口服务实例
 func (sm *ServiceManager) GetWindowService() *WindowService {
 	return sm.windowService
@@ -243,3 +507,198 @@ func (sm *ServiceManager) GetSystemService() *SystemService {
 func (sm *ServiceManager) GetHttpClientService() *HttpClientService {
 	return sm.httpClientService
 }
+
+// GetIntegrationService 获取第三方集成服务
+func (sm *ServiceManager) GetIntegrationService() *IntegrationService {
+	return sm.integrationService
+}
+
+// GetTimeTrackingService 获取文档计时服务实例
+func (sm *ServiceManager) GetTimeTrackingService() *TimeTrackingService {
+	return sm.timeTrackingService
+}
+
+// GetSecretScanService 获取敏感信息检测服务实例
+func (sm *ServiceManager) GetSecretScanService() *SecretScanService {
+	return sm.secretScanService
+}
+
+// GetAppLockService 获取应用锁服务实例
+func (sm *ServiceManager) GetAppLockService() *AppLockService {
+	return sm.appLockService
+}
+
+// GetExportService 获取导入导出服务实例
+func (sm *ServiceManager) GetExportService() *ExportService {
+	return sm.exportService
+}
+
+// GetLocalServerService 获取本地HTTP服务实例
+func (sm *ServiceManager) GetLocalServerService() *LocalServerService {
+	return sm.localServerService
+}
+
+// GetShareService 获取分享服务实例
+func (sm *ServiceManager) GetShareService() *ShareService {
+	return sm.shareService
+}
+
+// GetApiService 获取本地API服务实例
+func (sm *ServiceManager) GetApiService() *ApiService {
+	return sm.apiService
+}
+
+// GetExternalEditorService 获取外部编辑器服务实例
+func (sm *ServiceManager) GetExternalEditorService() *ExternalEditorService {
+	return sm.externalEditorService
+}
+
+// GetPluginProtocolService 获取编辑器插件协议服务实例
+func (sm *ServiceManager) GetPluginProtocolService() *PluginProtocolService {
+	return sm.pluginProtocolService
+}
+
+// GetSearchService 获取全文搜索服务实例
+func (sm *ServiceManager) GetSearchService() *SearchService {
+	return sm.searchService
+}
+
+// GetJobService 获取后台任务服务实例
+func (sm *ServiceManager) GetJobService() *JobService {
+	return sm.jobService
+}
+
+// GetAttachmentService 获取附件服务实例
+func (sm *ServiceManager) GetAttachmentService() *AttachmentService {
+	return sm.attachmentService
+}
+
+// GetAccessibilityService 获取辅助功能偏好服务实例
+func (sm *ServiceManager) GetAccessibilityService() *AccessibilityService {
+	return sm.accessibilityService
+}
+
+// GetQuickTranslateService 获取剪贴板快速翻译服务实例
+func (sm *ServiceManager) GetQuickTranslateService() *QuickTranslateService {
+	return sm.quickTranslateService
+}
+
+// GetNotificationCenterService 获取通知中心实例
+func (sm *ServiceManager) GetNotificationCenterService() *NotificationCenterService {
+	return sm.notificationCenterService
+}
+
+// GetSessionService 获取会话恢复服务实例
+func (sm *ServiceManager) GetSessionService() *SessionService {
+	return sm.sessionService
+}
+
+// GetMarkdownService 获取Markdown渲染服务实例
+func (sm *ServiceManager) GetMarkdownService() *MarkdownService {
+	return sm.markdownService
+}
+
+// GetTableService 获取表格块服务实例
+func (sm *ServiceManager) GetTableService() *TableService {
+	return sm.tableService
+}
+
+// GetDataFormatService 获取格式转换服务实例
+func (sm *ServiceManager) GetDataFormatService() *DataFormatService {
+	return sm.dataFormatService
+}
+
+// GetTimestampService 获取时间戳服务实例
+func (sm *ServiceManager) GetTimestampService() *TimestampService {
+	return sm.timestampService
+}
+
+// GetChecksumService 获取文件校验和服务实例
+func (sm *ServiceManager) GetChecksumService() *ChecksumService {
+	return sm.checksumService
+}
+
+// GetQRCodeService 获取二维码服务实例
+func (sm *ServiceManager) GetQRCodeService() *QRCodeService {
+	return sm.qrCodeService
+}
+
+// GetTOTPService 获取一次性密码服务实例
+func (sm *ServiceManager) GetTOTPService() *TOTPService {
+	return sm.totpService
+}
+
+// GetPublishService 获取发布服务实例
+func (sm *ServiceManager) GetPublishService() *PublishService {
+	return sm.publishService
+}
+
+// GetAnsiService 获取终端输出导入服务实例
+func (sm *ServiceManager) GetAnsiService() *AnsiService {
+	return sm.ansiService
+}
+
+// GetStackTraceService 获取堆栈识别服务实例
+func (sm *ServiceManager) GetStackTraceService() *StackTraceService {
+	return sm.stackTraceService
+}
+
+// GetLogToolService 获取日志整理服务实例
+func (sm *ServiceManager) GetLogToolService() *LogToolService {
+	return sm.logToolService
+}
+
+// GetGalleryService 获取模板库服务实例
+func (sm *ServiceManager) GetGalleryService() *GalleryService {
+	return sm.galleryService
+}
+
+// GetWhatsNewService 获取更新说明服务实例
+func (sm *ServiceManager) GetWhatsNewService() *WhatsNewService {
+	return sm.whatsNewService
+}
+
+// GetDataMigrationService 获取数据迁移服务实例
+func (sm *ServiceManager) GetDataMigrationService() *DataMigrationService {
+	return sm.dataMigrationService
+}
+
+// GetClipboardCaptureService 获取后台粘贴服务实例
+func (sm *ServiceManager) GetClipboardCaptureService() *ClipboardCaptureService {
+	return sm.clipboardCaptureService
+}
+
+// GetRetentionService 获取文档保留策略服务实例
+func (sm *ServiceManager) GetRetentionService() *RetentionService {
+	return sm.retentionService
+}
+
+// GetDashboardService 获取统计面板服务实例
+func (sm *ServiceManager) GetDashboardService() *DashboardService {
+	return sm.dashboardService
+}
+
+// GetHookService 获取事件命令服务实例
+func (sm *ServiceManager) GetHookService() *HookService {
+	return sm.hookService
+}
+
+// GetStatsService 获取文档统计服务实例
+func (sm *ServiceManager) GetStatsService() *StatsService {
+	return sm.statsService
+}
+
+// GetTaskbarProgressService 获取任务栏进度服务实例
+func (sm *ServiceManager) GetTaskbarProgressService() *TaskbarProgressService {
+	return sm.taskbarProgressService
+}
+
+// GetPatchService 获取补丁服务实例
+func (sm *ServiceManager) GetPatchService() *PatchService {
+	return sm.patchService
+}
+
+// GetCommandService 获取命令面板服务实例
+func (sm *ServiceManager) GetCommandService() *CommandService {
+	return sm.commandService
+}
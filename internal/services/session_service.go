@@ -0,0 +1,368 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+const (
+	// sessionLockFile 运行期间存在的锁文件，正常退出时删除
+	sessionLockFile = "voidraft.lock"
+	// sessionStateFile 会话状态文件
+	sessionStateFile = "session.json"
+	// sessionJournalDir 自动保存日志目录，每个文档一个文件
+	sessionJournalDir = "journal"
+	// sessionSnapshotInterval 记录打开窗口的间隔
+	sessionSnapshotInterval = 15 * time.Second
+)
+
+// SessionService 会话恢复服务
+// 通过锁文件和正常退出标记检测上次是否异常退出，异常退出后提供恢复窗口和未保存内容的选项
+type SessionService struct {
+	logger          *log.LogService
+	configService   *ConfigService
+	databaseService *DatabaseService
+	documentService *DocumentService
+	windowService   *WindowService
+
+	mu       sync.Mutex
+	dir      string
+	state    models.SessionState
+	recovery *models.SessionRecovery
+	// 最近一次有文档窗口打开时的窗口，启动时取上次会话记录的窗口
+	recentWindows []int64
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewSessionService 创建会话恢复服务实例
+func NewSessionService(configService *ConfigService, databaseService *DatabaseService, documentService *DocumentService, windowService *WindowService, logger *log.LogService) *SessionService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &SessionService{
+		logger:          logger,
+		configService:   configService,
+		databaseService: databaseService,
+		documentService: documentService,
+		windowService:   windowService,
+	}
+}
+
+// ServiceStartup 检测上次是否异常退出，并开始记录本次会话
+func (ss *SessionService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	config, err := ss.configService.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+	ss.dir = config.General.DataPath
+	if err := os.MkdirAll(ss.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	previous := ss.loadPreviousState()
+	ss.recentWindows = previous.Windows
+	ss.detectCrash(previous)
+	if ss.recovery == nil {
+		// 上次正常退出时遗留的日志已无需恢复
+		if err := os.RemoveAll(ss.path(sessionJournalDir)); err != nil {
+			ss.logger.Warning("Failed to clean session journal", "error", err)
+		}
+	}
+
+	now := time.Now()
+	ss.state = models.SessionState{StartedAt: now, UpdatedAt: now}
+	if err := os.WriteFile(ss.path(sessionLockFile), []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		ss.logger.Error("Failed to create session lock file", "error", err)
+	}
+	ss.saveState()
+
+	snapshotCtx, cancel := context.WithCancel(context.Background())
+	ss.cancel = cancel
+	ss.wg.Add(1)
+	go func() {
+		defer ss.wg.Done()
+		ticker := time.NewTicker(sessionSnapshotInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-snapshotCtx.Done():
+				return
+			case <-ticker.C:
+				ss.snapshot()
+			}
+		}
+	}()
+	return nil
+}
+
+// loadPreviousState 读取上次会话的状态，不存在时返回空状态
+func (ss *SessionService) loadPreviousState() models.SessionState {
+	var previous models.SessionState
+	if data, err := os.ReadFile(ss.path(sessionStateFile)); err == nil {
+		if err := json.Unmarshal(data, &previous); err != nil {
+			ss.logger.Warning("Failed to parse previous session state", "error", err)
+		}
+	}
+	return previous
+}
+
+// detectCrash 锁文件仍在且上次没有正常退出时，收集可恢复的窗口和日志
+func (ss *SessionService) detectCrash(previous models.SessionState) {
+	if _, err := os.Stat(ss.path(sessionLockFile)); err != nil {
+		return
+	}
+	if previous.CleanExit {
+		return
+	}
+
+	journal, err := ss.readJournal()
+	if err != nil {
+		ss.logger.Warning("Failed to read session journal", "error", err)
+	}
+	if len(previous.Windows) == 0 && len(journal) == 0 {
+		return
+	}
+
+	ss.recovery = &models.SessionRecovery{
+		CrashedAt: previous.UpdatedAt,
+		Windows:   previous.Windows,
+		Journal:   journal,
+	}
+	ss.logger.Warning("Previous session did not exit cleanly", "windows", len(previous.Windows), "journal", len(journal))
+	helper.EmitEvent(constant.EVENT_SESSION_RECOVERY_AVAILABLE, ss.recovery)
+}
+
+// GetSessionRecovery 获取上次异常退出后可恢复的会话，没有时返回 nil
+func (ss *SessionService) GetSessionRecovery() *models.SessionRecovery {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.recovery
+}
+
+// RestoreSession 恢复上次异常退出的会话
+// restoreWindows 重新打开上次的文档窗口，recoverJournal 将未保存的内容写回文档
+func (ss *SessionService) RestoreSession(restoreWindows, recoverJournal bool) error {
+	ss.mu.Lock()
+	recovery := ss.recovery
+	ss.recovery = nil
+	ss.mu.Unlock()
+	if recovery == nil {
+		return errors.New("no session to restore")
+	}
+
+	var errs []error
+	if recoverJournal {
+		for _, entry := range recovery.Journal {
+			if err := ss.documentService.UpdateDocumentContent(entry.DocumentID, entry.Content); err != nil {
+				errs = append(errs, fmt.Errorf("document %d: %w", entry.DocumentID, err))
+			}
+		}
+	}
+	if restoreWindows {
+		for _, documentID := range recovery.Windows {
+			if err := ss.windowService.OpenDocumentWindow(documentID); err != nil {
+				errs = append(errs, fmt.Errorf("window %d: %w", documentID, err))
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to restore session: %w", err)
+	}
+
+	ss.discardJournal(recovery.Journal)
+	return nil
+}
+
+// DiscardSessionRecovery 放弃恢复上次的会话，删除遗留的自动保存日志
+func (ss *SessionService) DiscardSessionRecovery() {
+	ss.mu.Lock()
+	recovery := ss.recovery
+	ss.recovery = nil
+	ss.mu.Unlock()
+	if recovery != nil {
+		ss.discardJournal(recovery.Journal)
+	}
+}
+
+// JournalDocument 记录文档尚未保存的内容，异常退出后可恢复
+// 数据库加密时不记录，避免明文内容落盘
+func (ss *SessionService) JournalDocument(documentID int64, content string) error {
+	if ss.dir == "" || ss.databaseService.GetDatabaseEncryptionStatus().Encrypted {
+		return nil
+	}
+	entry := models.JournalEntry{DocumentID: documentID, Content: content, UpdatedAt: time.Now()}
+	if doc, err := ss.documentService.GetDocumentByID(documentID); err == nil && doc != nil {
+		entry.Title = doc.Title
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal entry: %w", err)
+	}
+
+	dir := ss.path(sessionJournalDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	path := ss.journalPath(documentID)
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write journal entry: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("failed to write journal entry: %w", err)
+	}
+	return nil
+}
+
+// ClearJournal 文档保存后删除其自动保存日志
+func (ss *SessionService) ClearJournal(documentID int64) {
+	if ss.dir == "" {
+		return
+	}
+	if err := os.Remove(ss.journalPath(documentID)); err != nil && !os.IsNotExist(err) {
+		ss.logger.Warning("Failed to remove journal entry", "documentId", documentID, "error", err)
+	}
+}
+
+// readJournal 读取遗留的自动保存日志，跳过内容与数据库一致的条目
+func (ss *SessionService) readJournal() ([]models.JournalEntry, error) {
+	files, err := os.ReadDir(ss.path(sessionJournalDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var journal []models.JournalEntry
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(ss.path(sessionJournalDir), file.Name()))
+		if err != nil {
+			return journal, err
+		}
+		var entry models.JournalEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			ss.logger.Warning("Skipping corrupt journal entry", "file", file.Name(), "error", err)
+			continue
+		}
+		if doc, err := ss.documentService.GetDocumentByID(entry.DocumentID); err == nil && doc != nil && doc.Content == entry.Content {
+			continue
+		}
+		journal = append(journal, entry)
+	}
+	sort.Slice(journal, func(i, j int) bool { return journal[i].UpdatedAt.After(journal[j].UpdatedAt) })
+	return journal, nil
+}
+
+// discardJournal 删除已处理的日志条目
+func (ss *SessionService) discardJournal(journal []models.JournalEntry) {
+	for _, entry := range journal {
+		if err := os.Remove(ss.journalPath(entry.DocumentID)); err != nil && !os.IsNotExist(err) {
+			ss.logger.Warning("Failed to remove journal entry", "documentId", entry.DocumentID, "error", err)
+		}
+	}
+}
+
+// snapshot 记录当前打开的文档窗口
+func (ss *SessionService) snapshot() {
+	var windows []int64
+	for _, window := range ss.windowService.GetOpenWindows() {
+		if documentID, err := strconv.ParseInt(window.Name(), 10, 64); err == nil {
+			windows = append(windows, documentID)
+		}
+	}
+
+	ss.mu.Lock()
+	ss.state.Windows = windows
+	ss.state.UpdatedAt = time.Now()
+	if len(windows) > 0 {
+		ss.recentWindows = windows
+	}
+	ss.mu.Unlock()
+	ss.saveState()
+}
+
+// ReopenRecentWindows 重新打开最近一次打开过的文档窗口，已打开的窗口只会被聚焦
+// 本次运行中还没有打开过文档窗口时使用上次会话记录的窗口
+func (ss *SessionService) ReopenRecentWindows() error {
+	ss.mu.Lock()
+	windows := append([]int64(nil), ss.recentWindows...)
+	ss.mu.Unlock()
+
+	var errs []error
+	for _, documentID := range windows {
+		if err := ss.windowService.OpenDocumentWindow(documentID); err != nil {
+			errs = append(errs, fmt.Errorf("window %d: %w", documentID, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to reopen windows: %w", err)
+	}
+	return nil
+}
+
+// saveState 写入会话状态文件
+func (ss *SessionService) saveState() {
+	ss.mu.Lock()
+	data, err := json.MarshalIndent(ss.state, "", "  ")
+	ss.mu.Unlock()
+	if err != nil {
+		ss.logger.Error("Failed to encode session state", "error", err)
+		return
+	}
+	if err := os.WriteFile(ss.path(sessionStateFile), data, 0644); err != nil {
+		ss.logger.Error("Failed to write session state", "error", err)
+	}
+}
+
+// path 数据目录下的文件路径
+func (ss *SessionService) path(name string) string {
+	return filepath.Join(ss.dir, name)
+}
+
+// journalPath 文档的自动保存日志路径
+func (ss *SessionService) journalPath(documentID int64) string {
+	return filepath.Join(ss.path(sessionJournalDir), fmt.Sprintf("%d.json", documentID))
+}
+
+// ServiceShutdown 记录正常退出并删除锁文件
+func (ss *SessionService) ServiceShutdown() error {
+	if ss.cancel != nil {
+		ss.cancel()
+		ss.wg.Wait()
+	}
+	if ss.dir == "" {
+		return nil
+	}
+
+	ss.mu.Lock()
+	ss.state.CleanExit = true
+	ss.state.UpdatedAt = time.Now()
+	ss.mu.Unlock()
+	ss.saveState()
+
+	if err := os.Remove(ss.path(sessionLockFile)); err != nil && !os.IsNotExist(err) {
+		ss.logger.Error("Failed to remove session lock file", "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"os"
+	"testing"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// newTestSessionService 创建数据目录为 dir 的会话服务并启动
+func newTestSessionService(t *testing.T, ds *DocumentService, dir string) *SessionService {
+	t.Helper()
+	config := models.NewDefaultAppConfig()
+	config.General.DataPath = dir
+	ss := NewSessionService(newTestConfigService(t, config), ds.databaseService, ds, nil, nil)
+	if err := ss.ServiceStartup(context.Background(), application.ServiceOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	return ss
+}
+
+// crashSession 停止记录但不写入正常退出标记，模拟异常退出
+func crashSession(ss *SessionService) {
+	ss.cancel()
+	ss.wg.Wait()
+}
+
+// TestSessionCrashRecovery 异常退出后恢复未保存的内容，正常退出后不再提示
+func TestSessionCrashRecovery(t *testing.T) {
+	ds := newTestDocumentService(t)
+	changed := createTestDocument(t, ds, "Changed", "\n∞∞∞text-a\nsaved")
+	unchanged := createTestDocument(t, ds, "Unchanged", "\n∞∞∞text-a\nsame")
+	dir := t.TempDir()
+
+	first := newTestSessionService(t, ds, dir)
+	if first.GetSessionRecovery() != nil {
+		t.Fatal("fresh data directory should have nothing to recover")
+	}
+	if err := first.JournalDocument(changed, "\n∞∞∞text-a\nunsaved"); err != nil {
+		t.Fatal(err)
+	}
+	// 内容与数据库一致的日志不需要恢复
+	if err := first.JournalDocument(unchanged, "\n∞∞∞text-a\nsame"); err != nil {
+		t.Fatal(err)
+	}
+	crashSession(first)
+
+	second := newTestSessionService(t, ds, dir)
+	recovery := second.GetSessionRecovery()
+	if recovery == nil {
+		t.Fatal("crash was not detected")
+	}
+	if len(recovery.Journal) != 1 || recovery.Journal[0].DocumentID != changed || recovery.Journal[0].Title != "Changed" {
+		t.Fatalf("journal = %+v, want the changed document only", recovery.Journal)
+	}
+	if err := second.RestoreSession(false, true); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := ds.GetDocumentByID(changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Content != "\n∞∞∞text-a\nunsaved" {
+		t.Errorf("recovered content = %q", doc.Content)
+	}
+	if _, err := os.Stat(second.journalPath(changed)); !os.IsNotExist(err) {
+		t.Errorf("journal entry was not removed after restoring: %v", err)
+	}
+	if err := second.RestoreSession(false, true); err == nil {
+		t.Error("restoring twice should fail")
+	}
+	if err := second.ServiceShutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	third := newTestSessionService(t, ds, dir)
+	defer third.ServiceShutdown()
+	if third.GetSessionRecovery() != nil {
+		t.Error("clean exit reported as a crash")
+	}
+	if _, err := os.Stat(third.path(sessionJournalDir)); !os.IsNotExist(err) {
+		t.Errorf("journal directory left after a clean exit: %v", err)
+	}
+}
+
+// TestSessionDiscardRecovery 放弃恢复时删除日志，内容保持不变
+func TestSessionDiscardRecovery(t *testing.T) {
+	ds := newTestDocumentService(t)
+	id := createTestDocument(t, ds, "Doc", "\n∞∞∞text-a\nsaved")
+	dir := t.TempDir()
+
+	first := newTestSessionService(t, ds, dir)
+	if err := first.JournalDocument(id, "\n∞∞∞text-a\nunsaved"); err != nil {
+		t.Fatal(err)
+	}
+	crashSession(first)
+
+	second := newTestSessionService(t, ds, dir)
+	defer second.ServiceShutdown()
+	if second.GetSessionRecovery() == nil {
+		t.Fatal("crash was not detected")
+	}
+	second.DiscardSessionRecovery()
+	if second.GetSessionRecovery() != nil {
+		t.Error("recovery still available after discarding")
+	}
+	if _, err := os.Stat(second.journalPath(id)); !os.IsNotExist(err) {
+		t.Errorf("journal entry was not removed: %v", err)
+	}
+	doc, err := ds.GetDocumentByID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Content != "\n∞∞∞text-a\nsaved" {
+		t.Errorf("content = %q, want the saved content", doc.Content)
+	}
+}
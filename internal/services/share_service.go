@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/markdown"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// shareTimeLayout 分享链接时间格式
+const shareTimeLayout = time.RFC3339
+
+// shareMaxExpiryMinutes 分享链接最长有效期（7天）
+const shareMaxExpiryMinutes = 7 * 24 * 60
+
+// sharePageTemplate 分享页面模板，只读渲染文档块
+var sharePageTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex, nofollow">
+<title>{{.Title}}</title>
+<style>
+body{margin:0;padding:24px;background:#fafafa;color:#222;font:15px/1.6 -apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,sans-serif}
+main{max-width:960px;margin:0 auto}
+h1{font-size:20px;margin:0 0 4px}
+.meta{color:#888;font-size:12px;margin-bottom:16px}
+.block{background:#fff;border:1px solid #e5e5e5;border-radius:6px;margin:0 0 12px;padding:12px 16px}
+.block pre{margin:0;font:13px/1.5 ui-monospace,SFMono-Regular,Menlo,Consolas,monospace}
+@media (prefers-color-scheme:dark){body{background:#1e1e1e;color:#ddd}.block{background:#252526;border-color:#333}}
+{{.CSS}}
+</style>
+</head>
+<body>
+<main>
+<h1>{{.Title}}</h1>
+<div class="meta">Read-only · expires {{.ExpiresAt}}</div>
+{{.Body}}
+</main>
+</body>
+</html>
+`))
+
+// sharePageData 分享页面数据
+type sharePageData struct {
+	Title     string
+	ExpiresAt string
+	Body      template.HTML // 经过清理的渲染结果
+	CSS       template.CSS
+}
+
+// shareEntry 内存中的分享记录
+type shareEntry struct {
+	link      models.ShareLink
+	expiresAt time.Time
+}
+
+// ShareService 文档只读分享服务，链接仅保存在内存中，应用重启即全部失效
+type ShareService struct {
+	logger             *log.LogService
+	localServerService *LocalServerService
+	documentService    *DocumentService
+	secretScanService  *SecretScanService
+	configService      *ConfigService
+
+	mu     sync.Mutex
+	shares map[string]*shareEntry
+}
+
+// NewShareService 创建分享服务
+func NewShareService(localServerService *LocalServerService, documentService *DocumentService, secretScanService *SecretScanService, configService *ConfigService, logger *log.LogService) *ShareService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	ss := &ShareService{
+		logger:             logger,
+		localServerService: localServerService,
+		documentService:    documentService,
+		secretScanService:  secretScanService,
+		configService:      configService,
+		shares:             make(map[string]*shareEntry),
+	}
+	localServerService.handle("GET /share/{token}", ss.handleShare)
+	return ss
+}
+
+// ServiceStartup 服务启动
+func (ss *ShareService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	return nil
+}
+
+// CreateShareLink 为文档创建只读分享链接，expiresMinutes 为0时使用默认有效期
+func (ss *ShareService) CreateShareLink(documentID int64, expiresMinutes int) (*models.ShareLink, error) {
+	baseURL, err := ss.localServerService.baseURL()
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := ss.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil || doc.IsDeleted {
+		return nil, fmt.Errorf("document not found: %d", documentID)
+	}
+
+	if expiresMinutes <= 0 {
+		config, err := ss.configService.GetConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get config: %w", err)
+		}
+		expiresMinutes = config.Server.DefaultShareExpiry
+	}
+	if expiresMinutes <= 0 || expiresMinutes > shareMaxExpiryMinutes {
+		return nil, fmt.Errorf("expiry must be between 1 and %d minutes", shareMaxExpiryMinutes)
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(expiresMinutes) * time.Minute)
+	entry := &shareEntry{
+		link: models.ShareLink{
+			Token:      token,
+			DocumentID: documentID,
+			Title:      doc.Title,
+			URL:        baseURL + "/share/" + token,
+			CreatedAt:  now.Format(shareTimeLayout),
+			ExpiresAt:  expiresAt.Format(shareTimeLayout),
+		},
+		expiresAt: expiresAt,
+	}
+
+	ss.mu.Lock()
+	ss.shares[token] = entry
+	ss.mu.Unlock()
+
+	ss.logger.Info("Share link created", "documentId", documentID, "expiresAt", entry.link.ExpiresAt)
+	link := entry.link
+	return &link, nil
+}
+
+// ListShareLinks 列出未过期的分享链接
+func (ss *ShareService) ListShareLinks() []models.ShareLink {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.pruneExpiredLocked()
+	links := make([]models.ShareLink, 0, len(ss.shares))
+	for _, entry := range ss.shares {
+		links = append(links, entry.link)
+	}
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].CreatedAt > links[j].CreatedAt
+	})
+	return links
+}
+
+// RevokeShareLink 撤销分享链接
+func (ss *ShareService) RevokeShareLink(token string) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if _, ok := ss.shares[token]; !ok {
+		return errors.New("share link not found")
+	}
+	delete(ss.shares, token)
+	return nil
+}
+
+// RevokeAllShareLinks 撤销全部分享链接
+func (ss *ShareService) RevokeAllShareLinks() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.shares = make(map[string]*shareEntry)
+}
+
+// handleShare 渲染分享页面
+func (ss *ShareService) handleShare(w http.ResponseWriter, r *http.Request) {
+	header := w.Header()
+	header.Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("X-Frame-Options", "DENY")
+	header.Set("Referrer-Policy", "no-referrer")
+	header.Set("Cache-Control", "no-store")
+	header.Set("X-Robots-Tag", "noindex, nofollow")
+
+	token := r.PathValue("token")
+
+	ss.mu.Lock()
+	entry, ok := ss.shares[token]
+	if ok && time.Now().After(entry.expiresAt) {
+		delete(ss.shares, token)
+		ok = false
+	}
+	var link models.ShareLink
+	if ok {
+		entry.link.Views++
+		link = entry.link
+	}
+	ss.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	doc, err := ss.documentService.GetDocumentByID(link.DocumentID)
+	if errors.Is(err, ErrAppLocked) {
+		http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil || doc == nil || doc.IsDeleted {
+		http.NotFound(w, r)
+		return
+	}
+
+	rendered, err := markdown.RenderBlocks(blocks.Parse(ss.secretScanService.maskForExport(doc.Content)), markdown.StyleLight)
+	if err != nil {
+		ss.logger.Error("Failed to render share page", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	data := sharePageData{
+		Title:     doc.Title,
+		ExpiresAt: link.ExpiresAt,
+		Body:      template.HTML(rendered.HTML),
+		CSS:       template.CSS(markdown.AdaptiveCSS()),
+	}
+
+	header.Set("Content-Type", "text/html; charset=utf-8")
+	if err := sharePageTemplate.Execute(w, data); err != nil {
+		ss.logger.Error("Failed to render share page", "error", err)
+	}
+}
+
+// pruneExpiredLocked 清理过期链接，调用方需持有锁
+func (ss *ShareService) pruneExpiredLocked() {
+	now := time.Now()
+	for token, entry := range ss.shares {
+		if now.After(entry.expiresAt) {
+			delete(ss.shares, token)
+		}
+	}
+}
+
+// newShareToken 生成随机访问令牌
+func newShareToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// ServiceShutdown 服务关闭
+func (ss *ShareService) ServiceShutdown() error {
+	ss.RevokeAllShareLinks()
+	return nil
+}
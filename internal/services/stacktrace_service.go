@@ -0,0 +1,86 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/stacktrace"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// StackTraceService 堆栈识别服务，将粘贴的堆栈整理为独立的结构化块
+type StackTraceService struct {
+	logger          *log.LogService
+	documentService *DocumentService
+}
+
+// NewStackTraceService 创建堆栈识别服务实例
+func NewStackTraceService(documentService *DocumentService, logger *log.LogService) *StackTraceService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &StackTraceService{
+		logger:          logger,
+		documentService: documentService,
+	}
+}
+
+// DetectStackTraces 识别文本中的 Go、Java、Python 和 JavaScript 堆栈
+func (ss *StackTraceService) DetectStackTraces(text string) []stacktrace.Trace {
+	traces := stacktrace.Detect(text)
+	if traces == nil {
+		traces = []stacktrace.Trace{}
+	}
+	return traces
+}
+
+// FoldStackTraces 将文档中的堆栈拆分为独立的文本块，每帧一行并统一为 file:line 形式，返回处理的堆栈数量
+func (ss *StackTraceService) FoldStackTraces(documentID int64) (int, error) {
+	doc, err := ss.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil || doc.IsDeleted {
+		return 0, fmt.Errorf("document not found: %d", documentID)
+	}
+
+	folded, count := foldStackTraces(blocks.Parse(doc.Content))
+	if count == 0 {
+		return 0, nil
+	}
+	if err := ss.documentService.UpdateDocumentContent(documentID, blocks.Serialize(folded)); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// foldStackTraces 拆分块中的堆栈，堆栈前后的内容保留原块语言
+func foldStackTraces(list []blocks.Block) ([]blocks.Block, int) {
+	var result []blocks.Block
+	count := 0
+	for _, block := range list {
+		traces := stacktrace.Detect(block.Content)
+		// 已整理过的堆栈块不再重复拆分
+		if len(traces) == 0 || (len(traces) == 1 && block.Content == stacktrace.Format(traces[0])) {
+			result = append(result, block)
+			continue
+		}
+
+		rest := block.Content
+		offset := 0
+		for _, trace := range traces {
+			if before := strings.TrimRight(rest[:trace.Start-offset], "\n"); strings.TrimSpace(before) != "" {
+				result = append(result, blocks.Block{Language: block.Language, Auto: block.Auto, Content: before})
+			}
+			result = append(result, blocks.Block{Language: blocks.DefaultLanguage, Content: stacktrace.Format(trace)})
+			rest = rest[trace.End-offset:]
+			offset = trace.End
+			count++
+		}
+		if after := strings.TrimLeft(rest, "\n"); strings.TrimSpace(after) != "" {
+			result = append(result, blocks.Block{Language: block.Language, Auto: block.Auto, Content: after})
+		}
+	}
+	return result, count
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"testing"
+	"voidraft/internal/common/blocks"
+)
+
+func TestFoldStackTraces(t *testing.T) {
+	content := blocks.Delimiter("md", false) + "# Incident\nlog output:\npanic: boom\n\ngoroutine 1 [running]:\nmain.main()\n\t/app/main.go:9 +0x1\n\nfixed by restart"
+	folded, count := foldStackTraces(blocks.Parse(content))
+	if count != 1 {
+		t.Fatalf("count = %d", count)
+	}
+	want := blocks.Delimiter("md", false) + "# Incident\nlog output:" +
+		blocks.Delimiter("text", false) + "panic: boom\n    at main.main (/app/main.go:9)" +
+		blocks.Delimiter("md", false) + "fixed by restart"
+	if got := blocks.Serialize(folded); got != want {
+		t.Errorf("folded =\n%q\nwant\n%q", got, want)
+	}
+
+	// 再次整理时保持不变
+	if _, count := foldStackTraces(folded); count != 0 {
+		t.Errorf("refold count = %d", count)
+	}
+}
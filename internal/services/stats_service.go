@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/textstats"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// SQL 查询语句
+const (
+	sqlStatsDocuments = `
+SELECT id, title, updated_at FROM documents WHERE is_deleted = 0 AND is_archived = 0`
+
+	sqlStatsDocumentContent = `
+SELECT title, content, updated_at FROM documents WHERE id = ? AND is_deleted = 0`
+
+	sqlStatsCreatedPerDay = `
+SELECT substr(created_at, 1, 10) AS day, COUNT(*)
+FROM documents
+WHERE is_deleted = 0 AND substr(created_at, 1, 10) >= ?
+GROUP BY day`
+)
+
+const (
+	// statsDays 按天统计新建文档的天数，含今天
+	statsDays = 30
+	// statsWeeks 按周统计新建文档的周数，含本周
+	statsWeeks = 12
+)
+
+// StatsService 文档文本统计
+// 按文档计算字数、字符、行数和代码块，结果按最后编辑时间缓存，文档变更时失效；另提供代码行和注释比例的统计
+type StatsService struct {
+	logger          *log.LogService
+	databaseService *DatabaseService
+	documentService *DocumentService
+
+	mu    sync.Mutex
+	cache map[int64]*models.DocumentStats
+}
+
+// NewStatsService 创建文档统计服务实例
+func NewStatsService(databaseService *DatabaseService, documentService *DocumentService, logger *log.LogService) *StatsService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &StatsService{
+		logger:          logger,
+		databaseService: databaseService,
+		documentService: documentService,
+		cache:           make(map[int64]*models.DocumentStats),
+	}
+}
+
+// ServiceStartup 订阅文档变更
+func (ss *StatsService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	ss.documentService.onDocumentChange(ss.handleDocumentChange)
+	return nil
+}
+
+// handleDocumentChange 内容、标题变化或删除时丢弃缓存
+func (ss *StatsService) handleDocumentChange(event models.DocumentChangeEvent) {
+	switch event.Type {
+	case models.DocumentChangeContent, models.DocumentChangeTitle, models.DocumentChangeDeleted:
+		ss.mu.Lock()
+		delete(ss.cache, event.DocumentID)
+		ss.mu.Unlock()
+	}
+}
+
+// GetDocumentStats 获取单个文档的统计
+func (ss *StatsService) GetDocumentStats(documentID int64) (*models.DocumentStats, error) {
+	db, err := ss.db()
+	if err != nil {
+		return nil, err
+	}
+
+	var title, content, updatedAt string
+	err = db.QueryRow(sqlStatsDocumentContent, documentID).Scan(&title, &content, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("document not found: %d", documentID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if cached, ok := ss.cache[documentID]; ok && cached.UpdatedAt == updatedAt && cached.Title == title {
+		stats := *cached
+		return &stats, nil
+	}
+	stats := ss.computeLocked(documentID, title, content, updatedAt)
+	return &stats, nil
+}
+
+// GetStatsOverview 获取全部文档的汇总统计和新建文档趋势
+func (ss *StatsService) GetStatsOverview() (*models.StatsOverview, error) {
+	db, err := ss.db()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(sqlStatsDocuments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	type documentRow struct {
+		id        int64
+		title     string
+		updatedAt string
+	}
+	var docs []documentRow
+	for rows.Next() {
+		var doc documentRow
+		if err := rows.Scan(&doc.id, &doc.title, &doc.updatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan document: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	overview := &models.StatsOverview{Notes: len(docs), GeneratedAt: now}
+	for _, doc := range docs {
+		stats, err := ss.cachedStats(db, doc.id, doc.title, doc.updatedAt)
+		if err != nil {
+			return nil, err
+		}
+		overview.Words += stats.Words
+		overview.Characters += stats.Characters
+		overview.Lines += stats.Lines
+		overview.CodeBlocks += stats.CodeBlocks
+	}
+
+	created, err := ss.loadCreatedCounts(db, weekStart(now).AddDate(0, 0, -7*(statsWeeks-1)))
+	if err != nil {
+		return nil, err
+	}
+	overview.CreatedPerDay = createdPerDay(created, now, statsDays)
+	overview.CreatedPerWeek = createdPerWeek(created, now, statsWeeks)
+	return overview, nil
+}
+
+// cachedStats 最后编辑时间未变时使用缓存，否则读取内容重新统计
+func (ss *StatsService) cachedStats(db *sql.DB, id int64, title, updatedAt string) (models.DocumentStats, error) {
+	ss.mu.Lock()
+	if cached, ok := ss.cache[id]; ok && cached.UpdatedAt == updatedAt && cached.Title == title {
+		stats := *cached
+		ss.mu.Unlock()
+		return stats, nil
+	}
+	ss.mu.Unlock()
+
+	var content string
+	err := db.QueryRow(sqlStatsDocumentContent, id).Scan(&title, &content, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		// 统计期间被删除
+		return models.DocumentStats{DocumentID: id}, nil
+	}
+	if err != nil {
+		return models.DocumentStats{}, fmt.Errorf("failed to get document %d: %w", id, err)
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.computeLocked(id, title, content, updatedAt), nil
+}
+
+// computeLocked 统计文档并写入缓存，调用方需持有 mu
+func (ss *StatsService) computeLocked(id int64, title, content, updatedAt string) models.DocumentStats {
+	counted := textstats.Count(content)
+	stats := models.DocumentStats{
+		DocumentID:         id,
+		Title:              title,
+		Words:              counted.Words,
+		Characters:         counted.Characters,
+		CharactersNoSpaces: counted.CharactersNoSpaces,
+		Lines:              counted.Lines,
+		Blocks:             counted.Blocks,
+		CodeBlocks:         counted.CodeBlocks,
+		UpdatedAt:          updatedAt,
+	}
+	cached := stats
+	ss.cache[id] = &cached
+	return stats
+}
+
+// GetCodeStats 获取文档或其中一个块的代码统计，blockIndex 为负数时统计整个文档
+func (ss *StatsService) GetCodeStats(documentID int64, blockIndex int) (*models.CodeStats, error) {
+	db, err := ss.db()
+	if err != nil {
+		return nil, err
+	}
+
+	var title, content, updatedAt string
+	err = db.QueryRow(sqlStatsDocumentContent, documentID).Scan(&title, &content, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("document not found: %d", documentID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+
+	list := blocks.Parse(content)
+	if blockIndex >= 0 {
+		if blockIndex >= len(list) {
+			return nil, fmt.Errorf("block %d not found in document %d", blockIndex, documentID)
+		}
+		list = list[blockIndex : blockIndex+1]
+	} else {
+		blockIndex = -1
+	}
+	stats := codeStats(textstats.CountCode(list))
+	stats.DocumentID = documentID
+	stats.BlockIndex = blockIndex
+	return stats, nil
+}
+
+// AnalyzeCode 统计编辑器中尚未保存的块内容，language 为块语言
+func (ss *StatsService) AnalyzeCode(content string, language string) *models.CodeStats {
+	if language == "" {
+		language = blocks.DefaultLanguage
+	}
+	stats := codeStats(textstats.CountCode([]blocks.Block{{Language: language, Content: content}}))
+	stats.BlockIndex = -1
+	return stats
+}
+
+// codeStats 转换代码统计，语言按行数从多到少、行数相同时按名称排列
+func codeStats(counted textstats.CodeStats) *models.CodeStats {
+	stats := &models.CodeStats{
+		Lines:        counted.Lines,
+		CodeLines:    counted.CodeLines,
+		CommentLines: counted.CommentLines,
+		BlankLines:   counted.BlankLines,
+		ProseLines:   counted.ProseLines,
+		CommentRatio: counted.CommentRatio(),
+		Languages:    make([]models.CodeLanguageShare, 0, len(counted.Languages)),
+	}
+	for language, lines := range counted.Languages {
+		share := models.CodeLanguageShare{Language: language, Lines: lines}
+		if counted.Lines > 0 {
+			share.Share = float64(lines) / float64(counted.Lines)
+		}
+		stats.Languages = append(stats.Languages, share)
+	}
+	sort.Slice(stats.Languages, func(i, j int) bool {
+		if stats.Languages[i].Lines != stats.Languages[j].Lines {
+			return stats.Languages[i].Lines > stats.Languages[j].Lines
+		}
+		return stats.Languages[i].Language < stats.Languages[j].Language
+	})
+	return stats
+}
+
+// loadCreatedCounts 从 from 开始每天新建的文档数
+func (ss *StatsService) loadCreatedCounts(db *sql.DB, from time.Time) (map[string]int, error) {
+	rows, err := db.Query(sqlStatsCreatedPerDay, from.Format(time.DateOnly))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count created documents: %w", err)
+	}
+	defer rows.Close()
+
+	days := map[string]int{}
+	for rows.Next() {
+		var day string
+		var count int
+		if err := rows.Scan(&day, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan created documents: %w", err)
+		}
+		days[day] = count
+	}
+	return days, rows.Err()
+}
+
+// db 检查应用锁和数据库状态
+func (ss *StatsService) db() (*sql.DB, error) {
+	if err := ss.documentService.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	if ss.databaseService == nil || ss.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+	return ss.databaseService.getDB(), nil
+}
+
+// createdPerDay 最近 days 天（含今天）每天新建的文档数，按日期从旧到新
+func createdPerDay(created map[string]int, now time.Time, days int) []models.StatsPeriod {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	out := make([]models.StatsPeriod, 0, days)
+	for d := today.AddDate(0, 0, 1-days); !d.After(today); d = d.AddDate(0, 0, 1) {
+		date := d.Format(time.DateOnly)
+		out = append(out, models.StatsPeriod{Start: date, Created: created[date]})
+	}
+	return out
+}
+
+// createdPerWeek 最近 weeks 周（含本周）每周新建的文档数，周一为一周的开始
+func createdPerWeek(created map[string]int, now time.Time, weeks int) []models.StatsPeriod {
+	first := weekStart(now).AddDate(0, 0, -7*(weeks-1))
+	out := make([]models.StatsPeriod, weeks)
+	for i := range out {
+		out[i].Start = first.AddDate(0, 0, 7*i).Format(time.DateOnly)
+	}
+	for date, count := range created {
+		day, err := time.Parse(time.DateOnly, date)
+		if err != nil || day.Before(first) {
+			continue
+		}
+		if i := int(day.Sub(first).Hours() / 24 / 7); i < weeks {
+			out[i].Created += count
+		}
+	}
+	return out
+}
+
+// weekStart now 所在周的周一
+func weekStart(now time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
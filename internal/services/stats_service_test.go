@@ -0,0 +1,71 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"voidraft/internal/common/textstats"
+	"voidraft/internal/models"
+)
+
+func TestWeekStart(t *testing.T) {
+	tests := []struct{ date, want string }{
+		{"2026-10-12", "2026-10-12"}, // 周一
+		{"2026-10-16", "2026-10-12"}, // 周五
+		{"2026-10-18", "2026-10-12"}, // 周日
+	}
+	for _, tt := range tests {
+		now, _ := time.Parse(time.DateOnly, tt.date)
+		if got := weekStart(now).Format(time.DateOnly); got != tt.want {
+			t.Errorf("weekStart(%s) = %s, want %s", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestCreatedPeriods(t *testing.T) {
+	now := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+	created := map[string]int{
+		"2026-10-16": 2,
+		"2026-10-12": 1,
+		"2026-10-11": 4, // 上周日
+		"2026-07-01": 9, // 超出统计范围
+	}
+
+	days := createdPerDay(created, now, 7)
+	if len(days) != 7 || days[0].Start != "2026-10-10" || days[6].Start != "2026-10-16" {
+		t.Fatalf("createdPerDay range = %+v", days)
+	}
+	if days[6].Created != 2 || days[1].Created != 4 || days[3].Created != 0 {
+		t.Errorf("createdPerDay counts = %+v", days)
+	}
+
+	weeks := createdPerWeek(created, now, 3)
+	want := []struct {
+		start   string
+		created int
+	}{{"2026-09-28", 0}, {"2026-10-05", 4}, {"2026-10-12", 3}}
+	for i, w := range want {
+		if weeks[i].Start != w.start || weeks[i].Created != w.created {
+			t.Errorf("createdPerWeek[%d] = %+v, want %s/%d", i, weeks[i], w.start, w.created)
+		}
+	}
+}
+
+func TestCodeStats(t *testing.T) {
+	stats := codeStats(textstats.CodeStats{
+		Lines: 8, CodeLines: 3, CommentLines: 1, BlankLines: 2, ProseLines: 2,
+		Languages: map[string]int{"go": 3, "text": 2, "py": 3},
+	})
+
+	if stats.CommentRatio != 0.25 {
+		t.Errorf("CommentRatio = %v, want 0.25", stats.CommentRatio)
+	}
+	want := []models.CodeLanguageShare{
+		{Language: "go", Lines: 3, Share: 0.375},
+		{Language: "py", Lines: 3, Share: 0.375},
+		{Language: "text", Lines: 2, Share: 0.25},
+	}
+	if !reflect.DeepEqual(stats.Languages, want) {
+		t.Errorf("Languages = %+v, want %+v", stats.Languages, want)
+	}
+}
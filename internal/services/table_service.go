@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"voidraft/internal/common/table"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// TableService 表格块服务，在 CSV/TSV 文本与表格之间转换，并提供排序、筛选和导出格式转换
+type TableService struct {
+	logger *log.LogService
+}
+
+// NewTableService 创建表格块服务实例
+func NewTableService(logger *log.LogService) *TableService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &TableService{logger: logger}
+}
+
+// ParseTable 解析 CSV/TSV 文本，format 为空时自动判断
+func (ts *TableService) ParseTable(text string, format table.Format) (*table.Table, error) {
+	return table.Parse(text, format)
+}
+
+// FormatTable 将表格转换为 csv、tsv、markdown 或 html 文本
+func (ts *TableService) FormatTable(t *table.Table, format table.Format) (string, error) {
+	if t == nil {
+		return "", errors.New("table is empty")
+	}
+	return t.Format(format)
+}
+
+// ConvertTable 将 CSV/TSV 文本直接转换为另一种格式，from 为空时自动判断
+func (ts *TableService) ConvertTable(text string, from table.Format, to table.Format) (string, error) {
+	t, err := table.Parse(text, from)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(to)
+}
+
+// SortTable 按列排序，返回排序后的表格
+func (ts *TableService) SortTable(t *table.Table, column int, descending bool) (*table.Table, error) {
+	if t == nil {
+		return nil, errors.New("table is empty")
+	}
+	if err := t.Sort(column, descending); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// FilterTable 筛选单元格包含 query 的行，column 为 -1 时匹配任意列
+func (ts *TableService) FilterTable(t *table.Table, column int, query string) (*table.Table, error) {
+	if t == nil {
+		return nil, errors.New("table is empty")
+	}
+	return t.Filter(column, query)
+}
+
+// SelectTableColumns 按给定顺序保留部分列
+func (ts *TableService) SelectTableColumns(t *table.Table, columns []int) (*table.Table, error) {
+	if t == nil {
+		return nil, errors.New("table is empty")
+	}
+	return t.SelectColumns(columns)
+}
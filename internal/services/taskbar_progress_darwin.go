@@ -0,0 +1,13 @@
+//go:build darwin
+
+package services
+
+import "fmt"
+
+// applyProgress 在程序坞图标的徽章上显示百分比，任务结束后恢复原来的徽章
+func (ts *TaskbarProgressService) applyProgress(progress taskbarProgress) error {
+	if !progress.Active {
+		return ts.badge.setProgress("")
+	}
+	return ts.badge.setProgress(fmt.Sprintf("%d%%", int(progress.Value*100)))
+}
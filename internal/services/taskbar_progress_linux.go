@@ -0,0 +1,29 @@
+//go:build linux
+
+package services
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+const (
+	// launcherEntryAppURI 与 build/linux/voidraft.desktop 对应的应用标识
+	launcherEntryAppURI = "application://voidraft.desktop"
+	// launcherEntryPath 发送 LauncherEntry 信号的对象路径
+	launcherEntryPath = "/com/canonical/unity/launcherentry/voidraft"
+)
+
+// applyProgress 通过 Unity LauncherEntry 信号在启动器图标上显示进度
+// KDE Plasma、Dash to Dock 等支持该协议，没有 gdbus 时跳过
+func (ts *TaskbarProgressService) applyProgress(progress taskbarProgress) error {
+	gdbus, err := exec.LookPath("gdbus")
+	if err != nil {
+		return nil
+	}
+	properties := fmt.Sprintf("{'progress': <%.2f>, 'progress-visible': <%t>}", progress.Value, progress.Active)
+	return exec.Command(gdbus, "emit", "--session",
+		"--object-path", launcherEntryPath,
+		"--signal", "com.canonical.Unity.LauncherEntry.Update",
+		launcherEntryAppURI, properties).Run()
+}
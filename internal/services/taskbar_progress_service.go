@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sync"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// taskbarProgress 显示在任务栏或程序坞上的进度
+type taskbarProgress struct {
+	Active bool    // 是否有未结束的任务
+	Value  float64 // 所有未结束任务的平均进度，0 到 1
+}
+
+// TaskbarProgressService 把后台任务的进度显示在 Windows 任务栏按钮、macOS 程序坞图标和 Linux 启动器上
+// 窗口最小化时也能看到导入、导出等耗时操作的进度；各平台的实现见 taskbar_progress_*.go
+type TaskbarProgressService struct {
+	logger     *log.LogService
+	jobService *JobService
+	badge      *appBadge
+
+	mu      sync.Mutex
+	jobs    map[int64]float64 // 未结束任务的进度
+	applied taskbarProgress   // 最近一次显示的进度
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewTaskbarProgressService 创建任务栏进度服务
+func NewTaskbarProgressService(jobService *JobService, badge *appBadge, logger *log.LogService) *TaskbarProgressService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TaskbarProgressService{
+		logger:     logger,
+		jobService: jobService,
+		badge:      badge,
+		jobs:       make(map[int64]float64),
+		wake:       make(chan struct{}, 1),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// ServiceStartup 订阅任务变化，系统调用在单独的协程中执行，不阻塞任务进度上报
+func (ts *TaskbarProgressService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	ts.jobService.onJobUpdated(ts.handleJobUpdated)
+
+	ts.wg.Add(1)
+	go ts.loop()
+	return nil
+}
+
+// handleJobUpdated 记录任务进度并唤醒更新协程
+func (ts *TaskbarProgressService) handleJobUpdated(job models.Job) {
+	ts.mu.Lock()
+	if job.Status.IsFinished() {
+		delete(ts.jobs, job.ID)
+	} else {
+		ts.jobs[job.ID] = job.Progress
+	}
+	ts.mu.Unlock()
+
+	select {
+	case ts.wake <- struct{}{}:
+	default:
+	}
+}
+
+// loop 合并短时间内的多次变化，只在显示的进度变化时调用系统接口
+func (ts *TaskbarProgressService) loop() {
+	defer ts.wg.Done()
+
+	for {
+		select {
+		case <-ts.ctx.Done():
+			return
+		case <-ts.wake:
+			ts.mu.Lock()
+			progress := aggregateJobProgress(ts.jobs)
+			ts.mu.Unlock()
+			ts.apply(progress)
+		}
+	}
+}
+
+// apply 显示进度，与上次相同时跳过
+func (ts *TaskbarProgressService) apply(progress taskbarProgress) {
+	if progress == ts.applied {
+		return
+	}
+	ts.applied = progress
+	if err := ts.applyProgress(progress); err != nil {
+		ts.logger.Warning("Failed to update taskbar progress", "error", err)
+	}
+}
+
+// ServiceShutdown 停止更新并清除进度
+func (ts *TaskbarProgressService) ServiceShutdown() error {
+	ts.cancel()
+	ts.wg.Wait()
+	ts.apply(taskbarProgress{})
+	return nil
+}
+
+// aggregateJobProgress 未结束任务的平均进度，排队中的任务按 0 计算，按百分比取整以减少系统调用
+func aggregateJobProgress(jobs map[int64]float64) taskbarProgress {
+	if len(jobs) == 0 {
+		return taskbarProgress{}
+	}
+	total := 0.0
+	for _, progress := range jobs {
+		total += min(max(progress, 0), 1)
+	}
+	return taskbarProgress{Active: true, Value: math.Floor(total/float64(len(jobs))*100) / 100}
+}
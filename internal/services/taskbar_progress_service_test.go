@@ -0,0 +1,28 @@
+package services
+
+import (
+	"testing"
+
+	"voidraft/internal/models"
+)
+
+func TestTaskbarProgressTracksActiveJobs(t *testing.T) {
+	ts := NewTaskbarProgressService(nil, nil, nil)
+
+	ts.handleJobUpdated(models.Job{ID: 1, Status: models.JobRunning, Progress: 0.5})
+	ts.handleJobUpdated(models.Job{ID: 2, Status: models.JobQueued})
+	if got, want := aggregateJobProgress(ts.jobs), (taskbarProgress{Active: true, Value: 0.25}); got != want {
+		t.Fatalf("progress = %+v, want %+v", got, want)
+	}
+
+	ts.handleJobUpdated(models.Job{ID: 2, Status: models.JobRunning, Progress: 0.333})
+	if got := aggregateJobProgress(ts.jobs).Value; got != 0.41 {
+		t.Fatalf("progress value = %v, want 0.41", got)
+	}
+
+	ts.handleJobUpdated(models.Job{ID: 1, Status: models.JobSucceeded, Progress: 1})
+	ts.handleJobUpdated(models.Job{ID: 2, Status: models.JobCanceled})
+	if got := aggregateJobProgress(ts.jobs); got.Active {
+		t.Fatalf("progress after all jobs finished = %+v", got)
+	}
+}
@@ -0,0 +1,111 @@
+//go:build windows
+
+package services
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// ITaskbarList3 的 CLSID、IID 和用到的虚函数表位置
+var (
+	clsidTaskbarList = windows.GUID{Data1: 0x56fdf344, Data2: 0xfd6d, Data3: 0x11d0, Data4: [8]byte{0x95, 0x8a, 0x00, 0x60, 0x97, 0xc9, 0xa0, 0x90}}
+	iidTaskbarList3  = windows.GUID{Data1: 0xea1afb91, Data2: 0x9e28, Data3: 0x4b86, Data4: [8]byte{0x90, 0xe9, 0x9e, 0x9f, 0x8a, 0x5e, 0xef, 0xaf}}
+)
+
+const (
+	taskbarListRelease          = 2
+	taskbarListHrInit           = 3
+	taskbarListSetProgressValue = 9
+	taskbarListSetProgressState = 10
+
+	tbpfNoProgress = 0x0
+	tbpfNormal     = 0x2
+
+	clsctxInprocServer = 0x1
+	gwOwner            = 4
+
+	// taskbarProgressScale 传给 SetProgressValue 的总量
+	taskbarProgressScale = 1000
+)
+
+var (
+	procCoCreateInstance = windows.NewLazySystemDLL("ole32.dll").NewProc("CoCreateInstance")
+	procGetWindow        = windows.NewLazySystemDLL("user32.dll").NewProc("GetWindow")
+)
+
+// taskbarList3 ITaskbarList3 COM 对象
+type taskbarList3 struct {
+	vtbl *[taskbarListSetProgressState + 1]uintptr
+}
+
+// call 调用虚函数表中的方法，返回 HRESULT
+func (t *taskbarList3) call(method int, args ...uintptr) uintptr {
+	hr, _, _ := syscall.SyscallN(t.vtbl[method], append([]uintptr{uintptr(unsafe.Pointer(t))}, args...)...)
+	return hr
+}
+
+// applyProgress 通过 ITaskbarList3 在本进程所有顶层窗口的任务栏按钮上显示进度
+func (ts *TaskbarProgressService) applyProgress(progress taskbarProgress) error {
+	// COM 对象只能在创建它的线程上使用
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	// 已按其他模式初始化时直接使用，不需要反初始化
+	if err := windows.CoInitializeEx(0, windows.COINIT_APARTMENTTHREADED); err == nil || err == syscall.Errno(1) {
+		defer windows.CoUninitialize()
+	}
+
+	var taskbar *taskbarList3
+	hr, _, _ := procCoCreateInstance.Call(
+		uintptr(unsafe.Pointer(&clsidTaskbarList)), 0, clsctxInprocServer,
+		uintptr(unsafe.Pointer(&iidTaskbarList3)), uintptr(unsafe.Pointer(&taskbar)))
+	if hr != 0 || taskbar == nil {
+		return fmt.Errorf("failed to create taskbar list: 0x%08x", hr)
+	}
+	defer taskbar.call(taskbarListRelease)
+
+	if hr := taskbar.call(taskbarListHrInit); hr != 0 {
+		return fmt.Errorf("failed to initialize taskbar list: 0x%08x", hr)
+	}
+	for _, hwnd := range processTopLevelWindows() {
+		if !progress.Active {
+			taskbar.call(taskbarListSetProgressState, uintptr(hwnd), tbpfNoProgress)
+			continue
+		}
+		taskbar.call(taskbarListSetProgressState, uintptr(hwnd), tbpfNormal)
+		taskbar.call(taskbarListSetProgressValue, uintptr(hwnd), uintptr(progress.Value*taskbarProgressScale), taskbarProgressScale)
+	}
+	return nil
+}
+
+// 枚举窗口的回调只创建一次，系统对回调数量有限制
+var (
+	enumWindowsMu       sync.Mutex
+	enumWindowsFound    []windows.HWND
+	enumWindowsCallback = windows.NewCallback(func(hwnd windows.HWND, _ uintptr) uintptr {
+		var pid uint32
+		if _, err := windows.GetWindowThreadProcessId(hwnd, &pid); err == nil && int(pid) == os.Getpid() && windows.IsWindowVisible(hwnd) {
+			if owner, _, _ := procGetWindow.Call(uintptr(hwnd), gwOwner); owner == 0 {
+				enumWindowsFound = append(enumWindowsFound, hwnd)
+			}
+		}
+		return 1
+	})
+)
+
+// processTopLevelWindows 本进程中可见且在任务栏上有按钮的顶层窗口
+func processTopLevelWindows() []windows.HWND {
+	enumWindowsMu.Lock()
+	defer enumWindowsMu.Unlock()
+
+	enumWindowsFound = nil
+	_ = windows.EnumWindows(enumWindowsCallback, nil)
+	return enumWindowsFound
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"voidraft/internal/common/themecolor"
+)
+
+// ParseColor 解析颜色文本（hex、rgb、hsl、oklch），返回各种表示，用于笔记中的内联色块和调色板块
+func (ts *ThemeService) ParseColor(text string) (*themecolor.Info, error) {
+	c, err := themecolor.Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	info := c.Describe()
+	return &info, nil
+}
+
+// ConvertColor 将颜色转换为指定格式：hex、rgb、hsl 或 oklch
+func (ts *ThemeService) ConvertColor(value string, format themecolor.Format) (string, error) {
+	c, err := themecolor.Parse(value)
+	if err != nil {
+		return "", err
+	}
+	return c.Format(format)
+}
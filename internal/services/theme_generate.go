@@ -0,0 +1,119 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strings"
+	"voidraft/internal/common/themecolor"
+	"voidraft/internal/models"
+)
+
+const (
+	// themePaletteSize 从图片提取的颜色数量
+	themePaletteSize = 12
+	// themeImageMaxPixels 图片像素上限，避免解码超大图片占用过多内存
+	themeImageMaxPixels = 64 << 20
+)
+
+// GenerateThemeFromImage 从图片文件（PNG、JPEG、GIF）提取调色板生成主题并保存
+func (ts *ThemeService) GenerateThemeFromImage(path string, name string) (*models.Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	return ts.GenerateThemeFromImageData(data, name)
+}
+
+// GenerateThemeFromImageData 从图片数据提取调色板生成主题并保存，同名主题已存在时返回错误
+func (ts *ThemeService) GenerateThemeFromImageData(data []byte, name string) (*models.Theme, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("theme name cannot be empty")
+	}
+	existing, err := ts.GetThemeByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("theme already exists: %s", name)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported image: %w", err)
+	}
+	if config.Width*config.Height > themeImageMaxPixels {
+		return nil, errors.New("image is too large")
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	palette := themecolor.Extract(img, themePaletteSize)
+	if len(palette) == 0 {
+		return nil, errors.New("image has no opaque pixels")
+	}
+	colors := themecolor.FromPalette(name, palette)
+
+	return ts.saveNewTheme(name, colors)
+}
+
+// ImportTheme 导入 Base16 YAML 方案或 VS Code 颜色主题 JSON，name 为空时使用原主题名称
+func (ts *ThemeService) ImportTheme(path string, name string) (*models.Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read theme file: %w", err)
+	}
+	return ts.ImportThemeData(string(data), name)
+}
+
+// ImportThemeData 从文本内容导入主题，自动识别格式
+func (ts *ThemeService) ImportThemeData(data string, name string) (*models.Theme, error) {
+	imported, err := themecolor.Import([]byte(data))
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = strings.TrimSpace(imported.Name)
+	}
+	if name == "" {
+		return nil, fmt.Errorf("theme name cannot be empty")
+	}
+	existing, err := ts.GetThemeByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("theme already exists: %s", name)
+	}
+
+	return ts.saveNewTheme(name, imported.Colors)
+}
+
+// saveNewTheme 保存新主题并返回保存后的记录
+func (ts *ThemeService) saveNewTheme(name string, colors models.ThemeColorConfig) (*models.Theme, error) {
+	if err := ts.UpdateTheme(name, colors); err != nil {
+		return nil, err
+	}
+	ts.logger.Info("Theme created", "name", name)
+	return ts.GetThemeByName(name)
+}
+
+// ValidateTheme 检查主题颜色的对比度，返回不满足 WCAG 要求的颜色键
+func (ts *ThemeService) ValidateTheme(colors models.ThemeColorConfig) []themecolor.ContrastIssue {
+	return themecolor.Validate(colors)
+}
+
+// FixThemeContrast 自动调整对比度不足的颜色，返回修正后的配置，不会保存
+func (ts *ThemeService) FixThemeContrast(colors models.ThemeColorConfig) models.ThemeColorConfig {
+	return themecolor.Fix(colors)
+}
@@ -35,12 +35,15 @@ func NewThemeService(databaseService *DatabaseService, logger *log.LogService) *
 // ServiceStartup 服务启动
 func (ts *ThemeService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
 	ts.ctx = ctx
+	if err := ts.migrateThemeTokens(); err != nil {
+		ts.logger.Error("Failed to migrate theme tokens", "error", err)
+	}
 	return nil
 }
 
 // getDB 获取数据库连接
 func (ts *ThemeService) getDB() *sql.DB {
-	return ts.databaseService.db
+	return ts.databaseService.getDB()
 }
 
 // GetThemeByName 通过名称获取主题覆盖，若不存在则返回 nil
@@ -56,7 +59,7 @@ func (ts *ThemeService) GetThemeByName(name string) (*models.Theme, error) {
 	}
 
 	query := `
-		SELECT id, name, type, colors, is_default, created_at, updated_at 
+		SELECT id, name, type, colors, tokens, is_default, created_at, updated_at 
 		FROM themes 
 		WHERE name = ?
 		LIMIT 1
@@ -68,6 +71,7 @@ func (ts *ThemeService) GetThemeByName(name string) (*models.Theme, error) {
 		&theme.Name,
 		&theme.Type,
 		&theme.Colors,
+		&theme.Tokens,
 		&theme.IsDefault,
 		&theme.CreatedAt,
 		&theme.UpdatedAt,
@@ -83,12 +87,39 @@ func (ts *ThemeService) GetThemeByName(name string) (*models.Theme, error) {
 	return theme, nil
 }
 
+// ListThemes 列出全部已保存的主题，包括内置主题的覆盖和用户主题
+func (ts *ThemeService) ListThemes() ([]*models.Theme, error) {
+	db := ts.getDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	rows, err := db.Query(`SELECT id, name, type, colors, tokens, is_default, created_at, updated_at FROM themes ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query themes: %w", err)
+	}
+	defer rows.Close()
+
+	var themes []*models.Theme
+	for rows.Next() {
+		theme := &models.Theme{}
+		if err := rows.Scan(&theme.ID, &theme.Name, &theme.Type, &theme.Colors, &theme.Tokens, &theme.IsDefault, &theme.CreatedAt, &theme.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan theme: %w", err)
+		}
+		themes = append(themes, theme)
+	}
+	return themes, rows.Err()
+}
+
 // UpdateTheme 保存或更新主题覆盖
 func (ts *ThemeService) UpdateTheme(name string, colors models.ThemeColorConfig) error {
 	db := ts.getDB()
 	if db == nil {
 		return fmt.Errorf("database not available")
 	}
+	if err := ts.databaseService.ensureWritable(); err != nil {
+		return err
+	}
 
 	trimmed := strings.TrimSpace(name)
 	if trimmed == "" {
@@ -114,10 +145,11 @@ func (ts *ThemeService) UpdateTheme(name string, colors models.ThemeColorConfig)
 
 	if existing == nil {
 		_, err = db.Exec(
-			`INSERT INTO themes (name, type, colors, is_default, created_at, updated_at) VALUES (?, ?, ?, 0, ?, ?)`,
+			`INSERT INTO themes (name, type, colors, tokens, is_default, created_at, updated_at) VALUES (?, ?, ?, ?, 0, ?, ?)`,
 			trimmed,
 			themeType,
 			colors,
+			tokensFromColors(colors),
 			now,
 			now,
 		)
@@ -128,9 +160,10 @@ func (ts *ThemeService) UpdateTheme(name string, colors models.ThemeColorConfig)
 	}
 
 	_, err = db.Exec(
-		`UPDATE themes SET type = ?, colors = ?, updated_at = ? WHERE name = ?`,
+		`UPDATE themes SET type = ?, colors = ?, tokens = ?, updated_at = ? WHERE name = ?`,
 		themeType,
 		colors,
+		syncDefaultTokens(existing.Tokens, colors),
 		now,
 		trimmed,
 	)
@@ -147,6 +180,9 @@ func (ts *ThemeService) ResetTheme(name string) error {
 	if db == nil {
 		return fmt.Errorf("database not available")
 	}
+	if err := ts.databaseService.ensureWritable(); err != nil {
+		return err
+	}
 
 	trimmed := strings.TrimSpace(name)
 	if trimmed == "" {
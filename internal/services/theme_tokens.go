@@ -0,0 +1,151 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+	"voidraft/internal/common/themecolor"
+	"voidraft/internal/models"
+)
+
+// themeTokenGroupPattern 语言分组名称，与代码块语言标识一致
+var themeTokenGroupPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9+#_-]*$`)
+
+// validateThemeTokens 检查分组名称、语法标签和颜色格式
+func validateThemeTokens(tokens models.ThemeTokens) error {
+	for group, colors := range tokens {
+		if !themeTokenGroupPattern.MatchString(group) {
+			return fmt.Errorf("invalid token group: %q", group)
+		}
+		for tag, color := range colors {
+			if !themecolor.IsSyntaxTag(tag) {
+				return fmt.Errorf("unknown syntax tag in group %s: %q", group, tag)
+			}
+			if _, err := themecolor.Parse(color); err != nil {
+				return fmt.Errorf("invalid color for %s.%s: %w", group, tag, err)
+			}
+		}
+	}
+	return nil
+}
+
+// tokensFromColors 从扁平的主题颜色中提取语法颜色作为 default 分组
+func tokensFromColors(colors models.ThemeColorConfig) models.ThemeTokens {
+	defaults := models.ThemeTokenColors{}
+	for _, tag := range themecolor.SyntaxTags() {
+		if color, ok := colors[tag].(string); ok && color != "" {
+			defaults[tag] = color
+		}
+	}
+	if len(defaults) == 0 {
+		return models.ThemeTokens{}
+	}
+	return models.ThemeTokens{models.ThemeTokenGroupDefault: defaults}
+}
+
+// GetThemeTokens 获取主题按语言分组的语法颜色
+func (ts *ThemeService) GetThemeTokens(name string) (models.ThemeTokens, error) {
+	theme, err := ts.GetThemeByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if theme == nil {
+		return nil, fmt.Errorf("theme not found: %s", name)
+	}
+	return theme.Tokens, nil
+}
+
+// UpdateThemeTokens 保存主题的语法颜色，default 分组同步到扁平颜色配置中以兼容现有编辑器
+func (ts *ThemeService) UpdateThemeTokens(name string, tokens models.ThemeTokens) error {
+	db := ts.getDB()
+	if db == nil {
+		return fmt.Errorf("database not available")
+	}
+	if err := ts.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+	if err := validateThemeTokens(tokens); err != nil {
+		return err
+	}
+
+	theme, err := ts.GetThemeByName(name)
+	if err != nil {
+		return err
+	}
+	if theme == nil {
+		return fmt.Errorf("theme not found: %s", name)
+	}
+
+	colors := theme.Colors
+	if colors == nil {
+		colors = models.ThemeColorConfig{}
+	}
+	for tag, color := range tokens[models.ThemeTokenGroupDefault] {
+		colors[tag] = color
+	}
+
+	_, err = db.Exec(
+		`UPDATE themes SET colors = ?, tokens = ?, updated_at = ? WHERE id = ?`,
+		colors,
+		tokens,
+		time.Now().Format("2006-01-02 15:04:05"),
+		theme.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update theme tokens: %w", err)
+	}
+	return nil
+}
+
+// migrateThemeTokens 为尚无语法颜色分组的主题从扁平颜色配置生成 default 分组
+func (ts *ThemeService) migrateThemeTokens() error {
+	db := ts.getDB()
+	if db == nil || ts.databaseService.ensureWritable() != nil {
+		return nil
+	}
+
+	rows, err := db.Query(`SELECT id, colors FROM themes WHERE tokens IS NULL OR tokens = '' OR tokens = '{}'`)
+	if err != nil {
+		return fmt.Errorf("failed to query themes: %w", err)
+	}
+	pending := make(map[int]models.ThemeTokens)
+	for rows.Next() {
+		var id int
+		var colors models.ThemeColorConfig
+		if err := rows.Scan(&id, &colors); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan theme: %w", err)
+		}
+		if tokens := tokensFromColors(colors); len(tokens) > 0 {
+			pending[id] = tokens
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to query themes: %w", err)
+	}
+
+	for id, tokens := range pending {
+		if _, err := db.Exec(`UPDATE themes SET tokens = ? WHERE id = ?`, tokens, id); err != nil {
+			return fmt.Errorf("failed to migrate theme tokens: %w", err)
+		}
+	}
+	if len(pending) > 0 {
+		ts.logger.Info("Migrated theme syntax colors", "themes", len(pending))
+	}
+	return nil
+}
+
+// syncDefaultTokens 扁平颜色中的语法颜色变化时同步到 default 分组，保留其他语言分组
+func syncDefaultTokens(tokens models.ThemeTokens, colors models.ThemeColorConfig) models.ThemeTokens {
+	synced := models.ThemeTokens{}
+	for group, groupColors := range tokens {
+		if group != models.ThemeTokenGroupDefault {
+			synced[group] = groupColors
+		}
+	}
+	for group, groupColors := range tokensFromColors(colors) {
+		synced[group] = groupColors
+	}
+	return synced
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"testing"
+	"voidraft/internal/models"
+)
+
+func TestThemeTokensMigrationAndSync(t *testing.T) {
+	colors := models.ThemeColorConfig{
+		"background": "#282a36",
+		"keyword":    "#ff79c6",
+		"comment":    "#6272a4",
+		"dark":       true,
+	}
+
+	tokens := tokensFromColors(colors)
+	if len(tokens) != 1 || len(tokens[models.ThemeTokenGroupDefault]) != 2 {
+		t.Fatalf("tokensFromColors = %v", tokens)
+	}
+	if err := validateThemeTokens(tokens); err != nil {
+		t.Fatalf("validateThemeTokens: %v", err)
+	}
+
+	tokens["markdown"] = models.ThemeTokenColors{"heading1": "#bd93f9", "keyword": "#50fa7b"}
+	colors["keyword"] = "#8be9fd"
+	synced := syncDefaultTokens(tokens, colors)
+	if synced[models.ThemeTokenGroupDefault]["keyword"] != "#8be9fd" || synced["markdown"]["heading1"] != "#bd93f9" {
+		t.Fatalf("syncDefaultTokens = %v", synced)
+	}
+
+	resolved := synced.Resolve("markdown")
+	if resolved["keyword"] != "#50fa7b" || resolved["comment"] != "#6272a4" {
+		t.Fatalf("Resolve(markdown) = %v", resolved)
+	}
+
+	for _, invalid := range []models.ThemeTokens{
+		{"Bad Group": {"keyword": "#fff"}},
+		{"default": {"background": "#fff"}},
+		{"default": {"keyword": "blue"}},
+	} {
+		if validateThemeTokens(invalid) == nil {
+			t.Errorf("validateThemeTokens(%v) = nil", invalid)
+		}
+	}
+}
@@ -0,0 +1,418 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// SQL 查询语句
+const (
+	sqlInsertTimeEntry = `
+INSERT INTO time_entries (document_id, started_at, ended_at, duration, note)
+VALUES (?, ?, '', 0, '')`
+
+	sqlGetActiveTimeEntry = `
+SELECT id, document_id, started_at, ended_at, duration, note
+FROM time_entries
+WHERE ended_at = ''
+ORDER BY id DESC
+LIMIT 1`
+
+	sqlStopTimeEntry = `
+UPDATE time_entries
+SET ended_at = ?, duration = ?
+WHERE id = ?`
+
+	sqlUpdateTimeEntryNote = `
+UPDATE time_entries
+SET note = ?
+WHERE id = ?`
+
+	sqlDeleteTimeEntry = `
+DELETE FROM time_entries WHERE id = ? AND ended_at != ''`
+
+	sqlListTimeEntriesByDocument = `
+SELECT id, document_id, started_at, ended_at, duration, note
+FROM time_entries
+WHERE document_id = ?
+ORDER BY started_at DESC`
+
+	sqlListAllTimeEntries = `
+SELECT t.id, t.document_id, t.started_at, t.ended_at, t.duration, t.note, COALESCE(d.title, '')
+FROM time_entries t
+LEFT JOIN documents d ON d.id = t.document_id
+ORDER BY t.started_at`
+
+	sqlSummarizeTimeByDocument = `
+SELECT t.document_id, COALESCE(d.title, ''), SUM(t.duration), COUNT(*)
+FROM time_entries t
+LEFT JOIN documents d ON d.id = t.document_id
+WHERE t.ended_at != ''
+GROUP BY t.document_id
+ORDER BY SUM(t.duration) DESC`
+
+	sqlSummarizeTimeByDay = `
+SELECT t.document_id, COALESCE(d.title, ''), substr(t.started_at, 1, 10) AS day, SUM(t.duration), COUNT(*)
+FROM time_entries t
+LEFT JOIN documents d ON d.id = t.document_id
+WHERE t.ended_at != '' AND day >= ? AND day <= ?
+GROUP BY t.document_id, day
+ORDER BY day DESC, SUM(t.duration) DESC`
+)
+
+// timeEntryLayout 计时记录时间格式，与其他表保持一致
+const timeEntryLayout = "2006-01-02 15:04:05"
+
+// TimeTrackingService 文档计时服务
+type TimeTrackingService struct {
+	databaseService *DatabaseService
+	logger          *log.LogService
+	mu              sync.Mutex
+}
+
+// NewTimeTrackingService 创建文档计时服务
+func NewTimeTrackingService(databaseService *DatabaseService, logger *log.LogService) *TimeTrackingService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	return &TimeTrackingService{
+		databaseService: databaseService,
+		logger:          logger,
+	}
+}
+
+// ServiceStartup 服务启动
+func (ts *TimeTrackingService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	return nil
+}
+
+// StartTimer 为文档开始计时，已有进行中的计时会先被停止
+func (ts *TimeTrackingService) StartTimer(documentID int64) (*models.TimeEntry, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.databaseService == nil || ts.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+	if err := ts.databaseService.ensureWritable(); err != nil {
+		return nil, err
+	}
+
+	active, err := ts.getActiveEntry()
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		if active.DocumentID == documentID {
+			return active, nil
+		}
+		if _, err := ts.stopEntry(active); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().Format(timeEntryLayout)
+	result, err := ts.databaseService.getDB().Exec(sqlInsertTimeEntry, documentID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start timer: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entry ID: %w", err)
+	}
+
+	return &models.TimeEntry{
+		ID:         id,
+		DocumentID: documentID,
+		StartedAt:  now,
+	}, nil
+}
+
+// StopTimer 停止当前计时，没有进行中的计时时返回nil
+func (ts *TimeTrackingService) StopTimer() (*models.TimeEntry, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.databaseService == nil || ts.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	active, err := ts.getActiveEntry()
+	if err != nil || active == nil {
+		return nil, err
+	}
+	return ts.stopEntry(active)
+}
+
+// GetActiveTimer 获取进行中的计时，没有时返回nil
+func (ts *TimeTrackingService) GetActiveTimer() (*models.TimeEntry, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.databaseService == nil || ts.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	active, err := ts.getActiveEntry()
+	if err != nil || active == nil {
+		return nil, err
+	}
+	// 返回已经过的时长，便于前端显示
+	active.Duration = elapsedSeconds(active.StartedAt, time.Now())
+	return active, nil
+}
+
+// SetTimeEntryNote 设置计时记录备注
+func (ts *TimeTrackingService) SetTimeEntryNote(id int64, note string) error {
+	if ts.databaseService == nil || ts.databaseService.getDB() == nil {
+		return errors.New("database service not available")
+	}
+	if err := ts.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	if _, err := ts.databaseService.getDB().Exec(sqlUpdateTimeEntryNote, note, id); err != nil {
+		return fmt.Errorf("failed to update time entry note: %w", err)
+	}
+	return nil
+}
+
+// DeleteTimeEntry 删除已完成的计时记录
+func (ts *TimeTrackingService) DeleteTimeEntry(id int64) error {
+	if ts.databaseService == nil || ts.databaseService.getDB() == nil {
+		return errors.New("database service not available")
+	}
+	if err := ts.databaseService.ensureWritable(); err != nil {
+		return err
+	}
+
+	if _, err := ts.databaseService.getDB().Exec(sqlDeleteTimeEntry, id); err != nil {
+		return fmt.Errorf("failed to delete time entry: %w", err)
+	}
+	return nil
+}
+
+// ListTimeEntries 列出文档的计时记录
+func (ts *TimeTrackingService) ListTimeEntries(documentID int64) ([]*models.TimeEntry, error) {
+	if ts.databaseService == nil || ts.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	rows, err := ts.databaseService.getDB().Query(sqlListTimeEntriesByDocument, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query time entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.TimeEntry
+	for rows.Next() {
+		entry := &models.TimeEntry{}
+		if err := rows.Scan(&entry.ID, &entry.DocumentID, &entry.StartedAt, &entry.EndedAt, &entry.Duration, &entry.Note); err != nil {
+			return nil, fmt.Errorf("failed to scan time entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating time entry rows: %w", err)
+	}
+	return entries, nil
+}
+
+// GetDocumentTimeSummary 按文档汇总已完成的计时
+func (ts *TimeTrackingService) GetDocumentTimeSummary() ([]*models.TimeSummary, error) {
+	if ts.databaseService == nil || ts.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	rows, err := ts.databaseService.getDB().Query(sqlSummarizeTimeByDocument)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize time entries: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []*models.TimeSummary
+	for rows.Next() {
+		summary := &models.TimeSummary{}
+		if err := rows.Scan(&summary.DocumentID, &summary.Title, &summary.Duration, &summary.EntryCount); err != nil {
+			return nil, fmt.Errorf("failed to scan time summary: %w", err)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating time summary rows: %w", err)
+	}
+	return summaries, nil
+}
+
+// GetDailyTimeSummary 按文档和日期汇总计时，日期格式为 YYYY-MM-DD
+func (ts *TimeTrackingService) GetDailyTimeSummary(from, to string) ([]*models.TimeSummary, error) {
+	if ts.databaseService == nil || ts.databaseService.getDB() == nil {
+		return nil, errors.New("database service not available")
+	}
+
+	if from == "" {
+		from = "0000-00-00"
+	}
+	if to == "" {
+		to = "9999-99-99"
+	}
+
+	rows, err := ts.databaseService.getDB().Query(sqlSummarizeTimeByDay, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize time entries: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []*models.TimeSummary
+	for rows.Next() {
+		summary := &models.TimeSummary{}
+		if err := rows.Scan(&summary.DocumentID, &summary.Title, &summary.Date, &summary.Duration, &summary.EntryCount); err != nil {
+			return nil, fmt.Errorf("failed to scan time summary: %w", err)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating time summary rows: %w", err)
+	}
+	return summaries, nil
+}
+
+// ExportTimeEntriesCSV 导出全部计时记录为CSV文件
+func (ts *TimeTrackingService) ExportTimeEntriesCSV(path string) error {
+	if ts.databaseService == nil || ts.databaseService.getDB() == nil {
+		return errors.New("database service not available")
+	}
+	if path == "" {
+		return errors.New("export path is empty")
+	}
+
+	rows, err := ts.databaseService.getDB().Query(sqlListAllTimeEntries)
+	if err != nil {
+		return fmt.Errorf("failed to query time entries: %w", err)
+	}
+	defer rows.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"id", "document_id", "document_title", "date", "started_at", "ended_at", "duration_seconds", "duration_hours", "note"}); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	now := time.Now()
+	for rows.Next() {
+		var entry models.TimeEntry
+		var title string
+		if err := rows.Scan(&entry.ID, &entry.DocumentID, &entry.StartedAt, &entry.EndedAt, &entry.Duration, &entry.Note, &title); err != nil {
+			return fmt.Errorf("failed to scan time entry: %w", err)
+		}
+		if entry.IsRunning() {
+			entry.Duration = elapsedSeconds(entry.StartedAt, now)
+		}
+
+		date := entry.StartedAt
+		if len(date) >= 10 {
+			date = date[:10]
+		}
+
+		record := []string{
+			strconv.FormatInt(entry.ID, 10),
+			strconv.FormatInt(entry.DocumentID, 10),
+			title,
+			date,
+			entry.StartedAt,
+			entry.EndedAt,
+			strconv.FormatInt(entry.Duration, 10),
+			strconv.FormatFloat(float64(entry.Duration)/3600, 'f', 2, 64),
+			entry.Note,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv record: %w", err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating time entry rows: %w", err)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// getActiveEntry 查询进行中的计时
+func (ts *TimeTrackingService) getActiveEntry() (*models.TimeEntry, error) {
+	entry := &models.TimeEntry{}
+	err := ts.databaseService.getDB().QueryRow(sqlGetActiveTimeEntry).Scan(
+		&entry.ID, &entry.DocumentID, &entry.StartedAt, &entry.EndedAt, &entry.Duration, &entry.Note)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query active timer: %w", err)
+	}
+	return entry, nil
+}
+
+// stopEntry 结束计时并写入时长
+func (ts *TimeTrackingService) stopEntry(entry *models.TimeEntry) (*models.TimeEntry, error) {
+	if err := ts.databaseService.ensureWritable(); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	entry.EndedAt = now.Format(timeEntryLayout)
+	entry.Duration = elapsedSeconds(entry.StartedAt, now)
+
+	if _, err := ts.databaseService.getDB().Exec(sqlStopTimeEntry, entry.EndedAt, entry.Duration, entry.ID); err != nil {
+		return nil, fmt.Errorf("failed to stop timer: %w", err)
+	}
+	return entry, nil
+}
+
+// elapsedSeconds 计算从开始时间到指定时间经过的秒数
+func elapsedSeconds(startedAt string, now time.Time) int64 {
+	start, err := time.ParseInLocation(timeEntryLayout, startedAt, time.Local)
+	if err != nil {
+		return 0
+	}
+	elapsed := int64(now.Sub(start).Seconds())
+	if elapsed < 0 {
+		return 0
+	}
+	return elapsed
+}
+
+// ServiceShutdown 应用退出时停止进行中的计时，避免把关闭期间计入时长
+func (ts *TimeTrackingService) ServiceShutdown() error {
+	if ts.databaseService == nil || ts.databaseService.getDB() == nil {
+		return nil
+	}
+	if _, err := ts.StopTimer(); err != nil {
+		ts.logger.Error("Failed to stop timer on shutdown", "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestTimeTrackingService 基于内存数据库创建计时服务
+func newTestTimeTrackingService(t *testing.T) *TimeTrackingService {
+	t.Helper()
+	return NewTimeTrackingService(newTestDatabaseService(t, sqlCreateDocumentsTable, sqlCreateTimeEntriesTable), nil)
+}
+
+// backdateTimeEntry 把计时记录的开始时间提前，模拟经过的时长
+func backdateTimeEntry(t *testing.T, ts *TimeTrackingService, id int64, elapsed time.Duration) {
+	t.Helper()
+	started := time.Now().Add(-elapsed).Format(timeEntryLayout)
+	if _, err := ts.databaseService.getDB().Exec(`UPDATE time_entries SET started_at = ? WHERE id = ?`, started, id); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTimerStartStop(t *testing.T) {
+	ts := newTestTimeTrackingService(t)
+
+	if entry, err := ts.StopTimer(); err != nil || entry != nil {
+		t.Fatalf("StopTimer without active timer = %v, %v, want nil", entry, err)
+	}
+
+	first, err := ts.StartTimer(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 同一文档重复开始返回进行中的计时
+	again, err := ts.StartTimer(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != first.ID {
+		t.Errorf("StartTimer on the running document created entry %d, want %d", again.ID, first.ID)
+	}
+
+	backdateTimeEntry(t, ts, first.ID, 90*time.Second)
+	active, err := ts.GetActiveTimer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if active == nil || active.ID != first.ID || active.Duration < 90 {
+		t.Fatalf("GetActiveTimer = %+v, want entry %d with at least 90s elapsed", active, first.ID)
+	}
+	if err := ts.DeleteTimeEntry(first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := ts.ListTimeEntries(1); len(entries) != 1 {
+		t.Errorf("running entry was deleted, entries = %d", len(entries))
+	}
+
+	// 开始另一个文档的计时会结束当前计时
+	second, err := ts.StartTimer(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ts.ListTimeEntries(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].EndedAt == "" || entries[0].Duration < 90 || entries[0].Duration > 100 {
+		t.Fatalf("entries of document 1 = %+v, want one stopped entry of about 90s", entries)
+	}
+
+	stopped, err := ts.StopTimer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stopped == nil || stopped.ID != second.ID || stopped.EndedAt == "" {
+		t.Fatalf("StopTimer = %+v, want stopped entry %d", stopped, second.ID)
+	}
+	if active, err := ts.GetActiveTimer(); err != nil || active != nil {
+		t.Errorf("GetActiveTimer after stop = %v, %v, want nil", active, err)
+	}
+}
+
+func TestTimerStoppedOnShutdown(t *testing.T) {
+	ts := newTestTimeTrackingService(t)
+	entry, err := ts.StartTimer(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backdateTimeEntry(t, ts, entry.ID, time.Minute)
+
+	// 退出时结束计时，关闭期间不计入时长
+	if err := ts.ServiceShutdown(); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ts.ListTimeEntries(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].EndedAt == "" || entries[0].Duration < 60 || entries[0].Duration > 70 {
+		t.Fatalf("entries after shutdown = %+v, want one stopped entry of about 60s", entries)
+	}
+}
+
+func TestElapsedSeconds(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		started string
+		want    int64
+	}{
+		{"2026-10-16 11:58:30", 90},
+		{"2026-10-16 12:00:10", 0}, // 时钟回拨不产生负时长
+		{"not a time", 0},
+	}
+	for _, tt := range tests {
+		if got := elapsedSeconds(tt.started, now); got != tt.want {
+			t.Errorf("elapsedSeconds(%q) = %d, want %d", tt.started, got, tt.want)
+		}
+	}
+}
+
+func TestTimeSummaries(t *testing.T) {
+	ts := newTestTimeTrackingService(t)
+	db := ts.databaseService.getDB()
+	if _, err := db.Exec(`INSERT INTO documents (id, title, content, created_at, updated_at) VALUES (1, 'Client A', '', '', ''), (2, 'Client B', '', '', '')`); err != nil {
+		t.Fatal(err)
+	}
+	entries := []struct {
+		document int64
+		started  string
+		ended    string
+		duration int64
+	}{
+		{1, "2026-10-14 09:00:00", "2026-10-14 10:00:00", 3600},
+		{1, "2026-10-15 09:00:00", "2026-10-15 09:30:00", 1800},
+		{1, "2026-10-15 14:00:00", "2026-10-15 14:10:00", 600},
+		{2, "2026-10-15 11:00:00", "2026-10-15 13:00:00", 7200},
+		{2, "2026-10-16 08:00:00", "", 0}, // 进行中的计时不计入汇总
+	}
+	for _, e := range entries {
+		if _, err := db.Exec(`INSERT INTO time_entries (document_id, started_at, ended_at, duration, note) VALUES (?, ?, ?, ?, '')`,
+			e.document, e.started, e.ended, e.duration); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	totals, err := ts.GetDocumentTimeSummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(totals) != 2 {
+		t.Fatalf("document summaries = %d, want 2", len(totals))
+	}
+	if got := totals[0]; got.DocumentID != 2 || got.Title != "Client B" || got.Duration != 7200 || got.EntryCount != 1 {
+		t.Errorf("first summary = %+v, want Client B 7200s in 1 entry", got)
+	}
+	if got := totals[1]; got.DocumentID != 1 || got.Duration != 6000 || got.EntryCount != 3 {
+		t.Errorf("second summary = %+v, want Client A 6000s in 3 entries", got)
+	}
+
+	daily, err := ts.GetDailyTimeSummary("2026-10-15", "2026-10-16")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(daily) != 2 {
+		t.Fatalf("daily summaries = %d, want 2", len(daily))
+	}
+	if got := daily[0]; got.DocumentID != 2 || got.Date != "2026-10-15" || got.Duration != 7200 {
+		t.Errorf("first daily summary = %+v", got)
+	}
+	if got := daily[1]; got.DocumentID != 1 || got.Date != "2026-10-15" || got.Duration != 2400 || got.EntryCount != 2 {
+		t.Errorf("second daily summary = %+v", got)
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"time"
+	"voidraft/internal/common/timeparse"
+
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// defaultTimestampZones 未指定目标时区时换算的时区
+var defaultTimestampZones = []string{"Local", "UTC"}
+
+// TimestampService 时间戳与时区换算服务，供日期块和提醒解析使用
+type TimestampService struct {
+	logger *log.LogService
+}
+
+// NewTimestampService 创建时间戳服务实例
+func NewTimestampService(logger *log.LogService) *TimestampService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &TimestampService{logger: logger}
+}
+
+// ParseTimestamp 解析时间戳、ISO 时间或自然语言短语，并换算到指定时区
+// zone 为输入中没有时区时使用的时区，为空表示本地时区；zones 为空时换算到本地时区和 UTC
+func (ts *TimestampService) ParseTimestamp(input string, zone string, zones []string) (*timeparse.Result, error) {
+	loc, err := timeparse.LoadZone(zone)
+	if err != nil {
+		return nil, err
+	}
+	t, err := timeparse.Parse(input, time.Now(), loc)
+	if err != nil {
+		return nil, err
+	}
+	if len(zones) == 0 {
+		zones = defaultTimestampZones
+	}
+	return timeparse.Convert(t, zones)
+}
+
+// ValidateTimezone 检查时区名称是否有效，支持 IANA 名称、常用缩写和 UTC 偏移
+func (ts *TimestampService) ValidateTimezone(zone string) error {
+	_, err := timeparse.LoadZone(zone)
+	return err
+}
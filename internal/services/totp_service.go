@@ -0,0 +1,211 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"voidraft/internal/common/keystore"
+	"voidraft/internal/common/totp"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+)
+
+// totpKeyringKey 钥匙串中保存 TOTP 密钥的键
+func totpKeyringKey(name string) string {
+	return "totp:" + name
+}
+
+// TOTPService 一次性密码服务，密钥保存在系统钥匙串中，读取前需解除应用锁
+type TOTPService struct {
+	logger         *log.LogService
+	configService  *ConfigService
+	appLockService *AppLockService
+
+	menuMu         sync.Mutex
+	menu           *application.Menu // 托盘中的一次性密码子菜单，账户变化时重建
+	cancelObserver CancelFunc
+}
+
+// NewTOTPService 创建一次性密码服务
+func NewTOTPService(configService *ConfigService, appLockService *AppLockService, logger *log.LogService) *TOTPService {
+	if logger == nil {
+		logger = log.New()
+	}
+
+	return &TOTPService{
+		logger:         logger,
+		configService:  configService,
+		appLockService: appLockService,
+	}
+}
+
+// ListTOTP 获取已保存的一次性密码账户，不包含密钥
+func (ts *TOTPService) ListTOTP() []models.TOTPAccount {
+	config, err := ts.configService.GetConfig()
+	if err != nil {
+		return []models.TOTPAccount{}
+	}
+	accounts := config.Security.TOTPAccounts
+	if accounts == nil {
+		accounts = []models.TOTPAccount{}
+	}
+	return accounts
+}
+
+// AddTOTP 保存一次性密码账户，secret 可以是 otpauth:// 链接或 Base32 密钥
+func (ts *TOTPService) AddTOTP(name, secret string) (*models.TOTPAccount, error) {
+	if err := ts.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	key, err := totp.Parse(secret)
+	if err != nil {
+		return nil, err
+	}
+	accounts := ts.ListTOTP()
+	for _, account := range accounts {
+		if account.Name == name {
+			return nil, fmt.Errorf("TOTP account already exists: %s", name)
+		}
+	}
+
+	if err := keystore.Set(totpKeyringKey(name), key.Secret); err != nil {
+		return nil, fmt.Errorf("store TOTP secret: %w", err)
+	}
+	account := models.TOTPAccount{
+		Name:      name,
+		Issuer:    key.Issuer,
+		Account:   key.Account,
+		Algorithm: key.Algorithm,
+		Digits:    key.Digits,
+		Period:    key.Period,
+	}
+	if err := ts.configService.Set("security.totpAccounts", append(accounts, account)); err != nil {
+		_ = keystore.Delete(totpKeyringKey(name))
+		return nil, err
+	}
+	return &account, nil
+}
+
+// RemoveTOTP 删除一次性密码账户及其密钥
+func (ts *TOTPService) RemoveTOTP(name string) error {
+	if err := ts.appLockService.ensureUnlocked(); err != nil {
+		return err
+	}
+	accounts := ts.ListTOTP()
+	kept := make([]models.TOTPAccount, 0, len(accounts))
+	for _, account := range accounts {
+		if account.Name != name {
+			kept = append(kept, account)
+		}
+	}
+	if len(kept) == len(accounts) {
+		return fmt.Errorf("TOTP account not found: %s", name)
+	}
+
+	if err := keystore.Delete(totpKeyringKey(name)); err != nil && !errors.Is(err, keystore.ErrNotFound) {
+		return fmt.Errorf("delete TOTP secret: %w", err)
+	}
+	return ts.configService.Set("security.totpAccounts", kept)
+}
+
+// GetTOTP 生成指定账户当前的一次性密码
+func (ts *TOTPService) GetTOTP(name string) (*models.TOTPCode, error) {
+	if err := ts.appLockService.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	var account *models.TOTPAccount
+	for _, a := range ts.ListTOTP() {
+		if a.Name == name {
+			account = &a
+			break
+		}
+	}
+	if account == nil {
+		return nil, fmt.Errorf("TOTP account not found: %s", name)
+	}
+
+	secret, err := keystore.Get(totpKeyringKey(name))
+	if err != nil {
+		return nil, fmt.Errorf("read TOTP secret: %w", err)
+	}
+	key := &totp.Key{
+		Issuer:    account.Issuer,
+		Account:   account.Account,
+		Secret:    secret,
+		Algorithm: account.Algorithm,
+		Digits:    account.Digits,
+		Period:    account.Period,
+	}
+	code, remaining, err := key.Code(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &models.TOTPCode{Code: code, Remaining: remaining, Period: key.Period}, nil
+}
+
+// CopyTOTP 将指定账户当前的一次性密码复制到剪贴板
+func (ts *TOTPService) CopyTOTP(name string) error {
+	code, err := ts.GetTOTP(name)
+	if err != nil {
+		return err
+	}
+	if !application.Get().Clipboard.SetText(code.Code) {
+		return fmt.Errorf("failed to copy TOTP code to clipboard")
+	}
+	return nil
+}
+
+// AttachTrayMenu 关联托盘中的一次性密码子菜单，并在账户变化时重建
+func (ts *TOTPService) AttachTrayMenu(menu *application.Menu) {
+	ts.menuMu.Lock()
+	ts.menu = menu
+	if ts.cancelObserver == nil {
+		ts.cancelObserver = ts.configService.Watch("security.totpAccounts", func(oldValue, newValue interface{}) {
+			ts.rebuildTrayMenu()
+		})
+	}
+	ts.menuMu.Unlock()
+
+	ts.rebuildTrayMenu()
+}
+
+// rebuildTrayMenu 按当前账户重建托盘子菜单，点击菜单项复制当前密码
+func (ts *TOTPService) rebuildTrayMenu() {
+	ts.menuMu.Lock()
+	defer ts.menuMu.Unlock()
+	if ts.menu == nil {
+		return
+	}
+
+	ts.menu.Clear()
+	accounts := ts.ListTOTP()
+	if len(accounts) == 0 {
+		ts.menu.Add("No TOTP accounts").SetEnabled(false)
+	}
+	for _, account := range accounts {
+		name := account.Name
+		ts.menu.Add(name).OnClick(func(data *application.Context) {
+			if err := ts.CopyTOTP(name); err != nil {
+				ts.logger.Error("Failed to copy TOTP code", "account", name, "error", err)
+			}
+		})
+	}
+	ts.menu.Update()
+}
+
+// ServiceShutdown 取消配置监听
+func (ts *TOTPService) ServiceShutdown() error {
+	if ts.cancelObserver != nil {
+		ts.cancelObserver()
+	}
+	return nil
+}
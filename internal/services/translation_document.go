@@ -0,0 +1,170 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"voidraft/internal/common/blocks"
+	"voidraft/internal/common/langdetect"
+	"voidraft/internal/common/translator"
+	"voidraft/internal/models"
+)
+
+// proseSegment 文本块中的片段，translate 为 false 时原样保留
+type proseSegment struct {
+	text      string
+	translate bool
+}
+
+// TranslateDocument 翻译整个文档中的自然语言块，代码块、行内代码、URL 和占位符保持不变
+// 按块识别源语言，已是目标语言的块保持不变
+// 结果以一次文档补丁写回，可整体撤销；翻译期间文档被修改时放弃写入
+// @param {int64} id - 文档ID
+// @param {string} to - 目标语言代码
+// @param {string} provider - 翻译器类型，为空时使用默认翻译器
+// @returns {DocumentPatchResult} 补丁应用结果
+// @returns {error} 可能的错误
+func (s *TranslationService) TranslateDocument(id int64, to string, provider string) (*models.DocumentPatchResult, error) {
+	doc, err := s.documentService.GetDocumentByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil || doc.IsDeleted {
+		return nil, fmt.Errorf("document not found: %d", id)
+	}
+
+	var sb strings.Builder
+	translated, skipped := 0, 0
+	for _, block := range blocks.Parse(doc.Content) {
+		sb.WriteString(doc.Content[block.From:block.Start])
+		if !blocks.IsProse(block.Language) {
+			sb.WriteString(block.Content)
+			continue
+		}
+		// 源语言仍交给翻译器识别，各翻译器的语言代码不尽相同
+		if isTargetLanguage(langdetect.Detect(block.Content), to) {
+			sb.WriteString(block.Content)
+			continue
+		}
+
+		for _, segment := range splitProseSegments(block.Content, block.Language == "md") {
+			if !segment.translate {
+				sb.WriteString(segment.text)
+				continue
+			}
+			result, err := s.translateSegment(segment.text, to, provider)
+			if errors.Is(err, translator.ErrPlaceholderLost) {
+				// 译文破坏了受保护片段时保留原文
+				sb.WriteString(segment.text)
+				skipped++
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			sb.WriteString(result)
+			translated++
+		}
+	}
+
+	content := sb.String()
+	if content == doc.Content {
+		return nil, errors.New("nothing to translate")
+	}
+
+	s.logger.Info("Document translated", "id", id, "to", to, "segments", translated, "skipped", skipped)
+	return s.documentService.ApplyDocumentPatch(id, models.DocumentPatch{
+		From:   0,
+		To:     utf16Len(doc.Content),
+		Insert: content,
+		Expect: doc.Content,
+		Label:  "translate document",
+	})
+}
+
+// translateSegment 翻译单个段落，保留首尾空白和受保护片段
+func (s *TranslationService) translateSegment(text string, to string, provider string) (string, error) {
+	core := strings.TrimSpace(text)
+	leading := text[:strings.Index(text, core)]
+	trailing := text[len(leading)+len(core):]
+
+	protected := translator.Protect(core)
+	result, err := s.TranslateWith(protected.Text, "auto", to, provider)
+	if err != nil {
+		return "", err
+	}
+	restored, err := protected.Restore(strings.TrimSpace(result))
+	if err != nil {
+		return "", err
+	}
+	return leading + restored + trailing, nil
+}
+
+// isTargetLanguage 识别出的语言是否已是目标语言；目标语言带地区时只比较主语言，中文的简繁体除外
+func isTargetLanguage(detected, to string) bool {
+	if detected == "" {
+		return false
+	}
+	to = strings.ToLower(strings.ReplaceAll(to, "_", "-"))
+	base, region, _ := strings.Cut(to, "-")
+	return base == detected && (region == "" || detected != "zh")
+}
+
+// splitProseSegments 按空行将文本拆分为段落，Markdown 的围栏代码块整体保留
+func splitProseSegments(content string, markdown bool) []proseSegment {
+	var segments []proseSegment
+	var paragraph strings.Builder
+	flush := func() {
+		if paragraph.Len() == 0 {
+			return
+		}
+		text := paragraph.String()
+		segments = append(segments, proseSegment{text: text, translate: hasLetters(text)})
+		paragraph.Reset()
+	}
+	keep := func(line string) {
+		if n := len(segments); n > 0 && !segments[n-1].translate {
+			segments[n-1].text += line
+			return
+		}
+		segments = append(segments, proseSegment{text: line})
+	}
+
+	fence := ""
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if line == "" {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			keep(line)
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if markdown && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")) {
+			flush()
+			fence = trimmed[:3]
+			keep(line)
+			continue
+		}
+		if trimmed == "" {
+			flush()
+			keep(line)
+			continue
+		}
+		paragraph.WriteString(line)
+	}
+	flush()
+	return segments
+}
+
+// hasLetters 文本中是否包含文字，纯数字和符号无需翻译
+func hasLetters(text string) bool {
+	return strings.IndexFunc(translator.Protect(text).Text, func(r rune) bool {
+		return unicode.IsLetter(r)
+	}) >= 0
+}
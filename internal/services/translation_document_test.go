@@ -0,0 +1,71 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"voidraft/internal/models"
+)
+
+func TestSplitProseSegments(t *testing.T) {
+	content := "# Title\n\nSome text\nmore text\n\n```go\nfmt.Println(\"hi\")\n\nx := 1\n```\n123\n"
+	segments := splitProseSegments(content, true)
+
+	var rebuilt strings.Builder
+	var translated []string
+	for _, segment := range segments {
+		rebuilt.WriteString(segment.text)
+		if segment.translate {
+			translated = append(translated, segment.text)
+		}
+	}
+	if rebuilt.String() != content {
+		t.Fatalf("segments do not rebuild content: %q", rebuilt.String())
+	}
+	if len(translated) != 2 || translated[0] != "# Title\n" || translated[1] != "Some text\nmore text\n" {
+		t.Fatalf("translated segments = %q", translated)
+	}
+}
+
+func TestResolveTargetLanguage(t *testing.T) {
+	config := &models.TranslationConfig{
+		DefaultTarget: "en",
+		TargetRules: []models.TranslationTargetRule{
+			{Source: "zh-TW", Target: "ja"},
+			{Source: "zh", Target: "en"},
+			{Source: "de", Target: "en"},
+			{Source: "en", Target: "zh"},
+		},
+	}
+	tests := map[string]string{
+		"zh-TW": "ja",
+		"zh_CN": "en",
+		"zh":    "en",
+		"en-US": "zh",
+		"fr":    "en",
+	}
+	for detected, want := range tests {
+		if got := resolveTargetLanguage(config, detected); got != want {
+			t.Errorf("resolveTargetLanguage(%q) = %q, want %q", detected, got, want)
+		}
+	}
+}
+
+func TestIsTargetLanguage(t *testing.T) {
+	tests := []struct {
+		detected, to string
+		want         bool
+	}{
+		{"", "en", false},
+		{"en", "en", true},
+		{"en", "EN-us", true},
+		{"de", "en", false},
+		{"zh", "zh", true},
+		{"zh", "zh-TW", false},
+		{"pt", "pt_BR", true},
+	}
+	for _, tt := range tests {
+		if got := isTargetLanguage(tt.detected, tt.to); got != tt.want {
+			t.Errorf("isTargetLanguage(%q, %q) = %v, want %v", tt.detected, tt.to, got, tt.want)
+		}
+	}
+}
@@ -1,21 +1,44 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
+	"voidraft/internal/common/cache"
+	"voidraft/internal/common/langdetect"
 	"voidraft/internal/common/translator"
+	"voidraft/internal/common/translit"
+	"voidraft/internal/models"
 
+	"github.com/wailsapp/wails/v3/pkg/application"
 	"github.com/wailsapp/wails/v3/pkg/services/log"
 )
 
+const (
+	// translatorPluginDir 翻译插件清单目录，位于配置目录下
+	translatorPluginDir = "translators"
+	// dictionaryCacheTTL 词典查询结果缓存时间
+	dictionaryCacheTTL = 24 * time.Hour
+	// dictionaryMaxWordLength 词典查询的最大长度（字符）
+	dictionaryMaxWordLength = 64
+)
+
 // TranslationService 翻译服务
 // 提供翻译功能的核心服务结构体，管理多种翻译器实例并提供翻译服务
 type TranslationService struct {
-	logger         *log.LogService                                     // 日志服务实例，用于记录翻译过程中的日志信息
-	factory        *translator.TranslatorFactory                       // 翻译器工厂，用于创建不同类型的翻译器实例
-	defaultTimeout time.Duration                                       // 默认超时时间，用于控制翻译请求的最大等待时间
-	translators    map[translator.TranslatorType]translator.Translator // 翻译器映射表，存储已创建的翻译器实例
-	mutex          sync.RWMutex                                        // 读写锁，保证并发访问翻译器映射表的安全性
+	logger          *log.LogService                                     // 日志服务实例，用于记录翻译过程中的日志信息
+	configService   *ConfigService                                      // 配置服务，用于定位翻译插件目录
+	documentService *DocumentService                                    // 文档服务，用于选区翻译写回文档
+	factory         *translator.TranslatorFactory                       // 翻译器工厂，用于创建不同类型的翻译器实例
+	defaultTimeout  time.Duration                                       // 默认超时时间，用于控制翻译请求的最大等待时间
+	translators     map[translator.TranslatorType]translator.Translator // 翻译器映射表，存储已创建的翻译器实例
+	mutex           sync.RWMutex                                        // 读写锁，保证并发访问翻译器映射表的安全性
+	dictionaryCache *cache.Cache[string, *translator.DictionaryEntry]   // 词典查询缓存，避免重复请求
 }
 
 // NewTranslationService 创建翻译服务实例
@@ -23,22 +46,95 @@ type TranslationService struct {
 //
 // 参数:
 //
+//	configService - 配置服务实例，用于定位翻译插件目录
+//	documentService - 文档服务实例，用于选区翻译写回文档
 //	logger - 日志服务实例，用于记录翻译过程中的日志信息
 //
 // 返回值:
 //
 //	*TranslationService - 初始化完成的翻译服务实例
-func NewTranslationService(logger *log.LogService) *TranslationService {
+func NewTranslationService(configService *ConfigService, documentService *DocumentService, logger *log.LogService) *TranslationService {
+	if logger == nil {
+		logger = log.New()
+	}
+
 	// 初始化翻译服务的基本配置
 	service := &TranslationService{
-		logger:         logger,
-		factory:        translator.NewTranslatorFactory(),
-		defaultTimeout: 10 * time.Second,
-		translators:    make(map[translator.TranslatorType]translator.Translator),
+		logger:          logger,
+		configService:   configService,
+		documentService: documentService,
+		factory:         translator.NewTranslatorFactory(),
+		defaultTimeout:  10 * time.Second,
+		translators:     make(map[translator.TranslatorType]translator.Translator),
+		dictionaryCache: cache.New[string, *translator.DictionaryEntry](dictionaryCacheTTL),
 	}
 	return service
 }
 
+// ServiceStartup 服务启动时加载翻译插件
+func (s *TranslationService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	s.loadPlugins()
+	return nil
+}
+
+// ReloadTranslatorPlugins 重新加载翻译插件清单
+// 读取配置目录下 translators/*.json，无效的清单会被跳过并记录日志
+// @returns {[]string} 加载成功的插件名称
+func (s *TranslationService) ReloadTranslatorPlugins() []string {
+	return s.loadPlugins()
+}
+
+// loadPlugins 扫描插件目录并注册到翻译器工厂，替换之前加载的插件
+func (s *TranslationService) loadPlugins() []string {
+	if s.configService == nil {
+		return nil
+	}
+
+	dir := filepath.Join(s.configService.configDir, translatorPluginDir)
+	plugins, errs := translator.LoadPluginManifests(dir)
+	for _, err := range errs {
+		s.logger.Warning("Failed to load translator plugin", "error", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// 丢弃旧插件的缓存实例，内置翻译器保留
+	s.factory.ClearPlugins()
+	for _, translatorType := range s.pluginTypes() {
+		delete(s.translators, translatorType)
+	}
+
+	names := make([]string, 0, len(plugins))
+	for _, plugin := range plugins {
+		if err := s.factory.Register(plugin.Type(), func() translator.Translator { return plugin }); err != nil {
+			s.logger.Warning("Failed to register translator plugin", "name", plugin.Type(), "error", err)
+			continue
+		}
+		names = append(names, string(plugin.Type()))
+	}
+	if len(names) > 0 {
+		s.logger.Info("Translator plugins loaded", "plugins", names)
+	}
+	return names
+}
+
+// pluginTypes 已缓存的非内置翻译器类型，调用方需持有锁
+func (s *TranslationService) pluginTypes() []translator.TranslatorType {
+	builtin := make(map[translator.TranslatorType]bool)
+	for _, translatorType := range s.factory.Types() {
+		builtin[translatorType] = true
+	}
+
+	var types []translator.TranslatorType
+	for translatorType := range s.translators {
+		if !builtin[translatorType] {
+			types = append(types, translatorType)
+		}
+	}
+	return types
+}
+
 // getTranslator 获取指定类型的翻译器，如不存在则创建
 // getTranslator 根据翻译器类型获取对应的翻译器实例
 // 如果该类型的翻译器已存在则直接返回，否则创建新的实例并缓存
@@ -88,7 +184,7 @@ func (s *TranslationService) TranslateWith(text string, from string, to string,
 		return "", nil
 	}
 	if translatorType == "" {
-		translatorType = string(translator.BingTranslatorType)
+		translatorType = s.defaultTranslator()
 	}
 
 	// 转换为翻译器类型
@@ -111,16 +207,238 @@ func (s *TranslationService) TranslateWith(text string, from string, to string,
 	return trans.TranslateWithParams(text, params)
 }
 
-// GetTranslators 获取所有可用翻译器类型
+// TranslateSelection 翻译文档中的选区并写回文档
+// 写入通过文档补丁完成，可用 DocumentService.UndoDocumentPatch 撤销；
+// 翻译期间选区被修改时放弃写入
+// @param {int64} documentID - 文档ID
+// @param {TextRange} selection - 选区范围（UTF-16 偏移）
+// @param {string} mode - 写入方式 ("replace", "append", "side-by-side")
+// @param {string} from - 源语言代码
+// @param {string} to - 目标语言代码
+// @param {string} translatorType - 翻译器类型，为空时使用默认翻译器
+// @returns {DocumentPatchResult} 补丁应用结果
+// @returns {error} 可能的错误
+func (s *TranslationService) TranslateSelection(documentID int64, selection models.TextRange, mode models.TranslateSelectionMode, from string, to string, translatorType string) (*models.DocumentPatchResult, error) {
+	if !mode.IsValid() {
+		return nil, fmt.Errorf("invalid translate mode: %s", mode)
+	}
+	if selection.From >= selection.To {
+		return nil, errors.New("selection is empty")
+	}
+
+	doc, err := s.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil || doc.IsDeleted {
+		return nil, fmt.Errorf("document not found: %d", documentID)
+	}
+
+	start, ok := utf16ByteOffset(doc.Content, selection.From)
+	end, ok2 := utf16ByteOffset(doc.Content, selection.To)
+	if !ok || !ok2 {
+		return nil, ErrPatchOutOfRange
+	}
+	original := doc.Content[start:end]
+
+	translated, err := s.TranslateWith(original, from, to, translatorType)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := models.DocumentPatch{
+		From:   selection.From,
+		To:     selection.To,
+		Insert: composeTranslation(original, translated, mode),
+		Expect: original,
+		Label:  "translate",
+	}
+	return s.documentService.ApplyDocumentPatch(documentID, patch)
+}
+
+// composeTranslation 按写入方式组合原文和译文
+// 逐行对照要求行数一致，否则退化为追加
+func composeTranslation(original, translated string, mode models.TranslateSelectionMode) string {
+	translated = strings.TrimRight(translated, "\n")
+
+	switch mode {
+	case models.TranslateModeReplace:
+		return translated
+	case models.TranslateModeSideBySide:
+		originalLines := strings.Split(original, "\n")
+		translatedLines := strings.Split(translated, "\n")
+		if len(originalLines) == len(translatedLines) {
+			var sb strings.Builder
+			for i, line := range originalLines {
+				if i > 0 {
+					sb.WriteByte('\n')
+				}
+				sb.WriteString(line)
+				if strings.TrimSpace(line) != "" {
+					sb.WriteByte('\n')
+					sb.WriteString(translatedLines[i])
+				}
+			}
+			return sb.String()
+		}
+	}
+	return strings.TrimRight(original, "\n") + "\n" + translated + original[len(strings.TrimRight(original, "\n")):]
+}
+
+// Transliterate 按方案转写文本
+// 假名和西里尔字母在本地转写；汉字读音（拼音、日文汉字）由翻译服务提供，
+// 日文中的汉字读音获取失败时保留汉字，只转写假名
+// @param {string} text - 待转写文本
+// @param {string} scheme - 转写方案 ("pinyin", "romaji", "cyrillic-latin")
+// @returns {string} 转写结果
+// @returns {error} 可能的错误
+func (s *TranslationService) Transliterate(text string, scheme string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+
+	switch translit.Scheme(scheme) {
+	case translit.SchemeCyrillicLatin:
+		return translit.CyrillicToLatin(text), nil
+	case translit.SchemeRomaji:
+		if translit.ContainsKanji(text) {
+			romanized, err := s.romanize(text, "ja")
+			if err == nil {
+				return romanized, nil
+			}
+			s.logger.Warning("Failed to romanize kanji, transliterating kana only", "error", err)
+		}
+		return translit.KanaToRomaji(text), nil
+	case translit.SchemePinyin:
+		return s.romanize(text, "zh-CN")
+	default:
+		return "", fmt.Errorf("unsupported transliteration scheme: %s", scheme)
+	}
+}
+
+// GetTransliterationSchemes 获取支持的转写方案
+// @returns {[]string} 转写方案列表
+func (s *TranslationService) GetTransliterationSchemes() []string {
+	schemes := make([]string, 0, len(translit.Schemes))
+	for _, scheme := range translit.Schemes {
+		schemes = append(schemes, string(scheme))
+	}
+	return schemes
+}
+
+// romanize 通过支持读音转写的翻译器获取读音
+func (s *TranslationService) romanize(text string, from string) (string, error) {
+	trans, err := s.getTranslator(translator.GoogleTranslatorType)
+	if err != nil {
+		return "", err
+	}
+	romanizer, ok := trans.(translator.Romanizer)
+	if !ok {
+		return "", errors.New("no translator provides romanization")
+	}
+	return romanizer.Romanize(text, from)
+}
+
+// LookupWord 查询单词的释义、词性、读音和例句，结果会缓存
+// @param {string} word - 待查询的单词或短语
+// @param {string} from - 源语言代码，可为 "auto"
+// @param {string} to - 目标语言代码
+// @returns {DictionaryEntry} 词条
+// @returns {error} 可能的错误，词典中没有该词时返回 ErrWordNotFound
+func (s *TranslationService) LookupWord(word string, from string, to string) (*translator.DictionaryEntry, error) {
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return nil, errors.New("word is empty")
+	}
+	if strings.ContainsAny(word, "\r\n") || utf8.RuneCountInString(word) > dictionaryMaxWordLength {
+		return nil, errors.New("dictionary lookup only supports single words or short phrases")
+	}
+	if from == "" {
+		from = "auto"
+	}
+
+	key := strings.ToLower(word) + "\x00" + from + "\x00" + to
+	return s.dictionaryCache.Get(key, func() (*translator.DictionaryEntry, error) {
+		trans, err := s.getTranslator(translator.GoogleTranslatorType)
+		if err != nil {
+			return nil, err
+		}
+		dictionary, ok := trans.(translator.Dictionary)
+		if !ok {
+			return nil, errors.New("no translator provides dictionary lookup")
+		}
+		return dictionary.Lookup(word, from, to)
+	})
+}
+
+// ResolveTargetLanguage 根据检测到的源语言选择目标语言
+// 按配置中的规则顺序匹配，规则只写主语言（如 zh）时也匹配 zh-CN、zh-TW 等变体；
+// 没有匹配规则时使用默认目标语言
+// @param {string} detected - 检测到的源语言代码
+// @returns {string} 目标语言代码
+func (s *TranslationService) ResolveTargetLanguage(detected string) string {
+	if s.configService == nil {
+		return "en"
+	}
+	config, err := s.configService.GetConfig()
+	if err != nil {
+		s.logger.Warning("Failed to get config for translation rules", "error", err)
+		return "en"
+	}
+	return resolveTargetLanguage(&config.Translation, detected)
+}
+
+// DetectLanguages 识别各段文本的自然语言，用于按块设置拼写检查语言和默认翻译方向
+// @param {[]string} texts - 待识别的文本
+// @returns {[]string} 与 texts 一一对应的 ISO 639-1 代码，无法识别时为空
+func (s *TranslationService) DetectLanguages(texts []string) []string {
+	languages := make([]string, len(texts))
+	for i, text := range texts {
+		languages[i] = langdetect.Detect(text)
+	}
+	return languages
+}
+
+// defaultTranslator 配置中的默认翻译器，未配置时使用必应
+func (s *TranslationService) defaultTranslator() string {
+	if s.configService != nil {
+		if config, err := s.configService.GetConfig(); err == nil && config.Translation.DefaultTranslator != "" {
+			return config.Translation.DefaultTranslator
+		}
+	}
+	return string(translator.BingTranslatorType)
+}
+
+// resolveTargetLanguage 按规则匹配目标语言
+func resolveTargetLanguage(config *models.TranslationConfig, detected string) string {
+	detected = strings.ToLower(strings.ReplaceAll(detected, "_", "-"))
+	base, _, _ := strings.Cut(detected, "-")
+
+	for _, rule := range config.TargetRules {
+		source := strings.ToLower(strings.ReplaceAll(rule.Source, "_", "-"))
+		if rule.Target == "" || source == "" {
+			continue
+		}
+		if source == detected || (!strings.Contains(source, "-") && source == base) {
+			return rule.Target
+		}
+	}
+
+	if config.DefaultTarget != "" {
+		return config.DefaultTarget
+	}
+	return "en"
+}
+
+// GetTranslators 获取所有可用翻译器类型，包括已加载的翻译插件
 // @returns {[]string} 翻译器类型列表
 func (s *TranslationService) GetTranslators() []string {
-	return []string{
-		string(translator.BingTranslatorType),
-		string(translator.GoogleTranslatorType),
-		string(translator.YoudaoTranslatorType),
-		string(translator.DeeplTranslatorType),
-		string(translator.TartuNLPTranslatorType),
+	types := s.factory.Types()
+	result := make([]string, 0, len(types))
+	for _, translatorType := range types {
+		result = append(result, string(translatorType))
 	}
+	return result
 }
 
 // GetTranslatorLanguages 获取翻译器的语言列表
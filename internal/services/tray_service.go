@@ -1,17 +1,35 @@
 package services
 
 import (
+	"fmt"
+	"strings"
+	"sync"
 	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
 	"github.com/wailsapp/wails/v3/pkg/services/log"
 )
 
+const (
+	// maxTraySnippets 托盘菜单中显示的片段数量上限
+	maxTraySnippets = 10
+	// traySnippetLabelLength 片段菜单项名称的最大长度
+	traySnippetLabelLength = 40
+)
+
 // TrayService 系统托盘服务
 type TrayService struct {
 	logger        *log.LogService      // 日志服务实例，用于记录托盘相关日志
 	configService *ConfigService       // 配置服务实例，用于获取托盘配置信息
 	windowHelper  *helper.WindowHelper // 窗口助手实例，用于处理窗口显示/隐藏操作
+
+	snippetMu      sync.Mutex
+	snippetMenu    *application.Menu // 固定片段子菜单，配置变化时重建
+	cancelObserver CancelFunc
+
+	closingWindows sync.Map // 主动关闭中的文档窗口，见 window_buttons.go
+	askingWindows  sync.Map // 正在询问按钮行为的窗口
 }
 
 // NewTrayService 创建新的系统托盘服务实例
@@ -39,26 +57,21 @@ func (ts *TrayService) ShouldMinimizeToTray() bool {
 	return config.General.EnableSystemTray
 }
 
-// HandleWindowClose 处理窗口关闭事件
-// 根据配置决定是将窗口最小化到托盘还是直接退出应用程序
+// HandleWindowClose 处理主窗口关闭事件
+// 按配置的关闭按钮行为隐藏到托盘、最小化、退出应用或询问用户
 func (ts *TrayService) HandleWindowClose() {
-	// 判断是否应该最小化到托盘
-	if ts.ShouldMinimizeToTray() {
-		// 隐藏到托盘
-		ts.windowHelper.HideMainWindow()
-	} else {
-		// 直接退出应用
-		application.Get().Quit()
+	if window := ts.windowHelper.MustGetMainWindow(); window != nil {
+		ts.runWindowButtonAction(window, models.WindowKindMain, models.WindowButtonClose,
+			ts.windowButtonAction(models.WindowKindMain, models.WindowButtonClose))
 	}
 }
 
-// HandleWindowMinimize 处理窗口最小化事件
-// 当窗口需要最小化时，根据配置决定是否隐藏到系统托盘
+// HandleWindowMinimize 处理主窗口最小化事件
+// 按配置的最小化按钮行为执行对应操作
 func (ts *TrayService) HandleWindowMinimize() {
-	// 判断是否应该最小化到托盘
-	if ts.ShouldMinimizeToTray() {
-		// 隐藏到托盘
-		ts.windowHelper.HideMainWindow()
+	if window := ts.windowHelper.MustGetMainWindow(); window != nil {
+		ts.runWindowButtonAction(window, models.WindowKindMain, models.WindowButtonMinimize,
+			ts.windowButtonAction(models.WindowKindMain, models.WindowButtonMinimize))
 	}
 }
 
@@ -71,9 +84,9 @@ func (ts *TrayService) ShowWindow() {
 
 // MinimizeButtonClicked 处理标题栏最小化按钮点击事件
 //
-// 该函数通过调用windowHelper的MinimiseMainWindow方法来实现主窗口的最小化操作。
+// 按配置的最小化按钮行为处理主窗口
 func (ts *TrayService) MinimizeButtonClicked() {
-	ts.windowHelper.MinimiseMainWindow()
+	ts.HandleWindowMinimize()
 }
 
 // AutoShowHide 自动显示/隐藏主窗口
@@ -81,3 +94,93 @@ func (ts *TrayService) MinimizeButtonClicked() {
 func (ts *TrayService) AutoShowHide() {
 	ts.windowHelper.AutoShowMainWindow()
 }
+
+// GetTraySnippets 获取托盘菜单中显示的固定片段
+func (ts *TrayService) GetTraySnippets() []models.TraySnippet {
+	config, err := ts.configService.GetConfig()
+	if err != nil {
+		return nil
+	}
+	snippets := config.General.TraySnippets
+	if len(snippets) > maxTraySnippets {
+		snippets = snippets[:maxTraySnippets]
+	}
+	return snippets
+}
+
+// CopyTraySnippet 将指定位置的固定片段复制到剪贴板
+func (ts *TrayService) CopyTraySnippet(index int) error {
+	snippets := ts.GetTraySnippets()
+	if index < 0 || index >= len(snippets) {
+		return fmt.Errorf("snippet not found: %d", index)
+	}
+	if !application.Get().Clipboard.SetText(snippets[index].Content) {
+		return fmt.Errorf("failed to copy snippet to clipboard")
+	}
+	return nil
+}
+
+// AttachSnippetMenu 关联托盘中的固定片段子菜单，并在片段配置变化时重建
+func (ts *TrayService) AttachSnippetMenu(menu *application.Menu) {
+	ts.snippetMu.Lock()
+	ts.snippetMenu = menu
+	if ts.cancelObserver == nil {
+		ts.cancelObserver = ts.configService.Watch("general.traySnippets", func(oldValue, newValue interface{}) {
+			ts.rebuildSnippetMenu()
+		})
+	}
+	ts.snippetMu.Unlock()
+
+	ts.rebuildSnippetMenu()
+}
+
+// rebuildSnippetMenu 按当前配置重建固定片段子菜单
+func (ts *TrayService) rebuildSnippetMenu() {
+	ts.snippetMu.Lock()
+	defer ts.snippetMu.Unlock()
+	if ts.snippetMenu == nil {
+		return
+	}
+
+	ts.snippetMenu.Clear()
+	snippets := ts.GetTraySnippets()
+	if len(snippets) == 0 {
+		ts.snippetMenu.Add("No pinned snippets").SetEnabled(false)
+	}
+	for i, snippet := range snippets {
+		index := i
+		ts.snippetMenu.Add(traySnippetLabel(snippet)).OnClick(func(data *application.Context) {
+			if err := ts.CopyTraySnippet(index); err != nil {
+				ts.logger.Error("Failed to copy tray snippet", "error", err)
+			}
+		})
+	}
+	ts.snippetMenu.Update()
+}
+
+// traySnippetLabel 片段菜单项名称，未设置标题时取内容首个非空行
+func traySnippetLabel(snippet models.TraySnippet) string {
+	label := strings.TrimSpace(snippet.Title)
+	if label == "" {
+		for _, line := range strings.Split(snippet.Content, "\n") {
+			if label = strings.TrimSpace(line); label != "" {
+				break
+			}
+		}
+	}
+	if label == "" {
+		label = "(empty)"
+	}
+	if runes := []rune(label); len(runes) > traySnippetLabelLength {
+		label = string(runes[:traySnippetLabelLength-1]) + "…"
+	}
+	return label
+}
+
+// ServiceShutdown 取消配置监听
+func (ts *TrayService) ServiceShutdown() error {
+	if ts.cancelObserver != nil {
+		ts.cancelObserver()
+	}
+	return nil
+}
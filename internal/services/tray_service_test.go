@@ -0,0 +1,97 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"voidraft/internal/models"
+)
+
+func TestTraySnippetLabel(t *testing.T) {
+	tests := []struct {
+		snippet models.TraySnippet
+		want    string
+	}{
+		{models.TraySnippet{Title: "  Signature ", Content: "Best regards"}, "Signature"},
+		{models.TraySnippet{Content: "\n  \n  first line\nsecond line"}, "first line"},
+		{models.TraySnippet{Content: " \n "}, "(empty)"},
+		{models.TraySnippet{Title: strings.Repeat("长", 50)}, strings.Repeat("长", traySnippetLabelLength-1) + "…"},
+		{models.TraySnippet{Title: strings.Repeat("a", traySnippetLabelLength)}, strings.Repeat("a", traySnippetLabelLength)},
+	}
+	for _, tt := range tests {
+		if got := traySnippetLabel(tt.snippet); got != tt.want {
+			t.Errorf("traySnippetLabel(%+v) = %q, want %q", tt.snippet, got, tt.want)
+		}
+	}
+}
+
+// TestGetTraySnippets 托盘菜单只显示前 maxTraySnippets 个片段
+func TestGetTraySnippets(t *testing.T) {
+	config := models.NewDefaultAppConfig()
+	for i := 0; i < maxTraySnippets+3; i++ {
+		config.General.TraySnippets = append(config.General.TraySnippets, models.TraySnippet{Content: fmt.Sprintf("snippet %d", i)})
+	}
+	ts := NewTrayService(nil, newTestConfigService(t, config))
+
+	snippets := ts.GetTraySnippets()
+	if len(snippets) != maxTraySnippets {
+		t.Fatalf("GetTraySnippets() returned %d snippets, want %d", len(snippets), maxTraySnippets)
+	}
+	if snippets[0].Content != "snippet 0" || snippets[maxTraySnippets-1].Content != fmt.Sprintf("snippet %d", maxTraySnippets-1) {
+		t.Errorf("GetTraySnippets() = %+v, want the first snippets in order", snippets)
+	}
+
+	// 超出显示范围的片段不能被复制
+	for _, index := range []int{-1, maxTraySnippets} {
+		if err := ts.CopyTraySnippet(index); err == nil {
+			t.Errorf("CopyTraySnippet(%d) should fail", index)
+		}
+	}
+}
+
+func TestWindowButtonAction(t *testing.T) {
+	config := models.NewDefaultAppConfig()
+	config.General.EnableSystemTray = true
+	config.General.WindowButtons = models.WindowButtonsConfig{
+		Main:     models.WindowButtonBehavior{Close: models.WindowActionAsk, Minimize: models.WindowActionTray},
+		Document: models.WindowButtonBehavior{Close: models.WindowActionTray, Minimize: "bogus"},
+	}
+	configService := newTestConfigService(t, config)
+	ts := NewTrayService(nil, configService)
+
+	tests := []struct {
+		kind   models.WindowKind
+		button models.WindowButton
+		want   models.WindowButtonAction
+	}{
+		{models.WindowKindMain, models.WindowButtonClose, models.WindowActionAsk},
+		{models.WindowKindMain, models.WindowButtonMinimize, models.WindowActionTray},
+		{models.WindowKindDocument, models.WindowButtonClose, models.WindowActionTray},
+		// 无效的配置使用默认值
+		{models.WindowKindDocument, models.WindowButtonMinimize, models.WindowActionMinimize},
+	}
+	for _, tt := range tests {
+		if got := ts.windowButtonAction(tt.kind, tt.button); got != tt.want {
+			t.Errorf("windowButtonAction(%s, %s) = %s, want %s", tt.kind, tt.button, got, tt.want)
+		}
+	}
+
+	// 未启用托盘时隐藏到托盘退化为最小化或关闭
+	configService.koanf.Set("general.enableSystemTray", false)
+	if got := ts.windowButtonAction(models.WindowKindMain, models.WindowButtonMinimize); got != models.WindowActionMinimize {
+		t.Errorf("minimize without tray = %s, want minimize", got)
+	}
+	if got := ts.windowButtonAction(models.WindowKindDocument, models.WindowButtonClose); got != models.WindowActionClose {
+		t.Errorf("close without tray = %s, want close", got)
+	}
+	if got := ts.windowButtonAction(models.WindowKindMain, models.WindowButtonClose); got != models.WindowActionAsk {
+		t.Errorf("ask without tray = %s, want ask", got)
+	}
+}
+
+func TestWindowButtonClickedInvalid(t *testing.T) {
+	ts := NewTrayService(nil, newTestConfigService(t, models.NewDefaultAppConfig()))
+	if err := ts.WindowButtonClicked("main", "maximize"); err == nil {
+		t.Error("WindowButtonClicked should reject unknown buttons")
+	}
+}
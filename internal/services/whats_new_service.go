@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"voidraft/internal/common/changelog"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/common/updatesource"
+	"voidraft/internal/models"
+	"voidraft/internal/version"
+
+	"github.com/creativeprojects/go-selfupdate"
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/log"
+	"github.com/wailsapp/wails/v3/pkg/services/notifications"
+)
+
+// WhatsNewService 升级后的更新说明
+// 启动时比较已确认的版本与当前版本，版本升高时获取区间内的发布说明并提示，用户确认后不再重复
+type WhatsNewService struct {
+	logger             *log.LogService
+	configService      *ConfigService
+	notificationCenter *NotificationCenterService
+
+	mu      sync.Mutex
+	pending *models.WhatsNew
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewWhatsNewService 创建更新说明服务实例
+func NewWhatsNewService(configService *ConfigService, notificationCenter *NotificationCenterService, logger *log.LogService) *WhatsNewService {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &WhatsNewService{
+		logger:             logger,
+		configService:      configService,
+		notificationCenter: notificationCenter,
+	}
+}
+
+// ServiceStartup 在后台比较版本并准备更新说明
+func (s *WhatsNewService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	prepareCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.prepare(prepareCtx)
+	}()
+	return nil
+}
+
+// ServiceShutdown 取消尚未完成的获取
+func (s *WhatsNewService) ServiceShutdown() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	return nil
+}
+
+// prepare 版本升高时获取更新说明，发送事件和系统通知
+func (s *WhatsNewService) prepare(ctx context.Context) {
+	config, err := s.configService.GetConfig()
+	if err != nil {
+		s.logger.Error("whats new: get config failed", "error", err)
+		return
+	}
+	if !config.Updates.WhatsNew {
+		return
+	}
+
+	previous := config.Updates.AcknowledgedVersion
+	if previous == "" {
+		// 旧版本配置中没有确认记录，以配置写入时的版本为准
+		previous = config.Metadata.Version
+	}
+	current := version.Version
+	if previous == "" || changelog.Compare(current, previous) < 0 {
+		// 全新安装或降级，不提示
+		s.acknowledge(current)
+		return
+	}
+	if changelog.Compare(current, previous) == 0 {
+		return
+	}
+
+	whatsNew := &models.WhatsNew{
+		PreviousVersion: changelog.Normalize(previous),
+		CurrentVersion:  changelog.Normalize(current),
+	}
+	if effectiveUpdateMode(config.Updates) != models.UpdateModeOffline {
+		whatsNew.Entries = s.fetchEntries(ctx, config, previous, current)
+	}
+	if len(whatsNew.Entries) == 0 {
+		whatsNew.Offline = true
+		whatsNew.Entries = []changelog.Entry{{Version: whatsNew.CurrentVersion}}
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.pending = whatsNew
+	s.mu.Unlock()
+
+	helper.EmitEvent(constant.EVENT_WHATS_NEW, whatsNew)
+	s.notificationCenter.notify(models.NotificationCategoryWhatsNew, notifications.NotificationOptions{
+		ID:       "whats_new",
+		Title:    "Voidraft Updated",
+		Subtitle: "What's new",
+		Body:     fmt.Sprintf("Updated from %s to %s. Open Settings > Updates to see what changed.", whatsNew.PreviousVersion, whatsNew.CurrentVersion),
+	})
+}
+
+// fetchEntries 依次从主要源和备用源获取区间内的发布说明
+func (s *WhatsNewService) fetchEntries(ctx context.Context, config *models.AppConfig, previous, current string) []changelog.Entry {
+	timeout := config.Updates.UpdateTimeout
+	if timeout <= 0 {
+		timeout = 30
+	}
+
+	for _, sourceType := range []models.UpdateSourceType{config.Updates.PrimarySource, config.Updates.BackupSource} {
+		fetchCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		entries, err := s.fetchFromSource(fetchCtx, sourceType, config.Updates)
+		cancel()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			s.logger.Error("whats new: fetch release notes failed", "source", sourceType, "error", err)
+			continue
+		}
+		return changelog.Between(entries, previous, current)
+	}
+	return nil
+}
+
+// fetchFromSource 通过匿名发布源列出所有版本的发布说明
+func (s *WhatsNewService) fetchFromSource(ctx context.Context, sourceType models.UpdateSourceType, updates models.UpdatesConfig) ([]changelog.Entry, error) {
+	var source selfupdate.Source
+	var repo selfupdate.RepositorySlug
+	switch sourceType {
+	case models.UpdateSourceGithub:
+		source = updatesource.GitHub("")
+		repo = selfupdate.NewRepositorySlug(updates.Github.Owner, updates.Github.Repo)
+	case models.UpdateSourceGitea:
+		gitea, err := updatesource.Gitea(updates.Gitea.BaseURL)
+		if err != nil {
+			return nil, err
+		}
+		source = gitea
+		repo = selfupdate.NewRepositorySlug(updates.Gitea.Owner, updates.Gitea.Repo)
+	default:
+		return nil, fmt.Errorf("unsupported source: %s", sourceType)
+	}
+
+	releases, err := source.ListReleases(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]changelog.Entry, 0, len(releases))
+	for _, rel := range releases {
+		if rel.GetDraft() {
+			continue
+		}
+		entries = append(entries, changelog.Entry{
+			Version:     changelog.Normalize(rel.GetTagName()),
+			Title:       rel.GetName(),
+			Notes:       rel.GetReleaseNotes(),
+			URL:         rel.GetURL(),
+			PublishedAt: rel.GetPublishedAt(),
+		})
+	}
+	return entries, nil
+}
+
+// GetWhatsNew 获取尚未确认的更新说明，没有时返回 nil
+func (s *WhatsNewService) GetWhatsNew() *models.WhatsNew {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending
+}
+
+// AcknowledgeWhatsNew 确认已查看更新说明，之后同一版本不再提示
+func (s *WhatsNewService) AcknowledgeWhatsNew() error {
+	s.mu.Lock()
+	s.pending = nil
+	s.mu.Unlock()
+	return s.acknowledge(version.Version)
+}
+
+// acknowledge 记录已确认的版本
+func (s *WhatsNewService) acknowledge(v string) error {
+	if err := s.configService.Set("updates.acknowledgedVersion", changelog.Normalize(v)); err != nil {
+		s.logger.Error("whats new: save acknowledged version failed", "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"fmt"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// windowKindOf 根据窗口名称判断窗口类型
+func windowKindOf(window application.Window) models.WindowKind {
+	if window.Name() == constant.VOIDRAFT_MAIN_WINDOW_NAME {
+		return models.WindowKindMain
+	}
+	return models.WindowKindDocument
+}
+
+// windowButtonAction 获取窗口按钮配置的操作，未启用托盘时隐藏到托盘退化为关闭
+func (ts *TrayService) windowButtonAction(kind models.WindowKind, button models.WindowButton) models.WindowButtonAction {
+	config, err := ts.configService.GetConfig()
+	if err != nil {
+		if kind == models.WindowKindMain && button == models.WindowButtonClose {
+			return models.WindowActionTray
+		}
+		return models.WindowActionMinimize
+	}
+
+	action := config.General.WindowButtons.Behavior(kind).Action(button)
+	if !action.IsValid() {
+		action = models.NewDefaultAppConfig().General.WindowButtons.Behavior(kind).Action(button)
+	}
+	if action == models.WindowActionTray && !config.General.EnableSystemTray {
+		if button == models.WindowButtonMinimize {
+			return models.WindowActionMinimize
+		}
+		return models.WindowActionClose
+	}
+	return action
+}
+
+// WindowButtonClicked 处理标题栏按钮点击，按配置执行对应操作
+func (ts *TrayService) WindowButtonClicked(windowName string, button models.WindowButton) error {
+	if !button.IsValid() {
+		return fmt.Errorf("invalid window button: %s", button)
+	}
+	window, ok := application.Get().Window.GetByName(windowName)
+	if !ok {
+		return fmt.Errorf("window not found: %s", windowName)
+	}
+
+	kind := windowKindOf(window)
+	ts.runWindowButtonAction(window, kind, button, ts.windowButtonAction(kind, button))
+	return nil
+}
+
+// handleDocumentWindowClose 处理文档窗口的关闭事件
+// 返回 true 表示继续关闭窗口，false 表示取消关闭并已按配置处理
+func (ts *TrayService) handleDocumentWindowClose(window application.Window) bool {
+	if _, closing := ts.closingWindows.LoadAndDelete(window.Name()); closing {
+		return true
+	}
+	action := ts.windowButtonAction(models.WindowKindDocument, models.WindowButtonClose)
+	if action == models.WindowActionClose {
+		return true
+	}
+	ts.runWindowButtonAction(window, models.WindowKindDocument, models.WindowButtonClose, action)
+	return false
+}
+
+// forceCloseWindow 关闭文档窗口，关闭事件不再按按钮配置处理
+func (ts *TrayService) forceCloseWindow(window application.Window) {
+	ts.closingWindows.Store(window.Name(), struct{}{})
+	window.Close()
+}
+
+// runWindowButtonAction 执行按钮操作
+func (ts *TrayService) runWindowButtonAction(window application.Window, kind models.WindowKind, button models.WindowButton, action models.WindowButtonAction) {
+	switch action {
+	case models.WindowActionTray:
+		window.Hide()
+	case models.WindowActionMinimize:
+		window.Minimise()
+	case models.WindowActionClose:
+		if kind == models.WindowKindMain {
+			application.Get().Quit()
+			return
+		}
+		ts.forceCloseWindow(window)
+	case models.WindowActionQuit:
+		application.Get().Quit()
+	case models.WindowActionAsk:
+		ts.askWindowButtonAction(window, kind, button)
+	}
+}
+
+// askWindowButtonAction 询问用户按钮的行为，记住选择后执行
+func (ts *TrayService) askWindowButtonAction(window application.Window, kind models.WindowKind, button models.WindowButton) {
+	if _, asking := ts.askingWindows.LoadOrStore(window.Name(), struct{}{}); asking {
+		return
+	}
+
+	config, err := ts.configService.GetConfig()
+	trayEnabled := err != nil || config.General.EnableSystemTray
+
+	choices := []struct {
+		label  string
+		action models.WindowButtonAction
+	}{
+		{"Hide to tray", models.WindowActionTray},
+		{"Minimize", models.WindowActionMinimize},
+		{"Close window", models.WindowActionClose},
+		{"Quit voidraft", models.WindowActionQuit},
+	}
+
+	dialog := application.QuestionDialog().
+		SetTitle("voidraft").
+		SetMessage(fmt.Sprintf("What should the %s button do? Your choice will be remembered and can be changed in settings.", button))
+	for _, choice := range choices {
+		if choice.action == models.WindowActionTray && !trayEnabled {
+			continue
+		}
+		// 主窗口关闭即退出应用，不单独提供关闭窗口选项
+		if choice.action == models.WindowActionClose && kind == models.WindowKindMain {
+			continue
+		}
+		action := choice.action
+		dialog.AddButton(choice.label).OnClick(func() {
+			ts.askingWindows.Delete(window.Name())
+			key := fmt.Sprintf("general.windowButtons.%s.%s", kind, button)
+			if err := ts.configService.Set(key, string(action)); err != nil {
+				ts.logger.Error("Failed to save window button action", "key", key, "error", err)
+			}
+			ts.runWindowButtonAction(window, kind, button, action)
+		})
+	}
+	dialog.AddButton("Cancel").SetAsCancel().OnClick(func() {
+		ts.askingWindows.Delete(window.Name())
+	})
+	dialog.AttachToWindow(window).Show()
+}
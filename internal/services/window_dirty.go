@@ -0,0 +1,114 @@
+package services
+
+import (
+	"fmt"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// SetDocumentDirty 文档窗口上报是否有未保存的修改
+// 用户在关闭确认中选择保存后，前端保存完成并上报 dirty=false 时关闭该文档的标签，最后一个标签关闭窗口
+// 未保存状态变化时更新窗口标题前的标记和程序坞、任务栏徽章
+func (ws *WindowService) SetDocumentDirty(documentID int64, dirty bool) {
+	ws.dirtyMu.Lock()
+	changed := ws.dirtyDocuments[documentID] != dirty
+	if dirty {
+		ws.dirtyDocuments[documentID] = true
+		ws.dirtyMu.Unlock()
+		if changed {
+			ws.refreshDocumentWindowTitle(documentID)
+		}
+		return
+	}
+	delete(ws.dirtyDocuments, documentID)
+	closeAfterSave := ws.closeAfterSave[documentID]
+	delete(ws.closeAfterSave, documentID)
+	ws.dirtyMu.Unlock()
+
+	if closeAfterSave {
+		ws.closeDocumentTab(documentID)
+	} else if changed {
+		ws.refreshDocumentWindowTitle(documentID)
+	}
+}
+
+// refreshDocumentWindowTitle 更新文档所在窗口的标题，不重新通知标签列表
+func (ws *WindowService) refreshDocumentWindowTitle(documentID int64) {
+	windowID, ok := ws.documentWindowID(documentID)
+	if !ok {
+		ws.refreshModifiedBadge()
+		return
+	}
+	if tabs := ws.GetDocumentWindowTabs(windowID); tabs != nil {
+		ws.applyDocumentWindowTitle(windowID, tabs.Active)
+	}
+}
+
+// isDocumentDirty 文档窗口是否有未保存的修改
+func (ws *WindowService) isDocumentDirty(documentID int64) bool {
+	ws.dirtyMu.Lock()
+	defer ws.dirtyMu.Unlock()
+	return ws.dirtyDocuments[documentID]
+}
+
+// clearDocumentDirty 清除文档窗口的未保存状态
+func (ws *WindowService) clearDocumentDirty(documentID int64) {
+	ws.dirtyMu.Lock()
+	delete(ws.dirtyDocuments, documentID)
+	delete(ws.closeAfterSave, documentID)
+	ws.dirtyMu.Unlock()
+}
+
+// confirmCloseDirtyDocuments 关闭的标签中有未保存的修改时弹出保存/不保存/取消确认
+// 选择保存时立即关闭没有修改的标签，其余标签在保存完成后关闭；选择不保存时调用 discard
+// 返回 true 表示已取消本次关闭，由用户的选择决定后续操作
+func (ws *WindowService) confirmCloseDirtyDocuments(window application.Window, documentIDs []int64, discard func()) bool {
+	var dirty, clean []int64
+	for _, documentID := range documentIDs {
+		if ws.isDocumentDirty(documentID) {
+			dirty = append(dirty, documentID)
+		} else {
+			clean = append(clean, documentID)
+		}
+	}
+	if len(dirty) == 0 {
+		return false
+	}
+
+	message := fmt.Sprintf("Save changes to %d documents before closing?", len(dirty))
+	if len(dirty) == 1 {
+		title := fmt.Sprintf("document %d", dirty[0])
+		if doc, err := ws.documentService.GetDocumentByID(dirty[0]); err == nil && doc != nil {
+			title = fmt.Sprintf("%q", doc.Title)
+		}
+		message = fmt.Sprintf("Save changes to %s before closing?", title)
+	}
+
+	dialog := application.QuestionDialog().
+		SetTitle("voidraft").
+		SetMessage(message)
+	dialog.AddButton("Save").SetAsDefault().OnClick(func() {
+		for _, documentID := range clean {
+			ws.closeDocumentTab(documentID)
+		}
+		ws.dirtyMu.Lock()
+		for _, documentID := range dirty {
+			ws.closeAfterSave[documentID] = true
+		}
+		ws.dirtyMu.Unlock()
+		for _, documentID := range dirty {
+			helper.EmitEvent(constant.EVENT_DOCUMENT_SAVE_REQUESTED, documentID)
+		}
+	})
+	dialog.AddButton("Don't Save").OnClick(func() {
+		for _, documentID := range dirty {
+			ws.clearDocumentDirty(documentID)
+		}
+		discard()
+	})
+	dialog.AddButton("Cancel").SetAsCancel()
+	dialog.AttachToWindow(window).Show()
+	return true
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+	"voidraft/internal/models"
+)
+
+// TestSetDocumentDirty 上报保存完成后清除未保存状态和待关闭标记
+func TestSetDocumentDirty(t *testing.T) {
+	ws := &WindowService{
+		windows:        newDocumentWindowRegistry(),
+		dirtyDocuments: make(map[int64]bool),
+		closeAfterSave: make(map[int64]bool),
+		followModes:    make(map[int64]models.FollowMode),
+	}
+
+	ws.SetDocumentDirty(5, true)
+	if !ws.isDocumentDirty(5) {
+		t.Fatal("document should be dirty")
+	}
+	ws.closeAfterSave[5] = true
+	ws.SetDocumentDirty(5, false)
+	if ws.isDocumentDirty(5) || ws.closeAfterSave[5] {
+		t.Errorf("after save: dirty=%t closeAfterSave=%t", ws.isDocumentDirty(5), ws.closeAfterSave[5])
+	}
+
+	ws.SetDocumentDirty(6, true)
+	ws.closeAfterSave[6] = true
+	ws.clearDocumentDirty(6)
+	if ws.isDocumentDirty(6) || ws.closeAfterSave[6] {
+		t.Errorf("after clear: dirty=%t closeAfterSave=%t", ws.isDocumentDirty(6), ws.closeAfterSave[6])
+	}
+}
+
+// TestConfirmCloseCleanDocuments 没有未保存的修改时不询问，直接关闭
+func TestConfirmCloseCleanDocuments(t *testing.T) {
+	ws := &WindowService{
+		dirtyDocuments: map[int64]bool{9: true},
+		closeAfterSave: make(map[int64]bool),
+	}
+	discarded := false
+	if ws.confirmCloseDirtyDocuments(nil, []int64{1, 2}, func() { discarded = true }) {
+		t.Error("closing clean documents should not be cancelled")
+	}
+	if discarded {
+		t.Error("discard should not run when nothing is dirty")
+	}
+}
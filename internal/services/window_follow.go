@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
+)
+
+// ErrFollowReadOnly 只读跟随的视图不能发出编辑
+var ErrFollowReadOnly = errors.New("this view is following the document read-only")
+
+// ReportMainDocument 主窗口切换文档时上报当前文档，用于检测同一文档是否在文档窗口中重复打开
+// 分屏时上报的是获得焦点的编辑区中的文档
+func (ws *WindowService) ReportMainDocument(documentID int64) {
+	ws.followMu.Lock()
+	previous := ws.mainDocumentID
+	ws.mainDocumentID = documentID
+	ws.followMu.Unlock()
+
+	previousPane := ws.setActivePaneDocument(documentID)
+	if previous != previousPane && previous != documentID && previous != 0 {
+		ws.refreshFollow(previous, ws.IsDocumentWindowOpen(previous))
+	}
+	ws.refreshPaneFollow(previousPane, documentID)
+	ws.touchRecentDocument(documentID)
+	ws.applyMainWindowLayout()
+}
+
+// GetFollowState 获取文档的跟随状态
+func (ws *WindowService) GetFollowState(documentID int64) *models.FollowState {
+	ws.followMu.Lock()
+	defer ws.followMu.Unlock()
+	return ws.followStateLocked(documentID)
+}
+
+// SetFollowMode 切换重复打开文档的跟随模式：文档窗口只读跟随主窗口，或接管编辑使主窗口只读
+func (ws *WindowService) SetFollowMode(documentID int64, mode models.FollowMode) (*models.FollowState, error) {
+	if mode != models.FollowModeMirror && mode != models.FollowModeTakeover {
+		return nil, fmt.Errorf("unsupported follow mode: %s", mode)
+	}
+
+	ws.followMu.Lock()
+	if _, ok := ws.followModes[documentID]; !ok {
+		ws.followMu.Unlock()
+		return nil, fmt.Errorf("document %d is not open in both windows", documentID)
+	}
+	ws.followModes[documentID] = mode
+	state := ws.followStateLocked(documentID)
+	ws.followMu.Unlock()
+
+	helper.EmitEvent(constant.EVENT_DOCUMENT_FOLLOW_CHANGED, state)
+	return state, nil
+}
+
+// PublishDocumentChanges 可编辑视图发出编辑，转发给只读跟随的视图
+func (ws *WindowService) PublishDocumentChanges(documentID int64, source models.FollowView, changes string) error {
+	ws.followMu.Lock()
+	state := ws.followStateLocked(documentID)
+	ws.followMu.Unlock()
+
+	if !state.Duplicated {
+		return nil
+	}
+	if state.Editor != source {
+		return ErrFollowReadOnly
+	}
+	helper.EmitEvent(constant.EVENT_DOCUMENT_FOLLOW_CHANGES, &models.FollowChanges{
+		DocumentID: documentID,
+		Source:     source,
+		Changes:    changes,
+	})
+	return nil
+}
+
+// refreshFollow 文档窗口打开或关闭、主窗口任一编辑区切换文档后重新计算跟随状态，有变化时通知前端
+// 刚开始重复打开时默认由主窗口编辑，文档窗口只读跟随，避免两处同时保存互相覆盖
+func (ws *WindowService) refreshFollow(documentID int64, windowOpen bool) {
+	inPane := ws.isPaneDocument(documentID)
+	ws.followMu.Lock()
+	_, wasFollowing := ws.followModes[documentID]
+	duplicated := windowOpen && (ws.mainDocumentID == documentID || inPane)
+	switch {
+	case duplicated && !wasFollowing:
+		ws.followModes[documentID] = models.FollowModeMirror
+	case !duplicated && wasFollowing:
+		delete(ws.followModes, documentID)
+	default:
+		ws.followMu.Unlock()
+		return
+	}
+	state := ws.followStateLocked(documentID)
+	ws.followMu.Unlock()
+
+	helper.EmitEvent(constant.EVENT_DOCUMENT_FOLLOW_CHANGED, state)
+}
+
+// followStateLocked 生成跟随状态，调用方需持有 followMu
+func (ws *WindowService) followStateLocked(documentID int64) *models.FollowState {
+	mode, ok := ws.followModes[documentID]
+	return &models.FollowState{
+		DocumentID: documentID,
+		Duplicated: ok,
+		Mode:       mode,
+		Editor:     followEditor(mode),
+	}
+}
+
+// followEditor 跟随模式下可编辑的视图
+func followEditor(mode models.FollowMode) models.FollowView {
+	switch mode {
+	case models.FollowModeMirror:
+		return models.FollowViewMain
+	case models.FollowModeTakeover:
+		return models.FollowViewWindow
+	}
+	return ""
+}
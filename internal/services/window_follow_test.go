@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+
+	"voidraft/internal/models"
+)
+
+func newFollowTestService() *WindowService {
+	return &WindowService{followModes: make(map[int64]models.FollowMode)}
+}
+
+func TestRefreshFollow(t *testing.T) {
+	ws := newFollowTestService()
+	ws.mainDocumentID = 7
+
+	// 文档窗口打开了主窗口正在编辑的文档，默认由主窗口编辑
+	ws.refreshFollow(7, true)
+	state := ws.GetFollowState(7)
+	if !state.Duplicated || state.Mode != models.FollowModeMirror || state.Editor != models.FollowViewMain {
+		t.Fatalf("state after open = %+v", state)
+	}
+
+	// 切换为文档窗口接管编辑
+	if _, err := ws.SetFollowMode(7, models.FollowModeTakeover); err != nil {
+		t.Fatal(err)
+	}
+	if err := ws.PublishDocumentChanges(7, models.FollowViewMain, "[]"); err != ErrFollowReadOnly {
+		t.Fatalf("publish from read-only view = %v", err)
+	}
+	if err := ws.PublishDocumentChanges(7, models.FollowViewWindow, "[]"); err != nil {
+		t.Fatalf("publish from editor view = %v", err)
+	}
+
+	// 重新检测不会重置已选择的模式
+	ws.refreshFollow(7, true)
+	if got := ws.GetFollowState(7).Mode; got != models.FollowModeTakeover {
+		t.Fatalf("mode after refresh = %s", got)
+	}
+
+	// 文档窗口关闭后结束跟随
+	ws.refreshFollow(7, false)
+	if state := ws.GetFollowState(7); state.Duplicated || state.Editor != "" {
+		t.Fatalf("state after close = %+v", state)
+	}
+}
+
+func TestSetFollowModeRequiresDuplicate(t *testing.T) {
+	ws := newFollowTestService()
+	if _, err := ws.SetFollowMode(3, models.FollowModeMirror); err == nil {
+		t.Fatal("expected error for a document open in one window")
+	}
+
+	ws.mainDocumentID = 3
+	ws.refreshFollow(3, true)
+	if _, err := ws.SetFollowMode(3, "bogus"); err == nil {
+		t.Fatal("expected error for unknown mode")
+	}
+	if err := ws.PublishDocumentChanges(4, models.FollowViewWindow, "[]"); err != nil {
+		t.Fatalf("publish for a document open once = %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/icons"
+)
+
+// menuBarLabelLength 菜单栏标签显示的标题长度上限
+const menuBarLabelLength = 16
+
+// ErrMenuBarUnsupported 菜单栏文档仅支持 macOS
+var ErrMenuBarUnsupported = errors.New("menu bar notes are only supported on macOS")
+
+// PinDocumentToMenuBar 将文档固定到 macOS 菜单栏，点击状态栏图标时在图标下方弹出该文档
+// 同一时间只能固定一个文档，再次固定会替换之前的文档
+func (ws *WindowService) PinDocumentToMenuBar(documentID int64) error {
+	if runtime.GOOS != "darwin" {
+		return ErrMenuBarUnsupported
+	}
+	if err := ws.showMenuBarDocument(documentID); err != nil {
+		return err
+	}
+	if err := ws.configService.Set("general.menuBarDocumentId", documentID); err != nil {
+		return fmt.Errorf("failed to save menu bar document: %w", err)
+	}
+	return nil
+}
+
+// UnpinMenuBarDocument 取消固定菜单栏文档
+func (ws *WindowService) UnpinMenuBarDocument() error {
+	ws.removeMenuBarDocument()
+	if err := ws.configService.Set("general.menuBarDocumentId", 0); err != nil {
+		return fmt.Errorf("failed to save menu bar document: %w", err)
+	}
+	return nil
+}
+
+// GetMenuBarDocument 获取当前固定到菜单栏的文档ID，未固定时返回 0
+func (ws *WindowService) GetMenuBarDocument() int64 {
+	ws.menuBarMu.Lock()
+	defer ws.menuBarMu.Unlock()
+	return ws.menuBarDocumentID
+}
+
+// restoreMenuBarDocument 启动时恢复上次固定的菜单栏文档，文档已不存在时清除配置
+func (ws *WindowService) restoreMenuBarDocument() {
+	if runtime.GOOS != "darwin" || ws.configService == nil {
+		return
+	}
+	config, err := ws.configService.GetConfig()
+	if err != nil || config.General.MenuBarDocumentID == 0 {
+		return
+	}
+	if err := ws.showMenuBarDocument(config.General.MenuBarDocumentID); err != nil {
+		ws.logger.Warning("Failed to restore menu bar document", "documentID", config.General.MenuBarDocumentID, "error", err)
+		_ = ws.configService.Set("general.menuBarDocumentId", 0)
+	}
+}
+
+// showMenuBarDocument 创建状态栏图标和依附于它的菜单栏模式窗口
+func (ws *WindowService) showMenuBarDocument(documentID int64) error {
+	doc, err := ws.documentService.GetDocumentByID(documentID)
+	if err != nil {
+		return fmt.Errorf("failed to get document: %w", err)
+	}
+	if doc == nil {
+		return fmt.Errorf("document not found: %d", documentID)
+	}
+
+	ws.removeMenuBarDocument()
+
+	app := application.Get()
+	window := app.Window.NewWithOptions(application.WebviewWindowOptions{
+		Name:                       constant.VOIDRAFT_MENUBAR_WINDOW_NAME,
+		Title:                      fmt.Sprintf("voidraft - %s", doc.Title),
+		Width:                      constant.VOIDRAFT_MENUBAR_WINDOW_WIDTH,
+		Height:                     constant.VOIDRAFT_MENUBAR_WINDOW_HEIGHT,
+		Hidden:                     true,
+		Frameless:                  true,
+		AlwaysOnTop:                true,
+		DisableResize:              true,
+		DevToolsEnabled:            false,
+		DefaultContextMenuDisabled: false,
+		Mac: application.MacWindow{
+			Backdrop: application.MacBackdropTranslucent,
+		},
+		BackgroundColour: application.NewRGB(27, 38, 54),
+		URL:              fmt.Sprintf("/?documentId=%d&mode=%s", documentID, models.WindowModeMenuBar),
+	})
+
+	// 依附窗口后由托盘负责在图标下方定位，点击图标切换显示，失去焦点时自动隐藏
+	tray := app.SystemTray.New()
+	tray.SetTemplateIcon(icons.SystrayMacTemplate)
+	tray.SetLabel(menuBarLabel(doc.Title))
+	tray.SetTooltip(doc.Title)
+	tray.AttachWindow(window).WindowDebounce(200 * time.Millisecond)
+
+	ws.menuBarMu.Lock()
+	ws.menuBarDocumentID = documentID
+	ws.menuBarTray = tray
+	ws.menuBarWindow = window
+	ws.menuBarMu.Unlock()
+	return nil
+}
+
+// removeMenuBarDocument 移除状态栏图标并关闭菜单栏窗口
+func (ws *WindowService) removeMenuBarDocument() {
+	ws.menuBarMu.Lock()
+	tray, window := ws.menuBarTray, ws.menuBarWindow
+	ws.menuBarDocumentID, ws.menuBarTray, ws.menuBarWindow = 0, nil, nil
+	ws.menuBarMu.Unlock()
+
+	if tray != nil {
+		tray.Destroy()
+	}
+	if window != nil {
+		window.Close()
+	}
+}
+
+// menuBarLabel 菜单栏中显示的文档标题
+func menuBarLabel(title string) string {
+	runes := []rune(title)
+	if len(runes) > menuBarLabelLength {
+		return string(runes[:menuBarLabelLength-1]) + "…"
+	}
+	return title
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestMenuBarLabel(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Todo", "Todo"},
+		{strings.Repeat("a", menuBarLabelLength), strings.Repeat("a", menuBarLabelLength)},
+		{strings.Repeat("a", menuBarLabelLength+1), strings.Repeat("a", menuBarLabelLength-1) + "…"},
+		{strings.Repeat("笔", 20), strings.Repeat("笔", menuBarLabelLength-1) + "…"},
+	}
+	for _, tt := range tests {
+		if got := menuBarLabel(tt.title); got != tt.want {
+			t.Errorf("menuBarLabel(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+// TestMenuBarDocumentUnsupported 非 macOS 平台拒绝固定，不改动状态
+func TestMenuBarDocumentUnsupported(t *testing.T) {
+	if runtime.GOOS == "darwin" {
+		t.Skip("menu bar notes are supported on macOS")
+	}
+	ws := &WindowService{}
+	if err := ws.PinDocumentToMenuBar(1); !errors.Is(err, ErrMenuBarUnsupported) {
+		t.Errorf("PinDocumentToMenuBar error = %v, want ErrMenuBarUnsupported", err)
+	}
+	if got := ws.GetMenuBarDocument(); got != 0 {
+		t.Errorf("GetMenuBarDocument() = %d, want 0", got)
+	}
+	// 未固定时移除和恢复都不应出错
+	ws.removeMenuBarDocument()
+	ws.restoreMenuBarDocument()
+}
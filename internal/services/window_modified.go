@@ -0,0 +1,58 @@
+package services
+
+import "sort"
+
+// modifiedTitlePrefix 有未保存修改的窗口标题前缀，任务栏按钮和窗口菜单中同样可见
+const modifiedTitlePrefix = "● "
+
+// IsWindowModified 窗口中是否有未保存的修改，windowID 为 0 表示主窗口
+func (ws *WindowService) IsWindowModified(windowID int64) bool {
+	if windowID == 0 {
+		return len(ws.dirtyPaneDocuments()) > 0
+	}
+	for _, documentID := range ws.documentWindowDocuments(windowID) {
+		if ws.isDocumentDirty(documentID) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetModifiedWindows 有未保存修改的窗口，0 表示主窗口，按 ID 升序
+func (ws *WindowService) GetModifiedWindows() []int64 {
+	ids := []int64{}
+	if ws.IsWindowModified(0) {
+		ids = append(ids, 0)
+	}
+
+	seen := make(map[int64]bool)
+	ws.dirtyMu.Lock()
+	dirty := make([]int64, 0, len(ws.dirtyDocuments))
+	for documentID := range ws.dirtyDocuments {
+		dirty = append(dirty, documentID)
+	}
+	ws.dirtyMu.Unlock()
+	for _, documentID := range dirty {
+		if windowID, ok := ws.documentWindowID(documentID); ok && !seen[windowID] {
+			seen[windowID] = true
+			ids = append(ids, windowID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// refreshModifiedBadge 任一窗口有未保存的修改时显示程序坞和任务栏徽章
+func (ws *WindowService) refreshModifiedBadge() {
+	if err := ws.badge.set(badgeSourceUnsaved, len(ws.GetModifiedWindows()) > 0); err != nil {
+		ws.logger.Warning("Failed to update unsaved badge", "error", err)
+	}
+}
+
+// modifiedTitle 有未保存的修改时在窗口标题前加上标记
+func modifiedTitle(title string, modified bool) string {
+	if modified {
+		return modifiedTitlePrefix + title
+	}
+	return title
+}
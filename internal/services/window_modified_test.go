@@ -0,0 +1,39 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"voidraft/internal/models"
+)
+
+func TestGetModifiedWindows(t *testing.T) {
+	ws := &WindowService{
+		windows:        newDocumentWindowRegistry(),
+		dirtyDocuments: make(map[int64]bool),
+		mainPanes:      []models.MainPane{{Side: models.PaneSideLeft, DocumentID: 1}},
+	}
+	first := ws.windows.open(2)
+	if err := ws.windows.addTab(first, 3); err != nil {
+		t.Fatal(err)
+	}
+	second := ws.windows.open(4)
+
+	if got := ws.GetModifiedWindows(); len(got) != 0 {
+		t.Fatalf("modified windows without changes = %v", got)
+	}
+
+	// 非当前标签的修改同样标记所在窗口
+	ws.dirtyDocuments[3] = true
+	ws.mainPanes[0].Dirty = true
+	if got, want := ws.GetModifiedWindows(), []int64{0, first}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("modified windows = %v, want %v", got, want)
+	}
+	if ws.IsWindowModified(second) {
+		t.Fatal("window without changes reported as modified")
+	}
+
+	if got := modifiedTitle("voidraft - notes", true); got != "● voidraft - notes" {
+		t.Fatalf("modifiedTitle() = %q", got)
+	}
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
+)
+
+// GetMainWindowLayout 获取主窗口的分屏布局
+func (ws *WindowService) GetMainWindowLayout() *models.MainWindowLayout {
+	ws.paneMu.Lock()
+	panes := append([]models.MainPane(nil), ws.mainPanes...)
+	active := ws.activePane
+	ws.paneMu.Unlock()
+
+	for i := range panes {
+		panes[i].Title = ws.paneTitle(panes[i].DocumentID)
+	}
+	return &models.MainWindowLayout{
+		Split:  len(panes) > 1,
+		Active: active,
+		Panes:  panes,
+		Title:  composeMainWindowTitle(panes),
+	}
+}
+
+// SetMainPaneDocument 在主窗口的编辑区中打开文档
+// 在右侧打开文档即开始分屏，右侧传入 0 结束分屏
+func (ws *WindowService) SetMainPaneDocument(side models.PaneSide, documentID int64) (*models.MainWindowLayout, error) {
+	if side != models.PaneSideLeft && side != models.PaneSideRight {
+		return nil, fmt.Errorf("unsupported pane: %s", side)
+	}
+
+	ws.paneMu.Lock()
+	previous := ws.setPaneDocumentLocked(side, documentID)
+	ws.paneMu.Unlock()
+
+	ws.refreshPaneFollow(previous, documentID)
+	return ws.applyMainWindowLayout(), nil
+}
+
+// CloseSplitPane 关闭右侧编辑区，回到单文档布局
+func (ws *WindowService) CloseSplitPane() (*models.MainWindowLayout, error) {
+	return ws.SetMainPaneDocument(models.PaneSideRight, 0)
+}
+
+// SetActivePane 切换获得焦点的编辑区
+func (ws *WindowService) SetActivePane(side models.PaneSide) error {
+	ws.paneMu.Lock()
+	if ws.paneIndexLocked(side) < 0 {
+		ws.paneMu.Unlock()
+		return fmt.Errorf("pane %s is not open", side)
+	}
+	ws.activePane = side
+	ws.paneMu.Unlock()
+
+	ws.applyMainWindowLayout()
+	return nil
+}
+
+// SetMainDocumentDirty 主窗口上报文档是否有未保存的修改，显示该文档的编辑区都会更新
+func (ws *WindowService) SetMainDocumentDirty(documentID int64, dirty bool) {
+	ws.paneMu.Lock()
+	changed := false
+	for i := range ws.mainPanes {
+		if ws.mainPanes[i].DocumentID == documentID && ws.mainPanes[i].Dirty != dirty {
+			ws.mainPanes[i].Dirty = dirty
+			changed = true
+		}
+	}
+	ws.paneMu.Unlock()
+
+	if changed {
+		ws.applyMainWindowLayout()
+	}
+}
+
+// RequestSaveMainPanes 请求前端立即保存主窗口中所有未保存的文档，返回涉及的文档ID
+func (ws *WindowService) RequestSaveMainPanes() []int64 {
+	ids := ws.dirtyPaneDocuments()
+	for _, id := range ids {
+		helper.EmitEvent(constant.EVENT_DOCUMENT_SAVE_REQUESTED, id)
+	}
+	return ids
+}
+
+// setActivePaneDocument 主窗口切换当前文档时更新获得焦点的编辑区，返回原来的文档
+func (ws *WindowService) setActivePaneDocument(documentID int64) int64 {
+	ws.paneMu.Lock()
+	defer ws.paneMu.Unlock()
+	return ws.setPaneDocumentLocked(ws.activePane, documentID)
+}
+
+// setPaneDocumentLocked 更新编辑区的文档并返回原来的文档，调用方需持有 paneMu
+func (ws *WindowService) setPaneDocumentLocked(side models.PaneSide, documentID int64) int64 {
+	if len(ws.mainPanes) == 0 {
+		ws.mainPanes = []models.MainPane{{Side: models.PaneSideLeft}}
+		ws.activePane = models.PaneSideLeft
+	}
+
+	i := ws.paneIndexLocked(side)
+	switch {
+	case side == models.PaneSideRight && documentID == 0:
+		if i < 0 {
+			return 0
+		}
+		previous := ws.mainPanes[i].DocumentID
+		ws.mainPanes = ws.mainPanes[:i]
+		ws.activePane = models.PaneSideLeft
+		return previous
+	case i < 0:
+		ws.mainPanes = append(ws.mainPanes, models.MainPane{Side: side})
+		i = len(ws.mainPanes) - 1
+	}
+
+	previous := ws.mainPanes[i].DocumentID
+	if previous != documentID {
+		ws.mainPanes[i].DocumentID = documentID
+		ws.mainPanes[i].Dirty = false
+	}
+	return previous
+}
+
+// paneIndexLocked 编辑区在 mainPanes 中的位置，未打开时返回 -1，调用方需持有 paneMu
+func (ws *WindowService) paneIndexLocked(side models.PaneSide) int {
+	for i, pane := range ws.mainPanes {
+		if pane.Side == side {
+			return i
+		}
+	}
+	return -1
+}
+
+// isPaneDocument 文档是否在主窗口的某个编辑区中打开
+func (ws *WindowService) isPaneDocument(documentID int64) bool {
+	ws.paneMu.Lock()
+	defer ws.paneMu.Unlock()
+	for _, pane := range ws.mainPanes {
+		if pane.DocumentID == documentID {
+			return true
+		}
+	}
+	return false
+}
+
+// dirtyPaneDocuments 主窗口中有未保存修改的文档，同一文档只返回一次
+func (ws *WindowService) dirtyPaneDocuments() []int64 {
+	ws.paneMu.Lock()
+	defer ws.paneMu.Unlock()
+	var ids []int64
+	seen := make(map[int64]bool)
+	for _, pane := range ws.mainPanes {
+		if pane.Dirty && pane.DocumentID != 0 && !seen[pane.DocumentID] {
+			seen[pane.DocumentID] = true
+			ids = append(ids, pane.DocumentID)
+		}
+	}
+	return ids
+}
+
+// refreshPaneFollow 编辑区更换文档后重新检测与文档窗口的重复打开
+func (ws *WindowService) refreshPaneFollow(previous, documentID int64) {
+	if previous != 0 && previous != documentID {
+		ws.refreshFollow(previous, ws.IsDocumentWindowOpen(previous))
+	}
+	if documentID != 0 {
+		ws.refreshFollow(documentID, ws.IsDocumentWindowOpen(documentID))
+	}
+}
+
+// applyMainWindowLayout 按布局更新主窗口标题并通知前端
+func (ws *WindowService) applyMainWindowLayout() *models.MainWindowLayout {
+	layout := ws.GetMainWindowLayout()
+	if ws.windowHelper != nil {
+		if window, ok := ws.windowHelper.GetMainWindow(); ok {
+			window.SetTitle(modifiedTitle(layout.Title, ws.IsWindowModified(0)))
+		}
+	}
+	ws.refreshModifiedBadge()
+	helper.EmitEvent(constant.EVENT_MAIN_WINDOW_LAYOUT_CHANGED, layout)
+	return layout
+}
+
+// paneTitle 编辑区显示的文档标题
+func (ws *WindowService) paneTitle(documentID int64) string {
+	if documentID == 0 || ws.documentService == nil {
+		return ""
+	}
+	doc, err := ws.documentService.GetDocumentByID(documentID)
+	if err != nil || doc == nil {
+		return fmt.Sprintf("Document %d", documentID)
+	}
+	return doc.Title
+}
+
+// composeMainWindowTitle 组合主窗口标题，如 "A* | B - voidraft"，* 表示未保存
+func composeMainWindowTitle(panes []models.MainPane) string {
+	var names []string
+	for _, pane := range panes {
+		if pane.DocumentID == 0 {
+			continue
+		}
+		name := strings.TrimSpace(pane.Title)
+		if name == "" {
+			name = "Untitled"
+		}
+		if pane.Dirty {
+			name += "*"
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return constant.VOIDRAFT_APP_NAME
+	}
+	return strings.Join(names, " | ") + " - " + constant.VOIDRAFT_APP_NAME
+}
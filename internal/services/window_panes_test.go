@@ -0,0 +1,61 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"voidraft/internal/models"
+)
+
+func TestComposeMainWindowTitle(t *testing.T) {
+	tests := []struct {
+		panes []models.MainPane
+		want  string
+	}{
+		{nil, "voidraft"},
+		{[]models.MainPane{{Side: models.PaneSideLeft}}, "voidraft"},
+		{[]models.MainPane{{Side: models.PaneSideLeft, DocumentID: 1, Title: "Notes"}}, "Notes - voidraft"},
+		{[]models.MainPane{
+			{Side: models.PaneSideLeft, DocumentID: 1, Title: "Notes", Dirty: true},
+			{Side: models.PaneSideRight, DocumentID: 2, Title: " "},
+		}, "Notes* | Untitled - voidraft"},
+	}
+	for _, tt := range tests {
+		if got := composeMainWindowTitle(tt.panes); got != tt.want {
+			t.Errorf("composeMainWindowTitle(%+v) = %q, want %q", tt.panes, got, tt.want)
+		}
+	}
+}
+
+func TestSetPaneDocumentLocked(t *testing.T) {
+	ws := &WindowService{}
+
+	if previous := ws.setPaneDocumentLocked(models.PaneSideLeft, 1); previous != 0 {
+		t.Fatalf("previous left = %d", previous)
+	}
+	ws.setPaneDocumentLocked(models.PaneSideRight, 2)
+	ws.activePane = models.PaneSideRight
+	if len(ws.mainPanes) != 2 || !ws.isPaneDocument(2) {
+		t.Fatalf("panes after split = %+v", ws.mainPanes)
+	}
+
+	// 同一文档在两侧打开时只请求保存一次
+	ws.setPaneDocumentLocked(models.PaneSideRight, 1)
+	ws.SetMainDocumentDirty(1, true)
+	if got := ws.dirtyPaneDocuments(); !reflect.DeepEqual(got, []int64{1}) {
+		t.Fatalf("dirty documents = %v", got)
+	}
+
+	// 更换文档清除未保存标记
+	if previous := ws.setPaneDocumentLocked(models.PaneSideLeft, 3); previous != 1 || ws.mainPanes[0].Dirty {
+		t.Fatalf("left pane after switch = %+v, previous %d", ws.mainPanes[0], previous)
+	}
+
+	// 右侧传入 0 结束分屏，焦点回到左侧
+	if previous := ws.setPaneDocumentLocked(models.PaneSideRight, 0); previous != 1 {
+		t.Fatalf("previous right = %d", previous)
+	}
+	if len(ws.mainPanes) != 1 || ws.activePane != models.PaneSideLeft {
+		t.Fatalf("panes after closing split = %+v, active %s", ws.mainPanes, ws.activePane)
+	}
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+)
+
+// GetRecentDocument 获取最近获得焦点的文档，主窗口为当前编辑区的文档，文档窗口为当前标签
+// 没有记录时返回 0
+func (ws *WindowService) GetRecentDocument() int64 {
+	ws.recentMu.Lock()
+	defer ws.recentMu.Unlock()
+	return ws.recentDocumentID
+}
+
+// touchRecentDocument 记录最近获得焦点的文档
+func (ws *WindowService) touchRecentDocument(documentID int64) {
+	if documentID == 0 {
+		return
+	}
+	ws.recentMu.Lock()
+	ws.recentDocumentID = documentID
+	ws.recentMu.Unlock()
+}
+
+// forgetRecentDocument 文档关闭后不再作为最近的文档
+func (ws *WindowService) forgetRecentDocument(documentID int64) {
+	ws.recentMu.Lock()
+	if ws.recentDocumentID == documentID {
+		ws.recentDocumentID = 0
+	}
+	ws.recentMu.Unlock()
+}
+
+// watchMainWindowFocus 主窗口获得焦点时记录当前编辑区的文档
+func (ws *WindowService) watchMainWindowFocus() {
+	window, ok := ws.windowHelper.GetMainWindow()
+	if !ok {
+		return
+	}
+	window.OnWindowEvent(events.Common.WindowFocus, func(event *application.WindowEvent) {
+		ws.followMu.Lock()
+		documentID := ws.mainDocumentID
+		ws.followMu.Unlock()
+		ws.touchRecentDocument(documentID)
+	})
+}
+
+// watchDocumentWindowFocus 文档窗口获得焦点时记录当前标签的文档
+func (ws *WindowService) watchDocumentWindowFocus(window application.Window, windowID int64) {
+	window.OnWindowEvent(events.Common.WindowFocus, func(event *application.WindowEvent) {
+		if tabs := ws.GetDocumentWindowTabs(windowID); tabs != nil {
+			ws.touchRecentDocument(tabs.Active)
+		}
+	})
+}
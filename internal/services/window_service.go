@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync"
 	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
 	"github.com/wailsapp/wails/v3/pkg/events"
@@ -18,14 +21,50 @@ type WindowService struct {
 	documentService *DocumentService
 	// 吸附服务引用
 	windowSnapService *WindowSnapService
+	configService     *ConfigService
+	trayService       *TrayService
+	badge             *appBadge
+	windowHelper      *helper.WindowHelper
+
+	// 文档窗口与其中以标签打开的文档，见 window_tabs.go
+	tabsMu  sync.Mutex
+	windows *documentWindowRegistry
+
+	// 文档窗口的未保存状态，见 window_dirty.go
+	dirtyMu        sync.Mutex
+	dirtyDocuments map[int64]bool
+	closeAfterSave map[int64]bool
+
+	// 固定到菜单栏的文档，见 window_menubar.go
+	menuBarMu         sync.Mutex
+	menuBarDocumentID int64
+	menuBarTray       *application.SystemTray
+	menuBarWindow     *application.WebviewWindow
+
+	// 主窗口与文档窗口重复打开同一文档时的跟随模式，见 window_follow.go
+	followMu       sync.Mutex
+	mainDocumentID int64
+	followModes    map[int64]models.FollowMode
+
+	// 主窗口分屏中各编辑区的文档与未保存状态，见 window_panes.go
+	paneMu     sync.Mutex
+	mainPanes  []models.MainPane
+	activePane models.PaneSide
+
+	// 最近获得焦点的文档，用于后台粘贴等不显示窗口的操作，见 window_recent.go
+	recentMu         sync.Mutex
+	recentDocumentID int64
 }
 
 // NewWindowService 创建新的窗口服务实例
 // @param logger 日志服务实例，如果为nil则会创建默认日志服务
 // @param documentService 文档服务实例，用于处理文档相关操作
 // @param windowSnapService 窗口快照服务实例，用于窗口状态管理
+// @param configService 配置服务实例，用于保存菜单栏文档等窗口设置
+// @param trayService 托盘服务实例，用于按配置处理文档窗口的关闭按钮
+// @param badge 程序坞和任务栏徽章，有未保存的修改时显示
 // @return *WindowService 返回初始化完成的窗口服务实例
-func NewWindowService(logger *log.LogService, documentService *DocumentService, windowSnapService *WindowSnapService) *WindowService {
+func NewWindowService(logger *log.LogService, documentService *DocumentService, windowSnapService *WindowSnapService, configService *ConfigService, trayService *TrayService, badge *appBadge) *WindowService {
 	// 如果未提供日志服务，则使用默认日志服务
 	if logger == nil {
 		logger = log.New()
@@ -35,6 +74,16 @@ func NewWindowService(logger *log.LogService, documentService *DocumentService,
 		logger:            logger,
 		documentService:   documentService,
 		windowSnapService: windowSnapService,
+		configService:     configService,
+		trayService:       trayService,
+		badge:             badge,
+		windowHelper:      helper.NewWindowHelper(),
+		windows:           newDocumentWindowRegistry(),
+		dirtyDocuments:    make(map[int64]bool),
+		closeAfterSave:    make(map[int64]bool),
+		followModes:       make(map[int64]models.FollowMode),
+		mainPanes:         []models.MainPane{{Side: models.PaneSideLeft}},
+		activePane:        models.PaneSideLeft,
 	}
 }
 
@@ -45,10 +94,20 @@ func NewWindowService(logger *log.LogService, documentService *DocumentService,
 func (ws *WindowService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
 	// 更新主窗口缓存数据
 	ws.windowSnapService.UpdateMainWindowCache()
+	// 记录最近获得焦点的文档
+	ws.watchMainWindowFocus()
+	// 文档重命名时更新窗口标题
+	if ws.documentService != nil {
+		ws.documentService.onDocumentChange(ws.handleDocumentChange)
+	}
+
+	// 恢复固定到菜单栏的文档
+	ws.restoreMenuBarDocument()
 	return nil
 }
 
 // OpenDocumentWindow 为指定文档ID打开新窗口
+// 文档已在某个文档窗口中以标签打开时聚焦该窗口并切换到该标签
 //
 // 参数:
 //
@@ -58,15 +117,11 @@ func (ws *WindowService) ServiceStartup(ctx context.Context, options application
 //
 //	error: 打开窗口过程中发生的错误，如果成功则返回nil
 func (ws *WindowService) OpenDocumentWindow(documentID int64) error {
-	app := application.Get()
-	windowName := strconv.FormatInt(documentID, 10)
-
-	// 检查窗口是否已经存在
-	if existingWindow, exists := app.Window.GetByName(windowName); exists {
-		// 窗口已存在，显示并聚焦
-		existingWindow.Show()
-		existingWindow.Restore()
-		existingWindow.Focus()
+	if windowID, ok := ws.documentWindowID(documentID); ok {
+		if err := ws.SetActiveDocumentTab(windowID, documentID); err != nil {
+			return err
+		}
+		ws.focusDocumentWindow(windowID)
 		return nil
 	}
 
@@ -79,10 +134,16 @@ func (ws *WindowService) OpenDocumentWindow(documentID int64) error {
 		return fmt.Errorf("document not found: %d", documentID)
 	}
 
+	// 窗口以分配的窗口ID命名，后续可在其中以标签打开更多文档
+	ws.tabsMu.Lock()
+	windowID := ws.windows.open(documentID)
+	ws.tabsMu.Unlock()
+
 	// 创建新窗口
+	app := application.Get()
 	newWindow := app.Window.NewWithOptions(application.WebviewWindowOptions{
-		Name:                       windowName,
-		Title:                      fmt.Sprintf("voidraft - %s", doc.Title),
+		Name:                       strconv.FormatInt(windowID, 10),
+		Title:                      documentWindowTitle(doc.Title),
 		Width:                      constant.VOIDRAFT_WINDOW_WIDTH,
 		Height:                     constant.VOIDRAFT_WINDOW_HEIGHT,
 		Hidden:                     false,
@@ -98,19 +159,26 @@ func (ws *WindowService) OpenDocumentWindow(documentID int64) error {
 			Theme: application.SystemDefault,
 		},
 		BackgroundColour: application.NewRGB(27, 38, 54),
-		URL:              fmt.Sprintf("/?documentId=%d", documentID),
+		URL:              fmt.Sprintf("/?documentId=%d&windowId=%d", documentID, windowID),
 	})
 
 	// 注册窗口事件
-	ws.registerWindowEvents(newWindow, documentID)
+	ws.registerWindowEvents(newWindow, windowID)
+	ws.watchDocumentWindowFocus(newWindow, windowID)
+	ws.touchRecentDocument(documentID)
 
 	// 向吸附服务注册新窗口
 	if ws.windowSnapService != nil {
-		ws.windowSnapService.RegisterWindow(documentID, newWindow)
+		ws.windowSnapService.RegisterWindow(windowID, newWindow)
+	}
+
+	// 最后才移动窗口：按设置吸附到主窗口一侧，否则移动到中心
+	if ws.windowSnapService == nil || !ws.windowSnapService.PlaceNewWindow(windowID) {
+		newWindow.Center()
 	}
 
-	// 最后才移动窗口到中心
-	newWindow.Center()
+	// 主窗口正在编辑同一文档时进入跟随模式
+	ws.refreshFollow(documentID, true)
 
 	return nil
 }
@@ -120,20 +188,42 @@ func (ws *WindowService) OpenDocumentWindow(documentID int64) error {
 // 参数:
 //
 //	window: Webview窗口实例，用于注册事件钩子
-//	documentID: 文档标识符，用于标识具体的文档窗口
-func (ws *WindowService) registerWindowEvents(window *application.WebviewWindow, documentID int64) {
+//	windowID: 窗口标识符，窗口中的文档从登记表中查询
+func (ws *WindowService) registerWindowEvents(window *application.WebviewWindow, windowID int64) {
 	// 注册窗口关闭事件处理器，当窗口即将关闭时触发相应的业务逻辑
 	window.RegisterHook(events.Common.WindowClosing, func(event *application.WindowEvent) {
-		ws.onWindowClosing(documentID)
+		// 按配置的关闭按钮行为处理，隐藏或最小化时取消关闭
+		if ws.trayService != nil && !ws.trayService.handleDocumentWindowClose(window) {
+			event.Cancel()
+			return
+		}
+		// 任一标签有未保存的修改时先询问是否保存
+		documents := ws.documentWindowDocuments(windowID)
+		if ws.confirmCloseDirtyDocuments(window, documents, func() { ws.closeDocumentWindow(windowID) }) {
+			event.Cancel()
+			return
+		}
+		ws.onWindowClosing(windowID)
 	})
 }
 
 // onWindowClosing 处理窗口关闭事件
-// documentID: 窗口对应的文档ID
-func (ws *WindowService) onWindowClosing(documentID int64) {
+// windowID: 关闭的窗口ID，窗口中的所有标签随之关闭
+func (ws *WindowService) onWindowClosing(windowID int64) {
+	ws.tabsMu.Lock()
+	documents := ws.windows.removeWindow(windowID)
+	ws.tabsMu.Unlock()
+
+	for _, documentID := range documents {
+		ws.clearDocumentDirty(documentID)
+		ws.refreshFollow(documentID, false)
+		ws.forgetRecentDocument(documentID)
+	}
+	ws.refreshModifiedBadge()
+
 	// 从吸附服务中取消注册
 	if ws.windowSnapService != nil {
-		ws.windowSnapService.UnregisterWindow(documentID)
+		ws.windowSnapService.UnregisterWindow(windowID)
 	}
 }
 
@@ -148,7 +238,7 @@ func (ws *WindowService) GetOpenWindows() []application.Window {
 	return app.Window.GetAll()
 }
 
-// IsDocumentWindowOpen 检查指定文档的窗口是否已打开
+// IsDocumentWindowOpen 检查指定文档是否在某个文档窗口中打开
 // 参数:
 //
 //	documentID - 文档的唯一标识符
@@ -157,26 +247,23 @@ func (ws *WindowService) GetOpenWindows() []application.Window {
 //
 //	bool - 如果文档窗口已打开则返回true，否则返回false
 func (ws *WindowService) IsDocumentWindowOpen(documentID int64) bool {
-	// 获取应用程序实例
-	app := application.Get()
-	// 将文档ID转换为窗口名称
-	windowName := strconv.FormatInt(documentID, 10)
-	// 根据窗口名称查找窗口是否存在
-	_, exists := app.Window.GetByName(windowName)
-	return exists
+	_, open := ws.documentWindowID(documentID)
+	return open
 }
 
 // ServiceShutdown 实现服务关闭接口
 // 该函数负责在服务关闭时进行清理工作，主要包括从吸附服务中取消注册所有打开的窗口
 // 返回值：error类型，表示关闭过程中可能发生的错误，当前实现始终返回nil
 func (ws *WindowService) ServiceShutdown() error {
+	ws.removeMenuBarDocument()
+
 	// 从吸附服务中取消注册所有窗口
 	if ws.windowSnapService != nil {
-		windows := ws.GetOpenWindows()
-		for _, window := range windows {
-			if documentID, err := strconv.ParseInt(window.Name(), 10, 64); err == nil {
-				ws.windowSnapService.UnregisterWindow(documentID)
-			}
+		ws.tabsMu.Lock()
+		windowIDs := ws.windows.windowIDs()
+		ws.tabsMu.Unlock()
+		for _, windowID := range windowIDs {
+			ws.windowSnapService.UnregisterWindow(windowID)
 		}
 	}
 	return nil
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"math"
+	"time"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+const (
+	// snapAnimationDuration 吸附动画时长
+	snapAnimationDuration = 120 * time.Millisecond
+
+	// snapAnimationFrame 动画帧间隔，约 60 帧每秒
+	snapAnimationFrame = 16 * time.Millisecond
+)
+
+// snapAnimation 一个窗口进行中的吸附动画
+// 动画期间目标变化时（例如主窗口继续拖动）从当前位置重新开始，不会叠加多个动画
+type snapAnimation struct {
+	from    models.WindowPosition
+	to      models.WindowPosition
+	current models.WindowPosition
+	start   time.Time
+}
+
+// onWindowSnapAnimateChange 吸附动画开关变化
+func (wss *WindowSnapService) onWindowSnapAnimateChange(oldValue, newValue interface{}) {
+	enabled, _ := newValue.(bool)
+	wss.mu.Lock()
+	wss.animateSnap = enabled
+	wss.mu.Unlock()
+}
+
+// animationEnabled 是否播放吸附动画，系统或应用开启减少动态效果时直接移动
+func (wss *WindowSnapService) animationEnabled() bool {
+	if !wss.animateSnap {
+		return false
+	}
+	if wss.accessibilityService != nil && wss.accessibilityService.GetAccessibilityPreferences().Effective.ReducedMotion {
+		return false
+	}
+	return true
+}
+
+// moveWindowLocked 把窗口移到目标位置，调用方需持有 mu
+// 关闭动画时立即移动；否则在后台按帧插值移动，动画期间窗口的移动事件被忽略
+func (wss *WindowSnapService) moveWindowLocked(windowID int64, window *application.WebviewWindow, from, to models.WindowPosition) {
+	if anim, ok := wss.animations[windowID]; ok {
+		anim.from = anim.current
+		anim.to = to
+		anim.start = time.Now()
+		return
+	}
+
+	// 设置更新标志，防止事件循环
+	wss.isUpdatingPosition[windowID] = true
+
+	if !wss.animationEnabled() || from == to {
+		wss.mu.Unlock()
+		window.SetPosition(to.X, to.Y)
+		wss.mu.Lock()
+
+		// 清除更新标志
+		wss.isUpdatingPosition[windowID] = false
+		return
+	}
+
+	anim := &snapAnimation{from: from, to: to, current: from, start: time.Now()}
+	wss.animations[windowID] = anim
+	go wss.runSnapAnimation(windowID, window, anim)
+}
+
+// runSnapAnimation 逐帧移动窗口直到动画结束，窗口注销或服务清理后停止
+func (wss *WindowSnapService) runSnapAnimation(windowID int64, window *application.WebviewWindow, anim *snapAnimation) {
+	ticker := time.NewTicker(snapAnimationFrame)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		wss.mu.Lock()
+		if wss.animations[windowID] != anim {
+			wss.mu.Unlock()
+			return
+		}
+		pos, finished := snapAnimationStep(anim.from, anim.to, time.Since(anim.start))
+		anim.current = pos
+		wss.mu.Unlock()
+
+		window.SetPosition(pos.X, pos.Y)
+		if !finished {
+			continue
+		}
+
+		wss.mu.Lock()
+		if wss.animations[windowID] == anim && anim.current == anim.to {
+			delete(wss.animations, windowID)
+			wss.isUpdatingPosition[windowID] = false
+			wss.mu.Unlock()
+			return
+		}
+		wss.mu.Unlock()
+	}
+}
+
+// snapAnimationStep 计算动画经过 elapsed 后的位置，使用缓出曲线，先快后慢
+func snapAnimationStep(from, to models.WindowPosition, elapsed time.Duration) (models.WindowPosition, bool) {
+	if elapsed >= snapAnimationDuration {
+		return to, true
+	}
+	t := float64(elapsed) / float64(snapAnimationDuration)
+	if t < 0 {
+		t = 0
+	}
+	eased := 1 - (1-t)*(1-t)*(1-t)
+	return models.WindowPosition{
+		X: from.X + int(math.Round(float64(to.X-from.X)*eased)),
+		Y: from.Y + int(math.Round(float64(to.Y-from.Y)*eased)),
+	}, false
+}
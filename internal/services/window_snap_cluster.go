@@ -0,0 +1,68 @@
+package services
+
+import (
+	"time"
+	"voidraft/internal/models"
+)
+
+// clusterFollowSuppress 整组拖动时忽略主窗口移动事件的时长，事件可能晚于下一次子窗口移动到达
+const clusterFollowSuppress = 100 * time.Millisecond
+
+// onWindowSnapDragModeChange 拖动模式变化
+func (wss *WindowSnapService) onWindowSnapDragModeChange(oldValue, newValue interface{}) {
+	mode := models.WindowSnapDragDetach
+	if value, ok := newValue.(string); ok && models.WindowSnapDragMode(value) == models.WindowSnapDragCluster {
+		mode = models.WindowSnapDragCluster
+	}
+	wss.mu.Lock()
+	wss.dragMode = mode
+	wss.mu.Unlock()
+}
+
+// moveClusterLocked 已吸附的子窗口被拖动时，沿吸附关系逐级反推各吸附目标的位置，整组窗口一起移动
+// 整组吸附在主窗口上时移动主窗口，否则移动最顶层的文档窗口；调用方需持有 mu
+func (wss *WindowSnapService) moveClusterLocked(windowInfo *models.WindowInfo, currentPos models.WindowPosition) {
+	root, rootPos := windowInfo.WindowID, currentPos
+	for info := windowInfo; info != nil && info.IsSnapped; info = wss.managedWindows[root] {
+		rootPos = mainPositionFromChild(rootPos, info.SnapOffset)
+		root = info.SnapTarget
+		if root == mainSnapNode {
+			break
+		}
+	}
+	windowInfo.LastPos = currentPos
+
+	if root == mainSnapNode {
+		if rootPos == wss.lastMainWindowPos {
+			return
+		}
+		mainWindow := wss.windowHelper.MustGetMainWindow()
+		if mainWindow == nil {
+			return
+		}
+
+		wss.lastMainWindowPos = rootPos
+		wss.mainFollowUntil = time.Now().Add(clusterFollowSuppress)
+
+		wss.mu.Unlock()
+		mainWindow.SetPosition(rootPos.X, rootPos.Y)
+		wss.mu.Lock()
+	} else {
+		rootInfo, ok := wss.managedWindows[root]
+		window, exists := wss.windowRefs[root]
+		if !ok || !exists || rootInfo.LastPos == rootPos {
+			return
+		}
+		from := rootInfo.LastPos
+		rootInfo.LastPos = rootPos
+		wss.moveWindowLocked(root, window, from, rootPos)
+	}
+
+	wss.moveFollowersLocked(root, windowInfo.WindowID)
+}
+
+// mainPositionFromChild 由子窗口位置和它相对吸附目标的偏移计算吸附目标的位置
+// 吸附目标本身也吸附在其他窗口上时逐级计算，直到主窗口或最顶层的文档窗口
+func mainPositionFromChild(childPos models.WindowPosition, offset models.SnapPosition) models.WindowPosition {
+	return models.WindowPosition{X: childPos.X - offset.X, Y: childPos.Y - offset.Y}
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+const (
+	// 重新检测系统窗口排列功能的间隔，FancyZones 等可能在运行中启动或退出
+	tilingDetectInterval = 30 * time.Second
+
+	// 移动和尺寸变化间隔小于此值时视为系统排列窗口
+	osArrangeWindow = 250 * time.Millisecond
+
+	// 系统排列窗口后暂停吸附的时长，等待系统动画和后续的位置事件结束
+	osArrangeSuppress = time.Second
+)
+
+// linuxTilingWMs 常见的平铺式窗口管理器，按桌面会话名称匹配
+var linuxTilingWMs = []string{
+	"i3", "sway", "hyprland", "bspwm", "awesome", "dwm", "qtile", "xmonad",
+	"herbstluftwm", "river", "niri", "leftwm", "spectrwm",
+}
+
+// onWindowSnapCompatChange 兼容开关变化时重新读取配置
+func (wss *WindowSnapService) onWindowSnapCompatChange(oldValue, newValue interface{}) {
+	config, err := wss.configService.GetConfig()
+	if err != nil {
+		wss.logger.Error("window snap: reload compat config failed", "error", err)
+		return
+	}
+	wss.tilingMu.Lock()
+	wss.compat = config.General.WindowSnapCompat
+	wss.tilingMu.Unlock()
+}
+
+// GetTilingStatus 重新检测当前系统的窗口排列功能，供设置页面显示
+func (wss *WindowSnapService) GetTilingStatus() []models.TilingStatus {
+	return wss.refreshTiling(true)
+}
+
+// refreshTiling 检测结果过期或 force 为 true 时重新检测，返回附带兼容开关的结果
+func (wss *WindowSnapService) refreshTiling(force bool) []models.TilingStatus {
+	wss.tilingMu.Lock()
+	defer wss.tilingMu.Unlock()
+
+	if force || wss.tilingCheckedAt.IsZero() || time.Since(wss.tilingCheckedAt) > tilingDetectInterval {
+		wss.tiling = detectTilingFeatures()
+		wss.tilingCheckedAt = time.Now()
+	}
+	result := make([]models.TilingStatus, len(wss.tiling))
+	for i, status := range wss.tiling {
+		status.Compat = wss.compat.Enabled(status.Feature)
+		result[i] = status
+	}
+	return result
+}
+
+// tilingMode 当前的兼容模式
+// active 表示检测到开启了兼容的系统排列功能，ownsLayout 表示平铺式窗口管理器接管全部窗口位置，吸附完全停用
+func (wss *WindowSnapService) tilingMode() (active, ownsLayout bool) {
+	for _, status := range wss.refreshTiling(false) {
+		if !status.Detected || !status.Compat {
+			continue
+		}
+		active = true
+		if status.Feature == models.TilingFeatureTilingWM {
+			ownsLayout = true
+		}
+	}
+	return active, ownsLayout
+}
+
+// onMainWindowResized 主窗口尺寸变化，与移动几乎同时发生时视为系统排列，解除子窗口吸附而不是跟随移动
+func (wss *WindowSnapService) onMainWindowResized() {
+	if !wss.snapEnabled {
+		return
+	}
+	active, ownsLayout := wss.tilingMode()
+
+	mainWindow := wss.windowHelper.MustGetMainWindow()
+	if mainWindow == nil {
+		return
+	}
+	w, h := mainWindow.Size()
+
+	wss.mu.Lock()
+	defer wss.mu.Unlock()
+
+	wss.lastMainWindowSize = [2]int{w, h}
+	if !active || ownsLayout {
+		return
+	}
+	now := time.Now()
+	wss.mainResizeTime = now
+	if arrangedByOS(wss.mainMoveTime, wss.mainResizeTime, now) {
+		wss.mainArrangedUntil = now.Add(osArrangeSuppress)
+		wss.releaseSnappedWindowsLocked()
+	}
+}
+
+// onChildWindowResized 子窗口尺寸变化，更新尺寸缓存，与移动几乎同时发生时视为系统排列并解除吸附
+func (wss *WindowSnapService) onChildWindowResized(window *application.WebviewWindow, windowInfo *models.WindowInfo) {
+	active, ownsLayout := wss.tilingMode()
+	w, h := window.Size()
+
+	wss.mu.Lock()
+	defer wss.mu.Unlock()
+
+	if _, ok := wss.managedWindows[windowInfo.WindowID]; !ok {
+		return
+	}
+	wss.windowSizeCache[windowInfo.WindowID] = [2]int{w, h}
+	if !wss.snapEnabled || !active || ownsLayout {
+		return
+	}
+	now := time.Now()
+	windowInfo.ResizeTime = now
+	if arrangedByOS(windowInfo.MoveTime, windowInfo.ResizeTime, now) {
+		windowInfo.ArrangedUntil = now.Add(osArrangeSuppress)
+		wss.unsnapWindowLocked(windowInfo)
+	}
+}
+
+// releaseSnappedWindowsLocked 解除所有窗口的吸附，调用方需持有 mu
+func (wss *WindowSnapService) releaseSnappedWindowsLocked() {
+	for _, windowInfo := range wss.managedWindows {
+		wss.unsnapWindowLocked(windowInfo)
+	}
+}
+
+// arrangedByOS 移动和尺寸变化几乎同时发生时视为系统排列窗口，如贴靠布局、FancyZones 区域或切换台前调度
+// 用户拖动标题栏只移动位置，拖动右侧或下方边框只改变尺寸
+func arrangedByOS(moveTime, resizeTime, now time.Time) bool {
+	if moveTime.IsZero() || resizeTime.IsZero() {
+		return false
+	}
+	gap := moveTime.Sub(resizeTime)
+	if gap < 0 {
+		gap = -gap
+	}
+	latest := moveTime
+	if resizeTime.After(latest) {
+		latest = resizeTime
+	}
+	return gap <= osArrangeWindow && now.Sub(latest) <= osArrangeWindow
+}
+
+// tilingWMFromEnv 根据会话环境变量识别平铺式窗口管理器，未识别时返回空字符串
+func tilingWMFromEnv(getenv func(string) string) string {
+	switch {
+	case getenv("SWAYSOCK") != "":
+		return "sway"
+	case getenv("HYPRLAND_INSTANCE_SIGNATURE") != "":
+		return "hyprland"
+	case getenv("I3SOCK") != "":
+		return "i3"
+	}
+	for _, key := range []string{"XDG_CURRENT_DESKTOP", "XDG_SESSION_DESKTOP", "DESKTOP_SESSION"} {
+		for _, name := range strings.Split(strings.ToLower(getenv(key)), ":") {
+			name = strings.TrimSpace(name)
+			if slices.Contains(linuxTilingWMs, name) {
+				return name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// mainSnapNode 吸附关系图中主窗口的节点，文档窗口 ID 从 1 开始分配
+const mainSnapNode int64 = 0
+
+// snapGraph 窗口之间的吸附关系，边从吸附目标指向吸附在它上面的窗口
+// 每个窗口最多吸附到一个目标，且不会吸附到跟随自己的窗口上，因此关系图总是无环的
+type snapGraph struct {
+	targets   map[int64]int64              // 窗口 -> 吸附目标
+	followers map[int64]map[int64]struct{} // 吸附目标 -> 吸附在它上面的窗口
+}
+
+func newSnapGraph() *snapGraph {
+	return &snapGraph{
+		targets:   make(map[int64]int64),
+		followers: make(map[int64]map[int64]struct{}),
+	}
+}
+
+// link 把窗口吸附到目标上，原有的吸附关系被替换
+func (g *snapGraph) link(windowID, target int64) {
+	g.unlink(windowID)
+	g.targets[windowID] = target
+	if g.followers[target] == nil {
+		g.followers[target] = make(map[int64]struct{})
+	}
+	g.followers[target][windowID] = struct{}{}
+}
+
+// unlink 解除窗口自身的吸附，吸附在它上面的窗口保持不变
+func (g *snapGraph) unlink(windowID int64) {
+	target, ok := g.targets[windowID]
+	if !ok {
+		return
+	}
+	delete(g.targets, windowID)
+	delete(g.followers[target], windowID)
+	if len(g.followers[target]) == 0 {
+		delete(g.followers, target)
+	}
+}
+
+// remove 从关系图中移除窗口，返回原本直接吸附在它上面的窗口
+func (g *snapGraph) remove(windowID int64) []int64 {
+	g.unlink(windowID)
+	detached := g.directFollowers(windowID)
+	for _, id := range detached {
+		g.unlink(id)
+	}
+	return detached
+}
+
+// target 窗口的吸附目标
+func (g *snapGraph) target(windowID int64) (int64, bool) {
+	target, ok := g.targets[windowID]
+	return target, ok
+}
+
+// directFollowers 直接吸附在目标上的窗口，按 ID 升序
+func (g *snapGraph) directFollowers(target int64) []int64 {
+	ids := make([]int64, 0, len(g.followers[target]))
+	for id := range g.followers[target] {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// descendants 直接或间接跟随 root 的窗口，按层级从近到远排列，目标总在跟随它的窗口之前
+func (g *snapGraph) descendants(root int64) []int64 {
+	var out []int64
+	queue := []int64{root}
+	for len(queue) > 0 {
+		next := g.directFollowers(queue[0])
+		queue = append(queue[1:], next...)
+		out = append(out, next...)
+	}
+	return out
+}
+
+// follows 窗口是否直接或间接吸附在 ancestor 上
+func (g *snapGraph) follows(windowID, ancestor int64) bool {
+	for {
+		target, ok := g.targets[windowID]
+		if !ok {
+			return false
+		}
+		if target == ancestor {
+			return true
+		}
+		windowID = target
+	}
+}
+
+// root 沿吸附关系向上找到的最顶层窗口，未吸附的窗口返回自身
+func (g *snapGraph) root(windowID int64) int64 {
+	for {
+		target, ok := g.targets[windowID]
+		if !ok {
+			return windowID
+		}
+		windowID = target
+	}
+}
+
+// snapCandidate 一个可吸附的目标
+type snapCandidate struct {
+	target   int64           // 吸附目标，mainSnapNode 表示主窗口
+	edge     models.SnapEdge // 吸附的边缘
+	distance float64         // 与该边缘或角落的距离
+}
+
+// nearestSnapEdge 计算窗口相对目标最近的可吸附边缘
+// 角落优先于边，角落的判定范围为阈值的 1.5 倍；都超出阈值时返回 false
+func nearestSnapEdge(target, window application.Rect, threshold float64) (models.SnapEdge, float64, bool) {
+	cornerThreshold := threshold * 1.5
+
+	targetLeft, targetTop := target.X, target.Y
+	targetRight, targetBottom := target.X+target.Width, target.Y+target.Height
+
+	windowLeft, windowTop := window.X, window.Y
+	windowRight, windowBottom := window.X+window.Width, window.Y+window.Height
+
+	best := models.SnapEdgeNone
+	bestDistance := 0.0
+
+	// 先检查四个角落
+	cornerChecks := []struct {
+		edge models.SnapEdge
+		dx   int
+		dy   int
+	}{
+		{models.SnapEdgeTopRight, targetRight - windowLeft, targetTop - windowBottom},
+		{models.SnapEdgeBottomRight, targetRight - windowLeft, targetBottom - windowTop},
+		{models.SnapEdgeBottomLeft, targetLeft - windowRight, targetBottom - windowTop},
+		{models.SnapEdgeTopLeft, targetLeft - windowRight, targetTop - windowBottom},
+	}
+	for _, check := range cornerChecks {
+		dist := math.Sqrt(float64(check.dx*check.dx + check.dy*check.dy))
+		if dist <= cornerThreshold && (best == models.SnapEdgeNone || dist < bestDistance) {
+			best, bestDistance = check.edge, dist
+		}
+	}
+	if best != models.SnapEdgeNone {
+		return best, bestDistance, true
+	}
+
+	// 再检查四条边
+	edgeChecks := []struct {
+		edge     models.SnapEdge
+		distance float64
+	}{
+		{models.SnapEdgeRight, math.Abs(float64(targetRight - windowLeft))},
+		{models.SnapEdgeLeft, math.Abs(float64(targetLeft - windowRight))},
+		{models.SnapEdgeBottom, math.Abs(float64(targetBottom - windowTop))},
+		{models.SnapEdgeTop, math.Abs(float64(targetTop - windowBottom))},
+	}
+	for _, check := range edgeChecks {
+		if check.distance <= threshold && (best == models.SnapEdgeNone || check.distance < bestDistance) {
+			best, bestDistance = check.edge, check.distance
+		}
+	}
+	return best, bestDistance, best != models.SnapEdgeNone
+}
+
+// snapPositionFor 计算吸附到目标指定边缘后窗口的位置，吸附到边时保留另一方向的坐标
+func snapPositionFor(target application.Rect, edge models.SnapEdge, currentPos models.WindowPosition, width, height int) models.WindowPosition {
+	switch edge {
+	case models.SnapEdgeRight:
+		return models.WindowPosition{X: target.X + target.Width, Y: currentPos.Y}
+	case models.SnapEdgeLeft:
+		return models.WindowPosition{X: target.X - width, Y: currentPos.Y}
+	case models.SnapEdgeBottom:
+		return models.WindowPosition{X: currentPos.X, Y: target.Y + target.Height}
+	case models.SnapEdgeTop:
+		return models.WindowPosition{X: currentPos.X, Y: target.Y - height}
+	case models.SnapEdgeTopRight:
+		return models.WindowPosition{X: target.X + target.Width, Y: target.Y - height}
+	case models.SnapEdgeBottomRight:
+		return models.WindowPosition{X: target.X + target.Width, Y: target.Y + target.Height}
+	case models.SnapEdgeBottomLeft:
+		return models.WindowPosition{X: target.X - width, Y: target.Y + target.Height}
+	case models.SnapEdgeTopLeft:
+		return models.WindowPosition{X: target.X - width, Y: target.Y - height}
+	}
+	return currentPos
+}
+
+// snapTargetRectLocked 吸附目标当前的位置和尺寸，调用方需持有 mu
+// 主窗口使用缓存；文档窗口使用最近记录的位置和尺寸缓存，尚未缓存尺寸时返回 false
+func (wss *WindowSnapService) snapTargetRectLocked(target int64) (application.Rect, bool) {
+	if target == mainSnapNode {
+		size := wss.lastMainWindowSize
+		pos := wss.lastMainWindowPos
+		return application.Rect{X: pos.X, Y: pos.Y, Width: size[0], Height: size[1]}, size[0] > 0 && size[1] > 0
+	}
+	info, ok := wss.managedWindows[target]
+	if !ok {
+		return application.Rect{}, false
+	}
+	size, ok := wss.windowSizeCache[target]
+	if !ok || size[0] <= 0 || size[1] <= 0 {
+		return application.Rect{}, false
+	}
+	return application.Rect{X: info.LastPos.X, Y: info.LastPos.Y, Width: size[0], Height: size[1]}, true
+}
+
+// snapTargetPositionLocked 吸附目标当前的位置，调用方需持有 mu
+func (wss *WindowSnapService) snapTargetPositionLocked(target int64) models.WindowPosition {
+	if target == mainSnapNode {
+		return wss.lastMainWindowPos
+	}
+	if info, ok := wss.managedWindows[target]; ok {
+		return info.LastPos
+	}
+	return models.WindowPosition{}
+}
+
+// findSnapCandidateLocked 在主窗口和其他文档窗口中找出距离最近的可吸附边缘，调用方需持有 mu
+// 跟随当前窗口移动的窗口不作为目标，避免形成循环；距离相同时优先主窗口
+func (wss *WindowSnapService) findSnapCandidateLocked(windowID int64, rect application.Rect, threshold float64) (snapCandidate, bool) {
+	var best snapCandidate
+	found := false
+	consider := func(target int64) {
+		targetRect, ok := wss.snapTargetRectLocked(target)
+		if !ok {
+			return
+		}
+		edge, distance, ok := nearestSnapEdge(targetRect, rect, threshold)
+		if ok && (!found || distance < best.distance) {
+			best, found = snapCandidate{target: target, edge: edge, distance: distance}, true
+		}
+	}
+
+	consider(mainSnapNode)
+	ids := make([]int64, 0, len(wss.managedWindows))
+	for id := range wss.managedWindows {
+		if id != windowID && !wss.snapGraph.follows(id, windowID) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		consider(id)
+	}
+	return best, found
+}
+
+// snapWindowLocked 记录窗口吸附到目标，调用方需持有 mu
+func (wss *WindowSnapService) snapWindowLocked(windowInfo *models.WindowInfo, target int64, edge models.SnapEdge, pos models.WindowPosition) {
+	targetPos := wss.snapTargetPositionLocked(target)
+	windowInfo.IsSnapped = true
+	windowInfo.SnapTarget = target
+	windowInfo.SnapEdge = edge
+	windowInfo.SnapOffset = models.SnapPosition{X: pos.X - targetPos.X, Y: pos.Y - targetPos.Y}
+	wss.snapGraph.link(windowInfo.WindowID, target)
+	wss.emitSnapStateLocked(windowInfo)
+}
+
+// unsnapWindowLocked 解除窗口自身的吸附，吸附在它上面的窗口继续跟随它，调用方需持有 mu
+func (wss *WindowSnapService) unsnapWindowLocked(windowInfo *models.WindowInfo) {
+	wasSnapped := windowInfo.IsSnapped
+	windowInfo.IsSnapped = false
+	windowInfo.SnapTarget = mainSnapNode
+	windowInfo.SnapEdge = models.SnapEdgeNone
+	wss.snapGraph.unlink(windowInfo.WindowID)
+	if wasSnapped {
+		wss.emitSnapStateLocked(windowInfo)
+	}
+}
+
+// moveFollowersLocked 窗口位置变化后，按层级移动直接或间接吸附在它上面的窗口，调用方需持有 mu
+func (wss *WindowSnapService) moveFollowersLocked(root int64, skip int64) {
+	for _, id := range wss.snapGraph.descendants(root) {
+		if id == skip {
+			continue
+		}
+		if info, ok := wss.managedWindows[id]; ok && info.IsSnapped {
+			wss.updateSnappedWindowPosition(info)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+// PlaceNewWindow 按设置把新建的文档窗口直接吸附到主窗口一侧，返回是否已放置
+// 未开启该设置、吸附已停用、主窗口不可见或窗口位置由平铺式窗口管理器接管时返回 false，由调用方居中显示
+func (wss *WindowSnapService) PlaceNewWindow(windowID int64) bool {
+	config, err := wss.configService.GetConfig()
+	if err != nil || !config.General.SnapNewWindows {
+		return false
+	}
+	if _, ownsLayout := wss.tilingMode(); ownsLayout {
+		return false
+	}
+	mainWindow := wss.windowHelper.MustGetMainWindow()
+	if mainWindow == nil || !mainWindow.IsVisible() || mainWindow.IsMinimised() {
+		return false
+	}
+
+	wss.mu.Lock()
+	defer wss.mu.Unlock()
+
+	windowInfo, ok := wss.managedWindows[windowID]
+	window, exists := wss.windowRefs[windowID]
+	if !wss.snapEnabled || !ok || !exists {
+		return false
+	}
+
+	wss.updateMainWindowCacheLocked()
+	mainRect, ok := wss.snapTargetRectLocked(mainSnapNode)
+	if !ok {
+		return false
+	}
+
+	// 吸附到左右两侧时与主窗口顶部对齐，吸附到上下两侧时与主窗口左边对齐
+	edge := config.General.NewWindowSnapEdge.SnapEdge()
+	pos := wss.calculateSnapPosition(mainRect, edge, wss.lastMainWindowPos, windowID, window)
+	wss.snapWindowLocked(windowInfo, mainSnapNode, edge, pos)
+
+	// 新窗口刚创建，直接移动不播放动画
+	wss.isUpdatingPosition[windowID] = true
+	wss.mu.Unlock()
+	window.SetPosition(pos.X, pos.Y)
+	wss.mu.Lock()
+	wss.isUpdatingPosition[windowID] = false
+
+	windowInfo.LastPos = pos
+	return true
+}
@@ -25,13 +25,19 @@ const (
 // WindowSnapService 窗口吸附服务
 // 提供窗口自动吸附功能，管理主窗口和子窗口的位置关系，实现窗口间的智能对齐和吸附效果
 type WindowSnapService struct {
-	logger        *log.LogService
-	configService *ConfigService
-	windowHelper  *helper.WindowHelper
-	mu            sync.RWMutex
+	logger               *log.LogService
+	configService        *ConfigService
+	accessibilityService *AccessibilityService
+	windowHelper         *helper.WindowHelper
+	mu                   sync.RWMutex
 
 	// 吸附配置
-	snapEnabled bool // 是否启用窗口吸附功能
+	snapEnabled bool                      // 是否启用窗口吸附功能
+	animateSnap bool                      // 是否平滑移动吸附窗口
+	dragMode    models.WindowSnapDragMode // 拖动已吸附子窗口时解除吸附或整组移动
+
+	// 进行中的吸附动画
+	animations map[int64]*snapAnimation // windowID -> 动画状态
 
 	// 自适应阈值参数
 	baseThresholdRatio float64 // 基础阈值比例
@@ -43,31 +49,51 @@ type WindowSnapService struct {
 	lastMainWindowSize [2]int                // 缓存主窗口尺寸 [width, height]
 
 	// 管理的窗口
-	managedWindows map[int64]*models.WindowInfo         // documentID -> WindowInfo
-	windowRefs     map[int64]*application.WebviewWindow // documentID -> Window引用
+	managedWindows map[int64]*models.WindowInfo         // windowID -> WindowInfo
+	windowRefs     map[int64]*application.WebviewWindow // windowID -> Window引用
+	snapGraph      *snapGraph                           // 窗口之间的吸附关系，文档窗口可以吸附到主窗口或其他文档窗口
 
 	// 窗口尺寸缓存
-	windowSizeCache map[int64][2]int // documentID -> [width, height]
+	windowSizeCache map[int64][2]int // windowID -> [width, height]
 
 	// 事件循环保护
-	isUpdatingPosition map[int64]bool // documentID -> 是否正在更新位置
+	isUpdatingPosition map[int64]bool // windowID -> 是否正在更新位置
 
 	// 事件监听器清理函数
 	mainMoveUnhook    func()           // 主窗口移动监听清理函数
-	windowMoveUnhooks map[int64]func() // documentID -> 子窗口移动监听清理函数
+	mainResizeUnhook  func()           // 主窗口尺寸变化监听清理函数
+	windowMoveUnhooks map[int64]func() // windowID -> 子窗口移动和尺寸变化监听清理函数
+
+	// 主窗口被系统排列的检测状态
+	mainMoveTime      time.Time
+	mainResizeTime    time.Time
+	mainArrangedUntil time.Time
+
+	// 整组拖动时由子窗口带动主窗口，此时间前主窗口的移动事件由本服务引起，不再带动子窗口
+	mainFollowUntil time.Time
+
+	// 系统窗口排列功能的兼容开关和检测结果
+	tilingMu        sync.Mutex
+	compat          models.WindowSnapCompatConfig
+	tiling          []models.TilingStatus
+	tilingCheckedAt time.Time
 
 	// 配置观察者取消函数
-	cancelObserver CancelFunc
+	cancelObserver         CancelFunc
+	cancelCompatObserver   CancelFunc
+	cancelAnimateObserver  CancelFunc
+	cancelDragModeObserver CancelFunc
 }
 
 // NewWindowSnapService 创建一个新的窗口吸附服务实例
 // 参数:
 //   - logger: 日志服务实例，用于记录窗口吸附相关的日志信息，如果传入nil则会创建默认日志服务
 //   - configService: 配置服务实例，用于获取和监听窗口吸附相关配置
+//   - accessibilityService: 辅助功能偏好服务，开启减少动态效果时不播放吸附动画
 //
 // 返回值:
 //   - *WindowSnapService: 初始化完成的窗口吸附服务实例
-func NewWindowSnapService(logger *log.LogService, configService *ConfigService) *WindowSnapService {
+func NewWindowSnapService(logger *log.LogService, configService *ConfigService, accessibilityService *AccessibilityService) *WindowSnapService {
 	if logger == nil {
 		logger = log.New()
 	}
@@ -75,28 +101,43 @@ func NewWindowSnapService(logger *log.LogService, configService *ConfigService)
 	// 从配置获取窗口吸附设置
 	config, err := configService.GetConfig()
 	snapEnabled := true // 默认启用
+	animateSnap := true
+	dragMode := models.WindowSnapDragDetach
+	var compat models.WindowSnapCompatConfig
 
 	if err == nil {
 		snapEnabled = config.General.EnableWindowSnap
+		animateSnap = config.General.AnimateWindowSnap
+		dragMode = config.General.WindowSnapDragMode
+		compat = config.General.WindowSnapCompat
 	}
 
 	wss := &WindowSnapService{
-		logger:             logger,
-		configService:      configService,
-		windowHelper:       helper.NewWindowHelper(),
-		snapEnabled:        snapEnabled,
-		baseThresholdRatio: 0.025, // 2.5%的主窗口宽度作为基础阈值
-		minThreshold:       8,     // 最小8像素（小屏幕保底）
-		maxThreshold:       40,    // 最大40像素（大屏幕上限）
-		managedWindows:     make(map[int64]*models.WindowInfo),
-		windowRefs:         make(map[int64]*application.WebviewWindow),
-		windowSizeCache:    make(map[int64][2]int),
-		isUpdatingPosition: make(map[int64]bool),
-		windowMoveUnhooks:  make(map[int64]func()),
+		logger:               logger,
+		configService:        configService,
+		accessibilityService: accessibilityService,
+		windowHelper:         helper.NewWindowHelper(),
+		snapEnabled:          snapEnabled,
+		animateSnap:          animateSnap,
+		dragMode:             dragMode,
+		animations:           make(map[int64]*snapAnimation),
+		baseThresholdRatio:   0.025, // 2.5%的主窗口宽度作为基础阈值
+		minThreshold:         8,     // 最小8像素（小屏幕保底）
+		maxThreshold:         40,    // 最大40像素（大屏幕上限）
+		managedWindows:       make(map[int64]*models.WindowInfo),
+		windowRefs:           make(map[int64]*application.WebviewWindow),
+		snapGraph:            newSnapGraph(),
+		windowSizeCache:      make(map[int64][2]int),
+		isUpdatingPosition:   make(map[int64]bool),
+		windowMoveUnhooks:    make(map[int64]func()),
+		compat:               compat,
 	}
 
 	// 注册窗口吸附配置监听
 	wss.cancelObserver = configService.Watch("general.enableWindowSnap", wss.onWindowSnapConfigChange)
+	wss.cancelCompatObserver = configService.Watch("general.windowSnapCompat", wss.onWindowSnapCompatChange)
+	wss.cancelAnimateObserver = configService.Watch("general.animateWindowSnap", wss.onWindowSnapAnimateChange)
+	wss.cancelDragModeObserver = configService.Watch("general.windowSnapDragMode", wss.onWindowSnapDragModeChange)
 
 	return wss
 }
@@ -125,9 +166,9 @@ func (wss *WindowSnapService) onWindowSnapConfigChange(oldValue, newValue interf
 
 // RegisterWindow 注册需要吸附管理的窗口
 // RegisterWindow 注册一个窗口到窗口快照服务中，用于跟踪和管理窗口的位置变化
-// documentID: 文档唯一标识符，用于关联窗口和文档
+// windowID: 窗口唯一标识符，由 WindowService 分配，一个窗口可包含多个文档标签
 // window: 要注册的Webview窗口对象
-func (wss *WindowSnapService) RegisterWindow(documentID int64, window *application.WebviewWindow) {
+func (wss *WindowSnapService) RegisterWindow(windowID int64, window *application.WebviewWindow) {
 	wss.mu.Lock()
 	defer wss.mu.Unlock()
 
@@ -136,7 +177,7 @@ func (wss *WindowSnapService) RegisterWindow(documentID int64, window *applicati
 
 	// 创建窗口信息结构体，初始化窗口的基本状态和位置信息
 	windowInfo := &models.WindowInfo{
-		DocumentID: documentID,
+		WindowID:   windowID,
 		IsSnapped:  false,
 		SnapOffset: models.SnapPosition{X: 0, Y: 0},
 		SnapEdge:   models.SnapEdgeNone,
@@ -145,11 +186,11 @@ func (wss *WindowSnapService) RegisterWindow(documentID int64, window *applicati
 	}
 
 	// 将窗口信息存储到管理映射中
-	wss.managedWindows[documentID] = windowInfo
-	wss.windowRefs[documentID] = window
+	wss.managedWindows[windowID] = windowInfo
+	wss.windowRefs[windowID] = window
 
 	// 初始化窗口尺寸缓存
-	wss.updateWindowSizeCacheLocked(documentID, window)
+	wss.updateWindowSizeCacheLocked(windowID, window)
 
 	// 如果这是第一个注册的窗口，启动主窗口事件监听
 	if len(wss.managedWindows) == 1 {
@@ -160,31 +201,39 @@ func (wss *WindowSnapService) RegisterWindow(documentID int64, window *applicati
 	wss.setupWindowEvents(window, windowInfo)
 }
 
-// UnregisterWindow 取消注册指定文档ID的窗口，清理相关资源和事件监听
+// UnregisterWindow 取消注册指定ID的窗口，清理相关资源和事件监听
 //
 // 参数:
 //
-//	documentID - 需要取消注册的窗口对应的文档ID
+//	windowID - 需要取消注册的窗口的窗口ID
 //
 // 该函数会执行以下操作：
 // 1. 获取互斥锁以保证线程安全
 // 2. 清理指定窗口的子窗口移动事件监听器
 // 3. 从各个管理映射中删除该窗口的相关数据
 // 4. 如果没有剩余的管理窗口，则清理主窗口事件监听
-func (wss *WindowSnapService) UnregisterWindow(documentID int64) {
+func (wss *WindowSnapService) UnregisterWindow(windowID int64) {
 	wss.mu.Lock()
 	defer wss.mu.Unlock()
 
 	// 清理子窗口事件监听
-	if unhook, exists := wss.windowMoveUnhooks[documentID]; exists {
+	if unhook, exists := wss.windowMoveUnhooks[windowID]; exists {
 		unhook()
-		delete(wss.windowMoveUnhooks, documentID)
+		delete(wss.windowMoveUnhooks, windowID)
 	}
 
-	delete(wss.managedWindows, documentID)
-	delete(wss.windowRefs, documentID)
-	delete(wss.windowSizeCache, documentID)
-	delete(wss.isUpdatingPosition, documentID)
+	delete(wss.managedWindows, windowID)
+	delete(wss.windowRefs, windowID)
+	delete(wss.windowSizeCache, windowID)
+	delete(wss.isUpdatingPosition, windowID)
+	delete(wss.animations, windowID)
+
+	// 吸附在该窗口上的窗口失去目标，解除吸附
+	for _, id := range wss.snapGraph.remove(windowID) {
+		if info, ok := wss.managedWindows[id]; ok {
+			wss.unsnapWindowLocked(info)
+		}
+	}
 
 	// 如果没有管理的窗口了，取消主窗口事件监听
 	if len(wss.managedWindows) == 0 {
@@ -210,8 +259,7 @@ func (wss *WindowSnapService) SetSnapEnabled(enabled bool) {
 	if !enabled {
 		for _, windowInfo := range wss.managedWindows {
 			if windowInfo.IsSnapped {
-				windowInfo.IsSnapped = false
-				windowInfo.SnapEdge = models.SnapEdgeNone
+				wss.unsnapWindowLocked(windowInfo)
 			}
 		}
 	}
@@ -295,6 +343,9 @@ func (wss *WindowSnapService) setupMainWindowEvents() {
 	wss.mainMoveUnhook = mainWindow.RegisterHook(events.Common.WindowDidMove, func(event *application.WindowEvent) {
 		wss.onMainWindowMoved()
 	})
+	wss.mainResizeUnhook = mainWindow.RegisterHook(events.Common.WindowDidResize, func(event *application.WindowEvent) {
+		wss.onMainWindowResized()
+	})
 
 }
 
@@ -306,6 +357,10 @@ func (wss *WindowSnapService) cleanupMainWindowEvents() {
 		wss.mainMoveUnhook()
 		wss.mainMoveUnhook = nil
 	}
+	if wss.mainResizeUnhook != nil {
+		wss.mainResizeUnhook()
+		wss.mainResizeUnhook = nil
+	}
 }
 
 // setupWindowEvents 为子窗口设置事件监听
@@ -316,12 +371,18 @@ func (wss *WindowSnapService) cleanupMainWindowEvents() {
 //	windowInfo: 包含窗口信息的结构体指针
 func (wss *WindowSnapService) setupWindowEvents(window *application.WebviewWindow, windowInfo *models.WindowInfo) {
 	// 监听子窗口移动事件，保存清理函数
-	unhook := window.RegisterHook(events.Common.WindowDidMove, func(event *application.WindowEvent) {
+	unhookMove := window.RegisterHook(events.Common.WindowDidMove, func(event *application.WindowEvent) {
 		wss.onChildWindowMoved(window, windowInfo)
 	})
+	unhookResize := window.RegisterHook(events.Common.WindowDidResize, func(event *application.WindowEvent) {
+		wss.onChildWindowResized(window, windowInfo)
+	})
 
 	// 保存清理函数以便后续取消监听
-	wss.windowMoveUnhooks[windowInfo.DocumentID] = unhook
+	wss.windowMoveUnhooks[windowInfo.WindowID] = func() {
+		unhookMove()
+		unhookResize()
+	}
 }
 
 // updateMainWindowCacheLocked 更新主窗口缓存信息
@@ -355,42 +416,42 @@ func (wss *WindowSnapService) UpdateMainWindowCache() {
 }
 
 // updateWindowSizeCacheLocked 更新窗口尺寸缓存
-// updateWindowSizeCacheLocked 更新指定文档ID的窗口尺寸缓存
+// updateWindowSizeCacheLocked 更新指定窗口的尺寸缓存
 // 该函数在持有锁的情况下调用，会临时释放锁来安全地获取窗口尺寸，
 // 然后重新获取锁并更新缓存
 //
 // 参数:
 //
-//	documentID - 需要更新缓存的文档唯一标识符
+//	windowID - 需要更新缓存的窗口唯一标识符
 //	window - 窗口对象，用于获取当前尺寸信息
-func (wss *WindowSnapService) updateWindowSizeCacheLocked(documentID int64, window *application.WebviewWindow) {
+func (wss *WindowSnapService) updateWindowSizeCacheLocked(windowID int64, window *application.WebviewWindow) {
 	// 在锁外获取窗口尺寸，避免死锁
 	wss.mu.Unlock()
 	w, h := window.Size()
 	wss.mu.Lock()
 
-	wss.windowSizeCache[documentID] = [2]int{w, h}
+	wss.windowSizeCache[windowID] = [2]int{w, h}
 }
 
 // getWindowSizeCached 获取缓存的窗口尺寸，如果不存在则实时获取并缓存
-// getWindowSizeCached 获取指定文档窗口的尺寸，优先从缓存中获取，如果缓存不存在则实时获取并更新缓存
+// getWindowSizeCached 获取指定窗口的尺寸，优先从缓存中获取，如果缓存不存在则实时获取并更新缓存
 // 参数:
-//   - documentID: 文档唯一标识符，用于索引窗口尺寸缓存
+//   - windowID: 窗口唯一标识符，用于索引窗口尺寸缓存
 //   - window: Webview窗口对象，当缓存未命中时用于实时获取窗口尺寸
 //
 // 返回值:
 //   - int: 窗口宽度
 //   - int: 窗口高度
-func (wss *WindowSnapService) getWindowSizeCached(documentID int64, window *application.WebviewWindow) (int, int) {
+func (wss *WindowSnapService) getWindowSizeCached(windowID int64, window *application.WebviewWindow) (int, int) {
 	// 先检查缓存
-	if size, exists := wss.windowSizeCache[documentID]; exists {
+	if size, exists := wss.windowSizeCache[windowID]; exists {
 		return size[0], size[1]
 	}
 
 	// 缓存不存在，实时获取并缓存
-	wss.updateWindowSizeCacheLocked(documentID, window)
+	wss.updateWindowSizeCacheLocked(windowID, window)
 
-	if size, exists := wss.windowSizeCache[documentID]; exists {
+	if size, exists := wss.windowSizeCache[windowID]; exists {
 		return size[0], size[1]
 	}
 
@@ -409,6 +470,10 @@ func (wss *WindowSnapService) onMainWindowMoved() {
 	if !wss.snapEnabled {
 		return
 	}
+	active, ownsLayout := wss.tilingMode()
+	if ownsLayout {
+		return
+	}
 
 	// 先在锁外获取主窗口的位置和尺寸
 	mainWindow := wss.windowHelper.MustGetMainWindow()
@@ -422,16 +487,34 @@ func (wss *WindowSnapService) onMainWindowMoved() {
 	wss.mu.Lock()
 	defer wss.mu.Unlock()
 
+	// 整组拖动时主窗口由子窗口带动，缓存已更新，子窗口也已就位
+	now := time.Now()
+	if now.Before(wss.mainFollowUntil) {
+		return
+	}
+
 	// 更新主窗口位置和尺寸缓存
+	previousSize := wss.lastMainWindowSize
 	wss.lastMainWindowPos = models.WindowPosition{X: x, Y: y}
 	wss.lastMainWindowSize = [2]int{w, h}
+	wss.mainMoveTime = now
 
-	// 只更新已吸附窗口的位置，无需重新检测所有窗口
-	for _, windowInfo := range wss.managedWindows {
-		if windowInfo.IsSnapped {
-			wss.updateSnappedWindowPosition(windowInfo)
+	if active {
+		// 移动事件中尺寸已经变化，说明尺寸变化事件早于移动事件或不会单独触发
+		if previousSize[0] != 0 && previousSize != wss.lastMainWindowSize {
+			wss.mainResizeTime = now
+		}
+		if arrangedByOS(wss.mainMoveTime, wss.mainResizeTime, now) {
+			wss.mainArrangedUntil = now.Add(osArrangeSuppress)
+			wss.releaseSnappedWindowsLocked()
+		}
+		if now.Before(wss.mainArrangedUntil) {
+			return
 		}
 	}
+
+	// 只更新直接或间接吸附在主窗口上的窗口，无需重新检测所有窗口
+	wss.moveFollowersLocked(mainSnapNode, mainSnapNode)
 }
 
 // onChildWindowMoved 子窗口移动事件处理
@@ -442,10 +525,14 @@ func (wss *WindowSnapService) onChildWindowMoved(window *application.WebviewWind
 	if !wss.snapEnabled {
 		return
 	}
+	active, ownsLayout := wss.tilingMode()
+	if ownsLayout {
+		return
+	}
 
 	// 事件循环保护：如果正在更新位置，忽略此次事件
 	wss.mu.Lock()
-	if wss.isUpdatingPosition[windowInfo.DocumentID] {
+	if wss.isUpdatingPosition[windowInfo.WindowID] {
 		wss.mu.Unlock()
 		return
 	}
@@ -453,6 +540,10 @@ func (wss *WindowSnapService) onChildWindowMoved(window *application.WebviewWind
 
 	x, y := window.Position()
 	currentPos := models.WindowPosition{X: x, Y: y}
+	var currentSize [2]int
+	if active {
+		currentSize[0], currentSize[1] = window.Size()
+	}
 
 	wss.mu.Lock()
 	defer wss.mu.Unlock()
@@ -466,6 +557,22 @@ func (wss *WindowSnapService) onChildWindowMoved(window *application.WebviewWind
 	lastMoveTime := windowInfo.MoveTime
 	windowInfo.MoveTime = time.Now()
 
+	if active {
+		// 系统排列窗口时不吸附，也不把窗口拉回吸附位置
+		if cached, ok := wss.windowSizeCache[windowInfo.WindowID]; ok && cached != currentSize {
+			windowInfo.ResizeTime = windowInfo.MoveTime
+			wss.windowSizeCache[windowInfo.WindowID] = currentSize
+		}
+		if arrangedByOS(windowInfo.MoveTime, windowInfo.ResizeTime, windowInfo.MoveTime) {
+			windowInfo.ArrangedUntil = windowInfo.MoveTime.Add(osArrangeSuppress)
+			wss.unsnapWindowLocked(windowInfo)
+		}
+		if windowInfo.MoveTime.Before(windowInfo.ArrangedUntil) {
+			windowInfo.LastPos = currentPos
+			return
+		}
+	}
+
 	if windowInfo.IsSnapped {
 		// 已吸附窗口：检查是否被用户拖拽解除吸附
 		wss.handleSnappedWindow(window, windowInfo, currentPos)
@@ -480,35 +587,36 @@ func (wss *WindowSnapService) onChildWindowMoved(window *application.WebviewWind
 		}
 		// 如果成功吸附，位置已在handleUnsnappedWindow中更新
 	}
+
+	// 吸附在该窗口上的窗口跟随移动，整组拖动时已在 moveClusterLocked 中移动
+	if !windowInfo.IsSnapped || wss.dragMode != models.WindowSnapDragCluster {
+		wss.moveFollowersLocked(windowInfo.WindowID, mainSnapNode)
+	}
 }
 
 // updateSnappedWindowPosition 更新已吸附窗口的位置
-// 该函数根据主窗口的新位置和窗口的偏移量，计算并设置吸附窗口的目标位置
+// 该函数根据吸附目标的新位置和窗口的偏移量，计算并设置吸附窗口的目标位置
 // 参数:
 //
 //	windowInfo - 包含窗口信息的结构体指针，用于获取窗口的偏移量和更新最后位置记录
 func (wss *WindowSnapService) updateSnappedWindowPosition(windowInfo *models.WindowInfo) {
-	// 计算新的目标位置（基于主窗口新位置）
-	expectedX := wss.lastMainWindowPos.X + windowInfo.SnapOffset.X
-	expectedY := wss.lastMainWindowPos.Y + windowInfo.SnapOffset.Y
+	// 计算新的目标位置（基于吸附目标新位置）
+	targetPos := wss.snapTargetPositionLocked(windowInfo.SnapTarget)
+	expectedX := targetPos.X + windowInfo.SnapOffset.X
+	expectedY := targetPos.Y + windowInfo.SnapOffset.Y
 
 	// 查找对应的window对象
-	window, exists := wss.windowRefs[windowInfo.DocumentID]
+	window, exists := wss.windowRefs[windowInfo.WindowID]
 	if !exists {
 		return
 	}
 
-	// 设置更新标志，防止事件循环
-	wss.isUpdatingPosition[windowInfo.DocumentID] = true
+	// 跟随主窗口移动时同样避开任务栏和 Dock
+	windowWidth, windowHeight := wss.getWindowSizeCached(windowInfo.WindowID, window)
+	target := wss.keepInWorkArea(models.WindowPosition{X: expectedX, Y: expectedY}, windowWidth, windowHeight)
 
-	wss.mu.Unlock()
-	window.SetPosition(expectedX, expectedY)
-	wss.mu.Lock()
-
-	// 清除更新标志
-	wss.isUpdatingPosition[windowInfo.DocumentID] = false
-
-	windowInfo.LastPos = models.WindowPosition{X: expectedX, Y: expectedY}
+	wss.moveWindowLocked(windowInfo.WindowID, window, windowInfo.LastPos, target)
+	windowInfo.LastPos = target
 }
 
 // handleSnappedWindow 处理已吸附窗口的移动
@@ -521,9 +629,16 @@ func (wss *WindowSnapService) updateSnappedWindowPosition(windowInfo *models.Win
 //	windowInfo: 窗口信息结构体指针，包含窗口的吸附状态、偏移量等信息
 //	currentPos: 窗口当前位置坐标
 func (wss *WindowSnapService) handleSnappedWindow(window *application.WebviewWindow, windowInfo *models.WindowInfo, currentPos models.WindowPosition) {
+	// 整组拖动模式：子窗口带动主窗口和其他已吸附窗口，保持吸附
+	if wss.dragMode == models.WindowSnapDragCluster {
+		wss.moveClusterLocked(windowInfo, currentPos)
+		return
+	}
+
 	// 计算预期位置
-	expectedX := wss.lastMainWindowPos.X + windowInfo.SnapOffset.X
-	expectedY := wss.lastMainWindowPos.Y + windowInfo.SnapOffset.Y
+	targetPos := wss.snapTargetPositionLocked(windowInfo.SnapTarget)
+	expectedX := targetPos.X + windowInfo.SnapOffset.X
+	expectedY := targetPos.Y + windowInfo.SnapOffset.Y
 
 	// 计算实际位置与预期位置的距离
 	distanceX := math.Abs(float64(currentPos.X - expectedX))
@@ -536,13 +651,12 @@ func (wss *WindowSnapService) handleSnappedWindow(window *application.WebviewWin
 
 	if isUserDrag {
 		// 用户主动拖拽，解除吸附
-		windowInfo.IsSnapped = false
-		windowInfo.SnapEdge = models.SnapEdgeNone
+		wss.unsnapWindowLocked(windowInfo)
 	}
 }
 
 // handleUnsnappedWindow 处理未吸附窗口的移动，返回是否成功吸附
-// handleUnsnappedWindow 处理未吸附窗口的移动逻辑，检查是否应该将窗口吸附到主窗口边缘
+// handleUnsnappedWindow 处理未吸附窗口的移动逻辑，检查是否应该将窗口吸附到主窗口或其他文档窗口的边缘
 // 参数:
 //
 //	window: Webview窗口对象，表示需要处理的窗口
@@ -555,40 +669,30 @@ func (wss *WindowSnapService) handleSnappedWindow(window *application.WebviewWin
 //	bool: 如果窗口被成功吸附则返回true，否则返回false
 func (wss *WindowSnapService) handleUnsnappedWindow(window *application.WebviewWindow, windowInfo *models.WindowInfo, currentPos models.WindowPosition, lastMoveTime time.Time) bool {
 	// 检查是否应该吸附
-	should, snapEdge := wss.shouldSnapToMainWindow(window, windowInfo, currentPos, lastMoveTime)
-	if should {
-		// 设置吸附状态
-		windowInfo.IsSnapped = true
-		windowInfo.SnapEdge = snapEdge
-
-		// 执行吸附移动
-		targetPos := wss.calculateSnapPosition(snapEdge, currentPos, windowInfo.DocumentID, window)
-
-		// 设置更新标志，防止事件循环
-		wss.isUpdatingPosition[windowInfo.DocumentID] = true
-
-		wss.mu.Unlock()
-		window.SetPosition(targetPos.X, targetPos.Y)
-		wss.mu.Lock()
-
-		// 清除更新标志
-		wss.isUpdatingPosition[windowInfo.DocumentID] = false
+	candidate, should := wss.shouldSnapWindow(window, windowInfo, currentPos, lastMoveTime)
+	if !should {
+		return false
+	}
+	targetRect, ok := wss.snapTargetRectLocked(candidate.target)
+	if !ok {
+		return false
+	}
 
-		// 计算并保存偏移量
-		windowInfo.SnapOffset.X = targetPos.X - wss.lastMainWindowPos.X
-		windowInfo.SnapOffset.Y = targetPos.Y - wss.lastMainWindowPos.Y
+	// 执行吸附移动
+	targetPos := wss.calculateSnapPosition(targetRect, candidate.edge, currentPos, windowInfo.WindowID, window)
 
-		// 更新位置为吸附后的位置
-		windowInfo.LastPos = targetPos
+	// 设置吸附状态并计算相对吸附目标的偏移量
+	wss.snapWindowLocked(windowInfo, candidate.target, candidate.edge, targetPos)
+	wss.moveWindowLocked(windowInfo.WindowID, window, currentPos, targetPos)
 
-		return true
-	}
+	// 更新位置为吸附后的位置
+	windowInfo.LastPos = targetPos
 
-	return false
+	return true
 }
 
-// shouldSnapToMainWindow 吸附检测
-// shouldSnapToMainWindow 判断给定的子窗口是否应该吸附到主窗口的某个边缘或角落。
+// shouldSnapWindow 吸附检测
+// shouldSnapWindow 判断给定的子窗口是否应该吸附到主窗口或其他文档窗口的某个边缘或角落。
 // 参数：
 //   - window: 当前正在移动的 WebviewWindow 实例
 //   - windowInfo: 子窗口的信息模型对象
@@ -596,13 +700,13 @@ func (wss *WindowSnapService) handleUnsnappedWindow(window *application.WebviewW
 //   - lastMoveTime: 上次移动事件的时间戳，用于防抖处理
 //
 // 返回值：
+//   - snapCandidate: 距离最近的吸附目标和边缘
 //   - bool: 是否需要进行吸附操作
-//   - models.SnapEdge: 吸附的目标边缘类型（如顶部、底部、左侧等），如果不吸附则为 SnapEdgeNone
-func (wss *WindowSnapService) shouldSnapToMainWindow(window *application.WebviewWindow, windowInfo *models.WindowInfo, currentPos models.WindowPosition, lastMoveTime time.Time) (bool, models.SnapEdge) {
+func (wss *WindowSnapService) shouldSnapWindow(window *application.WebviewWindow, windowInfo *models.WindowInfo, currentPos models.WindowPosition, lastMoveTime time.Time) (snapCandidate, bool) {
 	// 防抖：如果距离上次移动时间过短，则跳过检测以避免频繁触发
 	timeSinceLastMove := time.Since(lastMoveTime)
 	if timeSinceLastMove < debounceThreshold {
-		return false, models.SnapEdgeNone
+		return snapCandidate{}, false
 	}
 
 	// 使用缓存的主窗口位置和尺寸数据。若尚未初始化，则立即更新缓存
@@ -610,147 +714,44 @@ func (wss *WindowSnapService) shouldSnapToMainWindow(window *application.Webview
 		wss.updateMainWindowCacheLocked()
 	}
 
-	mainPos := wss.lastMainWindowPos
-	mainWidth := wss.lastMainWindowSize[0]
-	mainHeight := wss.lastMainWindowSize[1]
-
 	// 获取并使用缓存中的子窗口尺寸，减少系统调用开销
-	windowWidth, windowHeight := wss.getWindowSizeCached(windowInfo.DocumentID, window)
+	windowWidth, windowHeight := wss.getWindowSizeCached(windowInfo.WindowID, window)
+	rect := application.Rect{X: currentPos.X, Y: currentPos.Y, Width: windowWidth, Height: windowHeight}
 
 	// 根据自适应逻辑计算吸附阈值，提高不同分辨率下的兼容性
 	threshold := float64(wss.calculateAdaptiveThreshold())
-	cornerThreshold := threshold * 1.5
-
-	// 计算主窗口与子窗口各自的左右上下边界坐标
-	mainLeft, mainTop := mainPos.X, mainPos.Y
-	mainRight, mainBottom := mainPos.X+mainWidth, mainPos.Y+mainHeight
 
-	windowLeft, windowTop := currentPos.X, currentPos.Y
-	windowRight, windowBottom := currentPos.X+windowWidth, currentPos.Y+windowHeight
+	candidate, found := wss.findSnapCandidateLocked(windowInfo.WindowID, rect, threshold)
 
-	// 定义一个结构体用于记录可能发生的吸附情况及其优先级
-	type snapCheck struct {
-		edge     models.SnapEdge
-		distance float64
-		priority int // 优先级：1 表示角落吸附，2 表示边缘吸附
-	}
-
-	var bestSnap *snapCheck
-
-	// 先检查四个角落方向的吸附可能性（高优先级）
-	cornerChecks := []struct {
-		edge models.SnapEdge
-		dx   int
-		dy   int
-	}{
-		{models.SnapEdgeTopRight, mainRight - windowLeft, mainTop - windowBottom},
-		{models.SnapEdgeBottomRight, mainRight - windowLeft, mainBottom - windowTop},
-		{models.SnapEdgeBottomLeft, mainLeft - windowRight, mainBottom - windowTop},
-		{models.SnapEdgeTopLeft, mainLeft - windowRight, mainTop - windowBottom},
-	}
-
-	for _, check := range cornerChecks {
-		dist := math.Sqrt(float64(check.dx*check.dx + check.dy*check.dy))
-		if dist <= cornerThreshold {
-			if bestSnap == nil || dist < bestSnap.distance {
-				bestSnap = &snapCheck{check.edge, dist, 1}
-			}
+	// 手动解除吸附的窗口移出吸附范围后才恢复自动吸附
+	if windowInfo.Released {
+		if !found {
+			windowInfo.Released = false
 		}
+		return snapCandidate{}, false
 	}
-
-	// 若无合适的角落吸附点，则继续检查四条边的边缘吸附（低优先级）
-	if bestSnap == nil {
-		edgeChecks := []struct {
-			edge     models.SnapEdge
-			distance float64
-		}{
-			{models.SnapEdgeRight, math.Abs(float64(mainRight - windowLeft))},
-			{models.SnapEdgeLeft, math.Abs(float64(mainLeft - windowRight))},
-			{models.SnapEdgeBottom, math.Abs(float64(mainBottom - windowTop))},
-			{models.SnapEdgeTop, math.Abs(float64(mainTop - windowBottom))},
-		}
-
-		for _, check := range edgeChecks {
-			if check.distance <= threshold {
-				if bestSnap == nil || check.distance < bestSnap.distance {
-					bestSnap = &snapCheck{check.edge, check.distance, 2}
-				}
-			}
-		}
-	}
-
-	// 如果没有任何满足条件的吸附目标，则不执行吸附
-	if bestSnap == nil {
-		return false, models.SnapEdgeNone
-	}
-
-	// 返回应启用吸附，并告知具体吸附的方向
-	return true, bestSnap.edge
+	return candidate, found
 }
 
 // calculateSnapPosition 计算吸附目标位置
 // calculateSnapPosition 根据指定的吸附边缘计算窗口的新位置。
 // 参数:
-//   - snapEdge: 指定窗口要吸附到主窗口的哪个边缘或角落。
+//   - target: 吸附目标窗口的位置和尺寸。
+//   - snapEdge: 指定窗口要吸附到目标的哪个边缘或角落。
 //   - currentPos: 当前窗口的位置信息。
-//   - documentID: 窗口对应的文档 ID，用于缓存尺寸查询。
+//   - windowID: 窗口 ID，用于缓存尺寸查询。
 //   - window: Webview 窗口对象，用于获取窗口尺寸等信息。
 //
 // 返回值:
-//   - models.WindowPosition: 计算后的新窗口位置。
-func (wss *WindowSnapService) calculateSnapPosition(snapEdge models.SnapEdge, currentPos models.WindowPosition, documentID int64, window *application.WebviewWindow) models.WindowPosition {
-	// 使用缓存的主窗口信息
-	mainPos := wss.lastMainWindowPos
-	mainWidth := wss.lastMainWindowSize[0]
-	mainHeight := wss.lastMainWindowSize[1]
-
+//   - models.WindowPosition: 计算后的新窗口位置，已限制在所在屏幕的工作区内。
+func (wss *WindowSnapService) calculateSnapPosition(target application.Rect, snapEdge models.SnapEdge, currentPos models.WindowPosition, windowID int64, window *application.WebviewWindow) models.WindowPosition {
 	// 使用缓存的子窗口尺寸，减少系统调用
-	windowWidth, windowHeight := wss.getWindowSizeCached(documentID, window)
+	windowWidth, windowHeight := wss.getWindowSizeCached(windowID, window)
 
-	switch snapEdge {
-	case models.SnapEdgeRight:
-		return models.WindowPosition{
-			X: mainPos.X + mainWidth,
-			Y: currentPos.Y, // 保持当前Y位置
-		}
-	case models.SnapEdgeLeft:
-		return models.WindowPosition{
-			X: mainPos.X - windowWidth,
-			Y: currentPos.Y,
-		}
-	case models.SnapEdgeBottom:
-		return models.WindowPosition{
-			X: currentPos.X,
-			Y: mainPos.Y + mainHeight,
-		}
-	case models.SnapEdgeTop:
-		return models.WindowPosition{
-			X: currentPos.X,
-			Y: mainPos.Y - windowHeight,
-		}
-	case models.SnapEdgeTopRight:
-		return models.WindowPosition{
-			X: mainPos.X + mainWidth,
-			Y: mainPos.Y - windowHeight,
-		}
-	case models.SnapEdgeBottomRight:
-		return models.WindowPosition{
-			X: mainPos.X + mainWidth,
-			Y: mainPos.Y + mainHeight,
-		}
-	case models.SnapEdgeBottomLeft:
-		return models.WindowPosition{
-			X: mainPos.X - windowWidth,
-			Y: mainPos.Y + mainHeight,
-		}
-	case models.SnapEdgeTopLeft:
-		return models.WindowPosition{
-			X: mainPos.X - windowWidth,
-			Y: mainPos.Y - windowHeight,
-		}
-	}
+	pos := snapPositionFor(target, snapEdge, currentPos, windowWidth, windowHeight)
 
-	return currentPos
+	// 吸附位置可能落在任务栏或 Dock 下方，移入工作区
+	return wss.keepInWorkArea(pos, windowWidth, windowHeight)
 }
 
 // Cleanup 清理窗口快照服务的所有资源
@@ -764,19 +765,21 @@ func (wss *WindowSnapService) Cleanup() {
 	wss.cleanupMainWindowEvents()
 
 	// 清理所有子窗口事件监听
-	for documentID, unhook := range wss.windowMoveUnhooks {
+	for windowID, unhook := range wss.windowMoveUnhooks {
 		if unhook != nil {
 			unhook()
 		}
-		delete(wss.windowMoveUnhooks, documentID)
+		delete(wss.windowMoveUnhooks, windowID)
 	}
 
 	// 清空管理的窗口
 	wss.managedWindows = make(map[int64]*models.WindowInfo)
 	wss.windowRefs = make(map[int64]*application.WebviewWindow)
+	wss.snapGraph = newSnapGraph()
 	wss.windowSizeCache = make(map[int64][2]int)
 	wss.isUpdatingPosition = make(map[int64]bool)
 	wss.windowMoveUnhooks = make(map[int64]func())
+	wss.animations = make(map[int64]*snapAnimation)
 }
 
 // ServiceShutdown 实现服务关闭接口
@@ -791,6 +794,15 @@ func (wss *WindowSnapService) ServiceShutdown() error {
 	if wss.cancelObserver != nil {
 		wss.cancelObserver()
 	}
+	if wss.cancelCompatObserver != nil {
+		wss.cancelCompatObserver()
+	}
+	if wss.cancelAnimateObserver != nil {
+		wss.cancelAnimateObserver()
+	}
+	if wss.cancelDragModeObserver != nil {
+		wss.cancelDragModeObserver()
+	}
 	wss.Cleanup()
 	return nil
 }
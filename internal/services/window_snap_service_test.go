@@ -1,6 +1,7 @@
 package services
 
 import (
+	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -50,6 +51,7 @@ func createTestService() *WindowSnapService {
 		maxThreshold:       40,
 		managedWindows:     make(map[int64]*models.WindowInfo),
 		windowRefs:         make(map[int64]*application.WebviewWindow),
+		snapGraph:          newSnapGraph(),
 		windowSizeCache:    make(map[int64][2]int),
 		isUpdatingPosition: make(map[int64]bool),
 		windowMoveUnhooks:  make(map[int64]func()),
@@ -147,7 +149,7 @@ func TestWindowSizeCache(t *testing.T) {
 	// 测试缓存读取
 	service.mu.Lock()
 	if size, exists := service.windowSizeCache[1]; !exists {
-		t.Error("Cache should exist for documentID 1")
+		t.Error("Cache should exist for windowID 1")
 	} else if size[0] != 640 || size[1] != 480 {
 		t.Errorf("Cache size = [%d, %d], want [640, 480]", size[0], size[1])
 	}
@@ -156,7 +158,7 @@ func TestWindowSizeCache(t *testing.T) {
 	// 测试缓存不存在的情况
 	service.mu.Lock()
 	if _, exists := service.windowSizeCache[999]; exists {
-		t.Error("Cache should not exist for documentID 999")
+		t.Error("Cache should not exist for windowID 999")
 	}
 	service.mu.Unlock()
 }
@@ -188,39 +190,39 @@ func TestUnregisterWindow(t *testing.T) {
 	service := createTestService()
 
 	// 模拟注册窗口
-	documentID := int64(1)
-	service.managedWindows[documentID] = &models.WindowInfo{DocumentID: documentID}
-	service.windowSizeCache[documentID] = [2]int{640, 480}
-	service.isUpdatingPosition[documentID] = false
+	windowID := int64(1)
+	service.managedWindows[windowID] = &models.WindowInfo{WindowID: windowID}
+	service.windowSizeCache[windowID] = [2]int{640, 480}
+	service.isUpdatingPosition[windowID] = false
 
 	// 模拟事件清理函数
 	cleanupCalled := false
-	service.windowMoveUnhooks[documentID] = func() {
+	service.windowMoveUnhooks[windowID] = func() {
 		cleanupCalled = true
 	}
 
 	// 验证已注册
 	service.mu.RLock()
-	if _, exists := service.managedWindows[documentID]; !exists {
+	if _, exists := service.managedWindows[windowID]; !exists {
 		t.Error("Window should be registered")
 	}
 	service.mu.RUnlock()
 
 	// 注销窗口
-	service.UnregisterWindow(documentID)
+	service.UnregisterWindow(windowID)
 
 	// 验证已清理
 	service.mu.RLock()
-	if _, exists := service.managedWindows[documentID]; exists {
+	if _, exists := service.managedWindows[windowID]; exists {
 		t.Error("managedWindows should be cleaned up")
 	}
-	if _, exists := service.windowSizeCache[documentID]; exists {
+	if _, exists := service.windowSizeCache[windowID]; exists {
 		t.Error("windowSizeCache should be cleaned up")
 	}
-	if _, exists := service.isUpdatingPosition[documentID]; exists {
+	if _, exists := service.isUpdatingPosition[windowID]; exists {
 		t.Error("isUpdatingPosition should be cleaned up")
 	}
-	if _, exists := service.windowMoveUnhooks[documentID]; exists {
+	if _, exists := service.windowMoveUnhooks[windowID]; exists {
 		t.Error("windowMoveUnhooks should be cleaned up")
 	}
 	service.mu.RUnlock()
@@ -236,8 +238,8 @@ func TestCleanup(t *testing.T) {
 	service := createTestService()
 
 	// 添加一些数据
-	service.managedWindows[1] = &models.WindowInfo{DocumentID: 1}
-	service.managedWindows[2] = &models.WindowInfo{DocumentID: 2}
+	service.managedWindows[1] = &models.WindowInfo{WindowID: 1}
+	service.managedWindows[2] = &models.WindowInfo{WindowID: 2}
 	service.windowSizeCache[1] = [2]int{640, 480}
 	service.windowSizeCache[2] = [2]int{800, 600}
 	service.isUpdatingPosition[1] = false
@@ -287,11 +289,11 @@ func TestEventCleanupOnUnregister(t *testing.T) {
 	service := createTestService()
 
 	// 模拟多个窗口注册
-	documentIDs := []int64{1, 2, 3, 4, 5}
+	windowIDs := []int64{1, 2, 3, 4, 5}
 	cleanupCounters := make(map[int64]int)
 
-	for _, id := range documentIDs {
-		service.managedWindows[id] = &models.WindowInfo{DocumentID: id}
+	for _, id := range windowIDs {
+		service.managedWindows[id] = &models.WindowInfo{WindowID: id}
 		service.windowSizeCache[id] = [2]int{640, 480}
 
 		// 为每个窗口添加清理函数
@@ -302,12 +304,12 @@ func TestEventCleanupOnUnregister(t *testing.T) {
 	}
 
 	// 验证初始状态
-	if len(service.windowMoveUnhooks) != len(documentIDs) {
-		t.Errorf("Expected %d cleanup hooks, got %d", len(documentIDs), len(service.windowMoveUnhooks))
+	if len(service.windowMoveUnhooks) != len(windowIDs) {
+		t.Errorf("Expected %d cleanup hooks, got %d", len(windowIDs), len(service.windowMoveUnhooks))
 	}
 
 	// 逐个注销窗口
-	for _, id := range documentIDs[:3] { // 注销前3个
+	for _, id := range windowIDs[:3] { // 注销前3个
 		service.UnregisterWindow(id)
 	}
 
@@ -321,14 +323,14 @@ func TestEventCleanupOnUnregister(t *testing.T) {
 	}
 
 	// 验证清理函数被调用
-	for _, id := range documentIDs[:3] {
+	for _, id := range windowIDs[:3] {
 		if cleanupCounters[id] != 1 {
 			t.Errorf("Cleanup for window %d should have been called once, got %d", id, cleanupCounters[id])
 		}
 	}
 
 	// 验证未注销的窗口清理函数未被调用
-	for _, id := range documentIDs[3:] {
+	for _, id := range windowIDs[3:] {
 		if cleanupCounters[id] != 0 {
 			t.Errorf("Cleanup for window %d should not have been called, got %d", id, cleanupCounters[id])
 		}
@@ -341,8 +343,8 @@ func TestEventCleanupOnUnregister(t *testing.T) {
 func TestEventCleanupNilSafety(t *testing.T) {
 	service := createTestService()
 
-	documentID := int64(1)
-	service.managedWindows[documentID] = &models.WindowInfo{DocumentID: documentID}
+	windowID := int64(1)
+	service.managedWindows[windowID] = &models.WindowInfo{WindowID: windowID}
 	// 故意不设置清理函数
 
 	// 注销窗口不应该panic
@@ -352,7 +354,7 @@ func TestEventCleanupNilSafety(t *testing.T) {
 		}
 	}()
 
-	service.UnregisterWindow(documentID)
+	service.UnregisterWindow(windowID)
 
 	t.Log("Nil cleanup function handled safely")
 }
@@ -373,16 +375,16 @@ func TestConcurrentRegisterUnregister(t *testing.T) {
 		go func(id int) {
 			defer wg.Done()
 			for j := 0; j < iterations; j++ {
-				documentID := int64(id*iterations + j)
+				windowID := int64(id*iterations + j)
 				windowInfo := &models.WindowInfo{
-					DocumentID: documentID,
-					IsSnapped:  false,
-					LastPos:    models.WindowPosition{X: 0, Y: 0},
-					MoveTime:   time.Now(),
+					WindowID:  windowID,
+					IsSnapped: false,
+					LastPos:   models.WindowPosition{X: 0, Y: 0},
+					MoveTime:  time.Now(),
 				}
 				service.mu.Lock()
-				service.managedWindows[documentID] = windowInfo
-				service.windowSizeCache[documentID] = [2]int{640, 480}
+				service.managedWindows[windowID] = windowInfo
+				service.windowSizeCache[windowID] = [2]int{640, 480}
 				service.mu.Unlock()
 			}
 		}(i)
@@ -394,8 +396,8 @@ func TestConcurrentRegisterUnregister(t *testing.T) {
 			defer wg.Done()
 			time.Sleep(10 * time.Millisecond) // 让注册先执行一点
 			for j := 0; j < iterations; j++ {
-				documentID := int64(id*iterations + j)
-				service.UnregisterWindow(documentID)
+				windowID := int64(id*iterations + j)
+				service.UnregisterWindow(windowID)
 			}
 		}(i)
 	}
@@ -477,9 +479,9 @@ func TestConcurrentCacheAccess(t *testing.T) {
 		go func(id int) {
 			defer wg.Done()
 			for j := 0; j < operations; j++ {
-				documentID := int64(id*operations + j)
+				windowID := int64(id*operations + j)
 				service.mu.Lock()
-				service.windowSizeCache[documentID] = [2]int{640, 480}
+				service.windowSizeCache[windowID] = [2]int{640, 480}
 				service.mu.Unlock()
 			}
 		}(i)
@@ -490,9 +492,9 @@ func TestConcurrentCacheAccess(t *testing.T) {
 		go func(id int) {
 			defer wg.Done()
 			for j := 0; j < operations; j++ {
-				documentID := int64(id*operations + j)
+				windowID := int64(id*operations + j)
 				service.mu.Lock()
-				delete(service.windowSizeCache, documentID)
+				delete(service.windowSizeCache, windowID)
 				service.mu.Unlock()
 			}
 		}(i)
@@ -553,13 +555,13 @@ func BenchmarkCalculateAdaptiveThreshold(b *testing.B) {
 // BenchmarkWindowSizeCacheHit 基准测试：缓存命中
 func BenchmarkWindowSizeCacheHit(b *testing.B) {
 	service := createTestService()
-	documentID := int64(1)
-	service.windowSizeCache[documentID] = [2]int{640, 480}
+	windowID := int64(1)
+	service.windowSizeCache[windowID] = [2]int{640, 480}
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		service.mu.RLock()
-		_, _ = service.windowSizeCache[documentID][0], service.windowSizeCache[documentID][1]
+		_, _ = service.windowSizeCache[windowID][0], service.windowSizeCache[windowID][1]
 		service.mu.RUnlock()
 	}
 }
@@ -570,20 +572,20 @@ func BenchmarkRegisterUnregister(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		documentID := int64(i)
+		windowID := int64(i)
 		windowInfo := &models.WindowInfo{
-			DocumentID: documentID,
-			IsSnapped:  false,
-			LastPos:    models.WindowPosition{X: 0, Y: 0},
-			MoveTime:   time.Now(),
+			WindowID:  windowID,
+			IsSnapped: false,
+			LastPos:   models.WindowPosition{X: 0, Y: 0},
+			MoveTime:  time.Now(),
 		}
 
 		service.mu.Lock()
-		service.managedWindows[documentID] = windowInfo
-		service.windowSizeCache[documentID] = [2]int{640, 480}
+		service.managedWindows[windowID] = windowInfo
+		service.windowSizeCache[windowID] = [2]int{640, 480}
 		service.mu.Unlock()
 
-		service.UnregisterWindow(documentID)
+		service.UnregisterWindow(windowID)
 	}
 }
 
@@ -600,9 +602,9 @@ func BenchmarkConcurrentCacheRead(b *testing.B) {
 	b.RunParallel(func(pb *testing.PB) {
 		i := 0
 		for pb.Next() {
-			documentID := int64(i % 100)
+			windowID := int64(i % 100)
 			service.mu.RLock()
-			_, _ = service.windowSizeCache[documentID][0], service.windowSizeCache[documentID][1]
+			_, _ = service.windowSizeCache[windowID][0], service.windowSizeCache[windowID][1]
 			service.mu.RUnlock()
 			i++
 		}
@@ -652,15 +654,15 @@ func TestMemoryUsage(t *testing.T) {
 
 	// 添加大量窗口
 	for i := 0; i < windows; i++ {
-		documentID := int64(i)
+		windowID := int64(i)
 		service.mu.Lock()
-		service.managedWindows[documentID] = &models.WindowInfo{
-			DocumentID: documentID,
-			IsSnapped:  false,
-			LastPos:    models.WindowPosition{X: i, Y: i},
-			MoveTime:   time.Now(),
+		service.managedWindows[windowID] = &models.WindowInfo{
+			WindowID:  windowID,
+			IsSnapped: false,
+			LastPos:   models.WindowPosition{X: i, Y: i},
+			MoveTime:  time.Now(),
 		}
-		service.windowSizeCache[documentID] = [2]int{640, 480}
+		service.windowSizeCache[windowID] = [2]int{640, 480}
 		service.mu.Unlock()
 	}
 
@@ -696,12 +698,12 @@ func TestWindowResizeCache(t *testing.T) {
 	service := createTestService()
 
 	// 初始化缓存
-	documentID := int64(1)
-	service.windowSizeCache[documentID] = [2]int{640, 480}
+	windowID := int64(1)
+	service.windowSizeCache[windowID] = [2]int{640, 480}
 
 	// 验证初始缓存
 	service.mu.RLock()
-	size := service.windowSizeCache[documentID]
+	size := service.windowSizeCache[windowID]
 	service.mu.RUnlock()
 
 	if size[0] != 640 || size[1] != 480 {
@@ -710,12 +712,12 @@ func TestWindowResizeCache(t *testing.T) {
 
 	// 模拟窗口尺寸变化
 	service.mu.Lock()
-	service.windowSizeCache[documentID] = [2]int{800, 600}
+	service.windowSizeCache[windowID] = [2]int{800, 600}
 	service.mu.Unlock()
 
 	// 验证缓存更新
 	service.mu.RLock()
-	newSize := service.windowSizeCache[documentID]
+	newSize := service.windowSizeCache[windowID]
 	service.mu.RUnlock()
 
 	if newSize[0] != 800 || newSize[1] != 600 {
@@ -734,9 +736,9 @@ func TestMainWindowResizeEffect(t *testing.T) {
 	service.lastMainWindowSize = [2]int{800, 600}
 
 	// 添加一个已吸附的窗口
-	documentID := int64(1)
-	service.managedWindows[documentID] = &models.WindowInfo{
-		DocumentID: documentID,
+	windowID := int64(1)
+	service.managedWindows[windowID] = &models.WindowInfo{
+		WindowID:   windowID,
 		IsSnapped:  true,
 		SnapEdge:   models.SnapEdgeRight,
 		SnapOffset: models.SnapPosition{X: 800, Y: 0}, // 吸附在右侧
@@ -753,7 +755,7 @@ func TestMainWindowResizeEffect(t *testing.T) {
 	// 注意：这里只是验证数据结构，实际的位置更新由事件触发
 
 	service.mu.RLock()
-	windowInfo := service.managedWindows[documentID]
+	windowInfo := service.managedWindows[windowID]
 	service.mu.RUnlock()
 
 	if !windowInfo.IsSnapped {
@@ -775,14 +777,14 @@ func TestConcurrentResizeAndMove(t *testing.T) {
 
 	// 初始化一些窗口
 	for i := 0; i < 10; i++ {
-		documentID := int64(i)
-		service.managedWindows[documentID] = &models.WindowInfo{
-			DocumentID: documentID,
-			IsSnapped:  false,
-			LastPos:    models.WindowPosition{X: i * 100, Y: i * 100},
-			MoveTime:   time.Now(),
+		windowID := int64(i)
+		service.managedWindows[windowID] = &models.WindowInfo{
+			WindowID:  windowID,
+			IsSnapped: false,
+			LastPos:   models.WindowPosition{X: i * 100, Y: i * 100},
+			MoveTime:  time.Now(),
 		}
-		service.windowSizeCache[documentID] = [2]int{640, 480}
+		service.windowSizeCache[windowID] = [2]int{640, 480}
 	}
 
 	var wg sync.WaitGroup
@@ -793,9 +795,9 @@ func TestConcurrentResizeAndMove(t *testing.T) {
 		go func(id int) {
 			defer wg.Done()
 			for j := 0; j < operations; j++ {
-				documentID := int64(id % 10)
+				windowID := int64(id % 10)
 				service.mu.Lock()
-				service.windowSizeCache[documentID] = [2]int{640 + j, 480 + j}
+				service.windowSizeCache[windowID] = [2]int{640 + j, 480 + j}
 				service.mu.Unlock()
 			}
 		}(i)
@@ -818,9 +820,9 @@ func TestConcurrentResizeAndMove(t *testing.T) {
 		go func() {
 			defer wg.Done()
 			for j := 0; j < operations; j++ {
-				documentID := int64(j % 10)
+				windowID := int64(j % 10)
 				service.mu.RLock()
-				_ = service.windowSizeCache[documentID]
+				_ = service.windowSizeCache[windowID]
 				service.mu.RUnlock()
 			}
 		}()
@@ -830,3 +832,309 @@ func TestConcurrentResizeAndMove(t *testing.T) {
 
 	t.Log("Concurrent resize and move test completed without race conditions")
 }
+
+// TestArrangedByOS 测试系统排列窗口的判断
+func TestArrangedByOS(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name       string
+		moveTime   time.Time
+		resizeTime time.Time
+		want       bool
+	}{
+		{"move and resize together", now.Add(-50 * time.Millisecond), now, true},
+		{"resize before move", now, now.Add(-100 * time.Millisecond), true},
+		{"drag only", now, time.Time{}, false},
+		{"resize long after move", now.Add(-2 * time.Second), now, false},
+		{"both long ago", now.Add(-2 * time.Second), now.Add(-2 * time.Second), false},
+	}
+	for _, tc := range cases {
+		if got := arrangedByOS(tc.moveTime, tc.resizeTime, now); got != tc.want {
+			t.Errorf("%s: arrangedByOS = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+// TestTilingWMFromEnv 测试平铺式窗口管理器的识别
+func TestTilingWMFromEnv(t *testing.T) {
+	cases := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{"SWAYSOCK": "/run/user/1000/sway-ipc.sock"}, "sway"},
+		{map[string]string{"HYPRLAND_INSTANCE_SIGNATURE": "abc"}, "hyprland"},
+		{map[string]string{"XDG_CURRENT_DESKTOP": "GNOME"}, ""},
+		{map[string]string{"XDG_CURRENT_DESKTOP": "ubuntu:GNOME"}, ""},
+		{map[string]string{"DESKTOP_SESSION": "bspwm"}, "bspwm"},
+		{map[string]string{"XDG_SESSION_DESKTOP": "i3"}, "i3"},
+		{map[string]string{}, ""},
+	}
+	for _, tc := range cases {
+		if got := tilingWMFromEnv(func(key string) string { return tc.env[key] }); got != tc.want {
+			t.Errorf("tilingWMFromEnv(%v) = %q, want %q", tc.env, got, tc.want)
+		}
+	}
+}
+
+// TestFitToWorkArea 测试吸附位置移入工作区
+func TestFitToWorkArea(t *testing.T) {
+	// 1920x1080 屏幕，底部 40 像素为任务栏
+	area := application.Rect{X: 0, Y: 0, Width: 1920, Height: 1040}
+	cases := []struct {
+		name string
+		pos  models.WindowPosition
+		want models.WindowPosition
+	}{
+		{"inside", models.WindowPosition{X: 100, Y: 100}, models.WindowPosition{X: 100, Y: 100}},
+		{"under taskbar", models.WindowPosition{X: 100, Y: 900}, models.WindowPosition{X: 100, Y: 740}},
+		{"off right edge", models.WindowPosition{X: 1800, Y: 100}, models.WindowPosition{X: 1520, Y: 100}},
+		{"off top left", models.WindowPosition{X: -50, Y: -20}, models.WindowPosition{X: 0, Y: 0}},
+	}
+	for _, tc := range cases {
+		if got := fitToWorkArea(tc.pos, 400, 300, area); got != tc.want {
+			t.Errorf("%s: fitToWorkArea = %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+
+	// 窗口比工作区大时对齐左上角
+	if got := fitToWorkArea(models.WindowPosition{X: 50, Y: 50}, 2000, 1200, area); got != (models.WindowPosition{}) {
+		t.Errorf("oversized window: fitToWorkArea = %+v, want origin", got)
+	}
+}
+
+// TestScreenForRect 测试多显示器下选择窗口所在的屏幕
+func TestScreenForRect(t *testing.T) {
+	left := &application.Screen{ID: "left", Bounds: application.Rect{X: 0, Y: 0, Width: 1920, Height: 1080}}
+	right := &application.Screen{ID: "right", Bounds: application.Rect{X: 1920, Y: 0, Width: 2560, Height: 1440}}
+	screens := []*application.Screen{left, right}
+
+	cases := []struct {
+		rect application.Rect
+		want string
+	}{
+		{application.Rect{X: 100, Y: 100, Width: 400, Height: 300}, "left"},
+		{application.Rect{X: 1800, Y: 100, Width: 400, Height: 300}, "right"},
+		{application.Rect{X: 5000, Y: 100, Width: 400, Height: 300}, "right"},
+		{application.Rect{X: -900, Y: 100, Width: 400, Height: 300}, "left"},
+	}
+	for _, tc := range cases {
+		if got := screenForRect(tc.rect, screens); got == nil || got.ID != tc.want {
+			t.Errorf("screenForRect(%+v) = %v, want %s", tc.rect, got, tc.want)
+		}
+	}
+	if got := screenForRect(application.Rect{Width: 10, Height: 10}, nil); got != nil {
+		t.Errorf("screenForRect without screens = %v, want nil", got)
+	}
+}
+
+// TestSnapAnimationStep 测试吸附动画的插值
+func TestSnapAnimationStep(t *testing.T) {
+	from := models.WindowPosition{X: 0, Y: 100}
+	to := models.WindowPosition{X: 200, Y: 0}
+
+	if pos, finished := snapAnimationStep(from, to, 0); pos != from || finished {
+		t.Errorf("start: got %+v finished=%v, want %+v", pos, finished, from)
+	}
+	if pos, finished := snapAnimationStep(from, to, snapAnimationDuration); pos != to || !finished {
+		t.Errorf("end: got %+v finished=%v, want %+v", pos, finished, to)
+	}
+
+	// 缓出曲线：过半时间时已经移动了大部分距离
+	pos, _ := snapAnimationStep(from, to, snapAnimationDuration/2)
+	if pos.X <= 100 || pos.X >= 200 || pos.Y >= 50 || pos.Y <= 0 {
+		t.Errorf("midway: got %+v, want most of the distance covered", pos)
+	}
+
+	prev := from.X
+	for elapsed := time.Duration(0); elapsed <= snapAnimationDuration; elapsed += snapAnimationFrame {
+		pos, _ := snapAnimationStep(from, to, elapsed)
+		if pos.X < prev {
+			t.Fatalf("animation moved backwards at %v: %d < %d", elapsed, pos.X, prev)
+		}
+		prev = pos.X
+	}
+}
+
+// TestMainPositionFromChild 测试整组拖动时由子窗口位置反推主窗口位置
+func TestMainPositionFromChild(t *testing.T) {
+	mainPos := models.WindowPosition{X: 100, Y: 100}
+	offset := models.SnapPosition{X: 800, Y: -40}
+	childPos := models.WindowPosition{X: mainPos.X + offset.X, Y: mainPos.Y + offset.Y}
+
+	if got := mainPositionFromChild(childPos, offset); got != mainPos {
+		t.Errorf("mainPositionFromChild = %+v, want %+v", got, mainPos)
+	}
+
+	moved := models.WindowPosition{X: childPos.X + 25, Y: childPos.Y - 10}
+	if got, want := mainPositionFromChild(moved, offset), (models.WindowPosition{X: 125, Y: 90}); got != want {
+		t.Errorf("mainPositionFromChild after drag = %+v, want %+v", got, want)
+	}
+}
+
+// TestWindowSnapDragModeChange 测试拖动模式配置变化
+func TestWindowSnapDragModeChange(t *testing.T) {
+	service := createTestService()
+
+	service.onWindowSnapDragModeChange(nil, string(models.WindowSnapDragCluster))
+	if service.dragMode != models.WindowSnapDragCluster {
+		t.Errorf("dragMode = %q, want %q", service.dragMode, models.WindowSnapDragCluster)
+	}
+
+	service.onWindowSnapDragModeChange(nil, "unknown")
+	if service.dragMode != models.WindowSnapDragDetach {
+		t.Errorf("dragMode = %q, want %q", service.dragMode, models.WindowSnapDragDetach)
+	}
+}
+
+// TestSnapGraph 测试吸附关系图的层级和移除
+func TestSnapGraph(t *testing.T) {
+	g := newSnapGraph()
+	g.link(1, mainSnapNode)
+	g.link(2, 1)
+	g.link(3, 2)
+	g.link(4, mainSnapNode)
+
+	if got := g.descendants(mainSnapNode); !reflect.DeepEqual(got, []int64{1, 4, 2, 3}) {
+		t.Errorf("descendants(main) = %v, want [1 4 2 3]", got)
+	}
+	if !g.follows(3, 1) || g.follows(1, 3) {
+		t.Error("follows should only hold from follower to target")
+	}
+	if got := g.root(3); got != mainSnapNode {
+		t.Errorf("root(3) = %d, want main", got)
+	}
+
+	// 重新吸附替换原有关系
+	g.link(2, 4)
+	if got := g.directFollowers(1); len(got) != 0 {
+		t.Errorf("directFollowers(1) = %v, want none", got)
+	}
+
+	if got := g.remove(2); !reflect.DeepEqual(got, []int64{3}) {
+		t.Errorf("remove(2) = %v, want [3]", got)
+	}
+	if _, ok := g.target(3); ok {
+		t.Error("window 3 should be detached after its target is removed")
+	}
+	if got := g.descendants(mainSnapNode); !reflect.DeepEqual(got, []int64{1, 4}) {
+		t.Errorf("descendants(main) after remove = %v, want [1 4]", got)
+	}
+}
+
+// TestNearestSnapEdge 测试吸附边缘检测，角落优先于边
+func TestNearestSnapEdge(t *testing.T) {
+	target := application.Rect{X: 100, Y: 100, Width: 800, Height: 600}
+	tests := []struct {
+		name   string
+		window application.Rect
+		edge   models.SnapEdge
+		ok     bool
+	}{
+		{"right edge", application.Rect{X: 905, Y: 300, Width: 400, Height: 300}, models.SnapEdgeRight, true},
+		{"bottom edge", application.Rect{X: 300, Y: 695, Width: 400, Height: 300}, models.SnapEdgeBottom, true},
+		{"bottom right corner", application.Rect{X: 905, Y: 705, Width: 400, Height: 300}, models.SnapEdgeBottomRight, true},
+		{"too far", application.Rect{X: 1000, Y: 300, Width: 400, Height: 300}, models.SnapEdgeNone, false},
+	}
+	for _, tt := range tests {
+		edge, _, ok := nearestSnapEdge(target, tt.window, 20)
+		if edge != tt.edge || ok != tt.ok {
+			t.Errorf("%s: nearestSnapEdge = %v, %v, want %v, %v", tt.name, edge, ok, tt.edge, tt.ok)
+		}
+	}
+}
+
+// TestFindSnapCandidateChildToChild 测试文档窗口之间的吸附，优先选择距离最近的边缘
+func TestFindSnapCandidateChildToChild(t *testing.T) {
+	service := createTestService()
+	// 主窗口 (100,100) 800x600，窗口 1 吸附在主窗口右侧
+	service.managedWindows[1] = &models.WindowInfo{WindowID: 1, LastPos: models.WindowPosition{X: 900, Y: 100}}
+	service.windowSizeCache[1] = [2]int{400, 300}
+	service.managedWindows[2] = &models.WindowInfo{WindowID: 2}
+	service.windowSizeCache[2] = [2]int{400, 300}
+
+	// 窗口 2 靠近窗口 1 的下边，离主窗口右边较远
+	rect := application.Rect{X: 905, Y: 403, Width: 400, Height: 300}
+	candidate, ok := service.findSnapCandidateLocked(2, rect, 20)
+	if !ok || candidate.target != 1 || candidate.edge != models.SnapEdgeBottom {
+		t.Fatalf("candidate = %+v, %v, want window 1 bottom", candidate, ok)
+	}
+
+	// 吸附后计算偏移，窗口 1 移动时跟随
+	pos := snapPositionFor(application.Rect{X: 900, Y: 100, Width: 400, Height: 300}, candidate.edge, models.WindowPosition{X: rect.X, Y: rect.Y}, 400, 300)
+	service.snapWindowLocked(service.managedWindows[2], candidate.target, candidate.edge, pos)
+	if got := service.managedWindows[2].SnapOffset; got != (models.SnapPosition{X: 5, Y: 300}) {
+		t.Errorf("SnapOffset = %+v, want {5 300}", got)
+	}
+
+	// 窗口 1 不能吸附到跟随它的窗口 2 上
+	service.snapWindowLocked(service.managedWindows[1], mainSnapNode, models.SnapEdgeRight, models.WindowPosition{X: 900, Y: 100})
+	rect = application.Rect{X: 905, Y: 705, Width: 400, Height: 300}
+	if candidate, ok := service.findSnapCandidateLocked(1, rect, 20); ok && candidate.target == 2 {
+		t.Errorf("window 1 should not snap to its follower, got %+v", candidate)
+	}
+
+	service.unsnapWindowLocked(service.managedWindows[2])
+	if info := service.managedWindows[2]; info.IsSnapped || info.SnapTarget != mainSnapNode {
+		t.Errorf("unsnapped window = %+v", info)
+	}
+}
+
+// TestUnsnapWindow 测试手动解除吸附后的状态
+func TestUnsnapWindow(t *testing.T) {
+	service := createTestService()
+	info := &models.WindowInfo{WindowID: 1}
+	service.managedWindows[1] = info
+	service.snapWindowLocked(info, mainSnapNode, models.SnapEdgeTopRight, models.WindowPosition{X: 900, Y: -200})
+
+	state, ok := service.GetWindowSnapState(1)
+	if !ok || !state.IsSnapped || state.Edge != "topRight" || state.Target != mainSnapNode {
+		t.Fatalf("GetWindowSnapState = %+v, %v", state, ok)
+	}
+
+	if !service.UnsnapWindow(1) {
+		t.Fatal("UnsnapWindow should find the managed window")
+	}
+	state, _ = service.GetWindowSnapState(1)
+	if state.IsSnapped || state.Edge != "none" || !info.Released {
+		t.Errorf("after unsnap: state = %+v, released = %v", state, info.Released)
+	}
+	if service.UnsnapWindow(2) {
+		t.Error("UnsnapWindow should report unknown windows")
+	}
+}
+
+// TestNewWindowSnapPlacement 新窗口吸附到主窗口指定一侧，并与主窗口顶部或左边对齐
+func TestNewWindowSnapPlacement(t *testing.T) {
+	service := createTestService()
+	mainRect, ok := service.snapTargetRectLocked(mainSnapNode)
+	if !ok {
+		t.Fatal("main window rect not cached")
+	}
+
+	tests := []struct {
+		edge models.NewWindowSnapEdge
+		want models.WindowPosition
+	}{
+		{models.NewWindowSnapRight, models.WindowPosition{X: 900, Y: 100}},
+		{models.NewWindowSnapLeft, models.WindowPosition{X: -300, Y: 100}},
+		{models.NewWindowSnapBottom, models.WindowPosition{X: 100, Y: 700}},
+		{models.NewWindowSnapTop, models.WindowPosition{X: 100, Y: -200}},
+		// 无效值按右侧处理
+		{"diagonal", models.WindowPosition{X: 900, Y: 100}},
+	}
+	for _, tt := range tests {
+		got := snapPositionFor(mainRect, tt.edge.SnapEdge(), service.lastMainWindowPos, 400, 300)
+		if got != tt.want {
+			t.Errorf("%s: position = %+v, want %+v", tt.edge, got, tt.want)
+		}
+	}
+}
+
+// TestPlaceNewWindowDisabled 未开启设置时由调用方居中显示
+func TestPlaceNewWindowDisabled(t *testing.T) {
+	service := createTestService()
+	service.configService = newTestConfigService(t, models.NewDefaultAppConfig())
+	if service.PlaceNewWindow(1) {
+		t.Error("PlaceNewWindow should not place windows when the setting is off")
+	}
+}
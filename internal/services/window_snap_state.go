@@ -0,0 +1,74 @@
+package services
+
+import (
+	"fmt"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
+)
+
+// snapState 窗口的吸附状态
+func snapState(windowInfo *models.WindowInfo) models.WindowSnapState {
+	state := models.WindowSnapState{WindowID: windowInfo.WindowID, Edge: models.SnapEdgeNone.String()}
+	if windowInfo.IsSnapped {
+		state.IsSnapped = true
+		state.Edge = windowInfo.SnapEdge.String()
+		state.Target = windowInfo.SnapTarget
+	}
+	return state
+}
+
+// emitSnapStateLocked 通知前端窗口吸附或解除吸附，调用方需持有 mu
+func (wss *WindowSnapService) emitSnapStateLocked(windowInfo *models.WindowInfo) {
+	helper.EmitEvent(constant.EVENT_WINDOW_SNAP_CHANGED, snapState(windowInfo))
+}
+
+// GetWindowSnapState 获取文档窗口的吸附状态
+func (wss *WindowSnapService) GetWindowSnapState(windowID int64) (models.WindowSnapState, bool) {
+	wss.mu.RLock()
+	defer wss.mu.RUnlock()
+
+	windowInfo, ok := wss.managedWindows[windowID]
+	if !ok {
+		return models.WindowSnapState{}, false
+	}
+	return snapState(windowInfo), true
+}
+
+// UnsnapWindow 手动解除文档窗口的吸附，窗口留在原位，移出吸附范围前不会再次自动吸附
+func (wss *WindowSnapService) UnsnapWindow(windowID int64) bool {
+	wss.mu.Lock()
+	defer wss.mu.Unlock()
+
+	windowInfo, ok := wss.managedWindows[windowID]
+	if !ok {
+		return false
+	}
+	if windowInfo.IsSnapped {
+		windowInfo.Released = true
+		wss.unsnapWindowLocked(windowInfo)
+	}
+	return true
+}
+
+// GetSnapState 获取文档所在文档窗口的吸附状态
+func (ws *WindowService) GetSnapState(documentID int64) (*models.WindowSnapState, error) {
+	windowID, ok := ws.documentWindowID(documentID)
+	if !ok || ws.windowSnapService == nil {
+		return nil, fmt.Errorf("document %d is not open in a document window", documentID)
+	}
+	state, ok := ws.windowSnapService.GetWindowSnapState(windowID)
+	if !ok {
+		return nil, fmt.Errorf("document window %d is not managed by window snapping", windowID)
+	}
+	return &state, nil
+}
+
+// UnsnapDocumentWindow 解除文档所在文档窗口的吸附
+func (ws *WindowService) UnsnapDocumentWindow(documentID int64) error {
+	windowID, ok := ws.documentWindowID(documentID)
+	if !ok || ws.windowSnapService == nil || !ws.windowSnapService.UnsnapWindow(windowID) {
+		return fmt.Errorf("document %d is not open in a document window", documentID)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"voidraft/internal/models"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// keepInWorkArea 把吸附后的位置移入所在屏幕的工作区，避免窗口被任务栏或 Dock 遮挡
+// 获取不到屏幕信息时保持原位置
+func (wss *WindowSnapService) keepInWorkArea(pos models.WindowPosition, width, height int) models.WindowPosition {
+	app := application.Get()
+	if app == nil || app.Screen == nil {
+		return pos
+	}
+	screen := screenForRect(application.Rect{X: pos.X, Y: pos.Y, Width: width, Height: height}, app.Screen.GetAll())
+	if screen == nil {
+		return pos
+	}
+	return fitToWorkArea(pos, width, height, screen.WorkArea)
+}
+
+// screenForRect 选出与窗口重叠面积最大的屏幕，完全不在任何屏幕上时选中心最近的屏幕
+func screenForRect(rect application.Rect, screens []*application.Screen) *application.Screen {
+	var best *application.Screen
+	bestOverlap := 0
+	for _, screen := range screens {
+		if overlap := rectOverlap(rect, screen.Bounds); overlap > bestOverlap {
+			best, bestOverlap = screen, overlap
+		}
+	}
+	if best != nil {
+		return best
+	}
+
+	cx, cy := rect.X+rect.Width/2, rect.Y+rect.Height/2
+	bestDistance := -1
+	for _, screen := range screens {
+		dx := clampInt(cx, screen.Bounds.X, screen.Bounds.X+screen.Bounds.Width) - cx
+		dy := clampInt(cy, screen.Bounds.Y, screen.Bounds.Y+screen.Bounds.Height) - cy
+		if distance := dx*dx + dy*dy; bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = screen, distance
+		}
+	}
+	return best
+}
+
+// fitToWorkArea 平移窗口使其完整位于工作区内，窗口比工作区大时对齐工作区左上角
+func fitToWorkArea(pos models.WindowPosition, width, height int, area application.Rect) models.WindowPosition {
+	if area.Width <= 0 || area.Height <= 0 {
+		return pos
+	}
+	return models.WindowPosition{
+		X: clampInt(pos.X, area.X, max(area.X, area.X+area.Width-width)),
+		Y: clampInt(pos.Y, area.Y, max(area.Y, area.Y+area.Height-height)),
+	}
+}
+
+// rectOverlap 两个矩形的重叠面积
+func rectOverlap(a, b application.Rect) int {
+	w := min(a.X+a.Width, b.X+b.Width) - max(a.X, b.X)
+	h := min(a.Y+a.Height, b.Y+b.Height) - max(a.Y, b.Y)
+	if w <= 0 || h <= 0 {
+		return 0
+	}
+	return w * h
+}
+
+// clampInt 把 v 限制在 [lo, hi] 内
+func clampInt(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"voidraft/internal/common/constant"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
+)
+
+// documentWindowRegistry 文档窗口与文档的对应关系
+// 一个窗口可以以标签打开多个文档，一个文档同一时间只在一个文档窗口中打开
+type documentWindowRegistry struct {
+	nextID int64
+	tabs   map[int64][]int64 // windowID -> 按标签顺序排列的文档
+	active map[int64]int64   // windowID -> 当前显示的文档
+	owner  map[int64]int64   // documentID -> windowID
+}
+
+// newDocumentWindowRegistry 创建空的窗口登记表
+func newDocumentWindowRegistry() *documentWindowRegistry {
+	return &documentWindowRegistry{
+		tabs:   make(map[int64][]int64),
+		active: make(map[int64]int64),
+		owner:  make(map[int64]int64),
+	}
+}
+
+// open 为文档分配新窗口，返回窗口ID
+func (r *documentWindowRegistry) open(documentID int64) int64 {
+	r.nextID++
+	windowID := r.nextID
+	r.tabs[windowID] = []int64{documentID}
+	r.active[windowID] = documentID
+	r.owner[documentID] = windowID
+	return windowID
+}
+
+// addTab 在已有窗口中以新标签打开文档并切换到该标签
+func (r *documentWindowRegistry) addTab(windowID, documentID int64) error {
+	if _, ok := r.tabs[windowID]; !ok {
+		return fmt.Errorf("document window %d is not open", windowID)
+	}
+	if owner, ok := r.owner[documentID]; ok {
+		if owner != windowID {
+			return fmt.Errorf("document %d is already open in window %d", documentID, owner)
+		}
+	} else {
+		r.tabs[windowID] = append(r.tabs[windowID], documentID)
+		r.owner[documentID] = windowID
+	}
+	r.active[windowID] = documentID
+	return nil
+}
+
+// removeTab 关闭文档所在的标签，返回所在窗口和剩余标签数
+// 关闭的是当前标签时切换到相邻的标签
+func (r *documentWindowRegistry) removeTab(documentID int64) (windowID int64, remaining int, ok bool) {
+	windowID, ok = r.owner[documentID]
+	if !ok {
+		return 0, 0, false
+	}
+	delete(r.owner, documentID)
+
+	tabs := r.tabs[windowID]
+	i := slices.Index(tabs, documentID)
+	tabs = slices.Delete(tabs, i, i+1)
+	r.tabs[windowID] = tabs
+	if r.active[windowID] == documentID {
+		if len(tabs) == 0 {
+			r.active[windowID] = 0
+		} else {
+			r.active[windowID] = tabs[min(i, len(tabs)-1)]
+		}
+	}
+	return windowID, len(tabs), true
+}
+
+// removeWindow 移除窗口及其全部标签，返回窗口中的文档
+func (r *documentWindowRegistry) removeWindow(windowID int64) []int64 {
+	documents := r.tabs[windowID]
+	for _, documentID := range documents {
+		delete(r.owner, documentID)
+	}
+	delete(r.tabs, windowID)
+	delete(r.active, windowID)
+	return documents
+}
+
+// activate 切换窗口当前显示的标签
+func (r *documentWindowRegistry) activate(windowID, documentID int64) error {
+	if r.owner[documentID] != windowID || windowID == 0 {
+		return fmt.Errorf("document %d is not open in window %d", documentID, windowID)
+	}
+	r.active[windowID] = documentID
+	return nil
+}
+
+// windowOf 文档所在的窗口
+func (r *documentWindowRegistry) windowOf(documentID int64) (int64, bool) {
+	windowID, ok := r.owner[documentID]
+	return windowID, ok
+}
+
+// window 窗口的标签快照，窗口不存在时返回 nil
+func (r *documentWindowRegistry) window(windowID int64) *models.DocumentWindow {
+	tabs, ok := r.tabs[windowID]
+	if !ok {
+		return nil
+	}
+	return &models.DocumentWindow{
+		WindowID:  windowID,
+		Documents: slices.Clone(tabs),
+		Active:    r.active[windowID],
+	}
+}
+
+// windowIDs 所有已登记的窗口
+func (r *documentWindowRegistry) windowIDs() []int64 {
+	ids := make([]int64, 0, len(r.tabs))
+	for windowID := range r.tabs {
+		ids = append(ids, windowID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// OpenDocumentInWindow 在已打开的文档窗口中以新标签打开文档
+// 文档已在其他文档窗口中打开时聚焦该窗口
+func (ws *WindowService) OpenDocumentInWindow(windowID, documentID int64) error {
+	ws.tabsMu.Lock()
+	if owner, ok := ws.windows.windowOf(documentID); ok && owner != windowID {
+		ws.tabsMu.Unlock()
+		ws.focusDocumentWindow(owner)
+		return nil
+	}
+	err := ws.windows.addTab(windowID, documentID)
+	ws.tabsMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	ws.refreshFollow(documentID, true)
+	ws.touchRecentDocument(documentID)
+	ws.applyDocumentWindowTabs(windowID)
+	ws.focusDocumentWindow(windowID)
+	return nil
+}
+
+// CloseDocumentTab 关闭文档所在的标签，有未保存的修改时先询问是否保存
+// 关闭窗口中最后一个标签时关闭窗口
+func (ws *WindowService) CloseDocumentTab(documentID int64) error {
+	windowID, ok := ws.documentWindowID(documentID)
+	if !ok {
+		return fmt.Errorf("document %d is not open in a document window", documentID)
+	}
+	if window, exists := ws.windowHelper.GetDocumentWindow(windowID); exists {
+		if ws.confirmCloseDirtyDocuments(window, []int64{documentID}, func() { ws.closeDocumentTab(documentID) }) {
+			return nil
+		}
+	}
+	ws.closeDocumentTab(documentID)
+	return nil
+}
+
+// SetActiveDocumentTab 切换文档窗口当前显示的标签
+func (ws *WindowService) SetActiveDocumentTab(windowID, documentID int64) error {
+	ws.tabsMu.Lock()
+	err := ws.windows.activate(windowID, documentID)
+	ws.tabsMu.Unlock()
+	if err != nil {
+		return err
+	}
+	ws.touchRecentDocument(documentID)
+	ws.applyDocumentWindowTabs(windowID)
+	return nil
+}
+
+// GetDocumentWindowTabs 获取文档窗口中的标签，窗口不存在时返回 nil
+func (ws *WindowService) GetDocumentWindowTabs(windowID int64) *models.DocumentWindow {
+	ws.tabsMu.Lock()
+	defer ws.tabsMu.Unlock()
+	return ws.windows.window(windowID)
+}
+
+// GetDocumentWindowID 获取文档所在的文档窗口，未打开时返回 0
+func (ws *WindowService) GetDocumentWindowID(documentID int64) int64 {
+	windowID, _ := ws.documentWindowID(documentID)
+	return windowID
+}
+
+// documentWindowID 文档所在的文档窗口
+func (ws *WindowService) documentWindowID(documentID int64) (int64, bool) {
+	ws.tabsMu.Lock()
+	defer ws.tabsMu.Unlock()
+	return ws.windows.windowOf(documentID)
+}
+
+// documentWindowDocuments 文档窗口中的全部文档
+func (ws *WindowService) documentWindowDocuments(windowID int64) []int64 {
+	ws.tabsMu.Lock()
+	defer ws.tabsMu.Unlock()
+	if window := ws.windows.window(windowID); window != nil {
+		return window.Documents
+	}
+	return nil
+}
+
+// closeDocumentTab 关闭标签，不再询问是否保存；最后一个标签直接关闭窗口
+func (ws *WindowService) closeDocumentTab(documentID int64) {
+	ws.tabsMu.Lock()
+	windowID, ok := ws.windows.windowOf(documentID)
+	if !ok {
+		ws.tabsMu.Unlock()
+		return
+	}
+	if len(ws.windows.window(windowID).Documents) == 1 {
+		ws.tabsMu.Unlock()
+		ws.closeDocumentWindow(windowID)
+		return
+	}
+	ws.windows.removeTab(documentID)
+	ws.tabsMu.Unlock()
+
+	ws.clearDocumentDirty(documentID)
+	ws.refreshFollow(documentID, false)
+	ws.forgetRecentDocument(documentID)
+	ws.applyDocumentWindowTabs(windowID)
+}
+
+// applyDocumentWindowTabs 按当前标签更新窗口标题并通知前端
+func (ws *WindowService) applyDocumentWindowTabs(windowID int64) {
+	tabs := ws.GetDocumentWindowTabs(windowID)
+	if tabs == nil {
+		return
+	}
+	ws.applyDocumentWindowTitle(windowID, tabs.Active)
+	helper.EmitEvent(constant.EVENT_DOCUMENT_WINDOW_TABS_CHANGED, tabs)
+}
+
+// applyDocumentWindowTitle 按当前标签和未保存状态更新文档窗口标题
+func (ws *WindowService) applyDocumentWindowTitle(windowID, active int64) {
+	if window, ok := ws.windowHelper.GetDocumentWindow(windowID); ok {
+		window.SetTitle(modifiedTitle(documentWindowTitle(ws.paneTitle(active)), ws.IsWindowModified(windowID)))
+	}
+	ws.refreshModifiedBadge()
+}
+
+// focusDocumentWindow 显示并聚焦文档窗口
+func (ws *WindowService) focusDocumentWindow(windowID int64) {
+	ws.windowHelper.FocusDocumentWindow(windowID)
+}
+
+// closeDocumentWindow 关闭文档窗口，不再按按钮配置处理
+func (ws *WindowService) closeDocumentWindow(windowID int64) {
+	window, ok := ws.windowHelper.GetDocumentWindow(windowID)
+	if !ok {
+		return
+	}
+	if ws.trayService != nil {
+		ws.trayService.forceCloseWindow(window)
+		return
+	}
+	window.Close()
+}
+
+// documentWindowTitle 文档窗口标题
+func documentWindowTitle(documentTitle string) string {
+	if documentTitle == "" {
+		return constant.VOIDRAFT_APP_NAME
+	}
+	return fmt.Sprintf("%s - %s", constant.VOIDRAFT_APP_NAME, documentTitle)
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocumentWindowRegistry(t *testing.T) {
+	r := newDocumentWindowRegistry()
+
+	first := r.open(10)
+	second := r.open(20)
+	if first == second {
+		t.Fatalf("window ids should be unique, got %d twice", first)
+	}
+
+	if err := r.addTab(first, 11); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.addTab(first, 12); err != nil {
+		t.Fatal(err)
+	}
+	// 同一文档不能同时在两个窗口中打开
+	if err := r.addTab(second, 11); err == nil {
+		t.Fatal("expected error when adding a document open in another window")
+	}
+	if err := r.addTab(99, 13); err == nil {
+		t.Fatal("expected error for an unknown window")
+	}
+
+	window := r.window(first)
+	if !reflect.DeepEqual(window.Documents, []int64{10, 11, 12}) || window.Active != 12 {
+		t.Fatalf("window after adding tabs = %+v", window)
+	}
+
+	// 关闭当前标签后切换到相邻标签
+	if windowID, remaining, ok := r.removeTab(12); !ok || windowID != first || remaining != 2 {
+		t.Fatalf("removeTab(12) = %d, %d, %v", windowID, remaining, ok)
+	}
+	if got := r.window(first).Active; got != 11 {
+		t.Fatalf("active after closing the last tab = %d", got)
+	}
+	if err := r.activate(first, 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.activate(second, 10); err == nil {
+		t.Fatal("expected error when activating a tab of another window")
+	}
+
+	if got := r.removeWindow(first); !reflect.DeepEqual(got, []int64{10, 11}) {
+		t.Fatalf("removeWindow = %v", got)
+	}
+	if _, ok := r.windowOf(10); ok {
+		t.Fatal("document 10 should be closed with its window")
+	}
+	if got := r.windowIDs(); !reflect.DeepEqual(got, []int64{second}) {
+		t.Fatalf("windowIDs = %v", got)
+	}
+}
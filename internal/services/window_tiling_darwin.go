@@ -0,0 +1,12 @@
+//go:build darwin
+
+package services
+
+import "voidraft/internal/models"
+
+// detectTilingFeatures 读取 com.apple.WindowManager 中的台前调度开关
+func detectTilingFeatures() []models.TilingStatus {
+	return []models.TilingStatus{
+		{Feature: models.TilingFeatureStageManager, Detected: readDefaultsBool("com.apple.WindowManager", "GloballyEnabled")},
+	}
+}
@@ -0,0 +1,16 @@
+//go:build linux
+
+package services
+
+import (
+	"os"
+	"voidraft/internal/models"
+)
+
+// detectTilingFeatures 通过会话环境变量检测平铺式窗口管理器
+func detectTilingFeatures() []models.TilingStatus {
+	name := tilingWMFromEnv(os.Getenv)
+	return []models.TilingStatus{
+		{Feature: models.TilingFeatureTilingWM, Detected: name != "", Name: name},
+	}
+}
@@ -0,0 +1,56 @@
+//go:build windows
+
+package services
+
+import (
+	"strings"
+	"unsafe"
+	"voidraft/internal/models"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// fancyZonesProcess FancyZones 模块的进程名
+const fancyZonesProcess = "PowerToys.FancyZones.exe"
+
+// detectTilingFeatures 检测贴靠窗口设置是否开启、FancyZones 是否正在运行
+func detectTilingFeatures() []models.TilingStatus {
+	return []models.TilingStatus{
+		{Feature: models.TilingFeatureSnapLayouts, Detected: snapLayoutsEnabled()},
+		{Feature: models.TilingFeatureFancyZones, Detected: processRunning(fancyZonesProcess)},
+	}
+}
+
+// snapLayoutsEnabled 读取“贴靠窗口”设置，未设置时系统默认开启
+func snapLayoutsEnabled() bool {
+	key, err := registry.OpenKey(registry.CURRENT_USER, `Control Panel\Desktop`, registry.QUERY_VALUE)
+	if err != nil {
+		return true
+	}
+	defer key.Close()
+
+	value, _, err := key.GetStringValue("WindowArrangementActive")
+	if err != nil {
+		return true
+	}
+	return value != "0"
+}
+
+// processRunning 通过进程快照检查指定进程是否正在运行
+func processRunning(name string) bool {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(snapshot)
+
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	for err = windows.Process32First(snapshot, &entry); err == nil; err = windows.Process32Next(snapshot, &entry) {
+		if strings.EqualFold(windows.UTF16ToString(entry.ExeFile[:]), name) {
+			return true
+		}
+	}
+	return false
+}
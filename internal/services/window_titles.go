@@ -0,0 +1,36 @@
+package services
+
+import (
+	"fmt"
+	"voidraft/internal/models"
+)
+
+// handleDocumentChange 文档重命名后更新打开它的窗口标题和菜单栏标签
+func (ws *WindowService) handleDocumentChange(event models.DocumentChangeEvent) {
+	if event.Type != models.DocumentChangeTitle {
+		return
+	}
+	if windowID, ok := ws.documentWindowID(event.DocumentID); ok {
+		ws.applyDocumentWindowTabs(windowID)
+	}
+	if ws.isPaneDocument(event.DocumentID) {
+		ws.applyMainWindowLayout()
+	}
+	ws.renameMenuBarDocument(event.DocumentID, event.Title)
+}
+
+// renameMenuBarDocument 固定到菜单栏的文档重命名后更新状态栏标签和窗口标题
+func (ws *WindowService) renameMenuBarDocument(documentID int64, title string) {
+	ws.menuBarMu.Lock()
+	defer ws.menuBarMu.Unlock()
+	if ws.menuBarDocumentID != documentID {
+		return
+	}
+	if ws.menuBarTray != nil {
+		ws.menuBarTray.SetLabel(menuBarLabel(title))
+		ws.menuBarTray.SetTooltip(title)
+	}
+	if ws.menuBarWindow != nil {
+		ws.menuBarWindow.SetTitle(fmt.Sprintf("voidraft - %s", title))
+	}
+}
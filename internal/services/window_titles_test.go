@@ -0,0 +1,52 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"voidraft/internal/common/helper"
+	"voidraft/internal/models"
+)
+
+// TestWindowTitleFollowsRename 重命名文档后主窗口和文档窗口使用新标题
+func TestWindowTitleFollowsRename(t *testing.T) {
+	ds := newTestDocumentService(t)
+	id := createTestDocument(t, ds, "Draft", "\n∞∞∞text-a\n")
+	events := make(chan models.DocumentChangeEvent, 8)
+	ds.onDocumentChange(func(event models.DocumentChangeEvent) { events <- event })
+
+	ws := &WindowService{
+		documentService: ds,
+		windowHelper:    helper.NewWindowHelper(),
+		windows:         newDocumentWindowRegistry(),
+		dirtyDocuments:  make(map[int64]bool),
+		closeAfterSave:  make(map[int64]bool),
+		mainPanes:       []models.MainPane{{Side: models.PaneSideLeft, DocumentID: id}},
+	}
+	windowID := ws.windows.open(id)
+	ws.menuBarDocumentID = id
+
+	if err := ds.UpdateDocumentTitle(id, "Renamed"); err != nil {
+		t.Fatal(err)
+	}
+	var event models.DocumentChangeEvent
+	for event.Type != models.DocumentChangeTitle {
+		select {
+		case event = <-events:
+		case <-time.After(time.Second):
+			t.Fatal("no title change event")
+		}
+	}
+	if event.DocumentID != id || event.Title != "Renamed" {
+		t.Fatalf("event = %+v", event)
+	}
+
+	// 没有实际窗口时只更新布局，不应出错
+	ws.handleDocumentChange(event)
+	if title := ws.GetMainWindowLayout().Title; !strings.Contains(title, "Renamed") {
+		t.Errorf("main window title = %q, want the new title", title)
+	}
+	if got := documentWindowTitle(ws.paneTitle(ws.GetDocumentWindowTabs(windowID).Active)); !strings.Contains(got, "Renamed") {
+		t.Errorf("document window title = %q, want the new title", got)
+	}
+}
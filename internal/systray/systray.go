@@ -18,7 +18,12 @@ import (
 //   - mainWindow: 主窗口对象，用于托盘事件交互
 //   - assets: 嵌入的静态资源文件系统，用于读取托盘图标
 //   - trayService: 托盘服务实例，处理托盘相关业务逻辑
-func SetupSystemTray(mainWindow *application.WebviewWindow, assets embed.FS, trayService *services.TrayService) {
+//   - appLockService: 应用锁服务实例，用于托盘菜单中的锁定操作
+//   - quickTranslateService: 剪贴板快速翻译服务实例，用于托盘菜单中的翻译操作
+//   - totpService: 一次性密码服务实例，用于托盘菜单中复制密码
+//   - documentService: 文档服务实例，用于托盘菜单中的最近文档
+//   - windowService: 窗口服务实例，用于从托盘菜单打开文档窗口
+func SetupSystemTray(mainWindow *application.WebviewWindow, assets embed.FS, trayService *services.TrayService, appLockService *services.AppLockService, quickTranslateService *services.QuickTranslateService, totpService *services.TOTPService, documentService *services.DocumentService, windowService *services.WindowService) {
 	// 获取应用程序的单例实例
 	// 该函数返回全局唯一的应用程序实例，确保整个应用生命周期中只有一个实例存在
 	// 返回值: 指向应用程序单例实例的指针
@@ -58,7 +63,7 @@ func SetupSystemTray(mainWindow *application.WebviewWindow, assets embed.FS, tra
 	menu := app.NewMenu()
 
 	// 注册托盘菜单事件
-	events.RegisterTrayMenuEvents(app, menu, mainWindow)
+	events.RegisterTrayMenuEvents(app, menu, mainWindow, trayService, appLockService, quickTranslateService, totpService, documentService, windowService)
 
 	// 将托盘菜单设置为系统托盘
 	systray.SetMenu(menu)
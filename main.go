@@ -4,8 +4,10 @@ import (
 	"embed"
 	_ "embed"
 	"log/slog"
+	"os"
 	"time"
 	"voidraft/internal/common/constant"
+	"voidraft/internal/models"
 	"voidraft/internal/services"
 	"voidraft/internal/systray"
 
@@ -18,12 +20,14 @@ var assets embed.FS
 // main 函数是应用程序的入口点。它初始化应用程序、创建窗口，并启动一个协程，
 // 每秒发送一次基于时间的事件。随后运行应用程序并记录可能发生的错误。
 func main() {
+	// 隐藏的基准命令，不启动界面
+	if len(os.Args) > 1 && os.Args[1] == "bench" {
+		os.Exit(runBench(os.Args[2:]))
+	}
+
 	// 创建服务管理器实例，用于管理应用程序的各种服务
 	serviceManager := services.NewServiceManager()
 
-	// 声明Webview窗口变量，用于创建和管理应用程序的主窗口界面
-	var window *application.WebviewWindow
-
 	// 定义32字节的加密密钥数组，用于数据加密和解密操作
 	// 该密钥采用固定的字节序列，按特定规律排列以确保加密安全性
 	var encryptionKey = [32]byte{
@@ -46,6 +50,8 @@ func main() {
 		Assets: application.AssetOptions{
 			// 设置资源文件处理器，使用嵌入的assets文件系统
 			Handler: application.AssetFileServerFS(assets),
+			// 附件等本地资源通过中间件流式提供
+			Middleware: serviceManager.AssetMiddleware(),
 		},
 		// 设置日志级别为调试级别
 		LogLevel: slog.LevelDebug,
@@ -61,17 +67,14 @@ func main() {
 			// 设置加密密钥用于实例间通信加密
 			EncryptionKey: encryptionKey,
 			// 当第二个实例启动时的回调处理函数
+			// 按设置或命令行参数聚焦主窗口、打开收集箱、新建文档或恢复最近的窗口
 			OnSecondInstanceLaunch: func(data application.SecondInstanceData) {
-				// 如果主窗口存在，则显示并聚焦该窗口
-				if window != nil {
-					window.Show()
-					window.Restore()
-					window.Focus()
-				}
+				serviceManager.GetSecondInstanceService().HandleSecondInstance(data)
 			},
-			// 附加数据，记录启动时间信息
+			// 附加数据，记录启动时间和命令行参数指定的行为
 			AdditionalData: map[string]string{
 				"launchtime": time.Now().Local().String(),
+				"action":     string(models.SecondInstanceActionFromArgs(os.Args[1:])),
 			},
 		},
 	})
@@ -79,7 +82,7 @@ func main() {
 	// 创建主窗口并进行配置
 	// 该函数创建一个带有特定配置的webview窗口，包括窗口大小、标题、样式等属性
 	// Mac平台下设置了透明效果和隐藏标题栏，Windows平台使用系统默认主题
-	// 窗口创建后会自动居中显示，并将窗口对象赋值给全局变量window
+	// 窗口创建后会自动居中显示
 	mainWindow := app.Window.NewWithOptions(application.WebviewWindowOptions{
 		// 设置窗口名称，用于内部标识
 		Name: constant.VOIDRAFT_MAIN_WINDOW_NAME,
@@ -120,15 +123,25 @@ func main() {
 	// 将窗口居中显示
 	mainWindow.Center()
 
-	// 将创建的主窗口赋值给全局window变量
-	window = mainWindow
-
 	// 获取系统托盘服务实例
 	// 从服务管理器中获取托盘服务，用于管理系统托盘图标和相关操作
 	trayService := serviceManager.GetTrayService()
 
+	// 获取应用锁服务实例，供托盘菜单锁定应用
+	appLockService := serviceManager.GetAppLockService()
+
+	// 获取快速翻译服务实例，供托盘菜单翻译剪贴板
+	quickTranslateService := serviceManager.GetQuickTranslateService()
+
+	// 获取一次性密码服务实例，供托盘菜单复制密码
+	totpService := serviceManager.GetTOTPService()
+
+	// 获取文档和窗口服务实例，供托盘菜单打开最近的文档
+	documentService := serviceManager.GetDocumentService()
+	windowService := serviceManager.GetWindowService()
+
 	// 初始化并设置系统托盘功能
-	systray.SetupSystemTray(mainWindow, assets, trayService)
+	systray.SetupSystemTray(mainWindow, assets, trayService, appLockService, quickTranslateService, totpService, documentService, windowService)
 
 	// 启动并运行整个应用程序。此调用会阻塞直到应用程序退出。
 	err := app.Run()